@@ -15,68 +15,53 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/dishes": {
-            "post": {
+        "/admin/abuse": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Inserts a new dish into database",
+                "description": "Lists the accounts that raised abuse signals or were reviewed, the highest score first, optionally by status",
                 "tags": [
-                    "dish"
+                    "admin"
                 ],
-                "summary": "Creates a dish",
+                "summary": "Gets suspicious accounts",
                 "parameters": [
                     {
-                        "description": "Dish info",
-                        "name": "dish",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dish.NewDish"
-                        }
+                        "type": "string",
+                        "description": "clear, challenge or shadow_ban",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dish.NewDishResp"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid dish data",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Server error while processing request",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.AbuseProfiles"
                         }
                     }
                 }
             }
         },
-        "/dishes/{id}": {
+        "/admin/abuse/{user_id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves dish info from database",
+                "description": "Gets the abuse signals the account raised in the current window, its score and status",
                 "tags": [
-                    "dish"
+                    "admin"
                 ],
-                "summary": "Gets a dish",
+                "summary": "Gets an account's abuse profile",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Dish ID",
-                        "name": "id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
@@ -85,17 +70,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dish.DishInfo"
+                            "$ref": "#/definitions/models.AbuseProfile"
                         }
                     },
                     "400": {
-                        "description": "Invalid dish ID",
+                        "description": "Invalid user ID",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "404": {
+                        "description": "Account raised no abuse signals",
                         "schema": {
                             "type": "string"
                         }
@@ -108,26 +93,26 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates dish info in database",
+                "description": "Sets the account's abuse status, which holds until set to auto: clear lets it go on, challenge makes it pass an OTP before anything else, shadow_ban holds its reviews. Auto hands it back to scoring",
                 "tags": [
-                    "dish"
+                    "admin"
                 ],
-                "summary": "Updates a dish",
+                "summary": "Reviews a suspicious account",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Dish ID",
-                        "name": "id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dish info",
-                        "name": "dish",
+                        "description": "Status and note",
+                        "name": "review",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dish.NewDataNoID"
+                            "$ref": "#/definitions/models.AbuseReview"
                         }
                     }
                 ],
@@ -135,52 +120,75 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dish.UpdatedData"
+                            "$ref": "#/definitions/models.AbuseProfile"
                         }
                     },
                     "400": {
-                        "description": "Invalid dish ID",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Server error while processing request",
+                        "description": "Invalid user ID or status",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/analytics/churn": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes dish from database",
+                "description": "Compares customers of the period with those of the preceding period of the same length",
                 "tags": [
-                    "dish"
+                    "admin"
                 ],
-                "summary": "Deletes a dish",
+                "summary": "Gets customer churn",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Dish ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "start date, unless period is given",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, unless period is given",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "last_7_days",
+                            "last_30_days",
+                            "last_90_days",
+                            "this_month",
+                            "last_month",
+                            "this_year"
+                        ],
+                        "type": "string",
+                        "description": "Days up to today in place of the dates",
+                        "name": "period",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Churn"
                         }
                     },
                     "400": {
-                        "description": "Invalid dish ID",
+                        "description": "Invalid date or period",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role is required",
                         "schema": {
                             "type": "string"
                         }
@@ -190,40 +198,73 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/dishes/{id}/nutrition": {
+        "/admin/analytics/orders-per-hour": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Informs about dish's nutritional value",
+                "description": "Counts orders across all kitchens for every hour of the period, by delivery time",
                 "tags": [
-                    "dish"
+                    "admin"
                 ],
-                "summary": "Gets dish's nutrition info",
+                "summary": "Gets orders per hour",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Dish ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "start date, unless period is given",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, unless period is given",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "last_7_days",
+                            "last_30_days",
+                            "last_90_days",
+                            "this_month",
+                            "last_month",
+                            "this_year"
+                        ],
+                        "type": "string",
+                        "description": "Days up to today in place of the dates",
+                        "name": "period",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/extra.NutritionalInfo"
+                            "$ref": "#/definitions/models.OrdersPerHour"
                         }
                     },
                     "400": {
-                        "description": "Invalid dish ID",
+                        "description": "Invalid date or period",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role is required",
                         "schema": {
                             "type": "string"
                         }
@@ -233,43 +274,75 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/kitchens": {
+        "/admin/analytics/revenue-by-city": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches all kitchens from database",
+                "description": "Sums orders and revenue of kitchens per city for the period",
                 "tags": [
-                    "kitchen"
+                    "admin"
                 ],
-                "summary": "Fetches all kitchens",
+                "summary": "Gets revenue by city",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "start date, unless period is given",
+                        "name": "start_date",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "end date, unless period is given",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "last_7_days",
+                            "last_30_days",
+                            "last_90_days",
+                            "this_month",
+                            "last_month",
+                            "this_year"
+                        ],
+                        "type": "string",
+                        "description": "Days up to today in place of the dates",
+                        "name": "period",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/kitchen.Kitchens"
+                            "$ref": "#/definitions/models.RevenueByCity"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date or period",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role is required",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -277,40 +350,89 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/analytics/top-kitchens": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Inserts a new kitchen into database",
+                "description": "Ranks kitchens by revenue or by number of orders for the period",
                 "tags": [
-                    "kitchen"
+                    "admin"
                 ],
-                "summary": "Creates a kitchen",
+                "summary": "Gets top kitchens",
                 "parameters": [
                     {
-                        "description": "Kitchen info",
-                        "name": "kitchen",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/kitchen.CreateRequest"
-                        }
+                        "type": "string",
+                        "description": "start date, unless period is given",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, unless period is given",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "last_7_days",
+                            "last_30_days",
+                            "last_90_days",
+                            "this_month",
+                            "last_month",
+                            "this_year"
+                        ],
+                        "type": "string",
+                        "description": "Days up to today in place of the dates",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "revenue",
+                            "orders"
+                        ],
+                        "type": "string",
+                        "description": "Ranking criterion",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of kitchens, 10 by default",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/kitchen.CreateResponse"
+                            "$ref": "#/definitions/models.TopKitchens"
                         }
                     },
                     "400": {
-                        "description": "Invalid kitchen data",
+                        "description": "Invalid date, period, sort or limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role is required",
                         "schema": {
                             "type": "string"
                         }
@@ -320,51 +442,57 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/kitchens/search": {
+        "/admin/api-usage": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Searches kitchens from database",
+                "description": "Tells how often each route was called and how it was answered, the most called first, with a breakdown by app platform and version from the X-Platform and X-App-Version headers. With below, only the calls of app versions older than it are counted, to tell which routes old apps still rely on. Covers the last 30 days by default",
                 "tags": [
-                    "kitchen"
+                    "admin"
                 ],
-                "summary": "Searches kitchens",
+                "summary": "Gets API usage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "query",
+                        "description": "First day, as YYYY-MM-DD",
+                        "name": "from",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Cuisine type",
-                        "name": "cuisine_type",
+                        "description": "Last day, as YYYY-MM-DD",
+                        "name": "to",
                         "in": "query"
                     },
                     {
-                        "type": "number",
-                        "description": "Rating",
-                        "name": "rating",
+                        "type": "string",
+                        "description": "Route as registered, such as /local-eats/orders/:id",
+                        "name": "route",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
+                        "type": "string",
+                        "description": "ios, android, web or other",
+                        "name": "platform",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "Only app versions older than this one, such as 2.0",
+                        "name": "below",
                         "in": "query"
                     }
                 ],
@@ -372,11 +500,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/kitchen.Kitchens"
+                            "$ref": "#/definitions/models.APIUsageReport"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "400": {
+                        "description": "Invalid dates",
                         "schema": {
                             "type": "string"
                         }
@@ -384,74 +512,102 @@ const docTemplate = `{
                 }
             }
         },
-        "/kitchens/{id}": {
+        "/admin/app-versions": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves kitchen info from database",
+                "description": "Tells how often each app version called the gateway and how many of its calls were answered with 426 Upgrade Required, by platform and newest first, with the oldest versions served. Covers the last 30 days by default",
                 "tags": [
-                    "kitchen"
+                    "admin"
                 ],
-                "summary": "Gets a kitchen",
+                "summary": "Gets app version usage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Kitchen ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "First day, as YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, as YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ios, android, web or other",
+                        "name": "platform",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/kitchen.Info"
+                            "$ref": "#/definitions/models.AppVersionReport"
                         }
                     },
                     "400": {
-                        "description": "Invalid kitchen ID",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Server error while processing request",
+                        "description": "Invalid dates",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/broadcasts": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates kitchen info in database",
+                "description": "Lists broadcasts with their stats, newest first, optionally by status",
                 "tags": [
-                    "kitchen"
+                    "broadcast"
                 ],
-                "summary": "Updates a kitchen",
+                "summary": "Gets broadcasts",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Kitchen ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
+                        "description": "scheduled, sending, sent or canceled",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Broadcasts"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
                     {
-                        "description": "Kitchen info",
-                        "name": "kitchen",
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Schedules a push and/or email campaign to the users of a segment, sent at send_at or right away. Users who turned promos off for a channel are skipped on it",
+                "tags": [
+                    "broadcast"
+                ],
+                "summary": "Schedules a broadcast",
+                "parameters": [
+                    {
+                        "description": "Broadcast",
+                        "name": "broadcast",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/kitchen.NewDataNoID"
+                            "$ref": "#/definitions/models.NewBroadcast"
                         }
                     }
                 ],
@@ -459,58 +615,104 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/kitchen.UpdatedData"
+                            "$ref": "#/definitions/models.Broadcast"
                         }
                     },
                     "400": {
-                        "description": "Invalid kitchen ID",
+                        "description": "Invalid broadcast data",
                         "schema": {
                             "type": "string"
                         }
+                    }
+                }
+            }
+        },
+        "/admin/broadcasts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets a broadcast with its stats, which are updated while it is being sent",
+                "tags": [
+                    "broadcast"
+                ],
+                "summary": "Gets a broadcast",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Broadcast ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Broadcast"
+                        }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "404": {
+                        "description": "Broadcast not found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes kitchen from database",
+                "description": "Changes a broadcast that has not started yet",
                 "tags": [
-                    "kitchen"
+                    "broadcast"
                 ],
-                "summary": "Deletes a kitchen",
+                "summary": "Updates a broadcast",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Kitchen ID",
+                        "description": "Broadcast ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Broadcast",
+                        "name": "broadcast",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewBroadcast"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Broadcast"
                         }
                     },
                     "400": {
-                        "description": "Invalid kitchen ID",
+                        "description": "Invalid broadcast data",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "404": {
+                        "description": "Broadcast not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Broadcast already started or canceled",
                         "schema": {
                             "type": "string"
                         }
@@ -518,50 +720,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/kitchens/{id}/dishes": {
-            "get": {
+        "/admin/broadcasts/{id}/cancel": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves dishes info from database",
+                "description": "Cancels a scheduled broadcast, or stops one being sent. Users reached so far stay in the stats",
                 "tags": [
-                    "dish"
+                    "broadcast"
                 ],
-                "summary": "Gets dishes",
+                "summary": "Cancels a broadcast",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Kitchen ID",
+                        "description": "Broadcast ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dish.Dishes"
+                            "$ref": "#/definitions/models.Broadcast"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "404": {
+                        "description": "Broadcast not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Broadcast already sent or canceled",
                         "schema": {
                             "type": "string"
                         }
@@ -569,57 +763,69 @@ const docTemplate = `{
                 }
             }
         },
-        "/kitchens/{id}/orders": {
+        "/admin/chargebacks": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets orders from database",
+                "description": "Lists the chargebacks providers reported, newest first, optionally by status, customer or order",
                 "tags": [
-                    "order"
+                    "admin"
                 ],
-                "summary": "Gets orders for kitchen",
+                "summary": "Gets chargebacks",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Kitchen ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "open, won or lost",
+                        "name": "status",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Status",
-                        "name": "status",
-                        "in": "query",
-                        "required": true
+                        "description": "Customer ID",
+                        "name": "user_id",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Chargebacks"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/commissions": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "ApiKeyAuth": []
                     }
                 ],
+                "description": "Lists the default commission and the rates set for kitchens and cuisine types",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets commission rates",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/order.OrdersKitchen"
+                            "$ref": "#/definitions/models.CommissionRates"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "403": {
+                        "description": "Admin role is required",
                         "schema": {
                             "type": "string"
                         }
@@ -627,56 +833,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/kitchens/{id}/reviews": {
+        "/admin/commissions/history": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets reviews from database",
+                "description": "Lists changes to commission rates, newest first, optionally only those of a scope or of one kitchen or cuisine type",
                 "tags": [
-                    "review"
+                    "admin"
                 ],
-                "summary": "Gets reviews",
+                "summary": "Gets commission changes",
                 "parameters": [
                     {
+                        "enum": [
+                            "kitchen",
+                            "cuisine"
+                        ],
                         "type": "string",
-                        "description": "Kitchen ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "description": "Scope of the rates",
+                        "name": "scope",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "Kitchen ID or cuisine type",
+                        "name": "key",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/review.Reviews"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid review data",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.CommissionHistory"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "403": {
+                        "description": "Admin role is required",
                         "schema": {
                             "type": "string"
                         }
@@ -684,18 +879,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/kitchens/{id}/statistics": {
+        "/admin/commissions/kitchens/{id}/effective": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Informs about kitchen statistics by date",
+                "description": "Tells the commission percent charged on the kitchen's new orders and whether it is the kitchen's, its cuisine's or the default rate",
                 "tags": [
-                    "kitchen"
+                    "admin"
                 ],
-                "summary": "Gets kitchen's statistics",
+                "summary": "Gets a kitchen's commission rate",
                 "parameters": [
                     {
                         "type": "string",
@@ -703,37 +898,29 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "start date",
-                        "name": "start_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "end date",
-                        "name": "end_date",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/extra.Statistics"
+                            "$ref": "#/definitions/models.EffectiveCommission"
                         }
                     },
                     "400": {
-                        "description": "Invalid kitchen ID or date format",
+                        "description": "Invalid kitchen ID",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "403": {
+                        "description": "Admin role is required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen not found",
                         "schema": {
                             "type": "string"
                         }
@@ -741,36 +928,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/kitchens/{id}/working-hours": {
-            "post": {
+        "/admin/commissions/{scope}/{key}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Sets working hours for kitchen",
+                "description": "Sets the commission percent for a kitchen or for all kitchens of a cuisine type. Orders placed afterwards are charged the new rate",
                 "tags": [
-                    "kitchen"
+                    "admin"
                 ],
-                "summary": "Sets working hours",
+                "summary": "Sets a commission rate",
                 "parameters": [
                     {
+                        "enum": [
+                            "kitchen",
+                            "cuisine"
+                        ],
                         "type": "string",
-                        "description": "Kitchen ID",
-                        "name": "id",
+                        "description": "What the rate applies to",
+                        "name": "scope",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Working hours",
-                        "name": "schedule",
+                        "type": "string",
+                        "description": "Kitchen ID or cuisine type",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rate",
+                        "name": "rate",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/extra.DaySchedule"
-                            }
+                            "$ref": "#/definitions/models.NewCommissionRate"
                         }
                     }
                 ],
@@ -778,147 +973,152 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/extra.WorkingHoursResp"
+                            "$ref": "#/definitions/models.CommissionRate"
                         }
                     },
                     "400": {
-                        "description": "Invalid kitchen ID or data",
+                        "description": "Invalid scope, key or rate",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "403": {
+                        "description": "Admin role is required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen not found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/orders": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets orders from database",
+                "description": "Removes the rate of a kitchen or cuisine type, so that its orders fall back to the cuisine or default rate",
                 "tags": [
-                    "order"
+                    "admin"
                 ],
-                "summary": "Gets orders for customer",
+                "summary": "Removes a commission rate",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
+                        "enum": [
+                            "kitchen",
+                            "cuisine"
+                        ],
+                        "type": "string",
+                        "description": "What the rate applies to",
+                        "name": "scope",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Kitchen ID or cuisine type",
+                        "name": "key",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for the audit trail",
+                        "name": "note",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/order.OrdersCustomer"
+                            "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "400": {
+                        "description": "Invalid scope or key",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role is required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No rate is set",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/cuisines": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Inserts a new order into database",
+                "description": "Lists the cuisine types with all their translations and aliases",
                 "tags": [
-                    "order"
-                ],
-                "summary": "Creates an order",
-                "parameters": [
-                    {
-                        "description": "Order info",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/order.NewOrder"
-                        }
-                    }
+                    "cuisine"
                 ],
+                "summary": "Gets the cuisine taxonomy",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/order.NewOrderResp"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid order data",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Server error while processing request",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Cuisines"
                         }
                     }
                 }
-            }
-        },
-        "/orders/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets order from database",
+                "description": "Adds a cuisine type to the taxonomy",
                 "tags": [
-                    "order"
+                    "cuisine"
                 ],
-                "summary": "Gets an order",
+                "summary": "Creates a cuisine type",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Cuisine info",
+                        "name": "cuisine",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewCuisineWithCode"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/order.OrderInfo"
+                            "$ref": "#/definitions/models.Cuisine"
                         }
                     },
                     "400": {
-                        "description": "Invalid order ID",
+                        "description": "Invalid cuisine data",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "409": {
+                        "description": "Code, name or alias already taken",
                         "schema": {
                             "type": "string"
                         }
@@ -926,33 +1126,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{id}/status": {
+        "/admin/cuisines/{code}": {
             "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates order status in database",
+                "description": "Replaces the name, translations and aliases of a cuisine type. Kitchens keep the cuisine name they were saved with",
                 "tags": [
-                    "order"
+                    "cuisine"
                 ],
-                "summary": "Updates an order",
+                "summary": "Updates a cuisine type",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID",
-                        "name": "id",
+                        "description": "Cuisine code",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Order status",
-                        "name": "status",
+                        "description": "Cuisine info",
+                        "name": "cuisine",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/order.StatusNoID"
+                            "$ref": "#/definitions/models.NewCuisine"
                         }
                     }
                 ],
@@ -960,62 +1160,58 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/order.UpdatedOrder"
+                            "$ref": "#/definitions/models.Cuisine"
                         }
                     },
                     "400": {
-                        "description": "Invalid order ID",
+                        "description": "Invalid cuisine data",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "404": {
+                        "description": "Cuisine not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Name or alias already taken",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/payments": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Inserts a new payment into database",
+                "description": "Removes a cuisine type from the taxonomy",
                 "tags": [
-                    "payment"
+                    "cuisine"
                 ],
-                "summary": "Creates a payment",
+                "summary": "Deletes a cuisine type",
                 "parameters": [
                     {
-                        "description": "Payment info",
-                        "name": "payment",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/payment.NewPayment"
-                        }
+                        "type": "string",
+                        "description": "Cuisine code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/payment.NewPayment"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid payment data",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "404": {
+                        "description": "Cuisine not found",
                         "schema": {
                             "type": "string"
                         }
@@ -1023,69 +1219,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/payments/{id}": {
+        "/admin/data-requests": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves payment info from database",
+                "description": "Lists the compiled data packages, latest first",
                 "tags": [
-                    "payment"
+                    "admin"
                 ],
-                "summary": "Gets a payment",
+                "summary": "Gets data requests",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Payment ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only the packages of this user",
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/payment.PaymentDetails"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid payment ID",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Server error while processing request",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.DataRequests"
                         }
                     }
                 }
-            }
-        },
-        "/reviews": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Inserts a new review into database",
+                "description": "Compiles everything kept about the user for a subject access request or a legal order: the profile, orders and payments from the services and every record the gateway keeps. The package is a zip archive of JSON files, encrypted with AES-256-GCM; the key is in this response only. The manifest lists every section, with the errors of those that could not be read in full. Orders, reviews, order notes and payments are found through the user's activity feed, which keeps the latest 500 items since the gateway started, so those sections are marked partial and the package is not complete. Compiling, downloading and deleting the package are audited",
                 "tags": [
-                    "review"
+                    "admin"
                 ],
-                "summary": "Creates a review",
+                "summary": "Compiles a user's data package",
                 "parameters": [
                     {
-                        "description": "Review info",
-                        "name": "review",
+                        "description": "Data request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/review.NewReview"
+                            "$ref": "#/definitions/models.NewDataRequest"
                         }
                     }
                 ],
@@ -1093,11 +1274,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/review.NewReviewResp"
+                            "$ref": "#/definitions/models.SealedDataRequest"
                         }
                     },
                     "400": {
-                        "description": "Invalid review data",
+                        "description": "Invalid data request",
                         "schema": {
                             "type": "string"
                         }
@@ -1111,22 +1292,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/admin/data-requests/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves user info from database",
+                "description": "Gives a compiled data package's sections and its audit trail",
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Gets a user",
+                "summary": "Gets a data request",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Data request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1136,61 +1317,58 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.Profile"
+                            "$ref": "#/definitions/models.DataRequestDetails"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Invalid data request ID",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "404": {
+                        "description": "Data request not found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates user info in database",
+                "description": "Deletes the encrypted package of a data request once it was handed over. The request and its audit trail are kept",
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Updates a user",
+                "summary": "Deletes a data package",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Data request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "User info",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.NewInfoNoID"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.Details"
+                            "$ref": "#/definitions/models.DataRequest"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Invalid data request ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Data request not found",
                         "schema": {
                             "type": "string"
                         }
@@ -1202,22 +1380,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/data-requests/{id}/package": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes user from database",
+                "description": "Downloads the encrypted package of a data request until it expires or is deleted",
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Deletes a user",
+                "summary": "Downloads a data package",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Data request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1227,17 +1407,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.Void"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Invalid data request ID",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "404": {
+                        "description": "Data request not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Package expired or deleted",
                         "schema": {
                             "type": "string"
                         }
@@ -1245,587 +1431,16193 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/activity": {
+        "/admin/emails": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Informs about user's activity by date",
+                "description": "Lists the emails the gateway queued with their delivery status, newest first, optionally by template or status",
                 "tags": [
-                    "user"
+                    "email"
                 ],
-                "summary": "Tracks user's activity",
+                "summary": "Gets sent emails",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "receipt, verification, invitation, staff_invite, dish_available or report",
+                        "name": "template",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "start date",
-                        "name": "start_date",
-                        "in": "query",
-                        "required": true
-                    },
+                        "description": "queued, sent or failed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Emails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/encryption": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "end date",
-                        "name": "end_date",
-                        "in": "query",
-                        "required": true
+                        "ApiKeyAuth": []
                     }
                 ],
+                "description": "Tells whether the personal data the gateway keeps, such as search history, tickets, messages, order notes, undelivered events, uploads and report files, is encrypted, with which key provider and key, and when the data key was last rotated",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets how personal data is encrypted at rest",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/extra.Activity"
+                            "$ref": "#/definitions/models.EncryptionStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/encryption/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reloads the key encryption key, so a key appended to the key file takes over, generates a new data key and reseals everything kept with it. The old data keys are dropped once everything is resealed. Rotate after a suspected key compromise or on the key policy's schedule",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Rotates the encryption keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KeyRotation"
                         }
                     },
-                    "400": {
-                        "description": "Invalid user ID or date format",
+                    "409": {
+                        "description": "Encryption is off, or keys are being rotated",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Server error while processing request",
+                        "description": "Keys could not be rotated",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dish.DishDetails": {
-            "type": "object",
-            "properties": {
-                "available": {
-                    "type": "boolean"
-                },
-                "category": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
+        },
+        "/admin/fraud": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the orders and payments held for fraud review, oldest first, optionally by status",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets fraud cases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FraudCases"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fraud/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets a held order or payment with the rules it matched",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets a fraud case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Case ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FraudCase"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid case ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Case not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Approves or rejects a held order or payment. An approved order is placed then; an approved payment can be tried again by the customer, as can payments of the approved order",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reviews a fraud case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Case ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decision",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FraudDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FraudCase"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or decision",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Case not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Case already reviewed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/inspections": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Attaches an inspection's score and its documents, uploaded with the inspection_report purpose, to a kitchen. The latest inspection sets the hygiene_rating shown with the kitchen",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Records a hygiene inspection",
+                "parameters": [
+                    {
+                        "description": "Inspection",
+                        "name": "inspection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewInspection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Inspection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid inspection data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/inspections/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes an inspection recorded in error. The kitchen's rating falls back to its previous inspection",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Deletes a hygiene inspection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Inspection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid inspection ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Inspection not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists invitations, newest first, optionally by status",
+                "tags": [
+                    "invitation"
+                ],
+                "summary": "Gets invitations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending or accepted",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invitations"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Emails a registration link with a signed token that expires. Opening it pre-fills registration; after signing in the invitee accepts it, which for staff invitations joins the kitchen's staff",
+                "tags": [
+                    "invitation"
+                ],
+                "summary": "Invites a kitchen owner or staff",
+                "parameters": [
+                    {
+                        "description": "Invitation",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewInvitation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatedInvitation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid invitation data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/limits/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the users with requests in flight or turned away with 429 for going over the per-user cap in the last 10 minutes, the most rejected first, to find clients stuck in retry storms",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets the per-user request cap's metrics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserLoads"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/moderation": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists reviews moderation held back, oldest first, optionally by status",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Gets held reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HeldReviews"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/moderation/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Publishes a held review or drops it",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Moderates a held review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Held review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decision",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ModerationDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HeldReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or decision",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Held review not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Review already moderated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/partners/keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the partner API keys, newest first, with when each was last used and whether it was revoked",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets partner API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartnerKeys"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Issues an API key for a partner aggregator to call the partner routes with in X-API-Key, and the secret to sign its order requests with. Both are returned only now; the gateway keeps a hash of the key. Partners approved with orders may place orders, whose statuses are sent to webhook_url. Keys with sandbox place test orders only",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Issues a partner API key",
+                "parameters": [
+                    {
+                        "description": "Partner",
+                        "name": "key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewPartnerKey"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatedPartnerKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid partner key data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/partners/keys/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Approves or stops the key's partner placing orders, sets where the statuses of its orders are sent, and moves the key in or out of the sandbox, where orders are test data",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Changes a partner API key's settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PartnerKeySettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartnerKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid key ID or settings",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes a partner API key; calls with it are refused from now on",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revokes a partner API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartnerKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid key ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/postman-collection": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Builds a Postman collection, which Insomnia imports too, from the routes the gateway serves: a folder per tag, a request per route with its parameters and an example body. Requests go to the baseUrl variable, the gateway's address by default, and are signed with the token variable",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Exports a Postman collection",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/recordings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the recordings, latest first, with how many requests each recorded",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets request recordings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recordings"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records the requests the gateway serves for the next minutes, up to RECORDING_MAX_MINUTES, to replay against a staging gateway with the replay command before a release. One recording runs at a time.\nRequests are anonymized as they are recorded: credentials and other headers that don't change the answer are dropped, emails, phones, names, addresses, notes, passwords and tokens are replaced with pseudonyms that are the same throughout the recording, card numbers with a test card and coordinates are rounded to about a kilometre. Bodies that are not JSON or larger than RECORDING_MAX_BODY are left out, as are requests past RECORDING_MAX_REQUESTS",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Starts recording requests",
+                "parameters": [
+                    {
+                        "description": "Recording",
+                        "name": "recording",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewRecording"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recording"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid recording data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "A recording is already running",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/recordings/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gives the recording with its anonymized requests in the order they were served, as the replay command reads it",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets a request recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recording ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecordingTrace"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid recording ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Recording not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the recording and its requests, stopping it if it runs",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Deletes a request recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recording ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid recording ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Recording not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/recordings/{id}/stop": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stops the recording before its time is up; its requests are kept",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Stops recording requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recording ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recording"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid recording ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Recording not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/retention": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gives the retention policy and the reports of the latest runs, latest first: how many orders and payments were anonymized in the services, what gateway records were purged, by kind, and what failed to be anonymized",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets data retention runs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RetentionRuns"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Anonymizes the orders and payments older than the policy's record days in the services, purges the ended group orders and meal plans and settled tickets older than its record days and clears the comments of refund requests decided before then, and purges the gateway's logs older than its log days now, even if scheduled runs are disabled, and returns the report. Orders the services fail to anonymize are kept and tried again on the next run",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Runs data retention",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RetentionRun"
+                        }
+                    },
+                    "409": {
+                        "description": "A run is already in progress",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/retention/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gives the report of a retention run",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets a data retention run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RetentionRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid run ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Run not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/roles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the built-in and custom roles with their permissions",
+                "tags": [
+                    "role"
+                ],
+                "summary": "Gets roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Roles"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a custom role. Tokens whose role claim names it get its permissions",
+                "tags": [
+                    "role"
+                ],
+                "summary": "Creates a role",
+                "parameters": [
+                    {
+                        "description": "Role info",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewRoleWithName"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Role already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/roles/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the permissions a role can be granted",
+                "tags": [
+                    "role"
+                ],
+                "summary": "Gets permissions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PermissionList"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/roles/{name}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the description and permissions of a custom role. Tokens already issued get the new permissions right away",
+                "tags": [
+                    "role"
+                ],
+                "summary": "Updates a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role info",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Built-in role",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a custom role. Tokens naming it lose its permissions",
+                "tags": [
+                    "role"
+                ],
+                "summary": "Deletes a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Built-in role",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets the fees, zone enforcement, maintenance mode, banners and oldest app versions served in effect, with their version",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets platform settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Settings"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the sections given, leaving the others as they are, and records the change. Fees apply to orders priced from then on. With version set, the update is refused if the settings were changed since",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Updates platform settings",
+                "parameters": [
+                    {
+                        "description": "Sections to replace",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SettingsUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Settings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid settings",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Settings were changed meanwhile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settings/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the changes of the settings, newest first, with each changed section before and after, optionally of one section",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets the history of platform settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "fees, zones, maintenance, banners or app_versions",
+                        "name": "section",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SettingsChanges"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/slo": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells where each backend service stands against its availability and latency SLOs: the share of good calls and of the error budget left over the SLO window, and how fast the budget burns over the last 5m, 30m, 1h and 6h. Counted since the gateway started",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets backend SLOs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLOReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sms/campaigns": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Texts a message to up to 1000 phones. Messages are sent in the background; their delivery can be followed by the campaign ID",
+                "tags": [
+                    "sms"
+                ],
+                "summary": "Sends a marketing SMS",
+                "parameters": [
+                    {
+                        "description": "Phones and text",
+                        "name": "campaign",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewSMSCampaign"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.SMSCampaign"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid campaign data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "SMS is not configured",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sms/messages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists sent text messages with their delivery status, newest first, optionally by campaign, purpose or status",
+                "tags": [
+                    "sms"
+                ],
+                "summary": "Gets sent SMS",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaign",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "otp, order_status or marketing",
+                        "name": "purpose",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sent, delivered or failed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SMSMessages"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/verifications": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists kitchens that submitted documents, oldest submission first, optionally by status",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen verifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, verified or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenVerifications"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/verifications/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Grants the verified badge to a kitchen, or refuses or revokes it. The kitchen owner is emailed the decision",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Reviews a kitchen verification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decision",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VerificationDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenVerification"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or decision",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen submitted no documents",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/zones": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists delivery zones, optionally of one city",
+                "tags": [
+                    "zone"
+                ],
+                "summary": "Gets delivery zones",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "City",
+                        "name": "city",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Zones"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Defines an area the service delivers to as a polygon of lat/lng points",
+                "tags": [
+                    "zone"
+                ],
+                "summary": "Creates a delivery zone",
+                "parameters": [
+                    {
+                        "description": "Zone info",
+                        "name": "zone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewZone"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Zone"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid zone data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/zones/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a delivery zone with its polygon",
+                "tags": [
+                    "zone"
+                ],
+                "summary": "Gets a delivery zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Zone"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid zone ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Zone not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the name, city, polygon and active flag of a delivery zone",
+                "tags": [
+                    "zone"
+                ],
+                "summary": "Updates a delivery zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Zone info",
+                        "name": "zone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewZone"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Zone"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid zone ID or data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Zone not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a delivery zone",
+                "tags": [
+                    "zone"
+                ],
+                "summary": "Deletes a delivery zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid zone ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Zone not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/allergens": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the allergen tags dishes and allergy profiles are declared with",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Gets allergen tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AllergenList"
+                        }
+                    }
+                }
+            }
+        },
+        "/banners": {
+            "get": {
+                "description": "Lists the banners to show at the top of the app now. Anyone may read them, before signing in too",
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Gets platform banners",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlatformBanners"
+                        }
+                    }
+                }
+            }
+        },
+        "/changelog": {
+            "get": {
+                "description": "Lists the changes of the API by version, the latest first: routes added, changed, deprecated and removed. Deprecated routes tell when they sunset and which route succeeds them; until then they answer with Deprecation and Sunset headers, and with 410 after. Anyone may read it",
+                "tags": [
+                    "docs"
+                ],
+                "summary": "Gets the API changelog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only versions after this one, such as 1.0",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Changelog"
+                        }
+                    }
+                }
+            }
+        },
+        "/coverage": {
+            "get": {
+                "description": "Tells whether the service delivers to a location and in which zone. While no zones are defined every location is covered",
+                "tags": [
+                    "zone"
+                ],
+                "summary": "Checks delivery coverage",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Coverage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid location",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cuisines": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the cuisine types kitchens can be searched by, with names in the language of the lang query or the Accept-Language header",
+                "tags": [
+                    "cuisine"
+                ],
+                "summary": "Gets cuisine types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language, e.g. ru",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LocalizedCuisines"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Inserts a new dish into database. Allergens are required, as tags from GET /allergens; an empty list declares a dish without any",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Creates a dish",
+                "parameters": [
+                    {
+                        "description": "Dish info",
+                        "name": "dish",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewDish"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dish.NewDishResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish data or allergens",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/availability": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Marks up to 100 dishes as available or sold out; every dish gets its own result. Customers waiting for a dish are notified once it is available. Only dishes of kitchens the caller owns or is staff with the menu permission of are changed; the others fail with 403",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Sets dish availability",
+                "parameters": [
+                    {
+                        "description": "Dish availability",
+                        "name": "dishes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Availability"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        }
+                    },
+                    "207": {
+                        "description": "Some dishes failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid availability data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates up to 100 dishes in one request; every dish gets its own result. Each dish must declare its allergens, as for POST /dishes. Dishes are created for kitchens the caller owns or is staff with the menu permission of; the others fail with 403",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Imports dishes",
+                "parameters": [
+                    {
+                        "description": "Dishes",
+                        "name": "dishes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NewDish"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        }
+                    },
+                    "207": {
+                        "description": "Some dishes failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Finds dishes with at most the given calories and grams of protein, fat and carbs per serving, lowest calories first. Dishes without nutrition info are left out, as are those of kitchens the caller blocked. At least one limit or the query is required",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Searches dishes by nutrition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the dish name",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most calories",
+                        "name": "max_calories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most grams of protein",
+                        "name": "max_protein",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most grams of fat",
+                        "name": "max_fat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most grams of carbs",
+                        "name": "max_carbs",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NutritionDishes"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid search parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves dish info from database. While a discount is active the dish also has discount_percent and discounted_price. The response may be cached at the edge under the surrogate key dish:{id}",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Gets a dish",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dish.DishInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates dish info in database. Customers waiting for the dish are notified once it is available",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Updates a dish",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dish info",
+                        "name": "dish",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dish.NewDataNoID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dish.UpdatedData"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes dish from database",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Deletes a dish",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}/discounts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the dish's discounts and whether each is active now. For the kitchen's owner and staff with the menu permission; customers see the discounted price in the dish",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Gets dish discounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DishDiscounts"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Takes a percent off the dish's price between starts_at and ends_at, on the given days and from from until until each day, such as a happy hour. When several discounts are active the largest applies. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Adds a dish discount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Discount",
+                        "name": "discount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewDishDiscount"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DishDiscount"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID or discount data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many discounts",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}/discounts/{discount_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces a discount of the dish. Orders already placed keep the price they got. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Updates a dish discount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Discount ID",
+                        "name": "discount_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Discount",
+                        "name": "discount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewDishDiscount"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DishDiscount"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or discount data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Discount not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ends a discount of the dish. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Deletes a dish discount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Discount ID",
+                        "name": "discount_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Discount not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}/ingredients": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Discloses the dish's ingredients and allergens. Declared is false for dishes created before allergens were required, whose allergens are only what the dish service lists",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Gets dish ingredients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DishIngredients"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}/modifiers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the modifier schema of a dish",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Gets dish modifiers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Modifiers"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the modifier schema (size, extras, spice level etc.) of a dish. For the kitchen owner, staff with the menu permission and admins",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Sets dish modifiers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Modifier groups",
+                        "name": "modifiers",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ModifiersNoID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Modifiers"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID or modifier data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}/notify-me": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Notifies the caller by push, email or both once the dish is available again. Asking again replaces the channels",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Waits for a sold-out dish",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notification channels",
+                        "name": "alert",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.NewDishAlert"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DishAlert"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID or channels",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Token has no user id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dish not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Dish is available",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels the caller's notification for the dish",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Stops waiting for a dish",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Token has no user id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not waiting for the dish",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}/nutrition": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Informs about dish's nutritional value",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Gets dish's nutrition info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/extra.NutritionalInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}/stock": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells how many portions of the dish are left today",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Gets dish stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DishStock"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dish stock is not limited",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets how many portions of the dish can be ordered per day (UTC). Portions sold today still count. The dish is marked sold out while none are left",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Sets dish stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Daily count",
+                        "name": "stock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewDishStock"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DishStock"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID or stock",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or menu staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dish not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stops limiting the portions of the dish. A dish sold out by its stock is available again",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Removes dish stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or menu staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dish or its stock not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}/translations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the dish's name and description in every locale it was translated into. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Lists a dish's translations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Translations"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or menu staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dish not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dishes/{id}/translations/{locale}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the dish's name and description in a locale, shown to users whose Accept-Language prefers it. An empty field leaves the dish's own. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Translates a dish",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "uz, ru or en",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Translation",
+                        "name": "translation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewTranslation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Translation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID, locale or translation",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or menu staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dish not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes the dish's translation into a locale, so its own name and description are shown again. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Deletes a dish's translation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dish ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "uz, ru or en",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dish ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or menu staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dish or translation not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-orders": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Opens a cart at a kitchen that others can join through its share link. The caller becomes the host who checks out",
+                "tags": [
+                    "group order"
+                ],
+                "summary": "Creates a group order",
+                "parameters": [
+                    {
+                        "description": "Kitchen and delivery",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewGroupOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group order",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Token has no user id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-orders/join/{code}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds the caller to the participants of the open group order the share code belongs to",
+                "tags": [
+                    "group order"
+                ],
+                "summary": "Joins a group order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupOrder"
+                        }
+                    },
+                    "403": {
+                        "description": "Token has no user id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Group order is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the group cart with everyone's items. For participants and admins",
+                "tags": [
+                    "group order"
+                ],
+                "summary": "Gets a group order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group order ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a participant",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Closes the cart without ordering. For the host and admins",
+                "tags": [
+                    "group order"
+                ],
+                "summary": "Cancels a group order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupOrder"
+                        }
+                    },
+                    "403": {
+                        "description": "Only the host can cancel",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Group order is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-orders/{id}/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Places one order with everyone's items for the host. The cart is locked while the order is placed and reopens if it fails. The order is screened for fraud as the host's, like POST /orders. Checked out in the sandbox, it is a test order",
+                "tags": [
+                    "group order"
+                ],
+                "summary": "Checks out a group order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Expected total",
+                        "name": "checkout",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupCheckout"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Where the host's phone is, as lat,lng",
+                        "name": "X-Device-Location",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.NewOrderResp"
+                        }
+                    },
+                    "202": {
+                        "description": "Order held for review",
+                        "schema": {
+                            "$ref": "#/definitions/models.FraudHold"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid group order",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Only the host can check out, or the host must pass an OTP first, see challenge",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Group order is closed, empty or its total does not match current prices",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Order breaks a kitchen rule, named by rule, or the location can't be routed to",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-orders/{id}/items": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Puts a dish of the group's kitchen into the cart on behalf of the caller",
+                "tags": [
+                    "group order"
+                ],
+                "summary": "Adds an item to a group order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewGroupItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid item",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a participant",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Group order is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-orders/{id}/items/{item_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the quantity of an item. Participants change their own items, the host any item",
+                "tags": [
+                    "group order"
+                ],
+                "summary": "Changes an item of a group order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quantity",
+                        "name": "quantity",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupItemQuantity"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid quantity",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Item belongs to another participant",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group order or item not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Group order is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Takes an item out of the cart. Participants remove their own items, the host any item",
+                "tags": [
+                    "group order"
+                ],
+                "summary": "Removes an item from a group order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupOrder"
+                        }
+                    },
+                    "403": {
+                        "description": "Item belongs to another participant",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group order or item not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Group order is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/group-orders/{id}/ws": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upgrades to a WebSocket that sends a models.GroupUpdate with the whole cart now and after every change, until the group is checked out or canceled",
+                "tags": [
+                    "group order"
+                ],
+                "summary": "Watches a group order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupUpdate"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a participant",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{key}": {
+            "get": {
+                "description": "Serves an image from object storage, optionally resized to fit width x height and converted to another format. The Android Go app, sending X-Platform android-go, gets images of at most 480 pixels a side, as WebP unless it asks for another format. Images of more than 25 megapixels are not resized",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/webp"
+                ],
+                "tags": [
+                    "image"
+                ],
+                "summary": "Gets an image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Object key, e.g. dishes/{id}.jpg",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum width in pixels",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum height in pixels",
+                        "name": "height",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "jpeg",
+                            "png",
+                            "webp"
+                        ],
+                        "type": "string",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid image parameters or image too large to resize",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Object storage error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/{token}": {
+            "get": {
+                "description": "Checks an invitation token and returns the details to pre-fill registration with. No sign-in is needed",
+                "tags": [
+                    "invitation"
+                ],
+                "summary": "Opens an invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.InvitationPrefill"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid invitation",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation already accepted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Invitation expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/{token}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Marks the invitation used by the signed-in user, whose email must be the invited one. Staff invitations add the user to the kitchen's staff",
+                "tags": [
+                    "invitation"
+                ],
+                "summary": "Accepts an invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invitation"
+                        }
+                    },
+                    "403": {
+                        "description": "Invitation is for another email",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid invitation",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation already accepted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Invitation expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches all kitchens from database, with the gateway's verified badge. Kitchens the caller blocked are left out, so a page may hold fewer items than the limit",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Fetches all kitchens",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/kitchen.Kitchens"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Inserts a new kitchen into database. The cuisine type must be one of GET /cuisines and is saved under its canonical name",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Creates a kitchen",
+                "parameters": [
+                    {
+                        "description": "Kitchen info",
+                        "name": "kitchen",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/kitchen.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/kitchen.CreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Searches kitchens from database, with the gateway's verified badge. The cuisine type may be given by code, name, translation or alias from GET /cuisines. Kitchens the caller blocked are left out, so a page may hold fewer items than the limit",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Searches kitchens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cuisine type",
+                        "name": "cuisine_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Rating",
+                        "name": "rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/kitchen.Kitchens"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves kitchen info from database, with the gateway's verified badge, the hygiene_rating of the latest inspection, null if never inspected, and the kitchen's active announcements, banners first. The response may be cached at the edge under the surrogate key kitchen:{id}",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets a kitchen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/kitchen.Info"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates kitchen info in database",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Updates a kitchen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Kitchen info",
+                        "name": "kitchen",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/kitchen.NewDataNoID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/kitchen.UpdatedData"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes kitchen from database",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Deletes a kitchen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/announcements": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the kitchen's announcements, latest start first, optionally by status. For the kitchen's owner and staff with the menu permission; customers see the active ones in the kitchen's detail",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen announcements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "scheduled, active or expired",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Announcements"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Publishes a notice or banner shown in the kitchen's detail and in its customers' feeds from starts_at until ends_at. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Publishes a kitchen announcement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Announcement",
+                        "name": "announcement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewAnnouncement"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Announcement"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or announcement data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many announcements",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/announcements/{announcement_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces an announcement, which may also reschedule it or bring an expired one back. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Updates a kitchen announcement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Announcement ID",
+                        "name": "announcement_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Announcement",
+                        "name": "announcement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewAnnouncement"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Announcement"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or announcement data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Announcement not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Takes an announcement down. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Deletes a kitchen announcement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Announcement ID",
+                        "name": "announcement_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Announcement not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/dishes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves dishes info from database. Dishes with an active discount also have discount_percent and discounted_price. The response may be cached at the edge under the surrogate key dishes",
+                "tags": [
+                    "dish"
+                ],
+                "summary": "Gets dishes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dish.Dishes"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/documents": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a licence or health certificate, uploaded with the kitchen_document purpose, to the kitchen's verification. An unverified kitchen then waits for an admin's review",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Submits a kitchen document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Document info",
+                        "name": "document",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewKitchenDocument"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenVerification"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/earnings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Summarizes what a kitchen earned in the period by day, week or month, after the platform commission and approved refunds, with a per-order breakdown. Days are in the kitchen's time zone. For the kitchen owner, staff with the payouts permission and admins",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen earnings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, unless period is given",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, unless period is given",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "last_7_days",
+                            "last_30_days",
+                            "last_90_days",
+                            "this_month",
+                            "last_month",
+                            "this_year"
+                        ],
+                        "type": "string",
+                        "description": "Days up to today in place of the dates",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Period length",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page of the order breakdown",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Orders per page, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Earnings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID, date, period, group or pagination",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/eta": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells the road distance from the kitchen, the delivery fee and when an order placed now would arrive. Addresses in a delivery zone are measured to the zone's center, so the whole zone is priced alike",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Estimates delivery to a location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeliveryEstimate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or location",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Kitchen location not set, location not covered or unreachable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/inspections": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the kitchen's hygiene inspections, latest first, with its current rating, the score of the latest one. Rating is null for kitchens not inspected yet",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen hygiene inspections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenInspections"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/location": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells where the kitchen's orders are picked up, which delivery distances are measured from",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets a kitchen's location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenLocation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Location not set",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets where the kitchen's orders are picked up. Orders, and quotes that give a delivery location, are priced by the road distance from it. For the kitchen's owner and admins",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Sets a kitchen's location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Location",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Point"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenLocation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or location",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/meta": {
+            "get": {
+                "description": "Returns the title, description and OpenGraph properties of the kitchen's public page, for the website to render link previews",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen page metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PageMeta"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/order-timer": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells whether orders the kitchen doesn't respond to are accepted or rejected, and after how many minutes. For the kitchen's owner and staff with the orders permission",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets a kitchen's order timer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderTimer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts or rejects orders the kitchen leaves without a response for after_minutes; accept with zero minutes accepts every order at once, and an empty action turns the timer off. The customer is notified as if the kitchen had responded. Orders placed before the change keep their timer. For the kitchen's owner and admins",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Sets a kitchen's order timer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order timer",
+                        "name": "timer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewOrderTimer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderTimer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or timer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/orders": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets orders from database, with the notes customers left on each order and its items and their delivery preferences. For the kitchen's owner, staff with the orders permission and admins",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Gets orders for kitchen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.OrdersKitchen"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/payouts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists payouts made to a kitchen, newest first, with the balance of earnings not paid out yet. For the kitchen owner, staff with the payouts permission and admins",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen payouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payouts"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records money transferred to a kitchen so that it shows in the kitchen's payouts and balance. For roles granted payouts:manage",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Records a payout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payout",
+                        "name": "payout",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewPayout"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payout"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or payout",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission payouts:manage is required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/qr": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Draws a QR code that opens the kitchen's public menu page, for printing on table cards",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets a kitchen's menu QR code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "description": "Image format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Width and height in pixels, 128 to 2048",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID, format or size",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/refund-requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists refund requests for the kitchen's orders, newest first",
+                "tags": [
+                    "refund"
+                ],
+                "summary": "Gets refund requests of a kitchen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Request status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RefundRequests"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/reports": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists reports generated for a kitchen, newest first. For the kitchen owner, staff with the payouts permission and admins",
+                "tags": [
+                    "report"
+                ],
+                "summary": "Gets generated reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reports"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Schedules a weekly or monthly statistics report in CSV or PDF, delivered by email or through the kitchen's report.generated webhooks. For the kitchen owner, staff with the payouts permission and admins",
+                "tags": [
+                    "report"
+                ],
+                "summary": "Schedules a report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule info",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewReportSchedule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportSchedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or schedule data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/reports/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the report schedules of a kitchen. For the kitchen owner, staff with the payouts permission and admins",
+                "tags": [
+                    "report"
+                ],
+                "summary": "Gets report schedules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportSchedules"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/reports/schedules/{schedule_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stops a scheduled report. Reports generated so far are kept. For the kitchen owner, staff with the payouts permission and admins",
+                "tags": [
+                    "report"
+                ],
+                "summary": "Deletes a report schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "schedule_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen or schedule ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Schedule not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/reports/{report_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Serves the file of a generated report. For the kitchen owner, staff with the payouts permission and admins",
+                "produces": [
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Downloads a report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "report_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen or report ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Report could not be read",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets reviews from database. Each review tells whether it is a verified purchase, written by the customer of its order at the reviewed kitchen. Filtering by rating or verified purchase is done by the gateway, which then counts the total and average rating of the matching reviews",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Gets reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Lowest rating, 1 to 5",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Highest rating, 1 to 5",
+                        "name": "max_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only verified purchases",
+                        "name": "verified_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/review.Reviews"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/rules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells the minimum order amount, delivery distance and preparation lead time orders at the kitchen must meet. Zero means no limit",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen ordering rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenRules"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the rules orders at the kitchen must meet; zero turns a rule off. Orders breaking a rule are refused with 422 and the rule's name. For the kitchen's owner and admins",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Sets kitchen ordering rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ordering rules",
+                        "name": "rules",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewKitchenRules"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenRules"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or rules",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/staff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the kitchen's staff and pending invitations, oldest first. For the kitchen owner and admins",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen staff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaffMembers"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Invites a user to help run the kitchen with limited permissions: orders (orders, refunds and support tickets), menu (dish stock) and payouts (earnings, payouts and statistics reports). The user must accept the invitation. For the kitchen owner and admins",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Invites kitchen staff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User and permissions",
+                        "name": "staff",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewStaffMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaffMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID, user or permissions",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "User is already staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/staff/accept": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Joins the kitchen as staff, after which the invitation's permissions apply",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Accepts a staff invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaffMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No invitation to the kitchen",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Already staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/staff/{user_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the permissions of a staff member or invitation. For the kitchen owner and admins",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Updates staff permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permissions",
+                        "name": "permissions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StaffPermissionsUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaffMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or permissions",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "User is not staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a staff member or withdraws an invitation. Staff may also remove themselves to leave the kitchen or decline an invitation",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Removes kitchen staff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or the staff member",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "User is not staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/statistics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Informs about kitchen statistics by date. Dates are in the kitchen's time zone. Sandbox orders are left out",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen's statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, unless period is given",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, unless period is given",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "last_7_days",
+                            "last_30_days",
+                            "last_90_days",
+                            "this_month",
+                            "last_month",
+                            "this_year"
+                        ],
+                        "type": "string",
+                        "description": "Days up to today in place of the dates",
+                        "name": "period",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/extra.Statistics"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID, date or period",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/statistics/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Renders kitchen statistics by date as a CSV or PDF file, leaving out sandbox orders. For the kitchen owner, staff with the payouts permission and admins",
+                "produces": [
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Exports kitchen's statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, unless period is given",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, unless period is given",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "last_7_days",
+                            "last_30_days",
+                            "last_90_days",
+                            "this_month",
+                            "last_month",
+                            "this_year"
+                        ],
+                        "type": "string",
+                        "description": "Days up to today in place of the dates",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID, date, period or format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/stock": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists today's stock of the kitchen's dishes that have one",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DishStocks"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or menu staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/time-zone": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells the IANA time zone the kitchen's working hours, statistics, earnings, discounts and meal plan deliveries are in. Default is set when the kitchen has not chosen one and is in the gateway's default zone",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen time zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenTimeZone"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the IANA time zone, such as Asia/Tashkent, the kitchen's working hours, statistics, earnings, discounts and meal plan deliveries are read in. For the kitchen's owner and admins",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Sets kitchen time zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Time zone",
+                        "name": "time_zone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewKitchenTimeZone"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenTimeZone"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or time zone",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/translations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the kitchen's name and description in every locale it was translated into. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Lists a kitchen's translations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Translations"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or menu staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/translations/{locale}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the kitchen's name and description in a locale, shown to users whose Accept-Language prefers it. An empty field leaves the kitchen's own. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Translates a kitchen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "uz, ru or en",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Translation",
+                        "name": "translation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewTranslation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Translation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID, locale or translation",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or menu staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes the kitchen's translation into a locale, so its own name and description are shown again. For the kitchen's owner and staff with the menu permission",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Deletes a kitchen's translation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "uz, ru or en",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner or menu staff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Translation not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/verification": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the kitchen's verification status and submitted documents",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Gets kitchen verification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KitchenVerification"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the kitchen's owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists webhooks registered for a kitchen",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Gets webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhooks"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Registers a callback URL for kitchen order events. Only the kitchen's owner manages its webhooks, and their URLs must lead to public addresses. The signing secret is only returned here",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Creates a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook info",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or webhook data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/webhooks/{webhook_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a webhook of a kitchen",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Gets a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen or webhook ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates URL and subscribed events of a webhook",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Updates a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook info",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen or webhook ID or webhook data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a webhook and its delivery log",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Deletes a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen or webhook ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/webhooks/{webhook_id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the most recent delivery attempts of a webhook",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Gets webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDeliveries"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen or webhook ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/working-hours": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets working hours for kitchen, in the kitchen's time zone",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Sets working hours",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Working hours",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/extra.DaySchedule"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/extra.WorkingHoursResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID or data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchens/{id}/zones/eta": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells the road distance, delivery fee and arrival time from the kitchen to each active delivery zone the kitchen can reach",
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Estimates delivery to every zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeliveryEstimates"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Kitchen location not set",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/links": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the caller's short links with their clicks, newest first. Admins see every link",
+                "tags": [
+                    "link"
+                ],
+                "summary": "Gets short links",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Links"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Makes a short share link to a kitchen or dish page of the web app. Opening the link counts a click and redirects to the page",
+                "tags": [
+                    "link"
+                ],
+                "summary": "Creates a short link",
+                "parameters": [
+                    {
+                        "description": "Link target",
+                        "name": "link",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewLink"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Link"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid link data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen or dish not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/links/{code}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a short link with its clicks",
+                "tags": [
+                    "link"
+                ],
+                "summary": "Gets a short link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Link"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the link's creator",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a short link, after which it no longer redirects",
+                "tags": [
+                    "link"
+                ],
+                "summary": "Deletes a short link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the link's creator",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/meal-plans": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the caller's meal plans, newest first. Admins may list the plans of any user",
+                "tags": [
+                    "meal plan"
+                ],
+                "summary": "Gets meal plans",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, for admins",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "paused",
+                            "canceled",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "Plan status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPlans"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a weekly plan of deliveries from a kitchen. The gateway places an order for each delivery ahead of its time and notifies the customer's feed when an order fails; three failures in a row pause the plan. A plan made in the sandbox places test orders",
+                "tags": [
+                    "meal plan"
+                ],
+                "summary": "Subscribes to a meal plan",
+                "parameters": [
+                    {
+                        "description": "Plan",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewMealPlan"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid meal plan",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Token has no user id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen or dish not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/meal-plans/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a meal plan with its next delivery. For its subscriber and admins",
+                "tags": [
+                    "meal plan"
+                ],
+                "summary": "Gets a meal plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid meal plan ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Meal plan not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the kitchen, address, deliveries and dates of a plan that is active or paused. Orders already placed are not changed",
+                "tags": [
+                    "meal plan"
+                ],
+                "summary": "Updates a meal plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Plan",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewMealPlan"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid meal plan",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Meal plan, kitchen or dish not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Meal plan is over",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/meal-plans/{id}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the orders the gateway placed or failed to place for the plan, newest delivery first",
+                "tags": [
+                    "meal plan"
+                ],
+                "summary": "Gets meal plan orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPlanRuns"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid meal plan ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Meal plan not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/meal-plans/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves an active or paused plan to active, paused or canceled. Resuming schedules the next delivery that is far enough ahead and clears failures",
+                "tags": [
+                    "meal plan"
+                ],
+                "summary": "Pauses, resumes or cancels a meal plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPlanStatus"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Meal plan not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Meal plan is over",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets orders from database",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Gets orders for customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.OrdersCustomer"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Inserts a new order into database. Item modifiers are validated against the dish's modifier schema and priced at the gateway.\nItems may carry a note for the kitchen and the order a note with special instructions; notes are limited in length and refused with 422 when moderation rejects them.\nDelivery preferences, such as contact-free delivery or an intercom code, are kept with the order for whoever delivers it.\nActive dish discounts are applied, and the response's pricing shows each item's original and discounted price.\nIf total_amount is sent, it must match the total recomputed from current prices, discounts and fees.\nDishes with a daily stock must have enough portions left, and the order must meet the kitchen's ordering rules\nThe delivery location is required, and the delivery fee is priced by the road distance to it from the kitchen.\nOrders are screened for fraud: some must wait until the customer passes an OTP, others are held for an admin's review and placed once approved.\nWith async=true the order is placed in the background and 202 is answered at once with a token to follow it by, for clients on unreliable connections\nOrders placed in the sandbox are test data: they skip fraud screening, are paid with fake money and are left out of statistics and earnings",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Creates an order",
+                "parameters": [
+                    {
+                        "description": "Order info",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewOrder"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Where the customer's phone is, as lat,lng",
+                        "name": "X-Device-Location",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Place the order in the background",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.NewOrderResp"
+                        }
+                    },
+                    "202": {
+                        "description": "Order held for review, or models.OrderPlacement when async",
+                        "schema": {
+                            "$ref": "#/definitions/models.FraudHold"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The customer must pass an OTP first, see challenge",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Submitted total does not match current prices, or too few portions of a dish are left",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Order breaks a kitchen rule, named by rule, a note was refused or the location can't be routed to",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/placements/{token}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells how placing an order sent with async=true went: pending, placed with its order_id, held for fraud review or failed. Held and failed placements carry the status and body the order would have been answered with. Placements are kept for 24 hours",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Gets an order placed in the background",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Placement token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderPlacement"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Placement not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/quote": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns an itemized price (subtotal, fees, tax, discounts) for the items without creating an order. Each item shows its unit price before and after any active dish discount\nWith kitchen_id and location the delivery fee is priced by the road distance from the kitchen instead of distance_km, as orders always are\nDishes holding allergens in the customer's allergy profile are listed in warnings",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Quotes an order",
+                "parameters": [
+                    {
+                        "description": "Order items",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.QuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pricing.Quote"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Kitchen location not set, location not covered or unreachable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets order from database, with its notes and delivery preferences. Orders that got dish discounts include their pricing. For the customer, the kitchen's owner and staff with the orders permission, and admins",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Gets an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.OrderInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the order's customer or kitchen",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/delivery": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells where, when and how to hand the order over: address, time, delivery preferences and notes. For the customer, the kitchen's owner and staff with the orders permission, and admins",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Gets an order's delivery details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the order's customer or kitchen",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/delivery-preferences": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces how the customer wants the order handed over, such as leaving it at the door or the intercom code, until it is delivered. For the customer and admins",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Sets an order's delivery preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delivery preferences",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DeliveryPreferences"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeliveryPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID or preferences",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the order's customer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Order already delivered or canceled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/receipt": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Queues the receipt of the order for the caller's email, in the language of the request. Receipts are also emailed when an order is delivered",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Emails an order receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Email"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID or caller has no email",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Order belongs to another user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Email delivery is not configured",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/refund-request": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Asks the kitchen to refund an order. Evidence holds IDs of completed attachment uploads, e.g. photos of the food",
+                "tags": [
+                    "refund"
+                ],
+                "summary": "Requests a refund",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewRefundRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RefundRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID or refund request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Order belongs to another user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Order already has a refund request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/refund-requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists refund requests of an order for its customer, its kitchen or admins",
+                "tags": [
+                    "refund"
+                ],
+                "summary": "Gets refund requests of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RefundRequests"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates order status in database. Once the kitchen responds, its order timer no longer applies to the order",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Updates an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/order.StatusNoID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.UpdatedOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/status/poll": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Long poll for clients that can't keep a WebSocket open. Answers at once when the status's version differs from since_version, otherwise when the status changes or after 30 seconds with the status unchanged; either way, poll again with the version answered. For the customer, the kitchen and admins",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Waits for an order's status to change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version of the status the client has, 0 for none",
+                        "name": "since_version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID or since_version",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the order's customer or kitchen",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/otp": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Texts a code to the phone to prove the caller owns it. A new code can be asked for once the resend wait has passed, and replaces the old one",
+                "tags": [
+                    "sms"
+                ],
+                "summary": "Sends a one-time code",
+                "parameters": [
+                    {
+                        "description": "Phone in international format",
+                        "name": "phone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewOTP"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OTPSent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Code was sent recently",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "SMS could not be sent",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/otp/verify": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Checks the code texted to the phone. A code works once, and only for the user it was sent to; after five wrong tries a new one must be sent. Passing it lifts an automatic abuse challenge on the account and lets held-back orders and payments needing verification go through",
+                "tags": [
+                    "sms"
+                ],
+                "summary": "Verifies a one-time code",
+                "parameters": [
+                    {
+                        "description": "Phone and code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OTPVerification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OTPVerified"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone or wrong code",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No code was sent to the phone",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Code expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong tries",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners/kitchens/delta": {
+            "get": {
+                "description": "For partner aggregators: the kitchens and dishes created, updated or deleted at or after since, oldest first, each with the entity as it was after the change. Pages are linked in Link headers. Use the synced_at of the first page as the since of the next sync. Changes are kept for PARTNER_DELTA_RETENTION_DAYS and since the gateway started; a since before that is answered with 410 and needs a full crawl",
+                "tags": [
+                    "partner"
+                ],
+                "summary": "Syncs changed kitchens and dishes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Partner API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "since",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of changes per page, up to 1000",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogDelta"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid since or pagination parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Changes since then are not kept",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners/orders": {
+            "post": {
+                "description": "For partner aggregators approved to place orders. Requests are signed with the key's secret: X-Signature is \"sha256=\" and the hex HMAC-SHA256 of \"\u003cX-Timestamp\u003e.\u003cmethod\u003e.\u003cpath and query\u003e.\u003cbody\u003e\", such as \"1760745600.POST./local-eats/partners/orders.{...}\", with X-Timestamp the current Unix time.\nThe partner's customer is mapped to a user of its own by external_customer_id, the same for all their orders. The order is priced, checked and placed like a customer's, without the fraud screening of customers' own orders.\nexternal_order_id makes the request idempotent: sending it again returns the order placed the first time with Idempotent-Replayed: true, or 409 while the first is still being placed. Reusing it for a different order is refused with 422.\nStatus changes are sent to the key's webhook_url as order.status_changed, signed the same way\nOrders placed with sandbox keys are test data, left out of statistics and earnings",
+                "tags": [
+                    "partner"
+                ],
+                "summary": "Places an order for a partner's customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Partner API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix time of the request",
+                        "name": "X-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of the request",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewPartnerOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartnerOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or signature",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Partner not approved to place orders",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "The order is still being placed, its total does not match current prices, or too few portions of a dish are left",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "external_order_id was used for a different order, or the order breaks a kitchen rule",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/partners/orders/{external_id}": {
+            "get": {
+                "description": "Gives the order placed for the partner's external order ID with its current status, for partners to reconcile with. Signed like order requests",
+                "tags": [
+                    "partner"
+                ],
+                "summary": "Gets a partner's order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Partner API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix time of the request",
+                        "name": "X-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of the request",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "External order ID",
+                        "name": "external_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartnerOrder"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or signature",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Partner not approved to place orders",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pays for an order through the requested or the default provider. Hosted checkouts (payme, click) answer with a pending payment and a redirect_url; stripe expects a Stripe.js payment_token instead of card details\nPayments are screened for fraud: some must wait until the customer passes an OTP, others are held for an admin's review and can be tried again once approved\nSandbox orders are paid with fake money by the sandbox provider, whatever provider is asked for: card 4000000000000002 is declined, 4000000000003220 must authenticate, and any other card or token is paid. Orders can only be paid in the mode they were placed in",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Creates a payment",
+                "parameters": [
+                    {
+                        "description": "Payment info",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewPayment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    },
+                    "202": {
+                        "description": "The customer must authenticate the payment (3-D Secure), see challenge; or models.FraudHold when held for review",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment declined",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The customer must pass an OTP first, see challenge, or the payment was refused after review",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "The order was placed in the other of sandbox and live mode",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Payment provider error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/chargebacks/{provider}": {
+            "post": {
+                "description": "Endpoint for payment providers to report chargebacks, such as stripe's charge.dispute.* events. Calls are authenticated with the provider's signature, not a token. A new chargeback flags its customer for fraud screening and is emailed to the admins",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Receives chargeback notifications",
+                "parameters": [
+                    {
+                        "enum": [
+                            "stripe"
+                        ],
+                        "type": "string",
+                        "description": "Provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Provider-specific reply",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid notification",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown provider or provider sends no chargebacks",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/providers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the payment providers that are configured and the default one",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Gets payment providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentProviders"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/tokenize": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Exchanges card details for a token to pay with in CreatePayment's payment_token, so the card number is sent only once. Internal tokens are single-use and expire after 15 minutes",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Tokenizes a card",
+                "parameters": [
+                    {
+                        "description": "Card details",
+                        "name": "card",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewCardToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CardToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid card details or provider",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "402": {
+                        "description": "Card declined",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Payment provider error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhooks/{provider}": {
+            "post": {
+                "description": "Endpoint for stripe, payme and click to report payment changes. Calls are authenticated with the provider's signature or credentials, not a token",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Receives payment provider notifications",
+                "parameters": [
+                    {
+                        "enum": [
+                            "stripe",
+                            "payme",
+                            "click"
+                        ],
+                        "type": "string",
+                        "description": "Provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Provider-specific reply",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid notification",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown provider",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves payment info; payments of external providers are answered by the gateway. Card numbers are masked and the CVV is never returned",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Gets a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payment.PaymentDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Completes a payment after the customer has answered its 3-D Secure challenge. A payment whose challenge is still open is answered with 409",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Confirms a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment declined",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment needs no confirmation or authentication is not complete",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Payment provider error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/refund-requests/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Approves a pending request and asks the payment service to refund it through the refund.approved event",
+                "tags": [
+                    "refund"
+                ],
+                "summary": "Approves a refund request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Refund request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decision comment",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RefundDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RefundRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid refund request ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Only the kitchen or an admin can decide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Refund request not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Refund request is already decided, or is more than is left of the payment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/refund-requests/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Rejects a pending request; the customer may then file a new one",
+                "tags": [
+                    "refund"
+                ],
+                "summary": "Rejects a refund request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Refund request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decision comment",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RefundDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RefundRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid refund request ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Only the kitchen or an admin can decide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Refund request not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Refund request is already decided",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Inserts a new review into database. The comment is moderated first: reviews with profanity or links are refused, and suspicious ones are held for an admin's approval",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Creates a review",
+                "parameters": [
+                    {
+                        "description": "Review info",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/review.NewReview"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/review.NewReviewResp"
+                        }
+                    },
+                    "202": {
+                        "description": "Review held for moderation",
+                        "schema": {
+                            "$ref": "#/definitions/models.HeldReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Review refused by moderation",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/sms/callbacks/{provider}": {
+            "post": {
+                "description": "Endpoint for eskiz, playmobile and twilio to report whether messages were delivered. Twilio signs its calls; the others must pass the callback secret in the secret query",
+                "tags": [
+                    "sms"
+                ],
+                "summary": "Receives SMS delivery reports",
+                "parameters": [
+                    {
+                        "enum": [
+                            "eskiz",
+                            "playmobile",
+                            "twilio"
+                        ],
+                        "type": "string",
+                        "description": "Provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid report",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown provider",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/support/tickets": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the caller's tickets, the tickets of a kitchen they own, or any tickets for admins, newest first",
+                "tags": [
+                    "support"
+                ],
+                "summary": "Gets support tickets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "kitchen_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "in_progress",
+                            "resolved",
+                            "closed"
+                        ],
+                        "type": "string",
+                        "description": "Ticket status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tickets"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Kitchen belongs to another user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Opens a ticket against one of the caller's orders. Attachments are IDs of completed uploads with the attachment purpose",
+                "tags": [
+                    "support"
+                ],
+                "summary": "Opens a support ticket",
+                "parameters": [
+                    {
+                        "description": "Ticket info",
+                        "name": "ticket",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewTicket"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Ticket"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticket data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Order belongs to another user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/support/tickets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a ticket with its conversation",
+                "tags": [
+                    "support"
+                ],
+                "summary": "Gets a support ticket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Ticket"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticket ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No access to the ticket",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Ticket not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a ticket. Only admins may delete tickets",
+                "tags": [
+                    "support"
+                ],
+                "summary": "Deletes a support ticket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticket ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role is required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Ticket not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/support/tickets/{id}/messages": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a message to the ticket conversation. A reply from the kitchen or an admin moves an open ticket to in_progress",
+                "tags": [
+                    "support"
+                ],
+                "summary": "Replies to a support ticket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewTicketMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Ticket"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticket ID or message",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No access to the ticket",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Ticket not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Ticket is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/support/tickets/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves a ticket along open, in_progress, resolved and closed. Customers may only close or reopen their tickets",
+                "tags": [
+                    "support"
+                ],
+                "summary": "Changes a ticket's status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TicketStatus"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Ticket"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticket ID or status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No access to the ticket or the transition",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Ticket not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Transition is not allowed from the current status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Opens a resumable upload for a dish image or a bulk import file. Chunks are then sent with PATCH /uploads/{id}. Only the uploader and admins may change, delete or attach the upload",
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Starts an upload",
+                "parameters": [
+                    {
+                        "description": "Upload info",
+                        "name": "upload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewUpload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Upload"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid upload data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reports upload progress; offset is where the next chunk has to start. HEAD returns the same in Upload-Offset and Upload-Length headers",
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Gets an upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Upload"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid upload ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the uploader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels an upload or removes a completed one",
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Deletes an upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid upload ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the uploader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Appends the request body at the position given by Content-Range (bytes start-end/size). A chunk must start at the current offset; otherwise 409 returns the offset to resume from",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Uploads a chunk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "bytes start-end/size",
+                        "name": "Content-Range",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Upload"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid upload ID or Content-Range",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the uploader",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Chunk does not start at the current offset",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads/{id}/content": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Serves a completed upload. Range requests are supported, so interrupted downloads can be resumed. For the uploader, admins and those with access to the ticket, refund request or kitchen verification it is attached to; inspection documents are for everyone",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Downloads an upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid upload ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No access to the upload",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Upload is not completed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves user info from database",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Gets a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates user info in database",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Updates a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User info",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.NewInfoNoID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Details"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes user from database",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Deletes a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Void"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/activity": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Informs about user's activity by date",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Tracks user's activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, unless period is given",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, unless period is given",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "yesterday",
+                            "last_7_days",
+                            "last_30_days",
+                            "last_90_days",
+                            "this_month",
+                            "last_month",
+                            "this_year"
+                        ],
+                        "type": "string",
+                        "description": "Days up to today in place of the dates",
+                        "name": "period",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/extra.Activity"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID, date or period",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/allergies": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the allergens the user avoids. For the user and admins",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Gets allergy profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AllergyProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the allergens the user avoids, as tags from GET /allergens. Order quotes warn about dishes that contain any of them. For the user and admins",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Sets allergy profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Allergens",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewAllergyProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AllergyProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or allergens",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/blocked-kitchens": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the kitchens the user blocked, oldest first",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Gets blocked kitchens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BlockedKitchens"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Hides a kitchen from the user's kitchen lists, searches and feed. The kitchen's own page stays reachable",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Blocks a kitchen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Kitchen to block",
+                        "name": "kitchen",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewBlockedKitchen"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BlockedKitchens"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user or kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/blocked-kitchens/{kitchen_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Shows a blocked kitchen to the user again",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Unblocks a kitchen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kitchen ID",
+                        "name": "kitchen_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user or kitchen ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Kitchen is not blocked",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/feed": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists orders placed and reviews written by the user, newest first, with current order status and kitchen names. The active announcements of the kitchens in the feed are mixed in by their start. Activity at kitchens the user blocked is left out",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Gets user's activity feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feed"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or pagination parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Gateway overloaded, retry later",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/notification-settings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells which channels, push, SMS and email, the user gets order updates, promos and review notifications through. Users who never changed them get everything",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Gets notification settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Turns channels on or off per kind of notification. Kinds and channels left out of the body keep their settings",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Updates notification settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notification settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewNotificationSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or settings",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/nutrition-summary": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sums the calories and macros of the dishes in the user's orders delivered on the day, in the gateway's time zone, from the nutrition info of each dish. Canceled orders are left out. For the user and admins",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Gets a day's nutrition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day as YYYY-MM-DD, today by default",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NutritionSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or date",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/searches/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the user's recent searches, newest first",
+                "tags": [
+                    "search"
+                ],
+                "summary": "Gets recent searches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of searches to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a kitchen search to the user's recent searches. Repeating a search moves it to the top",
+                "tags": [
+                    "search"
+                ],
+                "summary": "Records a search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Search parameters",
+                        "name": "search",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchQuery"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchHistoryEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or search data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes all recent searches of the user",
+                "tags": [
+                    "search"
+                ],
+                "summary": "Clears recent searches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/searches/saved": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the user's saved searches, newest first",
+                "tags": [
+                    "search"
+                ],
+                "summary": "Gets saved searches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearches"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Saves a named kitchen search. With notify set, the user is notified when a new kitchen matching it opens. A search with radius_km matches kitchens within that distance of lat and lng, so it is notified of once the new kitchen sets its location",
+                "tags": [
+                    "search"
+                ],
+                "summary": "Saves a search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Saved search info",
+                        "name": "search",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewSavedSearch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or search data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/searches/saved/{search_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the name, parameters or notification opt-in of a saved search",
+                "tags": [
+                    "search"
+                ],
+                "summary": "Updates a saved search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Saved search ID",
+                        "name": "search_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Saved search info",
+                        "name": "search",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NewSavedSearch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user or search ID or search data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a saved search and stops its notifications",
+                "tags": [
+                    "search"
+                ],
+                "summary": "Deletes a saved search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Saved search ID",
+                        "name": "search_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user or search ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/staff-kitchens": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the kitchens the user is staff of or invited to",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Gets the user's staff kitchens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaffMembers"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dish.DishDetails": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dish.DishInfo": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "available": {
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dietary_info": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nutrition_info": {
+                    "$ref": "#/definitions/dish.NutritionalInfo"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dish.Dishes": {
+            "type": "object",
+            "properties": {
+                "dishes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dish.DishDetails"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dish.NewDataNoID": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dish.NewDishResp": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dish.NutritionalInfo": {
+            "type": "object",
+            "properties": {
+                "calories": {
+                    "type": "integer"
+                },
+                "carbs": {
+                    "type": "integer"
+                },
+                "fat": {
+                    "type": "integer"
+                },
+                "protein": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dish.UpdatedData": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "extra.Activity": {
+            "type": "object",
+            "properties": {
+                "favorite_cuisines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/extra.Cuisine"
+                    }
+                },
+                "favorite_kitchens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/extra.Kitchen"
+                    }
+                },
+                "total_orders": {
+                    "type": "integer"
+                },
+                "total_spent": {
+                    "type": "number"
+                }
+            }
+        },
+        "extra.Cuisine": {
+            "type": "object",
+            "properties": {
+                "cuisine_type": {
+                    "type": "string"
+                },
+                "orders_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "extra.DaySchedule": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "string"
+                },
+                "open": {
+                    "type": "string"
+                }
+            }
+        },
+        "extra.Dish": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "orders_count": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                }
+            }
+        },
+        "extra.Kitchen": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "orders_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "extra.NutritionalInfo": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "calories": {
+                    "type": "integer"
+                },
+                "carbs": {
+                    "type": "integer"
+                },
+                "dietary_info": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "fat": {
+                    "type": "integer"
+                },
+                "protein": {
+                    "type": "integer"
+                }
+            }
+        },
+        "extra.Statistics": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "top_dishes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/extra.Dish"
+                    }
+                },
+                "total_orders": {
+                    "type": "integer"
+                },
+                "total_revenue": {
+                    "type": "number"
+                }
+            }
+        },
+        "extra.WorkingHoursResp": {
+            "type": "object",
+            "properties": {
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/extra.DaySchedule"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "kitchen.CreateRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "cuisine_type": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "kitchen.CreateResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cuisine_type": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                }
+            }
+        },
+        "kitchen.Info": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cuisine_type": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "total_orders": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "kitchen.KitchenDetails": {
+            "type": "object",
+            "properties": {
+                "cuisine_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "total_orders": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kitchen.Kitchens": {
+            "type": "object",
+            "properties": {
+                "kitchens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kitchen.KitchenDetails"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kitchen.NewDataNoID": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "kitchen.UpdatedData": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "cuisine_type": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIChange": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "successor": {
+                    "type": "string"
+                },
+                "sunset": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIUsageReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RouteUsage"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIVersion": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.APIChange"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AbuseFlag": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "signal": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AbuseProfile": {
+            "type": "object",
+            "properties": {
+                "flags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AbuseFlag"
+                    }
+                },
+                "manual": {
+                    "description": "Manual tells whether the status was set by an admin, who keeps it\nuntil setting it back to automatic.",
+                    "type": "boolean"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "passed_at": {
+                    "description": "PassedAt is when the account last passed a challenge.",
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AbuseProfiles": {
+            "type": "object",
+            "properties": {
+                "profiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AbuseProfile"
+                    }
+                }
+            }
+        },
+        "models.AbuseReview": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AllergenList": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.AllergenWarning": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dish_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AllergyProfile": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Announcement": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Kind is notice or banner, notice by default.",
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "description": "StartsAt defaults to now. Without EndsAt the announcement stays\nuntil it is deleted.",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status follows from the announcement's times when it is read.",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Announcements": {
+            "type": "object",
+            "properties": {
+                "announcements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Announcement"
+                    }
+                }
+            }
+        },
+        "models.AppVersionReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "minimums": {
+                    "$ref": "#/definitions/models.AppVersions"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AppVersionUsage"
+                    }
+                }
+            }
+        },
+        "models.AppVersionUsage": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
+                },
+                "client_errors": {
+                    "type": "integer"
+                },
+                "error_rate": {
+                    "type": "number"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "server_errors": {
+                    "type": "integer"
+                },
+                "supported": {
+                    "type": "boolean"
+                },
+                "upgrades": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AppVersions": {
+            "type": "object",
+            "properties": {
+                "android_store_url": {
+                    "type": "string"
+                },
+                "ios_store_url": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "min_android": {
+                    "type": "string"
+                },
+                "min_ios": {
+                    "type": "string"
+                },
+                "min_web": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Availability": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "dish_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BackendSLO": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "$ref": "#/definitions/models.SLOStatus"
+                },
+                "backend": {
+                    "type": "string"
+                },
+                "latency": {
+                    "$ref": "#/definitions/models.SLOStatus"
+                }
+            }
+        },
+        "models.BatchResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BlockedKitchen": {
+            "type": "object",
+            "properties": {
+                "blocked_at": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "kitchen_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BlockedKitchens": {
+            "type": "object",
+            "properties": {
+                "kitchens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BlockedKitchen"
+                    }
+                }
+            }
+        },
+        "models.Broadcast": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "channels": {
+                    "description": "Channels are push, email or both.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "segment": {
+                    "$ref": "#/definitions/models.BroadcastSegment"
+                },
+                "send_at": {
+                    "description": "SendAt defaults to now.",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "stats": {
+                    "$ref": "#/definitions/models.BroadcastStats"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BroadcastSegment": {
+            "type": "object",
+            "properties": {
+                "active_since": {
+                    "description": "ActiveSince selects users with activity at or after the time, in\nRFC 3339 or as a date.",
+                    "type": "string"
+                },
+                "cuisines": {
+                    "description": "Cuisines selects users who searched for any of the cuisine types.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kitchen_ids": {
+                    "description": "KitchenIds selects users who ordered from, reviewed or have a meal\nplan at any of the kitchens.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.BroadcastStats": {
+            "type": "object",
+            "properties": {
+                "emailed": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "pushed": {
+                    "type": "integer"
+                },
+                "recipients": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Broadcasts": {
+            "type": "object",
+            "properties": {
+                "broadcasts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Broadcast"
+                    }
+                }
+            }
+        },
+        "models.BurnRate": {
+            "type": "object",
+            "properties": {
+                "rate": {
+                    "type": "number"
+                },
+                "window": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CardToken": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "last4": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CatalogChange": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "object"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CatalogDelta": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogChange"
+                    }
+                },
+                "since": {
+                    "type": "string"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Changelog": {
+            "type": "object",
+            "properties": {
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.APIVersion"
+                    }
+                }
+            }
+        },
+        "models.Chargeback": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Chargebacks": {
+            "type": "object",
+            "properties": {
+                "chargebacks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Chargeback"
+                    }
+                }
+            }
+        },
+        "models.Churn": {
+            "type": "object",
+            "properties": {
+                "churn_rate": {
+                    "type": "number"
+                },
+                "churned_customers": {
+                    "type": "integer"
+                },
+                "current_customers": {
+                    "type": "integer"
+                },
+                "new_customers": {
+                    "type": "integer"
+                },
+                "previous_customers": {
+                    "type": "integer"
+                },
+                "previous_end_date": {
+                    "type": "string"
+                },
+                "previous_start_date": {
+                    "type": "string"
+                },
+                "retained_customers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CityRevenue": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "kitchens": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CommissionChange": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "new_percent": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "old_percent": {
+                    "type": "number"
+                },
+                "scope": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CommissionHistory": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CommissionChange"
+                    }
+                }
+            }
+        },
+        "models.CommissionRate": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CommissionRates": {
+            "type": "object",
+            "properties": {
+                "default_percent": {
+                    "type": "number"
+                },
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CommissionRate"
+                    }
+                }
+            }
+        },
+        "models.Coverage": {
+            "type": "object",
+            "properties": {
+                "covered": {
+                    "type": "boolean"
+                },
+                "zone": {
+                    "$ref": "#/definitions/models.CoverageZone"
+                }
+            }
+        },
+        "models.CoverageZone": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatedInvitation": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "accepted_by": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invited_by": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "kitchen_name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatedPartnerKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "boolean"
+                },
+                "partner": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "sandbox": {
+                    "description": "Sandbox keys place test orders, paid with fake money and left out\nof statistics, for partners to integrate with.",
+                    "type": "boolean"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "webhook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Cuisine": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Aliases are other spellings accepted for the cuisine.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the canonical name kitchens are stored and searched with.",
+                    "type": "string"
+                },
+                "names": {
+                    "description": "Names holds translations of the name by language, e.g. \"ru\".",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Cuisines": {
+            "type": "object",
+            "properties": {
+                "cuisines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Cuisine"
+                    }
+                }
+            }
+        },
+        "models.DataAccessEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DataRequest": {
+            "type": "object",
+            "properties": {
+                "complete": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DataSection"
+                    }
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Size and Sha256 are of the encrypted package, so the recipient can\ncheck it.",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DataRequestDetails": {
+            "type": "object",
+            "properties": {
+                "audit": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DataAccessEntry"
+                    }
+                },
+                "complete": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DataSection"
+                    }
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Size and Sha256 are of the encrypted package, so the recipient can\ncheck it.",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DataRequests": {
+            "type": "object",
+            "properties": {
+                "requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DataRequest"
+                    }
+                }
+            }
+        },
+        "models.DataSection": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "partial": {
+                    "type": "string"
+                },
+                "records": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DeliveryEstimate": {
+            "type": "object",
+            "properties": {
+                "arrives_at": {
+                    "type": "string"
+                },
+                "delivery_fee": {
+                    "type": "number"
+                },
+                "distance_km": {
+                    "type": "number"
+                },
+                "eta_minutes": {
+                    "type": "integer"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "prep_minutes": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "description": "Provider is the routing service the distance came from, or\nstraight_line when it was estimated without one.",
+                    "type": "string"
+                },
+                "travel_minutes": {
+                    "type": "integer"
+                },
+                "zone": {
+                    "$ref": "#/definitions/models.CoverageZone"
+                }
+            }
+        },
+        "models.DeliveryEstimates": {
+            "type": "object",
+            "properties": {
+                "estimates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeliveryEstimate"
+                    }
+                }
+            }
+        },
+        "models.DeliveryPreferences": {
+            "type": "object",
+            "properties": {
+                "call_on_arrival": {
+                    "type": "boolean"
+                },
+                "intercom_code": {
+                    "type": "string"
+                },
+                "leave_at_door": {
+                    "description": "LeaveAtDoor asks for contact-free delivery.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DishAlert": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dish_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DishDiscount": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active tells whether the discount applies when it is read.",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "days": {
+                    "description": "Days are weekday names such as \"friday\", in the kitchen's time\nzone.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dish_id": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "from": {
+                    "description": "From and Until are times of day as HH:MM in the kitchen's time\nzone, the happy hour. Until may be before From for windows that\npass midnight.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "starts_at": {
+                    "description": "StartsAt and EndsAt are in RFC 3339.",
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DishDiscounts": {
+            "type": "object",
+            "properties": {
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DishDiscount"
+                    }
+                }
+            }
+        },
+        "models.DishIngredients": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "declared": {
+                    "description": "Declared tells whether the allergens were declared when the dish\nwas created; others were only listed by the dish service.",
+                    "type": "boolean"
+                },
+                "dish_id": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DishStock": {
+            "type": "object",
+            "properties": {
+                "daily_count": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "dish_id": {
+                    "type": "string"
+                },
+                "dish_name": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "sold": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DishStocks": {
+            "type": "object",
+            "properties": {
+                "stocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DishStock"
+                    }
+                }
+            }
+        },
+        "models.Earnings": {
+            "type": "object",
+            "properties": {
+                "commission_percent": {
+                    "type": "number"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "orders": {
+                    "description": "Orders is one page of the per-order breakdown, newest first; Total\ncounts all orders of the period.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderEarnings"
+                    }
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EarningsSummary"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.EarningsSummary"
+                }
+            }
+        },
+        "models.EarningsSummary": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "type": "number"
+                },
+                "gross": {
+                    "type": "number"
+                },
+                "net": {
+                    "type": "number"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "refunds": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.EffectiveCommission": {
+            "type": "object",
+            "properties": {
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Email": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "driver": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Emails": {
+            "type": "object",
+            "properties": {
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Email"
+                    }
+                }
+            }
+        },
+        "models.EncryptionStatus": {
+            "type": "object",
+            "properties": {
+                "data_key_id": {
+                    "type": "string"
+                },
+                "data_keys": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "rotated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeeSettings": {
+            "type": "object",
+            "properties": {
+                "commission_percent": {
+                    "type": "number"
+                },
+                "delivery_fee": {
+                    "type": "number"
+                },
+                "delivery_fee_per_km": {
+                    "type": "number"
+                },
+                "service_fee_percent": {
+                    "type": "number"
+                },
+                "tax_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Feed": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeedItem"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FeedItem": {
+            "type": "object",
+            "properties": {
+                "announcement": {
+                    "description": "Announcement items occur when the announcement starts.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FeedKitchenAnnouncement"
+                        }
+                    ]
+                },
+                "meal_plan": {
+                    "$ref": "#/definitions/models.FeedMealPlan"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "order": {
+                    "$ref": "#/definitions/models.FeedOrder"
+                },
+                "review": {
+                    "$ref": "#/definitions/models.FeedReview"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeedKitchenAnnouncement": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "kitchen_name": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeedMealPlan": {
+            "type": "object",
+            "properties": {
+                "delivery_time": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "paused": {
+                    "description": "Paused is set when the failure paused the plan.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.FeedOrder": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "kitchen_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.FeedReview": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "kitchen_name": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.FraudCase": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FraudHit"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "order": {
+                    "description": "Order is the held order, for order cases.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NewOrder"
+                        }
+                    ]
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FraudCases": {
+            "type": "object",
+            "properties": {
+                "cases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FraudCase"
+                    }
+                }
+            }
+        },
+        "models.FraudDecision": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FraudHit": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FraudHold": {
+            "type": "object",
+            "properties": {
+                "case_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GroupCheckout": {
+            "type": "object",
+            "properties": {
+                "total_amount": {
+                    "description": "TotalAmount, if sent, must match the total of current prices.",
+                    "type": "number"
+                }
+            }
+        },
+        "models.GroupItem": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "dish_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Selection"
+                    }
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GroupItemQuantity": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GroupOrder": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_address": {
+                    "type": "string"
+                },
+                "delivery_time": {
+                    "type": "string"
+                },
+                "host_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GroupItem"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "share_code": {
+                    "type": "string"
+                },
+                "share_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GroupUpdate": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "$ref": "#/definitions/models.GroupOrder"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HeldReview": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "Reason names the check that held the review and Detail explains it.",
+                    "type": "string"
+                },
+                "review_id": {
+                    "description": "ReviewId is the published review's ID once the review is approved.",
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HeldReviews": {
+            "type": "object",
+            "properties": {
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HeldReview"
+                    }
+                }
+            }
+        },
+        "models.HourlyOrders": {
+            "type": "object",
+            "properties": {
+                "hour": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HygieneRating": {
+            "type": "object",
+            "properties": {
+                "authority": {
+                    "type": "string"
+                },
+                "inspected_at": {
+                    "type": "string"
+                },
+                "inspection_id": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Inspection": {
+            "type": "object",
+            "properties": {
+                "authority": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InspectionDocument"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inspected_at": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "recorded_by": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InspectionDocument": {
+            "type": "object",
+            "properties": {
+                "filename": {
+                    "type": "string"
+                },
+                "upload_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Invitation": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "accepted_by": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invited_by": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "kitchen_name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InvitationPrefill": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "kitchen_name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Invitations": {
+            "type": "object",
+            "properties": {
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Invitation"
+                    }
+                }
+            }
+        },
+        "models.ItemNote": {
+            "type": "object",
+            "properties": {
+                "dish_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ItemResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "data": {},
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.KeyRotation": {
+            "type": "object",
+            "properties": {
+                "data_key_id": {
+                    "type": "string"
+                },
+                "data_keys": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "files_resealed": {
+                    "type": "integer"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "outbox_resealed": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "resealed": {
+                    "type": "integer"
+                },
+                "rotated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KitchenDocument": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "upload_id": {
+                    "type": "string"
+                },
+                "uploaded_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KitchenInspections": {
+            "type": "object",
+            "properties": {
+                "inspections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Inspection"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "$ref": "#/definitions/models.HygieneRating"
+                }
+            }
+        },
+        "models.KitchenLocation": {
+            "type": "object",
+            "properties": {
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KitchenRank": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.KitchenRules": {
+            "type": "object",
+            "properties": {
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "max_distance_km": {
+                    "description": "MaxDistanceKm is how far the kitchen delivers. Orders must give\ntheir distance once it is set.",
+                    "type": "number"
+                },
+                "min_order_amount": {
+                    "description": "MinOrderAmount is the least the items must cost after dish\ndiscounts, fees not included.",
+                    "type": "number"
+                },
+                "prep_lead_minutes": {
+                    "description": "PrepLeadMinutes is how long before its delivery time an order\nmust be placed.",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KitchenTimeZone": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "type": "boolean"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KitchenVerification": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KitchenDocument"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "submitted_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KitchenVerifications": {
+            "type": "object",
+            "properties": {
+                "verifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KitchenVerification"
+                    }
+                }
+            }
+        },
+        "models.Link": {
+            "type": "object",
+            "properties": {
+                "campaign": {
+                    "type": "string"
+                },
+                "clicks": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "last_clicked_at": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_url": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Links": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Link"
+                    }
+                }
+            }
+        },
+        "models.LocalizedCuisine": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LocalizedCuisines": {
+            "type": "object",
+            "properties": {
+                "cuisines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LocalizedCuisine"
+                    }
+                },
+                "lang": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Maintenance": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MealPlan": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MealPlanDelivery"
+                    }
+                },
+                "delivery_address": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "failures": {
+                    "description": "Failures counts orders that failed in a row.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "next_delivery_at": {
+                    "description": "NextDeliveryAt is the next delivery an order will be placed for.",
+                    "type": "string"
+                },
+                "sandbox": {
+                    "description": "Sandbox marks plans made in the sandbox, whose orders are test\norders too.",
+                    "type": "boolean"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MealPlanDelivery": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItem"
+                    }
+                },
+                "time": {
+                    "type": "string"
+                },
+                "weekday": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MealPlanRun": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_time": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "plan_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MealPlanRuns": {
+            "type": "object",
+            "properties": {
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MealPlanRun"
+                    }
+                }
+            }
+        },
+        "models.MealPlanStatus": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MealPlans": {
+            "type": "object",
+            "properties": {
+                "meal_plans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MealPlan"
+                    }
+                }
+            }
+        },
+        "models.ModerationDecision": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is approved to publish the review or rejected to drop it.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModifierGroup": {
+            "type": "object",
+            "properties": {
+                "max_select": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModifierOption"
+                    }
+                },
+                "required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ModifierOption": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Modifiers": {
+            "type": "object",
+            "properties": {
+                "dish_id": {
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModifierGroup"
+                    }
+                }
+            }
+        },
+        "models.ModifiersNoID": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModifierGroup"
+                    }
+                }
+            }
+        },
+        "models.NewAllergyProfile": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NewAnnouncement": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Kind is notice or banner, notice by default.",
+                    "type": "string"
+                },
+                "starts_at": {
+                    "description": "StartsAt defaults to now. Without EndsAt the announcement stays\nuntil it is deleted.",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewBlockedKitchen": {
+            "type": "object",
+            "properties": {
+                "kitchen_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewBroadcast": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "channels": {
+                    "description": "Channels are push, email or both.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "segment": {
+                    "$ref": "#/definitions/models.BroadcastSegment"
+                },
+                "send_at": {
+                    "description": "SendAt defaults to now.",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewCardToken": {
+            "type": "object",
+            "properties": {
+                "card_number": {
+                    "type": "string"
+                },
+                "cvv": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "Provider that will charge the token; empty uses the configured\ndefault.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewCommissionRate": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "description": "Note explains the change in the audit trail.",
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.NewCuisine": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Aliases are other spellings accepted for the cuisine.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Name is the canonical name kitchens are stored and searched with.",
+                    "type": "string"
+                },
+                "names": {
+                    "description": "Names holds translations of the name by language, e.g. \"ru\".",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NewCuisineWithCode": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Aliases are other spellings accepted for the cuisine.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the canonical name kitchens are stored and searched with.",
+                    "type": "string"
+                },
+                "names": {
+                    "description": "Names holds translations of the name by language, e.g. \"ru\".",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NewDataRequest": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "description": "Kind is subject_access or legal_order.",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Reference is the case, ticket or court order number the package is\ncompiled for.",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewDish": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "description": "Allergens are tags from GET /allergens and are required; an empty\nlist declares a dish without any.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "available": {
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.NewDishAlert": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "description": "Channels defaults to push only.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NewDishDiscount": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "Days are weekday names such as \"friday\", in the kitchen's time\nzone.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "from": {
+                    "description": "From and Until are times of day as HH:MM in the kitchen's time\nzone, the happy hour. Until may be before From for windows that\npass midnight.",
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "starts_at": {
+                    "description": "StartsAt and EndsAt are in RFC 3339.",
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewDishStock": {
+            "type": "object",
+            "properties": {
+                "daily_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NewGroupItem": {
+            "type": "object",
+            "properties": {
+                "dish_id": {
+                    "type": "string"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Selection"
+                    }
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NewGroupOrder": {
+            "type": "object",
+            "properties": {
+                "delivery_address": {
+                    "type": "string"
+                },
+                "delivery_time": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/models.Point"
+                }
+            }
+        },
+        "models.NewInspection": {
+            "type": "object",
+            "properties": {
+                "authority": {
+                    "type": "string"
+                },
+                "inspected_at": {
+                    "description": "InspectedAt is the date of the inspection, YYYY-MM-DD.",
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Score is from 0, urgent improvement necessary, to 5, very good.",
+                    "type": "integer"
+                },
+                "upload_ids": {
+                    "description": "UploadIds are completed inspection_report uploads, the report and\nany certificates.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NewInvitation": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "description": "Type is kitchen_owner or staff. Staff invitations name the kitchen\nand the staff permissions to grant.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewKitchenDocument": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt is the document's expiry date (YYYY-MM-DD), if it has one.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "upload_id": {
+                    "description": "UploadId is a completed kitchen_document upload.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewKitchenRules": {
+            "type": "object",
+            "properties": {
+                "max_distance_km": {
+                    "description": "MaxDistanceKm is how far the kitchen delivers. Orders must give\ntheir distance once it is set.",
+                    "type": "number"
+                },
+                "min_order_amount": {
+                    "description": "MinOrderAmount is the least the items must cost after dish\ndiscounts, fees not included.",
+                    "type": "number"
+                },
+                "prep_lead_minutes": {
+                    "description": "PrepLeadMinutes is how long before its delivery time an order\nmust be placed.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NewKitchenTimeZone": {
+            "type": "object",
+            "properties": {
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewLink": {
+            "type": "object",
+            "properties": {
+                "campaign": {
+                    "description": "Campaign is passed on to the web app as utm_campaign.",
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is kitchen or dish.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewMealPlan": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MealPlanDelivery"
+                    }
+                },
+                "delivery_address": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "start_date": {
+                    "description": "StartDate and EndDate (YYYY-MM-DD) bound the deliveries; without\nthem the plan starts now and runs until canceled.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewNotificationSettings": {
+            "type": "object",
+            "properties": {
+                "order_updates": {
+                    "$ref": "#/definitions/models.NotificationChannels"
+                },
+                "promos": {
+                    "description": "Promos covers saved search matches and dish alerts.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NotificationChannels"
+                        }
+                    ]
+                },
+                "reviews": {
+                    "$ref": "#/definitions/models.NotificationChannels"
+                }
+            }
+        },
+        "models.NewOTP": {
+            "type": "object",
+            "properties": {
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewOrder": {
+            "type": "object",
+            "properties": {
+                "delivery_address": {
+                    "type": "string"
+                },
+                "delivery_preferences": {
+                    "$ref": "#/definitions/models.DeliveryPreferences"
+                },
+                "delivery_time": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItem"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "location": {
+                    "description": "Location is where the order is delivered. The delivery fee is\npriced by the road distance to it from the kitchen.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Point"
+                        }
+                    ]
+                },
+                "note": {
+                    "description": "Note holds special instructions for the whole order.",
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewOrderTimer": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "after_minutes": {
+                    "description": "AfterMinutes is how long an order may wait for the kitchen. Orders\nare accepted at once when it is zero.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NewPartnerKey": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "type": "boolean"
+                },
+                "partner": {
+                    "description": "Partner names the aggregator the key is issued to; its orders are\nkept under this name.",
+                    "type": "string"
+                },
+                "sandbox": {
+                    "description": "Sandbox keys place test orders, paid with fake money and left out\nof statistics, for partners to integrate with.",
+                    "type": "boolean"
+                },
+                "webhook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewPartnerOrder": {
+            "type": "object",
+            "properties": {
+                "delivery_address": {
+                    "type": "string"
+                },
+                "delivery_preferences": {
+                    "description": "DeliveryPreferences are kept with the order like a customer's.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DeliveryPreferences"
+                        }
+                    ]
+                },
+                "delivery_time": {
+                    "type": "string"
+                },
+                "external_customer_id": {
+                    "type": "string"
+                },
+                "external_order_id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItem"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.NewPayment": {
+            "type": "object",
+            "properties": {
+                "card_number": {
+                    "type": "string"
+                },
+                "cvv": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "payment_token": {
+                    "description": "PaymentToken is a payment method created client-side by the\nprovider's SDK, e.g. a Stripe PaymentMethod ID.",
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "Provider selects the payment gateway; empty uses the configured\ndefault.",
+                    "type": "string"
+                },
+                "return_url": {
+                    "description": "ReturnUrl is where hosted checkout pages send the customer back.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewPayout": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewRecording": {
+            "type": "object",
+            "properties": {
+                "minutes": {
+                    "type": "integer"
+                },
+                "note": {
+                    "description": "Note tells what the recording is for, e.g. the release it tests.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewRefundRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount to refund; zero asks for the full payment.",
+                    "type": "number"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "payment_id": {
+                    "description": "PaymentId is needed only when the payment was not made through\nthis gateway.",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewReportSchedule": {
+            "type": "object",
+            "properties": {
+                "delivery": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewRole": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NewRoleWithName": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the value of the token's role claim, up to 32 lowercase\nletters, digits or underscores.",
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NewSMSCampaign": {
+            "type": "object",
+            "properties": {
+                "phones": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewSavedSearch": {
+            "type": "object",
+            "properties": {
+                "cuisine_type": {
+                    "type": "string"
+                },
+                "lat": {
+                    "description": "With RadiusKm set only kitchens within that distance of Lat and\nLng match.",
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notify": {
+                    "type": "boolean"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "radius_km": {
+                    "type": "number"
+                },
+                "rating": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.NewStaffMember": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewTicket": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewTicketMessage": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "body": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewTranslation": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewUpload": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NewWebhook": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewZone": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active zones count for coverage. It defaults to true.",
+                    "type": "boolean"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "polygon": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Point"
+                    }
+                }
+            }
+        },
+        "models.NotificationChannels": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "boolean"
+                },
+                "push": {
+                    "type": "boolean"
+                },
+                "sms": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.NotificationSettings": {
+            "type": "object",
+            "properties": {
+                "order_updates": {
+                    "$ref": "#/definitions/models.NotificationChannels"
+                },
+                "promos": {
+                    "description": "Promos covers saved search matches and dish alerts.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NotificationChannels"
+                        }
+                    ]
+                },
+                "reviews": {
+                    "$ref": "#/definitions/models.NotificationChannels"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Nutrients": {
+            "type": "object",
+            "properties": {
+                "calories": {
+                    "type": "integer"
+                },
+                "carbs": {
+                    "type": "integer"
+                },
+                "fat": {
+                    "type": "integer"
+                },
+                "protein": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NutritionDish": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "available": {
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "dietary_info": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nutrition": {
+                    "$ref": "#/definitions/models.Nutrients"
+                },
                 "price": {
                     "type": "number"
                 }
             }
         },
-        "dish.DishInfo": {
+        "models.NutritionDishes": {
+            "type": "object",
+            "properties": {
+                "dishes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NutritionDish"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NutritionItem": {
+            "type": "object",
+            "properties": {
+                "dish_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nutrition": {
+                    "$ref": "#/definitions/models.Nutrients"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NutritionOrder": {
+            "type": "object",
+            "properties": {
+                "delivery_time": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NutritionItem"
+                    }
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "kitchen_name": {
+                    "type": "string"
+                },
+                "nutrition": {
+                    "$ref": "#/definitions/models.Nutrients"
+                },
+                "order_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NutritionSummary": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NutritionOrder"
+                    }
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.Nutrients"
+                },
+                "unknown_dishes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OTPSent": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "resend_after": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OTPVerification": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OTPVerified": {
+            "type": "object",
+            "properties": {
+                "phone": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.OrderDelivery": {
+            "type": "object",
+            "properties": {
+                "delivery_address": {
+                    "type": "string"
+                },
+                "delivery_time": {
+                    "type": "string"
+                },
+                "notes": {
+                    "$ref": "#/definitions/models.OrderNotes"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "preferences": {
+                    "$ref": "#/definitions/models.DeliveryPreferences"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrderEarnings": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "type": "number"
+                },
+                "delivery_time": {
+                    "type": "string"
+                },
+                "gross": {
+                    "type": "number"
+                },
+                "net": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "refunds": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrderItem": {
+            "type": "object",
+            "properties": {
+                "dish_id": {
+                    "type": "string"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Selection"
+                    }
+                },
+                "note": {
+                    "description": "Note is for the kitchen, such as \"no onions\".",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OrderNotes": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ItemNote"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrderPlacement": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "response": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrderStatus": {
+            "type": "object",
+            "properties": {
+                "order_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OrderTimer": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "after_minutes": {
+                    "description": "AfterMinutes is how long an order may wait for the kitchen. Orders\nare accepted at once when it is zero.",
+                    "type": "integer"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrdersPerHour": {
+            "type": "object",
+            "properties": {
+                "hours": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HourlyOrders"
+                    }
+                }
+            }
+        },
+        "models.PageMeta": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "open_graph": {
+                    "description": "OpenGraph holds og: properties, ready to be written as meta tags.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PartnerKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "boolean"
+                },
+                "partner": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "sandbox": {
+                    "description": "Sandbox keys place test orders, paid with fake money and left out\nof statistics, for partners to integrate with.",
+                    "type": "boolean"
+                },
+                "webhook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PartnerKeySettings": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "type": "boolean"
+                },
+                "sandbox": {
+                    "description": "Sandbox keys place test orders, paid with fake money and left out\nof statistics, for partners to integrate with.",
+                    "type": "boolean"
+                },
+                "webhook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PartnerKeys": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PartnerKey"
+                    }
+                }
+            }
+        },
+        "models.PartnerOrder": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "external_customer_id": {
+                    "type": "string"
+                },
+                "external_order_id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "partner": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "anonymized_at": {
+                    "description": "AnonymizedAt is set once the payment's personal data was removed\nunder the retention policy.",
+                    "type": "string"
+                },
+                "canceled_at": {
+                    "type": "string"
+                },
+                "challenge": {
+                    "description": "Challenge is set while the card issuer asks the customer to\nauthenticate the payment (3-D Secure).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PaymentChallenge"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "redirect_url": {
+                    "description": "RedirectUrl is the hosted checkout page to send the customer to.",
+                    "type": "string"
+                },
+                "refunded_amount": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaymentChallenge": {
+            "type": "object",
+            "properties": {
+                "client_secret": {
+                    "type": "string"
+                },
+                "redirect_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaymentProviders": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "type": "string"
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Payout": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayoutBalance": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "number"
+                },
+                "earned": {
+                    "type": "number"
+                },
+                "paid_out": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Payouts": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "$ref": "#/definitions/models.PayoutBalance"
+                },
+                "payouts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payout"
+                    }
+                }
+            }
+        },
+        "models.PermissionList": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.PlatformBanner": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "link_url": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlatformBanners": {
+            "type": "object",
+            "properties": {
+                "banners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlatformBanner"
+                    }
+                }
+            }
+        },
+        "models.Point": {
+            "type": "object",
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PricedItem": {
+            "type": "object",
+            "properties": {
+                "discount_id": {
+                    "type": "string"
+                },
+                "discount_percent": {
+                    "type": "number"
+                },
+                "discounted_price": {
+                    "type": "number"
+                },
+                "dish_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "unit_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.QuoteRequest": {
+            "type": "object",
+            "properties": {
+                "distance_km": {
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItem"
+                    }
+                },
+                "kitchen_id": {
+                    "description": "With the kitchen and the delivery location the distance is worked\nout from the road route instead.",
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/models.Point"
+                }
+            }
+        },
+        "models.RecordedRequest": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "object"
+                },
+                "body_omitted": {
+                    "description": "BodyOmitted is set when the body was not JSON or too large to keep.",
+                    "type": "boolean"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "header": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Recording": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "description": "Dropped counts the requests served past the most a recording keeps.",
+                    "type": "integer"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "started_by": {
+                    "type": "string"
+                },
+                "stopped_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecordingTrace": {
+            "type": "object",
+            "properties": {
+                "recording": {
+                    "$ref": "#/definitions/models.Recording"
+                },
+                "requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecordedRequest"
+                    }
+                }
+            }
+        },
+        "models.Recordings": {
+            "type": "object",
+            "properties": {
+                "recordings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Recording"
+                    }
+                }
+            }
+        },
+        "models.RefundDecision": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RefundRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "string"
+                },
+                "decision_comment": {
+                    "type": "string"
+                },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RefundRequests": {
+            "type": "object",
+            "properties": {
+                "requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RefundRequest"
+                    }
+                }
+            }
+        },
+        "models.Report": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportSchedule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kitchen_id": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportSchedules": {
+            "type": "object",
+            "properties": {
+                "schedules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReportSchedule"
+                    }
+                }
+            }
+        },
+        "models.Reports": {
+            "type": "object",
+            "properties": {
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Report"
+                    }
+                }
+            }
+        },
+        "models.RetentionFailure": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RetentionPolicy": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "log_days": {
+                    "type": "integer"
+                },
+                "record_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RetentionRun": {
             "type": "object",
             "properties": {
-                "allergens": {
+                "anonymized_orders": {
+                    "type": "integer"
+                },
+                "anonymized_payments": {
+                    "type": "integer"
+                },
+                "failure_count": {
+                    "type": "integer"
+                },
+                "failures": {
+                    "description": "Failures lists the first failures of the run.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RetentionFailure"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "logs_before": {
+                    "type": "string"
+                },
+                "purged": {
+                    "description": "Purged counts the gateway records removed, by kind.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "records_before": {
+                    "description": "RecordsBefore and LogsBefore are the cutoffs of the run.",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "started_by": {
+                    "type": "string"
+                },
+                "trigger": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RetentionRuns": {
+            "type": "object",
+            "properties": {
+                "policy": {
+                    "$ref": "#/definitions/models.RetentionPolicy"
+                },
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RetentionRun"
+                    }
+                }
+            }
+        },
+        "models.RevenueByCity": {
+            "type": "object",
+            "properties": {
+                "cities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CityRevenue"
+                    }
+                }
+            }
+        },
+        "models.Role": {
+            "type": "object",
+            "properties": {
+                "built_in": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "available": {
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Roles": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                }
+            }
+        },
+        "models.RouteUsage": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
+                },
+                "client_errors": {
+                    "type": "integer"
+                },
+                "error_rate": {
+                    "type": "number"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "server_errors": {
+                    "type": "integer"
+                },
+                "upgrades": {
+                    "type": "integer"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VersionUsage"
+                    }
+                }
+            }
+        },
+        "models.SLOReport": {
+            "type": "object",
+            "properties": {
+                "backends": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackendSLO"
+                    }
+                },
+                "window": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SLOStatus": {
+            "type": "object",
+            "properties": {
+                "budget_remaining": {
+                    "type": "number"
+                },
+                "burn_rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BurnRate"
+                    }
+                },
+                "calls": {
+                    "type": "integer"
+                },
+                "good": {
+                    "type": "integer"
+                },
+                "objective": {
+                    "type": "number"
+                },
+                "sli": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SMSAttempt": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SMSCampaign": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "recipients": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SMSMessage": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SMSAttempt"
+                    }
+                },
+                "campaign": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SMSMessages": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SMSMessage"
+                    }
+                }
+            }
+        },
+        "models.SavedSearch": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "cuisine_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lat": {
+                    "description": "With RadiusKm set only kitchens within that distance of Lat and\nLng match.",
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notify": {
                     "type": "boolean"
                 },
-                "category": {
+                "query": {
+                    "type": "string"
+                },
+                "radius_km": {
+                    "type": "number"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.SavedSearches": {
+            "type": "object",
+            "properties": {
+                "searches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SavedSearch"
+                    }
+                }
+            }
+        },
+        "models.SealedDataRequest": {
+            "type": "object",
+            "properties": {
+                "complete": {
+                    "type": "boolean"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key is the base64 AES-256-GCM key of the package. The package is\nthe 12-byte nonce followed by the ciphertext of a zip archive, with\nthe request ID as additional data.",
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "requested_by": {
                     "type": "string"
                 },
-                "dietary_info": {
+                "sections": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.DataSection"
                     }
                 },
-                "id": {
+                "sha256": {
                     "type": "string"
                 },
-                "ingredients": {
+                "size": {
+                    "description": "Size and Sha256 are of the encrypted package, so the recipient can\ncheck it.",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SearchHistory": {
+            "type": "object",
+            "properties": {
+                "searches": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.SearchHistoryEntry"
                     }
-                },
-                "kitchen_id": {
+                }
+            }
+        },
+        "models.SearchHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "cuisine_type": {
                     "type": "string"
                 },
-                "name": {
+                "lat": {
+                    "description": "With RadiusKm set only kitchens within that distance of Lat and\nLng match.",
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "query": {
                     "type": "string"
                 },
-                "nutrition_info": {
-                    "$ref": "#/definitions/dish.NutritionalInfo"
+                "radius_km": {
+                    "type": "number"
                 },
-                "price": {
+                "rating": {
                     "type": "number"
                 },
-                "updated_at": {
+                "searched_at": {
                     "type": "string"
                 }
             }
         },
-        "dish.Dishes": {
+        "models.SearchQuery": {
             "type": "object",
             "properties": {
-                "dishes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dish.DishDetails"
-                    }
+                "cuisine_type": {
+                    "type": "string"
                 },
-                "limit": {
-                    "type": "integer"
+                "lat": {
+                    "description": "With RadiusKm set only kitchens within that distance of Lat and\nLng match.",
+                    "type": "number"
                 },
-                "page": {
-                    "type": "integer"
+                "lng": {
+                    "type": "number"
                 },
-                "total": {
-                    "type": "integer"
+                "query": {
+                    "type": "string"
+                },
+                "radius_km": {
+                    "type": "number"
+                },
+                "rating": {
+                    "type": "number"
                 }
             }
         },
-        "dish.NewDataNoID": {
+        "models.SectionChange": {
             "type": "object",
             "properties": {
-                "available": {
-                    "type": "boolean"
-                },
-                "name": {
+                "after": {},
+                "before": {},
+                "section": {
                     "type": "string"
-                },
-                "price": {
-                    "type": "number"
                 }
             }
         },
-        "dish.NewDish": {
+        "models.Selection": {
             "type": "object",
             "properties": {
-                "available": {
-                    "type": "boolean"
-                },
-                "category": {
-                    "type": "string"
-                },
-                "description": {
+                "group": {
                     "type": "string"
                 },
-                "ingredients": {
+                "options": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                }
+            }
+        },
+        "models.Settings": {
+            "type": "object",
+            "properties": {
+                "app_versions": {
+                    "$ref": "#/definitions/models.AppVersions"
                 },
-                "kitchen_id": {
+                "banners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlatformBanner"
+                    }
+                },
+                "fees": {
+                    "$ref": "#/definitions/models.FeeSettings"
+                },
+                "maintenance": {
+                    "$ref": "#/definitions/models.Maintenance"
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "name": {
+                "updated_by": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "version": {
+                    "type": "integer"
+                },
+                "zones": {
+                    "$ref": "#/definitions/models.ZoneSettings"
                 }
             }
         },
-        "dish.NewDishResp": {
+        "models.SettingsChange": {
             "type": "object",
             "properties": {
-                "available": {
-                    "type": "boolean"
-                },
-                "category": {
-                    "type": "string"
-                },
-                "created_at": {
+                "changed_at": {
                     "type": "string"
                 },
-                "description": {
+                "changed_by": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "ingredients": {
+                "sections": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.SectionChange"
                     }
                 },
-                "kitchen_id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "number"
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "dish.NutritionalInfo": {
+        "models.SettingsChanges": {
             "type": "object",
             "properties": {
-                "calories": {
-                    "type": "integer"
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SettingsChange"
+                    }
+                }
+            }
+        },
+        "models.SettingsUpdate": {
+            "type": "object",
+            "properties": {
+                "app_versions": {
+                    "$ref": "#/definitions/models.AppVersions"
                 },
-                "carbs": {
-                    "type": "integer"
+                "banners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlatformBanner"
+                    }
                 },
-                "fat": {
-                    "type": "integer"
+                "fees": {
+                    "$ref": "#/definitions/models.FeeSettings"
                 },
-                "protein": {
+                "maintenance": {
+                    "$ref": "#/definitions/models.Maintenance"
+                },
+                "version": {
                     "type": "integer"
+                },
+                "zones": {
+                    "$ref": "#/definitions/models.ZoneSettings"
                 }
             }
         },
-        "dish.UpdatedData": {
+        "models.StaffMember": {
             "type": "object",
             "properties": {
-                "available": {
-                    "type": "boolean"
+                "invited_at": {
+                    "type": "string"
                 },
-                "category": {
+                "invited_by": {
                     "type": "string"
                 },
-                "description": {
+                "joined_at": {
                     "type": "string"
                 },
-                "id": {
+                "kitchen_id": {
                     "type": "string"
                 },
-                "ingredients": {
+                "kitchen_name": {
+                    "type": "string"
+                },
+                "permissions": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "kitchen_id": {
+                "status": {
                     "type": "string"
                 },
-                "name": {
+                "user_id": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
-                },
-                "updated_at": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "extra.Activity": {
+        "models.StaffMembers": {
             "type": "object",
             "properties": {
-                "favorite_cuisines": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/extra.Cuisine"
-                    }
-                },
-                "favorite_kitchens": {
+                "staff": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/extra.Kitchen"
+                        "$ref": "#/definitions/models.StaffMember"
                     }
-                },
-                "total_orders": {
-                    "type": "integer"
-                },
-                "total_spent": {
-                    "type": "number"
                 }
             }
         },
-        "extra.Cuisine": {
+        "models.StaffPermissionsUpdate": {
             "type": "object",
             "properties": {
-                "cuisine_type": {
-                    "type": "string"
-                },
-                "orders_count": {
-                    "type": "integer"
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "extra.DaySchedule": {
+        "models.Ticket": {
             "type": "object",
             "properties": {
-                "close": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "open": {
+                "description": {
                     "type": "string"
-                }
-            }
-        },
-        "extra.Dish": {
-            "type": "object",
-            "properties": {
+                },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "kitchen_id": {
                     "type": "string"
                 },
-                "orders_count": {
-                    "type": "integer"
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TicketMessage"
+                    }
                 },
-                "revenue": {
-                    "type": "number"
-                }
-            }
-        },
-        "extra.Kitchen": {
-            "type": "object",
-            "properties": {
-                "id": {
+                "order_id": {
                     "type": "string"
                 },
-                "name": {
+                "status": {
                     "type": "string"
                 },
-                "orders_count": {
-                    "type": "integer"
+                "subject": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "extra.NutritionalInfo": {
+        "models.TicketMessage": {
             "type": "object",
             "properties": {
-                "allergens": {
+                "attachments": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "calories": {
-                    "type": "integer"
+                "author_id": {
+                    "type": "string"
                 },
-                "carbs": {
-                    "type": "integer"
+                "author_role": {
+                    "type": "string"
                 },
-                "dietary_info": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "body": {
+                    "type": "string"
                 },
-                "fat": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "protein": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
                 }
             }
         },
-        "extra.Statistics": {
+        "models.TicketStatus": {
             "type": "object",
             "properties": {
-                "average_rating": {
-                    "type": "number"
-                },
-                "top_dishes": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Tickets": {
+            "type": "object",
+            "properties": {
+                "tickets": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/extra.Dish"
+                        "$ref": "#/definitions/models.Ticket"
                     }
-                },
-                "total_orders": {
-                    "type": "integer"
-                },
-                "total_revenue": {
-                    "type": "number"
                 }
             }
         },
-        "extra.WorkingHoursResp": {
+        "models.TopKitchens": {
             "type": "object",
             "properties": {
-                "kitchen_id": {
-                    "type": "string"
-                },
-                "schedule": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/extra.DaySchedule"
+                "kitchens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KitchenRank"
                     }
-                },
-                "updated_at": {
-                    "type": "string"
                 }
             }
         },
-        "kitchen.CreateRequest": {
+        "models.Translation": {
             "type": "object",
             "properties": {
-                "address": {
+                "description": {
                     "type": "string"
                 },
-                "cuisine_type": {
+                "entity_id": {
                     "type": "string"
                 },
-                "description": {
+                "kind": {
+                    "type": "string"
+                },
+                "locale": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "owner_id": {
+                "updated_at": {
                     "type": "string"
                 },
-                "phone_number": {
+                "updated_by": {
                     "type": "string"
                 }
             }
         },
-        "kitchen.CreateResponse": {
+        "models.Translations": {
             "type": "object",
             "properties": {
-                "address": {
+                "translations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Translation"
+                    }
+                }
+            }
+        },
+        "models.Upload": {
+            "type": "object",
+            "properties": {
+                "content_type": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "cuisine_type": {
-                    "type": "string"
-                },
-                "description": {
+                "filename": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "offset": {
+                    "type": "integer"
+                },
+                "purpose": {
                     "type": "string"
                 },
-                "owner_id": {
+                "size": {
+                    "type": "integer"
+                },
+                "status": {
                     "type": "string"
                 },
-                "phone_number": {
+                "updated_at": {
                     "type": "string"
                 },
-                "rating": {
-                    "type": "number"
+                "user_id": {
+                    "description": "UserId is the uploader, the only one besides admins to change,\ndelete or attach the upload.",
+                    "type": "string"
                 }
             }
         },
-        "kitchen.Info": {
+        "models.UserLoad": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
+                "in_flight": {
+                    "type": "integer"
                 },
-                "created_at": {
+                "last_rejected_at": {
                     "type": "string"
                 },
-                "cuisine_type": {
-                    "type": "string"
+                "rejected": {
+                    "type": "integer"
                 },
-                "description": {
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.UserLoads": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
                 },
-                "id": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserLoad"
+                    }
+                }
+            }
+        },
+        "models.VerificationDecision": {
+            "type": "object",
+            "properties": {
+                "note": {
                     "type": "string"
                 },
-                "name": {
+                "status": {
+                    "description": "Status is verified to grant the badge or rejected to refuse or\nrevoke it.",
                     "type": "string"
+                }
+            }
+        },
+        "models.VersionUsage": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
                 },
-                "owner_id": {
+                "client_errors": {
+                    "type": "integer"
+                },
+                "error_rate": {
+                    "type": "number"
+                },
+                "last_seen_at": {
                     "type": "string"
                 },
-                "phone_number": {
+                "platform": {
                     "type": "string"
                 },
-                "rating": {
-                    "type": "number"
+                "server_errors": {
+                    "type": "integer"
                 },
-                "total_orders": {
+                "upgrades": {
                     "type": "integer"
                 },
-                "updated_at": {
+                "version": {
                     "type": "string"
                 }
             }
         },
-        "kitchen.KitchenDetails": {
+        "models.Webhook": {
             "type": "object",
             "properties": {
-                "cuisine_type": {
+                "created_at": {
                     "type": "string"
                 },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "kitchen_id": {
                     "type": "string"
                 },
-                "rating": {
-                    "type": "number"
+                "secret": {
+                    "type": "string"
                 },
-                "total_orders": {
-                    "type": "integer"
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "kitchen.Kitchens": {
+        "models.WebhookDeliveries": {
             "type": "object",
             "properties": {
-                "kitchens": {
+                "deliveries": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/kitchen.KitchenDetails"
+                        "$ref": "#/definitions/models.WebhookDelivery"
                     }
-                },
-                "limit": {
-                    "type": "integer"
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
                 }
             }
         },
-        "kitchen.NewDataNoID": {
+        "models.WebhookDelivery": {
             "type": "object",
             "properties": {
-                "description": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "name": {
+                "error": {
                     "type": "string"
                 },
-                "phone_number": {
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "webhook_id": {
                     "type": "string"
                 }
             }
         },
-        "kitchen.UpdatedData": {
+        "models.Webhooks": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Webhook"
+                    }
+                }
+            }
+        },
+        "models.Zone": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
                 },
-                "cuisine_type": {
+                "city": {
                     "type": "string"
                 },
-                "description": {
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
@@ -1834,20 +17626,36 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "owner_id": {
-                    "type": "string"
-                },
-                "phone_number": {
-                    "type": "string"
-                },
-                "rating": {
-                    "type": "number"
+                "polygon": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Point"
+                    }
                 },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
+        "models.ZoneSettings": {
+            "type": "object",
+            "properties": {
+                "enforced": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Zones": {
+            "type": "object",
+            "properties": {
+                "zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Zone"
+                    }
+                }
+            }
+        },
         "order.Item": {
             "type": "object",
             "properties": {
@@ -1876,29 +17684,6 @@ const docTemplate = `{
                 }
             }
         },
-        "order.NewOrder": {
-            "type": "object",
-            "properties": {
-                "delivery_address": {
-                    "type": "string"
-                },
-                "delivery_time": {
-                    "type": "string"
-                },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/order.Item"
-                    }
-                },
-                "kitchen_id": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                }
-            }
-        },
         "order.NewOrderResp": {
             "type": "object",
             "properties": {
@@ -2077,26 +17862,6 @@ const docTemplate = `{
                 }
             }
         },
-        "payment.NewPayment": {
-            "type": "object",
-            "properties": {
-                "card_number": {
-                    "type": "string"
-                },
-                "cvv": {
-                    "type": "string"
-                },
-                "expiry_date": {
-                    "type": "string"
-                },
-                "order_id": {
-                    "type": "string"
-                },
-                "payment_method": {
-                    "type": "string"
-                }
-            }
-        },
         "payment.PaymentDetails": {
             "type": "object",
             "properties": {
@@ -2132,6 +17897,42 @@ const docTemplate = `{
                 }
             }
         },
+        "pricing.Quote": {
+            "type": "object",
+            "properties": {
+                "delivery_fee": {
+                    "type": "number"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PricedItem"
+                    }
+                },
+                "service_fee": {
+                    "type": "number"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "warnings": {
+                    "description": "Warnings name the dishes holding allergens the customer avoids.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AllergenWarning"
+                    }
+                }
+            }
+        },
         "review.NewReview": {
             "type": "object",
             "properties": {
@@ -2306,9 +18107,9 @@ const docTemplate = `{
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
-	Host:             "localhost:8080",
+	Host:             "",
 	BasePath:         "/local-eats",
-	Schemes:          []string{"http"},
+	Schemes:          []string{},
 	Title:            "Local Eats",
 	Description:      "API Gateway of Local Eats",
 	InfoInstanceName: "swagger",
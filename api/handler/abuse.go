@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"cmp"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// FetchAbuseProfiles godoc
+// @Summary Gets suspicious accounts
+// @Description Lists the accounts that raised abuse signals or were reviewed, the highest score first, optionally by status
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param status query string false "clear, challenge or shadow_ban"
+// @Success 200 {object} models.AbuseProfiles
+// @Router /admin/abuse [get]
+func (h *Handler) FetchAbuseProfiles(c *gin.Context) {
+	h.Logger.Info("FetchAbuseProfiles method is starting")
+
+	status := c.Query("status")
+	res := models.AbuseProfiles{Profiles: []models.AbuseProfile{}}
+	for _, p := range h.Abuse.List(time.Now()) {
+		if status == "" || p.Status == status {
+			res.Profiles = append(res.Profiles, p)
+		}
+	}
+	slices.SortFunc(res.Profiles, func(a, b models.AbuseProfile) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	h.Logger.Info("FetchAbuseProfiles method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetAbuseProfile godoc
+// @Summary Gets an account's abuse profile
+// @Description Gets the abuse signals the account raised in the current window, its score and status
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.AbuseProfile
+// @Failure 400 {object} string "Invalid user ID"
+// @Failure 404 {object} string "Account raised no abuse signals"
+// @Router /admin/abuse/{user_id} [get]
+func (h *Handler) GetAbuseProfile(c *gin.Context) {
+	h.Logger.Info("GetAbuseProfile method is starting")
+
+	userID, err := pathUUID(c, "user_id", "user id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	p, ok := h.Abuse.Profile(userID, time.Now())
+	if !ok {
+		er := "account raised no abuse signals"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetAbuseProfile method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
+
+// ReviewAbuse godoc
+// @Summary Reviews a suspicious account
+// @Description Sets the account's abuse status, which holds until set to auto: clear lets it go on, challenge makes it pass an OTP before anything else, shadow_ban holds its reviews. Auto hands it back to scoring
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Param review body models.AbuseReview true "Status and note"
+// @Success 200 {object} models.AbuseProfile
+// @Failure 400 {object} string "Invalid user ID or status"
+// @Router /admin/abuse/{user_id} [put]
+func (h *Handler) ReviewAbuse(c *gin.Context) {
+	h.Logger.Info("ReviewAbuse method is starting")
+
+	userID, err := pathUUID(c, "user_id", "user id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	reviewer, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.AbuseReview
+	err = c.ShouldBindJSON(&data)
+	var p models.AbuseProfile
+	if err == nil {
+		p, err = h.Abuse.Review(userID, data, reviewer, time.Now())
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid review").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("ReviewAbuse method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
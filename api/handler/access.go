@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// maxAttachments bounds the uploads attached to a single request.
+const maxAttachments = 5
+
+// caller returns the user ID and role claims of the request's token.
+func (h *Handler) caller(c *gin.Context) (string, string, bool) {
+	userID, role := c.GetString(middleware.UserIDKey), c.GetString(middleware.RoleKey)
+	if userID == "" && role != models.RoleAdmin {
+		er := "token has no user id"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return "", "", false
+	}
+	return userID, role, true
+}
+
+// user returns the caller's user ID, refusing tokens without one such as
+// admin service tokens.
+func (h *Handler) user(c *gin.Context) (string, bool) {
+	userID, _, ok := h.caller(c)
+	if ok && userID == "" {
+		er := "token has no user id"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return "", false
+	}
+	return userID, ok
+}
+
+// accessRole tells how the caller relates to a customer's order at a
+// kitchen: admin, the customer, or the kitchen. The kitchen's owner and
+// its staff granted permission act as the kitchen; an empty permission
+// admits the owner only. Anyone else is refused with 403.
+func (h *Handler) accessRole(c *gin.Context, customerID, kitchenID, permission string) (string, bool) {
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return "", false
+	}
+
+	switch {
+	case role == models.RoleAdmin:
+		return models.RoleAdmin, true
+	case customerID == userID:
+		return models.RoleCustomer, true
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	allowed, err := h.kitchenAccess(ctx, userID, kitchenID, permission)
+	if allowed {
+		return models.RoleKitchen, true
+	}
+
+	status, er := http.StatusForbidden, "access denied"
+	if err != nil {
+		status, _ = errorStatus(err)
+		er = errors.Wrap(err, "error getting kitchen").Error()
+	}
+	c.AbortWithStatusJSON(status,
+		gin.H{"error": er})
+	h.Logger.Error(er)
+	return "", false
+}
+
+// canAccess tells, as accessRole does but without answering the
+// request, whether the caller may act on a customer's order at a
+// kitchen.
+func (h *Handler) canAccess(ctx context.Context, c *gin.Context, customerID, kitchenID, permission string) (bool, error) {
+	userID, role := c.GetString(middleware.UserIDKey), c.GetString(middleware.RoleKey)
+	switch {
+	case role == models.RoleAdmin:
+		return true, nil
+	case userID == "":
+		return false, nil
+	case customerID == userID:
+		return true, nil
+	}
+	return h.kitchenAccess(ctx, userID, kitchenID, permission)
+}
+
+// kitchenAccess tells whether the user owns the kitchen or is its staff
+// granted permission.
+func (h *Handler) kitchenAccess(ctx context.Context, userID, kitchenID, permission string) (bool, error) {
+	if permission != "" {
+		if m, ok := h.staffMember(kitchenID, userID); ok && m.Allows(permission) {
+			return true, nil
+		}
+	}
+
+	owner, err := h.kitchenOwner(ctx, kitchenID)
+	return err == nil && owner == userID, err
+}
+
+func (h *Handler) kitchenOwner(ctx context.Context, kitchenID string) (string, error) {
+	if _, err := uuid.Parse(kitchenID); err != nil {
+		return "", errors.Wrap(err, "invalid kitchen id")
+	}
+
+	k, err := h.KitchenClient.Get(ctx, &pbk.ID{Id: kitchenID})
+	if err != nil {
+		return "", err
+	}
+	return k.OwnerId, nil
+}
+
+// validateAttachments checks that every ID is a completed attachment
+// upload of the caller.
+func (h *Handler) validateAttachments(c *gin.Context, ids []string) error {
+	if len(ids) > maxAttachments {
+		return errors.Errorf("at most %d attachments are allowed", maxAttachments)
+	}
+
+	for _, id := range ids {
+		u, err := h.ownUpload(c, id)
+		if err != nil {
+			return errors.Wrapf(err, "attachment %s", id)
+		}
+		if u.Purpose != models.UploadAttachment || u.Status != models.UploadCompleted {
+			return errors.Errorf("attachment %s must be a completed %s upload", id, models.UploadAttachment)
+		}
+	}
+	return nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
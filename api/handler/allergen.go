@@ -0,0 +1,266 @@
+package handler
+
+import (
+	pbd "api-gateway/genproto/dish"
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// FetchAllergens godoc
+// @Summary Gets allergen tags
+// @Description Lists the allergen tags dishes and allergy profiles are declared with
+// @Tags dish
+// @Security ApiKeyAuth
+// @Success 200 {object} models.AllergenList
+// @Router /allergens [get]
+func (h *Handler) FetchAllergens(c *gin.Context) {
+	h.Logger.Info("FetchAllergens method is starting")
+
+	res := models.AllergenList{Allergens: models.Allergens}
+
+	h.Logger.Info("FetchAllergens method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetDishIngredients godoc
+// @Summary Gets dish ingredients
+// @Description Discloses the dish's ingredients and allergens. Declared is false for dishes created before allergens were required, whose allergens are only what the dish service lists
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Success 200 {object} models.DishIngredients
+// @Failure 400 {object} string "Invalid dish ID"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
+// @Router /dishes/{id}/ingredients [get]
+func (h *Handler) GetDishIngredients(c *gin.Context) {
+	serve(h, c, Proxy[*pbd.ID, *pbd.DishInfo]{
+		Name: "GetDishIngredients",
+		Bind: func(c *gin.Context) (*pbd.ID, error) {
+			id, err := pathUUID(c, "id", "dish id")
+			return &pbd.ID{Id: id}, err
+		},
+		Call:  h.DishClient.Read,
+		Error: "error getting dish",
+		Render: func(c *gin.Context, res *pbd.DishInfo) {
+			h.localize(c, res)
+			_, declared := h.Storage.DishAllergens.Get(res.Id)
+			h.render(c, http.StatusOK, models.DishIngredients{
+				DishId:      res.Id,
+				Name:        res.Name,
+				Ingredients: append([]string{}, res.Ingredients...),
+				Allergens:   h.dishAllergens(res),
+				Declared:    declared,
+			})
+		},
+	})
+}
+
+// GetAllergyProfile godoc
+// @Summary Gets allergy profile
+// @Description Lists the allergens the user avoids. For the user and admins
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.AllergyProfile
+// @Failure 400 {object} string "Invalid user ID"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/allergies [get]
+func (h *Handler) GetAllergyProfile(c *gin.Context) {
+	h.Logger.Info("GetAllergyProfile method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+	p, ok := h.Storage.AllergyProfiles.Get(userID)
+	if !ok {
+		p = models.AllergyProfile{UserId: userID, NewAllergyProfile: models.NewAllergyProfile{Allergens: []string{}}}
+	}
+
+	h.Logger.Info("GetAllergyProfile method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
+
+// SetAllergyProfile godoc
+// @Summary Sets allergy profile
+// @Description Replaces the allergens the user avoids, as tags from GET /allergens. Order quotes warn about dishes that contain any of them. For the user and admins
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param profile body models.NewAllergyProfile true "Allergens"
+// @Success 200 {object} models.AllergyProfile
+// @Failure 400 {object} string "Invalid user ID or allergens"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/allergies [put]
+func (h *Handler) SetAllergyProfile(c *gin.Context) {
+	h.Logger.Info("SetAllergyProfile method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewAllergyProfile
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		data.Allergens, err = canonicalAllergens(data.Allergens)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid allergy profile").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	p := models.AllergyProfile{
+		UserId:            userID,
+		NewAllergyProfile: data,
+		UpdatedAt:         time.Now().Format(time.RFC3339),
+	}
+	h.Storage.AllergyProfiles.Set(userID, p)
+
+	h.Logger.Info("SetAllergyProfile method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
+
+// selfOrAdmin returns the user ID of the path if the caller is that user
+// or an admin.
+func (h *Handler) selfOrAdmin(c *gin.Context) (string, bool) {
+	userID, err := pathUUID(c, "id", "user id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return "", false
+	}
+
+	callerID, role, ok := h.caller(c)
+	if !ok {
+		return "", false
+	}
+	if role != models.RoleAdmin && callerID != userID {
+		er := "access denied"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return "", false
+	}
+	return userID, true
+}
+
+// canonicalAllergens checks the tags and returns them canonical, sorted
+// and without repeats.
+func canonicalAllergens(tags []string) ([]string, error) {
+	res := []string{}
+	for _, t := range tags {
+		a, ok := models.CanonicalAllergen(t)
+		if !ok {
+			return nil, errors.Errorf("unknown allergen %q, see GET /allergens", t)
+		}
+		res = append(res, a)
+	}
+	slices.Sort(res)
+	return slices.Compact(res), nil
+}
+
+// declaredAllergens checks the allergens a new dish declares, which
+// must be given even if empty.
+func declaredAllergens(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, errors.New("allergens are required, [] for a dish without any")
+	}
+	return canonicalAllergens(tags)
+}
+
+func (h *Handler) declareAllergens(dishID string, allergens []string) {
+	h.Storage.DishAllergens.Set(dishID, models.DishAllergens{
+		DishId:    dishID,
+		Allergens: allergens,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+	})
+}
+
+// dishAllergens joins the allergens declared for the dish with those the
+// dish service lists that are known tags.
+func (h *Handler) dishAllergens(dish *pbd.DishInfo) []string {
+	declared, _ := h.Storage.DishAllergens.Get(dish.Id)
+	res := slices.Clone(declared.Allergens)
+	for _, t := range dish.Allergens {
+		if a, ok := models.CanonicalAllergen(t); ok {
+			res = append(res, a)
+		}
+	}
+	slices.Sort(res)
+	return append([]string{}, slices.Compact(res)...)
+}
+
+// allergenWarnings finds the dishes of the items that hold allergens the
+// user avoids. Dishes without declared allergens are read from the dish
+// service; those that can't be read are skipped.
+func (h *Handler) allergenWarnings(ctx context.Context, userID string, items []models.OrderItem) []models.AllergenWarning {
+	profile, ok := h.Storage.AllergyProfiles.Get(userID)
+	if !ok || len(profile.Allergens) == 0 {
+		return nil
+	}
+
+	var ids []string
+	for _, item := range items {
+		if !slices.Contains(ids, item.DishId) {
+			ids = append(ids, item.DishId)
+		}
+	}
+
+	found := make([][]string, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		dish := &pbd.DishInfo{Id: id}
+		if _, declared := h.Storage.DishAllergens.Get(id); declared {
+			found[i] = h.dishAllergens(dish)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dish, err := h.DishClient.Read(ctx, &pbd.ID{Id: id})
+			if err != nil {
+				h.Logger.Error(errors.Wrapf(err, "error getting allergens of dish %s", id).Error())
+				return
+			}
+			found[i] = h.dishAllergens(dish)
+		}()
+	}
+	wg.Wait()
+
+	var res []models.AllergenWarning
+	for i, id := range ids {
+		conflicts := slices.DeleteFunc(found[i], func(a string) bool {
+			return !slices.Contains(profile.Allergens, a)
+		})
+		if len(conflicts) > 0 {
+			res = append(res, models.AllergenWarning{DishId: id, Allergens: conflicts})
+		}
+	}
+	return res
+}
+
+func newDish(d *models.NewDish) *pbd.NewDish {
+	return &pbd.NewDish{
+		KitchenId:   d.KitchenId,
+		Name:        d.Name,
+		Description: d.Description,
+		Price:       d.Price,
+		Category:    d.Category,
+		Ingredients: d.Ingredients,
+		Available:   d.Available,
+	}
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	analyticsTimeout = 30 * time.Second
+	// maxAnalyticsDays bounds the period so hourly series stay reasonable.
+	maxAnalyticsDays = 366
+)
+
+// OrdersPerHour godoc
+// @Summary Gets orders per hour
+// @Description Counts orders across all kitchens for every hour of the period, by delivery time
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param start_date query string false "start date, unless period is given"
+// @Param end_date query string false "end date, unless period is given"
+// @Param period query string false "Days up to today in place of the dates" Enums(today, yesterday, last_7_days, last_30_days, last_90_days, this_month, last_month, this_year)
+// @Success 200 {object} models.OrdersPerHour
+// @Failure 400 {object} string "Invalid date or period"
+// @Failure 403 {object} string "Admin role is required"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
+// @Router /admin/analytics/orders-per-hour [get]
+func (h *Handler) OrdersPerHour(c *gin.Context) {
+	h.serveAnalytics(c, "OrdersPerHour", func(ctx context.Context, start, end time.Time) (any, error) {
+		hours, err := h.Analytics.OrdersPerHour(ctx, start, end)
+		return models.OrdersPerHour{Hours: hours}, err
+	})
+}
+
+// RevenueByCity godoc
+// @Summary Gets revenue by city
+// @Description Sums orders and revenue of kitchens per city for the period
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param start_date query string false "start date, unless period is given"
+// @Param end_date query string false "end date, unless period is given"
+// @Param period query string false "Days up to today in place of the dates" Enums(today, yesterday, last_7_days, last_30_days, last_90_days, this_month, last_month, this_year)
+// @Success 200 {object} models.RevenueByCity
+// @Failure 400 {object} string "Invalid date or period"
+// @Failure 403 {object} string "Admin role is required"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
+// @Router /admin/analytics/revenue-by-city [get]
+func (h *Handler) RevenueByCity(c *gin.Context) {
+	h.serveAnalytics(c, "RevenueByCity", func(ctx context.Context, start, end time.Time) (any, error) {
+		cities, err := h.Analytics.RevenueByCity(ctx, start, end)
+		return models.RevenueByCity{Cities: cities}, err
+	})
+}
+
+// TopKitchens godoc
+// @Summary Gets top kitchens
+// @Description Ranks kitchens by revenue or by number of orders for the period
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param start_date query string false "start date, unless period is given"
+// @Param end_date query string false "end date, unless period is given"
+// @Param period query string false "Days up to today in place of the dates" Enums(today, yesterday, last_7_days, last_30_days, last_90_days, this_month, last_month, this_year)
+// @Param sort query string false "Ranking criterion" Enums(revenue, orders)
+// @Param limit query int false "Number of kitchens, 10 by default"
+// @Success 200 {object} models.TopKitchens
+// @Failure 400 {object} string "Invalid date, period, sort or limit"
+// @Failure 403 {object} string "Admin role is required"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
+// @Router /admin/analytics/top-kitchens [get]
+func (h *Handler) TopKitchens(c *gin.Context) {
+	sort := c.DefaultQuery("sort", "revenue")
+	limit, err := queryInt(c, "limit")
+	if err == nil && (limit < 0 || (sort != "revenue" && sort != "orders")) {
+		err = errors.New("invalid sort or limit")
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	if limit == 0 {
+		limit = 10
+	}
+
+	h.serveAnalytics(c, "TopKitchens", func(ctx context.Context, start, end time.Time) (any, error) {
+		kitchens, err := h.Analytics.TopKitchens(ctx, start, end, limit, sort == "orders")
+		return models.TopKitchens{Kitchens: kitchens}, err
+	})
+}
+
+// Churn godoc
+// @Summary Gets customer churn
+// @Description Compares customers of the period with those of the preceding period of the same length
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param start_date query string false "start date, unless period is given"
+// @Param end_date query string false "end date, unless period is given"
+// @Param period query string false "Days up to today in place of the dates" Enums(today, yesterday, last_7_days, last_30_days, last_90_days, this_month, last_month, this_year)
+// @Success 200 {object} models.Churn
+// @Failure 400 {object} string "Invalid date or period"
+// @Failure 403 {object} string "Admin role is required"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
+// @Router /admin/analytics/churn [get]
+func (h *Handler) Churn(c *gin.Context) {
+	h.serveAnalytics(c, "Churn", func(ctx context.Context, start, end time.Time) (any, error) {
+		return h.Analytics.Churn(ctx, start, end)
+	})
+}
+
+// serveAnalytics reads the start_date and end_date of an analytics
+// endpoint and renders the result of fn for that period.
+func (h *Handler) serveAnalytics(c *gin.Context, name string,
+	fn func(ctx context.Context, start, end time.Time) (any, error)) {
+	h.Logger.Info(name + " method is starting")
+
+	start, end, err := analyticsPeriod(c, time.UTC)
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, analyticsTimeout)
+	defer cancel()
+
+	res, err := fn(ctx, start, end)
+	if err != nil {
+		er := errors.Wrap(err, "error computing analytics").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info(name + " method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// periodShortcut is a span of days the period query takes in place of
+// start_date and end_date.
+type periodShortcut struct {
+	name string
+	span func(today time.Time) (time.Time, time.Time)
+}
+
+var periods = []periodShortcut{
+	{"today", func(t time.Time) (time.Time, time.Time) { return t, t }},
+	{"yesterday", func(t time.Time) (time.Time, time.Time) { return t.AddDate(0, 0, -1), t.AddDate(0, 0, -1) }},
+	{"last_7_days", func(t time.Time) (time.Time, time.Time) { return t.AddDate(0, 0, -6), t }},
+	{"last_30_days", func(t time.Time) (time.Time, time.Time) { return t.AddDate(0, 0, -29), t }},
+	{"last_90_days", func(t time.Time) (time.Time, time.Time) { return t.AddDate(0, 0, -89), t }},
+	{"this_month", func(t time.Time) (time.Time, time.Time) { return t.AddDate(0, 0, 1-t.Day()), t }},
+	{"last_month", func(t time.Time) (time.Time, time.Time) {
+		first := t.AddDate(0, 0, 1-t.Day())
+		return first.AddDate(0, -1, 0), first.AddDate(0, 0, -1)
+	}},
+	{"this_year", func(t time.Time) (time.Time, time.Time) { return t.AddDate(0, 0, 1-t.YearDay()), t }},
+}
+
+// analyticsPeriod reads the days of a period from the period shortcut or
+// else from start_date and end_date, both included. The days begin at
+// midnight in loc, which also tells what today is.
+func analyticsPeriod(c *gin.Context, loc *time.Location) (time.Time, time.Time, error) {
+	var start, end time.Time
+	if name := c.Query("period"); name != "" {
+		if c.Query("start_date") != "" || c.Query("end_date") != "" {
+			return time.Time{}, time.Time{}, errors.New("period cannot be used with start_date or end_date")
+		}
+		i := slices.IndexFunc(periods, func(p periodShortcut) bool { return p.name == name })
+		if i < 0 {
+			names := make([]string, len(periods))
+			for i, p := range periods {
+				names[i] = p.name
+			}
+			return time.Time{}, time.Time{}, errors.Errorf("period must be one of %s", strings.Join(names, ", "))
+		}
+		now := time.Now().In(loc)
+		start, end = periods[i].span(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	} else {
+		startDate, err := dateQuery(c, "start_date", "start date")
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		endDate, err := dateQuery(c, "end_date", "end date")
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start, _ = time.Parse("2006-01-02", startDate)
+		end, _ = time.Parse("2006-01-02", endDate)
+	}
+
+	// The days are counted in UTC, where they are all 24 hours long.
+	switch days := int(end.Sub(start).Hours()/24) + 1; {
+	case end.Before(start):
+		return time.Time{}, time.Time{}, errors.New("start_date must not be after end_date")
+	case days > maxAnalyticsDays:
+		return time.Time{}, time.Time{}, errors.Errorf("period must span at most %d days, not %d", maxAnalyticsDays, days)
+	}
+	return inZone(start, loc), inZone(end, loc), nil
+}
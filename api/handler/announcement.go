@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/cdn"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxAnnouncementTitle = 100
+	maxAnnouncementBody  = 1000
+	// maxAnnouncements bounds the scheduled and active announcements of a
+	// kitchen.
+	maxAnnouncements = 20
+)
+
+// CreateAnnouncement godoc
+// @Summary Publishes a kitchen announcement
+// @Description Publishes a notice or banner shown in the kitchen's detail and in its customers' feeds from starts_at until ends_at. For the kitchen's owner and staff with the menu permission
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param announcement body models.NewAnnouncement true "Announcement"
+// @Success 200 {object} models.Announcement
+// @Failure 400 {object} string "Invalid kitchen ID or announcement data"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Failure 409 {object} string "Too many announcements"
+// @Router /kitchens/{id}/announcements [post]
+func (h *Handler) CreateAnnouncement(c *gin.Context) {
+	h.Logger.Info("CreateAnnouncement method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffMenu); !ok {
+		return
+	}
+	userID, _, _ := h.caller(c)
+
+	var data models.NewAnnouncement
+	err = c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateAnnouncement(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid announcement data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now()
+	current := 0
+	for _, a := range h.Storage.Announcements.List() {
+		if a.KitchenId == kitchenID && announcementStatus(a, now) != models.AnnouncementExpired {
+			current++
+		}
+	}
+	if current >= maxAnnouncements {
+		er := errors.Errorf("a kitchen may have at most %d scheduled or active announcements", maxAnnouncements).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	a := models.Announcement{
+		Id:              uuid.NewString(),
+		KitchenId:       kitchenID,
+		NewAnnouncement: data,
+		CreatedBy:       userID,
+		CreatedAt:       now.Format(time.RFC3339),
+		UpdatedAt:       now.Format(time.RFC3339),
+	}
+	h.Storage.Announcements.Set(a.Id, a)
+	h.Edge.Purge(cdn.KitchenKey(a.KitchenId))
+	a.Status = announcementStatus(a, now)
+
+	h.Logger.Info("CreateAnnouncement method has finished successfully")
+	h.render(c, http.StatusOK, a)
+}
+
+// FetchAnnouncements godoc
+// @Summary Gets kitchen announcements
+// @Description Lists the kitchen's announcements, latest start first, optionally by status. For the kitchen's owner and staff with the menu permission; customers see the active ones in the kitchen's detail
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param status query string false "scheduled, active or expired"
+// @Success 200 {object} models.Announcements
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Router /kitchens/{id}/announcements [get]
+func (h *Handler) FetchAnnouncements(c *gin.Context) {
+	h.Logger.Info("FetchAnnouncements method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffMenu); !ok {
+		return
+	}
+
+	status, now := c.Query("status"), time.Now()
+	res := models.Announcements{Announcements: []models.Announcement{}}
+	for _, a := range h.Storage.Announcements.List() {
+		a.Status = announcementStatus(a, now)
+		if a.KitchenId == kitchenID && (status == "" || a.Status == status) {
+			res.Announcements = append(res.Announcements, a)
+		}
+	}
+	slices.SortFunc(res.Announcements, func(a, b models.Announcement) int {
+		return parseTime(b.StartsAt).Compare(parseTime(a.StartsAt))
+	})
+
+	h.Logger.Info("FetchAnnouncements method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// UpdateAnnouncement godoc
+// @Summary Updates a kitchen announcement
+// @Description Replaces an announcement, which may also reschedule it or bring an expired one back. For the kitchen's owner and staff with the menu permission
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param announcement_id path string true "Announcement ID"
+// @Param announcement body models.NewAnnouncement true "Announcement"
+// @Success 200 {object} models.Announcement
+// @Failure 400 {object} string "Invalid ID or announcement data"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Failure 404 {object} string "Announcement not found"
+// @Router /kitchens/{id}/announcements/{announcement_id} [put]
+func (h *Handler) UpdateAnnouncement(c *gin.Context) {
+	h.Logger.Info("UpdateAnnouncement method is starting")
+
+	a, ok := h.findAnnouncement(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewAnnouncement
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateAnnouncement(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid announcement data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now()
+	a = h.Storage.Announcements.Update(a.Id, func(a models.Announcement, _ bool) models.Announcement {
+		a.NewAnnouncement = data
+		a.UpdatedAt = now.Format(time.RFC3339)
+		return a
+	})
+	a.Status = announcementStatus(a, now)
+	h.Edge.Purge(cdn.KitchenKey(a.KitchenId))
+
+	h.Logger.Info("UpdateAnnouncement method has finished successfully")
+	h.render(c, http.StatusOK, a)
+}
+
+// DeleteAnnouncement godoc
+// @Summary Deletes a kitchen announcement
+// @Description Takes an announcement down. For the kitchen's owner and staff with the menu permission
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param announcement_id path string true "Announcement ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid ID"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Failure 404 {object} string "Announcement not found"
+// @Router /kitchens/{id}/announcements/{announcement_id} [delete]
+func (h *Handler) DeleteAnnouncement(c *gin.Context) {
+	h.Logger.Info("DeleteAnnouncement method is starting")
+
+	a, ok := h.findAnnouncement(c)
+	if !ok {
+		return
+	}
+	h.Storage.Announcements.Delete(a.Id)
+	h.Edge.Purge(cdn.KitchenKey(a.KitchenId))
+
+	h.Logger.Info("DeleteAnnouncement method has finished successfully")
+	h.render(c, http.StatusOK, "Announcement deleted successfully")
+}
+
+// findAnnouncement returns the announcement of the path's kitchen once
+// the caller may manage it.
+func (h *Handler) findAnnouncement(c *gin.Context) (models.Announcement, bool) {
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	var id string
+	if err == nil {
+		id, err = pathUUID(c, "announcement_id", "announcement id")
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Announcement{}, false
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffMenu); !ok {
+		return models.Announcement{}, false
+	}
+
+	a, ok := h.Storage.Announcements.Get(id)
+	if !ok || a.KitchenId != kitchenID {
+		er := "announcement not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Announcement{}, false
+	}
+	return a, true
+}
+
+// validateAnnouncement checks the announcement, filling in its kind and
+// start time when they are missing.
+func validateAnnouncement(data *models.NewAnnouncement) error {
+	switch data.Kind {
+	case "":
+		data.Kind = models.AnnouncementNotice
+	case models.AnnouncementNotice, models.AnnouncementBanner:
+	default:
+		return errors.Errorf("kind must be %s or %s", models.AnnouncementNotice, models.AnnouncementBanner)
+	}
+
+	data.Title, data.Body = strings.TrimSpace(data.Title), strings.TrimSpace(data.Body)
+	if data.Title == "" || utf8.RuneCountInString(data.Title) > maxAnnouncementTitle {
+		return errors.Errorf("title must have 1 to %d characters", maxAnnouncementTitle)
+	}
+	if utf8.RuneCountInString(data.Body) > maxAnnouncementBody {
+		return errors.Errorf("body must have at most %d characters", maxAnnouncementBody)
+	}
+
+	now := time.Now()
+	start := now
+	if data.StartsAt != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, data.StartsAt); err != nil {
+			return errors.Wrap(err, "invalid starts_at")
+		}
+	}
+	data.StartsAt = start.Format(time.RFC3339)
+
+	if data.EndsAt != "" {
+		end, err := time.Parse(time.RFC3339, data.EndsAt)
+		if err != nil {
+			return errors.Wrap(err, "invalid ends_at")
+		}
+		if !end.After(start) || !end.After(now) {
+			return errors.New("ends_at must be after starts_at and in the future")
+		}
+		data.EndsAt = end.Format(time.RFC3339)
+	}
+	return nil
+}
+
+func announcementStatus(a models.Announcement, now time.Time) string {
+	switch {
+	case parseTime(a.StartsAt).After(now):
+		return models.AnnouncementScheduled
+	case a.EndsAt != "" && !parseTime(a.EndsAt).After(now):
+		return models.AnnouncementExpired
+	}
+	return models.AnnouncementActive
+}
+
+// activeAnnouncements returns the announcements active at the kitchens,
+// banners first and then by latest start.
+func (h *Handler) activeAnnouncements(kitchenIDs []string, now time.Time) []models.Announcement {
+	res := []models.Announcement{}
+	for _, a := range h.Storage.Announcements.List() {
+		if !slices.Contains(kitchenIDs, a.KitchenId) {
+			continue
+		}
+		if a.Status = announcementStatus(a, now); a.Status == models.AnnouncementActive {
+			res = append(res, a)
+		}
+	}
+	slices.SortFunc(res, func(a, b models.Announcement) int {
+		if banner := a.Kind == models.AnnouncementBanner; banner != (b.Kind == models.AnnouncementBanner) {
+			if banner {
+				return -1
+			}
+			return 1
+		}
+		return parseTime(b.StartsAt).Compare(parseTime(a.StartsAt))
+	})
+	return res
+}
+
+// parseTime parses a stored RFC 3339 time, which is the zero time if
+// it is missing.
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/dish"
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxBatchSize = 100
+
+// batch runs fn for every item and collects the per-item outcomes. A
+// failing item does not stop the others.
+func batch[T any](c *gin.Context, items []T, fn func(ctx context.Context, item T) (string, any, error)) models.BatchResult {
+	res := models.BatchResult{Results: make([]models.ItemResult, 0, len(items))}
+
+	for i, item := range items {
+		ctx, cancel := callContext(c, defaultTimeout)
+		id, data, err := fn(ctx, item)
+		cancel()
+
+		r := models.ItemResult{Index: i, Id: id, Status: http.StatusOK, Data: data}
+		if err != nil {
+			r.Status, r.Code = errorStatus(err)
+			r.Message = err.Error()
+			r.Data = nil
+			res.Failed++
+		} else {
+			res.Succeeded++
+		}
+		res.Results = append(res.Results, r)
+	}
+
+	return res
+}
+
+// batchAccess checks that the caller may act for the kitchen of a batch
+// item as accessRole does, refusing with PermissionDenied so that only
+// the item is answered 403.
+func (h *Handler) batchAccess(ctx context.Context, c *gin.Context, kitchenID, permission string) error {
+	allowed, err := h.canAccess(ctx, c, "", kitchenID, permission)
+	if err != nil {
+		return errors.Wrap(err, "error getting kitchen")
+	}
+	if !allowed {
+		return status.Error(codes.PermissionDenied, "access denied")
+	}
+	return nil
+}
+
+// renderBatch answers 200 when every item succeeded and 207 Multi-Status
+// when some or all failed, so clients always inspect the item list.
+func (h *Handler) renderBatch(c *gin.Context, res models.BatchResult) {
+	code := http.StatusOK
+	if res.Failed > 0 {
+		code = http.StatusMultiStatus
+	}
+	h.render(c, code, res)
+}
+
+// errorStatus maps an item error to an HTTP status and a snake_case
+// error code. Errors not coming from gRPC are treated as bad input.
+func errorStatus(err error) (int, string) {
+	st, ok := status.FromError(errors.Cause(err))
+	if !ok {
+		return http.StatusBadRequest, "invalid_argument"
+	}
+
+	code := toSnake(st.Code().String())
+	switch st.Code() {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest, code
+	case codes.NotFound:
+		return http.StatusNotFound, code
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict, code
+	case codes.PermissionDenied:
+		return http.StatusForbidden, code
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized, code
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests, code
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable, code
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout, code
+	}
+	return http.StatusInternalServerError, code
+}
+
+func toSnake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ImportDishes godoc
+// @Summary Imports dishes
+// @Description Creates up to 100 dishes in one request; every dish gets its own result. Each dish must declare its allergens, as for POST /dishes. Dishes are created for kitchens the caller owns or is staff with the menu permission of; the others fail with 403
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param dishes body []models.NewDish true "Dishes"
+// @Success 200 {object} models.BatchResult
+// @Success 207 {object} models.BatchResult "Some dishes failed"
+// @Failure 400 {object} string "Invalid dish data"
+// @Router /dishes/batch [post]
+func (h *Handler) ImportDishes(c *gin.Context) {
+	h.Logger.Info("ImportDishes method is starting")
+
+	var data []*models.NewDish
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid dish data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if len(data) == 0 || len(data) > maxBatchSize {
+		er := errors.Errorf("invalid dish data: between 1 and %d dishes are allowed", maxBatchSize).Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res := batch(c, data, func(ctx context.Context, d *models.NewDish) (string, any, error) {
+		if d == nil || d.Name == "" {
+			return "", nil, errors.New("dish name is required")
+		}
+		if _, err := uuid.Parse(d.KitchenId); err != nil {
+			return "", nil, errors.Wrap(err, "invalid kitchen id")
+		}
+		if err := h.batchAccess(ctx, c, d.KitchenId, models.StaffMenu); err != nil {
+			return "", nil, err
+		}
+		allergens, err := declaredAllergens(d.Allergens)
+		if err != nil {
+			return "", nil, err
+		}
+
+		dish, err := h.DishClient.Add(ctx, newDish(d))
+		if err != nil {
+			return "", nil, errors.Wrap(err, "error creating dish")
+		}
+		h.trackCatalog(models.EventDishCreated, models.CatalogDish, dish.Id, dish.KitchenId, dish)
+		h.declareAllergens(dish.Id, allergens)
+		h.purgeDishes(dish.Id)
+		return dish.Id, dish, nil
+	})
+
+	h.Logger.Info("ImportDishes method has finished successfully")
+	h.renderBatch(c, res)
+}
+
+// SetAvailability godoc
+// @Summary Sets dish availability
+// @Description Marks up to 100 dishes as available or sold out; every dish gets its own result. Customers waiting for a dish are notified once it is available. Only dishes of kitchens the caller owns or is staff with the menu permission of are changed; the others fail with 403
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param dishes body []models.Availability true "Dish availability"
+// @Success 200 {object} models.BatchResult
+// @Success 207 {object} models.BatchResult "Some dishes failed"
+// @Failure 400 {object} string "Invalid availability data"
+// @Router /dishes/availability [put]
+func (h *Handler) SetAvailability(c *gin.Context) {
+	h.Logger.Info("SetAvailability method is starting")
+
+	var data []models.Availability
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid availability data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if len(data) == 0 || len(data) > maxBatchSize {
+		er := errors.Errorf("invalid availability data: between 1 and %d dishes are allowed", maxBatchSize).Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res := batch(c, data, func(ctx context.Context, a models.Availability) (string, any, error) {
+		if _, err := uuid.Parse(a.DishId); err != nil {
+			return a.DishId, nil, errors.Wrap(err, "invalid dish ID")
+		}
+		dish, err := h.DishClient.Read(ctx, &pb.ID{Id: a.DishId})
+		if err != nil {
+			return a.DishId, nil, errors.Wrap(err, "error getting dish")
+		}
+		if err := h.batchAccess(ctx, c, dish.KitchenId, models.StaffMenu); err != nil {
+			return a.DishId, nil, err
+		}
+
+		upd, err := h.setDishAvailable(ctx, a.DishId, a.Available)
+		return a.DishId, upd, err
+	})
+
+	h.Logger.Info("SetAvailability method has finished successfully")
+	h.renderBatch(c, res)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// BlockKitchen godoc
+// @Summary Blocks a kitchen
+// @Description Hides a kitchen from the user's kitchen lists, searches and feed. The kitchen's own page stays reachable
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param kitchen body models.NewBlockedKitchen true "Kitchen to block"
+// @Success 200 {object} models.BlockedKitchens
+// @Failure 400 {object} string "Invalid user or kitchen ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Kitchen not found"
+// @Router /users/{id}/blocked-kitchens [post]
+func (h *Handler) BlockKitchen(c *gin.Context) {
+	h.Logger.Info("BlockKitchen method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewBlockedKitchen
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		_, err = uuid.Parse(data.KitchenId)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid kitchen id").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	name, err := h.KitchenClient.GetName(ctx, &pbk.ID{Id: data.KitchenId})
+	if err != nil {
+		st, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting kitchen").Error()
+		c.AbortWithStatusJSON(st,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	blocked := h.Storage.BlockedKitchens.Update(userID, func(list []models.BlockedKitchen, _ bool) []models.BlockedKitchen {
+		if slices.ContainsFunc(list, func(b models.BlockedKitchen) bool {
+			return b.KitchenId == data.KitchenId
+		}) {
+			return list
+		}
+		return append(slices.Clone(list), models.BlockedKitchen{
+			KitchenId:   data.KitchenId,
+			KitchenName: name.Name,
+			BlockedAt:   time.Now().Format(time.RFC3339),
+		})
+	})
+
+	h.Logger.Info("BlockKitchen method has finished successfully")
+	h.render(c, http.StatusOK, models.BlockedKitchens{Kitchens: blocked})
+}
+
+// FetchBlockedKitchens godoc
+// @Summary Gets blocked kitchens
+// @Description Lists the kitchens the user blocked, oldest first
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.BlockedKitchens
+// @Failure 400 {object} string "Invalid user ID"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/blocked-kitchens [get]
+func (h *Handler) FetchBlockedKitchens(c *gin.Context) {
+	h.Logger.Info("FetchBlockedKitchens method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	blocked, _ := h.Storage.BlockedKitchens.Get(userID)
+	res := models.BlockedKitchens{Kitchens: append([]models.BlockedKitchen{}, blocked...)}
+
+	h.Logger.Info("FetchBlockedKitchens method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// UnblockKitchen godoc
+// @Summary Unblocks a kitchen
+// @Description Shows a blocked kitchen to the user again
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param kitchen_id path string true "Kitchen ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid user or kitchen ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Kitchen is not blocked"
+// @Router /users/{id}/blocked-kitchens/{kitchen_id} [delete]
+func (h *Handler) UnblockKitchen(c *gin.Context) {
+	h.Logger.Info("UnblockKitchen method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+	kitchenID, err := pathUUID(c, "kitchen_id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if !h.blocked(userID, kitchenID) {
+		er := "kitchen is not blocked"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Storage.BlockedKitchens.Update(userID, func(list []models.BlockedKitchen, _ bool) []models.BlockedKitchen {
+		return slices.DeleteFunc(slices.Clone(list), func(b models.BlockedKitchen) bool {
+			return b.KitchenId == kitchenID
+		})
+	})
+
+	h.Logger.Info("UnblockKitchen method has finished successfully")
+	h.render(c, http.StatusOK, "Kitchen unblocked successfully")
+}
+
+// blocked tells whether the user blocked the kitchen.
+func (h *Handler) blocked(userID, kitchenID string) bool {
+	list, _ := h.Storage.BlockedKitchens.Get(userID)
+	return slices.ContainsFunc(list, func(b models.BlockedKitchen) bool {
+		return b.KitchenId == kitchenID
+	})
+}
+
+// callerBlocked tells whether the caller blocked the kitchen.
+func (h *Handler) callerBlocked(c *gin.Context, kitchenID string) bool {
+	userID := c.GetString(middleware.UserIDKey)
+	return userID != "" && h.blocked(userID, kitchenID)
+}
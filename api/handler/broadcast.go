@@ -0,0 +1,340 @@
+package handler
+
+import (
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/broadcast"
+	"api-gateway/pkg/email"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxBroadcastTitle = 100
+	maxBroadcastBody  = 2000
+)
+
+// CreateBroadcast godoc
+// @Summary Schedules a broadcast
+// @Description Schedules a push and/or email campaign to the users of a segment, sent at send_at or right away. Users who turned promos off for a channel are skipped on it
+// @Tags broadcast
+// @Security ApiKeyAuth
+// @Param broadcast body models.NewBroadcast true "Broadcast"
+// @Success 200 {object} models.Broadcast
+// @Failure 400 {object} string "Invalid broadcast data"
+// @Router /admin/broadcasts [post]
+func (h *Handler) CreateBroadcast(c *gin.Context) {
+	h.Logger.Info("CreateBroadcast method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewBroadcast
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = h.validateBroadcast(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid broadcast data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	b := models.Broadcast{
+		Id:           uuid.NewString(),
+		NewBroadcast: data,
+		Status:       models.BroadcastScheduled,
+		CreatedBy:    userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	h.Storage.Broadcasts.Set(b.Id, b)
+	h.Broadcasts.RunDue(time.Now())
+
+	h.Logger.Info("CreateBroadcast method has finished successfully")
+	h.render(c, http.StatusOK, b)
+}
+
+// FetchBroadcasts godoc
+// @Summary Gets broadcasts
+// @Description Lists broadcasts with their stats, newest first, optionally by status
+// @Tags broadcast
+// @Security ApiKeyAuth
+// @Param status query string false "scheduled, sending, sent or canceled"
+// @Success 200 {object} models.Broadcasts
+// @Router /admin/broadcasts [get]
+func (h *Handler) FetchBroadcasts(c *gin.Context) {
+	h.Logger.Info("FetchBroadcasts method is starting")
+
+	status := c.Query("status")
+	res := models.Broadcasts{Broadcasts: []models.Broadcast{}}
+	for _, b := range h.Storage.Broadcasts.List() {
+		if status == "" || b.Status == status {
+			res.Broadcasts = append(res.Broadcasts, b)
+		}
+	}
+	slices.SortFunc(res.Broadcasts, func(a, b models.Broadcast) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchBroadcasts method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetBroadcast godoc
+// @Summary Gets a broadcast
+// @Description Gets a broadcast with its stats, which are updated while it is being sent
+// @Tags broadcast
+// @Security ApiKeyAuth
+// @Param id path string true "Broadcast ID"
+// @Success 200 {object} models.Broadcast
+// @Failure 404 {object} string "Broadcast not found"
+// @Router /admin/broadcasts/{id} [get]
+func (h *Handler) GetBroadcast(c *gin.Context) {
+	h.Logger.Info("GetBroadcast method is starting")
+
+	b, ok := h.findBroadcast(c)
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("GetBroadcast method has finished successfully")
+	h.render(c, http.StatusOK, b)
+}
+
+// UpdateBroadcast godoc
+// @Summary Updates a broadcast
+// @Description Changes a broadcast that has not started yet
+// @Tags broadcast
+// @Security ApiKeyAuth
+// @Param id path string true "Broadcast ID"
+// @Param broadcast body models.NewBroadcast true "Broadcast"
+// @Success 200 {object} models.Broadcast
+// @Failure 400 {object} string "Invalid broadcast data"
+// @Failure 404 {object} string "Broadcast not found"
+// @Failure 409 {object} string "Broadcast already started or canceled"
+// @Router /admin/broadcasts/{id} [put]
+func (h *Handler) UpdateBroadcast(c *gin.Context) {
+	h.Logger.Info("UpdateBroadcast method is starting")
+
+	cur, ok := h.findBroadcast(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewBroadcast
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = h.validateBroadcast(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid broadcast data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// The scheduler may have started it since it was read.
+	var status string
+	b := h.Storage.Broadcasts.Update(cur.Id, func(b models.Broadcast, _ bool) models.Broadcast {
+		status = b.Status
+		if b.Status == models.BroadcastScheduled {
+			b.NewBroadcast = data
+			b.UpdatedAt = time.Now().Format(time.RFC3339)
+		}
+		return b
+	})
+	if status != models.BroadcastScheduled {
+		er := errors.Errorf("broadcast is %s", status).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	h.Broadcasts.RunDue(time.Now())
+
+	h.Logger.Info("UpdateBroadcast method has finished successfully")
+	h.render(c, http.StatusOK, b)
+}
+
+// CancelBroadcast godoc
+// @Summary Cancels a broadcast
+// @Description Cancels a scheduled broadcast, or stops one being sent. Users reached so far stay in the stats
+// @Tags broadcast
+// @Security ApiKeyAuth
+// @Param id path string true "Broadcast ID"
+// @Success 200 {object} models.Broadcast
+// @Failure 404 {object} string "Broadcast not found"
+// @Failure 409 {object} string "Broadcast already sent or canceled"
+// @Router /admin/broadcasts/{id}/cancel [post]
+func (h *Handler) CancelBroadcast(c *gin.Context) {
+	h.Logger.Info("CancelBroadcast method is starting")
+
+	cur, ok := h.findBroadcast(c)
+	if !ok {
+		return
+	}
+
+	var status string
+	b := h.Storage.Broadcasts.Update(cur.Id, func(b models.Broadcast, _ bool) models.Broadcast {
+		status = b.Status
+		if b.Status == models.BroadcastScheduled || b.Status == models.BroadcastSending {
+			b.Status = models.BroadcastCanceled
+			b.UpdatedAt = time.Now().Format(time.RFC3339)
+			b.FinishedAt = b.UpdatedAt
+		}
+		return b
+	})
+	if status != models.BroadcastScheduled && status != models.BroadcastSending {
+		er := errors.Errorf("broadcast is %s", status).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("CancelBroadcast method has finished successfully")
+	h.render(c, http.StatusOK, b)
+}
+
+func (h *Handler) findBroadcast(c *gin.Context) (models.Broadcast, bool) {
+	id, err := pathUUID(c, "id", "broadcast id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Broadcast{}, false
+	}
+
+	b, ok := h.Storage.Broadcasts.Get(id)
+	if !ok {
+		er := "broadcast not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Broadcast{}, false
+	}
+	return b, true
+}
+
+// validateBroadcast checks the broadcast, dropping duplicate channels and
+// filter values, and sets its send time to now when there is none.
+func (h *Handler) validateBroadcast(data *models.NewBroadcast) error {
+	data.Title, data.Body = strings.TrimSpace(data.Title), strings.TrimSpace(data.Body)
+	if data.Title == "" || utf8.RuneCountInString(data.Title) > maxBroadcastTitle {
+		return errors.Errorf("title must have 1 to %d characters", maxBroadcastTitle)
+	}
+	if data.Body == "" || utf8.RuneCountInString(data.Body) > maxBroadcastBody {
+		return errors.Errorf("body must have 1 to %d characters", maxBroadcastBody)
+	}
+
+	var channels []string
+	for _, ch := range data.Channels {
+		switch ch {
+		case models.ChannelPush:
+		case models.ChannelEmail:
+			if !h.Mailer.Enabled() {
+				return email.ErrDisabled
+			}
+		default:
+			return errors.Errorf("channel must be %s or %s", models.ChannelPush, models.ChannelEmail)
+		}
+		if !slices.Contains(channels, ch) {
+			channels = append(channels, ch)
+		}
+	}
+	if len(channels) == 0 {
+		return errors.New("at least one channel is required")
+	}
+	data.Channels = channels
+
+	seg := &data.Segment
+	for _, ids := range [][]string{seg.UserIds, seg.KitchenIds} {
+		for _, id := range ids {
+			if _, err := uuid.Parse(id); err != nil {
+				return errors.Wrapf(err, "invalid id %q", id)
+			}
+		}
+	}
+	slices.Sort(seg.UserIds)
+	slices.Sort(seg.KitchenIds)
+	seg.UserIds, seg.KitchenIds = slices.Compact(seg.UserIds), slices.Compact(seg.KitchenIds)
+	if _, err := broadcast.ParseTime(seg.ActiveSince); err != nil {
+		return errors.Wrap(err, "invalid active_since")
+	}
+	var cuisines []string
+	for _, cu := range seg.Cuisines {
+		if cu = strings.TrimSpace(cu); cu != "" && !slices.Contains(cuisines, cu) {
+			cuisines = append(cuisines, cu)
+		}
+	}
+	seg.Cuisines = cuisines
+	if len(seg.UserIds) == 0 && len(seg.KitchenIds) == 0 && seg.ActiveSince == "" && len(seg.Cuisines) == 0 {
+		return errors.New("segment needs at least one filter")
+	}
+
+	if data.SendAt == "" {
+		data.SendAt = time.Now().Format(time.RFC3339)
+	}
+	at, err := time.Parse(time.RFC3339, data.SendAt)
+	if err != nil {
+		return errors.Wrap(err, "invalid send_at")
+	}
+	data.SendAt = at.Format(time.RFC3339)
+	return nil
+}
+
+// sendBroadcast pushes and emails the broadcast to one user on the
+// channels they get promos through.
+func (h *Handler) sendBroadcast(ctx context.Context, b models.Broadcast, userID string) models.BroadcastStats {
+	var stats models.BroadcastStats
+	for _, ch := range b.Channels {
+		if !h.notifies(userID, models.NotifyPromos, ch) {
+			stats.Skipped++
+			continue
+		}
+
+		switch ch {
+		case models.ChannelPush:
+			h.Events.Emit(models.EventBroadcast, userID, models.BroadcastMessage{
+				BroadcastId: b.Id,
+				UserId:      userID,
+				Title:       b.Title,
+				Body:        b.Body,
+			})
+			stats.Pushed++
+		case models.ChannelEmail:
+			profile, err := h.UserClient.GetProfile(ctx, &pbu.ID{Id: userID})
+			if err == nil && profile.Email == "" {
+				stats.Skipped++
+				continue
+			}
+			if err == nil {
+				_, err = h.Mailer.Queue(profile.Email, "en", models.EmailBroadcast,
+					email.BroadcastData{Title: b.Title, Body: b.Body})
+			}
+			if err != nil {
+				h.Logger.Error(errors.Wrapf(err, "error emailing broadcast %s to user %s", b.Id, userID).Error())
+				stats.Failed++
+				continue
+			}
+			stats.Emailed++
+		}
+	}
+	return stats
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"api-gateway/pkg/cdn"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tagEdge lets the CDN cache the response under the keys. Only responses
+// that are the same for every caller may be tagged. They still differ by
+// API format, by platform, which picks the response profile, and by
+// language, so the edge keeps one per each.
+func (h *Handler) tagEdge(c *gin.Context, keys ...string) {
+	if !h.Edge.Enabled() {
+		return
+	}
+	c.Writer.Header().Add("Vary", "X-API-Format, X-Platform, Accept-Language")
+	h.Edge.Tag(c.Writer.Header(), keys...)
+}
+
+// purgeDishes drops the cached responses showing the dishes: their own
+// and the dish list.
+func (h *Handler) purgeDishes(ids ...string) {
+	keys := []string{cdn.KeyDishes}
+	for _, id := range ids {
+		keys = append(keys, cdn.DishKey(id))
+	}
+	h.Edge.Purge(keys...)
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/version"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetChangelog godoc
+// @Summary Gets the API changelog
+// @Description Lists the changes of the API by version, the latest first: routes added, changed, deprecated and removed. Deprecated routes tell when they sunset and which route succeeds them; until then they answer with Deprecation and Sunset headers, and with 410 after. Anyone may read it
+// @Tags docs
+// @Param since query string false "Only versions after this one, such as 1.0"
+// @Success 200 {object} models.Changelog
+// @Router /changelog [get]
+func (h *Handler) GetChangelog(c *gin.Context) {
+	h.Logger.Info("GetChangelog method is starting")
+
+	since := c.Query("since")
+	res := models.Changelog{Versions: []models.APIVersion{}}
+	for _, v := range models.APIChangelog {
+		if since == "" || version.Compare(v.Version, since) > 0 {
+			res.Versions = append(res.Versions, v)
+		}
+	}
+
+	h.Logger.Info("GetChangelog method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	pbo "api-gateway/genproto/order"
+	"api-gateway/models"
+	"api-gateway/pkg/email"
+	"api-gateway/pkg/payments"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// ChargebackWebhook godoc
+// @Summary Receives chargeback notifications
+// @Description Endpoint for payment providers to report chargebacks, such as stripe's charge.dispute.* events. Calls are authenticated with the provider's signature, not a token. A new chargeback flags its customer for fraud screening and is emailed to the admins
+// @Tags payment
+// @Param provider path string true "Provider" Enums(stripe)
+// @Success 200 {object} object "Provider-specific reply"
+// @Failure 400 {object} string "Invalid notification"
+// @Failure 401 {object} string "Invalid signature"
+// @Failure 404 {object} string "Unknown provider or provider sends no chargebacks"
+// @Router /payments/chargebacks/{provider} [post]
+func (h *Handler) ChargebackWebhook(c *gin.Context) {
+	h.Logger.Info("ChargebackWebhook method is starting")
+
+	provider, err := h.Payments.Get(c.Param("provider"))
+	if err != nil || provider.Name() != c.Param("provider") {
+		er := "unknown payment provider"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	disputer, ok := provider.(payments.Disputer)
+	if !ok {
+		er := payments.ErrNoChargebacks.Error()
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	cb, reply, err := disputer.Chargeback(c.Request, h.Storage.Payments)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Cause(err) == payments.ErrSignature {
+			status = http.StatusUnauthorized
+		}
+		er := errors.Wrap(err, "invalid chargeback notification").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if cb.PaymentId != "" {
+		h.applyChargeback(c, provider.Name(), cb)
+	}
+
+	h.Logger.Info("ChargebackWebhook method has finished successfully")
+	// Providers expect their own reply format, so it is never enveloped.
+	c.JSON(http.StatusOK, reply)
+}
+
+// applyChargeback records a chargeback. The first notice of one flags
+// the customer, who is found through the order, and alerts the admins;
+// later ones update its status.
+func (h *Handler) applyChargeback(ctx context.Context, provider string, cb payments.Chargeback) {
+	p, _ := h.Storage.Payments.Get(cb.PaymentId)
+	now := time.Now().Format(time.RFC3339)
+
+	var opened, changed bool
+	res := h.Storage.Chargebacks.Update(provider+":"+cb.Id, func(r models.Chargeback, ok bool) models.Chargeback {
+		if !ok {
+			opened = true
+			r = models.Chargeback{
+				Id:        cb.Id,
+				Provider:  provider,
+				PaymentId: p.Id,
+				OrderId:   p.OrderId,
+				OpenedAt:  cb.At.Format(time.RFC3339),
+			}
+		}
+		changed = opened || r.Status != cb.Status
+		r.Amount, r.Currency, r.Reason, r.Status = cb.Amount, cb.Currency, cb.Reason, cb.Status
+		r.UpdatedAt = now
+		return r
+	})
+	if !changed {
+		return
+	}
+	if !opened {
+		h.Logger.Info(fmt.Sprintf("chargeback %s of order %s is %s", res.Id, res.OrderId, res.Status))
+		h.Events.Emit(models.EventChargebackUpdated, res.OrderId, res)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	order, err := h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: res.OrderId})
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error getting order of chargeback "+res.Id).Error())
+	} else {
+		res = h.Storage.Chargebacks.Update(provider+":"+cb.Id, func(r models.Chargeback, _ bool) models.Chargeback {
+			r.UserId = order.UserId
+			return r
+		})
+		h.Fraud.ChargedBack(order.UserId)
+	}
+
+	h.Logger.Warn(fmt.Sprintf("chargeback %s opened on order %s of user %s: %.2f %s, %s",
+		res.Id, res.OrderId, res.UserId, res.Amount, res.Currency, res.Reason))
+	h.Events.Emit(models.EventChargebackOpened, res.OrderId, res)
+	h.alertAdmins(res)
+}
+
+// alertAdmins emails a new chargeback to ADMIN_ALERT_EMAILS.
+func (h *Handler) alertAdmins(cb models.Chargeback) {
+	data := email.AlertData{
+		Title: fmt.Sprintf("Chargeback on order %s", cb.OrderId),
+		Body: fmt.Sprintf("A %s customer disputed a payment.\n\nAmount: %.2f %s\nReason: %s\nOrder: %s\nPayment: %s\nCustomer: %s\nDispute: %s",
+			cb.Provider, cb.Amount, cb.Currency, cb.Reason, cb.OrderId, cb.PaymentId, cb.UserId, cb.Id),
+	}
+	for _, to := range h.AlertEmails {
+		if _, err := h.Mailer.Queue(to, "en", models.EmailAlert, data); err != nil {
+			h.Logger.Error(errors.Wrap(err, "error emailing chargeback alert").Error())
+		}
+	}
+}
+
+// FetchChargebacks godoc
+// @Summary Gets chargebacks
+// @Description Lists the chargebacks providers reported, newest first, optionally by status, customer or order
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param status query string false "open, won or lost"
+// @Param user_id query string false "Customer ID"
+// @Param order_id query string false "Order ID"
+// @Success 200 {object} models.Chargebacks
+// @Router /admin/chargebacks [get]
+func (h *Handler) FetchChargebacks(c *gin.Context) {
+	h.Logger.Info("FetchChargebacks method is starting")
+
+	status, userID, orderID := c.Query("status"), c.Query("user_id"), c.Query("order_id")
+	res := models.Chargebacks{Chargebacks: []models.Chargeback{}}
+	for _, cb := range h.Storage.Chargebacks.List() {
+		if (status == "" || cb.Status == status) && (userID == "" || cb.UserId == userID) &&
+			(orderID == "" || cb.OrderId == orderID) {
+			res.Chargebacks = append(res.Chargebacks, cb)
+		}
+	}
+	slices.SortFunc(res.Chargebacks, func(a, b models.Chargeback) int {
+		return strings.Compare(b.OpenedAt, a.OpenedAt)
+	})
+
+	h.Logger.Info("FetchChargebacks method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
@@ -0,0 +1,322 @@
+package handler
+
+import (
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// FetchCommissionRates godoc
+// @Summary Gets commission rates
+// @Description Lists the default commission and the rates set for kitchens and cuisine types
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.CommissionRates
+// @Failure 403 {object} string "Admin role is required"
+// @Router /admin/commissions [get]
+func (h *Handler) FetchCommissionRates(c *gin.Context) {
+	h.Logger.Info("FetchCommissionRates method is starting")
+
+	res := models.CommissionRates{
+		DefaultPercent: h.Pricing.CommissionPercent(),
+		Rates:          h.Storage.CommissionRates.List(),
+	}
+	slices.SortFunc(res.Rates, func(a, b models.CommissionRate) int {
+		return cmp.Or(strings.Compare(a.Scope, b.Scope), strings.Compare(a.Key, b.Key))
+	})
+
+	h.Logger.Info("FetchCommissionRates method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// SetCommissionRate godoc
+// @Summary Sets a commission rate
+// @Description Sets the commission percent for a kitchen or for all kitchens of a cuisine type. Orders placed afterwards are charged the new rate
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param scope path string true "What the rate applies to" Enums(kitchen, cuisine)
+// @Param key path string true "Kitchen ID or cuisine type"
+// @Param rate body models.NewCommissionRate true "Rate"
+// @Success 200 {object} models.CommissionRate
+// @Failure 400 {object} string "Invalid scope, key or rate"
+// @Failure 403 {object} string "Admin role is required"
+// @Failure 404 {object} string "Kitchen not found"
+// @Router /admin/commissions/{scope}/{key} [put]
+func (h *Handler) SetCommissionRate(c *gin.Context) {
+	h.Logger.Info("SetCommissionRate method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	scope, key, err := h.commissionTarget(c)
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := err.Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var data models.NewCommissionRate
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid commission rate").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if data.Percent < 0 || data.Percent > 100 {
+		er := "invalid commission rate: percent must be between 0 and 100"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339Nano)
+	var old *float32
+	rate := h.Storage.CommissionRates.Update(commissionKey(scope, key),
+		func(r models.CommissionRate, ok bool) models.CommissionRate {
+			if ok {
+				p := r.Percent
+				old = &p
+			}
+			return models.CommissionRate{
+				Scope:     scope,
+				Key:       key,
+				Percent:   data.Percent,
+				UpdatedBy: userID,
+				UpdatedAt: now,
+			}
+		})
+	h.recordCommissionChange(models.CommissionChange{
+		Scope:      scope,
+		Key:        key,
+		OldPercent: old,
+		NewPercent: &data.Percent,
+		Note:       data.Note,
+		ChangedBy:  userID,
+		ChangedAt:  now,
+	})
+
+	h.Logger.Info("SetCommissionRate method has finished successfully")
+	h.render(c, http.StatusOK, rate)
+}
+
+// DeleteCommissionRate godoc
+// @Summary Removes a commission rate
+// @Description Removes the rate of a kitchen or cuisine type, so that its orders fall back to the cuisine or default rate
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param scope path string true "What the rate applies to" Enums(kitchen, cuisine)
+// @Param key path string true "Kitchen ID or cuisine type"
+// @Param note query string false "Reason for the audit trail"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid scope or key"
+// @Failure 403 {object} string "Admin role is required"
+// @Failure 404 {object} string "No rate is set"
+// @Router /admin/commissions/{scope}/{key} [delete]
+func (h *Handler) DeleteCommissionRate(c *gin.Context) {
+	h.Logger.Info("DeleteCommissionRate method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	scope, key, err := commissionParams(c)
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	rate, ok := h.Storage.CommissionRates.Get(commissionKey(scope, key))
+	if !ok || !h.Storage.CommissionRates.Delete(commissionKey(scope, key)) {
+		er := "no commission rate is set for " + scope + " " + key
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	h.recordCommissionChange(models.CommissionChange{
+		Scope:      scope,
+		Key:        key,
+		OldPercent: &rate.Percent,
+		Note:       c.Query("note"),
+		ChangedBy:  userID,
+		ChangedAt:  time.Now().Format(time.RFC3339Nano),
+	})
+
+	h.Logger.Info("DeleteCommissionRate method has finished successfully")
+	h.render(c, http.StatusOK, "Commission rate deleted successfully")
+}
+
+// FetchCommissionHistory godoc
+// @Summary Gets commission changes
+// @Description Lists changes to commission rates, newest first, optionally only those of a scope or of one kitchen or cuisine type
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param scope query string false "Scope of the rates" Enums(kitchen, cuisine)
+// @Param key query string false "Kitchen ID or cuisine type"
+// @Success 200 {object} models.CommissionHistory
+// @Failure 403 {object} string "Admin role is required"
+// @Router /admin/commissions/history [get]
+func (h *Handler) FetchCommissionHistory(c *gin.Context) {
+	h.Logger.Info("FetchCommissionHistory method is starting")
+
+	scope, key := c.Query("scope"), c.Query("key")
+	if scope == models.CommissionCuisine {
+		key = cuisineKey(key)
+	}
+
+	res := models.CommissionHistory{Changes: []models.CommissionChange{}}
+	for _, ch := range h.Storage.CommissionChanges.List() {
+		if (scope == "" || ch.Scope == scope) && (key == "" || ch.Key == key) {
+			res.Changes = append(res.Changes, ch)
+		}
+	}
+	slices.SortFunc(res.Changes, func(a, b models.CommissionChange) int {
+		at, _ := time.Parse(time.RFC3339Nano, a.ChangedAt)
+		bt, _ := time.Parse(time.RFC3339Nano, b.ChangedAt)
+		return bt.Compare(at)
+	})
+
+	h.Logger.Info("FetchCommissionHistory method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetEffectiveCommission godoc
+// @Summary Gets a kitchen's commission rate
+// @Description Tells the commission percent charged on the kitchen's new orders and whether it is the kitchen's, its cuisine's or the default rate
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.EffectiveCommission
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Admin role is required"
+// @Failure 404 {object} string "Kitchen not found"
+// @Router /admin/commissions/kitchens/{id}/effective [get]
+func (h *Handler) GetEffectiveCommission(c *gin.Context) {
+	h.Logger.Info("GetEffectiveCommission method is starting")
+
+	id, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	res, err := h.commissionRate(ctx, id)
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting kitchen").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetEffectiveCommission method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// commissionRate resolves the commission charged on the kitchen's new
+// orders: its own rate, else its cuisine's, else the default.
+func (h *Handler) commissionRate(ctx context.Context, kitchenID string) (models.EffectiveCommission, error) {
+	res := models.EffectiveCommission{
+		KitchenId: kitchenID,
+		Percent:   h.Pricing.CommissionPercent(),
+		Source:    models.CommissionDefault,
+	}
+
+	if r, ok := h.Storage.CommissionRates.Get(commissionKey(models.CommissionKitchen, kitchenID)); ok {
+		res.Percent, res.Source = r.Percent, models.CommissionKitchen
+		return res, nil
+	}
+
+	// Only look the kitchen up when there are cuisine rates to match.
+	if !slices.ContainsFunc(h.Storage.CommissionRates.List(), func(r models.CommissionRate) bool {
+		return r.Scope == models.CommissionCuisine
+	}) {
+		return res, nil
+	}
+
+	k, err := h.KitchenClient.Get(ctx, &pbk.ID{Id: kitchenID})
+	if err != nil {
+		return res, err
+	}
+	if r, ok := h.Storage.CommissionRates.Get(commissionKey(models.CommissionCuisine, cuisineKey(k.CuisineType))); ok {
+		res.Percent, res.Source = r.Percent, models.CommissionCuisine
+	}
+	return res, nil
+}
+
+// commissionTarget reads the scope and key of a rate, checking that the
+// kitchen exists.
+func (h *Handler) commissionTarget(c *gin.Context) (string, string, error) {
+	scope, key, err := commissionParams(c)
+	if err != nil || scope != models.CommissionKitchen {
+		return scope, key, err
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	if _, err := h.KitchenClient.Get(ctx, &pbk.ID{Id: key}); err != nil {
+		return "", "", errors.Wrap(err, "error getting kitchen")
+	}
+	return scope, key, nil
+}
+
+func commissionParams(c *gin.Context) (string, string, error) {
+	scope, key := c.Param("scope"), c.Param("key")
+	switch scope {
+	case models.CommissionKitchen:
+		if _, err := uuid.Parse(key); err != nil {
+			return "", "", errors.Wrap(err, "invalid kitchen id")
+		}
+	case models.CommissionCuisine:
+		if key = cuisineKey(key); key == "" {
+			return "", "", errors.New("cuisine type is required")
+		}
+	default:
+		return "", "", errors.Errorf("scope must be %s or %s",
+			models.CommissionKitchen, models.CommissionCuisine)
+	}
+	return scope, key, nil
+}
+
+func (h *Handler) recordCommissionChange(ch models.CommissionChange) {
+	ch.Id = uuid.NewString()
+	h.Storage.CommissionChanges.Set(ch.Id, ch)
+}
+
+func commissionKey(scope, key string) string {
+	return scope + ":" + key
+}
+
+// cuisineKey makes cuisine types match regardless of case and spacing.
+func cuisineKey(cuisine string) string {
+	return strings.ToLower(strings.TrimSpace(cuisine))
+}
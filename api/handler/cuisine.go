@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	"api-gateway/models"
+	"api-gateway/storage"
+	"cmp"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+var cuisineCode = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// FetchCuisines godoc
+// @Summary Gets cuisine types
+// @Description Lists the cuisine types kitchens can be searched by, with names in the language of the lang query or the Accept-Language header
+// @Tags cuisine
+// @Security ApiKeyAuth
+// @Param lang query string false "Language, e.g. ru"
+// @Success 200 {object} models.LocalizedCuisines
+// @Router /cuisines [get]
+func (h *Handler) FetchCuisines(c *gin.Context) {
+	h.Logger.Info("FetchCuisines method is starting")
+
+	lang := requestLang(c)
+	res := models.LocalizedCuisines{Lang: lang, Cuisines: []models.LocalizedCuisine{}}
+	for _, cu := range h.Storage.Cuisines.List() {
+		res.Cuisines = append(res.Cuisines, cu.Localized(lang))
+	}
+	slices.SortFunc(res.Cuisines, func(a, b models.LocalizedCuisine) int {
+		return strings.Compare(a.DisplayName, b.DisplayName)
+	})
+
+	h.Logger.Info("FetchCuisines method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// FetchCuisineTaxonomy godoc
+// @Summary Gets the cuisine taxonomy
+// @Description Lists the cuisine types with all their translations and aliases
+// @Tags cuisine
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Cuisines
+// @Router /admin/cuisines [get]
+func (h *Handler) FetchCuisineTaxonomy(c *gin.Context) {
+	h.Logger.Info("FetchCuisineTaxonomy method is starting")
+
+	res := models.Cuisines{Cuisines: h.Storage.Cuisines.List()}
+	slices.SortFunc(res.Cuisines, func(a, b models.Cuisine) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+
+	h.Logger.Info("FetchCuisineTaxonomy method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// CreateCuisine godoc
+// @Summary Creates a cuisine type
+// @Description Adds a cuisine type to the taxonomy
+// @Tags cuisine
+// @Security ApiKeyAuth
+// @Param cuisine body models.NewCuisineWithCode true "Cuisine info"
+// @Success 200 {object} models.Cuisine
+// @Failure 400 {object} string "Invalid cuisine data"
+// @Failure 409 {object} string "Code, name or alias already taken"
+// @Router /admin/cuisines [post]
+func (h *Handler) CreateCuisine(c *gin.Context) {
+	h.Logger.Info("CreateCuisine method is starting")
+
+	var data models.NewCuisineWithCode
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid cuisine data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if !cuisineCode.MatchString(data.Code) {
+		er := "invalid cuisine data: code must be up to 32 lowercase letters, digits or underscores"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.Storage.Cuisines.Get(data.Code); ok {
+		er := errors.Errorf("cuisine %s already exists", data.Code).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	cu, ok := h.saveCuisine(c, data.Code, data.NewCuisine, "")
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("CreateCuisine method has finished successfully")
+	h.render(c, http.StatusOK, cu)
+}
+
+// UpdateCuisine godoc
+// @Summary Updates a cuisine type
+// @Description Replaces the name, translations and aliases of a cuisine type. Kitchens keep the cuisine name they were saved with
+// @Tags cuisine
+// @Security ApiKeyAuth
+// @Param code path string true "Cuisine code"
+// @Param cuisine body models.NewCuisine true "Cuisine info"
+// @Success 200 {object} models.Cuisine
+// @Failure 400 {object} string "Invalid cuisine data"
+// @Failure 404 {object} string "Cuisine not found"
+// @Failure 409 {object} string "Name or alias already taken"
+// @Router /admin/cuisines/{code} [put]
+func (h *Handler) UpdateCuisine(c *gin.Context) {
+	h.Logger.Info("UpdateCuisine method is starting")
+
+	cur, ok := h.Storage.Cuisines.Get(c.Param("code"))
+	if !ok {
+		er := "cuisine not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var data models.NewCuisine
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid cuisine data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	cu, ok := h.saveCuisine(c, cur.Code, data, cur.CreatedAt)
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("UpdateCuisine method has finished successfully")
+	h.render(c, http.StatusOK, cu)
+}
+
+// DeleteCuisine godoc
+// @Summary Deletes a cuisine type
+// @Description Removes a cuisine type from the taxonomy
+// @Tags cuisine
+// @Security ApiKeyAuth
+// @Param code path string true "Cuisine code"
+// @Success 200 {object} string
+// @Failure 404 {object} string "Cuisine not found"
+// @Router /admin/cuisines/{code} [delete]
+func (h *Handler) DeleteCuisine(c *gin.Context) {
+	h.Logger.Info("DeleteCuisine method is starting")
+
+	if !h.Storage.Cuisines.Delete(c.Param("code")) {
+		er := "cuisine not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("DeleteCuisine method has finished successfully")
+	h.render(c, http.StatusOK, "Cuisine deleted successfully")
+}
+
+// saveCuisine validates the cuisine and stores it under code. Every
+// spelling must name one cuisine only, so it refuses ones another
+// cuisine already uses.
+func (h *Handler) saveCuisine(c *gin.Context, code string, data models.NewCuisine, createdAt string) (models.Cuisine, bool) {
+	data.Name = strings.TrimSpace(data.Name)
+	if data.Name == "" {
+		er := "invalid cuisine data: name is required"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Cuisine{}, false
+	}
+
+	spellings := []string{data.Name}
+	for lang, name := range data.Names {
+		data.Names[lang] = strings.TrimSpace(name)
+		spellings = append(spellings, data.Names[lang])
+	}
+	for i, a := range data.Aliases {
+		data.Aliases[i] = strings.TrimSpace(a)
+		spellings = append(spellings, data.Aliases[i])
+	}
+
+	for _, other := range h.Storage.Cuisines.List() {
+		if other.Code == code {
+			continue
+		}
+		for _, s := range spellings {
+			if other.Matches(s) {
+				er := errors.Errorf("%q already names cuisine %s", s, other.Code).Error()
+				c.AbortWithStatusJSON(http.StatusConflict,
+					gin.H{"error": er})
+				h.Logger.Error(er)
+				return models.Cuisine{}, false
+			}
+		}
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	cu := models.Cuisine{
+		Code:       code,
+		NewCuisine: data,
+		CreatedAt:  cmp.Or(createdAt, now),
+		UpdatedAt:  now,
+	}
+	h.Storage.Cuisines.Set(code, cu)
+	return cu, true
+}
+
+// canonicalCuisine returns the canonical name of the cuisine type s
+// names. While the taxonomy is empty any cuisine type is accepted.
+func (h *Handler) canonicalCuisine(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+
+	cuisines := h.Storage.Cuisines.List()
+	for _, cu := range cuisines {
+		if cu.Matches(s) {
+			return cu.Name, nil
+		}
+	}
+	if len(cuisines) == 0 {
+		return s, nil
+	}
+	return "", errors.Errorf("unknown cuisine type %q", s)
+}
+
+// requestLang picks the language of the lang query, or else the locale
+// of the request.
+func requestLang(c *gin.Context) string {
+	lang, _, _ := strings.Cut(strings.TrimSpace(c.Query("lang")), "-")
+	if lang == "" {
+		return cmp.Or(c.GetString(middleware.LocaleKey), models.LocaleEnglish)
+	}
+	return strings.ToLower(lang)
+}
+
+func seedCuisines(s *storage.Storage) {
+	now := time.Now().Format(time.RFC3339)
+	for _, cu := range models.DefaultCuisines {
+		s.Cuisines.Set(cu.Code, models.Cuisine{
+			Code:       cu.Code,
+			NewCuisine: cu.NewCuisine,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+}
@@ -0,0 +1,487 @@
+package handler
+
+import (
+	pbo "api-gateway/genproto/order"
+	pbp "api-gateway/genproto/payment"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/dataexport"
+	"api-gateway/pkg/redact"
+	"api-gateway/storage"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	dataRequestTimeout = 60 * time.Second
+	maxDataReference   = 100
+	maxDataReason      = 1000
+)
+
+// CreateDataRequest godoc
+// @Summary Compiles a user's data package
+// @Description Compiles everything kept about the user for a subject access request or a legal order: the profile, orders and payments from the services and every record the gateway keeps. The package is a zip archive of JSON files, encrypted with AES-256-GCM; the key is in this response only. The manifest lists every section, with the errors of those that could not be read in full. Orders, reviews, order notes and payments are found through the user's activity feed, which keeps the latest 500 items since the gateway started, so those sections are marked partial and the package is not complete. Compiling, downloading and deleting the package are audited
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param request body models.NewDataRequest true "Data request"
+// @Success 200 {object} models.SealedDataRequest
+// @Failure 400 {object} string "Invalid data request"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/data-requests [post]
+func (h *Handler) CreateDataRequest(c *gin.Context) {
+	h.Logger.Info("CreateDataRequest method is starting")
+
+	actorID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewDataRequest
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateDataRequest(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid data request").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, dataRequestTimeout)
+	defer cancel()
+
+	now := time.Now()
+	req := models.DataRequest{
+		Id:          uuid.NewString(),
+		UserId:      data.UserId,
+		Kind:        data.Kind,
+		Reference:   data.Reference,
+		Reason:      data.Reason,
+		RequestedBy: actorID,
+		Complete:    true,
+		CreatedAt:   now.Format(time.RFC3339),
+		ExpiresAt:   now.Add(h.DataRequestTTL).Format(time.RFC3339),
+	}
+	files, sections := h.compileUserData(ctx, data.UserId)
+	req.Sections = sections
+	for _, s := range sections {
+		req.Complete = req.Complete && s.Error == "" && s.Partial == ""
+	}
+	manifest := dataexport.File{Name: "manifest.json", Data: req}
+	sealed, err := h.DataExports.Seal(req.Id, append([]dataexport.File{manifest}, files...))
+	if err != nil {
+		er := errors.Wrap(err, "error sealing data package").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	req.Size, req.Sha256 = sealed.Size, sealed.SHA256
+
+	h.Storage.DataRequests.Set(req.Id, req)
+	h.auditDataAccess(req, models.DataAccessCompiled, actorID)
+
+	h.Logger.Info("CreateDataRequest method has finished successfully")
+	h.render(c, http.StatusOK, models.SealedDataRequest{DataRequest: req, Key: sealed.Key})
+}
+
+// FetchDataRequests godoc
+// @Summary Gets data requests
+// @Description Lists the compiled data packages, latest first
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param user_id query string false "Only the packages of this user"
+// @Success 200 {object} models.DataRequests
+// @Router /admin/data-requests [get]
+func (h *Handler) FetchDataRequests(c *gin.Context) {
+	h.Logger.Info("FetchDataRequests method is starting")
+
+	userID := c.Query("user_id")
+	res := models.DataRequests{Requests: mine(h.Storage.DataRequests, func(r models.DataRequest) bool {
+		return userID == "" || r.UserId == userID
+	})}
+	slices.SortFunc(res.Requests, func(a, b models.DataRequest) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchDataRequests method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetDataRequest godoc
+// @Summary Gets a data request
+// @Description Gives a compiled data package's sections and its audit trail
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Data request ID"
+// @Success 200 {object} models.DataRequestDetails
+// @Failure 400 {object} string "Invalid data request ID"
+// @Failure 404 {object} string "Data request not found"
+// @Router /admin/data-requests/{id} [get]
+func (h *Handler) GetDataRequest(c *gin.Context) {
+	h.Logger.Info("GetDataRequest method is starting")
+
+	req, ok := h.dataRequest(c)
+	if !ok {
+		return
+	}
+	res := models.DataRequestDetails{
+		DataRequest: req,
+		Audit: mine(h.Storage.DataAccessLog, func(e models.DataAccessEntry) bool {
+			return e.RequestId == req.Id
+		}),
+	}
+	slices.SortFunc(res.Audit, func(a, b models.DataAccessEntry) int {
+		return strings.Compare(a.At, b.At)
+	})
+
+	h.Logger.Info("GetDataRequest method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// DownloadDataRequest godoc
+// @Summary Downloads a data package
+// @Description Downloads the encrypted package of a data request until it expires or is deleted
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Data request ID"
+// @Success 200 {file} file
+// @Failure 400 {object} string "Invalid data request ID"
+// @Failure 404 {object} string "Data request not found"
+// @Failure 410 {object} string "Package expired or deleted"
+// @Router /admin/data-requests/{id}/package [get]
+func (h *Handler) DownloadDataRequest(c *gin.Context) {
+	h.Logger.Info("DownloadDataRequest method is starting")
+
+	actorID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+	req, ok := h.dataRequest(c)
+	if !ok {
+		return
+	}
+
+	expired := !parseTime(req.ExpiresAt).After(time.Now())
+	if expired && req.DeletedAt == "" {
+		h.deleteDataPackage(req, actorID)
+	}
+	_, err := os.Stat(h.DataExports.Path(req.Id))
+	if expired || req.DeletedAt != "" || err != nil {
+		er := "data package expired or deleted"
+		c.AbortWithStatusJSON(http.StatusGone,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	h.auditDataAccess(req, models.DataAccessDownloaded, actorID)
+
+	h.Logger.Info("DownloadDataRequest method has finished successfully")
+	c.FileAttachment(h.DataExports.Path(req.Id),
+		fmt.Sprintf("data-%s-%s.zip.enc", req.UserId, req.CreatedAt[:len("2006-01-02")]))
+}
+
+// DeleteDataRequest godoc
+// @Summary Deletes a data package
+// @Description Deletes the encrypted package of a data request once it was handed over. The request and its audit trail are kept
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Data request ID"
+// @Success 200 {object} models.DataRequest
+// @Failure 400 {object} string "Invalid data request ID"
+// @Failure 404 {object} string "Data request not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/data-requests/{id} [delete]
+func (h *Handler) DeleteDataRequest(c *gin.Context) {
+	h.Logger.Info("DeleteDataRequest method is starting")
+
+	actorID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+	req, ok := h.dataRequest(c)
+	if !ok {
+		return
+	}
+	if req.DeletedAt == "" {
+		var err error
+		if req, err = h.deleteDataPackage(req, actorID); err != nil {
+			er := errors.Wrap(err, "error deleting data package").Error()
+			c.AbortWithStatusJSON(http.StatusInternalServerError,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	h.Logger.Info("DeleteDataRequest method has finished successfully")
+	h.render(c, http.StatusOK, req)
+}
+
+func validateDataRequest(data *models.NewDataRequest) error {
+	if _, err := uuid.Parse(data.UserId); err != nil {
+		return errors.Wrap(err, "invalid user id")
+	}
+	data.Reference = strings.TrimSpace(data.Reference)
+	data.Reason = strings.TrimSpace(data.Reason)
+	switch {
+	case data.Kind != models.DataRequestSubject && data.Kind != models.DataRequestLegal:
+		return errors.Errorf("kind must be %s or %s", models.DataRequestSubject, models.DataRequestLegal)
+	case data.Reference == "":
+		return errors.New("reference is required")
+	case utf8.RuneCountInString(data.Reference) > maxDataReference:
+		return errors.Errorf("reference must be at most %d characters", maxDataReference)
+	case utf8.RuneCountInString(data.Reason) > maxDataReason:
+		return errors.Errorf("reason must be at most %d characters", maxDataReason)
+	}
+	return nil
+}
+
+// dataRequest returns the data request of the path.
+func (h *Handler) dataRequest(c *gin.Context) (models.DataRequest, bool) {
+	id, err := pathUUID(c, "id", "data request id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.DataRequest{}, false
+	}
+	req, ok := h.Storage.DataRequests.Get(id)
+	if !ok {
+		er := "data request not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+	}
+	return req, ok
+}
+
+func (h *Handler) deleteDataPackage(req models.DataRequest, actorID string) (models.DataRequest, error) {
+	if err := h.DataExports.Remove(req.Id); err != nil {
+		return req, err
+	}
+	req = h.Storage.DataRequests.Update(req.Id, func(r models.DataRequest, _ bool) models.DataRequest {
+		r.DeletedAt = time.Now().Format(time.RFC3339)
+		return r
+	})
+	h.auditDataAccess(req, models.DataAccessDeleted, actorID)
+	return req, nil
+}
+
+func (h *Handler) auditDataAccess(req models.DataRequest, action, actorID string) {
+	e := models.DataAccessEntry{
+		Id:        uuid.NewString(),
+		RequestId: req.Id,
+		UserId:    req.UserId,
+		Action:    action,
+		ActorId:   actorID,
+		Reference: req.Reference,
+		At:        time.Now().Format(time.RFC3339Nano),
+	}
+	h.Storage.DataAccessLog.Set(e.Id, e)
+	h.Logger.Info("data access", "request_id", req.Id, "user_id", req.UserId,
+		"action", action, "actor_id", actorID, "reference", req.Reference)
+}
+
+// compileUserData reads everything kept about the user, from the
+// services and the gateway, as the files of a data package and the
+// sections they make up.
+func (h *Handler) compileUserData(ctx context.Context, userID string) ([]dataexport.File, []models.DataSection) {
+	var (
+		files    []dataexport.File
+		sections []models.DataSection
+	)
+	add := func(name string, data any, records int, err error) {
+		s := models.DataSection{Name: name, Records: records}
+		if err != nil {
+			s.Error = err.Error()
+		}
+		sections = append(sections, s)
+		files = append(files, dataexport.File{Name: name + ".json", Data: data})
+	}
+	one := func(ok bool) int {
+		if ok {
+			return 1
+		}
+		return 0
+	}
+	s := h.Storage
+
+	profile, perr := h.UserClient.GetProfile(ctx, &pbu.ID{Id: userID})
+	if perr != nil {
+		perr = errors.Wrap(perr, "error getting profile")
+	}
+	add("profile", profile, one(perr == nil), perr)
+
+	activity, _ := s.Activity.Get(userID)
+	add("activity", activity, len(activity), nil)
+	var orderIDs []string
+	var reviews []*models.FeedReview
+	for _, it := range activity {
+		switch {
+		case it.Type == models.FeedOrderPlaced && it.Order != nil:
+			orderIDs = append(orderIDs, it.Order.Id)
+		case it.Type == models.FeedReviewWritten && it.Review != nil:
+			reviews = append(reviews, it.Review)
+		}
+	}
+	add("reviews", reviews, len(reviews), nil)
+
+	orders, oerr := h.userOrders(ctx, orderIDs)
+	add("orders", orders, len(orders), oerr)
+	notes := make(map[string]any)
+	var payments []models.Payment
+	for _, id := range orderIDs {
+		if n, ok := s.OrderNotes.Get(id); ok {
+			notes[id+"/notes"] = n
+		}
+		if d, ok := s.OrderDelivery.Get(id); ok {
+			notes[id+"/delivery_preferences"] = d
+		}
+		if pid, ok := s.OrderPayments.Get(id); ok {
+			if p, ok := s.Payments.Get(pid); ok {
+				payments = append(payments, p)
+			}
+		}
+	}
+	add("order_notes", notes, len(notes), nil)
+	add("payments", payments, len(payments), nil)
+	details, derr := h.paymentDetails(ctx, payments)
+	add("payment_details", details, len(details), derr)
+
+	search, _ := s.SearchHistory.Get(userID)
+	add("search_history", search, len(search), nil)
+	saved := mine(s.SavedSearches, func(v models.SavedSearch) bool { return v.UserId == userID })
+	add("saved_searches", saved, len(saved), nil)
+	allergies, ok := s.AllergyProfiles.Get(userID)
+	add("allergy_profile", allergies, one(ok), nil)
+	notify, ok := s.NotifySettings.Get(userID)
+	add("notification_settings", notify, one(ok), nil)
+	blocked, _ := s.BlockedKitchens.Get(userID)
+	add("blocked_kitchens", blocked, len(blocked), nil)
+	plans := mine(s.MealPlans, func(v models.MealPlan) bool { return v.UserId == userID })
+	add("meal_plans", plans, len(plans), nil)
+	alerts := mine(s.DishAlerts, func(v models.DishAlert) bool { return v.UserId == userID })
+	add("dish_alerts", alerts, len(alerts), nil)
+	groups := mine(s.GroupOrders, func(v models.GroupOrder) bool {
+		return v.HostId == userID || slices.Contains(v.Participants, userID)
+	})
+	add("group_orders", groups, len(groups), nil)
+	tickets := mine(s.Tickets, func(v models.Ticket) bool { return v.UserId == userID })
+	add("tickets", tickets, len(tickets), nil)
+	refunds := mine(s.RefundRequests, func(v models.RefundRequest) bool { return v.UserId == userID })
+	add("refund_requests", refunds, len(refunds), nil)
+	chargebacks := mine(s.Chargebacks, func(v models.Chargeback) bool { return v.UserId == userID })
+	add("chargebacks", chargebacks, len(chargebacks), nil)
+	abuse, ok := s.AbuseProfiles.Get(userID)
+	add("abuse_profile", abuse, one(ok), nil)
+	fraud, ok := s.FraudHistory.Get(userID)
+	add("fraud_history", fraud, one(ok), nil)
+	cases := mine(s.FraudCases, func(v models.FraudCase) bool { return v.UserId == userID })
+	add("fraud_cases", cases, len(cases), nil)
+
+	// Emails and SMS are kept by address, which only the profile gives.
+	cerr := errors.Wrap(perr, "needs the profile")
+	var emails []models.Email
+	var sms []models.SMSMessage
+	if perr == nil {
+		emails = mine(s.Emails, func(v models.Email) bool {
+			return profile.Email != "" && strings.EqualFold(v.To, profile.Email)
+		})
+		sms = mine(s.SMSMessages, func(v models.SMSMessage) bool {
+			return profile.PhoneNumber != "" && v.To == profile.PhoneNumber
+		})
+	}
+	add("emails", emails, len(emails), cerr)
+	add("sms_messages", sms, len(sms), cerr)
+
+	// The services can't list another user's orders and reviews, so they
+	// are found through the activity feed, which is capped and starts
+	// empty when the gateway does.
+	fromFeed := []string{"reviews", "orders", "order_notes", "payments", "payment_details"}
+	for i := range sections {
+		if slices.Contains(fromFeed, sections[i].Name) {
+			sections[i].Partial = fmt.Sprintf(
+				"found through the activity feed, which keeps the latest %d items since the gateway started", models.MaxFeedItems)
+		}
+	}
+
+	return files, sections
+}
+
+// userOrders reads the orders from the order service. Orders that can't
+// be read are left out and reported in the error.
+func (h *Handler) userOrders(ctx context.Context, ids []string) ([]*pbo.OrderInfo, error) {
+	orders := make([]*pbo.OrderInfo, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			orders[i], errs[i] = h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: id})
+			if errs[i] != nil {
+				errs[i] = errors.Wrapf(errs[i], "error getting order %s", id)
+			}
+		}()
+	}
+	wg.Wait()
+	return slices.DeleteFunc(orders, func(o *pbo.OrderInfo) bool { return o == nil }), firstError(errs)
+}
+
+// paymentDetails reads the payments the payment service keeps, with
+// their card numbers masked and without CVVs, which are never handed out.
+func (h *Handler) paymentDetails(ctx context.Context, payments []models.Payment) ([]*pbp.PaymentDetails, error) {
+	var res []*pbp.PaymentDetails
+	var errs []error
+	for _, p := range payments {
+		if p.Provider != models.ProviderInternal {
+			continue
+		}
+		d, err := h.PaymentClient.GetPayment(ctx, &pbp.ID{Id: p.Id})
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting payment %s", p.Id))
+			continue
+		}
+		d.CardNumber, d.Cvv = redact.Card(d.CardNumber), ""
+		res = append(res, d)
+	}
+	return res, firstError(errs)
+}
+
+// firstError returns the first of errs, noting how many others failed.
+func firstError(errs []error) error {
+	errs = slices.DeleteFunc(errs, func(err error) bool { return err == nil })
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return errors.Errorf("%v, and %d more", errs[0], len(errs)-1)
+}
+
+// mine returns the records of store that of selects.
+func mine[T any](store *storage.Store[T], of func(T) bool) []T {
+	res := []T{}
+	for _, v := range store.List() {
+		if of(v) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	pbo "api-gateway/genproto/order"
+	"api-gateway/models"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const maxIntercomCode = 16
+
+// closedOrderStatuses are the statuses after which an order's delivery
+// can no longer change.
+var closedOrderStatuses = []string{"delivered", "completed", "cancelled", "canceled", "rejected"}
+
+// GetOrderDelivery godoc
+// @Summary Gets an order's delivery details
+// @Description Tells where, when and how to hand the order over: address, time, delivery preferences and notes. For the customer, the kitchen's owner and staff with the orders permission, and admins
+// @Tags order
+// @Security ApiKeyAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} models.OrderDelivery
+// @Failure 400 {object} string "Invalid order ID"
+// @Failure 403 {object} string "Not the order's customer or kitchen"
+// @Router /orders/{id}/delivery [get]
+func (h *Handler) GetOrderDelivery(c *gin.Context) {
+	h.Logger.Info("GetOrderDelivery method is starting")
+
+	order, ok := h.deliveryOrder(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.accessRole(c, order.UserId, order.KitchenId, models.StaffOrders); !ok {
+		return
+	}
+
+	res := models.OrderDelivery{
+		OrderId:         order.Id,
+		Status:          order.Status,
+		DeliveryAddress: order.DeliveryAddress,
+		DeliveryTime:    order.DeliveryTime,
+	}
+	res.Preferences, _ = h.Storage.OrderDelivery.Get(order.Id)
+	if notes, ok := h.Storage.OrderNotes.Get(order.Id); ok {
+		res.Notes = &notes
+	}
+
+	h.Logger.Info("GetOrderDelivery method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// SetDeliveryPreferences godoc
+// @Summary Sets an order's delivery preferences
+// @Description Replaces how the customer wants the order handed over, such as leaving it at the door or the intercom code, until it is delivered. For the customer and admins
+// @Tags order
+// @Security ApiKeyAuth
+// @Param id path string true "Order ID"
+// @Param preferences body models.DeliveryPreferences true "Delivery preferences"
+// @Success 200 {object} models.DeliveryPreferences
+// @Failure 400 {object} string "Invalid order ID or preferences"
+// @Failure 403 {object} string "Not the order's customer"
+// @Failure 409 {object} string "Order already delivered or canceled"
+// @Router /orders/{id}/delivery-preferences [put]
+func (h *Handler) SetDeliveryPreferences(c *gin.Context) {
+	h.Logger.Info("SetDeliveryPreferences method is starting")
+
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	order, ok := h.deliveryOrder(c)
+	if !ok {
+		return
+	}
+	if order.UserId != userID && role != models.RoleAdmin {
+		er := "order belongs to another user"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if slices.Contains(closedOrderStatuses, strings.ToLower(order.Status)) {
+		er := errors.Errorf("order is %s", order.Status).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var data models.DeliveryPreferences
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateDeliveryPreferences(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid delivery preferences").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	h.Storage.OrderDelivery.Set(order.Id, data)
+
+	h.Logger.Info("SetDeliveryPreferences method has finished successfully")
+	h.render(c, http.StatusOK, data)
+}
+
+func (h *Handler) deliveryOrder(c *gin.Context) (*pbo.OrderInfo, bool) {
+	id, err := pathUUID(c, "id", "order id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return nil, false
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	order, err := h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: id})
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting order").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return nil, false
+	}
+	return order, true
+}
+
+// validateDeliveryPreferences trims the intercom code and checks it is
+// something a keypad can take.
+func validateDeliveryPreferences(p *models.DeliveryPreferences) error {
+	p.IntercomCode = strings.TrimSpace(p.IntercomCode)
+	if len(p.IntercomCode) > maxIntercomCode {
+		return errors.Errorf("intercom_code must have at most %d characters", maxIntercomCode)
+	}
+	for _, r := range p.IntercomCode {
+		if !(r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || strings.ContainsRune("#*-+ ", r)) {
+			return errors.New("intercom_code may only have digits, latin letters, spaces and # * - +")
+		}
+	}
+	return nil
+}
+
+func (h *Handler) addDelivery(order map[string]any) {
+	id, ok := order["id"].(string)
+	if !ok {
+		return
+	}
+	if p, ok := h.Storage.OrderDelivery.Get(id); ok {
+		order["delivery_preferences"] = p
+	}
+}
@@ -0,0 +1,335 @@
+package handler
+
+import (
+	pbd "api-gateway/genproto/dish"
+	"api-gateway/models"
+	"api-gateway/pkg/mealplan"
+	"api-gateway/pkg/pricing"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxDishDiscounts bounds the discounts of a dish.
+const maxDishDiscounts = 10
+
+// CreateDishDiscount godoc
+// @Summary Adds a dish discount
+// @Description Takes a percent off the dish's price between starts_at and ends_at, on the given days and from from until until each day, such as a happy hour. When several discounts are active the largest applies. For the kitchen's owner and staff with the menu permission
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Param discount body models.NewDishDiscount true "Discount"
+// @Success 200 {object} models.DishDiscount
+// @Failure 400 {object} string "Invalid dish ID or discount data"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Failure 409 {object} string "Too many discounts"
+// @Router /dishes/{id}/discounts [post]
+func (h *Handler) CreateDishDiscount(c *gin.Context) {
+	h.Logger.Info("CreateDishDiscount method is starting")
+
+	dish, ok := h.ownDish(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewDishDiscount
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateDishDiscount(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid discount data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if len(h.dishDiscounts(dish.Id)) >= maxDishDiscounts {
+		er := errors.Errorf("a dish may have at most %d discounts", maxDishDiscounts).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now()
+	d := models.DishDiscount{
+		Id:              uuid.NewString(),
+		DishId:          dish.Id,
+		KitchenId:       dish.KitchenId,
+		NewDishDiscount: data,
+		CreatedAt:       now.Format(time.RFC3339),
+		UpdatedAt:       now.Format(time.RFC3339),
+	}
+	h.Storage.DishDiscounts.Set(d.Id, d)
+	h.purgeDishes(d.DishId)
+	d.Active = pricing.DiscountActive(d.NewDishDiscount, now.In(h.TimeZones.Of(d.KitchenId)))
+
+	h.Logger.Info("CreateDishDiscount method has finished successfully")
+	h.render(c, http.StatusOK, d)
+}
+
+// FetchDishDiscounts godoc
+// @Summary Gets dish discounts
+// @Description Lists the dish's discounts and whether each is active now. For the kitchen's owner and staff with the menu permission; customers see the discounted price in the dish
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Success 200 {object} models.DishDiscounts
+// @Failure 400 {object} string "Invalid dish ID"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Router /dishes/{id}/discounts [get]
+func (h *Handler) FetchDishDiscounts(c *gin.Context) {
+	h.Logger.Info("FetchDishDiscounts method is starting")
+
+	dish, ok := h.ownDish(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now().In(h.TimeZones.Of(dish.KitchenId))
+	res := models.DishDiscounts{Discounts: h.dishDiscounts(dish.Id)}
+	for i := range res.Discounts {
+		res.Discounts[i].Active = pricing.DiscountActive(res.Discounts[i].NewDishDiscount, now)
+	}
+
+	h.Logger.Info("FetchDishDiscounts method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// UpdateDishDiscount godoc
+// @Summary Updates a dish discount
+// @Description Replaces a discount of the dish. Orders already placed keep the price they got. For the kitchen's owner and staff with the menu permission
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Param discount_id path string true "Discount ID"
+// @Param discount body models.NewDishDiscount true "Discount"
+// @Success 200 {object} models.DishDiscount
+// @Failure 400 {object} string "Invalid ID or discount data"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Failure 404 {object} string "Discount not found"
+// @Router /dishes/{id}/discounts/{discount_id} [put]
+func (h *Handler) UpdateDishDiscount(c *gin.Context) {
+	h.Logger.Info("UpdateDishDiscount method is starting")
+
+	d, ok := h.findDishDiscount(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewDishDiscount
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateDishDiscount(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid discount data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now()
+	d = h.Storage.DishDiscounts.Update(d.Id, func(d models.DishDiscount, _ bool) models.DishDiscount {
+		d.NewDishDiscount = data
+		d.UpdatedAt = now.Format(time.RFC3339)
+		return d
+	})
+	h.purgeDishes(d.DishId)
+	d.Active = pricing.DiscountActive(d.NewDishDiscount, now.In(h.TimeZones.Of(d.KitchenId)))
+
+	h.Logger.Info("UpdateDishDiscount method has finished successfully")
+	h.render(c, http.StatusOK, d)
+}
+
+// DeleteDishDiscount godoc
+// @Summary Deletes a dish discount
+// @Description Ends a discount of the dish. For the kitchen's owner and staff with the menu permission
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Param discount_id path string true "Discount ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid ID"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Failure 404 {object} string "Discount not found"
+// @Router /dishes/{id}/discounts/{discount_id} [delete]
+func (h *Handler) DeleteDishDiscount(c *gin.Context) {
+	h.Logger.Info("DeleteDishDiscount method is starting")
+
+	d, ok := h.findDishDiscount(c)
+	if !ok {
+		return
+	}
+	h.Storage.DishDiscounts.Delete(d.Id)
+	h.purgeDishes(d.DishId)
+
+	h.Logger.Info("DeleteDishDiscount method has finished successfully")
+	h.render(c, http.StatusOK, "Discount deleted successfully")
+}
+
+// findDishDiscount returns the discount of the path's dish once the
+// caller may manage it.
+func (h *Handler) findDishDiscount(c *gin.Context) (models.DishDiscount, bool) {
+	id, err := pathUUID(c, "discount_id", "discount id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.DishDiscount{}, false
+	}
+
+	dish, ok := h.ownDish(c)
+	if !ok {
+		return models.DishDiscount{}, false
+	}
+
+	d, ok := h.Storage.DishDiscounts.Get(id)
+	if !ok || d.DishId != dish.Id {
+		er := "discount not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.DishDiscount{}, false
+	}
+	return d, true
+}
+
+// validateDishDiscount checks the discount, normalizing its times and
+// dropping duplicate days.
+func validateDishDiscount(data *models.NewDishDiscount) error {
+	if data.Percent <= 0 || data.Percent >= 100 {
+		return errors.New("percent must be between 0 and 100")
+	}
+
+	var start time.Time
+	if data.StartsAt != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, data.StartsAt); err != nil {
+			return errors.Wrap(err, "invalid starts_at")
+		}
+		data.StartsAt = start.Format(time.RFC3339)
+	}
+	if data.EndsAt != "" {
+		end, err := time.Parse(time.RFC3339, data.EndsAt)
+		if err != nil {
+			return errors.Wrap(err, "invalid ends_at")
+		}
+		if !end.After(start) || !end.After(time.Now()) {
+			return errors.New("ends_at must be after starts_at and in the future")
+		}
+		data.EndsAt = end.Format(time.RFC3339)
+	}
+
+	var days []string
+	for _, d := range data.Days {
+		day, ok := mealplan.Weekday(d)
+		if !ok {
+			return errors.Errorf("unknown weekday %q", d)
+		}
+		if name := strings.ToLower(day.String()); !slices.Contains(days, name) {
+			days = append(days, name)
+		}
+	}
+	data.Days = days
+
+	if (data.From == "") != (data.Until == "") {
+		return errors.New("from and until must be given together")
+	}
+	if data.From != "" {
+		from, err := time.Parse("15:04", data.From)
+		if err != nil {
+			return errors.Errorf("invalid from %q, HH:MM expected", data.From)
+		}
+		until, err := time.Parse("15:04", data.Until)
+		if err != nil {
+			return errors.Errorf("invalid until %q, HH:MM expected", data.Until)
+		}
+		if from.Equal(until) {
+			return errors.New("from and until must differ")
+		}
+		data.From, data.Until = from.Format("15:04"), until.Format("15:04")
+	}
+	return nil
+}
+
+// dishDiscounts returns the discounts of the dish, oldest first.
+func (h *Handler) dishDiscounts(dishID string) []models.DishDiscount {
+	var res []models.DishDiscount
+	for _, d := range h.Storage.DishDiscounts.List() {
+		if d.DishId == dishID {
+			res = append(res, d)
+		}
+	}
+	slices.SortFunc(res, func(a, b models.DishDiscount) int {
+		return strings.Compare(a.CreatedAt, b.CreatedAt)
+	})
+	return res
+}
+
+// bestDiscount returns the dish's active discount with the largest
+// percent. Their days and hours are in the time zone of the dish's
+// kitchen.
+func (h *Handler) bestDiscount(dishID string, now time.Time) *models.DishDiscount {
+	discounts := h.dishDiscounts(dishID)
+	if len(discounts) == 0 {
+		return nil
+	}
+	return pricing.BestDiscount(discounts, now.In(h.TimeZones.Of(discounts[0].KitchenId)))
+}
+
+// renderDishes renders a dish service response, translated into the
+// request's locale, with the discounted price added to the dish, or to
+// every dish of a list, while a discount is active.
+func (h *Handler) renderDishes(c *gin.Context, res proto.Message) {
+	h.localize(c, res)
+	data, err := h.encode(c, res)
+	if err != nil {
+		h.write(c, http.StatusOK, res, data, err)
+		return
+	}
+
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		h.write(c, http.StatusOK, res, data, err)
+		return
+	}
+
+	now := time.Now()
+	switch res := res.(type) {
+	case *pbd.DishInfo:
+		h.addDiscount(v, res.Id, res.Price, now)
+	case *pbd.Dishes:
+		list, _ := v["dishes"].([]any)
+		for i, d := range res.Dishes[:min(len(res.Dishes), len(list))] {
+			if k, ok := list[i].(map[string]any); ok {
+				h.addDiscount(k, d.Id, d.Price, now)
+			}
+		}
+	}
+
+	data, err = json.Marshal(v)
+	h.write(c, http.StatusOK, res, data, err)
+}
+
+func (h *Handler) addDiscount(dish map[string]any, id string, price float32, now time.Time) {
+	if d := h.bestDiscount(id, now); d != nil {
+		dish["discount_percent"] = d.Percent
+		dish["discounted_price"] = pricing.Discounted(price, d.Percent)
+		if d.EndsAt != "" {
+			dish["discount_ends_at"] = d.EndsAt
+		}
+	}
+}
@@ -2,96 +2,77 @@ package handler
 
 import (
 	pb "api-gateway/genproto/dish"
-	"context"
-	"net/http"
-	"strconv"
-	"time"
+	"api-gateway/models"
+	"api-gateway/pkg/cdn"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
 
 // CreateDish godoc
 // @Summary Creates a dish
-// @Description Inserts a new dish into database
+// @Description Inserts a new dish into database. Allergens are required, as tags from GET /allergens; an empty list declares a dish without any
 // @Tags dish
 // @Security ApiKeyAuth
-// @Param dish body dish.NewDish true "Dish info"
+// @Param dish body models.NewDish true "Dish info"
 // @Success 200 {object} dish.NewDishResp
-// @Failure 400 {object} string "Invalid dish data"
+// @Failure 400 {object} string "Invalid dish data or allergens"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /dishes [post]
 func (h *Handler) CreateDish(c *gin.Context) {
-	h.Logger.Info("CreateDish method is starting")
-
-	var data pb.NewDish
-	if err := c.ShouldBindJSON(&data); err != nil {
-		er := errors.Wrap(err, "invalid dish data").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.DishClient.Add(ctx, &data)
-	if err != nil {
-		er := errors.Wrap(err, "error creating dish").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("CreateDish method has finished successfully")
-	c.JSON(http.StatusOK, res)
+	var allergens []string
+	serve(h, c, Proxy[*pb.NewDish, *pb.NewDishResp]{
+		Name: "CreateDish",
+		Bind: func(c *gin.Context) (*pb.NewDish, error) {
+			data, err := bindJSON[models.NewDish](c, "dish data")
+			if err != nil {
+				return nil, err
+			}
+			if allergens, err = declaredAllergens(data.Allergens); err != nil {
+				return nil, errors.Wrap(err, "invalid dish data")
+			}
+			return newDish(data), nil
+		},
+		Call:  h.DishClient.Add,
+		Error: "error creating dish",
+		After: func(res *pb.NewDishResp) {
+			h.trackCatalog(models.EventDishCreated, models.CatalogDish, res.Id, res.KitchenId, res)
+			h.declareAllergens(res.Id, allergens)
+			h.purgeDishes(res.Id)
+		},
+	})
 }
 
 // GetDish godoc
 // @Summary Gets a dish
-// @Description Retrieves dish info from database
+// @Description Retrieves dish info from database. While a discount is active the dish also has discount_percent and discounted_price. The response may be cached at the edge under the surrogate key dish:{id}
 // @Tags dish
 // @Security ApiKeyAuth
 // @Param id path string true "Dish ID"
 // @Success 200 {object} dish.DishInfo
 // @Failure 400 {object} string "Invalid dish ID"
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
 // @Router /dishes/{id} [get]
 func (h *Handler) GetDish(c *gin.Context) {
-	h.Logger.Info("GetDish method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid dish ID").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.DishClient.Read(ctx, &pb.ID{Id: id})
-	if err != nil {
-		er := errors.Wrap(err, "error getting dish").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("GetDish method has finished successfully")
-	c.JSON(http.StatusOK, res)
+	serve(h, c, Proxy[*pb.ID, *pb.DishInfo]{
+		Name: "GetDish",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "dish ID")
+			return &pb.ID{Id: id}, err
+		},
+		Call:  h.DishClient.Read,
+		Error: "error getting dish",
+		Render: func(c *gin.Context, res *pb.DishInfo) {
+			h.tagEdge(c, cdn.DishKey(res.Id), cdn.KitchenKey(res.KitchenId))
+			h.renderDishes(c, res)
+		},
+	})
 }
 
 // UpdateDish godoc
 // @Summary Updates a dish
-// @Description Updates dish info in database
+// @Description Updates dish info in database. Customers waiting for the dish are notified once it is available
 // @Tags dish
 // @Security ApiKeyAuth
 // @Param id path string true "Dish ID"
@@ -101,46 +82,34 @@ func (h *Handler) GetDish(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /dishes/{id} [put]
 func (h *Handler) UpdateDish(c *gin.Context) {
-	h.Logger.Info("UpdateDish method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid dish ID").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	var data pb.NewData
-	if err := c.ShouldBindJSON(&data); err != nil {
-		er := errors.Wrap(err, "invalid dish data").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.DishClient.Update(ctx, &pb.NewData{
-		Id:        id,
-		Name:      data.Name,
-		Price:     data.Price,
-		Available: data.Available,
+	serve(h, c, Proxy[*pb.NewData, *pb.UpdatedData]{
+		Name: "UpdateDish",
+		Bind: func(c *gin.Context) (*pb.NewData, error) {
+			id, err := pathUUID(c, "id", "dish ID")
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := bindJSON[pb.NewDataNoID](c, "dish data")
+			if err != nil {
+				return nil, err
+			}
+
+			return &pb.NewData{
+				Id:        id,
+				Name:      data.Name,
+				Price:     data.Price,
+				Available: data.Available,
+			}, nil
+		},
+		Call:  h.DishClient.Update,
+		Error: "error updating dish",
+		After: func(res *pb.UpdatedData) {
+			h.trackCatalog(models.EventDishUpdated, models.CatalogDish, res.Id, res.KitchenId, res)
+			h.purgeDishes(res.Id)
+			h.notifyDishAvailable(res)
+		},
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error updating dish").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("UpdateDish method has finished successfully")
-	c.JSON(http.StatusOK, res)
 }
 
 // DeleteDish godoc
@@ -154,37 +123,26 @@ func (h *Handler) UpdateDish(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /dishes/{id} [delete]
 func (h *Handler) DeleteDish(c *gin.Context) {
-	h.Logger.Info("DeleteDish method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid dish ID").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	_, err = h.DishClient.Delete(ctx, &pb.ID{Id: id})
-	if err != nil {
-		er := errors.Wrap(err, "error deleting dish").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("DeleteDish method has finished successfully")
-	c.JSON(http.StatusOK, "Dish deleted successfully")
+	serve(h, c, Proxy[*pb.ID, *pb.Void]{
+		Name: "DeleteDish",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "dish ID")
+			return &pb.ID{Id: id}, err
+		},
+		Call:  h.DishClient.Delete,
+		Error: "error deleting dish",
+		After: func(*pb.Void) {
+			h.trackCatalog(models.EventDishDeleted, models.CatalogDish, c.Param("id"), "", nil)
+			h.Storage.DishAllergens.Delete(c.Param("id"))
+			h.purgeDishes(c.Param("id"))
+		},
+		Message: "Dish deleted successfully",
+	})
 }
 
 // FetchDishes godoc
 // @Summary Gets dishes
-// @Description Retrieves dishes info from database
+// @Description Retrieves dishes info from database. Dishes with an active discount also have discount_percent and discounted_price. The response may be cached at the edge under the surrogate key dishes
 // @Tags dish
 // @Security ApiKeyAuth
 // @Param id path string true "Kitchen ID"
@@ -192,46 +150,24 @@ func (h *Handler) DeleteDish(c *gin.Context) {
 // @Param limit query int true "Number of items per page"
 // @Success 200 {object} dish.Dishes
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
 // @Router /kitchens/{id}/dishes [get]
 func (h *Handler) FetchDishes(c *gin.Context) {
-	h.Logger.Info("FetchDishes method is starting")
-
-	page := c.Query("page")
-	limit := c.Query("limit")
-
-	p, err := strconv.Atoi(page)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	l, err := strconv.Atoi(limit)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.DishClient.Fetch(ctx, &pb.Pagination{
-		Limit:  int32(l),
-		Offset: int32((p - 1) * l),
+	serve(h, c, Proxy[*pb.Pagination, *pb.Dishes]{
+		Name: "FetchDishes",
+		Bind: func(c *gin.Context) (*pb.Pagination, error) {
+			limit, offset, err := pagination(c)
+			return &pb.Pagination{Limit: limit, Offset: offset}, err
+		},
+		Call:  h.DishClient.Fetch,
+		Error: "error getting dishes",
+		Render: func(c *gin.Context, res *pb.Dishes) {
+			keys := []string{cdn.KeyDishes}
+			for _, d := range res.Dishes {
+				keys = append(keys, cdn.DishKey(d.Id))
+			}
+			h.tagEdge(c, keys...)
+			h.renderDishes(c, res)
+		},
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error getting dishes").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("FetchDishes method has finished successfully")
-	c.JSON(http.StatusOK, res)
 }
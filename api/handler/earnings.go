@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/analytics"
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const defaultEarningsLimit = 50
+
+// GetEarnings godoc
+// @Summary Gets kitchen earnings
+// @Description Summarizes what a kitchen earned in the period by day, week or month, after the platform commission and approved refunds, with a per-order breakdown. Days are in the kitchen's time zone. For the kitchen owner, staff with the payouts permission and admins
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param start_date query string false "start date, unless period is given"
+// @Param end_date query string false "end date, unless period is given"
+// @Param period query string false "Days up to today in place of the dates" Enums(today, yesterday, last_7_days, last_30_days, last_90_days, this_month, last_month, this_year)
+// @Param group query string false "Period length" Enums(day, week, month)
+// @Param page query int false "Page of the order breakdown"
+// @Param limit query int false "Orders per page, 50 by default"
+// @Success 200 {object} models.Earnings
+// @Failure 400 {object} string "Invalid kitchen ID, date, period, group or pagination"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /kitchens/{id}/earnings [get]
+func (h *Handler) GetEarnings(c *gin.Context) {
+	h.Logger.Info("GetEarnings method is starting")
+
+	id, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// Days are the kitchen's, from its midnight.
+	start, end, err := analyticsPeriod(c, h.TimeZones.Of(id))
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	group := c.DefaultQuery("group", models.GroupDay)
+	page, err := queryInt(c, "page")
+	limit, lerr := queryInt(c, "limit")
+	if err == nil {
+		err = lerr
+	}
+	if err == nil && (page < 0 || limit < 0 ||
+		!slices.Contains([]string{models.GroupDay, models.GroupWeek, models.GroupMonth}, group)) {
+		err = errors.New("invalid group or pagination parameters")
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	page, limit = max(page, 1), cmp.Or(limit, defaultEarningsLimit)
+
+	if _, ok := h.accessRole(c, "", id, models.StaffPayouts); !ok {
+		return
+	}
+
+	ctx, cancel := callContext(c, analyticsTimeout)
+	defer cancel()
+
+	var res models.Earnings
+	fees, rate, err := h.earningsFees(ctx, id)
+	if err == nil {
+		res, err = h.Analytics.Earnings(ctx, id, start, end, group, fees)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "error computing earnings").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	res.CommissionPercent = rate
+
+	from := min((page-1)*limit, len(res.Orders))
+	res.Orders = res.Orders[from:min(from+limit, len(res.Orders))]
+
+	h.Logger.Info("GetEarnings method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// FetchPayouts godoc
+// @Summary Gets kitchen payouts
+// @Description Lists payouts made to a kitchen, newest first, with the balance of earnings not paid out yet. For the kitchen owner, staff with the payouts permission and admins
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.Payouts
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /kitchens/{id}/payouts [get]
+func (h *Handler) FetchPayouts(c *gin.Context) {
+	h.Logger.Info("FetchPayouts method is starting")
+
+	id, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", id, models.StaffPayouts); !ok {
+		return
+	}
+
+	ctx, cancel := callContext(c, analyticsTimeout)
+	defer cancel()
+
+	var earned float32
+	fees, _, err := h.earningsFees(ctx, id)
+	if err == nil {
+		earned, err = h.Analytics.NetEarnings(ctx, id, fees)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "error computing earnings").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res := models.Payouts{Payouts: []models.Payout{}}
+	for _, p := range h.Storage.Payouts.List() {
+		if p.KitchenId == id {
+			res.Payouts = append(res.Payouts, p)
+			res.Balance.PaidOut += p.Amount
+		}
+	}
+	slices.SortFunc(res.Payouts, func(a, b models.Payout) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+	res.Balance.Earned = earned
+	res.Balance.Available = earned - res.Balance.PaidOut
+
+	h.Logger.Info("FetchPayouts method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// CreatePayout godoc
+// @Summary Records a payout
+// @Description Records money transferred to a kitchen so that it shows in the kitchen's payouts and balance. For roles granted payouts:manage
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param payout body models.NewPayout true "Payout"
+// @Success 200 {object} models.Payout
+// @Failure 400 {object} string "Invalid kitchen ID or payout"
+// @Failure 403 {object} string "Permission payouts:manage is required"
+// @Router /kitchens/{id}/payouts [post]
+func (h *Handler) CreatePayout(c *gin.Context) {
+	h.Logger.Info("CreatePayout method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	id, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var data models.NewPayout
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid payout").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if err := validatePayout(&data); err != nil {
+		er := errors.Wrap(err, "invalid payout").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	p := models.Payout{
+		Id:        uuid.NewString(),
+		KitchenId: id,
+		Amount:    data.Amount,
+		Currency:  h.Payments.Currency(),
+		Reference: data.Reference,
+		StartDate: data.StartDate,
+		EndDate:   data.EndDate,
+		CreatedBy: userID,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	h.Storage.Payouts.Set(p.Id, p)
+
+	h.Logger.Info("CreatePayout method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
+
+// earningsFees collects what is deducted from the kitchen's sales, along
+// with the commission percent of its new orders. Orders placed before
+// their rate was recorded are charged that percent.
+func (h *Handler) earningsFees(ctx context.Context, kitchenID string) (analytics.Fees, float32, error) {
+	rate, err := h.commissionRate(ctx, kitchenID)
+	if err != nil {
+		return analytics.Fees{}, 0, err
+	}
+
+	refunds := make(map[string]float32)
+	for _, r := range h.Storage.RefundRequests.List() {
+		if r.KitchenId == kitchenID && r.Status == models.RefundApproved {
+			refunds[r.OrderId] += r.Amount
+		}
+	}
+
+	commission := func(orderID string, amount float32) float32 {
+		percent, ok := h.Storage.OrderCommissions.Get(orderID)
+		if !ok {
+			percent = rate.Percent
+		}
+		return h.Pricing.Commission(amount, percent)
+	}
+	return analytics.Fees{Commission: commission, Refunds: refunds}, rate.Percent, nil
+}
+
+func validatePayout(data *models.NewPayout) error {
+	if data.Amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+	for _, d := range []string{data.StartDate, data.EndDate} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return errors.Wrap(err, "dates must be YYYY-MM-DD")
+		}
+	}
+	if data.StartDate != "" && data.EndDate != "" && data.EndDate < data.StartDate {
+		return errors.New("period must end after it starts")
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	pbk "api-gateway/genproto/kitchen"
+	pbo "api-gateway/genproto/order"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/email"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// SendReceipt godoc
+// @Summary Emails an order receipt
+// @Description Queues the receipt of the order for the caller's email, in the language of the request. Receipts are also emailed when an order is delivered
+// @Tags order
+// @Security ApiKeyAuth
+// @Param id path string true "Order ID"
+// @Success 202 {object} models.Email
+// @Failure 400 {object} string "Invalid order ID or caller has no email"
+// @Failure 403 {object} string "Order belongs to another user"
+// @Failure 404 {object} string "Order not found"
+// @Failure 503 {object} string "Email delivery is not configured"
+// @Router /orders/{id}/receipt [post]
+func (h *Handler) SendReceipt(c *gin.Context) {
+	h.Logger.Info("SendReceipt method is starting")
+
+	orderID, err := pathUUID(c, "id", "order id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	if !h.Mailer.Enabled() {
+		er := email.ErrDisabled.Error()
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	info, err := h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: orderID})
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting order").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if info.UserId != userID {
+		er := "order belongs to another user"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	profile, err := h.UserClient.GetProfile(ctx, &pbu.ID{Id: userID})
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting user").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	if profile.Email == "" {
+		er := "user has no email"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res, err := h.Mailer.Queue(profile.Email, requestLang(c), models.EmailReceipt, h.receipt(info))
+	if err != nil {
+		er := errors.Wrap(err, "error emailing receipt").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("SendReceipt method has finished successfully")
+	h.render(c, http.StatusAccepted, res)
+}
+
+// FetchEmails godoc
+// @Summary Gets sent emails
+// @Description Lists the emails the gateway queued with their delivery status, newest first, optionally by template or status
+// @Tags email
+// @Security ApiKeyAuth
+// @Param template query string false "receipt, verification, invitation, staff_invite, dish_available or report"
+// @Param status query string false "queued, sent or failed"
+// @Success 200 {object} models.Emails
+// @Router /admin/emails [get]
+func (h *Handler) FetchEmails(c *gin.Context) {
+	h.Logger.Info("FetchEmails method is starting")
+
+	template, status := c.Query("template"), c.Query("status")
+	res := models.Emails{Emails: []models.Email{}}
+	for _, e := range h.Storage.Emails.List() {
+		if (template == "" || e.Template == template) && (status == "" || e.Status == status) {
+			res.Emails = append(res.Emails, e)
+		}
+	}
+	slices.SortFunc(res.Emails, func(a, b models.Email) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchEmails method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// emailReceipt emails the customer the receipt of their delivered order
+// unless they turned off order update emails.
+func (h *Handler) emailReceipt(ctx context.Context, info *pbo.OrderInfo) {
+	status := strings.ToLower(info.Status)
+	if status != "delivered" && status != "completed" || !h.Mailer.Enabled() ||
+		!h.notifies(info.UserId, models.NotifyOrderUpdates, models.ChannelEmail) {
+		return
+	}
+
+	profile, err := h.UserClient.GetProfile(ctx, &pbu.ID{Id: info.UserId})
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error getting customer for receipt").Error())
+		return
+	}
+	if profile.Email == "" {
+		return
+	}
+
+	if _, err := h.Mailer.Queue(profile.Email, "en", models.EmailReceipt, h.receipt(info)); err != nil {
+		h.Logger.Error(errors.Wrapf(err, "error emailing receipt of order %s", info.Id).Error())
+	}
+}
+
+func (h *Handler) receipt(info *pbo.OrderInfo) email.ReceiptData {
+	data := email.ReceiptData{
+		OrderId:     info.Id,
+		KitchenName: info.KitchenName,
+		Items:       make([]email.ReceiptItem, len(info.Items)),
+		Total:       info.TotalAmount,
+		Currency:    h.Payments.Currency(),
+		Address:     info.DeliveryAddress,
+		Date:        info.CreatedAt,
+	}
+	for i, it := range info.Items {
+		data.Items[i] = email.ReceiptItem{
+			Name:     it.Name,
+			Quantity: it.Quantity,
+			Price:    it.Price,
+			Total:    it.Price * float32(it.Quantity),
+		}
+	}
+	return data
+}
+
+// emailVerification tells the kitchen owner the verification decision.
+func (h *Handler) emailVerification(v models.KitchenVerification) {
+	if !h.Mailer.Enabled() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	k, err := h.KitchenClient.Get(ctx, &pbk.ID{Id: v.KitchenId})
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error getting kitchen for verification email").Error())
+		return
+	}
+	profile, err := h.UserClient.GetProfile(ctx, &pbu.ID{Id: k.OwnerId})
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error getting kitchen owner for verification email").Error())
+		return
+	}
+	if profile.Email == "" {
+		return
+	}
+
+	_, err = h.Mailer.Queue(profile.Email, "en", models.EmailVerification, email.VerificationData{
+		KitchenName: k.Name,
+		Verified:    v.Status == models.VerificationVerified,
+		Note:        v.Note,
+	})
+	if err != nil {
+		h.Logger.Error(errors.Wrapf(err, "error emailing verification of kitchen %s", v.KitchenId).Error())
+	}
+}
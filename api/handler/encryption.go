@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/envelope"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetEncryptionStatus godoc
+// @Summary Gets how personal data is encrypted at rest
+// @Description Tells whether the personal data the gateway keeps, such as search history, tickets, messages, order notes, undelivered events, uploads and report files, is encrypted, with which key provider and key, and when the data key was last rotated
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.EncryptionStatus
+// @Router /admin/encryption [get]
+func (h *Handler) GetEncryptionStatus(c *gin.Context) {
+	h.Logger.Info("GetEncryptionStatus method is starting")
+
+	var res models.EncryptionStatus
+	if h.Encryption != nil {
+		res = h.Encryption.Status()
+	}
+
+	h.Logger.Info("GetEncryptionStatus method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// RotateEncryptionKey godoc
+// @Summary Rotates the encryption keys
+// @Description Reloads the key encryption key, so a key appended to the key file takes over, generates a new data key and reseals everything kept with it. The old data keys are dropped once everything is resealed. Rotate after a suspected key compromise or on the key policy's schedule
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.KeyRotation
+// @Failure 409 {object} string "Encryption is off, or keys are being rotated"
+// @Failure 500 {object} string "Keys could not be rotated"
+// @Router /admin/encryption/rotate [post]
+func (h *Handler) RotateEncryptionKey(c *gin.Context) {
+	h.Logger.Info("RotateEncryptionKey method is starting")
+
+	if h.Encryption == nil {
+		er := "encryption at rest is off"
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var res models.KeyRotation
+	err := h.Encryption.Rotate(func() error {
+		var err error
+		if res.Resealed, err = h.Storage.Reseal(); err != nil {
+			return errors.Wrap(err, "error resealing storage")
+		}
+		if res.OutboxResealed, err = h.Events.Reseal(); err != nil {
+			return errors.Wrap(err, "error resealing outbox")
+		}
+		uploads, err := h.Uploads.Reseal()
+		res.FilesResealed += uploads
+		if err != nil {
+			return errors.Wrap(err, "error resealing uploads")
+		}
+		reports, err := h.Reports.Reseal()
+		res.FilesResealed += reports
+		return errors.Wrap(err, "error resealing reports")
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, envelope.ErrRotating) {
+			status = http.StatusConflict
+		}
+		er := err.Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	res.EncryptionStatus = h.Encryption.Status()
+
+	h.Logger.Info("RotateEncryptionKey method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"api-gateway/pkg/routing"
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetKitchenLocation godoc
+// @Summary Gets a kitchen's location
+// @Description Tells where the kitchen's orders are picked up, which delivery distances are measured from
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.KitchenLocation
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 404 {object} string "Location not set"
+// @Router /kitchens/{id}/location [get]
+func (h *Handler) GetKitchenLocation(c *gin.Context) {
+	h.Logger.Info("GetKitchenLocation method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	loc, ok := h.Storage.KitchenLocations.Get(kitchenID)
+	if !ok {
+		er := models.ErrNoKitchenLocation.Error()
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetKitchenLocation method has finished successfully")
+	h.render(c, http.StatusOK, loc)
+}
+
+// SetKitchenLocation godoc
+// @Summary Sets a kitchen's location
+// @Description Sets where the kitchen's orders are picked up. Orders, and quotes that give a delivery location, are priced by the road distance from it. For the kitchen's owner and admins
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param location body models.Point true "Location"
+// @Success 200 {object} models.KitchenLocation
+// @Failure 400 {object} string "Invalid kitchen ID or location"
+// @Failure 403 {object} string "Not the kitchen's owner"
+// @Router /kitchens/{id}/location [put]
+func (h *Handler) SetKitchenLocation(c *gin.Context) {
+	h.Logger.Info("SetKitchenLocation method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+	userID, _, _ := h.caller(c)
+
+	var data models.Point
+	err = c.ShouldBindJSON(&data)
+	if err == nil {
+		err = data.Validate()
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid location").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	loc := models.KitchenLocation{
+		KitchenId: kitchenID,
+		Location:  data,
+		UpdatedBy: userID,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+	}
+	var located bool
+	h.Storage.KitchenLocations.Update(kitchenID, func(_ models.KitchenLocation, ok bool) models.KitchenLocation {
+		located = ok
+		return loc
+	})
+	h.Routes.Forget(kitchenID, "")
+
+	// Saved searches around a point learn of a kitchen once it is located.
+	if !located {
+		ctx, cancel := callContext(c, defaultTimeout)
+		defer cancel()
+		if k, err := h.KitchenClient.Get(ctx, &pbk.ID{Id: kitchenID}); err != nil {
+			h.Logger.Error(errors.Wrap(err, "error getting kitchen for saved searches").Error())
+		} else {
+			h.notifySavedSearches(k, true)
+		}
+	}
+
+	h.Logger.Info("SetKitchenLocation method has finished successfully")
+	h.render(c, http.StatusOK, loc)
+}
+
+// EstimateDelivery godoc
+// @Summary Estimates delivery to a location
+// @Description Tells the road distance from the kitchen, the delivery fee and when an order placed now would arrive. Addresses in a delivery zone are measured to the zone's center, so the whole zone is priced alike
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Success 200 {object} models.DeliveryEstimate
+// @Failure 400 {object} string "Invalid kitchen ID or location"
+// @Failure 422 {object} string "Kitchen location not set, location not covered or unreachable"
+// @Router /kitchens/{id}/eta [get]
+func (h *Handler) EstimateDelivery(c *gin.Context) {
+	h.Logger.Info("EstimateDelivery method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	var lng float64
+	if err == nil {
+		lng, err = strconv.ParseFloat(c.Query("lng"), 64)
+	}
+	pt := models.Point{Lat: lat, Lng: lng}
+	if err == nil {
+		err = pt.Validate()
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid location").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	res, err := h.estimateDelivery(ctx, kitchenID, pt)
+	if err != nil {
+		er := errors.Wrap(err, "error estimating delivery").Error()
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("EstimateDelivery method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// FetchZoneEstimates godoc
+// @Summary Estimates delivery to every zone
+// @Description Tells the road distance, delivery fee and arrival time from the kitchen to each active delivery zone the kitchen can reach
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.DeliveryEstimates
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 422 {object} string "Kitchen location not set"
+// @Router /kitchens/{id}/zones/eta [get]
+func (h *Handler) FetchZoneEstimates(c *gin.Context) {
+	h.Logger.Info("FetchZoneEstimates method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	loc, ok := h.Storage.KitchenLocations.Get(kitchenID)
+	if !ok {
+		er := models.ErrNoKitchenLocation.Error()
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var zones []models.Zone
+	for _, z := range h.Storage.Zones.List() {
+		if z.Active {
+			zones = append(zones, z)
+		}
+	}
+	slices.SortFunc(zones, cmpZones)
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	now := time.Now()
+	prep := h.prepTime(kitchenID)
+	res := models.DeliveryEstimates{Estimates: []models.DeliveryEstimate{}}
+	for i, e := range h.Routes.Zones(ctx, kitchenID, loc.Location, zones) {
+		if e.Found {
+			res.Estimates = append(res.Estimates, h.deliveryEstimate(kitchenID, &zones[i], e, prep, now))
+		}
+	}
+
+	h.Logger.Info("FetchZoneEstimates method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// estimateDelivery estimates delivery from the kitchen to pt, measured
+// to the centroid of pt's zone when it lies in one.
+func (h *Handler) estimateDelivery(ctx context.Context, kitchenID string, pt models.Point) (models.DeliveryEstimate, error) {
+	loc, ok := h.Storage.KitchenLocations.Get(kitchenID)
+	if !ok {
+		return models.DeliveryEstimate{}, models.ErrNoKitchenLocation
+	}
+	zone, covered := h.zoneAt(pt)
+	if !covered {
+		return models.DeliveryEstimate{}, models.ErrNotCovered
+	}
+
+	var e routing.Estimate
+	if zone != nil {
+		e = h.Routes.Zones(ctx, kitchenID, loc.Location, []models.Zone{*zone})[0]
+	} else {
+		e = h.Routes.Route(ctx, loc.Location, pt)
+	}
+	if !e.Found {
+		return models.DeliveryEstimate{}, models.ErrNoRoute
+	}
+	return h.deliveryEstimate(kitchenID, zone, e, h.prepTime(kitchenID), time.Now()), nil
+}
+
+// deliveryDistance works out the distance of an order to loc, keeping
+// distanceKm when no location is given.
+func (h *Handler) deliveryDistance(ctx context.Context, kitchenID string, loc *models.Point, distanceKm float32) (float32, error) {
+	if loc == nil {
+		return distanceKm, nil
+	}
+	e, err := h.estimateDelivery(ctx, kitchenID, *loc)
+	if err != nil {
+		return 0, err
+	}
+	return e.DistanceKm, nil
+}
+
+func (h *Handler) deliveryEstimate(kitchenID string, zone *models.Zone, e routing.Estimate, prep time.Duration, now time.Time) models.DeliveryEstimate {
+	distance := float32(math.Round(e.DistanceKm*10) / 10)
+	res := models.DeliveryEstimate{
+		KitchenId:     kitchenID,
+		DistanceKm:    distance,
+		TravelMinutes: int(math.Ceil(e.Duration.Minutes())),
+		PrepMinutes:   int(math.Ceil(prep.Minutes())),
+		DeliveryFee:   h.Pricing.DeliveryFee(distance),
+		Provider:      e.Provider,
+	}
+	res.EtaMinutes = res.PrepMinutes + res.TravelMinutes
+	res.ArrivesAt = now.Add(time.Duration(res.EtaMinutes) * time.Minute).Format(time.RFC3339)
+	if zone != nil {
+		res.Zone = &models.CoverageZone{Id: zone.Id, Name: zone.Name, City: zone.City}
+	}
+	return res
+}
+
+// prepTime is the kitchen's preparation lead time, or the default for
+// kitchens without one.
+func (h *Handler) prepTime(kitchenID string) time.Duration {
+	if r := h.kitchenRules(kitchenID); r.PrepLeadMinutes > 0 {
+		return time.Duration(r.PrepLeadMinutes) * time.Minute
+	}
+	return h.PrepTime
+}
@@ -2,79 +2,59 @@ package handler
 
 import (
 	pb "api-gateway/genproto/extra"
+	"api-gateway/pkg/analytics"
 	"context"
-	"log"
-	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
 // GetStatistics godoc
 // @Summary Gets kitchen's statistics
-// @Description Informs about kitchen statistics by date
+// @Description Informs about kitchen statistics by date. Dates are in the kitchen's time zone. Sandbox orders are left out
 // @Tags kitchen
 // @Security ApiKeyAuth
 // @Param id path string true "Kitchen ID"
-// @Param start_date query string true "start date"
-// @Param end_date query string true "end date"
+// @Param start_date query string false "start date, unless period is given"
+// @Param end_date query string false "end date, unless period is given"
+// @Param period query string false "Days up to today in place of the dates" Enums(today, yesterday, last_7_days, last_30_days, last_90_days, this_month, last_month, this_year)
 // @Success 200 {object} extra.Statistics
-// @Failure 400 {object} string "Invalid kitchen ID or date format"
+// @Failure 400 {object} string "Invalid kitchen ID, date or period"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /kitchens/{id}/statistics [get]
 func (h *Handler) GetStatistics(c *gin.Context) {
-	h.Logger.Info("GetStatistics method is starting")
-	kitchenID := c.Param("id")
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
-
-	_, err := uuid.Parse(kitchenID)
-	if err != nil {
-		er := errors.Wrap(err, "invalid kitchen id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	_, err = time.Parse("2006-01-02", startDate)
-	if err != nil {
-		er := errors.Wrap(err, "invalid start date").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	_, err = time.Parse("2006-01-02", endDate)
-	if err != nil {
-		er := errors.Wrap(err, "invalid end date").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	res, err := h.ExtraClient.GetStatistics(ctx, &pb.Period{
-		Id:        kitchenID,
-		StartDate: startDate,
-		EndDate:   endDate,
+	serve(h, c, Proxy[*pb.Period, *pb.Statistics]{
+		Name: "GetStatistics",
+		Bind: func(c *gin.Context) (*pb.Period, error) {
+			id, err := pathUUID(c, "id", "kitchen id")
+			if err != nil {
+				return nil, err
+			}
+
+			start, end, err := analyticsPeriod(c, h.TimeZones.Of(id))
+			if err != nil {
+				return nil, err
+			}
+
+			return &pb.Period{
+				Id:        id,
+				StartDate: start.Format("2006-01-02"),
+				EndDate:   end.Format("2006-01-02"),
+			}, nil
+		},
+		Call: func(ctx context.Context, in *pb.Period, opts ...grpc.CallOption) (*pb.Statistics, error) {
+			stats, err := inKitchenZone(h, func(in *pb.Period) string { return in.Id },
+				h.ExtraClient.GetStatistics)(ctx, in, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return analytics.WithoutSandbox(h.Storage.SandboxOrders, in, h.TimeZones.Of(in.Id), stats), nil
+		},
+		Error:   "error getting statistics",
+		Timeout: 10 * time.Second,
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error getting statistics").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("GetStatistics method has finished successfully")
-	c.JSON(http.StatusOK, res)
 }
 
 // TrackActivity godoc
@@ -83,68 +63,42 @@ func (h *Handler) GetStatistics(c *gin.Context) {
 // @Tags user
 // @Security ApiKeyAuth
 // @Param id path string true "User ID"
-// @Param start_date query string true "start date"
-// @Param end_date query string true "end date"
+// @Param start_date query string false "start date, unless period is given"
+// @Param end_date query string false "end date, unless period is given"
+// @Param period query string false "Days up to today in place of the dates" Enums(today, yesterday, last_7_days, last_30_days, last_90_days, this_month, last_month, this_year)
 // @Success 200 {object} extra.Activity
-// @Failure 400 {object} string "Invalid user ID or date format"
+// @Failure 400 {object} string "Invalid user ID, date or period"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /users/{id}/activity [get]
 func (h *Handler) TrackActivity(c *gin.Context) {
-	h.Logger.Info("TrackActivity method is starting")
-	userID := c.Param("id")
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
-
-	_, err := uuid.Parse(userID)
-	if err != nil {
-		er := errors.Wrap(err, "invalid user id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	_, err = time.Parse("2006-01-02", startDate)
-	if err != nil {
-		er := errors.Wrap(err, "invalid start date").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	_, err = time.Parse("2006-01-02", endDate)
-	if err != nil {
-		er := errors.Wrap(err, "invalid end date").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	res, err := h.ExtraClient.TrackActivity(ctx, &pb.Period{
-		Id:        userID,
-		StartDate: startDate,
-		EndDate:   endDate,
+	serve(h, c, Proxy[*pb.Period, *pb.Activity]{
+		Name: "TrackActivity",
+		Bind: func(c *gin.Context) (*pb.Period, error) {
+			id, err := pathUUID(c, "id", "user id")
+			if err != nil {
+				return nil, err
+			}
+
+			start, end, err := analyticsPeriod(c, h.TimeZones.Default())
+			if err != nil {
+				return nil, err
+			}
+
+			return &pb.Period{
+				Id:        id,
+				StartDate: start.Format("2006-01-02"),
+				EndDate:   end.Format("2006-01-02"),
+			}, nil
+		},
+		Call:    h.ExtraClient.TrackActivity,
+		Error:   "error tracking activity",
+		Timeout: 10 * time.Second,
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error tracking activity").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("TrackActivity method has finished successfully")
-	c.JSON(http.StatusOK, res)
 }
 
 // SetWorkingHours godoc
 // @Summary Sets working hours
-// @Description Sets working hours for kitchen
+// @Description Sets working hours for kitchen, in the kitchen's time zone
 // @Tags kitchen
 // @Security ApiKeyAuth
 // @Param id path string true "Kitchen ID"
@@ -154,44 +108,28 @@ func (h *Handler) TrackActivity(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /kitchens/{id}/working-hours [post]
 func (h *Handler) SetWorkingHours(c *gin.Context) {
-	h.Logger.Info("SetWorkingHours method is starting")
-	kitchenID := c.Param("id")
-
-	_, err := uuid.Parse(kitchenID)
-	if err != nil {
-		er := errors.Wrap(err, "invalid kitchen id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	var data map[string]*pb.DaySchedule
-	if err := c.ShouldBindJSON(&data); err != nil {
-		er := errors.Wrap(err, "invalid data").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	res, err := h.ExtraClient.SetWorkingHours(ctx, &pb.WorkingHours{
-		KitchenId: kitchenID,
-		Schedule:  data,
+	serve(h, c, Proxy[*pb.WorkingHours, *pb.WorkingHoursResp]{
+		Name: "SetWorkingHours",
+		Bind: func(c *gin.Context) (*pb.WorkingHours, error) {
+			kitchenID, err := pathUUID(c, "id", "kitchen id")
+			if err != nil {
+				return nil, err
+			}
+
+			var data map[string]*pb.DaySchedule
+			if err := c.ShouldBindJSON(&data); err != nil {
+				return nil, errors.Wrap(err, "invalid data")
+			}
+
+			return &pb.WorkingHours{
+				KitchenId: kitchenID,
+				Schedule:  data,
+			}, nil
+		},
+		Call: inKitchenZone(h, func(in *pb.WorkingHours) string { return in.KitchenId },
+			h.ExtraClient.SetWorkingHours),
+		Error: "error setting working hours",
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error setting working hours").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("SetWorkingHours method has finished successfully")
-	c.JSON(http.StatusOK, res)
 }
 
 // GetNutrition godoc
@@ -203,33 +141,16 @@ func (h *Handler) SetWorkingHours(c *gin.Context) {
 // @Success 200 {object} extra.NutritionalInfo
 // @Failure 400 {object} string "Invalid dish ID"
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
 // @Router /dishes/{id}/nutrition [get]
 func (h *Handler) GetNutrition(c *gin.Context) {
-	h.Logger.Info("GetNutrition method is starting")
-	dishID := c.Param("id")
-	log.Print(dishID)
-
-	_, err := uuid.Parse(dishID)
-	if err != nil {
-		er := errors.Wrap(err, "invalid dish id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	res, err := h.ExtraClient.GetNutrition(ctx, &pb.ID{Id: dishID})
-	if err != nil {
-		er := errors.Wrap(err, "error getting dish's nutritional info").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("GetNutrition method has finished successfully")
-	c.JSON(http.StatusOK, res)
+	serve(h, c, Proxy[*pb.ID, *pb.NutritionalInfo]{
+		Name: "GetNutrition",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "dish id")
+			return &pb.ID{Id: id}, err
+		},
+		Call:  h.ExtraClient.GetNutrition,
+		Error: "error getting dish's nutritional info",
+	})
 }
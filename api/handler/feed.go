@@ -0,0 +1,195 @@
+package handler
+
+import (
+	pbk "api-gateway/genproto/kitchen"
+	pbo "api-gateway/genproto/order"
+	pbr "api-gateway/genproto/review"
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetFeed godoc
+// @Summary Gets user's activity feed
+// @Description Lists orders placed and reviews written by the user, newest first, with current order status and kitchen names. The active announcements of the kitchens in the feed are mixed in by their start. Activity at kitchens the user blocked is left out
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param page query int true "Page number"
+// @Param limit query int true "Number of items per page"
+// @Success 200 {object} models.Feed
+// @Failure 400 {object} string "Invalid user ID or pagination parameters"
+// @Failure 403 {object} string "Access denied"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
+// @Router /users/{id}/feed [get]
+func (h *Handler) GetFeed(c *gin.Context) {
+	h.Logger.Info("GetFeed method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := pagination(c)
+	if err == nil && (limit <= 0 || offset < 0) {
+		err = errors.New("invalid pagination parameters")
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// Activities are stored oldest first.
+	items, _ := h.Storage.Activity.Get(userID)
+	items = slices.DeleteFunc(slices.Clone(items), func(it models.FeedItem) bool {
+		return h.blocked(userID, it.KitchenId())
+	})
+	slices.Reverse(items)
+	items = append(items, h.feedAnnouncements(items)...)
+	slices.SortStableFunc(items, func(a, b models.FeedItem) int {
+		return parseTime(b.OccurredAt).Compare(parseTime(a.OccurredAt))
+	})
+
+	res := models.Feed{
+		Items: []models.FeedItem{},
+		Total: int32(len(items)),
+		Page:  offset/limit + 1,
+		Limit: limit,
+	}
+	if int(offset) < len(items) {
+		res.Items = items[offset:min(int(offset+limit), len(items))]
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+	h.enrichFeed(ctx, res.Items)
+
+	h.Logger.Info("GetFeed method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// enrichFeed fills in current order statuses and kitchen names from the
+// order and kitchen services. Items keep their recorded data when a
+// lookup fails, so one slow service does not break the feed.
+func (h *Handler) enrichFeed(ctx context.Context, items []models.FeedItem) {
+	var wg sync.WaitGroup
+	for i := range items {
+		// Copy the details so the stored activity is not modified.
+		switch it := &items[i]; it.Type {
+		case models.FeedOrderPlaced:
+			o := *it.Order
+			it.Order = &o
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				info, err := h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: o.Id})
+				if err != nil {
+					h.Logger.Error(errors.Wrapf(err, "error getting order %s for feed", o.Id).Error())
+					return
+				}
+				it.Order.Status = info.Status
+				it.Order.KitchenName = info.KitchenName
+			}()
+		case models.FeedReviewWritten:
+			r := *it.Review
+			it.Review = &r
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				name, err := h.KitchenClient.GetName(ctx, &pbk.ID{Id: r.KitchenId})
+				if err != nil {
+					h.Logger.Error(errors.Wrapf(err, "error getting kitchen %s for feed", r.KitchenId).Error())
+					return
+				}
+				it.Review.KitchenName = name.Name
+			}()
+		case models.FeedAnnouncement:
+			a := *it.Announcement
+			it.Announcement = &a
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				name, err := h.KitchenClient.GetName(ctx, &pbk.ID{Id: a.KitchenId})
+				if err != nil {
+					h.Logger.Error(errors.Wrapf(err, "error getting kitchen %s for feed", a.KitchenId).Error())
+					return
+				}
+				it.Announcement.KitchenName = name.Name
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+// feedAnnouncements returns the active announcements of the kitchens in
+// the activity as feed items.
+func (h *Handler) feedAnnouncements(items []models.FeedItem) []models.FeedItem {
+	var kitchenIDs []string
+	for _, it := range items {
+		if id := it.KitchenId(); id != "" && !slices.Contains(kitchenIDs, id) {
+			kitchenIDs = append(kitchenIDs, id)
+		}
+	}
+
+	var res []models.FeedItem
+	for _, a := range h.activeAnnouncements(kitchenIDs, time.Now()) {
+		res = append(res, models.FeedItem{
+			Type:       models.FeedAnnouncement,
+			OccurredAt: a.StartsAt,
+			Announcement: &models.FeedKitchenAnnouncement{
+				Id:        a.Id,
+				KitchenId: a.KitchenId,
+				Kind:      a.Kind,
+				Title:     a.Title,
+				Body:      a.Body,
+				EndsAt:    a.EndsAt,
+			},
+		})
+	}
+	return res
+}
+
+func (h *Handler) recordOrderPlaced(res *pbo.NewOrderResp) {
+	h.recordActivity(res.UserId, models.FeedItem{
+		Type: models.FeedOrderPlaced,
+		Order: &models.FeedOrder{
+			Id:          res.Id,
+			KitchenId:   res.KitchenId,
+			TotalAmount: res.TotalAmount,
+			Status:      res.Status,
+		},
+	})
+}
+
+func (h *Handler) recordReviewWritten(res *pbr.NewReviewResp) {
+	h.recordActivity(res.UserId, models.FeedItem{
+		Type: models.FeedReviewWritten,
+		Review: &models.FeedReview{
+			Id:        res.Id,
+			OrderId:   res.OrderId,
+			KitchenId: res.KitchenId,
+			Rating:    res.Rating,
+			Comment:   res.Comment,
+		},
+	})
+}
+
+func (h *Handler) recordActivity(userID string, item models.FeedItem) {
+	item.OccurredAt = time.Now().Format(time.RFC3339)
+	h.Storage.Activity.Update(userID, func(items []models.FeedItem, _ bool) []models.FeedItem {
+		items = append(items, item)
+		if len(items) > models.MaxFeedItems {
+			items = slices.Clone(items[len(items)-models.MaxFeedItems:])
+		}
+		return items
+	})
+}
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	pbo "api-gateway/genproto/order"
+	"api-gateway/models"
+	"api-gateway/pkg/fraud"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// deviceLocation reads where the customer's phone is from the
+// X-Device-Location header, "lat,lng", which apps send when they may.
+func deviceLocation(c *gin.Context) (*models.Point, error) {
+	header := c.GetHeader("X-Device-Location")
+	if header == "" {
+		return nil, nil
+	}
+
+	lat, lng, ok := strings.Cut(header, ",")
+	var p models.Point
+	var err error
+	if ok {
+		p.Lat, err = strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	}
+	if ok && err == nil {
+		p.Lng, err = strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	}
+	if ok && err == nil {
+		err = p.Validate()
+	}
+	if !ok || err != nil {
+		return nil, errors.New("invalid X-Device-Location: must be lat,lng")
+	}
+	return &p, nil
+}
+
+// screenOrder checks the customer's order for fraud once its total is
+// known. Orders of tokens without a user are not screened.
+func (h *Handler) screenOrder(userID string, data models.NewOrder, total float32, device *models.Point) *orderFailure {
+	if userID == "" {
+		return nil
+	}
+
+	d := h.Fraud.Order(fraud.Order{
+		UserId:   userID,
+		Amount:   total,
+		Delivery: data.Location,
+		Device:   device,
+	}, time.Now())
+	switch d.Action {
+	case models.FraudVerify:
+		return verifyFailure("order")
+	case models.FraudReview:
+		fc := h.holdForFraud(models.FraudCase{
+			Kind:   models.FraudCaseOrder,
+			UserId: userID,
+			Amount: total,
+			Hits:   d.Hits,
+			Order:  &data,
+		})
+		return holdFailure(fc, "Your order is being reviewed and will be placed once approved")
+	}
+	return nil
+}
+
+// screenPayment checks the customer's payment for fraud. Payments of
+// orders an admin approved are not screened again, and those held stay
+// so until decided.
+func (h *Handler) screenPayment(userID string, order *pbo.OrderInfo) *orderFailure {
+	if userID == "" {
+		return nil
+	}
+
+	if fc, ok := h.fraudCase(order.Id); ok {
+		switch fc.Status {
+		case models.FraudApproved:
+			return nil
+		case models.FraudRejected:
+			er := "payment refused after review"
+			return &orderFailure{status: http.StatusForbidden, err: er, body: gin.H{"error": er}}
+		default:
+			return holdFailure(fc, "Your payment is being reviewed, try again once it is approved")
+		}
+	}
+
+	d := h.Fraud.Payment(fraud.Payment{
+		UserId:  userID,
+		OrderId: order.Id,
+		Amount:  order.TotalAmount,
+	}, time.Now())
+	switch d.Action {
+	case models.FraudVerify:
+		return verifyFailure("payment")
+	case models.FraudReview:
+		fc := h.holdForFraud(models.FraudCase{
+			Kind:    models.FraudCasePayment,
+			UserId:  userID,
+			OrderId: order.Id,
+			Amount:  order.TotalAmount,
+			Hits:    d.Hits,
+		})
+		return holdFailure(fc, "Your payment is being reviewed, try again once it is approved")
+	}
+	return nil
+}
+
+// fraudCase finds the latest case of the order.
+func (h *Handler) fraudCase(orderID string) (models.FraudCase, bool) {
+	var found models.FraudCase
+	for _, fc := range h.Storage.FraudCases.List() {
+		if fc.OrderId == orderID && fc.CreatedAt >= found.CreatedAt {
+			found = fc
+		}
+	}
+	return found, found.Id != ""
+}
+
+func (h *Handler) holdForFraud(fc models.FraudCase) models.FraudCase {
+	fc.Id = uuid.NewString()
+	fc.Status = models.FraudPending
+	fc.CreatedAt = time.Now().Format(time.RFC3339)
+	h.Storage.FraudCases.Set(fc.Id, fc)
+	return fc
+}
+
+// verifyFailure asks the customer to pass an OTP. Which rules matched is
+// not told, so they can't be probed.
+func verifyFailure(what string) *orderFailure {
+	er := what + " needs verification, pass an OTP first"
+	return &orderFailure{status: http.StatusForbidden, err: er,
+		body: gin.H{"error": er, "challenge": "otp"}}
+}
+
+func holdFailure(fc models.FraudCase, message string) *orderFailure {
+	return &orderFailure{status: http.StatusAccepted, err: fc.Kind + " held for fraud review as case " + fc.Id,
+		body: gin.H{"case_id": fc.Id, "status": fc.Status, "message": message}}
+}
+
+// FetchFraudCases godoc
+// @Summary Gets fraud cases
+// @Description Lists the orders and payments held for fraud review, oldest first, optionally by status
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param status query string false "pending, approved or rejected"
+// @Success 200 {object} models.FraudCases
+// @Router /admin/fraud [get]
+func (h *Handler) FetchFraudCases(c *gin.Context) {
+	h.Logger.Info("FetchFraudCases method is starting")
+
+	status := c.Query("status")
+	res := models.FraudCases{Cases: []models.FraudCase{}}
+	for _, fc := range h.Storage.FraudCases.List() {
+		if status == "" || fc.Status == status {
+			res.Cases = append(res.Cases, fc)
+		}
+	}
+	slices.SortFunc(res.Cases, func(a, b models.FraudCase) int {
+		return strings.Compare(a.CreatedAt, b.CreatedAt)
+	})
+
+	h.Logger.Info("FetchFraudCases method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetFraudCase godoc
+// @Summary Gets a fraud case
+// @Description Gets a held order or payment with the rules it matched
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Case ID"
+// @Success 200 {object} models.FraudCase
+// @Failure 400 {object} string "Invalid case ID"
+// @Failure 404 {object} string "Case not found"
+// @Router /admin/fraud/{id} [get]
+func (h *Handler) GetFraudCase(c *gin.Context) {
+	h.Logger.Info("GetFraudCase method is starting")
+
+	id, err := pathUUID(c, "id", "case id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	fc, ok := h.Storage.FraudCases.Get(id)
+	if !ok {
+		er := "fraud case not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetFraudCase method has finished successfully")
+	h.render(c, http.StatusOK, fc)
+}
+
+// ReviewFraudCase godoc
+// @Summary Reviews a fraud case
+// @Description Approves or rejects a held order or payment. An approved order is placed then; an approved payment can be tried again by the customer, as can payments of the approved order
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Case ID"
+// @Param decision body models.FraudDecision true "Decision"
+// @Success 200 {object} models.FraudCase
+// @Failure 400 {object} string "Invalid ID or decision"
+// @Failure 404 {object} string "Case not found"
+// @Failure 409 {object} string "Case already reviewed"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/fraud/{id} [put]
+func (h *Handler) ReviewFraudCase(c *gin.Context) {
+	h.Logger.Info("ReviewFraudCase method is starting")
+
+	id, err := pathUUID(c, "id", "case id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.FraudDecision
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid decision").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if data.Status != models.FraudApproved && data.Status != models.FraudRejected {
+		er := errors.Errorf("invalid decision: status must be %s or %s",
+			models.FraudApproved, models.FraudRejected).Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	fc, ok := h.Storage.FraudCases.Get(id)
+	if !ok {
+		er := "fraud case not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	if fc.Status != models.FraudPending {
+		er := errors.Errorf("case was already %s", fc.Status).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if data.Status == models.FraudApproved && fc.Kind == models.FraudCaseOrder {
+		ctx, cancel := callContext(c, time.Second*5)
+		defer cancel()
+
+		res, fail := h.placeOrder(ctx, *fc.Order, nil)
+		if fail != nil {
+			er := "failed to place held order: " + fail.err
+			c.AbortWithStatusJSON(fail.status,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+		h.Fraud.Placed(fc.UserId)
+		fc.OrderId = res.Id
+	}
+
+	fc.Status = data.Status
+	fc.Note = data.Note
+	fc.ReviewedBy = userID
+	fc.ReviewedAt = time.Now().Format(time.RFC3339)
+	h.Storage.FraudCases.Set(fc.Id, fc)
+
+	h.Logger.Info("ReviewFraudCase method has finished successfully")
+	h.render(c, http.StatusOK, fc)
+}
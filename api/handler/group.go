@@ -0,0 +1,675 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"crypto/rand"
+	"encoding/base32"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+)
+
+// maxGroupItems bounds the size of a group cart.
+const maxGroupItems = 100
+
+// CreateGroupOrder godoc
+// @Summary Creates a group order
+// @Description Opens a cart at a kitchen that others can join through its share link. The caller becomes the host who checks out
+// @Tags group order
+// @Security ApiKeyAuth
+// @Param group body models.NewGroupOrder true "Kitchen and delivery"
+// @Success 200 {object} models.GroupOrder
+// @Failure 400 {object} string "Invalid group order"
+// @Failure 403 {object} string "Token has no user id"
+// @Failure 404 {object} string "Kitchen not found"
+// @Router /group-orders [post]
+func (h *Handler) CreateGroupOrder(c *gin.Context) {
+	h.Logger.Info("CreateGroupOrder method is starting")
+
+	userID, ok := h.user(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewGroupOrder
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid group order").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, err := uuid.Parse(data.KitchenId); err != nil || data.DeliveryAddress == "" || data.Location == nil {
+		er := "invalid group order: kitchen_id, delivery_address and location are required"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	if err := data.Location.Validate(); err != nil {
+		er := errors.Wrap(err, "invalid group order").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	if _, err := h.KitchenClient.Get(ctx, &pbk.ID{Id: data.KitchenId}); err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting kitchen").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	code, err := shareCode()
+	if err != nil {
+		er := errors.Wrap(err, "error creating share code").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	g := models.GroupOrder{
+		Id:              uuid.NewString(),
+		ShareCode:       code,
+		ShareUrl:        strings.TrimSuffix(h.GroupURL, "/") + "/" + code,
+		HostId:          userID,
+		KitchenId:       data.KitchenId,
+		DeliveryAddress: data.DeliveryAddress,
+		DeliveryTime:    data.DeliveryTime,
+		Location:        data.Location,
+		Status:          models.GroupOpen,
+		Participants:    []string{userID},
+		Items:           []models.GroupItem{},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	h.Storage.GroupOrders.Set(g.Id, g)
+	h.Storage.GroupShareCodes.Set(code, g.Id)
+
+	h.Logger.Info("CreateGroupOrder method has finished successfully")
+	h.render(c, http.StatusOK, g)
+}
+
+// JoinGroupOrder godoc
+// @Summary Joins a group order
+// @Description Adds the caller to the participants of the open group order the share code belongs to
+// @Tags group order
+// @Security ApiKeyAuth
+// @Param code path string true "Share code"
+// @Success 200 {object} models.GroupOrder
+// @Failure 403 {object} string "Token has no user id"
+// @Failure 404 {object} string "Group order not found"
+// @Failure 409 {object} string "Group order is closed"
+// @Router /group-orders/join/{code} [post]
+func (h *Handler) JoinGroupOrder(c *gin.Context) {
+	h.Logger.Info("JoinGroupOrder method is starting")
+
+	userID, ok := h.user(c)
+	if !ok {
+		return
+	}
+
+	id, ok := h.Storage.GroupShareCodes.Get(c.Param("code"))
+	if !ok {
+		er := "group order not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	g, ok := h.changeGroup(c, id, models.GroupUpdateJoined, func(g *models.GroupOrder) (int, error) {
+		if !slices.Contains(g.Participants, userID) {
+			g.Participants = append(g.Participants, userID)
+		}
+		return 0, nil
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("JoinGroupOrder method has finished successfully")
+	h.render(c, http.StatusOK, g)
+}
+
+// GetGroupOrder godoc
+// @Summary Gets a group order
+// @Description Retrieves the group cart with everyone's items. For participants and admins
+// @Tags group order
+// @Security ApiKeyAuth
+// @Param id path string true "Group order ID"
+// @Success 200 {object} models.GroupOrder
+// @Failure 400 {object} string "Invalid group order ID"
+// @Failure 403 {object} string "Not a participant"
+// @Failure 404 {object} string "Group order not found"
+// @Router /group-orders/{id} [get]
+func (h *Handler) GetGroupOrder(c *gin.Context) {
+	h.Logger.Info("GetGroupOrder method is starting")
+
+	g, _, ok := h.findGroup(c)
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("GetGroupOrder method has finished successfully")
+	h.render(c, http.StatusOK, g)
+}
+
+// AddGroupItem godoc
+// @Summary Adds an item to a group order
+// @Description Puts a dish of the group's kitchen into the cart on behalf of the caller
+// @Tags group order
+// @Security ApiKeyAuth
+// @Param id path string true "Group order ID"
+// @Param item body models.NewGroupItem true "Item"
+// @Success 200 {object} models.GroupOrder
+// @Failure 400 {object} string "Invalid item"
+// @Failure 403 {object} string "Not a participant"
+// @Failure 404 {object} string "Group order not found"
+// @Failure 409 {object} string "Group order is closed"
+// @Router /group-orders/{id}/items [post]
+func (h *Handler) AddGroupItem(c *gin.Context) {
+	h.Logger.Info("AddGroupItem method is starting")
+
+	g, userID, ok := h.findGroup(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewGroupItem
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid item").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if data.Quantity <= 0 {
+		er := "invalid item: quantity must be positive"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	dish, price, err := h.itemPrice(ctx, models.OrderItem{
+		DishId:    data.DishId,
+		Quantity:  data.Quantity,
+		Modifiers: data.Modifiers,
+	})
+	if err == nil && dish.KitchenId != g.KitchenId {
+		err = errors.New("dish belongs to another kitchen")
+	}
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "invalid item").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	item := models.GroupItem{
+		Id:        uuid.NewString(),
+		UserId:    userID,
+		DishId:    data.DishId,
+		Quantity:  data.Quantity,
+		Modifiers: data.Modifiers,
+		UnitPrice: price,
+		AddedAt:   time.Now().Format(time.RFC3339),
+	}
+	g, ok = h.changeGroup(c, g.Id, models.GroupUpdateItemAdded, func(g *models.GroupOrder) (int, error) {
+		if len(g.Items) >= maxGroupItems {
+			return http.StatusConflict, errors.Errorf("group cart is full, at most %d items", maxGroupItems)
+		}
+		g.Items = append(g.Items, item)
+		return 0, nil
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("AddGroupItem method has finished successfully")
+	h.render(c, http.StatusOK, g)
+}
+
+// UpdateGroupItem godoc
+// @Summary Changes an item of a group order
+// @Description Changes the quantity of an item. Participants change their own items, the host any item
+// @Tags group order
+// @Security ApiKeyAuth
+// @Param id path string true "Group order ID"
+// @Param item_id path string true "Item ID"
+// @Param quantity body models.GroupItemQuantity true "Quantity"
+// @Success 200 {object} models.GroupOrder
+// @Failure 400 {object} string "Invalid quantity"
+// @Failure 403 {object} string "Item belongs to another participant"
+// @Failure 404 {object} string "Group order or item not found"
+// @Failure 409 {object} string "Group order is closed"
+// @Router /group-orders/{id}/items/{item_id} [put]
+func (h *Handler) UpdateGroupItem(c *gin.Context) {
+	h.Logger.Info("UpdateGroupItem method is starting")
+
+	g, userID, ok := h.findGroup(c)
+	if !ok {
+		return
+	}
+
+	var data models.GroupItemQuantity
+	if err := c.ShouldBindJSON(&data); err != nil || data.Quantity <= 0 {
+		er := "invalid quantity: must be positive"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	g, ok = h.changeGroup(c, g.Id, models.GroupUpdateItemChanged, func(g *models.GroupOrder) (int, error) {
+		i, status, err := groupItem(g, c.Param("item_id"), userID)
+		if err != nil {
+			return status, err
+		}
+		g.Items[i].Quantity = data.Quantity
+		return 0, nil
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("UpdateGroupItem method has finished successfully")
+	h.render(c, http.StatusOK, g)
+}
+
+// RemoveGroupItem godoc
+// @Summary Removes an item from a group order
+// @Description Takes an item out of the cart. Participants remove their own items, the host any item
+// @Tags group order
+// @Security ApiKeyAuth
+// @Param id path string true "Group order ID"
+// @Param item_id path string true "Item ID"
+// @Success 200 {object} models.GroupOrder
+// @Failure 403 {object} string "Item belongs to another participant"
+// @Failure 404 {object} string "Group order or item not found"
+// @Failure 409 {object} string "Group order is closed"
+// @Router /group-orders/{id}/items/{item_id} [delete]
+func (h *Handler) RemoveGroupItem(c *gin.Context) {
+	h.Logger.Info("RemoveGroupItem method is starting")
+
+	g, userID, ok := h.findGroup(c)
+	if !ok {
+		return
+	}
+
+	g, ok = h.changeGroup(c, g.Id, models.GroupUpdateItemRemoved, func(g *models.GroupOrder) (int, error) {
+		i, status, err := groupItem(g, c.Param("item_id"), userID)
+		if err != nil {
+			return status, err
+		}
+		g.Items = slices.Delete(g.Items, i, i+1)
+		return 0, nil
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("RemoveGroupItem method has finished successfully")
+	h.render(c, http.StatusOK, g)
+}
+
+// CheckoutGroupOrder godoc
+// @Summary Checks out a group order
+// @Description Places one order with everyone's items for the host. The cart is locked while the order is placed and reopens if it fails. The order is screened for fraud as the host's, like POST /orders. Checked out in the sandbox, it is a test order
+// @Tags group order
+// @Security ApiKeyAuth
+// @Param id path string true "Group order ID"
+// @Param checkout body models.GroupCheckout false "Expected total"
+// @Param X-Device-Location header string false "Where the host's phone is, as lat,lng"
+// @Success 200 {object} order.NewOrderResp
+// @Success 202 {object} models.FraudHold "Order held for review"
+// @Failure 400 {object} string "Invalid group order"
+// @Failure 403 {object} string "Only the host can check out, or the host must pass an OTP first, see challenge"
+// @Failure 404 {object} string "Group order not found"
+// @Failure 409 {object} string "Group order is closed, empty or its total does not match current prices"
+// @Failure 422 {object} string "Order breaks a kitchen rule, named by rule, or the location can't be routed to"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /group-orders/{id}/checkout [post]
+func (h *Handler) CheckoutGroupOrder(c *gin.Context) {
+	h.Logger.Info("CheckoutGroupOrder method is starting")
+
+	g, userID, ok := h.findGroup(c)
+	if !ok {
+		return
+	}
+
+	var checkout models.GroupCheckout
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&checkout); err != nil {
+			er := errors.Wrap(err, "invalid checkout").Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	device, err := deviceLocation(c)
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	g, ok = h.changeGroup(c, g.Id, models.GroupUpdateCheckingOut, func(g *models.GroupOrder) (int, error) {
+		switch {
+		case g.HostId != userID:
+			return http.StatusForbidden, errors.New("only the host can check out")
+		case len(g.Items) == 0:
+			return http.StatusConflict, errors.New("group cart is empty")
+		}
+		g.Status = models.GroupCheckingOut
+		return 0, nil
+	})
+	if !ok {
+		return
+	}
+
+	data := models.NewOrder{
+		UserId:          g.HostId,
+		KitchenId:       g.KitchenId,
+		DeliveryAddress: g.DeliveryAddress,
+		DeliveryTime:    g.DeliveryTime,
+		Location:        g.Location,
+		TotalAmount:     checkout.TotalAmount,
+		Sandbox:         middleware.InSandbox(c),
+	}
+	for _, item := range g.Items {
+		data.Items = append(data.Items, models.OrderItem{
+			DishId:    item.DishId,
+			Quantity:  item.Quantity,
+			Modifiers: item.Modifiers,
+		})
+	}
+
+	// The host pays for the whole cart, so the order is screened as theirs.
+	screen := func(total float32) *orderFailure {
+		return h.screenOrder(userID, data, total, device)
+	}
+	if data.Sandbox {
+		screen = nil
+	}
+
+	ctx, cancel := callContext(c, time.Second*5)
+	defer cancel()
+
+	res, fail := h.placeOrder(ctx, data, screen)
+	if fail != nil {
+		h.closeCheckout(g.Id, "")
+		c.AbortWithStatusJSON(fail.status, fail.body)
+		h.Logger.Error(fail.err)
+		return
+	}
+	h.closeCheckout(g.Id, res.Id)
+	h.customerPlaced(userID, c.GetString(middleware.RequestIDKey), data)
+
+	h.Logger.Info("CheckoutGroupOrder method has finished successfully")
+	h.renderOrder(c, res)
+}
+
+// CancelGroupOrder godoc
+// @Summary Cancels a group order
+// @Description Closes the cart without ordering. For the host and admins
+// @Tags group order
+// @Security ApiKeyAuth
+// @Param id path string true "Group order ID"
+// @Success 200 {object} models.GroupOrder
+// @Failure 403 {object} string "Only the host can cancel"
+// @Failure 404 {object} string "Group order not found"
+// @Failure 409 {object} string "Group order is closed"
+// @Router /group-orders/{id} [delete]
+func (h *Handler) CancelGroupOrder(c *gin.Context) {
+	h.Logger.Info("CancelGroupOrder method is starting")
+
+	g, userID, ok := h.findGroup(c)
+	if !ok {
+		return
+	}
+
+	_, role, _ := h.caller(c)
+	g, ok = h.changeGroup(c, g.Id, models.GroupUpdateCanceled, func(g *models.GroupOrder) (int, error) {
+		if g.HostId != userID && role != models.RoleAdmin {
+			return http.StatusForbidden, errors.New("only the host can cancel")
+		}
+		g.Status = models.GroupCanceled
+		return 0, nil
+	})
+	if !ok {
+		return
+	}
+	h.Storage.GroupShareCodes.Delete(g.ShareCode)
+
+	h.Logger.Info("CancelGroupOrder method has finished successfully")
+	h.render(c, http.StatusOK, g)
+}
+
+// WatchGroupOrder godoc
+// @Summary Watches a group order
+// @Description Upgrades to a WebSocket that sends a models.GroupUpdate with the whole cart now and after every change, until the group is checked out or canceled
+// @Tags group order
+// @Security ApiKeyAuth
+// @Param id path string true "Group order ID"
+// @Success 101 {object} models.GroupUpdate
+// @Failure 403 {object} string "Not a participant"
+// @Failure 404 {object} string "Group order not found"
+// @Router /group-orders/{id}/ws [get]
+func (h *Handler) WatchGroupOrder(c *gin.Context) {
+	h.Logger.Info("WatchGroupOrder method is starting")
+
+	g, _, ok := h.findGroup(c)
+	if !ok {
+		return
+	}
+
+	websocket.Server{
+		// Clients authenticate with their token rather than by origin.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			// The server's read and write timeouts were meant for the
+			// upgrade request, not the socket.
+			ws.SetDeadline(time.Time{})
+
+			updates, stop := h.Groups.Subscribe(g.Id)
+			defer stop()
+
+			// The client sends nothing; reading only notices it leave.
+			left := make(chan struct{})
+			go func() {
+				io.Copy(io.Discard, ws)
+				close(left)
+			}()
+
+			g, _ = h.Storage.GroupOrders.Get(g.Id)
+			u := models.GroupUpdate{Type: models.GroupUpdateSnapshot, Group: g}
+			for {
+				if err := websocket.JSON.Send(ws, u); err != nil {
+					h.Logger.Error(errors.Wrap(err, "error sending group update").Error())
+					return
+				}
+				if groupClosed(u.Group.Status) {
+					return
+				}
+
+				select {
+				case u = <-updates:
+				case <-left:
+					return
+				}
+			}
+		},
+	}.ServeHTTP(c.Writer, c.Request)
+
+	h.Logger.Info("WatchGroupOrder method has finished successfully")
+}
+
+// findGroup loads the group of the path and checks that the caller takes
+// part in it. Admins may see any group.
+func (h *Handler) findGroup(c *gin.Context) (models.GroupOrder, string, bool) {
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return models.GroupOrder{}, "", false
+	}
+
+	id, err := pathUUID(c, "id", "group order id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.GroupOrder{}, "", false
+	}
+
+	g, ok := h.Storage.GroupOrders.Get(id)
+	if !ok {
+		er := "group order not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.GroupOrder{}, "", false
+	}
+
+	if role != models.RoleAdmin && !slices.Contains(g.Participants, userID) {
+		er := "not a participant of the group order"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.GroupOrder{}, "", false
+	}
+	return g, userID, true
+}
+
+// changeGroup applies fn to the open group atomically and sends the
+// result to the group's WebSocket clients. fn refuses the change by
+// returning an error with its HTTP status.
+func (h *Handler) changeGroup(c *gin.Context, id, update string,
+	fn func(g *models.GroupOrder) (int, error)) (models.GroupOrder, bool) {
+	status, err := http.StatusNotFound, errors.New("group order not found")
+	g := h.Storage.GroupOrders.Update(id, func(g models.GroupOrder, ok bool) models.GroupOrder {
+		switch {
+		case !ok:
+			return g
+		case g.Status != models.GroupOpen:
+			status, err = http.StatusConflict, errors.Errorf("group order is %s", g.Status)
+			return g
+		}
+
+		next := g
+		next.Participants = slices.Clone(g.Participants)
+		next.Items = slices.Clone(g.Items)
+		if status, err = fn(&next); err != nil {
+			return g
+		}
+		next.Subtotal = groupSubtotal(next.Items)
+		next.UpdatedAt = time.Now().Format(time.RFC3339)
+		return next
+	})
+	if err != nil {
+		if g.Id == "" {
+			h.Storage.GroupOrders.Delete(id)
+		}
+		er := err.Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.GroupOrder{}, false
+	}
+
+	h.publishGroup(update, g)
+	return g, true
+}
+
+// closeCheckout finishes a checkout: the group is done when the order
+// was placed, and open for changes again when orderID is empty.
+func (h *Handler) closeCheckout(id, orderID string) {
+	placed := orderID != ""
+	update := models.GroupUpdateCheckoutFailed
+	if placed {
+		update = models.GroupUpdateCheckedOut
+	}
+
+	g := h.Storage.GroupOrders.Update(id, func(g models.GroupOrder, _ bool) models.GroupOrder {
+		g.Status, g.OrderId = models.GroupOpen, orderID
+		if placed {
+			g.Status = models.GroupCheckedOut
+		}
+		g.UpdatedAt = time.Now().Format(time.RFC3339)
+		return g
+	})
+	if placed {
+		h.Storage.GroupShareCodes.Delete(g.ShareCode)
+	}
+	h.publishGroup(update, g)
+}
+
+func (h *Handler) publishGroup(update string, g models.GroupOrder) {
+	h.Groups.Publish(g.Id, models.GroupUpdate{Type: update, Group: g})
+}
+
+// groupItem finds the item that the user may change: their own, or any
+// item for the host.
+func groupItem(g *models.GroupOrder, itemID, userID string) (int, int, error) {
+	i := slices.IndexFunc(g.Items, func(item models.GroupItem) bool {
+		return item.Id == itemID
+	})
+	switch {
+	case i < 0:
+		return 0, http.StatusNotFound, errors.New("item not found")
+	case g.Items[i].UserId != userID && g.HostId != userID:
+		return 0, http.StatusForbidden, errors.New("item belongs to another participant")
+	}
+	return i, 0, nil
+}
+
+func groupSubtotal(items []models.GroupItem) float32 {
+	var total float32
+	for _, item := range items {
+		total += item.UnitPrice * float32(item.Quantity)
+	}
+	return total
+}
+
+func groupClosed(status string) bool {
+	return status == models.GroupCheckedOut || status == models.GroupCanceled
+}
+
+// shareCode makes an unguessable code for share links.
+func shareCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.EncodeToString(b)), nil
+}
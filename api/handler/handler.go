@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"api-gateway/api/middleware"
 	"api-gateway/config"
 	"api-gateway/genproto/dish"
 	"api-gateway/genproto/extra"
@@ -9,9 +10,50 @@ import (
 	"api-gateway/genproto/payment"
 	"api-gateway/genproto/review"
 	"api-gateway/genproto/user"
+	"api-gateway/models"
 	"api-gateway/pkg"
+	"api-gateway/pkg/abuse"
+	"api-gateway/pkg/analytics"
+	"api-gateway/pkg/broadcast"
+	"api-gateway/pkg/catalog"
+	"api-gateway/pkg/cdn"
+	"api-gateway/pkg/dataexport"
+	"api-gateway/pkg/email"
+	"api-gateway/pkg/encoded"
+	"api-gateway/pkg/envelope"
+	"api-gateway/pkg/events"
+	"api-gateway/pkg/fraud"
+	"api-gateway/pkg/hub"
+	"api-gateway/pkg/imageproxy"
+	"api-gateway/pkg/invitation"
 	"api-gateway/pkg/logger"
+	"api-gateway/pkg/mealplan"
+	"api-gateway/pkg/moderation"
+	"api-gateway/pkg/ordertimer"
+	"api-gateway/pkg/payments"
+	"api-gateway/pkg/pricing"
+	"api-gateway/pkg/recording"
+	"api-gateway/pkg/report"
+	"api-gateway/pkg/retention"
+	"api-gateway/pkg/routing"
+	"api-gateway/pkg/seo"
+	"api-gateway/pkg/settings"
+	"api-gateway/pkg/slo"
+	"api-gateway/pkg/sms"
+	"api-gateway/pkg/stock"
+	"api-gateway/pkg/timezone"
+	"api-gateway/pkg/upload"
+	"api-gateway/pkg/usage"
+	"api-gateway/pkg/webhook"
+	"api-gateway/storage"
 	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type Handler struct {
@@ -23,17 +65,208 @@ type Handler struct {
 	PaymentClient payment.PaymentClient
 	ExtraClient   extra.ExtraClient
 	Logger        *slog.Logger
+	Storage       *storage.Storage
+	Pricing       *pricing.Calculator
+	Webhooks      *webhook.Dispatcher
+	Events        *events.Emitter
+	Marshaler     protojson.MarshalOptions
+	APIFormat     string
+	Envelope      bool
+	Uploads       *upload.Manager
+	Images        *imageproxy.Proxy
+	ImageMaxAge   time.Duration
+	Reports       *report.Scheduler
+	Mailer        *email.Mailer
+	Analytics     *analytics.Aggregator
+	Payments      *payments.Registry
+	Groups        *hub.Hub[models.GroupUpdate]
+	Statuses      *hub.Hub[models.OrderStatus]
+	GroupURL      string
+	WebURL        string
+	LinkURL       string
+	MenuURL       string
+	MealPlans     *mealplan.Scheduler
+	Stock         *stock.Manager
+	Moderator     *moderation.Moderator
+	Site          *seo.Site
+	RBAC          *middleware.RBAC
+	Shedder       *middleware.Shedder
+	Classes       *middleware.Classes
+	UserLimits    *middleware.UserLimits
+	Abuse         *abuse.Detector
+	Fraud         *fraud.Screener
+	Invites       *invitation.Signer
+	SMS           *sms.Sender
+	// SMSOrderStatus texts customers when their order's status changes.
+	SMSOrderStatus bool
+	OTPTTL         time.Duration
+	OTPResendWait  time.Duration
+	Broadcasts     *broadcast.Scheduler
+	Routes         *routing.Router
+	// PrepTime is how long kitchens without a lead time of their own
+	// take to prepare an order.
+	PrepTime    time.Duration
+	OrderTimers *ordertimer.Scheduler
+	Edge        *cdn.Edge
+	// Encoded keeps the JSON of kitchens for renderKitchens.
+	Encoded *encoded.Cache
+	// AlertEmails are emailed about chargebacks.
+	AlertEmails []string
+	Settings    *settings.Manager
+	SLO         *slo.Tracker
+	Calls       *middleware.CallLog
+	// Spec is the OpenAPI spec of the routes, set once they are registered.
+	Spec      []byte
+	Usage     *usage.Recorder
+	TimeZones *timezone.Zones
+	Retention *retention.Enforcer
+	// DataExports keeps the packages of data requests for DataRequestTTL.
+	DataExports    *dataexport.Store
+	DataRequestTTL time.Duration
+	// Encryption is nil unless personal data is encrypted at rest.
+	Encryption *envelope.Keyring
+	// StreamMinItems is how long a list response must be to be streamed,
+	// or 0 to never stream.
+	StreamMinItems int
+	PartnerKeys    *middleware.PartnerKeys
+	Catalog        *catalog.Tracker
+	Recorder       *recording.Recorder
+	// RecordingMax is the longest recording admins may start.
+	RecordingMax time.Duration
 }
 
-func NewHandler(cfg *config.Config) *Handler {
-	return &Handler{
-		UserClient:    pkg.NewUserClient(cfg),
-		KitchenClient: pkg.NewKitchenClient(cfg),
-		DishClient:    pkg.NewDishClient(cfg),
-		OrderClient:   pkg.NewOrderClient(cfg),
-		ReviewClient:  pkg.NewReviewClient(cfg),
-		PaymentClient: pkg.NewPaymentClient(cfg),
-		ExtraClient:   pkg.NewExtraClient(cfg),
-		Logger:        logger.NewLogger(),
+// NewHandler connects to the services with opts added to the gateway's
+// own dial options, which lets tests stand in for the services.
+func NewHandler(cfg *config.Config, opts ...grpc.DialOption) *Handler {
+	log := logger.NewLogger()
+	store := storage.New()
+	seedCuisines(store)
+	seedRoles(store)
+
+	// Personal data must not be kept in the clear when encryption is
+	// configured, so a key that can't be loaded stops the gateway.
+	keys, err := envelope.New(cfg)
+	if err != nil {
+		log.Error(errors.Wrap(err, "error loading encryption keys").Error())
+		os.Exit(1)
+	}
+	if keys != nil {
+		err = store.Seal(keys, func(err error) {
+			log.Error(err.Error())
+		})
+		if err != nil {
+			log.Error(errors.Wrap(err, "error sealing storage").Error())
+			os.Exit(1)
+		}
 	}
+
+	// The shedder times the calls to the services to tell when they are
+	// overloaded.
+	shedder := middleware.NewShedder(cfg.SHED_MAX_IN_FLIGHT, cfg.SHED_MAX_LATENCY)
+	// The tracker measures them against the backends' SLOs.
+	tracker := slo.NewTracker(cfg)
+	calls := middleware.NewCallLog(log, cfg.DEBUG_TIMING, cfg.SERVER_TIMING)
+	observe := grpc.WithChainUnaryInterceptor(shedder.Observe, tracker.Observe, calls.Observe,
+		middleware.ForwardLocale)
+	streams := grpc.WithChainStreamInterceptor(calls.ObserveStream, middleware.ForwardLocaleStream)
+	dial := append([]grpc.DialOption{observe, streams}, opts...)
+
+	kitchens := pkg.NewKitchenClient(cfg, dial...)
+	dishes := pkg.NewDishClient(cfg, dial...)
+	orders := pkg.NewOrderClient(cfg, dial...)
+	extra := pkg.NewExtraClient(cfg, dial...)
+	pays := pkg.NewPaymentClient(cfg, dial...)
+	webhooks := webhook.NewDispatcher(store, log)
+	mailer := email.NewMailer(cfg, store.Emails, log)
+
+	h := &Handler{
+		UserClient:    pkg.NewUserClient(cfg, dial...),
+		KitchenClient: kitchens,
+		DishClient:    dishes,
+		OrderClient:   orders,
+		ReviewClient:  pkg.NewReviewClient(cfg, dial...),
+		PaymentClient: pays,
+		ExtraClient:   extra,
+		Logger:        log,
+		Storage:       store,
+		Pricing:       pricing.NewCalculator(cfg),
+		Webhooks:      webhooks,
+		Events:        events.NewEmitter(cfg, keys, log),
+		Marshaler:     newMarshaler(cfg),
+		APIFormat:     cfg.DEFAULT_API_FORMAT,
+		Envelope:      cfg.RESPONSE_ENVELOPE,
+		Uploads:       upload.NewManager(cfg.UPLOAD_DIR, cfg.UPLOAD_MAX_SIZE, store.Uploads, keys),
+		Images:        imageproxy.NewProxy(cfg.IMAGE_STORAGE_URL, cfg.IMAGE_MAX_DIMENSION),
+		ImageMaxAge:   cfg.IMAGE_CACHE_MAX_AGE,
+		Reports: report.NewScheduler(extra, store, webhooks, mailer, keys,
+			cfg.REPORT_DIR, cfg.REPORT_CHECK_INTERVAL, log),
+		Mailer: mailer,
+		Analytics: analytics.NewAggregator(kitchens, orders, extra, store.SandboxOrders,
+			cfg.ANALYTICS_CACHE_TTL, cfg.ANALYTICS_CONCURRENCY),
+		Payments: payments.NewRegistry(cfg, pays),
+		Groups:   hub.New[models.GroupUpdate](),
+		Statuses: hub.New[models.OrderStatus](),
+		GroupURL: cfg.GROUP_ORDER_URL,
+		WebURL:   cfg.WEB_APP_URL,
+		LinkURL:  cfg.SHORT_LINK_URL,
+		MenuURL:  cfg.MENU_URL,
+		Site:     seo.NewSite(kitchens, dishes, cfg.WEB_APP_URL, cfg.SEO_CACHE_TTL),
+	}
+	// The default zone was checked by config.Load.
+	zone, _ := time.LoadLocation(cfg.DEFAULT_TIME_ZONE)
+	h.TimeZones = timezone.NewZones(store.KitchenTimeZones, zone)
+	// Meal plan orders go through the handler's order pipeline.
+	h.MealPlans = mealplan.NewScheduler(store, h.placeScheduledOrder, h.mealPlanFailed,
+		h.TimeZones.Of, cfg.MEAL_PLAN_LEAD_TIME, cfg.MEAL_PLAN_CHECK_INTERVAL, log)
+	h.Stock = stock.NewManager(store.DishStocks, h.restockDishes, log)
+	h.Moderator = moderation.NewModerator(cfg, log)
+	h.RBAC = middleware.NewRBAC(store.Roles)
+	h.Shedder = shedder
+	h.SLO = tracker
+	h.Calls = calls
+	h.Classes = middleware.NewClasses(map[string]int{
+		middleware.ClassCheckout:    cfg.CLASS_CHECKOUT_LIMIT,
+		middleware.ClassOrderStatus: cfg.CLASS_ORDER_STATUS_LIMIT,
+		middleware.ClassBrowse:      cfg.CLASS_BROWSE_LIMIT,
+		middleware.ClassAnalytics:   cfg.CLASS_ANALYTICS_LIMIT,
+	})
+	h.UserLimits = middleware.NewUserLimits(cfg.USER_MAX_IN_FLIGHT)
+	h.Abuse = abuse.NewDetector(cfg, store.AbuseProfiles, log)
+	h.Fraud = fraud.NewScreener(cfg, store.FraudHistory, log)
+	h.Settings = settings.NewManager(cfg, store.Settings, store.SettingsHistory, log)
+	h.Usage = usage.NewRecorder(store.APIUsage, cfg.USAGE_RETENTION_DAYS)
+	h.Retention = retention.NewEnforcer(store, pkg.NewRetentionClient(cfg, dial...),
+		cfg.RETENTION_ENABLED, cfg.RETENTION_RECORD_DAYS, cfg.RETENTION_LOG_DAYS,
+		cfg.RETENTION_CHECK_INTERVAL, log)
+	h.DataExports = dataexport.NewStore(cfg.DATA_REQUEST_DIR)
+	h.DataRequestTTL = cfg.DATA_REQUEST_TTL
+	h.Encryption = keys
+	h.StreamMinItems = cfg.RESPONSE_STREAM_MIN_ITEMS
+	h.PartnerKeys = middleware.NewPartnerKeys(store.PartnerKeys)
+	h.Catalog = catalog.NewTracker(store.CatalogChanges, h.Events, cfg.PARTNER_DELTA_RETENTION_DAYS)
+	h.Recorder = recording.NewRecorder(store.Recordings, store.RecordedRequests,
+		cfg.RECORDING_MAX_REQUESTS, cfg.RECORDING_MAX_BODY)
+	h.RecordingMax = time.Duration(cfg.RECORDING_MAX_MINUTES) * time.Minute
+	// Orders are priced with the fees admins set.
+	h.Settings.OnChange(func(s models.Settings) { h.Pricing.SetFees(s.Fees) })
+	for _, to := range strings.Split(cfg.ADMIN_ALERT_EMAILS, ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			h.AlertEmails = append(h.AlertEmails, to)
+		}
+	}
+	h.Invites = invitation.NewSigner(cfg.INVITATION_SECRET, cfg.INVITATION_TTL)
+	h.SMS = sms.NewSender(cfg, store.SMSMessages, log)
+	h.SMSOrderStatus = cfg.SMS_ORDER_STATUS
+	h.OTPTTL, h.OTPResendWait = cfg.OTP_TTL, cfg.OTP_RESEND_WAIT
+	h.Broadcasts = broadcast.NewScheduler(store, h.sendBroadcast, cfg.BROADCAST_RATE,
+		cfg.BROADCAST_CHECK_INTERVAL, log)
+	h.Routes = routing.NewRouter(cfg, log)
+	h.PrepTime = cfg.DEFAULT_PREP_TIME
+	h.OrderTimers = ordertimer.NewScheduler(store.PendingOrders, h.respondToOrder,
+		cfg.ORDER_TIMER_CHECK_INTERVAL, log)
+	h.Site.StartWarming(cfg.WARM_KITCHENS, cfg.WARM_INTERVAL, log)
+	h.Edge = cdn.NewEdge(cfg, log)
+	h.Encoded = encoded.NewCache()
+
+	return h
 }
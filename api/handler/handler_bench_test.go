@@ -0,0 +1,98 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/kitchen"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+func newBenchHandler(envelope bool) *Handler {
+	return &Handler{
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		APIFormat: formatLegacy,
+		Envelope:  envelope,
+	}
+}
+
+func benchContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, w
+}
+
+func benchKitchens(n int) *pb.Kitchens {
+	res := &pb.Kitchens{Total: int32(n * 3), Page: 1, Limit: int32(n)}
+	for i := range n {
+		res.Kitchens = append(res.Kitchens, &pb.KitchenDetails{
+			Id:          uuid.NewString(),
+			Name:        "Kitchen " + strconv.Itoa(i),
+			CuisineType: "uzbek",
+			Rating:      4.5,
+			TotalOrders: int32(i * 10),
+		})
+	}
+	return res
+}
+
+// BenchmarkRender encodes a page of kitchens as the list endpoints do,
+// in the envelope and bare.
+func BenchmarkRender(b *testing.B) {
+	gin.SetMode(gin.ReleaseMode)
+	res := benchKitchens(20)
+
+	for _, envelope := range []bool{true, false} {
+		name := "bare"
+		if envelope {
+			name = "envelope"
+		}
+		b.Run(name, func(b *testing.B) {
+			h := newBenchHandler(envelope)
+			b.ReportAllocs()
+			for range b.N {
+				c, _ := benchContext("/local-eats/kitchens?page=1&limit=20")
+				h.render(c, http.StatusOK, res)
+			}
+		})
+	}
+}
+
+// BenchmarkServe proxies a call answered at once, which leaves what the
+// gateway itself spends binding, logging and rendering.
+func BenchmarkServe(b *testing.B) {
+	gin.SetMode(gin.ReleaseMode)
+	h := newBenchHandler(true)
+	id := uuid.NewString()
+	kitchen := &pb.Info{Id: id, Name: "Kitchen", CuisineType: "uzbek", Rating: 4.5}
+
+	p := Proxy[*pb.ID, *pb.Info]{
+		Name: "GetKitchen",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "kitchen id")
+			return &pb.ID{Id: id}, err
+		},
+		Call: func(ctx context.Context, in *pb.ID, opts ...grpc.CallOption) (*pb.Info, error) {
+			return kitchen, nil
+		},
+		Error: "error getting kitchen",
+	}
+
+	b.ReportAllocs()
+	for range b.N {
+		c, w := benchContext("/local-eats/kitchens/" + id)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		serve(h, c, p)
+		if w.Code != http.StatusOK {
+			b.Fatalf("answered %d: %s", w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/cdn"
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxInspectionAuthority = 200
+	maxInspectionNotes     = 2000
+	maxInspectionDocuments = 10
+)
+
+// GetKitchenInspections godoc
+// @Summary Gets kitchen hygiene inspections
+// @Description Lists the kitchen's hygiene inspections, latest first, with its current rating, the score of the latest one. Rating is null for kitchens not inspected yet
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.KitchenInspections
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Router /kitchens/{id}/inspections [get]
+func (h *Handler) GetKitchenInspections(c *gin.Context) {
+	h.Logger.Info("GetKitchenInspections method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res := models.KitchenInspections{
+		KitchenId:   kitchenID,
+		Rating:      h.hygieneRating(kitchenID),
+		Inspections: h.kitchenInspections(kitchenID),
+	}
+
+	h.Logger.Info("GetKitchenInspections method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// CreateInspection godoc
+// @Summary Records a hygiene inspection
+// @Description Attaches an inspection's score and its documents, uploaded with the inspection_report purpose, to a kitchen. The latest inspection sets the hygiene_rating shown with the kitchen
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param inspection body models.NewInspection true "Inspection"
+// @Success 200 {object} models.Inspection
+// @Failure 400 {object} string "Invalid inspection data"
+// @Router /admin/inspections [post]
+func (h *Handler) CreateInspection(c *gin.Context) {
+	h.Logger.Info("CreateInspection method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewInspection
+	var docs []models.InspectionDocument
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		docs, err = h.validateInspection(c, &data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid inspection data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	in := models.Inspection{
+		Id:          uuid.NewString(),
+		KitchenId:   data.KitchenId,
+		InspectedAt: data.InspectedAt,
+		Score:       data.Score,
+		Authority:   data.Authority,
+		Notes:       data.Notes,
+		Documents:   docs,
+		RecordedBy:  userID,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	}
+	h.Storage.Inspections.Set(in.Id, in)
+	h.rateHygiene(in.KitchenId)
+
+	h.Logger.Info("CreateInspection method has finished successfully")
+	h.render(c, http.StatusOK, in)
+}
+
+// DeleteInspection godoc
+// @Summary Deletes a hygiene inspection
+// @Description Removes an inspection recorded in error. The kitchen's rating falls back to its previous inspection
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Inspection ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid inspection ID"
+// @Failure 404 {object} string "Inspection not found"
+// @Router /admin/inspections/{id} [delete]
+func (h *Handler) DeleteInspection(c *gin.Context) {
+	h.Logger.Info("DeleteInspection method is starting")
+
+	id, err := pathUUID(c, "id", "inspection id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	in, ok := h.Storage.Inspections.Get(id)
+	if !ok || !h.Storage.Inspections.Delete(id) {
+		er := "inspection not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	h.rateHygiene(in.KitchenId)
+
+	h.Logger.Info("DeleteInspection method has finished successfully")
+	h.render(c, http.StatusOK, "Inspection deleted successfully")
+}
+
+// validateInspection checks the inspection and returns its documents.
+func (h *Handler) validateInspection(c *gin.Context, data *models.NewInspection) ([]models.InspectionDocument, error) {
+	if _, err := uuid.Parse(data.KitchenId); err != nil {
+		return nil, errors.Wrap(err, "invalid kitchen id")
+	}
+	inspected, err := time.Parse("2006-01-02", data.InspectedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid inspection date")
+	}
+	if inspected.After(time.Now()) {
+		return nil, errors.New("inspection date must not be in the future")
+	}
+	if data.Score < 0 || data.Score > models.MaxHygieneScore {
+		return nil, errors.Errorf("score must be between 0 and %d", models.MaxHygieneScore)
+	}
+	data.Authority = strings.TrimSpace(data.Authority)
+	data.Notes = strings.TrimSpace(data.Notes)
+	switch {
+	case data.Authority == "":
+		return nil, errors.New("authority is required")
+	case utf8.RuneCountInString(data.Authority) > maxInspectionAuthority:
+		return nil, errors.Errorf("authority must be at most %d characters", maxInspectionAuthority)
+	case utf8.RuneCountInString(data.Notes) > maxInspectionNotes:
+		return nil, errors.Errorf("notes must be at most %d characters", maxInspectionNotes)
+	case len(data.UploadIds) > maxInspectionDocuments:
+		return nil, errors.Errorf("at most %d documents are allowed", maxInspectionDocuments)
+	}
+
+	docs := []models.InspectionDocument{}
+	for _, id := range data.UploadIds {
+		u, err := h.ownUpload(c, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "upload %s", id)
+		}
+		if u.Purpose != models.UploadInspection || u.Status != models.UploadCompleted {
+			return nil, errors.Errorf("upload %s must be a completed %s upload", id, models.UploadInspection)
+		}
+		docs = append(docs, models.InspectionDocument{UploadId: u.Id, Filename: u.Filename})
+	}
+	return docs, nil
+}
+
+// kitchenInspections returns the kitchen's inspections, latest first.
+func (h *Handler) kitchenInspections(kitchenID string) []models.Inspection {
+	res := []models.Inspection{}
+	for _, in := range h.Storage.Inspections.List() {
+		if in.KitchenId == kitchenID {
+			res = append(res, in)
+		}
+	}
+	slices.SortFunc(res, func(a, b models.Inspection) int {
+		return cmp.Or(strings.Compare(b.InspectedAt, a.InspectedAt), strings.Compare(b.CreatedAt, a.CreatedAt))
+	})
+	return res
+}
+
+// rateHygiene sets the kitchen's rating from its latest inspection. The
+// rating is kept apart so kitchen responses don't go through every
+// inspection.
+func (h *Handler) rateHygiene(kitchenID string) {
+	if list := h.kitchenInspections(kitchenID); len(list) > 0 {
+		h.Storage.HygieneRatings.Set(kitchenID, models.HygieneRating{
+			Score:        list[0].Score,
+			InspectedAt:  list[0].InspectedAt,
+			Authority:    list[0].Authority,
+			InspectionId: list[0].Id,
+		})
+	} else {
+		h.Storage.HygieneRatings.Delete(kitchenID)
+	}
+	h.Edge.Purge(cdn.KitchenKey(kitchenID))
+}
+
+// hygieneRating returns the kitchen's rating, nil if it was never
+// inspected.
+func (h *Handler) hygieneRating(kitchenID string) *models.HygieneRating {
+	if r, ok := h.Storage.HygieneRatings.Get(kitchenID); ok {
+		return &r
+	}
+	return nil
+}
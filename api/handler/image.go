@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	"api-gateway/pkg/imageproxy"
+	"cmp"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetImage godoc
+// @Summary Gets an image
+// @Description Serves an image from object storage, optionally resized to fit width x height and converted to another format. The Android Go app, sending X-Platform android-go, gets images of at most 480 pixels a side, as WebP unless it asks for another format. Images of more than 25 megapixels are not resized
+// @Tags image
+// @Produce image/jpeg,image/png,image/webp
+// @Param key path string true "Object key, e.g. dishes/{id}.jpg"
+// @Param width query int false "Maximum width in pixels"
+// @Param height query int false "Maximum height in pixels"
+// @Param format query string false "Output format" Enums(jpeg, png, webp)
+// @Success 200 {file} file
+// @Success 304 {object} string "Not modified"
+// @Failure 400 {object} string "Invalid image parameters or image too large to resize"
+// @Failure 404 {object} string "Image not found"
+// @Failure 502 {object} string "Object storage error"
+// @Router /images/{key} [get]
+func (h *Handler) GetImage(c *gin.Context) {
+	h.Logger.Info("GetImage method is starting")
+
+	opts := imageproxy.Options{Format: c.Query("format")}
+	var err error
+	opts.Width, err = queryInt(c, "width")
+	if err == nil {
+		opts.Height, err = queryInt(c, "height")
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// Devices of the lite profile get smaller images, unless they ask for
+	// smaller still.
+	if p := middleware.ProfileOf(c); p.MaxImageDimension > 0 {
+		limit := min(p.MaxImageDimension, h.Images.MaxDimension())
+		opts.Width = min(cmp.Or(opts.Width, limit), limit)
+		opts.Height = min(cmp.Or(opts.Height, limit), limit)
+		opts.Format = cmp.Or(opts.Format, p.ImageFormat)
+	}
+	c.Header("Vary", "X-Platform")
+
+	img, err := h.Images.Fetch(c.Request.Context(), c.Param("key"), opts)
+	if err != nil {
+		status := http.StatusBadGateway
+		switch errors.Cause(err) {
+		case imageproxy.ErrInvalid:
+			status = http.StatusBadRequest
+		case imageproxy.ErrNotFound:
+			status = http.StatusNotFound
+		}
+		er := errors.Wrap(err, "error getting image").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.ImageMaxAge.Seconds())))
+	c.Header("ETag", img.ETag)
+	if img.LastModified != "" {
+		c.Header("Last-Modified", img.LastModified)
+	}
+
+	h.Logger.Info("GetImage method has finished successfully")
+	if c.GetHeader("If-None-Match") == img.ETag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, img.ContentType, img.Data)
+}
+
+// queryInt reads an optional integer query parameter, zero when absent.
+func queryInt(c *gin.Context, name string) (int, error) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid "+name)
+	}
+	return n, nil
+}
@@ -0,0 +1,311 @@
+package handler
+
+import (
+	pbk "api-gateway/genproto/kitchen"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/email"
+	"api-gateway/pkg/invitation"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// CreateInvitation godoc
+// @Summary Invites a kitchen owner or staff
+// @Description Emails a registration link with a signed token that expires. Opening it pre-fills registration; after signing in the invitee accepts it, which for staff invitations joins the kitchen's staff
+// @Tags invitation
+// @Security ApiKeyAuth
+// @Param invitation body models.NewInvitation true "Invitation"
+// @Success 200 {object} models.CreatedInvitation
+// @Failure 400 {object} string "Invalid invitation data"
+// @Failure 404 {object} string "Kitchen not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/invitations [post]
+func (h *Handler) CreateInvitation(c *gin.Context) {
+	h.Logger.Info("CreateInvitation method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewInvitation
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateInvitation(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid invitation data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	inv := models.Invitation{
+		Id:          uuid.NewString(),
+		Email:       data.Email,
+		FullName:    data.FullName,
+		Type:        data.Type,
+		KitchenId:   data.KitchenId,
+		Permissions: data.Permissions,
+		Status:      models.InvitationPending,
+		InvitedBy:   userID,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	}
+
+	if inv.Type == models.InviteStaff {
+		ctx, cancel := callContext(c, defaultTimeout)
+		defer cancel()
+
+		name, err := h.KitchenClient.GetName(ctx, &pbk.ID{Id: inv.KitchenId})
+		if err != nil {
+			status, _ := errorStatus(err)
+			er := errors.Wrap(err, "error getting kitchen").Error()
+			c.AbortWithStatusJSON(status,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+		inv.KitchenName = name.Name
+	}
+
+	token, expires, err := h.Invites.Sign(inv.Id, inv.Email)
+	if err != nil {
+		er := errors.Wrap(err, "error signing invitation").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	inv.ExpiresAt = expires.Format(time.RFC3339)
+	h.Storage.Invitations.Set(inv.Id, inv)
+
+	res := models.CreatedInvitation{
+		Invitation: inv,
+		Token:      token,
+		Url: strings.TrimSuffix(h.WebURL, "/") + "/register?" +
+			url.Values{"invitation": {token}}.Encode(),
+	}
+	h.emailInvitation(res, requestLang(c))
+
+	h.Logger.Info("CreateInvitation method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// FetchInvitations godoc
+// @Summary Gets invitations
+// @Description Lists invitations, newest first, optionally by status
+// @Tags invitation
+// @Security ApiKeyAuth
+// @Param status query string false "pending or accepted"
+// @Success 200 {object} models.Invitations
+// @Router /admin/invitations [get]
+func (h *Handler) FetchInvitations(c *gin.Context) {
+	h.Logger.Info("FetchInvitations method is starting")
+
+	status := c.Query("status")
+	res := models.Invitations{Invitations: []models.Invitation{}}
+	for _, inv := range h.Storage.Invitations.List() {
+		if status == "" || inv.Status == status {
+			res.Invitations = append(res.Invitations, inv)
+		}
+	}
+	slices.SortFunc(res.Invitations, func(a, b models.Invitation) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchInvitations method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetInvitation godoc
+// @Summary Opens an invitation
+// @Description Checks an invitation token and returns the details to pre-fill registration with. No sign-in is needed
+// @Tags invitation
+// @Param token path string true "Invitation token"
+// @Success 200 {object} models.InvitationPrefill
+// @Failure 404 {object} string "Invalid invitation"
+// @Failure 409 {object} string "Invitation already accepted"
+// @Failure 410 {object} string "Invitation expired"
+// @Router /invitations/{token} [get]
+func (h *Handler) GetInvitation(c *gin.Context) {
+	h.Logger.Info("GetInvitation method is starting")
+
+	inv, ok := h.pendingInvitation(c)
+	if !ok {
+		return
+	}
+
+	res := models.InvitationPrefill{
+		Email:       inv.Email,
+		FullName:    inv.FullName,
+		Type:        inv.Type,
+		KitchenId:   inv.KitchenId,
+		KitchenName: inv.KitchenName,
+		Permissions: inv.Permissions,
+		ExpiresAt:   inv.ExpiresAt,
+	}
+
+	h.Logger.Info("GetInvitation method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// AcceptInvitation godoc
+// @Summary Accepts an invitation
+// @Description Marks the invitation used by the signed-in user, whose email must be the invited one. Staff invitations add the user to the kitchen's staff
+// @Tags invitation
+// @Security ApiKeyAuth
+// @Param token path string true "Invitation token"
+// @Success 200 {object} models.Invitation
+// @Failure 403 {object} string "Invitation is for another email"
+// @Failure 404 {object} string "Invalid invitation"
+// @Failure 409 {object} string "Invitation already accepted"
+// @Failure 410 {object} string "Invitation expired"
+// @Router /invitations/{token}/accept [post]
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	h.Logger.Info("AcceptInvitation method is starting")
+
+	userID, ok := h.user(c)
+	if !ok {
+		return
+	}
+
+	inv, ok := h.pendingInvitation(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	profile, err := h.UserClient.GetProfile(ctx, &pbu.ID{Id: userID})
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting user").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	if !strings.EqualFold(profile.Email, inv.Email) {
+		er := "invitation is for another email"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// Accepting is checked and recorded in one update, so a token can
+	// only be used once.
+	var accepted bool
+	now := time.Now().Format(time.RFC3339)
+	inv = h.Storage.Invitations.Update(inv.Id, func(inv models.Invitation, _ bool) models.Invitation {
+		if inv.Status != models.InvitationPending {
+			return inv
+		}
+		accepted = true
+		inv.Status = models.InvitationAccepted
+		inv.AcceptedBy = userID
+		inv.AcceptedAt = now
+		return inv
+	})
+	if !accepted {
+		er := "invitation was already accepted"
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if inv.Type == models.InviteStaff {
+		m := models.StaffMember{
+			KitchenId:   inv.KitchenId,
+			KitchenName: inv.KitchenName,
+			UserId:      userID,
+			Username:    profile.Username,
+			Permissions: inv.Permissions,
+			Status:      models.StaffActive,
+			InvitedBy:   inv.InvitedBy,
+			InvitedAt:   inv.CreatedAt,
+			JoinedAt:    now,
+		}
+		h.Storage.KitchenStaff.Update(inv.KitchenId, func(staff []models.StaffMember, _ bool) []models.StaffMember {
+			staff = slices.DeleteFunc(slices.Clone(staff), func(s models.StaffMember) bool {
+				return s.UserId == userID
+			})
+			return append(staff, m)
+		})
+	}
+
+	h.Logger.Info("AcceptInvitation method has finished successfully")
+	h.render(c, http.StatusOK, inv)
+}
+
+// pendingInvitation finds the invitation of the token path parameter,
+// refusing bad, expired and used tokens.
+func (h *Handler) pendingInvitation(c *gin.Context) (models.Invitation, bool) {
+	id, err := h.Invites.Parse(c.Param("token"))
+	inv, found := h.Storage.Invitations.Get(id)
+
+	status, er := http.StatusNotFound, "invitation not found"
+	switch {
+	case errors.Is(err, invitation.ErrExpired):
+		status, er = http.StatusGone, err.Error()
+	case err != nil:
+		er = err.Error()
+	case !found:
+	case inv.Status != models.InvitationPending:
+		status, er = http.StatusConflict, "invitation was already accepted"
+	default:
+		return inv, true
+	}
+
+	c.AbortWithStatusJSON(status,
+		gin.H{"error": er})
+	h.Logger.Error(er)
+	return models.Invitation{}, false
+}
+
+// validateInvitation checks the invitation and normalizes its email.
+func validateInvitation(data *models.NewInvitation) error {
+	addr, err := mail.ParseAddress(strings.TrimSpace(data.Email))
+	if err != nil {
+		return errors.Wrap(err, "invalid email")
+	}
+	data.Email = strings.ToLower(addr.Address)
+	data.FullName = strings.TrimSpace(data.FullName)
+
+	switch data.Type {
+	case models.InviteKitchenOwner:
+		data.KitchenId, data.Permissions = "", []string{}
+	case models.InviteStaff:
+		if _, err := uuid.Parse(data.KitchenId); err != nil {
+			return errors.Wrap(err, "invalid kitchen id")
+		}
+		data.Permissions, err = staffPermissions(data.Permissions)
+		return err
+	default:
+		return errors.Errorf("type must be %s or %s", models.InviteKitchenOwner, models.InviteStaff)
+	}
+	return nil
+}
+
+// emailInvitation emails the invitation in the language of the inviter.
+func (h *Handler) emailInvitation(inv models.CreatedInvitation, lang string) {
+	data := email.InvitationData{FullName: inv.FullName, Url: inv.Url, ExpiresAt: inv.ExpiresAt}
+	if inv.Type == models.InviteStaff {
+		data.KitchenName = inv.KitchenName
+	}
+	if _, err := h.Mailer.Queue(inv.Email, lang, models.EmailInvitation, data); err != nil {
+		h.Logger.Error(errors.Wrapf(err, "error emailing invitation %s", inv.Id).Error())
+	}
+}
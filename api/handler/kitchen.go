@@ -2,19 +2,17 @@ package handler
 
 import (
 	pb "api-gateway/genproto/kitchen"
-	"context"
-	"net/http"
+	"api-gateway/models"
+	"api-gateway/pkg/cdn"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
 
 // CreateKitchen godoc
 // @Summary Creates a kitchen
-// @Description Inserts a new kitchen into database
+// @Description Inserts a new kitchen into database. The cuisine type must be one of GET /cuisines and is saved under its canonical name
 // @Tags kitchen
 // @Security ApiKeyAuth
 // @Param kitchen body kitchen.CreateRequest true "Kitchen info"
@@ -23,70 +21,51 @@ import (
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /kitchens [post]
 func (h *Handler) CreateKitchen(c *gin.Context) {
-	h.Logger.Info("CreateKitchen method is starting")
-	var data pb.CreateRequest
-
-	if err := c.ShouldBindJSON(&data); err != nil {
-		er := errors.Wrap(err, "invalid kitchen data").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.KitchenClient.Create(ctx, &data)
-	if err != nil {
-		er := errors.Wrap(err, "error creating kitchen").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("CreateKitchen method has finished successfully")
-	c.JSON(http.StatusOK, res)
+	serve(h, c, Proxy[*pb.CreateRequest, *pb.CreateResponse]{
+		Name: "CreateKitchen",
+		Bind: func(c *gin.Context) (*pb.CreateRequest, error) {
+			req, err := bindJSON[pb.CreateRequest](c, "kitchen data")
+			if err != nil {
+				return nil, err
+			}
+
+			req.CuisineType, err = h.canonicalCuisine(req.CuisineType)
+			return req, errors.Wrap(err, "invalid kitchen data")
+		},
+		Call:  h.KitchenClient.Create,
+		Error: "error creating kitchen",
+		After: func(res *pb.CreateResponse) {
+			h.trackCatalog(models.EventKitchenCreated, models.CatalogKitchen, res.Id, res.Id, res)
+			h.notifySavedSearches(res, false)
+		},
+	})
 }
 
 // GetKitchen godoc
 // @Summary Gets a kitchen
-// @Description Retrieves kitchen info from database
+// @Description Retrieves kitchen info from database, with the gateway's verified badge, the hygiene_rating of the latest inspection, null if never inspected, and the kitchen's active announcements, banners first. The response may be cached at the edge under the surrogate key kitchen:{id}
 // @Tags kitchen
 // @Security ApiKeyAuth
 // @Param id path string true "Kitchen ID"
 // @Success 200 {object} kitchen.Info
 // @Failure 400 {object} string "Invalid kitchen ID"
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
 // @Router /kitchens/{id} [get]
 func (h *Handler) GetKitchen(c *gin.Context) {
-	h.Logger.Info("GetKitchen method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid kitchen id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	kitchen, err := h.KitchenClient.Get(ctx, &pb.ID{Id: id})
-	if err != nil {
-		er := errors.Wrap(err, "error getting kitchen").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("GetKitchen method has finished successfully")
-	c.JSON(http.StatusOK, kitchen)
+	serve(h, c, Proxy[*pb.ID, *pb.Info]{
+		Name: "GetKitchen",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "kitchen id")
+			return &pb.ID{Id: id}, err
+		},
+		Call:  h.KitchenClient.Get,
+		Error: "error getting kitchen",
+		Render: func(c *gin.Context, res *pb.Info) {
+			h.tagEdge(c, cdn.KitchenKey(res.Id))
+			h.renderKitchens(c, res)
+		},
+	})
 }
 
 // UpdateKitchen godoc
@@ -101,46 +80,33 @@ func (h *Handler) GetKitchen(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /kitchens/{id} [put]
 func (h *Handler) UpdateKitchen(c *gin.Context) {
-	h.Logger.Info("UpdateKitchen method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid kitchen id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	var data pb.NewDataNoID
-	if err := c.ShouldBindJSON(&data); err != nil {
-		er := errors.Wrap(err, "invalid kitchen data").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.KitchenClient.Update(ctx, &pb.NewData{
-		Id:          id,
-		Name:        data.Name,
-		Description: data.Description,
-		PhoneNumber: data.PhoneNumber,
+	serve(h, c, Proxy[*pb.NewData, *pb.UpdatedData]{
+		Name: "UpdateKitchen",
+		Bind: func(c *gin.Context) (*pb.NewData, error) {
+			id, err := pathUUID(c, "id", "kitchen id")
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := bindJSON[pb.NewDataNoID](c, "kitchen data")
+			if err != nil {
+				return nil, err
+			}
+
+			return &pb.NewData{
+				Id:          id,
+				Name:        data.Name,
+				Description: data.Description,
+				PhoneNumber: data.PhoneNumber,
+			}, nil
+		},
+		Call:  h.KitchenClient.Update,
+		Error: "error updating kitchen",
+		After: func(res *pb.UpdatedData) {
+			h.trackCatalog(models.EventKitchenUpdated, models.CatalogKitchen, res.Id, res.Id, res)
+			h.Edge.Purge(cdn.KitchenKey(res.Id))
+		},
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error updating kitchen").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("UpdateKitchen method has finished successfully")
-	c.JSON(http.StatusOK, res)
 }
 
 // DeleteKitchen godoc
@@ -154,90 +120,51 @@ func (h *Handler) UpdateKitchen(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /kitchens/{id} [delete]
 func (h *Handler) DeleteKitchen(c *gin.Context) {
-	h.Logger.Info("DeleteKitchen method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid kitchen id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	_, err = h.KitchenClient.Delete(ctx, &pb.ID{Id: id})
-	if err != nil {
-		er := errors.Wrap(err, "error deleting kitchen").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("DeleteKitchen method has finished successfully")
-	c.JSON(http.StatusOK, "Kitchen deleted successfully")
+	serve(h, c, Proxy[*pb.ID, *pb.Void]{
+		Name: "DeleteKitchen",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "kitchen id")
+			return &pb.ID{Id: id}, err
+		},
+		Call:  h.KitchenClient.Delete,
+		Error: "error deleting kitchen",
+		After: func(*pb.Void) {
+			h.trackCatalog(models.EventKitchenDeleted, models.CatalogKitchen, c.Param("id"), c.Param("id"), nil)
+			h.Edge.Purge(cdn.KitchenKey(c.Param("id")))
+		},
+		Message: "Kitchen deleted successfully",
+	})
 }
 
 // FetchKitchens godoc
 // @Summary Fetches all kitchens
-// @Description Fetches all kitchens from database
+// @Description Fetches all kitchens from database, with the gateway's verified badge. Kitchens the caller blocked are left out, so a page may hold fewer items than the limit
 // @Tags kitchen
 // @Security ApiKeyAuth
 // @Param page query int true "Page number"
 // @Param limit query int true "Number of items per page"
 // @Success 200 {object} kitchen.Kitchens
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
 // @Router /kitchens [get]
 func (h *Handler) FetchKitchens(c *gin.Context) {
-	h.Logger.Info("FetchKitchens method is starting")
-
-	page := c.Query("page")
-	limit := c.Query("limit")
-
-	p, err := strconv.Atoi(page)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	l, err := strconv.Atoi(limit)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.KitchenClient.Fetch(ctx, &pb.Pagination{
-		Limit:  int32(l),
-		Offset: int32((p - 1) * l),
+	serve(h, c, Proxy[*pb.Pagination, *pb.Kitchens]{
+		Name: "FetchKitchens",
+		Bind: func(c *gin.Context) (*pb.Pagination, error) {
+			limit, offset, err := pagination(c)
+			return &pb.Pagination{Limit: limit, Offset: offset}, err
+		},
+		Call:  h.KitchenClient.Fetch,
+		Error: "error fetching kitchens",
+		Render: func(c *gin.Context, res *pb.Kitchens) {
+			h.renderKitchens(c, res)
+		},
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error fetching kitchens").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("FetchKitchens method has finished successfully")
-	c.JSON(http.StatusOK, res)
 }
 
 // SearchKitchens godoc
 // @Summary Searches kitchens
-// @Description Searches kitchens from database
+// @Description Searches kitchens from database, with the gateway's verified badge. The cuisine type may be given by code, name, translation or alias from GET /cuisines. Kitchens the caller blocked are left out, so a page may hold fewer items than the limit
 // @Tags kitchen
 // @Security ApiKeyAuth
 // @Param query query string false "Search query"
@@ -247,75 +174,53 @@ func (h *Handler) FetchKitchens(c *gin.Context) {
 // @Param limit query int false "Number of items per page"
 // @Success 200 {object} kitchen.Kitchens
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
 // @Router /kitchens/search [get]
 func (h *Handler) SearchKitchens(c *gin.Context) {
-	h.Logger.Info("SearchKitchens method is starting")
-
-	query := c.Query("query")
-	cuisineType := c.Query("cuisine_type")
-	rating := c.Query("rating")
-	page := c.Query("page")
-	limit := c.Query("limit")
-	var ratingFloat float64
-
-	if query == "" && cuisineType == "" && rating == "" {
-		er := errors.New("invalid search parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	if rating != "" {
-		r, err := strconv.ParseFloat(rating, 32)
-		if err != nil {
-			er := errors.Wrap(err, "invalid search parameters").Error()
-			c.AbortWithStatusJSON(http.StatusBadRequest,
-				gin.H{"error": er})
-			h.Logger.Error(er)
-			return
-		}
-		ratingFloat = r
-	}
-
-	p, err := strconv.Atoi(page)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	l, err := strconv.Atoi(limit)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.KitchenClient.Search(ctx, &pb.SearchDetails{
-		Query:       query,
-		CuisineType: cuisineType,
-		Rating:      float32(ratingFloat),
-		Pagination: &pb.Pagination{
-			Limit:  int32(l),
-			Offset: int32((p - 1) * l),
+	serve(h, c, Proxy[*pb.SearchDetails, *pb.Kitchens]{
+		Name: "SearchKitchens",
+		Bind: func(c *gin.Context) (*pb.SearchDetails, error) {
+			query := c.Query("query")
+			cuisineType := c.Query("cuisine_type")
+			rating := c.Query("rating")
+
+			if query == "" && cuisineType == "" && rating == "" {
+				return nil, errors.New("invalid search parameters")
+			}
+
+			cuisineType, err := h.canonicalCuisine(cuisineType)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid search parameters")
+			}
+
+			var ratingFloat float64
+			if rating != "" {
+				r, err := strconv.ParseFloat(rating, 32)
+				if err != nil {
+					return nil, errors.Wrap(err, "invalid search parameters")
+				}
+				ratingFloat = r
+			}
+
+			limit, offset, err := pagination(c)
+			if err != nil {
+				return nil, err
+			}
+
+			return &pb.SearchDetails{
+				Query:       query,
+				CuisineType: cuisineType,
+				Rating:      float32(ratingFloat),
+				Pagination: &pb.Pagination{
+					Limit:  limit,
+					Offset: offset,
+				},
+			}, nil
+		},
+		Call:  h.KitchenClient.Search,
+		Error: "error searching kitchens",
+		Render: func(c *gin.Context, res *pb.Kitchens) {
+			h.renderKitchens(c, res)
 		},
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error searching kitchens").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("SearchKitchens method has finished successfully")
-	c.JSON(http.StatusOK, res)
 }
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pb "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+// renderKitchens renders a kitchen service response, translated into the
+// request's locale, with the verified badge added to the kitchen, or to
+// every kitchen of a list. A single kitchen also gets its hygiene rating
+// and active announcements.
+//
+// These are the busiest responses, so the kitchens are not decoded to add
+// the fields: each kitchen's JSON comes from h.Encoded and the fields are
+// spliced in before its closing brace, all in a pooled buffer.
+func (h *Handler) renderKitchens(c *gin.Context, res proto.Message) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	// Kitchens are cached by locale too, their names being translated.
+	h.localize(c, res)
+	variant := h.apiFormat(c) + "/" + c.GetString(middleware.LocaleKey)
+	var err error
+	switch res := res.(type) {
+	case *pb.Info:
+		*buf, err = h.appendKitchen(c, *buf, variant, res)
+	case *pb.Kitchens:
+		*buf, err = h.appendKitchenList(c, *buf, variant, res)
+	default:
+		h.render(c, http.StatusOK, res)
+		return
+	}
+	h.write(c, http.StatusOK, res, *buf, err)
+}
+
+func (h *Handler) appendKitchen(c *gin.Context, dst []byte, variant string, k *pb.Info) ([]byte, error) {
+	dst, err := h.Encoded.Append(dst, k.Id, variant, k, func() ([]byte, error) {
+		return h.encode(c, k)
+	})
+	if err != nil {
+		return dst, err
+	}
+
+	announcements, err := json.Marshal(h.activeAnnouncements([]string{k.Id}, time.Now()))
+	if err != nil {
+		return dst, err
+	}
+	rating, err := json.Marshal(h.hygieneRating(k.Id))
+	if err != nil {
+		return dst, err
+	}
+
+	dst, more := openObject(dst)
+	dst = strconv.AppendBool(appendKey(dst, more, "verified"), h.verified(k.Id))
+	dst = append(appendKey(dst, true, "hygiene_rating"), rating...)
+	dst = append(appendKey(dst, true, "announcements"), announcements...)
+	return append(dst, '}'), nil
+}
+
+// appendKitchenList leaves out the kitchens the caller blocked.
+func (h *Handler) appendKitchenList(c *gin.Context, dst []byte, variant string, res *pb.Kitchens) ([]byte, error) {
+	kitchens := res.Kitchens
+	res.Kitchens = nil
+	head, err := h.encode(c, res)
+	res.Kitchens = kitchens
+	if err != nil {
+		return dst, err
+	}
+	if h.Marshaler.EmitUnpopulated {
+		head = dropEmptyList(head, "kitchens")
+	} else if len(kitchens) == 0 {
+		return append(dst, head...), nil
+	}
+
+	var blocked []models.BlockedKitchen
+	if userID := c.GetString(middleware.UserIDKey); userID != "" {
+		blocked, _ = h.Storage.BlockedKitchens.Get(userID)
+	}
+
+	dst, more := openObject(append(dst, head...))
+	dst = append(appendKey(dst, more, "kitchens"), '[')
+	first := true
+	for _, k := range kitchens {
+		if slices.ContainsFunc(blocked, func(b models.BlockedKitchen) bool { return b.KitchenId == k.Id }) {
+			continue
+		}
+		if !first {
+			dst = append(dst, ',')
+		}
+		first = false
+
+		dst, err = h.Encoded.Append(dst, k.Id, variant, k, func() ([]byte, error) {
+			return h.encode(c, k)
+		})
+		if err != nil {
+			return dst, err
+		}
+		dst, more = openObject(dst)
+		dst = strconv.AppendBool(appendKey(dst, more, "verified"), h.verified(k.Id))
+		dst = append(dst, '}')
+	}
+	return append(dst, ']', '}'), nil
+}
+
+// verified tells whether the kitchen has the verified badge.
+func (h *Handler) verified(kitchenID string) bool {
+	v, _ := h.Storage.Verifications.Get(kitchenID)
+	return v.Status == models.VerificationVerified
+}
+
+// openObject drops the closing brace of the JSON object that ends dst so
+// fields can be appended, telling whether it has fields already.
+func openObject(dst []byte) ([]byte, bool) {
+	dst = bytes.TrimRight(dst, " \n")
+	dst = dst[:len(dst)-1]
+	rest := bytes.TrimRight(dst, " \n")
+	return dst, rest[len(rest)-1] != '{'
+}
+
+// appendKey appends an object key, after a comma when more follows an
+// earlier field.
+func appendKey(dst []byte, more bool, name string) []byte {
+	if more {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, '"')
+	dst = append(dst, name...)
+	return append(dst, '"', ':')
+}
+
+// dropEmptyList removes the empty list field name, which EmitUnpopulated
+// writes for a cleared list, from the JSON object obj.
+func dropEmptyList(obj []byte, name string) []byte {
+	start := bytes.Index(obj, []byte(`"`+name+`"`))
+	if start < 0 {
+		return obj
+	}
+	end := start + len(name) + 2
+	for _, want := range []byte(":[]") {
+		for end < len(obj) && obj[end] == ' ' {
+			end++
+		}
+		if end == len(obj) || obj[end] != want {
+			return obj
+		}
+		end++
+	}
+
+	// Take the comma on either side along, whichever there is.
+	rest := bytes.TrimLeft(obj[end:], " ")
+	if len(rest) > 0 && rest[0] == ',' {
+		end = len(obj) - len(rest) + 1
+	} else if before := bytes.TrimRight(obj[:start], " "); before[len(before)-1] == ',' {
+		start = len(before) - 1
+	}
+	return append(obj[:start:start], obj[end:]...)
+}
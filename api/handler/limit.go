@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"cmp"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FetchUserLoads godoc
+// @Summary Gets the per-user request cap's metrics
+// @Description Lists the users with requests in flight or turned away with 429 for going over the per-user cap in the last 10 minutes, the most rejected first, to find clients stuck in retry storms
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.UserLoads
+// @Router /admin/limits/users [get]
+func (h *Handler) FetchUserLoads(c *gin.Context) {
+	h.Logger.Info("FetchUserLoads method is starting")
+
+	res := h.UserLimits.Loads()
+	slices.SortFunc(res.Users, func(a, b models.UserLoad) int {
+		if n := cmp.Compare(b.Rejected, a.Rejected); n != 0 {
+			return n
+		}
+		return cmp.Compare(b.InFlight, a.InFlight)
+	})
+
+	h.Logger.Info("FetchUserLoads method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	pbd "api-gateway/genproto/dish"
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"crypto/rand"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	linkAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	linkCodeLen  = 7
+)
+
+// CreateLink godoc
+// @Summary Creates a short link
+// @Description Makes a short share link to a kitchen or dish page of the web app. Opening the link counts a click and redirects to the page
+// @Tags link
+// @Security ApiKeyAuth
+// @Param link body models.NewLink true "Link target"
+// @Success 200 {object} models.Link
+// @Failure 400 {object} string "Invalid link data"
+// @Failure 404 {object} string "Kitchen or dish not found"
+// @Router /links [post]
+func (h *Handler) CreateLink(c *gin.Context) {
+	h.Logger.Info("CreateLink method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewLink
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		_, err = uuid.Parse(data.TargetId)
+	}
+	if err == nil && data.Type != models.LinkKitchen && data.Type != models.LinkDish {
+		err = errors.Errorf("type must be %s or %s", models.LinkKitchen, models.LinkDish)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid link data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	if data.Type == models.LinkKitchen {
+		_, err = h.KitchenClient.GetName(ctx, &pbk.ID{Id: data.TargetId})
+	} else {
+		_, err = h.DishClient.Read(ctx, &pbd.ID{Id: data.TargetId})
+	}
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrapf(err, "error getting %s", data.Type).Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	code, err := h.newLinkCode()
+	if err != nil {
+		er := errors.Wrap(err, "error creating link code").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	l := models.Link{
+		Code:      code,
+		ShortUrl:  strings.TrimSuffix(h.LinkURL, "/") + "/" + code,
+		Type:      data.Type,
+		TargetId:  data.TargetId,
+		Campaign:  strings.TrimSpace(data.Campaign),
+		CreatedBy: userID,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	l.TargetUrl = h.linkTarget(l)
+	h.Storage.Links.Set(code, l)
+
+	h.Logger.Info("CreateLink method has finished successfully")
+	h.render(c, http.StatusOK, l)
+}
+
+// FetchLinks godoc
+// @Summary Gets short links
+// @Description Lists the caller's short links with their clicks, newest first. Admins see every link
+// @Tags link
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Links
+// @Router /links [get]
+func (h *Handler) FetchLinks(c *gin.Context) {
+	h.Logger.Info("FetchLinks method is starting")
+
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	res := models.Links{Links: []models.Link{}}
+	for _, l := range h.Storage.Links.List() {
+		if role == models.RoleAdmin || l.CreatedBy == userID {
+			res.Links = append(res.Links, l)
+		}
+	}
+	slices.SortFunc(res.Links, func(a, b models.Link) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchLinks method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetLink godoc
+// @Summary Gets a short link
+// @Description Retrieves a short link with its clicks
+// @Tags link
+// @Security ApiKeyAuth
+// @Param code path string true "Link code"
+// @Success 200 {object} models.Link
+// @Failure 403 {object} string "Not the link's creator"
+// @Failure 404 {object} string "Link not found"
+// @Router /links/{code} [get]
+func (h *Handler) GetLink(c *gin.Context) {
+	h.Logger.Info("GetLink method is starting")
+
+	l, ok := h.ownLink(c)
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("GetLink method has finished successfully")
+	h.render(c, http.StatusOK, l)
+}
+
+// DeleteLink godoc
+// @Summary Deletes a short link
+// @Description Removes a short link, after which it no longer redirects
+// @Tags link
+// @Security ApiKeyAuth
+// @Param code path string true "Link code"
+// @Success 200 {object} string
+// @Failure 403 {object} string "Not the link's creator"
+// @Failure 404 {object} string "Link not found"
+// @Router /links/{code} [delete]
+func (h *Handler) DeleteLink(c *gin.Context) {
+	h.Logger.Info("DeleteLink method is starting")
+
+	l, ok := h.ownLink(c)
+	if !ok {
+		return
+	}
+
+	h.Storage.Links.Delete(l.Code)
+
+	h.Logger.Info("DeleteLink method has finished successfully")
+	h.render(c, http.StatusOK, "Link deleted successfully")
+}
+
+// FollowLink redirects a short link to its page and counts the click.
+// It is served at the site root, outside the API, so links stay short.
+func (h *Handler) FollowLink(c *gin.Context) {
+	h.Logger.Info("FollowLink method is starting")
+
+	code := c.Param("code")
+	if _, ok := h.Storage.Links.Get(code); !ok {
+		er := "link not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	l := h.Storage.Links.Update(code, func(l models.Link, _ bool) models.Link {
+		l.Clicks++
+		l.LastClickedAt = time.Now().Format(time.RFC3339)
+		return l
+	})
+
+	h.Logger.Info("FollowLink method has finished successfully")
+	c.Redirect(http.StatusFound, l.TargetUrl)
+}
+
+// ownLink finds the link of the code path parameter, allowing only its
+// creator and admins.
+func (h *Handler) ownLink(c *gin.Context) (models.Link, bool) {
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return models.Link{}, false
+	}
+
+	l, ok := h.Storage.Links.Get(c.Param("code"))
+	if !ok {
+		er := "link not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Link{}, false
+	}
+
+	if role != models.RoleAdmin && l.CreatedBy != userID {
+		er := "access denied"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Link{}, false
+	}
+	return l, true
+}
+
+// linkTarget builds the web app URL a link redirects to.
+func (h *Handler) linkTarget(l models.Link) string {
+	page := "/kitchens/"
+	if l.Type == models.LinkDish {
+		page = "/dishes/"
+	}
+	target := strings.TrimSuffix(h.WebURL, "/") + page + l.TargetId
+	if l.Campaign != "" {
+		target += "?" + url.Values{
+			"utm_source":   {"share_link"},
+			"utm_campaign": {l.Campaign},
+		}.Encode()
+	}
+	return target
+}
+
+// newLinkCode picks an unused short code. The alphabet leaves out
+// characters that are easily confused when a link is typed.
+func (h *Handler) newLinkCode() (string, error) {
+	size := big.NewInt(int64(len(linkAlphabet)))
+	for {
+		b := make([]byte, linkCodeLen)
+		for i := range b {
+			n, err := rand.Int(rand.Reader, size)
+			if err != nil {
+				return "", err
+			}
+			b[i] = linkAlphabet[n.Int64()]
+		}
+		if _, taken := h.Storage.Links.Get(string(b)); !taken {
+			return string(b), nil
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// streamFlushEvery is how many items of a streamed list are sent at a
+// time.
+const streamFlushEvery = 50
+
+// renderList renders res, whose field list holds a list of items, with
+// each item passed to each, if set, to add to it. Lists of StreamMinItems
+// or more are streamed: the items are encoded, shaped and sent a few at a
+// time, so the response is never held in memory whole. A streamed
+// response that fails midway is cut short, as its status is sent.
+func (h *Handler) renderList(c *gin.Context, res proto.Message, list protoreflect.Name, each func(map[string]any)) {
+	m := res.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName(list)
+	key := fd.JSONName()
+	if h.Marshaler.UseProtoNames {
+		key = string(fd.Name())
+	}
+
+	n := m.Get(fd).List().Len()
+	if h.StreamMinItems == 0 || n < h.StreamMinItems {
+		h.renderBuffered(c, res, key, each)
+		return
+	}
+
+	// The items are taken out to encode the rest of the response, and put
+	// back after.
+	items := make([]protoreflect.Value, n)
+	for i := range items {
+		items[i] = m.Get(fd).List().Get(i)
+	}
+	m.Clear(fd)
+	head, err := h.encodeHead(c, res, key)
+	dst := m.Mutable(fd).List()
+	for _, item := range items {
+		dst.Append(item)
+	}
+	if err != nil {
+		h.write(c, http.StatusOK, res, nil, err)
+		return
+	}
+
+	// The first item is encoded before anything is sent, so a response
+	// that can't be encoded at all is answered with an error. Its key is
+	// the list's key as the client's schema version names it.
+	name, first, err := h.encodeItem(c, items[0].Message().Interface(), key, each)
+	if err != nil {
+		h.write(c, http.StatusOK, res, nil, err)
+		return
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	b := *buf
+	if h.Envelope {
+		b = append(b, `{"data":`...)
+	}
+	b = append(b, head[:len(head)-1]...)
+	if len(head) > 2 {
+		b = append(b, ',')
+	}
+	quoted, _ := json.Marshal(name)
+	b = append(append(b, quoted...), ":["...)
+	b = append(b, first...)
+
+	setPageLinks(c, res)
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+	for i, item := range items[1:] {
+		if (i+1)%streamFlushEvery == 0 {
+			if _, err := c.Writer.Write(b); err != nil {
+				h.Logger.Error(errors.Wrap(err, "error streaming response").Error())
+				c.Abort()
+				return
+			}
+			c.Writer.Flush()
+			b = b[:0]
+		}
+
+		_, data, err := h.encodeItem(c, item.Message().Interface(), key, each)
+		if err != nil {
+			h.Logger.Error(errors.Wrap(err, "error encoding streamed response, cutting it short").Error())
+			c.Writer.Write(b)
+			c.Abort()
+			return
+		}
+		b = append(append(b, ','), data...)
+	}
+	b = append(b, "]}"...)
+
+	if h.Envelope {
+		meta, err := json.Marshal(envelopeMeta(c, res))
+		if err != nil {
+			h.Logger.Error(errors.Wrap(err, "error encoding streamed response, cutting it short").Error())
+			c.Writer.Write(b)
+			c.Abort()
+			return
+		}
+		b = append(b, `,"meta":`...)
+		b = append(append(b, meta...), '}')
+	}
+	if _, err := c.Writer.Write(b); err != nil {
+		h.Logger.Error(errors.Wrap(err, "error streaming response").Error())
+	}
+	*buf = b
+}
+
+// renderBuffered renders res with the items of its list under key passed
+// to each.
+func (h *Handler) renderBuffered(c *gin.Context, res proto.Message, key string, each func(map[string]any)) {
+	data, err := h.encode(c, res)
+	if err != nil || each == nil {
+		h.write(c, http.StatusOK, res, data, err)
+		return
+	}
+
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		h.write(c, http.StatusOK, res, data, err)
+		return
+	}
+
+	list, _ := v[key].([]any)
+	for _, item := range list {
+		if item, ok := item.(map[string]any); ok {
+			each(item)
+		}
+	}
+
+	data, err = json.Marshal(v)
+	h.write(c, http.StatusOK, res, data, err)
+}
+
+// encodeHead encodes res, its list cleared, as a shaped JSON object
+// without the list.
+func (h *Handler) encodeHead(c *gin.Context, res proto.Message, key string) ([]byte, error) {
+	data, err := h.encode(c, res)
+	if err != nil {
+		return nil, err
+	}
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	// Unpopulated fields may be emitted, the list too.
+	delete(v, key)
+	if data, err = json.Marshal(v); err != nil {
+		return nil, err
+	}
+	return h.shapeData(c, data)
+}
+
+// encodeItem encodes an item of a list under key as the list's only item,
+// so it is shaped and migrated as it would be in the whole response, and
+// returns the item with the key the list ends up under.
+func (h *Handler) encodeItem(c *gin.Context, item proto.Message, key string, each func(map[string]any)) (string, []byte, error) {
+	data, err := h.encode(c, item)
+	if err != nil {
+		return "", nil, err
+	}
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return "", nil, err
+	}
+	if each != nil {
+		each(v)
+	}
+
+	if data, err = json.Marshal(map[string]any{key: []any{v}}); err != nil {
+		return "", nil, err
+	}
+	if data, err = h.shapeData(c, data); err != nil {
+		return "", nil, err
+	}
+	var shaped map[string][]json.RawMessage
+	if err := json.Unmarshal(data, &shaped); err != nil {
+		return "", nil, err
+	}
+	for name, list := range shaped {
+		if len(shaped) == 1 && len(list) == 1 {
+			return name, list[0], nil
+		}
+	}
+	return "", nil, errors.New("list item shaped into more than an item")
+}
@@ -0,0 +1,443 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"api-gateway/pkg/mealplan"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// maxMealPlanDeliveries bounds the deliveries of a plan, two a day.
+const maxMealPlanDeliveries = 14
+
+// CreateMealPlan godoc
+// @Summary Subscribes to a meal plan
+// @Description Creates a weekly plan of deliveries from a kitchen. The gateway places an order for each delivery ahead of its time and notifies the customer's feed when an order fails; three failures in a row pause the plan. A plan made in the sandbox places test orders
+// @Tags meal plan
+// @Security ApiKeyAuth
+// @Param plan body models.NewMealPlan true "Plan"
+// @Success 200 {object} models.MealPlan
+// @Failure 400 {object} string "Invalid meal plan"
+// @Failure 403 {object} string "Token has no user id"
+// @Failure 404 {object} string "Kitchen or dish not found"
+// @Router /meal-plans [post]
+func (h *Handler) CreateMealPlan(c *gin.Context) {
+	h.Logger.Info("CreateMealPlan method is starting")
+
+	userID, ok := h.user(c)
+	if !ok {
+		return
+	}
+
+	data, ok := h.bindMealPlan(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	p := models.MealPlan{
+		Id:        uuid.NewString(),
+		UserId:    userID,
+		Status:    models.MealPlanActive,
+		Sandbox:   middleware.InSandbox(c),
+		CreatedAt: now.Format(time.RFC3339),
+	}
+	setMealPlan(&p, data)
+	h.MealPlans.Advance(&p, now.Add(h.MealPlans.Lead()))
+	h.Storage.MealPlans.Set(p.Id, p)
+
+	h.Logger.Info("CreateMealPlan method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
+
+// FetchMealPlans godoc
+// @Summary Gets meal plans
+// @Description Lists the caller's meal plans, newest first. Admins may list the plans of any user
+// @Tags meal plan
+// @Security ApiKeyAuth
+// @Param user_id query string false "User ID, for admins"
+// @Param status query string false "Plan status" Enums(active, paused, canceled, completed)
+// @Success 200 {object} models.MealPlans
+// @Failure 403 {object} string "Access denied"
+// @Router /meal-plans [get]
+func (h *Handler) FetchMealPlans(c *gin.Context) {
+	h.Logger.Info("FetchMealPlans method is starting")
+
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return
+	}
+	if role == models.RoleAdmin {
+		userID = c.Query("user_id")
+	}
+
+	res := models.MealPlans{MealPlans: []models.MealPlan{}}
+	for _, p := range h.Storage.MealPlans.List() {
+		switch {
+		case userID != "" && p.UserId != userID:
+		case c.Query("status") != "" && p.Status != c.Query("status"):
+		default:
+			res.MealPlans = append(res.MealPlans, p)
+		}
+	}
+	slices.SortFunc(res.MealPlans, func(a, b models.MealPlan) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchMealPlans method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetMealPlan godoc
+// @Summary Gets a meal plan
+// @Description Retrieves a meal plan with its next delivery. For its subscriber and admins
+// @Tags meal plan
+// @Security ApiKeyAuth
+// @Param id path string true "Meal plan ID"
+// @Success 200 {object} models.MealPlan
+// @Failure 400 {object} string "Invalid meal plan ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Meal plan not found"
+// @Router /meal-plans/{id} [get]
+func (h *Handler) GetMealPlan(c *gin.Context) {
+	h.Logger.Info("GetMealPlan method is starting")
+
+	p, ok := h.findMealPlan(c)
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("GetMealPlan method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
+
+// UpdateMealPlan godoc
+// @Summary Updates a meal plan
+// @Description Replaces the kitchen, address, deliveries and dates of a plan that is active or paused. Orders already placed are not changed
+// @Tags meal plan
+// @Security ApiKeyAuth
+// @Param id path string true "Meal plan ID"
+// @Param plan body models.NewMealPlan true "Plan"
+// @Success 200 {object} models.MealPlan
+// @Failure 400 {object} string "Invalid meal plan"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Meal plan, kitchen or dish not found"
+// @Failure 409 {object} string "Meal plan is over"
+// @Router /meal-plans/{id} [put]
+func (h *Handler) UpdateMealPlan(c *gin.Context) {
+	h.Logger.Info("UpdateMealPlan method is starting")
+
+	p, ok := h.findMealPlan(c)
+	if !ok {
+		return
+	}
+
+	data, ok := h.bindMealPlan(c)
+	if !ok {
+		return
+	}
+
+	p, ok = h.changeMealPlan(c, p.Id, func(p *models.MealPlan) {
+		setMealPlan(p, data)
+		if p.Status == models.MealPlanActive {
+			h.MealPlans.Advance(p, time.Now().Add(h.MealPlans.Lead()))
+		}
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("UpdateMealPlan method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
+
+// ChangeMealPlanStatus godoc
+// @Summary Pauses, resumes or cancels a meal plan
+// @Description Moves an active or paused plan to active, paused or canceled. Resuming schedules the next delivery that is far enough ahead and clears failures
+// @Tags meal plan
+// @Security ApiKeyAuth
+// @Param id path string true "Meal plan ID"
+// @Param status body models.MealPlanStatus true "New status"
+// @Success 200 {object} models.MealPlan
+// @Failure 400 {object} string "Invalid status"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Meal plan not found"
+// @Failure 409 {object} string "Meal plan is over"
+// @Router /meal-plans/{id}/status [put]
+func (h *Handler) ChangeMealPlanStatus(c *gin.Context) {
+	h.Logger.Info("ChangeMealPlanStatus method is starting")
+
+	p, ok := h.findMealPlan(c)
+	if !ok {
+		return
+	}
+
+	var data models.MealPlanStatus
+	if err := c.ShouldBindJSON(&data); err != nil ||
+		!slices.Contains([]string{models.MealPlanActive, models.MealPlanPaused, models.MealPlanCanceled}, data.Status) {
+		er := "invalid status: must be active, paused or canceled"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	p, ok = h.changeMealPlan(c, p.Id, func(p *models.MealPlan) {
+		resumed := data.Status == models.MealPlanActive && p.Status != models.MealPlanActive
+		p.Status = data.Status
+		if resumed {
+			p.Failures = 0
+			h.MealPlans.Advance(p, time.Now().Add(h.MealPlans.Lead()))
+		}
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("ChangeMealPlanStatus method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
+
+// FetchMealPlanRuns godoc
+// @Summary Gets meal plan orders
+// @Description Lists the orders the gateway placed or failed to place for the plan, newest delivery first
+// @Tags meal plan
+// @Security ApiKeyAuth
+// @Param id path string true "Meal plan ID"
+// @Success 200 {object} models.MealPlanRuns
+// @Failure 400 {object} string "Invalid meal plan ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Meal plan not found"
+// @Router /meal-plans/{id}/runs [get]
+func (h *Handler) FetchMealPlanRuns(c *gin.Context) {
+	h.Logger.Info("FetchMealPlanRuns method is starting")
+
+	p, ok := h.findMealPlan(c)
+	if !ok {
+		return
+	}
+
+	res := models.MealPlanRuns{Runs: []models.MealPlanRun{}}
+	for _, r := range h.Storage.MealPlanRuns.List() {
+		if r.PlanId == p.Id {
+			res.Runs = append(res.Runs, r)
+		}
+	}
+	slices.SortFunc(res.Runs, func(a, b models.MealPlanRun) int {
+		return strings.Compare(b.DeliveryTime, a.DeliveryTime)
+	})
+
+	h.Logger.Info("FetchMealPlanRuns method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// findMealPlan loads the plan of the path for its subscriber or an admin.
+func (h *Handler) findMealPlan(c *gin.Context) (models.MealPlan, bool) {
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return models.MealPlan{}, false
+	}
+
+	id, err := pathUUID(c, "id", "meal plan id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.MealPlan{}, false
+	}
+
+	p, ok := h.Storage.MealPlans.Get(id)
+	if !ok {
+		er := "meal plan not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.MealPlan{}, false
+	}
+
+	if role != models.RoleAdmin && p.UserId != userID {
+		er := "access denied"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.MealPlan{}, false
+	}
+	return p, true
+}
+
+// changeMealPlan applies fn to a plan that is not over yet.
+func (h *Handler) changeMealPlan(c *gin.Context, id string, fn func(p *models.MealPlan)) (models.MealPlan, bool) {
+	over := false
+	p := h.Storage.MealPlans.Update(id, func(p models.MealPlan, _ bool) models.MealPlan {
+		if over = p.Status == models.MealPlanCanceled || p.Status == models.MealPlanCompleted; over {
+			return p
+		}
+		fn(&p)
+		p.UpdatedAt = time.Now().Format(time.RFC3339)
+		return p
+	})
+	if over {
+		er := "meal plan is " + p.Status
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.MealPlan{}, false
+	}
+	return p, true
+}
+
+// bindMealPlan reads and validates a plan, checking that the kitchen
+// exists and that every item is a dish of it with valid modifiers.
+func (h *Handler) bindMealPlan(c *gin.Context) (models.NewMealPlan, bool) {
+	var data models.NewMealPlan
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid meal plan").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return data, false
+	}
+
+	err := validateMealPlan(data)
+	if err == nil {
+		ctx, cancel := callContext(c, defaultTimeout)
+		defer cancel()
+		err = h.checkMealPlanItems(ctx, data)
+	}
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "invalid meal plan").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return data, false
+	}
+	return data, true
+}
+
+func (h *Handler) checkMealPlanItems(ctx context.Context, data models.NewMealPlan) error {
+	if _, err := h.KitchenClient.Get(ctx, &pbk.ID{Id: data.KitchenId}); err != nil {
+		return errors.Wrap(err, "error getting kitchen")
+	}
+
+	for _, d := range data.Deliveries {
+		for _, item := range d.Items {
+			dish, _, err := h.itemPrice(ctx, item)
+			if err != nil {
+				return err
+			}
+			if dish.KitchenId != data.KitchenId {
+				return errors.Errorf("dish %s belongs to another kitchen", item.DishId)
+			}
+		}
+	}
+	return nil
+}
+
+func validateMealPlan(data models.NewMealPlan) error {
+	if _, err := uuid.Parse(data.KitchenId); err != nil {
+		return errors.Wrap(err, "invalid kitchen id")
+	}
+	if data.DeliveryAddress == "" || data.Location == nil {
+		return errors.New("delivery_address and location are required")
+	}
+	if err := data.Location.Validate(); err != nil {
+		return err
+	}
+
+	if len(data.Deliveries) == 0 || len(data.Deliveries) > maxMealPlanDeliveries {
+		return errors.Errorf("a plan needs 1 to %d deliveries", maxMealPlanDeliveries)
+	}
+	slots := make(map[string]bool)
+	for _, d := range data.Deliveries {
+		if _, ok := mealplan.Weekday(d.Weekday); !ok {
+			return errors.Errorf("unknown weekday %q", d.Weekday)
+		}
+		if _, err := time.Parse("15:04", d.Time); err != nil {
+			return errors.Errorf("invalid time %q, HH:MM expected", d.Time)
+		}
+		slot := strings.ToLower(d.Weekday) + " " + d.Time
+		if slots[slot] {
+			return errors.Errorf("two deliveries on %s", slot)
+		}
+		slots[slot] = true
+
+		if len(d.Items) == 0 {
+			return errors.Errorf("delivery on %s has no items", slot)
+		}
+		for _, item := range d.Items {
+			if item.Quantity <= 0 {
+				return errors.New("quantity must be positive")
+			}
+		}
+	}
+
+	var start, end time.Time
+	for _, d := range []struct {
+		value string
+		t     *time.Time
+	}{{data.StartDate, &start}, {data.EndDate, &end}} {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			return errors.Wrap(err, "dates must be YYYY-MM-DD")
+		}
+		*d.t = t
+	}
+	if !end.IsZero() && (end.Before(start) || end.Before(time.Now().Truncate(24*time.Hour))) {
+		return errors.New("end date must not be before the start date or today")
+	}
+	return nil
+}
+
+func setMealPlan(p *models.MealPlan, data models.NewMealPlan) {
+	p.KitchenId = data.KitchenId
+	p.DeliveryAddress = data.DeliveryAddress
+	p.Location = data.Location
+	p.Deliveries = data.Deliveries
+	p.StartDate = data.StartDate
+	p.EndDate = data.EndDate
+	p.UpdatedAt = time.Now().Format(time.RFC3339)
+}
+
+// placeScheduledOrder places a meal plan order through the same checks
+// as CreateOrder.
+func (h *Handler) placeScheduledOrder(ctx context.Context, data models.NewOrder) (string, error) {
+	res, fail := h.placeOrder(ctx, data, nil)
+	if fail != nil {
+		return "", errors.New(fail.err)
+	}
+	return res.Id, nil
+}
+
+// mealPlanFailed tells the subscriber, through their feed and an event,
+// that a scheduled order was not placed. The event is only published when
+// the subscriber gets order update pushes.
+func (h *Handler) mealPlanFailed(p models.MealPlan, run models.MealPlanRun) {
+	item := models.FeedMealPlan{
+		Id:           p.Id,
+		KitchenId:    p.KitchenId,
+		DeliveryTime: run.DeliveryTime,
+		Error:        run.Error,
+		Paused:       p.Status == models.MealPlanPaused && p.Failures >= models.MaxMealPlanFailures,
+	}
+	h.recordActivity(p.UserId, models.FeedItem{
+		Type:     models.FeedMealPlanFailed,
+		MealPlan: &item,
+	})
+	if h.notifies(p.UserId, models.NotifyOrderUpdates, models.ChannelPush) {
+		h.Events.Emit(models.EventMealPlanFailed, p.Id, item)
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/review"
+	"api-gateway/models"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// FetchHeldReviews godoc
+// @Summary Gets held reviews
+// @Description Lists reviews moderation held back, oldest first, optionally by status
+// @Tags review
+// @Security ApiKeyAuth
+// @Param status query string false "pending, approved or rejected"
+// @Success 200 {object} models.HeldReviews
+// @Router /admin/moderation [get]
+func (h *Handler) FetchHeldReviews(c *gin.Context) {
+	h.Logger.Info("FetchHeldReviews method is starting")
+
+	status := c.Query("status")
+	res := models.HeldReviews{Reviews: []models.HeldReview{}}
+	for _, r := range h.Storage.HeldReviews.List() {
+		if status == "" || r.Status == status {
+			res.Reviews = append(res.Reviews, r)
+		}
+	}
+	slices.SortFunc(res.Reviews, func(a, b models.HeldReview) int {
+		return strings.Compare(a.CreatedAt, b.CreatedAt)
+	})
+
+	h.Logger.Info("FetchHeldReviews method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// ModerateReview godoc
+// @Summary Moderates a held review
+// @Description Publishes a held review or drops it
+// @Tags review
+// @Security ApiKeyAuth
+// @Param id path string true "Held review ID"
+// @Param decision body models.ModerationDecision true "Decision"
+// @Success 200 {object} models.HeldReview
+// @Failure 400 {object} string "Invalid ID or decision"
+// @Failure 404 {object} string "Held review not found"
+// @Failure 409 {object} string "Review already moderated"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/moderation/{id} [put]
+func (h *Handler) ModerateReview(c *gin.Context) {
+	h.Logger.Info("ModerateReview method is starting")
+
+	id, err := pathUUID(c, "id", "held review id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.ModerationDecision
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid decision").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if data.Status != models.ModerationApproved && data.Status != models.ModerationRejected {
+		er := errors.Errorf("invalid decision: status must be %s or %s",
+			models.ModerationApproved, models.ModerationRejected).Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	r, ok := h.Storage.HeldReviews.Get(id)
+	if !ok {
+		er := "held review not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	if r.Status != models.ModerationPending {
+		er := errors.Errorf("review was already %s", r.Status).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if data.Status == models.ModerationApproved {
+		ctx, cancel := callContext(c, defaultTimeout)
+		defer cancel()
+
+		res, err := h.publishReview(ctx, &pb.NewReview{
+			OrderId: r.OrderId,
+			Rating:  r.Rating,
+			Comment: r.Comment,
+		})
+		if err != nil {
+			er := errors.Wrap(err, "failed to publish review").Error()
+			st, _ := errorStatus(err)
+			c.AbortWithStatusJSON(st,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+		r.ReviewId = res.Id
+	}
+
+	r.Status = data.Status
+	r.Note = data.Note
+	r.ReviewedBy = userID
+	r.ReviewedAt = time.Now().Format(time.RFC3339)
+	h.Storage.HeldReviews.Set(r.Id, r)
+
+	h.Logger.Info("ModerateReview method has finished successfully")
+	h.render(c, http.StatusOK, r)
+}
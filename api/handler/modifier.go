@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// SetModifiers godoc
+// @Summary Sets dish modifiers
+// @Description Replaces the modifier schema (size, extras, spice level etc.) of a dish. For the kitchen owner, staff with the menu permission and admins
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Param modifiers body models.ModifiersNoID true "Modifier groups"
+// @Success 200 {object} models.Modifiers
+// @Failure 400 {object} string "Invalid dish ID or modifier data"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /dishes/{id}/modifiers [put]
+func (h *Handler) SetModifiers(c *gin.Context) {
+	h.Logger.Info("SetModifiers method is starting")
+
+	dish, ok := h.ownDish(c)
+	if !ok {
+		return
+	}
+
+	var data models.ModifiersNoID
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid modifier data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	mods := models.Modifiers{
+		DishId: dish.Id,
+		Groups: data.Groups,
+	}
+	if err := mods.Validate(); err != nil {
+		er := errors.Wrap(err, "invalid modifier data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Storage.Modifiers.Set(dish.Id, mods)
+
+	h.Logger.Info("SetModifiers method has finished successfully")
+	h.render(c, http.StatusOK, mods)
+}
+
+// GetModifiers godoc
+// @Summary Gets dish modifiers
+// @Description Retrieves the modifier schema of a dish
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Success 200 {object} models.Modifiers
+// @Failure 400 {object} string "Invalid dish ID"
+// @Router /dishes/{id}/modifiers [get]
+func (h *Handler) GetModifiers(c *gin.Context) {
+	h.Logger.Info("GetModifiers method is starting")
+
+	id := c.Param("id")
+	_, err := uuid.Parse(id)
+	if err != nil {
+		er := errors.Wrap(err, "invalid dish ID").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	mods, ok := h.Storage.Modifiers.Get(id)
+	if !ok {
+		mods = models.Modifiers{DishId: id, Groups: []models.ModifierGroup{}}
+	}
+
+	h.Logger.Info("GetModifiers method has finished successfully")
+	h.render(c, http.StatusOK, mods)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/order"
+	"api-gateway/models"
+	"api-gateway/pkg/moderation"
+	"context"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxOrderNote = 500
+	maxItemNote  = 200
+)
+
+// checkNotes trims the notes of the order and checks their length and
+// content. Notes are refused only for what moderation rejects outright:
+// phone numbers and the like are fine for a courier or a kitchen.
+func (h *Handler) checkNotes(ctx context.Context, data *models.NewOrder) *orderFailure {
+	data.Note = strings.TrimSpace(data.Note)
+	if utf8.RuneCountInString(data.Note) > maxOrderNote {
+		return failOrder(http.StatusBadRequest,
+			errors.Errorf("invalid order data: note must have at most %d characters", maxOrderNote).Error())
+	}
+	for i := range data.Items {
+		item := &data.Items[i]
+		item.Note = strings.TrimSpace(item.Note)
+		if utf8.RuneCountInString(item.Note) > maxItemNote {
+			return failOrder(http.StatusBadRequest, errors.Errorf(
+				"invalid order data: note of dish %s must have at most %d characters", item.DishId, maxItemNote).Error())
+		}
+	}
+
+	notes, ok := orderNotes(*data)
+	if !ok {
+		return nil
+	}
+	texts := []string{notes.Note}
+	for _, n := range notes.Items {
+		texts = append(texts, n.Note)
+	}
+	for _, text := range texts {
+		if m := h.Moderator.Check(ctx, text); m.Action == moderation.Reject {
+			er := "order refused: a note " + m.Detail
+			return &orderFailure{status: http.StatusUnprocessableEntity, err: er,
+				body: gin.H{"error": er, "reason": m.Reason}}
+		}
+	}
+	return nil
+}
+
+// orderNotes collects the notes of the order, telling whether it has
+// any.
+func orderNotes(data models.NewOrder) (models.OrderNotes, bool) {
+	notes := models.OrderNotes{Note: data.Note}
+	for _, item := range data.Items {
+		if item.Note != "" {
+			notes.Items = append(notes.Items, models.ItemNote{DishId: item.DishId, Note: item.Note})
+		}
+	}
+	return notes, notes.Note != "" || len(notes.Items) > 0
+}
+
+// renderKitchenOrders renders the kitchen's orders with the notes and
+// delivery preferences of each order added, marking sandbox orders.
+func (h *Handler) renderKitchenOrders(c *gin.Context, res *pb.OrdersKitchen) {
+	h.renderList(c, res, "orders", func(order map[string]any) {
+		h.addNotes(order)
+		h.addDelivery(order)
+		h.addSandbox(order)
+	})
+}
+
+func (h *Handler) addNotes(order map[string]any) {
+	id, ok := order["id"].(string)
+	if !ok {
+		return
+	}
+	if notes, ok := h.Storage.OrderNotes.Get(id); ok {
+		order["notes"] = notes
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetNotificationSettings godoc
+// @Summary Gets notification settings
+// @Description Tells which channels, push, SMS and email, the user gets order updates, promos and review notifications through. Users who never changed them get everything
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.NotificationSettings
+// @Failure 400 {object} string "Invalid user ID"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/notification-settings [get]
+func (h *Handler) GetNotificationSettings(c *gin.Context) {
+	h.Logger.Info("GetNotificationSettings method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("GetNotificationSettings method has finished successfully")
+	h.render(c, http.StatusOK, h.notificationSettings(userID))
+}
+
+// UpdateNotificationSettings godoc
+// @Summary Updates notification settings
+// @Description Turns channels on or off per kind of notification. Kinds and channels left out of the body keep their settings
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param settings body models.NewNotificationSettings true "Notification settings"
+// @Success 200 {object} models.NotificationSettings
+// @Failure 400 {object} string "Invalid user ID or settings"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/notification-settings [put]
+func (h *Handler) UpdateNotificationSettings(c *gin.Context) {
+	h.Logger.Info("UpdateNotificationSettings method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	// The body is decoded over the current settings, so only the
+	// channels it holds change.
+	data := h.notificationSettings(userID).NewNotificationSettings
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid settings").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res := models.NotificationSettings{
+		UserId:                  userID,
+		NewNotificationSettings: data,
+		UpdatedAt:               time.Now().Format(time.RFC3339),
+	}
+	h.Storage.NotifySettings.Set(userID, res)
+
+	h.Logger.Info("UpdateNotificationSettings method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+func (h *Handler) notificationSettings(userID string) models.NotificationSettings {
+	if s, ok := h.Storage.NotifySettings.Get(userID); ok {
+		return s
+	}
+	return models.DefaultNotificationSettings(userID)
+}
+
+// notifies tells whether the user wants the kind of notification through
+// the channel. Every dispatcher asks before sending.
+func (h *Handler) notifies(userID, kind, channel string) bool {
+	return h.notificationSettings(userID).Allows(kind, channel)
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pbd "api-gateway/genproto/dish"
+	pbe "api-gateway/genproto/extra"
+	pbo "api-gateway/genproto/order"
+	"api-gateway/models"
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	nutritionTimeout = 10 * time.Second
+	// nutritionLookback is how long before a day its orders may have been
+	// placed, as kitchens take orders up to a week ahead.
+	nutritionLookback = (maxPrepLeadMinutes + 24*60) * time.Minute
+)
+
+// nutrientLimit is a query parameter dishes can be searched by, the
+// most of a nutrient they may hold.
+type nutrientLimit struct {
+	name  string
+	value func(n *pbd.NutritionalInfo) int32
+}
+
+var nutrientLimits = []nutrientLimit{
+	{"max_calories", (*pbd.NutritionalInfo).GetCalories},
+	{"max_protein", (*pbd.NutritionalInfo).GetProtein},
+	{"max_fat", (*pbd.NutritionalInfo).GetFat},
+	{"max_carbs", (*pbd.NutritionalInfo).GetCarbs},
+}
+
+// SearchDishes godoc
+// @Summary Searches dishes by nutrition
+// @Description Finds dishes with at most the given calories and grams of protein, fat and carbs per serving, lowest calories first. Dishes without nutrition info are left out, as are those of kitchens the caller blocked. At least one limit or the query is required
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param query query string false "Part of the dish name"
+// @Param max_calories query int false "Most calories"
+// @Param max_protein query int false "Most grams of protein"
+// @Param max_fat query int false "Most grams of fat"
+// @Param max_carbs query int false "Most grams of carbs"
+// @Param page query int true "Page number"
+// @Param limit query int true "Number of items per page"
+// @Success 200 {object} models.NutritionDishes
+// @Failure 400 {object} string "Invalid search parameters"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
+// @Router /dishes/search [get]
+func (h *Handler) SearchDishes(c *gin.Context) {
+	h.Logger.Info("SearchDishes method is starting")
+
+	query := strings.ToLower(strings.TrimSpace(c.Query("query")))
+	limits := make(map[string]int, len(nutrientLimits))
+	var err error
+	for _, l := range nutrientLimits {
+		if c.Query(l.name) == "" {
+			continue
+		}
+		if limits[l.name], err = queryInt(c, l.name); err == nil && limits[l.name] < 0 {
+			err = errors.Errorf("%s must not be negative", l.name)
+		}
+		if err != nil {
+			break
+		}
+	}
+	limit, offset, perr := pagination(c)
+	if err == nil {
+		err = perr
+	}
+	if err == nil && (limit <= 0 || offset < 0) {
+		err = errors.New("invalid pagination parameters")
+	}
+	if err == nil && query == "" && len(limits) == 0 {
+		err = errors.New("a query or a nutrient limit is required")
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid search parameters").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, nutritionTimeout)
+	defer cancel()
+
+	dishes, err := h.Site.Dishes(ctx)
+	if err != nil {
+		er := errors.Wrap(err, "error searching dishes").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var found []*pbd.DishInfo
+	for _, d := range dishes {
+		if query != "" && !strings.Contains(strings.ToLower(d.Name), query) {
+			continue
+		}
+		if (len(limits) > 0 && d.NutritionInfo == nil) || h.callerBlocked(c, d.KitchenId) {
+			continue
+		}
+		if !slices.ContainsFunc(nutrientLimits, func(l nutrientLimit) bool {
+			most, ok := limits[l.name]
+			return ok && int(l.value(d.NutritionInfo)) > most
+		}) {
+			found = append(found, d)
+		}
+	}
+	slices.SortFunc(found, func(a, b *pbd.DishInfo) int {
+		return cmp.Or(cmp.Compare(a.NutritionInfo.GetCalories(), b.NutritionInfo.GetCalories()),
+			strings.Compare(a.Name, b.Name))
+	})
+
+	res := models.NutritionDishes{
+		Dishes: []models.NutritionDish{},
+		Total:  int32(len(found)),
+		Page:   offset/limit + 1,
+		Limit:  limit,
+	}
+	locale := c.GetString(middleware.LocaleKey)
+	for _, d := range found[min(int(offset), len(found)):min(int(offset+limit), len(found))] {
+		// The dishes are shared by the site's cache.
+		d = proto.Clone(d).(*pbd.DishInfo)
+		h.translate(d, locale)
+		res.Dishes = append(res.Dishes, models.NutritionDish{
+			Id:          d.Id,
+			KitchenId:   d.KitchenId,
+			Name:        d.Name,
+			Category:    d.Category,
+			Price:       d.Price,
+			Available:   d.Available,
+			Nutrition:   dishNutrients(d.NutritionInfo),
+			Allergens:   d.Allergens,
+			DietaryInfo: d.DietaryInfo,
+		})
+	}
+
+	h.Logger.Info("SearchDishes method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetNutritionSummary godoc
+// @Summary Gets a day's nutrition
+// @Description Sums the calories and macros of the dishes in the user's orders delivered on the day, in the gateway's time zone, from the nutrition info of each dish. Canceled orders are left out. For the user and admins
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param date query string false "Day as YYYY-MM-DD, today by default"
+// @Success 200 {object} models.NutritionSummary
+// @Failure 400 {object} string "Invalid user ID or date"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /users/{id}/nutrition-summary [get]
+func (h *Handler) GetNutritionSummary(c *gin.Context) {
+	h.Logger.Info("GetNutritionSummary method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	zone := h.TimeZones.Default()
+	day := inZone(time.Now().In(zone), zone)
+	if c.Query("date") != "" {
+		date, err := dateQuery(c, "date", "date")
+		if err != nil {
+			er := err.Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+		day, _ = time.ParseInLocation("2006-01-02", date, zone)
+	}
+
+	ctx, cancel := callContext(c, nutritionTimeout)
+	defer cancel()
+
+	orders, err := h.dayOrders(ctx, userID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		er := errors.Wrap(err, "error getting orders").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res := models.NutritionSummary{
+		UserId:   userID,
+		Date:     day.Format("2006-01-02"),
+		TimeZone: zone.String(),
+		Orders:   []models.NutritionOrder{},
+	}
+	nutrients := h.dishesNutrients(ctx, orders)
+	for _, o := range orders {
+		order := models.NutritionOrder{
+			OrderId:      o.Id,
+			KitchenId:    o.KitchenId,
+			KitchenName:  o.KitchenName,
+			DeliveryTime: o.DeliveryTime,
+			Items:        []models.NutritionItem{},
+		}
+		for _, item := range o.Items {
+			n, ok := nutrients[item.DishId]
+			if !ok {
+				if !slices.Contains(res.UnknownDishes, item.DishId) {
+					res.UnknownDishes = append(res.UnknownDishes, item.DishId)
+				}
+				continue
+			}
+			it := models.NutritionItem{DishId: item.DishId, Name: item.Name, Quantity: item.Quantity}
+			it.Nutrition.Add(n, item.Quantity)
+			order.Items = append(order.Items, it)
+			order.Nutrition.Add(n, item.Quantity)
+		}
+		res.Totals.Add(order.Nutrition, 1)
+		res.Orders = append(res.Orders, order)
+	}
+
+	h.Logger.Info("GetNutritionSummary method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// dayOrders reads the user's orders delivered from start until end that
+// were not canceled, earliest first. The orders are found in the user's
+// activity, as the order service lists only the caller's own.
+func (h *Handler) dayOrders(ctx context.Context, userID string, start, end time.Time) ([]*pbo.OrderInfo, error) {
+	items, _ := h.Storage.Activity.Get(userID)
+	var ids []string
+	for _, it := range items {
+		placed := parseTime(it.OccurredAt)
+		if it.Type == models.FeedOrderPlaced && placed.After(start.Add(-nutritionLookback)) && placed.Before(end) {
+			ids = append(ids, it.Order.Id)
+		}
+	}
+
+	orders := make([]*pbo.OrderInfo, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			orders[i], errs[i] = h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: id})
+			if errs[i] != nil {
+				errs[i] = errors.Wrapf(errs[i], "error getting order %s", id)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	orders = slices.DeleteFunc(orders, func(o *pbo.OrderInfo) bool {
+		status := strings.ToLower(o.Status)
+		at := parseTime(o.DeliveryTime)
+		return status == "cancelled" || status == "canceled" || status == "rejected" ||
+			at.Before(start) || !at.Before(end)
+	})
+	slices.SortFunc(orders, func(a, b *pbo.OrderInfo) int {
+		return parseTime(a.DeliveryTime).Compare(parseTime(b.DeliveryTime))
+	})
+	return orders, nil
+}
+
+// dishesNutrients reads the nutrition info of every dish in the orders.
+// Dishes whose info can't be read are left out.
+func (h *Handler) dishesNutrients(ctx context.Context, orders []*pbo.OrderInfo) map[string]models.Nutrients {
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		res = make(map[string]models.Nutrients)
+	)
+	seen := make(map[string]bool)
+	for _, o := range orders {
+		for _, item := range o.Items {
+			if seen[item.DishId] {
+				continue
+			}
+			seen[item.DishId] = true
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				info, err := h.ExtraClient.GetNutrition(ctx, &pbe.ID{Id: item.DishId})
+				if err != nil {
+					h.Logger.Error(errors.Wrapf(err, "error getting nutrition of dish %s", item.DishId).Error())
+					return
+				}
+				mu.Lock()
+				res[item.DishId] = models.Nutrients{
+					Calories: info.Calories,
+					Protein:  info.Protein,
+					Fat:      info.Fat,
+					Carbs:    info.Carbs,
+				}
+				mu.Unlock()
+			}()
+		}
+	}
+	wg.Wait()
+	return res
+}
+
+func dishNutrients(n *pbd.NutritionalInfo) models.Nutrients {
+	return models.Nutrients{
+		Calories: n.GetCalories(),
+		Protein:  n.GetProtein(),
+		Fat:      n.GetFat(),
+		Carbs:    n.GetCarbs(),
+	}
+}
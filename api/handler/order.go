@@ -1,31 +1,52 @@
 package handler
 
 import (
+	"api-gateway/api/middleware"
+	pbd "api-gateway/genproto/dish"
 	pb "api-gateway/genproto/order"
+	"api-gateway/models"
+	"api-gateway/pkg/pricing"
 	"context"
+	"encoding/json"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
 )
 
 // CreateOrder godoc
 // @Summary Creates an order
-// @Description Inserts a new order into database
+// @Description Inserts a new order into database. Item modifiers are validated against the dish's modifier schema and priced at the gateway.
+// @Description Items may carry a note for the kitchen and the order a note with special instructions; notes are limited in length and refused with 422 when moderation rejects them.
+// @Description Delivery preferences, such as contact-free delivery or an intercom code, are kept with the order for whoever delivers it.
+// @Description Active dish discounts are applied, and the response's pricing shows each item's original and discounted price.
+// @Description If total_amount is sent, it must match the total recomputed from current prices, discounts and fees.
+// @Description Dishes with a daily stock must have enough portions left, and the order must meet the kitchen's ordering rules
+// @Description The delivery location is required, and the delivery fee is priced by the road distance to it from the kitchen.
+// @Description Orders are screened for fraud: some must wait until the customer passes an OTP, others are held for an admin's review and placed once approved.
+// @Description With async=true the order is placed in the background and 202 is answered at once with a token to follow it by, for clients on unreliable connections
+// @Description Orders placed in the sandbox are test data: they skip fraud screening, are paid with fake money and are left out of statistics and earnings
 // @Tags order
 // @Security ApiKeyAuth
-// @Param order body order.NewOrder true "Order info"
+// @Param order body models.NewOrder true "Order info"
+// @Param X-Device-Location header string false "Where the customer's phone is, as lat,lng"
+// @Param async query bool false "Place the order in the background"
 // @Success 200 {object} order.NewOrderResp
+// @Success 202 {object} models.FraudHold "Order held for review, or models.OrderPlacement when async"
 // @Failure 400 {object} string "Invalid order data"
+// @Failure 403 {object} string "The customer must pass an OTP first, see challenge"
+// @Failure 409 {object} string "Submitted total does not match current prices, or too few portions of a dish are left"
+// @Failure 422 {object} string "Order breaks a kitchen rule, named by rule, a note was refused or the location can't be routed to"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /orders [post]
 func (h *Handler) CreateOrder(c *gin.Context) {
 	h.Logger.Info("CreateOrder method is starting")
 
-	var data pb.NewOrder
+	var data models.NewOrder
 	if err := c.ShouldBindJSON(&data); err != nil {
 		er := errors.Wrap(err, "invalid order data").Error()
 		c.AbortWithStatusJSON(http.StatusBadRequest,
@@ -34,64 +55,492 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
+	for _, item := range data.Items {
+		if item.Quantity <= 0 {
+			er := errors.New("invalid order data: quantity must be positive").Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
 
-	res, err := h.OrderClient.MakeOrder(ctx, &data)
+	device, err := deviceLocation(c)
 	if err != nil {
-		er := errors.Wrap(err, "error creating order").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
 			gin.H{"error": er})
 		h.Logger.Error(er)
 		return
 	}
 
+	async := false
+	if s := c.Query("async"); s != "" {
+		if async, err = strconv.ParseBool(s); err != nil {
+			er := errors.Wrap(err, "invalid async").Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	userID, requestID := c.GetString(middleware.UserIDKey), c.GetString(middleware.RequestIDKey)
+	screen := func(total float32) *orderFailure {
+		return h.screenOrder(userID, data, total, device)
+	}
+	// Test orders are neither screened nor counted for fraud checks.
+	if data.Sandbox = middleware.InSandbox(c); data.Sandbox {
+		screen = nil
+	}
+	if async {
+		p := h.placeAsync(userID, requestID, data, screen)
+		h.Logger.Info("CreateOrder method has finished successfully")
+		h.render(c, http.StatusAccepted, p)
+		return
+	}
+
+	ctx, cancel := callContext(c, time.Second*5)
+	defer cancel()
+
+	res, fail := h.placeOrder(ctx, data, screen)
+	if fail != nil {
+		c.AbortWithStatusJSON(fail.status, fail.body)
+		h.Logger.Error(fail.err)
+		return
+	}
+	h.customerPlaced(userID, requestID, data)
+
 	h.Logger.Info("Order created successfully")
-	c.JSON(http.StatusOK, res)
+	h.renderOrder(c, res)
 }
 
-// GetOrderByID godoc
-// @Summary Gets an order
-// @Description Gets order from database
+// customerPlaced records an order the customer placed themselves for the
+// fraud and abuse checks. Only such orders tell where the customer is.
+func (h *Handler) customerPlaced(userID, requestID string, data models.NewOrder) {
+	if data.Sandbox {
+		return
+	}
+	h.Fraud.Placed(userID)
+	if data.Location != nil {
+		h.Abuse.Order(userID, requestID, *data.Location, time.Now())
+	}
+}
+
+// orderFailure is why an order could not be placed, with the response
+// to send.
+type orderFailure struct {
+	status int
+	err    string
+	body   gin.H
+}
+
+func failOrder(status int, er string) *orderFailure {
+	return &orderFailure{status: status, err: er, body: gin.H{"error": er}}
+}
+
+// placeOrder checks the notes and delivery preferences, routes the order to its
+// location, prices the items, checks the kitchen's rules and the submitted total and
+// creates the order, then announces it to webhooks, events and the user's feed.
+// screen, when given, is asked about the order once its total is known.
+func (h *Handler) placeOrder(ctx context.Context, data models.NewOrder, screen func(total float32) *orderFailure) (*pb.NewOrderResp, *orderFailure) {
+	if fail := h.checkNotes(ctx, &data); fail != nil {
+		return nil, fail
+	}
+	if data.DeliveryPreferences != nil {
+		if err := validateDeliveryPreferences(data.DeliveryPreferences); err != nil {
+			return nil, failOrder(http.StatusBadRequest, errors.Wrap(err, "invalid order data").Error())
+		}
+	}
+	if data.Location == nil {
+		return nil, failOrder(http.StatusBadRequest, "invalid order data: location is required")
+	}
+	if err := data.Location.Validate(); err != nil {
+		return nil, failOrder(http.StatusBadRequest, errors.Wrap(err, "invalid order data").Error())
+	}
+	// The delivery fee is priced by the routed distance only, so it can't be
+	// talked down by the client.
+	route, err := h.estimateDelivery(ctx, data.KitchenId, *data.Location)
+	if err != nil {
+		return nil, failOrder(http.StatusUnprocessableEntity, errors.Wrap(err, "error routing order").Error())
+	}
+	data.DistanceKm = route.DistanceKm
+
+	items, priced, err := h.priceItems(ctx, data.Items)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Cause(err) == models.ErrInvalidModifier {
+			status = http.StatusBadRequest
+		}
+		return nil, failOrder(status, errors.Wrap(err, "error pricing order").Error())
+	}
+
+	total, discount := pricing.Totals(priced)
+	quote := h.Pricing.Quote(pricing.Input{
+		Subtotal:   total,
+		DistanceKm: data.DistanceKm,
+		Discount:   discount,
+		Items:      priced,
+	})
+
+	var ruleErr *models.RuleError
+	if err := h.checkOrderRules(data, quote, time.Now()); errors.As(err, &ruleErr) {
+		er := ruleErr.Error()
+		return nil, &orderFailure{status: http.StatusUnprocessableEntity, err: er,
+			body: gin.H{"error": er, "rule": ruleErr.Rule}}
+	}
+
+	if data.TotalAmount != nil && !quote.Matches(*data.TotalAmount) {
+		er := errors.Errorf("total amount mismatch: expected %.2f, got %.2f",
+			quote.Total, *data.TotalAmount).Error()
+		return nil, &orderFailure{status: http.StatusConflict, err: er,
+			body: gin.H{"error": er, "total_amount": quote.Total, "quote": quote}}
+	}
+
+	if screen != nil {
+		if fail := screen(quote.Total); fail != nil {
+			return nil, fail
+		}
+	}
+
+	commission, err := h.commissionRate(ctx, data.KitchenId)
+	if err != nil {
+		status, _ := errorStatus(err)
+		return nil, failOrder(status, errors.Wrap(err, "error getting commission rate").Error())
+	}
+
+	// Portions are taken before the order is created so two customers
+	// cannot both get the last one, and given back if it fails.
+	quantities, reserved := orderQuantities(data.Items), time.Now()
+	soldOut, err := h.Stock.Reserve(quantities, reserved)
+	var stockErr *models.StockError
+	if errors.As(err, &stockErr) {
+		er := stockErr.Error()
+		return nil, &orderFailure{status: http.StatusConflict, err: er,
+			body: gin.H{"error": er, "dish_id": stockErr.DishId, "remaining": stockErr.Remaining}}
+	}
+
+	var modified []models.OrderItem
+	for _, item := range data.Items {
+		if len(item.Modifiers) > 0 {
+			modified = append(modified, item)
+		}
+	}
+
+	if len(modified) > 0 {
+		mods, err := json.Marshal(modified)
+		if err != nil {
+			h.Stock.Release(quantities, reserved, time.Now())
+			return nil, failOrder(http.StatusInternalServerError,
+				errors.Wrap(err, "error encoding modifiers").Error())
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-order-modifiers", string(mods))
+	}
+
+	if discount > 0 {
+		discounts, err := json.Marshal(priced)
+		if err != nil {
+			h.Stock.Release(quantities, reserved, time.Now())
+			return nil, failOrder(http.StatusInternalServerError,
+				errors.Wrap(err, "error encoding discounts").Error())
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-order-discounts", string(discounts))
+	}
+
+	notes, hasNotes := orderNotes(data)
+	if hasNotes {
+		encoded, err := json.Marshal(notes)
+		if err != nil {
+			h.Stock.Release(quantities, reserved, time.Now())
+			return nil, failOrder(http.StatusInternalServerError,
+				errors.Wrap(err, "error encoding notes").Error())
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-order-notes", string(encoded))
+	}
+
+	if data.DeliveryPreferences != nil {
+		prefs, err := json.Marshal(data.DeliveryPreferences)
+		if err != nil {
+			h.Stock.Release(quantities, reserved, time.Now())
+			return nil, failOrder(http.StatusInternalServerError,
+				errors.Wrap(err, "error encoding delivery preferences").Error())
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-order-delivery", string(prefs))
+	}
+
+	// The order service is told about test orders so kitchens can tell
+	// them apart.
+	if data.Sandbox {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-order-sandbox", "true")
+	}
+
+	res, err := h.OrderClient.MakeOrder(ctx, &pb.NewOrder{
+		UserId:          data.UserId,
+		KitchenId:       data.KitchenId,
+		Items:           items,
+		DeliveryAddress: data.DeliveryAddress,
+		DeliveryTime:    data.DeliveryTime,
+	})
+	if err != nil {
+		h.Stock.Release(quantities, reserved, time.Now())
+		return nil, failOrder(http.StatusInternalServerError,
+			errors.Wrap(err, "error creating order").Error())
+	}
+	if len(soldOut) > 0 {
+		go h.syncAvailability(soldOut, false)
+	}
+
+	// The order service does not know about modifiers, discounts or fees
+	// yet, so the gateway's total, the one total_amount is checked
+	// against, is the authoritative one.
+	res.TotalAmount = quote.Total
+	if discount > 0 {
+		h.Storage.OrderPricing.Set(res.Id, models.OrderPricing{
+			Subtotal: quote.Subtotal,
+			Discount: quote.Discount,
+			Items:    priced,
+		})
+	}
+	if hasNotes {
+		h.Storage.OrderNotes.Set(res.Id, notes)
+	}
+	if data.DeliveryPreferences != nil {
+		h.Storage.OrderDelivery.Set(res.Id, *data.DeliveryPreferences)
+	}
+	if data.Sandbox {
+		h.Storage.SandboxOrders.Set(res.Id, models.SandboxOrder{
+			KitchenId:    data.KitchenId,
+			Amount:       res.TotalAmount,
+			Items:        priced,
+			DeliveryTime: res.DeliveryTime,
+		})
+	}
+
+	// Rate changes apply to new orders only, so the kitchen's earnings
+	// keep the rate in effect when the order was placed.
+	h.Storage.OrderCommissions.Set(res.Id, commission.Percent)
+
+	if t, ok := h.Storage.OrderTimers.Get(data.KitchenId); ok {
+		h.OrderTimers.Start(t, res.Id, res.Status, time.Now())
+	}
+
+	h.Webhooks.Dispatch(data.KitchenId, models.EventOrderCreated, res)
+	h.Events.Emit(models.EventOrderCreated, res.Id, res)
+	h.recordOrderPlaced(res)
+	h.statusChanged(res.Id, res.Status)
+
+	return res, nil
+}
+
+// QuoteOrder godoc
+// @Summary Quotes an order
+// @Description Returns an itemized price (subtotal, fees, tax, discounts) for the items without creating an order. Each item shows its unit price before and after any active dish discount
+// @Description With kitchen_id and location the delivery fee is priced by the road distance from the kitchen instead of distance_km, as orders always are
+// @Description Dishes holding allergens in the customer's allergy profile are listed in warnings
+// @Failure 422 {object} string "Kitchen location not set, location not covered or unreachable"
 // @Tags order
 // @Security ApiKeyAuth
-// @Param id path string true "Order ID"
-// @Success 200 {object} order.OrderInfo
-// @Failure 400 {object} string "Invalid order ID"
+// @Param order body models.QuoteRequest true "Order items"
+// @Success 200 {object} pricing.Quote
+// @Failure 400 {object} string "Invalid order data"
 // @Failure 500 {object} string "Server error while processing request"
-// @Router /orders/{id} [get]
-func (h *Handler) GetOrderByID(c *gin.Context) {
-	h.Logger.Info("GetOrderByID method is starting")
+// @Router /orders/quote [post]
+func (h *Handler) QuoteOrder(c *gin.Context) {
+	h.Logger.Info("QuoteOrder method is starting")
 
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid order id").Error()
+	var data models.QuoteRequest
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid order data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if len(data.Items) == 0 || data.DistanceKm < 0 {
+		er := errors.New("invalid order data: items are required and distance must not be negative").Error()
 		c.AbortWithStatusJSON(http.StatusBadRequest,
 			gin.H{"error": er})
 		h.Logger.Error(er)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
+	if data.Location != nil {
+		err := data.Location.Validate()
+		if err == nil && data.KitchenId == "" {
+			err = errors.New("kitchen_id is required with location")
+		}
+		if err != nil {
+			er := errors.Wrap(err, "invalid order data").Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	for _, item := range data.Items {
+		if item.Quantity <= 0 {
+			er := errors.New("invalid order data: quantity must be positive").Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	ctx, cancel := callContext(c, time.Second*5)
 	defer cancel()
 
-	res, err := h.OrderClient.GetOrderByID(ctx, &pb.ID{Id: id})
+	distance, err := h.deliveryDistance(ctx, data.KitchenId, data.Location, data.DistanceKm)
+	if err != nil {
+		er := errors.Wrap(err, "error routing order").Error()
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	_, priced, err := h.priceItems(ctx, data.Items)
 	if err != nil {
-		er := errors.Wrap(err, "error getting order").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
+		status := http.StatusInternalServerError
+		if errors.Cause(err) == models.ErrInvalidModifier {
+			status = http.StatusBadRequest
+		}
+		er := errors.Wrap(err, "error pricing order").Error()
+		c.AbortWithStatusJSON(status,
 			gin.H{"error": er})
 		h.Logger.Error(er)
 		return
 	}
 
-	h.Logger.Info("GetOrderByID method has finished successfully")
-	c.JSON(http.StatusOK, res)
+	total, discount := pricing.Totals(priced)
+	quote := h.Pricing.Quote(pricing.Input{
+		Subtotal:   total,
+		DistanceKm: distance,
+		Discount:   discount,
+		Items:      priced,
+	})
+	quote.Warnings = h.allergenWarnings(ctx, c.GetString(middleware.UserIDKey), data.Items)
+
+	h.Logger.Info("QuoteOrder method has finished successfully")
+	h.render(c, http.StatusOK, quote)
+}
+
+// renderOrder renders an order service response with the pricing the
+// order got from dish discounts, its notes and its delivery preferences,
+// if any, marking sandbox orders.
+func (h *Handler) renderOrder(c *gin.Context, res proto.Message) {
+	data, err := h.encode(c, res)
+	if err != nil {
+		h.write(c, http.StatusOK, res, data, err)
+		return
+	}
+
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		h.write(c, http.StatusOK, res, data, err)
+		return
+	}
+
+	if id, ok := v["id"].(string); ok {
+		if p, ok := h.Storage.OrderPricing.Get(id); ok {
+			v["pricing"] = p
+		}
+	}
+	h.addNotes(v)
+	h.addDelivery(v)
+	h.addSandbox(v)
+
+	data, err = json.Marshal(v)
+	h.write(c, http.StatusOK, res, data, err)
+}
+
+// priceItems reads current dish prices, validates modifier selections
+// and applies active dish discounts. It returns the items for MakeOrder
+// along with how each was priced.
+func (h *Handler) priceItems(ctx context.Context, items []models.OrderItem) ([]*pb.Item, []models.PricedItem, error) {
+	now := time.Now()
+	res := make([]*pb.Item, 0, len(items))
+	priced := make([]models.PricedItem, 0, len(items))
+
+	for _, item := range items {
+		_, price, err := h.itemPrice(ctx, item)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		priced = append(priced, pricing.Apply(models.PricedItem{
+			DishId:    item.DishId,
+			Quantity:  item.Quantity,
+			UnitPrice: price,
+		}, h.bestDiscount(item.DishId, now)))
+		res = append(res, &pb.Item{
+			DishId:   item.DishId,
+			Quantity: item.Quantity,
+		})
+	}
+
+	return res, priced, nil
+}
+
+// itemPrice returns the dish of the item and its unit price with the
+// selected modifiers.
+func (h *Handler) itemPrice(ctx context.Context, item models.OrderItem) (*pbd.DishInfo, float32, error) {
+	dish, err := h.DishClient.Read(ctx, &pbd.ID{Id: item.DishId})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "error getting dish %s", item.DishId)
+	}
+
+	price := dish.Price
+	mods, ok := h.Storage.Modifiers.Get(item.DishId)
+	if !ok && len(item.Modifiers) > 0 {
+		return nil, 0, errors.Wrapf(models.ErrInvalidModifier, "dish %s has no modifiers", item.DishId)
+	}
+	if ok {
+		extra, err := mods.Price(item.Modifiers)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "dish %s", item.DishId)
+		}
+		price += extra
+	}
+
+	return dish, price, nil
+}
+
+// GetOrderByID godoc
+// @Summary Gets an order
+// @Description Gets order from database, with its notes and delivery preferences. Orders that got dish discounts include their pricing. For the customer, the kitchen's owner and staff with the orders permission, and admins
+// @Tags order
+// @Security ApiKeyAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} order.OrderInfo
+// @Failure 400 {object} string "Invalid order ID"
+// @Failure 403 {object} string "Not the order's customer or kitchen"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /orders/{id} [get]
+func (h *Handler) GetOrderByID(c *gin.Context) {
+	serve(h, c, Proxy[*pb.ID, *pb.OrderInfo]{
+		Name: "GetOrderByID",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "order id")
+			return &pb.ID{Id: id}, err
+		},
+		Call:  h.OrderClient.GetOrderByID,
+		Error: "error getting order",
+		Render: func(c *gin.Context, res *pb.OrderInfo) {
+			// The order carries where and how to deliver it.
+			if _, ok := h.accessRole(c, res.UserId, res.KitchenId, models.StaffOrders); !ok {
+				return
+			}
+			h.renderOrder(c, res)
+		},
+	})
 }
 
 // ChangeStatus godoc
 // @Summary Updates an order
-// @Description Updates order status in database
+// @Description Updates order status in database. Once the kitchen responds, its order timer no longer applies to the order
 // @Tags order
 // @Security ApiKeyAuth
 // @Param id path string true "Order ID"
@@ -101,44 +550,51 @@ func (h *Handler) GetOrderByID(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /orders/{id}/status [put]
 func (h *Handler) ChangeStatus(c *gin.Context) {
-	h.Logger.Info("ChangeStatus method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid order id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	var data pb.StatusNoID
-	if err := c.ShouldBindJSON(&data); err != nil {
-		er := errors.Wrap(err, "invalid order data").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
+	serve(h, c, Proxy[*pb.Status, *pb.UpdatedOrder]{
+		Name: "ChangeStatus",
+		Bind: func(c *gin.Context) (*pb.Status, error) {
+			id, err := pathUUID(c, "id", "order id")
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := bindJSON[pb.StatusNoID](c, "order data")
+			if err != nil {
+				return nil, err
+			}
+
+			return &pb.Status{
+				Id:     id,
+				Status: data.Status,
+			}, nil
+		},
+		Call:  h.OrderClient.ChangeStatus,
+		Error: "error changing order status",
+		After: func(res *pb.UpdatedOrder) {
+			h.OrderTimers.Stop(res.Id)
+			h.statusChanged(res.Id, res.Status)
+			h.Events.Emit(models.EventOrderStatusChanged, res.Id, res)
+			go h.dispatchStatusChanged(res)
+		},
+	})
+}
 
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
+// dispatchStatusChanged notifies the kitchen's webhooks, texts the
+// customer and emails the receipt once the order is delivered.
+// UpdatedOrder carries no kitchen ID, so the order is read first.
+func (h *Handler) dispatchStatusChanged(upd *pb.UpdatedOrder) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	res, err := h.OrderClient.ChangeStatus(ctx, &pb.Status{
-		Id:     id,
-		Status: data.Status,
-	})
+	info, err := h.OrderClient.GetOrderByID(ctx, &pb.ID{Id: upd.Id})
 	if err != nil {
-		er := errors.Wrap(err, "error changing order status").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
+		h.Logger.Error(errors.Wrap(err, "error getting order for webhooks").Error())
 		return
 	}
 
-	h.Logger.Info("ChangeStatus method has finished successfully")
-	c.JSON(http.StatusOK, res)
+	h.Webhooks.Dispatch(info.KitchenId, models.EventOrderStatusChanged, upd)
+	h.smsOrderStatus(ctx, info)
+	h.emailReceipt(ctx, info)
 }
 
 // FetchOrdersForCustomer godoc
@@ -152,51 +608,23 @@ func (h *Handler) ChangeStatus(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /orders [get]
 func (h *Handler) FetchOrdersForCustomer(c *gin.Context) {
-	h.Logger.Info("FetchOrdersForCustomer method is starting")
-
-	page := c.Query("page")
-	limit := c.Query("limit")
-
-	p, err := strconv.Atoi(page)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	l, err := strconv.Atoi(limit)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.OrderClient.FetchOrdersForCustomer(ctx, &pb.Pagination{
-		Limit:  int32(l),
-		Offset: int32((p - 1) * l),
+	serve(h, c, Proxy[*pb.Pagination, *pb.OrdersCustomer]{
+		Name: "FetchOrdersForCustomer",
+		Bind: func(c *gin.Context) (*pb.Pagination, error) {
+			limit, offset, err := pagination(c)
+			return &pb.Pagination{Limit: limit, Offset: offset}, err
+		},
+		Call:  h.OrderClient.FetchOrdersForCustomer,
+		Error: "error getting orders",
+		Render: func(c *gin.Context, res *pb.OrdersCustomer) {
+			h.renderList(c, res, "orders", nil)
+		},
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error getting orders").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("FetchOrdersForCustomer method has finished successfully")
-	c.JSON(http.StatusOK, res)
 }
 
 // FetchOrdersForKitchen godoc
 // @Summary Gets orders for kitchen
-// @Description Gets orders from database
+// @Description Gets orders from database, with the notes customers left on each order and its items and their delivery preferences. For the kitchen's owner, staff with the orders permission and admins
 // @Tags order
 // @Security ApiKeyAuth
 // @Param id path string true "Kitchen ID"
@@ -204,62 +632,50 @@ func (h *Handler) FetchOrdersForCustomer(c *gin.Context) {
 // @Param page query int true "Page number"
 // @Param limit query int true "Number of items per page"
 // @Success 200 {object} order.OrdersKitchen
+// @Failure 403 {object} string "Access denied"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /kitchens/{id}/orders [get]
 func (h *Handler) FetchOrdersForKitchen(c *gin.Context) {
-	h.Logger.Info("FetchOrdersForKitchen method is starting")
-
-	kitchenID := c.Param("id")
-	status := c.Query("status")
-	page := c.Query("page")
-	limit := c.Query("limit")
-
-	_, err := uuid.Parse(kitchenID)
-	if err != nil {
-		er := errors.Wrap(err, "invalid dish ID").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	p, err := strconv.Atoi(page)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	l, err := strconv.Atoi(limit)
-	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	res, err := h.OrderClient.FetchOrdersForKitchen(ctx, &pb.Filter{
-		KitchenId: kitchenID,
-		Status:    status,
-		Pagination: &pb.Pagination{
-			Limit:  int32(l),
-			Offset: int32((p - 1) * l),
+	serve(h, c, Proxy[*pb.Filter, *pb.OrdersKitchen]{
+		Name: "FetchOrdersForKitchen",
+		Bind: func(c *gin.Context) (*pb.Filter, error) {
+			kitchenID, err := pathUUID(c, "id", "kitchen ID")
+			if err != nil {
+				return nil, err
+			}
+
+			limit, offset, err := pagination(c)
+			if err != nil {
+				return nil, err
+			}
+
+			return &pb.Filter{
+				KitchenId: kitchenID,
+				Status:    c.Query("status"),
+				Pagination: &pb.Pagination{
+					Limit:  limit,
+					Offset: offset,
+				},
+			}, nil
+		},
+		Call:  h.OrderClient.FetchOrdersForKitchen,
+		Error: "error getting orders",
+		Render: func(c *gin.Context, res *pb.OrdersKitchen) {
+			if _, ok := h.accessRole(c, "", c.Param("id"), models.StaffOrders); !ok {
+				return
+			}
+			h.renderKitchenOrders(c, res)
 		},
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error getting orders").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
+}
+
+// addSandbox marks test orders placed in the sandbox.
+func (h *Handler) addSandbox(order map[string]any) {
+	id, ok := order["id"].(string)
+	if !ok {
 		return
 	}
-
-	h.Logger.Info("FetchOrdersForKitchen method has finished successfully")
-	c.JSON(http.StatusOK, res)
+	if _, ok := h.Storage.SandboxOrders.Get(id); ok {
+		order["sandbox"] = true
+	}
 }
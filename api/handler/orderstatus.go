@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// pollTimeout is the longest a long poll is held.
+const pollTimeout = 30 * time.Second
+
+// statusChanged records the status an order has now, waking its long
+// polls and telling the partner that placed it when it changed.
+func (h *Handler) statusChanged(orderID, status string) models.OrderStatus {
+	changed := false
+	res := h.Storage.OrderStatuses.Update(orderID, func(s models.OrderStatus, ok bool) models.OrderStatus {
+		if ok && s.Status == status {
+			return s
+		}
+		changed = true
+		return models.OrderStatus{
+			OrderId:   orderID,
+			Status:    status,
+			Version:   s.Version + 1,
+			UpdatedAt: time.Now().Format(time.RFC3339),
+		}
+	})
+	if changed {
+		h.Statuses.Publish(orderID, res)
+		h.notifyPartner(res)
+	}
+	return res
+}
+
+// PollOrderStatus godoc
+// @Summary Waits for an order's status to change
+// @Description Long poll for clients that can't keep a WebSocket open. Answers at once when the status's version differs from since_version, otherwise when the status changes or after 30 seconds with the status unchanged; either way, poll again with the version answered. For the customer, the kitchen and admins
+// @Tags order
+// @Security ApiKeyAuth
+// @Param id path string true "Order ID"
+// @Param since_version query int false "Version of the status the client has, 0 for none"
+// @Success 200 {object} models.OrderStatus
+// @Failure 400 {object} string "Invalid order ID or since_version"
+// @Failure 403 {object} string "Not the order's customer or kitchen"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /orders/{id}/status/poll [get]
+func (h *Handler) PollOrderStatus(c *gin.Context) {
+	h.Logger.Info("PollOrderStatus method is starting")
+
+	since := 0
+	if s := c.Query("since_version"); s != "" {
+		var err error
+		if since, err = strconv.Atoi(s); err != nil || since < 0 {
+			er := errors.New("invalid since_version: must be a non-negative integer").Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	order, ok := h.deliveryOrder(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.accessRole(c, order.UserId, order.KitchenId, models.StaffOrders); !ok {
+		return
+	}
+
+	// Subscribing first, no change is missed between reading the status
+	// and waiting. Changes made around the gateway are caught up with
+	// here, from the order just read.
+	updates, stop := h.Statuses.Subscribe(order.Id)
+	defer stop()
+	res := h.statusChanged(order.Id, order.Status)
+
+	if res.Version == since {
+		timer := time.NewTimer(pollTimeout)
+		defer timer.Stop()
+
+		select {
+		case res = <-updates:
+		case <-timer.C:
+		case <-c.Request.Context().Done():
+			h.Logger.Info("PollOrderStatus client left before the status changed")
+			return
+		}
+	}
+
+	h.Logger.Info("PollOrderStatus method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/order"
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// maxTimerMinutes is a day.
+const maxTimerMinutes = 24 * 60
+
+// GetOrderTimer godoc
+// @Summary Gets a kitchen's order timer
+// @Description Tells whether orders the kitchen doesn't respond to are accepted or rejected, and after how many minutes. For the kitchen's owner and staff with the orders permission
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.OrderTimer
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Not the kitchen's owner or staff"
+// @Router /kitchens/{id}/order-timer [get]
+func (h *Handler) GetOrderTimer(c *gin.Context) {
+	h.Logger.Info("GetOrderTimer method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffOrders); !ok {
+		return
+	}
+
+	t, ok := h.Storage.OrderTimers.Get(kitchenID)
+	if !ok {
+		t.KitchenId = kitchenID
+	}
+
+	h.Logger.Info("GetOrderTimer method has finished successfully")
+	h.render(c, http.StatusOK, t)
+}
+
+// SetOrderTimer godoc
+// @Summary Sets a kitchen's order timer
+// @Description Accepts or rejects orders the kitchen leaves without a response for after_minutes; accept with zero minutes accepts every order at once, and an empty action turns the timer off. The customer is notified as if the kitchen had responded. Orders placed before the change keep their timer. For the kitchen's owner and admins
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param timer body models.NewOrderTimer true "Order timer"
+// @Success 200 {object} models.OrderTimer
+// @Failure 400 {object} string "Invalid kitchen ID or timer"
+// @Failure 403 {object} string "Not the kitchen's owner"
+// @Router /kitchens/{id}/order-timer [put]
+func (h *Handler) SetOrderTimer(c *gin.Context) {
+	h.Logger.Info("SetOrderTimer method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+	userID, _, _ := h.caller(c)
+
+	var data models.NewOrderTimer
+	err = c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateOrderTimer(data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid order timer").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	t := models.OrderTimer{
+		KitchenId:     kitchenID,
+		NewOrderTimer: data,
+		UpdatedBy:     userID,
+		UpdatedAt:     time.Now().Format(time.RFC3339),
+	}
+	h.Storage.OrderTimers.Set(kitchenID, t)
+
+	h.Logger.Info("SetOrderTimer method has finished successfully")
+	h.render(c, http.StatusOK, t)
+}
+
+func validateOrderTimer(data models.NewOrderTimer) error {
+	switch data.Action {
+	case "":
+		return nil
+	case models.AutoAccept:
+		if data.AfterMinutes < 0 || data.AfterMinutes > maxTimerMinutes {
+			return errors.Errorf("after_minutes must be between 0 and %d", maxTimerMinutes)
+		}
+	case models.AutoReject:
+		if data.AfterMinutes < 1 || data.AfterMinutes > maxTimerMinutes {
+			return errors.Errorf("after_minutes must be between 1 and %d", maxTimerMinutes)
+		}
+	default:
+		return errors.Errorf("action must be %s, %s or empty", models.AutoAccept, models.AutoReject)
+	}
+	return nil
+}
+
+// respondToOrder changes the status of an order whose kitchen's timer is
+// due, then notifies like a status change by the kitchen. Orders whose
+// status moved on since they were placed are left alone.
+func (h *Handler) respondToOrder(ctx context.Context, p models.PendingOrder) error {
+	info, err := h.OrderClient.GetOrderByID(ctx, &pb.ID{Id: p.OrderId})
+	if err != nil {
+		return errors.Wrap(err, "error getting order")
+	}
+	if info.Status != p.Status {
+		return nil
+	}
+
+	status := models.OrderAccepted
+	if p.Action == models.AutoReject {
+		status = models.OrderRejected
+	}
+	res, err := h.OrderClient.ChangeStatus(ctx, &pb.Status{Id: p.OrderId, Status: status})
+	if err != nil {
+		return errors.Wrap(err, "error changing order status")
+	}
+	h.Logger.Info("order " + p.OrderId + " was " + status + " by its kitchen's timer")
+
+	h.statusChanged(res.Id, res.Status)
+	h.Events.Emit(models.EventOrderStatusChanged, res.Id, res)
+	go h.dispatchStatusChanged(res)
+	return nil
+}
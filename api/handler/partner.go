@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	"api-gateway/models"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// partnerKeyPrefix starts partner keys, so leaked ones are easy to
+	// scan for.
+	partnerKeyPrefix = "lep_"
+	maxDeltaLimit    = 1000
+)
+
+// CreatePartnerKey godoc
+// @Summary Issues a partner API key
+// @Description Issues an API key for a partner aggregator to call the partner routes with in X-API-Key, and the secret to sign its order requests with. Both are returned only now; the gateway keeps a hash of the key. Partners approved with orders may place orders, whose statuses are sent to webhook_url. Keys with sandbox place test orders only
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param key body models.NewPartnerKey true "Partner"
+// @Success 200 {object} models.CreatedPartnerKey
+// @Failure 400 {object} string "Invalid partner key data"
+// @Router /admin/partners/keys [post]
+func (h *Handler) CreatePartnerKey(c *gin.Context) {
+	h.Logger.Info("CreatePartnerKey method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewPartnerKey
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validatePartnerKey(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid partner key data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	random := make([]byte, 64)
+	if _, err := rand.Read(random); err != nil {
+		er := errors.Wrap(err, "error generating partner key").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	key := partnerKeyPrefix + hex.EncodeToString(random[:32])
+
+	k := models.PartnerKey{
+		Id:                 uuid.NewString(),
+		Partner:            data.Partner,
+		Prefix:             key[:len(partnerKeyPrefix)+8],
+		PartnerKeySettings: data.PartnerKeySettings,
+		CreatedBy:          userID,
+		CreatedAt:          time.Now().Format(time.RFC3339),
+		Hash:               middleware.HashKey(key),
+		Secret:             hex.EncodeToString(random[32:]),
+	}
+	h.Storage.PartnerKeys.Set(k.Hash, k)
+
+	h.Logger.Info("CreatePartnerKey method has finished successfully")
+	h.render(c, http.StatusOK, models.CreatedPartnerKey{PartnerKey: k, Key: key, Secret: k.Secret})
+}
+
+// FetchPartnerKeys godoc
+// @Summary Gets partner API keys
+// @Description Lists the partner API keys, newest first, with when each was last used and whether it was revoked
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.PartnerKeys
+// @Router /admin/partners/keys [get]
+func (h *Handler) FetchPartnerKeys(c *gin.Context) {
+	h.Logger.Info("FetchPartnerKeys method is starting")
+
+	res := models.PartnerKeys{Keys: h.Storage.PartnerKeys.List()}
+	slices.SortFunc(res.Keys, func(a, b models.PartnerKey) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchPartnerKeys method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// RevokePartnerKey godoc
+// @Summary Revokes a partner API key
+// @Description Revokes a partner API key; calls with it are refused from now on
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Key ID"
+// @Success 200 {object} models.PartnerKey
+// @Failure 400 {object} string "Invalid key ID"
+// @Failure 404 {object} string "Key not found"
+// @Router /admin/partners/keys/{id} [delete]
+func (h *Handler) RevokePartnerKey(c *gin.Context) {
+	h.Logger.Info("RevokePartnerKey method is starting")
+
+	id, err := pathUUID(c, "id", "key id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	k, ok := h.findPartnerKey(id)
+	if !ok {
+		er := "partner key not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	k = h.Storage.PartnerKeys.Update(k.Hash, func(k models.PartnerKey, _ bool) models.PartnerKey {
+		if k.RevokedAt == "" {
+			k.RevokedAt = time.Now().Format(time.RFC3339)
+		}
+		return k
+	})
+
+	h.Logger.Info("RevokePartnerKey method has finished successfully")
+	h.render(c, http.StatusOK, k)
+}
+
+// UpdatePartnerKey godoc
+// @Summary Changes a partner API key's settings
+// @Description Approves or stops the key's partner placing orders, sets where the statuses of its orders are sent, and moves the key in or out of the sandbox, where orders are test data
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Key ID"
+// @Param settings body models.PartnerKeySettings true "Settings"
+// @Success 200 {object} models.PartnerKey
+// @Failure 400 {object} string "Invalid key ID or settings"
+// @Failure 404 {object} string "Key not found"
+// @Router /admin/partners/keys/{id} [put]
+func (h *Handler) UpdatePartnerKey(c *gin.Context) {
+	h.Logger.Info("UpdatePartnerKey method is starting")
+
+	id, err := pathUUID(c, "id", "key id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var data models.PartnerKeySettings
+	err = c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validatePartnerKeySettings(data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid partner key settings").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	k, ok := h.findPartnerKey(id)
+	if !ok {
+		er := "partner key not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	k = h.Storage.PartnerKeys.Update(k.Hash, func(k models.PartnerKey, _ bool) models.PartnerKey {
+		k.PartnerKeySettings = data
+		return k
+	})
+
+	h.Logger.Info("UpdatePartnerKey method has finished successfully")
+	h.render(c, http.StatusOK, k)
+}
+
+// GetCatalogDelta godoc
+// @Summary Syncs changed kitchens and dishes
+// @Description For partner aggregators: the kitchens and dishes created, updated or deleted at or after since, oldest first, each with the entity as it was after the change. Pages are linked in Link headers. Use the synced_at of the first page as the since of the next sync. Changes are kept for PARTNER_DELTA_RETENTION_DAYS and since the gateway started; a since before that is answered with 410 and needs a full crawl
+// @Tags partner
+// @Param X-API-Key header string true "Partner API key"
+// @Param since query string true "RFC 3339 time"
+// @Param page query int true "Page number"
+// @Param limit query int true "Number of changes per page, up to 1000"
+// @Success 200 {object} models.CatalogDelta
+// @Failure 400 {object} string "Invalid since or pagination parameters"
+// @Failure 401 {object} string "Invalid API key"
+// @Failure 410 {object} string "Changes since then are not kept"
+// @Router /partners/kitchens/delta [get]
+func (h *Handler) GetCatalogDelta(c *gin.Context) {
+	h.Logger.Info("GetCatalogDelta method is starting")
+
+	now := time.Now()
+	since, err := time.Parse(time.RFC3339, c.Query("since"))
+	if err != nil {
+		er := errors.Wrap(err, "invalid since").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	limit, offset, err := pagination(c)
+	if err == nil && (limit < 1 || limit > maxDeltaLimit || offset < 0) {
+		err = errors.Errorf("invalid pagination parameters: limit must be between 1 and %d", maxDeltaLimit)
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if oldest := h.Catalog.Oldest(now); since.Before(oldest) {
+		er := "changes before " + oldest.Format(time.RFC3339) + " are not kept, crawl the full catalog"
+		c.AbortWithStatusJSON(http.StatusGone,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	changes := h.Catalog.Since(since)
+	start := min(int(offset), len(changes))
+	end := min(start+int(limit), len(changes))
+	res := models.CatalogDelta{
+		Changes:  changes[start:end],
+		Total:    int32(len(changes)),
+		Since:    since.Format(time.RFC3339),
+		SyncedAt: now.UTC().Format(time.RFC3339),
+	}
+
+	h.Logger.Info("GetCatalogDelta method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// findPartnerKey finds a partner key by ID.
+func (h *Handler) findPartnerKey(id string) (models.PartnerKey, bool) {
+	keys := h.Storage.PartnerKeys.List()
+	i := slices.IndexFunc(keys, func(k models.PartnerKey) bool {
+		return k.Id == id
+	})
+	if i < 0 {
+		return models.PartnerKey{}, false
+	}
+	return keys[i], true
+}
+
+func validatePartnerKey(data *models.NewPartnerKey) error {
+	data.Partner = strings.TrimSpace(data.Partner)
+	if data.Partner == "" {
+		return errors.New("partner is required")
+	}
+	// Partner orders are keyed by the partner's name and "|".
+	if strings.Contains(data.Partner, "|") {
+		return errors.New("partner must not contain |")
+	}
+	return validatePartnerKeySettings(data.PartnerKeySettings)
+}
+
+func validatePartnerKeySettings(data models.PartnerKeySettings) error {
+	if data.WebhookUrl == "" {
+		return nil
+	}
+	u, err := url.Parse(data.WebhookUrl)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook_url must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+// trackCatalog records a change of a kitchen or dish for partners. res
+// is the entity after the change, nil for deletions.
+func (h *Handler) trackCatalog(eventType, entity, id, kitchenID string, res proto.Message) {
+	var data []byte
+	if res != nil {
+		var err error
+		if data, err = h.Marshaler.Marshal(res); err != nil {
+			h.Logger.Error(errors.Wrapf(err, "error encoding %s change", entity).Error())
+		}
+	}
+	h.Catalog.Record(eventType, entity, id, kitchenID, data, time.Now())
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	"api-gateway/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// partnerCustomers is the namespace partners' customers are mapped to
+// user IDs in.
+var partnerCustomers = uuid.MustParse("5b0e7c1e-3f0a-4d8e-9a51-2c6f1d0b7e43")
+
+// maxExternalID is the longest external ID partners may send.
+const maxExternalID = 128
+
+// CreatePartnerOrder godoc
+// @Summary Places an order for a partner's customer
+// @Description For partner aggregators approved to place orders. Requests are signed with the key's secret: X-Signature is "sha256=" and the hex HMAC-SHA256 of "<X-Timestamp>.<method>.<path and query>.<body>", such as "1760745600.POST./local-eats/partners/orders.{...}", with X-Timestamp the current Unix time.
+// @Description The partner's customer is mapped to a user of its own by external_customer_id, the same for all their orders. The order is priced, checked and placed like a customer's, without the fraud screening of customers' own orders.
+// @Description external_order_id makes the request idempotent: sending it again returns the order placed the first time with Idempotent-Replayed: true, or 409 while the first is still being placed. Reusing it for a different order is refused with 422.
+// @Description Status changes are sent to the key's webhook_url as order.status_changed, signed the same way
+// @Description Orders placed with sandbox keys are test data, left out of statistics and earnings
+// @Tags partner
+// @Param X-API-Key header string true "Partner API key"
+// @Param X-Timestamp header int true "Unix time of the request"
+// @Param X-Signature header string true "Signature of the request"
+// @Param order body models.NewPartnerOrder true "Order"
+// @Success 200 {object} models.PartnerOrder
+// @Failure 400 {object} string "Invalid order data"
+// @Failure 401 {object} string "Invalid API key or signature"
+// @Failure 403 {object} string "Partner not approved to place orders"
+// @Failure 409 {object} string "The order is still being placed, its total does not match current prices, or too few portions of a dish are left"
+// @Failure 422 {object} string "external_order_id was used for a different order, or the order breaks a kitchen rule"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /partners/orders [post]
+func (h *Handler) CreatePartnerOrder(c *gin.Context) {
+	h.Logger.Info("CreatePartnerOrder method is starting")
+
+	k, ok := h.orderingPartner(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewPartnerOrder
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validatePartnerOrder(data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid order data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// Retries are told from different orders by what they ask for, not
+	// by how the JSON was written.
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		er := errors.Wrap(err, "error encoding order").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	sum := sha256.Sum256(encoded)
+	hash := hex.EncodeToString(sum[:])
+
+	key := k.Partner + "|" + data.ExternalOrderId
+	first := false
+	po := h.Storage.PartnerOrders.Update(key, func(po models.PartnerOrder, ok bool) models.PartnerOrder {
+		if ok {
+			return po
+		}
+		first = true
+		return models.PartnerOrder{
+			Partner:            k.Partner,
+			ExternalOrderId:    data.ExternalOrderId,
+			ExternalCustomerId: data.ExternalCustomerId,
+			UserId:             partnerCustomer(k.Partner, data.ExternalCustomerId),
+			KitchenId:          data.KitchenId,
+			Status:             models.PartnerOrderPlacing,
+			CreatedAt:          time.Now().Format(time.RFC3339),
+			KeyId:              k.Id,
+			RequestHash:        hash,
+		}
+	})
+
+	if !first {
+		switch {
+		case po.RequestHash != hash:
+			er := "external_order_id " + data.ExternalOrderId + " was used for a different order"
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+		case po.OrderId == "":
+			er := "order " + data.ExternalOrderId + " is still being placed"
+			c.AbortWithStatusJSON(http.StatusConflict,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+		default:
+			c.Header("Idempotent-Replayed", "true")
+			h.Logger.Info("CreatePartnerOrder method has finished successfully")
+			h.render(c, http.StatusOK, po)
+		}
+		return
+	}
+
+	ctx, cancel := callContext(c, time.Second*5)
+	defer cancel()
+
+	res, fail := h.placeOrder(ctx, models.NewOrder{
+		UserId:              po.UserId,
+		KitchenId:           data.KitchenId,
+		Items:               data.Items,
+		DeliveryAddress:     data.DeliveryAddress,
+		DeliveryTime:        data.DeliveryTime,
+		TotalAmount:         data.TotalAmount,
+		Location:            data.Location,
+		Note:                data.Note,
+		DeliveryPreferences: data.DeliveryPreferences,
+		Sandbox:             middleware.InSandbox(c),
+	}, nil)
+	if fail != nil {
+		// Nothing was placed, so the partner may try again.
+		h.Storage.PartnerOrders.Delete(key)
+		c.AbortWithStatusJSON(fail.status, fail.body)
+		h.Logger.Error(fail.err)
+		return
+	}
+
+	po = h.Storage.PartnerOrders.Update(key, func(po models.PartnerOrder, _ bool) models.PartnerOrder {
+		po.OrderId = res.Id
+		po.Status = res.Status
+		po.TotalAmount = res.TotalAmount
+		return po
+	})
+	h.Storage.PartnerOrderKeys.Set(res.Id, key)
+
+	h.Logger.Info("CreatePartnerOrder method has finished successfully")
+	h.render(c, http.StatusOK, po)
+}
+
+// GetPartnerOrder godoc
+// @Summary Gets a partner's order
+// @Description Gives the order placed for the partner's external order ID with its current status, for partners to reconcile with. Signed like order requests
+// @Tags partner
+// @Param X-API-Key header string true "Partner API key"
+// @Param X-Timestamp header int true "Unix time of the request"
+// @Param X-Signature header string true "Signature of the request"
+// @Param external_id path string true "External order ID"
+// @Success 200 {object} models.PartnerOrder
+// @Failure 401 {object} string "Invalid API key or signature"
+// @Failure 403 {object} string "Partner not approved to place orders"
+// @Failure 404 {object} string "Order not found"
+// @Router /partners/orders/{external_id} [get]
+func (h *Handler) GetPartnerOrder(c *gin.Context) {
+	h.Logger.Info("GetPartnerOrder method is starting")
+
+	k, ok := h.orderingPartner(c)
+	if !ok {
+		return
+	}
+
+	po, ok := h.Storage.PartnerOrders.Get(k.Partner + "|" + c.Param("external_id"))
+	if !ok {
+		er := "order not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetPartnerOrder method has finished successfully")
+	h.render(c, http.StatusOK, po)
+}
+
+// orderingPartner returns the request's partner key, refusing partners
+// not approved to place orders.
+func (h *Handler) orderingPartner(c *gin.Context) (models.PartnerKey, bool) {
+	k, ok := middleware.PartnerKeyOf(c)
+	if !ok || !k.Orders {
+		er := "partner is not approved to place orders"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return k, false
+	}
+	return k, true
+}
+
+// notifyPartner tells the partner an order was placed for about its new
+// status, at the webhook of the key it was placed with.
+func (h *Handler) notifyPartner(s models.OrderStatus) {
+	key, ok := h.Storage.PartnerOrderKeys.Get(s.OrderId)
+	if !ok {
+		return
+	}
+	po := h.Storage.PartnerOrders.Update(key, func(po models.PartnerOrder, _ bool) models.PartnerOrder {
+		po.Status = s.Status
+		po.UpdatedAt = s.UpdatedAt
+		return po
+	})
+
+	k, ok := h.findPartnerKey(po.KeyId)
+	if !ok || k.RevokedAt != "" || k.WebhookUrl == "" {
+		return
+	}
+	h.Webhooks.Send(models.Webhook{
+		Id:        k.Id,
+		KitchenId: po.KitchenId,
+		Url:       k.WebhookUrl,
+		Secret:    k.Secret,
+	}, models.EventOrderStatusChanged, po)
+}
+
+// partnerCustomer is the user ID a partner's customer is mapped to.
+func partnerCustomer(partner, externalID string) string {
+	return uuid.NewSHA1(partnerCustomers, []byte(partner+"|"+externalID)).String()
+}
+
+func validatePartnerOrder(data models.NewPartnerOrder) error {
+	if data.ExternalOrderId == "" || len(data.ExternalOrderId) > maxExternalID {
+		return errors.Errorf("external_order_id must have 1 to %d characters", maxExternalID)
+	}
+	if data.ExternalCustomerId == "" || len(data.ExternalCustomerId) > maxExternalID {
+		return errors.Errorf("external_customer_id must have 1 to %d characters", maxExternalID)
+	}
+	if _, err := uuid.Parse(data.KitchenId); err != nil {
+		return errors.Wrap(err, "invalid kitchen id")
+	}
+	if len(data.Items) == 0 {
+		return errors.New("at least one item is required")
+	}
+	for _, item := range data.Items {
+		if item.Quantity <= 0 {
+			return errors.New("quantity must be positive")
+		}
+	}
+	return nil
+}
@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"api-gateway/api/middleware"
+	pbo "api-gateway/genproto/order"
 	pb "api-gateway/genproto/payment"
+	"api-gateway/models"
+	"api-gateway/pkg/payments"
+	"api-gateway/pkg/redact"
 	"context"
 	"net/http"
 	"time"
@@ -9,78 +14,135 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/status"
 )
 
 // CreatePayment godoc
 // @Summary Creates a payment
-// @Description Inserts a new payment into database
+// @Description Pays for an order through the requested or the default provider. Hosted checkouts (payme, click) answer with a pending payment and a redirect_url; stripe expects a Stripe.js payment_token instead of card details
+// @Description Payments are screened for fraud: some must wait until the customer passes an OTP, others are held for an admin's review and can be tried again once approved
+// @Description Sandbox orders are paid with fake money by the sandbox provider, whatever provider is asked for: card 4000000000000002 is declined, 4000000000003220 must authenticate, and any other card or token is paid. Orders can only be paid in the mode they were placed in
 // @Tags payment
 // @Security ApiKeyAuth
-// @Param payment body payment.NewPayment true "Payment info"
-// @Success 200 {object} payment.NewPayment
+// @Param payment body models.NewPayment true "Payment info"
+// @Success 200 {object} models.Payment
+// @Success 202 {object} models.Payment "The customer must authenticate the payment (3-D Secure), see challenge; or models.FraudHold when held for review"
 // @Failure 400 {object} string "Invalid payment data"
+// @Failure 402 {object} string "Payment declined"
+// @Failure 403 {object} string "The customer must pass an OTP first, see challenge, or the payment was refused after review"
+// @Failure 404 {object} string "Order not found"
+// @Failure 409 {object} string "The order was placed in the other of sandbox and live mode"
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 502 {object} string "Payment provider error"
 // @Router /payments [post]
 func (h *Handler) CreatePayment(c *gin.Context) {
 	h.Logger.Info("CreatePayment method is starting")
 
-	var data pb.NewPayment
+	var data models.NewPayment
 	if err := c.ShouldBindJSON(&data); err != nil {
-		er := errors.Wrap(err, "invalid payment data").Error()
+		er := redact.PAN(errors.Wrap(err, "invalid payment data").Error())
 		c.AbortWithStatusJSON(http.StatusBadRequest,
 			gin.H{"error": er})
 		h.Logger.Error(er)
 		return
 	}
 
-	if data.CardNumber != "" {
-		if len(data.CardNumber) != 16 {
-			er := errors.New("invalid card number").Error()
-			c.AbortWithStatusJSON(http.StatusBadRequest,
-				gin.H{"error": er})
-			h.Logger.Error(er)
-			return
-		}
+	if _, err := uuid.Parse(data.OrderId); err != nil {
+		er := errors.Wrap(err, "invalid order id").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
 	}
 
-	if data.ExpiryDate != "" {
-		if len(data.ExpiryDate) != 5 {
-			er := errors.New("invalid expiry date").Error()
-			c.AbortWithStatusJSON(http.StatusBadRequest,
-				gin.H{"error": er})
-			h.Logger.Error(er)
-			return
-		}
+	sandbox := middleware.InSandbox(c)
+	provider, err := h.paymentProvider(data.Provider, sandbox)
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
 	}
 
-	if data.Cvv != "" {
-		if len(data.Cvv) != 3 {
-			er := errors.New("invalid CVV").Error()
-			c.AbortWithStatusJSON(http.StatusBadRequest,
-				gin.H{"error": er})
-			h.Logger.Error(er)
-			return
-		}
+	if err := provider.Validate(&data); err != nil {
+		er := redact.PAN(errors.Wrap(err, "invalid payment data").Error())
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
 	}
 
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
+	ctx, cancel := callContext(c, defaultTimeout)
 	defer cancel()
 
-	res, err := h.PaymentClient.MakePayment(ctx, &data)
+	order, err := h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: data.OrderId})
 	if err != nil {
-		er := errors.Wrap(err, "error creating payment").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting order").Error()
+		c.AbortWithStatusJSON(status,
 			gin.H{"error": er})
 		h.Logger.Error(er)
 		return
 	}
 
-	c.JSON(http.StatusOK, res)
+	if _, test := h.Storage.SandboxOrders.Get(order.Id); test != sandbox {
+		er := "live orders can't be paid in the sandbox"
+		if test {
+			er = "sandbox orders can only be paid in the sandbox"
+		}
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// Fake money needs no fraud screening.
+	userID := c.GetString(middleware.UserIDKey)
+	if !sandbox {
+		if fail := h.screenPayment(userID, order); fail != nil {
+			c.AbortWithStatusJSON(fail.status, fail.body)
+			h.Logger.Error(fail.err)
+			return
+		}
+	}
+
+	p := models.Payment{
+		Id:        uuid.NewString(),
+		OrderId:   order.Id,
+		Provider:  provider.Name(),
+		Amount:    order.TotalAmount,
+		Currency:  h.Payments.Currency(),
+		Status:    models.PaymentPending,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	if err := provider.Charge(ctx, &p, &data); err != nil {
+		// The payment service may quote the card it was sent.
+		er := redact.PAN(errors.Wrap(err, "error creating payment").Error())
+		c.AbortWithStatusJSON(paymentErrorStatus(err),
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Storage.Payments.Set(p.Id, p)
+	h.Storage.OrderPayments.Set(p.OrderId, p.Id)
+	if userID != "" && !sandbox && p.Status != models.PaymentFailed {
+		h.Fraud.Paid(userID)
+	}
+	h.Events.Emit(models.EventPaymentCreated, p.OrderId, p)
+
+	h.Logger.Info("CreatePayment method has finished successfully")
+	if p.Status == models.PaymentRequiresAction {
+		h.render(c, http.StatusAccepted, p)
+		return
+	}
+	h.render(c, http.StatusOK, p)
 }
 
 // GetPayment godoc
 // @Summary Gets a payment
-// @Description Retrieves payment info from database
+// @Description Retrieves payment info; payments of external providers are answered by the gateway. Card numbers are masked and the CVV is never returned
 // @Tags payment
 // @Security ApiKeyAuth
 // @Param id path string true "Payment ID"
@@ -89,29 +151,315 @@ func (h *Handler) CreatePayment(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /payments/{id} [get]
 func (h *Handler) GetPayment(c *gin.Context) {
-	h.Logger.Info("GetPayment method is starting")
+	if p, ok := h.Storage.Payments.Get(c.Param("id")); ok && p.Provider != models.ProviderInternal {
+		h.render(c, http.StatusOK, p)
+		return
+	}
+
+	serve(h, c, Proxy[*pb.ID, *pb.PaymentDetails]{
+		Name: "GetPayment",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "payment id")
+			return &pb.ID{Id: id}, err
+		},
+		Call:  h.PaymentClient.GetPayment,
+		Error: "error getting payment",
+		After: func(res *pb.PaymentDetails) {
+			res.CardNumber = redact.Card(res.CardNumber)
+			res.Cvv = ""
+		},
+	})
+}
+
+// TokenizeCard godoc
+// @Summary Tokenizes a card
+// @Description Exchanges card details for a token to pay with in CreatePayment's payment_token, so the card number is sent only once. Internal tokens are single-use and expire after 15 minutes
+// @Tags payment
+// @Security ApiKeyAuth
+// @Param card body models.NewCardToken true "Card details"
+// @Success 200 {object} models.CardToken
+// @Failure 400 {object} string "Invalid card details or provider"
+// @Failure 402 {object} string "Card declined"
+// @Failure 502 {object} string "Payment provider error"
+// @Router /payments/tokenize [post]
+func (h *Handler) TokenizeCard(c *gin.Context) {
+	h.Logger.Info("TokenizeCard method is starting")
+
+	var data models.NewCardToken
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := redact.PAN(errors.Wrap(err, "invalid card details").Error())
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
 
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
+	provider, err := h.Payments.Get(data.Provider)
 	if err != nil {
-		er := errors.Wrap(err, "invalid payment id").Error()
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	tokenizer, ok := provider.(payments.Tokenizer)
+	if !ok {
+		er := "payment provider does not support card tokenization"
 		c.AbortWithStatusJSON(http.StatusBadRequest,
 			gin.H{"error": er})
 		h.Logger.Error(er)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
+	ctx, cancel := callContext(c, defaultTimeout)
 	defer cancel()
 
-	res, err := h.PaymentClient.GetPayment(ctx, &pb.ID{Id: id})
+	token, err := tokenizer.Tokenize(ctx, data)
+	if err != nil {
+		er := redact.PAN(errors.Wrap(err, "error tokenizing card").Error())
+		c.AbortWithStatusJSON(paymentErrorStatus(err),
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("TokenizeCard method has finished successfully")
+	h.render(c, http.StatusOK, token)
+}
+
+// ConfirmPayment godoc
+// @Summary Confirms a payment
+// @Description Completes a payment after the customer has answered its 3-D Secure challenge. A payment whose challenge is still open is answered with 409
+// @Tags payment
+// @Security ApiKeyAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} models.Payment
+// @Failure 400 {object} string "Invalid payment ID"
+// @Failure 402 {object} string "Payment declined"
+// @Failure 404 {object} string "Payment not found"
+// @Failure 409 {object} string "Payment needs no confirmation or authentication is not complete"
+// @Failure 502 {object} string "Payment provider error"
+// @Router /payments/{id}/confirm [post]
+func (h *Handler) ConfirmPayment(c *gin.Context) {
+	h.Logger.Info("ConfirmPayment method is starting")
+
+	id, err := pathUUID(c, "id", "payment id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	p, ok := h.Storage.Payments.Get(id)
+	if !ok {
+		er := "payment not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if p.Status != models.PaymentRequiresAction {
+		er := errors.Errorf("payment is %s and needs no confirmation", p.Status).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	provider, err := h.Payments.Get(p.Provider)
 	if err != nil {
-		er := errors.Wrap(err, "error getting payment").Error()
+		er := err.Error()
 		c.AbortWithStatusJSON(http.StatusInternalServerError,
 			gin.H{"error": er})
 		h.Logger.Error(er)
 		return
 	}
 
-	c.JSON(http.StatusOK, res)
+	challenger, ok := provider.(payments.Challenger)
+	if !ok {
+		er := "payment provider does not support authentication challenges"
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	if err := challenger.Confirm(ctx, &p); err != nil {
+		er := errors.Wrap(err, "error confirming payment").Error()
+		c.AbortWithStatusJSON(paymentErrorStatus(err),
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.applyNotification(payments.Notification{PaymentId: p.Id, Status: p.Status, At: time.Now()})
+
+	if p.Status == models.PaymentRequiresAction {
+		er := "authentication is not complete"
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er, "challenge": p.Challenge})
+		h.Logger.Error(er)
+		return
+	}
+	if p.Status == models.PaymentFailed {
+		er := "payment authentication failed"
+		c.AbortWithStatusJSON(http.StatusPaymentRequired,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	p, _ = h.Storage.Payments.Get(id)
+	h.Logger.Info("ConfirmPayment method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
+
+// FetchPaymentProviders godoc
+// @Summary Gets payment providers
+// @Description Lists the payment providers that are configured and the default one
+// @Tags payment
+// @Security ApiKeyAuth
+// @Success 200 {object} models.PaymentProviders
+// @Router /payments/providers [get]
+func (h *Handler) FetchPaymentProviders(c *gin.Context) {
+	h.render(c, http.StatusOK, models.PaymentProviders{
+		Default:   h.Payments.Default(),
+		Providers: h.Payments.Names(),
+	})
+}
+
+// PaymentWebhook godoc
+// @Summary Receives payment provider notifications
+// @Description Endpoint for stripe, payme and click to report payment changes. Calls are authenticated with the provider's signature or credentials, not a token
+// @Tags payment
+// @Param provider path string true "Provider" Enums(stripe, payme, click)
+// @Success 200 {object} object "Provider-specific reply"
+// @Failure 400 {object} string "Invalid notification"
+// @Failure 401 {object} string "Invalid signature"
+// @Failure 404 {object} string "Unknown provider"
+// @Router /payments/webhooks/{provider} [post]
+func (h *Handler) PaymentWebhook(c *gin.Context) {
+	h.Logger.Info("PaymentWebhook method is starting")
+
+	provider, err := h.Payments.Get(c.Param("provider"))
+	if err != nil || provider.Name() != c.Param("provider") {
+		er := "unknown payment provider"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	n, reply, err := provider.Webhook(c.Request, h.Storage.Payments)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch errors.Cause(err) {
+		case payments.ErrSignature:
+			status = http.StatusUnauthorized
+		case payments.ErrNoWebhooks:
+			status = http.StatusNotFound
+		}
+		er := errors.Wrap(err, "invalid payment notification").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if n.PaymentId != "" {
+		h.applyNotification(n)
+	}
+
+	h.Logger.Info("PaymentWebhook method has finished successfully")
+	// Providers expect their own reply format, so it is never enveloped.
+	c.JSON(http.StatusOK, reply)
+}
+
+// applyNotification records a payment change reported by its provider
+// and publishes it when the status changed.
+func (h *Handler) applyNotification(n payments.Notification) {
+	var changed bool
+	p := h.Storage.Payments.Update(n.PaymentId, func(p models.Payment, _ bool) models.Payment {
+		if n.TransactionId != "" {
+			p.TransactionId = n.TransactionId
+		}
+		if n.Status == "" || n.Status == p.Status {
+			return p
+		}
+
+		changed = true
+		p.Status = n.Status
+		p.Challenge = nil
+		at := n.At.Format(time.RFC3339)
+		switch n.Status {
+		case models.PaymentSucceeded:
+			p.PaidAt = at
+		case models.PaymentRefunded:
+			p.RefundedAmount = p.Amount
+			p.CanceledAt = at
+		case models.PaymentCanceled, models.PaymentFailed:
+			p.CanceledAt = at
+		}
+		return p
+	})
+
+	if changed {
+		h.Events.Emit(models.EventPaymentUpdated, p.OrderId, p)
+	}
+}
+
+// paymentProvider returns the provider called name for live requests,
+// and the sandbox for sandbox ones, which live requests can't ask for.
+func (h *Handler) paymentProvider(name string, sandbox bool) (payments.Provider, error) {
+	if sandbox {
+		return h.Payments.Sandbox(), nil
+	}
+	if name == models.ProviderSandbox {
+		return nil, errors.Wrapf(payments.ErrUnknownProvider, "%q is only for sandbox requests", name)
+	}
+	return h.Payments.Get(name)
+}
+
+// payment looks a payment up in the gateway, falling back to the payment
+// service for payments the gateway has not seen or that it made there.
+func (h *Handler) payment(ctx context.Context, id string) (models.Payment, error) {
+	if p, ok := h.Storage.Payments.Get(id); ok && p.Provider != models.ProviderInternal {
+		return p, nil
+	}
+
+	res, err := h.PaymentClient.GetPayment(ctx, &pb.ID{Id: id})
+	if err != nil {
+		return models.Payment{}, err
+	}
+
+	p, _ := h.Storage.Payments.Get(id)
+	p.Id = res.Id
+	p.OrderId = res.OrderId
+	p.Provider = models.ProviderInternal
+	p.Amount = res.Amount
+	p.Status = res.Status
+	return p, nil
+}
+
+// paymentErrorStatus maps a provider error to an HTTP status: declines
+// are the customer's to fix, anything else is the provider's fault.
+func paymentErrorStatus(err error) int {
+	switch errors.Cause(err) {
+	case payments.ErrDeclined:
+		return http.StatusPaymentRequired
+	case payments.ErrPartialRefund, payments.ErrInvalidCard, payments.ErrInvalidToken:
+		return http.StatusBadRequest
+	}
+	if _, ok := status.FromError(errors.Cause(err)); ok {
+		code, _ := errorStatus(err)
+		return code
+	}
+	return http.StatusBadGateway
 }
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// placementTTL is how long the outcome of an order placed in the
+// background is kept for its client to come back for.
+const placementTTL = 24 * time.Hour
+
+// placeAsync places the order in the background and returns the
+// placement tracking it.
+func (h *Handler) placeAsync(userID, requestID string, data models.NewOrder, screen func(total float32) *orderFailure) models.OrderPlacement {
+	now := time.Now()
+	h.prunePlacements(now)
+
+	p := models.OrderPlacement{
+		Token:     uuid.NewString(),
+		UserId:    userID,
+		Status:    models.PlacementPending,
+		CreatedAt: now.Format(time.RFC3339),
+		UpdatedAt: now.Format(time.RFC3339),
+	}
+	h.Storage.OrderPlacements.Set(p.Token, p)
+
+	go func() {
+		// The request is over by now; placing takes what it takes
+		// synchronously.
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		res, fail := h.placeOrder(ctx, data, screen)
+		p.UpdatedAt = time.Now().Format(time.RFC3339)
+		switch {
+		case fail == nil:
+			p.Status, p.OrderId = models.PlacementPlaced, res.Id
+			p.ResponseStatus = http.StatusOK
+			h.customerPlaced(userID, requestID, data)
+			h.Logger.Info("order of placement " + p.Token + " created successfully")
+		case fail.status == http.StatusAccepted:
+			p.Status, p.ResponseStatus, p.Response = models.PlacementHeld, fail.status, fail.body
+			h.Logger.Info("order of placement " + p.Token + " is held: " + fail.err)
+		default:
+			p.Status, p.ResponseStatus, p.Response = models.PlacementFailed, fail.status, fail.body
+			h.Logger.Error("order of placement " + p.Token + " failed: " + fail.err)
+		}
+		h.Storage.OrderPlacements.Set(p.Token, p)
+	}()
+
+	return p
+}
+
+// prunePlacements forgets placements older than placementTTL.
+func (h *Handler) prunePlacements(now time.Time) {
+	for _, p := range h.Storage.OrderPlacements.List() {
+		if created, _ := time.Parse(time.RFC3339, p.CreatedAt); now.Sub(created) > placementTTL {
+			h.Storage.OrderPlacements.Delete(p.Token)
+		}
+	}
+}
+
+// GetOrderPlacement godoc
+// @Summary Gets an order placed in the background
+// @Description Tells how placing an order sent with async=true went: pending, placed with its order_id, held for fraud review or failed. Held and failed placements carry the status and body the order would have been answered with. Placements are kept for 24 hours
+// @Tags order
+// @Security ApiKeyAuth
+// @Param token path string true "Placement token"
+// @Success 200 {object} models.OrderPlacement
+// @Failure 400 {object} string "Invalid token"
+// @Failure 404 {object} string "Placement not found"
+// @Router /orders/placements/{token} [get]
+func (h *Handler) GetOrderPlacement(c *gin.Context) {
+	h.Logger.Info("GetOrderPlacement method is starting")
+
+	token, err := pathUUID(c, "token", "placement token")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// Others' placements are not found, rather than forbidden, so tokens
+	// can't be probed.
+	p, ok := h.Storage.OrderPlacements.Get(token)
+	if !ok || p.UserId != c.GetString(middleware.UserIDKey) {
+		er := "order placement not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetOrderPlacement method has finished successfully")
+	h.render(c, http.StatusOK, p)
+}
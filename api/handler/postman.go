@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"api-gateway/pkg/openapi"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// ExportPostmanCollection godoc
+// @Summary Exports a Postman collection
+// @Description Builds a Postman collection, which Insomnia imports too, from the routes the gateway serves: a folder per tag, a request per route with its parameters and an example body. Requests go to the baseUrl variable, the gateway's address by default, and are signed with the token variable
+// @Tags admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} object
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/postman-collection [get]
+func (h *Handler) ExportPostmanCollection(c *gin.Context) {
+	h.Logger.Info("ExportPostmanCollection method is starting")
+
+	res, err := openapi.Postman(h.Spec, baseURL(c))
+	if err != nil {
+		er := errors.Wrap(err, "error building collection").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("ExportPostmanCollection method has finished successfully")
+	c.Header("Content-Disposition", `attachment; filename="local-eats.postman_collection.json"`)
+	c.Data(http.StatusOK, "application/json", res)
+}
+
+// baseURL is the address the client reached the gateway at.
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// trimFields leaves the fields of the profile out of data, a JSON
+// response, at any depth. Responses with none of them are returned as
+// they are.
+func trimFields(data []byte, p models.ResponseProfile) ([]byte, error) {
+	if len(p.Trim) == 0 {
+		return data, nil
+	}
+
+	trim := make(map[string]bool, len(p.Trim))
+	for _, f := range p.Trim {
+		trim[fieldKey(f)] = true
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are kept as they were, 64-bit IDs included.
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if !trimValue(v, trim) {
+		return data, nil
+	}
+	return json.Marshal(v)
+}
+
+// trimValue drops the fields in trim from v, reporting whether there were
+// any.
+func trimValue(v any, trim map[string]bool) bool {
+	trimmed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, fv := range v {
+			if trim[fieldKey(k)] {
+				delete(v, k)
+				trimmed = true
+				continue
+			}
+			trimmed = trimValue(fv, trim) || trimmed
+		}
+	case []any:
+		for _, item := range v {
+			trimmed = trimValue(item, trim) || trimmed
+		}
+	}
+	return trimmed
+}
+
+// fieldKey is a field's name with its naming ignored, so nutrition_info
+// and nutritionInfo match.
+func fieldKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// Proxy declares a REST endpoint backed by a single unary gRPC call. Bind
+// builds the gRPC request from the HTTP request and Call is the client
+// method; serve does the rest, so every proxied endpoint logs, times out
+// and reports errors the same way.
+type Proxy[Req, Res any] struct {
+	Name    string
+	Bind    func(c *gin.Context) (Req, error)
+	Call    func(ctx context.Context, in Req, opts ...grpc.CallOption) (Res, error)
+	Error   string
+	Timeout time.Duration
+
+	// Message, if set, is sent instead of the gRPC response.
+	Message string
+	// After runs once the call has succeeded.
+	After func(res Res)
+	// Render, if set, sends the response instead of rendering it as is.
+	Render func(c *gin.Context, res Res)
+}
+
+func serve[Req, Res any](h *Handler, c *gin.Context, p Proxy[Req, Res]) {
+	h.Logger.Info(p.Name + " method is starting")
+
+	req, err := p.Bind(c)
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx, cancel := callContext(c, timeout)
+	defer cancel()
+
+	res, err := p.Call(ctx, req)
+	if err != nil {
+		// Errors of the services keep their status, such as 404 for a
+		// missing record; anything else is the gateway's fault.
+		code := http.StatusInternalServerError
+		if _, ok := status.FromError(errors.Cause(err)); ok {
+			code, _ = errorStatus(err)
+		}
+		er := errors.Wrap(err, p.Error).Error()
+		c.AbortWithStatusJSON(code,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if p.After != nil {
+		p.After(res)
+	}
+
+	h.Logger.Info(p.Name + " method has finished successfully")
+	switch {
+	case p.Message != "":
+		h.render(c, http.StatusOK, p.Message)
+	case p.Render != nil:
+		p.Render(c, res)
+	default:
+		h.render(c, http.StatusOK, res)
+	}
+}
+
+// callContext is the context to call the services with while serving c.
+// It times out after timeout, or by the deadline the client asked for
+// with X-Timeout-Ms.
+func callContext(c *gin.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if deadline, ok := c.Value(middleware.DeadlineKey).(time.Time); ok {
+		return context.WithDeadline(c, deadline)
+	}
+	return context.WithTimeout(c, timeout)
+}
+
+// pathUUID returns the path parameter after checking that it is a UUID.
+func pathUUID(c *gin.Context, name, what string) (string, error) {
+	id := c.Param(name)
+	if _, err := uuid.Parse(id); err != nil {
+		return "", errors.Wrap(err, "invalid "+what)
+	}
+	return id, nil
+}
+
+// bindJSON decodes the request body, describing failures with what.
+func bindJSON[T any](c *gin.Context, what string) (*T, error) {
+	var data T
+	if err := c.ShouldBindJSON(&data); err != nil {
+		return nil, errors.Wrap(err, "invalid "+what)
+	}
+	return &data, nil
+}
+
+// pagination converts the page and limit query parameters to a limit
+// and offset.
+func pagination(c *gin.Context) (int32, int32, error) {
+	p, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid pagination parameters")
+	}
+
+	l, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid pagination parameters")
+	}
+
+	return int32(l), int32((p - 1) * l), nil
+}
+
+// dateQuery reads a YYYY-MM-DD query parameter.
+func dateQuery(c *gin.Context, name, what string) (string, error) {
+	date := c.Query(name)
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return "", errors.Wrap(err, "invalid "+what)
+	}
+	return date, nil
+}
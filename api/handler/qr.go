@@ -0,0 +1,86 @@
+package handler
+
+import (
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/pkg/qr"
+	"cmp"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const defaultQRSize = 512
+
+// GetKitchenQR godoc
+// @Summary Gets a kitchen's menu QR code
+// @Description Draws a QR code that opens the kitchen's public menu page, for printing on table cards
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Produce image/png,image/svg+xml
+// @Param id path string true "Kitchen ID"
+// @Param format query string false "Image format" Enums(png, svg)
+// @Param size query int false "Width and height in pixels, 128 to 2048"
+// @Success 200 {file} file
+// @Failure 400 {object} string "Invalid kitchen ID, format or size"
+// @Failure 404 {object} string "Kitchen not found"
+// @Router /kitchens/{id}/qr [get]
+func (h *Handler) GetKitchenQR(c *gin.Context) {
+	h.Logger.Info("GetKitchenQR method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	var size int
+	if err == nil {
+		size, err = queryInt(c, "size")
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	if _, err := h.KitchenClient.GetName(ctx, &pbk.ID{Id: kitchenID}); err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting kitchen").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	format := cmp.Or(c.Query("format"), qr.FormatPNG)
+	img, err := qr.Encode(h.menuURL(kitchenID), format, cmp.Or(size, defaultQRSize))
+	if err != nil {
+		er := errors.Wrap(err, "invalid QR code parameters").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	contentType := "image/png"
+	if format == qr.FormatSVG {
+		contentType = "image/svg+xml"
+	}
+	// A kitchen's code never changes.
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="menu-%s.%s"`, kitchenID, format))
+
+	h.Logger.Info("GetKitchenQR method has finished successfully")
+	c.Data(http.StatusOK, contentType, img)
+}
+
+// menuURL is the public menu page of the kitchen, tagged so visits from
+// printed codes can be told apart.
+func (h *Handler) menuURL(kitchenID string) string {
+	return strings.TrimSuffix(h.MenuURL, "/") + "/" + kitchenID + "?" +
+		url.Values{"utm_source": {"qr"}}.Encode()
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/recording"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// maxRecordingNote is the longest note of a recording.
+const maxRecordingNote = 200
+
+// StartRecording godoc
+// @Summary Starts recording requests
+// @Description Records the requests the gateway serves for the next minutes, up to RECORDING_MAX_MINUTES, to replay against a staging gateway with the replay command before a release. One recording runs at a time.
+// @Description Requests are anonymized as they are recorded: credentials and other headers that don't change the answer are dropped, emails, phones, names, addresses, notes, passwords and tokens are replaced with pseudonyms that are the same throughout the recording, card numbers with a test card and coordinates are rounded to about a kilometre. Bodies that are not JSON or larger than RECORDING_MAX_BODY are left out, as are requests past RECORDING_MAX_REQUESTS
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param recording body models.NewRecording true "Recording"
+// @Success 200 {object} models.Recording
+// @Failure 400 {object} string "Invalid recording data"
+// @Failure 409 {object} string "A recording is already running"
+// @Router /admin/recordings [post]
+func (h *Handler) StartRecording(c *gin.Context) {
+	h.Logger.Info("StartRecording method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewRecording
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = h.validateRecording(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid recording data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	rec, err := h.Recorder.Start(userID, data.Note, time.Duration(data.Minutes)*time.Minute, time.Now())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, recording.ErrRecording) {
+			status = http.StatusConflict
+		}
+		er := err.Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("StartRecording method has finished successfully")
+	h.render(c, http.StatusOK, rec)
+}
+
+// FetchRecordings godoc
+// @Summary Gets request recordings
+// @Description Lists the recordings, latest first, with how many requests each recorded
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Recordings
+// @Router /admin/recordings [get]
+func (h *Handler) FetchRecordings(c *gin.Context) {
+	h.Logger.Info("FetchRecordings method is starting")
+
+	res := models.Recordings{Recordings: h.Storage.Recordings.List()}
+	slices.SortFunc(res.Recordings, func(a, b models.Recording) int {
+		return strings.Compare(b.StartedAt, a.StartedAt)
+	})
+
+	h.Logger.Info("FetchRecordings method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetRecording godoc
+// @Summary Gets a request recording
+// @Description Gives the recording with its anonymized requests in the order they were served, as the replay command reads it
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Recording ID"
+// @Success 200 {object} models.RecordingTrace
+// @Failure 400 {object} string "Invalid recording ID"
+// @Failure 404 {object} string "Recording not found"
+// @Router /admin/recordings/{id} [get]
+func (h *Handler) GetRecording(c *gin.Context) {
+	h.Logger.Info("GetRecording method is starting")
+
+	rec, ok := h.recording(c)
+	if !ok {
+		return
+	}
+
+	res := models.RecordingTrace{Recording: rec, Requests: h.Recorder.Requests(rec.Id)}
+
+	h.Logger.Info("GetRecording method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// StopRecording godoc
+// @Summary Stops recording requests
+// @Description Stops the recording before its time is up; its requests are kept
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Recording ID"
+// @Success 200 {object} models.Recording
+// @Failure 400 {object} string "Invalid recording ID"
+// @Failure 404 {object} string "Recording not found"
+// @Router /admin/recordings/{id}/stop [post]
+func (h *Handler) StopRecording(c *gin.Context) {
+	h.Logger.Info("StopRecording method is starting")
+
+	rec, ok := h.recording(c)
+	if !ok {
+		return
+	}
+	rec, _ = h.Recorder.Stop(rec.Id, time.Now())
+
+	h.Logger.Info("StopRecording method has finished successfully")
+	h.render(c, http.StatusOK, rec)
+}
+
+// DeleteRecording godoc
+// @Summary Deletes a request recording
+// @Description Deletes the recording and its requests, stopping it if it runs
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Recording ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid recording ID"
+// @Failure 404 {object} string "Recording not found"
+// @Router /admin/recordings/{id} [delete]
+func (h *Handler) DeleteRecording(c *gin.Context) {
+	h.Logger.Info("DeleteRecording method is starting")
+
+	rec, ok := h.recording(c)
+	if !ok {
+		return
+	}
+	h.Recorder.Delete(rec.Id)
+
+	h.Logger.Info("DeleteRecording method has finished successfully")
+	h.render(c, http.StatusOK, "Recording deleted successfully")
+}
+
+// recording finds the recording of the id path parameter.
+func (h *Handler) recording(c *gin.Context) (models.Recording, bool) {
+	id, err := pathUUID(c, "id", "recording id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Recording{}, false
+	}
+
+	rec, ok := h.Storage.Recordings.Get(id)
+	if !ok {
+		er := "recording not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Recording{}, false
+	}
+	return rec, true
+}
+
+func (h *Handler) validateRecording(data *models.NewRecording) error {
+	if limit := int(h.RecordingMax / time.Minute); data.Minutes < 1 || data.Minutes > limit {
+		return errors.Errorf("minutes must be between 1 and %d", limit)
+	}
+	data.Note = strings.TrimSpace(data.Note)
+	if len(data.Note) > maxRecordingNote {
+		return errors.Errorf("note must have at most %d characters", maxRecordingNote)
+	}
+	return nil
+}
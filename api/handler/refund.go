@@ -0,0 +1,464 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pbo "api-gateway/genproto/order"
+	"api-gateway/models"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// RequestRefund godoc
+// @Summary Requests a refund
+// @Description Asks the kitchen to refund an order. Evidence holds IDs of completed attachment uploads, e.g. photos of the food
+// @Tags refund
+// @Security ApiKeyAuth
+// @Param id path string true "Order ID"
+// @Param request body models.NewRefundRequest true "Refund request"
+// @Success 200 {object} models.RefundRequest
+// @Failure 400 {object} string "Invalid order ID or refund request"
+// @Failure 403 {object} string "Order belongs to another user"
+// @Failure 404 {object} string "Order not found"
+// @Failure 409 {object} string "Order already has a refund request"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /orders/{id}/refund-request [post]
+func (h *Handler) RequestRefund(c *gin.Context) {
+	h.Logger.Info("RequestRefund method is starting")
+
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	orderID, err := pathUUID(c, "id", "order id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var data models.NewRefundRequest
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid refund request").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if err := h.validateRefundRequest(c, &data); err != nil {
+		er := errors.Wrap(err, "invalid refund request").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	order, err := h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: orderID})
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting order").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if order.UserId != userID && role != models.RoleAdmin {
+		er := "order belongs to another user"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	paymentID := data.PaymentId
+	if paymentID == "" {
+		paymentID, _ = h.Storage.OrderPayments.Get(orderID)
+	}
+	if paymentID == "" {
+		er := "payment of the order is unknown, payment_id is required"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	payment, err := h.payment(ctx, paymentID)
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting payment").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if payment.Provider != models.ProviderInternal && payment.Status != models.PaymentSucceeded {
+		er := errors.Errorf("payment is %s and cannot be refunded", payment.Status).Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	refundable := payment.Amount - payment.RefundedAmount
+	if data.Amount == 0 {
+		data.Amount = refundable
+	}
+	if payment.OrderId != orderID || data.Amount > refundable {
+		er := "payment does not belong to the order or is smaller than the amount"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	r := models.RefundRequest{
+		Id:        uuid.NewString(),
+		OrderId:   orderID,
+		PaymentId: payment.Id,
+		KitchenId: order.KitchenId,
+		UserId:    order.UserId,
+		Reason:    data.Reason,
+		Comment:   data.Comment,
+		Amount:    data.Amount,
+		Evidence:  nonNil(data.Evidence),
+		Status:    models.RefundPending,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+
+	// The order is claimed and the request filed in one step, so requests
+	// sent at once can't both be filed.
+	var open models.RefundRequest
+	h.Storage.OrderRefunds.Update(orderID, func(id string, _ bool) string {
+		if prev, ok := h.Storage.RefundRequests.Get(id); ok && prev.Status != models.RefundRejected {
+			open = prev
+			return id
+		}
+		h.Storage.RefundRequests.Set(r.Id, r)
+		return r.Id
+	})
+	if open.Id != "" {
+		er := errors.Errorf("order already has a %s refund request", open.Status).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	h.Webhooks.Dispatch(r.KitchenId, models.EventRefundRequested, r)
+
+	h.Logger.Info("RequestRefund method has finished successfully")
+	h.render(c, http.StatusOK, r)
+}
+
+// FetchOrderRefundRequests godoc
+// @Summary Gets refund requests of an order
+// @Description Lists refund requests of an order for its customer, its kitchen or admins
+// @Tags refund
+// @Security ApiKeyAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} models.RefundRequests
+// @Failure 400 {object} string "Invalid order ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Order not found"
+// @Router /orders/{id}/refund-requests [get]
+func (h *Handler) FetchOrderRefundRequests(c *gin.Context) {
+	h.Logger.Info("FetchOrderRefundRequests method is starting")
+
+	orderID, err := pathUUID(c, "id", "order id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	order, err := h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: orderID})
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting order").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, order.UserId, order.KitchenId, models.StaffOrders); !ok {
+		return
+	}
+
+	res := h.refundRequests(func(r models.RefundRequest) bool {
+		return r.OrderId == orderID
+	})
+
+	h.Logger.Info("FetchOrderRefundRequests method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// FetchKitchenRefundRequests godoc
+// @Summary Gets refund requests of a kitchen
+// @Description Lists refund requests for the kitchen's orders, newest first
+// @Tags refund
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param status query string false "Request status" Enums(pending, approved, rejected)
+// @Success 200 {object} models.RefundRequests
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Access denied"
+// @Router /kitchens/{id}/refund-requests [get]
+func (h *Handler) FetchKitchenRefundRequests(c *gin.Context) {
+	h.Logger.Info("FetchKitchenRefundRequests method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// No customer owns a whole kitchen's requests, so only the kitchen
+	// owner, its order staff and admins pass.
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffOrders); !ok {
+		return
+	}
+
+	status := c.Query("status")
+	res := h.refundRequests(func(r models.RefundRequest) bool {
+		return r.KitchenId == kitchenID && (status == "" || r.Status == status)
+	})
+
+	h.Logger.Info("FetchKitchenRefundRequests method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// ApproveRefund godoc
+// @Summary Approves a refund request
+// @Description Approves a pending request and asks the payment service to refund it through the refund.approved event
+// @Tags refund
+// @Security ApiKeyAuth
+// @Param id path string true "Refund request ID"
+// @Param decision body models.RefundDecision false "Decision comment"
+// @Success 200 {object} models.RefundRequest
+// @Failure 400 {object} string "Invalid refund request ID"
+// @Failure 403 {object} string "Only the kitchen or an admin can decide"
+// @Failure 404 {object} string "Refund request not found"
+// @Failure 409 {object} string "Refund request is already decided, or is more than is left of the payment"
+// @Router /refund-requests/{id}/approve [post]
+func (h *Handler) ApproveRefund(c *gin.Context) {
+	h.decideRefund(c, "ApproveRefund", models.RefundApproved)
+}
+
+// RejectRefund godoc
+// @Summary Rejects a refund request
+// @Description Rejects a pending request; the customer may then file a new one
+// @Tags refund
+// @Security ApiKeyAuth
+// @Param id path string true "Refund request ID"
+// @Param decision body models.RefundDecision false "Decision comment"
+// @Success 200 {object} models.RefundRequest
+// @Failure 400 {object} string "Invalid refund request ID"
+// @Failure 403 {object} string "Only the kitchen or an admin can decide"
+// @Failure 404 {object} string "Refund request not found"
+// @Failure 409 {object} string "Refund request is already decided"
+// @Router /refund-requests/{id}/reject [post]
+func (h *Handler) RejectRefund(c *gin.Context) {
+	h.decideRefund(c, "RejectRefund", models.RefundRejected)
+}
+
+func (h *Handler) decideRefund(c *gin.Context, name, status string) {
+	h.Logger.Info(name + " method is starting")
+
+	id, err := pathUUID(c, "id", "refund request id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// The decision comment is optional, so an empty body is fine.
+	var data models.RefundDecision
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&data); err != nil {
+			er := errors.Wrap(err, "invalid decision").Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	r, ok := h.Storage.RefundRequests.Get(id)
+	if !ok {
+		er := "refund request not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// Roles granted refunds:decide may decide on any kitchen's refunds.
+	role := c.GetString(middleware.RoleKey)
+	if !h.RBAC.Allows(role, models.PermRefunds) {
+		if role, ok = h.accessRole(c, r.UserId, r.KitchenId, models.StaffOrders); !ok {
+			return
+		}
+	}
+	if role == models.RoleCustomer {
+		er := "only the kitchen or an admin can decide on a refund"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var decided bool
+	r = h.Storage.RefundRequests.Update(id, func(r models.RefundRequest, _ bool) models.RefundRequest {
+		if r.Status != models.RefundPending {
+			return r
+		}
+		decided = true
+		r.Status = status
+		r.DecisionComment = data.Comment
+		r.DecidedBy = c.GetString(middleware.UserIDKey)
+		r.DecidedAt = time.Now().Format(time.RFC3339)
+		return r
+	})
+	if !decided {
+		er := errors.Errorf("refund request is already %s", r.Status).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if status == models.RefundApproved {
+		if err := h.refund(c, r); err != nil {
+			// Reopen the request so that the approval can be retried.
+			h.Storage.RefundRequests.Update(id, func(r models.RefundRequest, _ bool) models.RefundRequest {
+				r.Status = models.RefundPending
+				r.DecisionComment, r.DecidedBy, r.DecidedAt = "", "", ""
+				return r
+			})
+			code := paymentErrorStatus(err)
+			if errors.Cause(err) == errRefundExceeds {
+				code = http.StatusConflict
+			}
+			er := errors.Wrap(err, "error refunding payment").Error()
+			c.AbortWithStatusJSON(code,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+		h.Events.Emit(models.EventRefundApproved, r.PaymentId, r)
+	}
+
+	h.Logger.Info(name + " method has finished successfully")
+	h.render(c, http.StatusOK, r)
+}
+
+var errRefundExceeds = errors.New("refund is more than is left of the payment")
+
+// refund pays an approved refund back through the provider that took
+// the payment. Payments the gateway has no record of were made in the
+// payment service; a record is kept of them too, to count their refunds.
+func (h *Handler) refund(c *gin.Context, r models.RefundRequest) error {
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	p, err := h.payment(ctx, r.PaymentId)
+	if err != nil {
+		return err
+	}
+
+	provider, err := h.Payments.Get(p.Provider)
+	if err != nil {
+		return err
+	}
+
+	// The amount is taken off what is left of the payment before the
+	// provider is asked, so refunds approved at once can't together pay
+	// back more than was paid.
+	var reserved bool
+	h.Storage.Payments.Update(p.Id, func(s models.Payment, ok bool) models.Payment {
+		if !ok {
+			s = p
+		}
+		if r.Amount > s.Amount-s.RefundedAmount {
+			return s
+		}
+		reserved = true
+		s.RefundedAmount += r.Amount
+		return s
+	})
+	if !reserved {
+		return errRefundExceeds
+	}
+
+	if err := provider.Refund(ctx, p, r.Id, r.Amount); err != nil {
+		h.Storage.Payments.Update(p.Id, func(p models.Payment, _ bool) models.Payment {
+			p.RefundedAmount -= r.Amount
+			return p
+		})
+		return err
+	}
+
+	h.Storage.Payments.Update(p.Id, func(p models.Payment, _ bool) models.Payment {
+		if p.RefundedAmount >= p.Amount {
+			p.Status = models.PaymentRefunded
+			p.CanceledAt = time.Now().Format(time.RFC3339)
+		}
+		return p
+	})
+	return nil
+}
+
+func (h *Handler) refundRequests(match func(models.RefundRequest) bool) models.RefundRequests {
+	res := models.RefundRequests{Requests: []models.RefundRequest{}}
+	for _, r := range h.Storage.RefundRequests.List() {
+		if match(r) {
+			res.Requests = append(res.Requests, r)
+		}
+	}
+	slices.SortFunc(res.Requests, func(a, b models.RefundRequest) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+	return res
+}
+
+func (h *Handler) validateRefundRequest(c *gin.Context, data *models.NewRefundRequest) error {
+	if !slices.Contains(models.RefundReasons, data.Reason) {
+		return errors.Errorf("reason must be one of %s", strings.Join(models.RefundReasons, ", "))
+	}
+	if data.Reason == "other" && strings.TrimSpace(data.Comment) == "" {
+		return errors.New("comment is required for reason other")
+	}
+	if data.Amount < 0 {
+		return errors.New("amount cannot be negative")
+	}
+	if data.PaymentId != "" {
+		if _, err := uuid.Parse(data.PaymentId); err != nil {
+			return errors.Wrap(err, "invalid payment id")
+		}
+	}
+	return h.validateAttachments(c, data.Evidence)
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	"api-gateway/config"
+	"api-gateway/models"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxPooledBuffer keeps the odd huge response from pinning its buffer.
+const maxPooledBuffer = 1 << 20
+
+// buffers recycles the buffers responses are assembled in.
+var buffers = sync.Pool{New: func() any {
+	b := make([]byte, 0, 4096)
+	return &b
+}}
+
+func getBuffer() *[]byte {
+	return buffers.Get().(*[]byte)
+}
+
+func putBuffer(b *[]byte) {
+	if cap(*b) <= maxPooledBuffer {
+		*b = (*b)[:0]
+		buffers.Put(b)
+	}
+}
+
+// newMarshaler configures how protobuf responses are encoded. The
+// defaults keep the snake_case field names and omitted zero values that
+// clients got from encoding/json.
+func newMarshaler(cfg *config.Config) protojson.MarshalOptions {
+	return protojson.MarshalOptions{
+		UseProtoNames:   cfg.RESPONSE_PROTO_NAMES,
+		UseEnumNumbers:  cfg.RESPONSE_ENUM_NUMBERS,
+		EmitUnpopulated: cfg.RESPONSE_EMIT_UNPOPULATED,
+	}
+}
+
+// render writes a successful response, wrapped in the {"data", "meta"}
+// envelope unless the legacy bare format is configured.
+func (h *Handler) render(c *gin.Context, status int, v any) {
+	data, err := h.encode(c, v)
+	h.write(c, status, v, data, err)
+}
+
+// write sends data, the encoded v, as the response, shaped with the
+// caller's profile and migrated to their schema version. It is split
+// from render for handlers that change the encoded response.
+func (h *Handler) write(c *gin.Context, status int, v any, data []byte, err error) {
+	if err == nil {
+		data, err = h.shapeData(c, data)
+	}
+	if err == nil && status < http.StatusMultipleChoices {
+		setPageLinks(c, v)
+	}
+	if err == nil && h.Envelope {
+		buf := getBuffer()
+		defer putBuffer(buf)
+
+		*buf, err = appendEnvelope(*buf, data, envelopeMeta(c, v))
+		data = *buf
+	}
+	if err != nil {
+		er := errors.Wrap(err, "error encoding response").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	c.Data(status, "application/json; charset=utf-8", data)
+}
+
+// envelopeMeta is the meta of the envelope v is sent in.
+func envelopeMeta(c *gin.Context, v any) models.Meta {
+	return models.Meta{
+		RequestId:  c.GetString(middleware.RequestIDKey),
+		Pagination: paginationMeta(c, v),
+	}
+}
+
+// shapeData shapes data, an encoded response, with the caller's profile
+// and migrates it to their schema version.
+func (h *Handler) shapeData(c *gin.Context, data []byte) ([]byte, error) {
+	data, err := trimFields(data, middleware.ProfileOf(c))
+	if err != nil {
+		return nil, err
+	}
+	return middleware.MigrateResponse(c, data)
+}
+
+// appendEnvelope appends data wrapped like models.Envelope. Only the meta
+// is marshaled, so data is not copied and validated again.
+func appendEnvelope(dst, data []byte, meta models.Meta) ([]byte, error) {
+	m, err := json.Marshal(meta)
+	if err != nil {
+		return dst, err
+	}
+	dst = append(dst, `{"data":`...)
+	dst = append(dst, data...)
+	dst = append(dst, `,"meta":`...)
+	dst = append(dst, m...)
+	return append(dst, '}'), nil
+}
+
+// encode marshals protobuf messages with protojson, which unlike
+// encoding/json handles oneofs, enums and 64-bit integers correctly, or
+// shapes them for clients asking for the standard format. Anything else
+// is encoded as plain JSON.
+func (h *Handler) encode(c *gin.Context, v any) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return json.Marshal(v)
+	}
+
+	if h.apiFormat(c) == formatStandard {
+		return json.Marshal(h.shape(msg.ProtoReflect()))
+	}
+	return h.Marshaler.Marshal(msg)
+}
+
+// setPageLinks links a page of a list response to the first, previous,
+// next and last pages in Link headers (RFC 8288), by the same URL with
+// the page changed. The last page is known from the total; without one,
+// a full page is taken to have a next one.
+func setPageLinks(c *gin.Context, v any) {
+	meta := paginationMeta(c, v)
+	if meta == nil || meta.Page < 1 || meta.Limit < 1 {
+		return
+	}
+	_, n := listOf(v)
+
+	last := 0
+	if meta.Total > 0 {
+		last = int((meta.Total + int64(meta.Limit) - 1) / int64(meta.Limit))
+	}
+	link := func(page int, rel string) {
+		u := *c.Request.URL
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		u.RawQuery = q.Encode()
+		c.Writer.Header().Add("Link", "<"+u.RequestURI()+`>; rel="`+rel+`"`)
+	}
+
+	link(1, "first")
+	if meta.Page > 1 {
+		link(min(meta.Page-1, max(last, 1)), "prev")
+	}
+	switch {
+	case last > 0 && meta.Page < last:
+		link(meta.Page+1, "next")
+	case last == 0 && n >= meta.Limit:
+		link(meta.Page+1, "next")
+	}
+	if last > 0 {
+		link(last, "last")
+	}
+}
+
+// listOf tells the total of v, a list response, and how many items it
+// has, by its total field and its first list field.
+func listOf(v any) (total int64, n int) {
+	if msg, ok := v.(proto.Message); ok {
+		m := msg.ProtoReflect()
+		if fd := m.Descriptor().Fields().ByName("total"); fd != nil {
+			total = m.Get(fd).Int()
+		}
+		fields := m.Descriptor().Fields()
+		for i := range fields.Len() {
+			if fd := fields.Get(i); fd.IsList() {
+				return total, m.Get(fd).List().Len()
+			}
+		}
+		return total, 0
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return 0, 0
+	}
+	if f := rv.FieldByName("Total"); f.IsValid() && f.CanInt() {
+		total = f.Int()
+	}
+	for i := range rv.NumField() {
+		if f := rv.Field(i); f.Kind() == reflect.Slice && rv.Type().Field(i).IsExported() {
+			return total, f.Len()
+		}
+	}
+	return total, 0
+}
+
+// paginationMeta describes the page of a list response, taking the total
+// from the response's total field when it has one.
+func paginationMeta(c *gin.Context, v any) *models.PaginationMeta {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		return nil
+	}
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		return nil
+	}
+
+	total, _ := listOf(v)
+	return &models.PaginationMeta{Page: page, Limit: limit, Total: total}
+}
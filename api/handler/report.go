@@ -0,0 +1,375 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/extra"
+	"api-gateway/models"
+	"api-gateway/pkg/analytics"
+	"api-gateway/pkg/email"
+	"api-gateway/pkg/report"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ScheduleReport godoc
+// @Summary Schedules a report
+// @Description Schedules a weekly or monthly statistics report in CSV or PDF, delivered by email or through the kitchen's report.generated webhooks. For the kitchen owner, staff with the payouts permission and admins
+// @Tags report
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param schedule body models.NewReportSchedule true "Schedule info"
+// @Success 200 {object} models.ReportSchedule
+// @Failure 400 {object} string "Invalid kitchen ID or schedule data"
+// @Failure 403 {object} string "Access denied"
+// @Router /kitchens/{id}/reports [post]
+func (h *Handler) ScheduleReport(c *gin.Context) {
+	h.Logger.Info("ScheduleReport method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffPayouts); !ok {
+		return
+	}
+
+	var data models.NewReportSchedule
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid schedule data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if err := h.validateReportSchedule(&data); err != nil {
+		er := errors.Wrap(err, "invalid schedule data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now()
+	s := models.ReportSchedule{
+		Id:        uuid.NewString(),
+		KitchenId: kitchenID,
+		Frequency: data.Frequency,
+		Format:    data.Format,
+		Delivery:  data.Delivery,
+		Email:     data.Email,
+		NextRunAt: report.NextRun(data.Frequency, now).Format(time.RFC3339),
+		CreatedAt: now.Format(time.RFC3339),
+	}
+	h.Storage.ReportSchedules.Set(s.Id, s)
+
+	h.Logger.Info("ScheduleReport method has finished successfully")
+	h.render(c, http.StatusOK, s)
+}
+
+// FetchReportSchedules godoc
+// @Summary Gets report schedules
+// @Description Lists the report schedules of a kitchen. For the kitchen owner, staff with the payouts permission and admins
+// @Tags report
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.ReportSchedules
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Access denied"
+// @Router /kitchens/{id}/reports/schedules [get]
+func (h *Handler) FetchReportSchedules(c *gin.Context) {
+	h.Logger.Info("FetchReportSchedules method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffPayouts); !ok {
+		return
+	}
+
+	res := models.ReportSchedules{Schedules: []models.ReportSchedule{}}
+	for _, s := range h.Storage.ReportSchedules.List() {
+		if s.KitchenId == kitchenID {
+			res.Schedules = append(res.Schedules, s)
+		}
+	}
+	slices.SortFunc(res.Schedules, func(a, b models.ReportSchedule) int {
+		return strings.Compare(a.CreatedAt, b.CreatedAt)
+	})
+
+	h.Logger.Info("FetchReportSchedules method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// DeleteReportSchedule godoc
+// @Summary Deletes a report schedule
+// @Description Stops a scheduled report. Reports generated so far are kept. For the kitchen owner, staff with the payouts permission and admins
+// @Tags report
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param schedule_id path string true "Schedule ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid kitchen or schedule ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Schedule not found"
+// @Router /kitchens/{id}/reports/schedules/{schedule_id} [delete]
+func (h *Handler) DeleteReportSchedule(c *gin.Context) {
+	h.Logger.Info("DeleteReportSchedule method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err == nil {
+		_, err = pathUUID(c, "schedule_id", "schedule id")
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffPayouts); !ok {
+		return
+	}
+
+	s, ok := h.Storage.ReportSchedules.Get(c.Param("schedule_id"))
+	if !ok || s.KitchenId != kitchenID {
+		er := "schedule not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	h.Storage.ReportSchedules.Delete(s.Id)
+
+	h.Logger.Info("DeleteReportSchedule method has finished successfully")
+	h.render(c, http.StatusOK, "Report schedule deleted successfully")
+}
+
+// FetchReports godoc
+// @Summary Gets generated reports
+// @Description Lists reports generated for a kitchen, newest first. For the kitchen owner, staff with the payouts permission and admins
+// @Tags report
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.Reports
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Access denied"
+// @Router /kitchens/{id}/reports [get]
+func (h *Handler) FetchReports(c *gin.Context) {
+	h.Logger.Info("FetchReports method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffPayouts); !ok {
+		return
+	}
+
+	res := models.Reports{Reports: []models.Report{}}
+	for _, r := range h.Storage.Reports.List() {
+		if r.KitchenId == kitchenID {
+			res.Reports = append(res.Reports, r)
+		}
+	}
+	slices.SortFunc(res.Reports, func(a, b models.Report) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchReports method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// DownloadReport godoc
+// @Summary Downloads a report
+// @Description Serves the file of a generated report. For the kitchen owner, staff with the payouts permission and admins
+// @Tags report
+// @Security ApiKeyAuth
+// @Produce text/csv,application/pdf
+// @Param id path string true "Kitchen ID"
+// @Param report_id path string true "Report ID"
+// @Success 200 {file} file
+// @Failure 400 {object} string "Invalid kitchen or report ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Report not found"
+// @Failure 500 {object} string "Report could not be read"
+// @Router /kitchens/{id}/reports/{report_id} [get]
+func (h *Handler) DownloadReport(c *gin.Context) {
+	h.Logger.Info("DownloadReport method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err == nil {
+		_, err = pathUUID(c, "report_id", "report id")
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, models.StaffPayouts); !ok {
+		return
+	}
+
+	r, ok := h.Storage.Reports.Get(c.Param("report_id"))
+	var data []byte
+	if ok && r.KitchenId == kitchenID {
+		data, err = h.Reports.Read(r)
+	}
+	if !ok || r.KitchenId != kitchenID || os.IsNotExist(err) {
+		er := "report not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	if err != nil {
+		er := errors.Wrap(err, "error reading report").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", report.Filename(r)))
+	h.Logger.Info("DownloadReport method has finished successfully")
+	c.Data(http.StatusOK, report.ContentTypes[r.Format], data)
+}
+
+// ExportStatistics godoc
+// @Summary Exports kitchen's statistics
+// @Description Renders kitchen statistics by date as a CSV or PDF file, leaving out sandbox orders. For the kitchen owner, staff with the payouts permission and admins
+// @Tags report
+// @Security ApiKeyAuth
+// @Produce text/csv,application/pdf
+// @Param id path string true "Kitchen ID"
+// @Param start_date query string false "start date, unless period is given"
+// @Param end_date query string false "end date, unless period is given"
+// @Param period query string false "Days up to today in place of the dates" Enums(today, yesterday, last_7_days, last_30_days, last_90_days, this_month, last_month, this_year)
+// @Param format query string true "File format" Enums(csv, pdf)
+// @Success 200 {file} file
+// @Failure 400 {object} string "Invalid kitchen ID, date, period or format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /kitchens/{id}/statistics/export [get]
+func (h *Handler) ExportStatistics(c *gin.Context) {
+	h.Logger.Info("ExportStatistics method is starting")
+
+	id, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", id, models.StaffPayouts); !ok {
+		return
+	}
+
+	period := report.Period{KitchenId: id}
+	start, end, err := analyticsPeriod(c, h.TimeZones.Of(id))
+	if err == nil {
+		period.StartDate, period.EndDate = start.Format("2006-01-02"), end.Format("2006-01-02")
+	}
+	format := c.Query("format")
+	if _, ok := report.ContentTypes[format]; err == nil && !ok {
+		err = errors.Errorf("invalid format %q", format)
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, 10*time.Second)
+	defer cancel()
+
+	in := &pb.Period{
+		Id:        id,
+		StartDate: period.StartDate,
+		EndDate:   period.EndDate,
+	}
+	stats, err := h.ExtraClient.GetStatistics(ctx, in)
+	if err == nil {
+		stats = analytics.WithoutSandbox(h.Storage.SandboxOrders, in, h.TimeZones.Of(id), stats)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "error getting statistics").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	data, err := report.Render(format, period, stats)
+	if err != nil {
+		er := errors.Wrap(err, "error rendering report").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		report.Filename(models.Report{StartDate: period.StartDate, EndDate: period.EndDate, Format: format})))
+
+	h.Logger.Info("ExportStatistics method has finished successfully")
+	c.Data(http.StatusOK, report.ContentTypes[format], data)
+}
+
+func (h *Handler) validateReportSchedule(data *models.NewReportSchedule) error {
+	if data.Frequency != models.ReportWeekly && data.Frequency != models.ReportMonthly {
+		return errors.Errorf("frequency must be %s or %s", models.ReportWeekly, models.ReportMonthly)
+	}
+	if _, ok := report.ContentTypes[data.Format]; !ok {
+		return errors.Errorf("format must be %s or %s", models.ReportCSV, models.ReportPDF)
+	}
+
+	switch data.Delivery {
+	case models.ReportDeliveryEmail:
+		if !h.Reports.EmailEnabled() {
+			return email.ErrDisabled
+		}
+		addr, err := mail.ParseAddress(data.Email)
+		if err != nil {
+			return errors.Wrap(err, "invalid email")
+		}
+		data.Email = addr.Address
+	case models.ReportDeliveryWebhook:
+		data.Email = ""
+	default:
+		return errors.Errorf("delivery must be %s or %s", models.ReportDeliveryEmail, models.ReportDeliveryWebhook)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/retention"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// FetchRetentionRuns godoc
+// @Summary Gets data retention runs
+// @Description Gives the retention policy and the reports of the latest runs, latest first: how many orders and payments were anonymized in the services, what gateway records were purged, by kind, and what failed to be anonymized
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.RetentionRuns
+// @Router /admin/retention [get]
+func (h *Handler) FetchRetentionRuns(c *gin.Context) {
+	h.Logger.Info("FetchRetentionRuns method is starting")
+
+	res := models.RetentionRuns{
+		Policy: h.Retention.Policy(),
+		Runs:   h.Retention.Runs(),
+	}
+
+	h.Logger.Info("FetchRetentionRuns method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetRetentionRun godoc
+// @Summary Gets a data retention run
+// @Description Gives the report of a retention run
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Run ID"
+// @Success 200 {object} models.RetentionRun
+// @Failure 400 {object} string "Invalid run ID"
+// @Failure 404 {object} string "Run not found"
+// @Router /admin/retention/{id} [get]
+func (h *Handler) GetRetentionRun(c *gin.Context) {
+	h.Logger.Info("GetRetentionRun method is starting")
+
+	id, err := pathUUID(c, "id", "run id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	run, ok := h.Storage.RetentionRuns.Get(id)
+	if !ok {
+		er := "retention run not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetRetentionRun method has finished successfully")
+	h.render(c, http.StatusOK, run)
+}
+
+// RunRetention godoc
+// @Summary Runs data retention
+// @Description Anonymizes the orders and payments older than the policy's record days in the services, purges the ended group orders and meal plans and settled tickets older than its record days and clears the comments of refund requests decided before then, and purges the gateway's logs older than its log days now, even if scheduled runs are disabled, and returns the report. Orders the services fail to anonymize are kept and tried again on the next run
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.RetentionRun
+// @Failure 409 {object} string "A run is already in progress"
+// @Router /admin/retention [post]
+func (h *Handler) RunRetention(c *gin.Context) {
+	h.Logger.Info("RunRetention method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.Retention.Run(time.Now(), models.RetentionManual, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, retention.ErrRunning) {
+			status = http.StatusConflict
+		}
+		er := err.Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("RunRetention method has finished successfully")
+	h.render(c, http.StatusOK, run)
+}
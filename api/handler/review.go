@@ -1,43 +1,93 @@
 package handler
 
 import (
+	"api-gateway/api/middleware"
+	pbo "api-gateway/genproto/order"
 	pb "api-gateway/genproto/review"
+	"api-gateway/models"
+	"api-gateway/pkg/moderation"
 	"context"
+	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
+// reviewPageSize is how many reviews are read per call when the gateway
+// filters a kitchen's reviews itself.
+const reviewPageSize = 100
+
 // CreateReview godoc
 // @Summary Creates a review
-// @Description Inserts a new review into database
+// @Description Inserts a new review into database. The comment is moderated first: reviews with profanity or links are refused, and suspicious ones are held for an admin's approval
 // @Tags review
 // @Security ApiKeyAuth
 // @Param review body review.NewReview true "Review info"
 // @Success 200 {object} review.NewReviewResp
+// @Success 202 {object} models.HeldReview "Review held for moderation"
 // @Failure 400 {object} string "Invalid review data"
+// @Failure 422 {object} string "Review refused by moderation"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /reviews [post]
 func (h *Handler) CreateReview(c *gin.Context) {
 	h.Logger.Info("CreateReview method is starting")
 
-	var data pb.NewReview
-	if err := c.ShouldBindJSON(&data); err != nil {
-		er := errors.Wrap(err, "invalid review data").Error()
+	data, err := bindJSON[pb.NewReview](c, "review data")
+	if err != nil {
+		er := err.Error()
 		c.AbortWithStatusJSON(http.StatusBadRequest,
 			gin.H{"error": er})
 		h.Logger.Error(er)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
+	m := h.Moderator.Check(c, data.Comment)
+	// Reviews of shadow-banned accounts are held, telling their authors no
+	// more than any held review would, until an admin approves them.
+	if m.Action != moderation.Reject && c.GetString(middleware.AbuseStatusKey) == models.AbuseShadowBan {
+		m = moderation.Result{Action: moderation.Review, Reason: moderation.ReasonAbuse,
+			Detail: "was written by a shadow-banned account"}
+	}
+
+	switch m.Action {
+	case moderation.Reject:
+		er := "review refused: it " + m.Detail
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity,
+			gin.H{"error": er, "reason": m.Reason})
+		h.Logger.Error(er)
+		return
+	case moderation.Review:
+		held := models.HeldReview{
+			Id:        uuid.NewString(),
+			UserId:    c.GetString(middleware.UserIDKey),
+			OrderId:   data.OrderId,
+			Rating:    data.Rating,
+			Comment:   data.Comment,
+			Reason:    m.Reason,
+			Detail:    m.Detail,
+			Status:    models.ModerationPending,
+			CreatedAt: time.Now().Format(time.RFC3339),
+		}
+		h.Storage.HeldReviews.Set(held.Id, held)
+		if held.Reason == moderation.ReasonAbuse {
+			held.Reason, held.Detail = moderation.ReasonExternal, "needs a moderator's review"
+		}
+
+		h.Logger.Info("CreateReview method has finished successfully")
+		h.render(c, http.StatusAccepted, held)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
 	defer cancel()
 
-	res, err := h.ReviewClient.RateAndComment(ctx, &data)
+	res, err := h.publishReview(ctx, data)
 	if err != nil {
 		er := errors.Wrap(err, "failed to create review").Error()
 		c.AbortWithStatusJSON(http.StatusInternalServerError,
@@ -46,70 +96,211 @@ func (h *Handler) CreateReview(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, res)
+	h.Logger.Info("CreateReview method has finished successfully")
+	h.render(c, http.StatusOK, res)
 }
 
 // GetReviews godoc
 // @Summary Gets reviews
-// @Description Gets reviews from database
+// @Description Gets reviews from database. Each review tells whether it is a verified purchase, written by the customer of its order at the reviewed kitchen. Filtering by rating or verified purchase is done by the gateway, which then counts the total and average rating of the matching reviews
 // @Tags review
 // @Security ApiKeyAuth
 // @Param id path string true "Kitchen ID"
 // @Param page query int true "Page number"
 // @Param limit query int true "Number of items per page"
+// @Param min_rating query number false "Lowest rating, 1 to 5"
+// @Param max_rating query number false "Highest rating, 1 to 5"
+// @Param verified_only query bool false "Only verified purchases"
 // @Success 200 {object} review.Reviews
 // @Failure 400 {object} string "Invalid review data"
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
 // @Router /kitchens/{id}/reviews [get]
 func (h *Handler) GetReviews(c *gin.Context) {
-	h.Logger.Info("GetReviews method is starting")
+	serve(h, c, Proxy[*reviewQuery, *pb.Reviews]{
+		Name:    "GetReviews",
+		Bind:    bindReviewQuery,
+		Call:    h.fetchReviews,
+		Error:   "error getting reviews",
+		Timeout: 10 * time.Second,
+		Render:  h.renderReviews,
+	})
+}
 
-	kitchenID := c.Param("id")
-	page := c.Query("page")
-	limit := c.Query("limit")
+// reviewQuery asks for a page of a kitchen's reviews with the filters the
+// review service lacks.
+type reviewQuery struct {
+	filter       *pb.Filter
+	minRating    float32
+	maxRating    float32
+	verifiedOnly bool
+}
+
+func (q *reviewQuery) filtered() bool {
+	return q.minRating > 1 || q.maxRating < 5 || q.verifiedOnly
+}
 
-	_, err := uuid.Parse(kitchenID)
+func bindReviewQuery(c *gin.Context) (*reviewQuery, error) {
+	kitchenID, err := pathUUID(c, "id", "kitchen ID")
 	if err != nil {
-		er := errors.Wrap(err, "invalid dish ID").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
+		return nil, err
 	}
 
-	p, err := strconv.Atoi(page)
+	limit, offset, err := pagination(c)
 	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
+		return nil, err
+	}
+
+	q := &reviewQuery{
+		filter: &pb.Filter{
+			KitchenId: kitchenID,
+			Limit:     limit,
+			Offset:    offset,
+		},
+		minRating: 1,
+		maxRating: 5,
+	}
+
+	for _, r := range []struct {
+		name string
+		v    *float32
+	}{{"min_rating", &q.minRating}, {"max_rating", &q.maxRating}} {
+		s := c.Query(r.name)
+		if s == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(s, 32)
+		if err != nil || f < 1 || f > 5 {
+			return nil, errors.Errorf("invalid %s: must be a number from 1 to 5", r.name)
+		}
+		*r.v = float32(f)
+	}
+	if q.minRating > q.maxRating {
+		return nil, errors.New("invalid rating range: min_rating is above max_rating")
+	}
+
+	if s := c.Query("verified_only"); s != "" {
+		q.verifiedOnly, err = strconv.ParseBool(s)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid verified_only")
+		}
+	}
+
+	return q, nil
+}
+
+// fetchReviews gets a page of reviews from the review service. When
+// filters are given it reads all of the kitchen's reviews and pages the
+// matching ones itself.
+func (h *Handler) fetchReviews(ctx context.Context, q *reviewQuery, opts ...grpc.CallOption) (*pb.Reviews, error) {
+	if !q.filtered() {
+		return h.ReviewClient.GetReviewOfKitchen(ctx, q.filter, opts...)
+	}
+
+	var matched []*pb.ReviewDetails
+	var sum float32
+	for offset := int32(0); ; offset += reviewPageSize {
+		page, err := h.ReviewClient.GetReviewOfKitchen(ctx, &pb.Filter{
+			KitchenId: q.filter.KitchenId,
+			Limit:     reviewPageSize,
+			Offset:    offset,
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range page.Reviews {
+			if r.Rating < q.minRating || r.Rating > q.maxRating {
+				continue
+			}
+			if verified, _ := h.Storage.ReviewPurchases.Get(r.Id); q.verifiedOnly && !verified {
+				continue
+			}
+			matched = append(matched, r)
+			sum += r.Rating
+		}
+		if len(page.Reviews) < reviewPageSize {
+			break
+		}
+	}
+
+	res := &pb.Reviews{
+		Total: int32(len(matched)),
+		Limit: q.filter.Limit,
 	}
+	if len(matched) > 0 {
+		res.AverageRating = sum / float32(len(matched))
+	}
+	if q.filter.Limit > 0 {
+		res.Page = q.filter.Offset/q.filter.Limit + 1
+	}
+
+	start := min(max(q.filter.Offset, 0), res.Total)
+	end := min(start+max(q.filter.Limit, 0), res.Total)
+	res.Reviews = matched[start:end]
+	return res, nil
+}
 
-	l, err := strconv.Atoi(limit)
+// renderReviews renders the reviews with the verified_purchase flag added
+// to each of them.
+func (h *Handler) renderReviews(c *gin.Context, res *pb.Reviews) {
+	data, err := h.encode(c, res)
 	if err != nil {
-		er := errors.Wrap(err, "invalid pagination parameters").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
+		h.write(c, http.StatusOK, res, data, err)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		h.write(c, http.StatusOK, res, data, err)
+		return
+	}
+
+	if list, ok := v["reviews"].([]any); ok {
+		for _, r := range list {
+			r, ok := r.(map[string]any)
+			if !ok {
+				continue
+			}
+			if id, ok := r["id"].(string); ok {
+				r["verified_purchase"], _ = h.Storage.ReviewPurchases.Get(id)
+			}
+		}
+	}
+
+	data, err = json.Marshal(v)
+	h.write(c, http.StatusOK, res, data, err)
+}
+
+// publishReview creates the review in the review service.
+func (h *Handler) publishReview(ctx context.Context, data *pb.NewReview) (*pb.NewReviewResp, error) {
+	res, err := h.ReviewClient.RateAndComment(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	h.recordReviewWritten(res)
+	go h.checkReviewPurchase(res)
+	return res, nil
+}
+
+// checkReviewPurchase records whether the review's order was placed by
+// the review's author at the reviewed kitchen and went through. The
+// review service takes any order ID, so the gateway checks it against
+// the order service. Reviews whose order can't be read stay unverified.
+func (h *Handler) checkReviewPurchase(res *pb.NewReviewResp) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
-	res, err := h.ReviewClient.GetReviewOfKitchen(ctx, &pb.Filter{
-		KitchenId: kitchenID,
-		Limit:     int32(l),
-		Offset:    int32((p - 1) * l),
-	})
+	o, err := h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: res.OrderId})
 	if err != nil {
-		er := errors.Wrap(err, "error getting reviews").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
+		h.Logger.Error(errors.Wrapf(err, "error checking order of review %s", res.Id).Error())
+		h.Storage.ReviewPurchases.Set(res.Id, false)
 		return
 	}
 
-	c.JSON(http.StatusOK, res)
+	status := strings.ToLower(o.Status)
+	verified := o.UserId == res.UserId && o.KitchenId == res.KitchenId &&
+		status != "cancelled" && status != "canceled" && status != "rejected"
+	h.Storage.ReviewPurchases.Set(res.Id, verified)
 }
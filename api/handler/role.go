@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// FetchRoles godoc
+// @Summary Gets roles
+// @Description Lists the built-in and custom roles with their permissions
+// @Tags role
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Roles
+// @Router /admin/roles [get]
+func (h *Handler) FetchRoles(c *gin.Context) {
+	h.Logger.Info("FetchRoles method is starting")
+
+	res := models.Roles{Roles: h.Storage.Roles.List()}
+	slices.SortFunc(res.Roles, func(a, b models.Role) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	h.Logger.Info("FetchRoles method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// FetchPermissions godoc
+// @Summary Gets permissions
+// @Description Lists the permissions a role can be granted
+// @Tags role
+// @Security ApiKeyAuth
+// @Success 200 {object} models.PermissionList
+// @Router /admin/roles/permissions [get]
+func (h *Handler) FetchPermissions(c *gin.Context) {
+	h.Logger.Info("FetchPermissions method is starting")
+
+	res := models.PermissionList{Permissions: slices.Clone(models.Permissions)}
+
+	h.Logger.Info("FetchPermissions method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// CreateRole godoc
+// @Summary Creates a role
+// @Description Adds a custom role. Tokens whose role claim names it get its permissions
+// @Tags role
+// @Security ApiKeyAuth
+// @Param role body models.NewRoleWithName true "Role info"
+// @Success 200 {object} models.Role
+// @Failure 400 {object} string "Invalid role data"
+// @Failure 409 {object} string "Role already exists"
+// @Router /admin/roles [post]
+func (h *Handler) CreateRole(c *gin.Context) {
+	h.Logger.Info("CreateRole method is starting")
+
+	var data models.NewRoleWithName
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid role data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if !cuisineCode.MatchString(data.Name) {
+		er := "invalid role data: name must be up to 32 lowercase letters, digits or underscores"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.Storage.Roles.Get(data.Name); ok {
+		er := errors.Errorf("role %s already exists", data.Name).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	r, ok := h.saveRole(c, data.Name, data.NewRole, "")
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("CreateRole method has finished successfully")
+	h.render(c, http.StatusOK, r)
+}
+
+// UpdateRole godoc
+// @Summary Updates a role
+// @Description Replaces the description and permissions of a custom role. Tokens already issued get the new permissions right away
+// @Tags role
+// @Security ApiKeyAuth
+// @Param name path string true "Role name"
+// @Param role body models.NewRole true "Role info"
+// @Success 200 {object} models.Role
+// @Failure 400 {object} string "Invalid role data"
+// @Failure 404 {object} string "Role not found"
+// @Failure 409 {object} string "Built-in role"
+// @Router /admin/roles/{name} [put]
+func (h *Handler) UpdateRole(c *gin.Context) {
+	h.Logger.Info("UpdateRole method is starting")
+
+	cur, ok := h.customRole(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewRole
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid role data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	r, ok := h.saveRole(c, cur.Name, data, cur.CreatedAt)
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("UpdateRole method has finished successfully")
+	h.render(c, http.StatusOK, r)
+}
+
+// DeleteRole godoc
+// @Summary Deletes a role
+// @Description Removes a custom role. Tokens naming it lose its permissions
+// @Tags role
+// @Security ApiKeyAuth
+// @Param name path string true "Role name"
+// @Success 200 {object} string
+// @Failure 404 {object} string "Role not found"
+// @Failure 409 {object} string "Built-in role"
+// @Router /admin/roles/{name} [delete]
+func (h *Handler) DeleteRole(c *gin.Context) {
+	h.Logger.Info("DeleteRole method is starting")
+
+	r, ok := h.customRole(c)
+	if !ok {
+		return
+	}
+
+	h.Storage.Roles.Delete(r.Name)
+
+	h.Logger.Info("DeleteRole method has finished successfully")
+	h.render(c, http.StatusOK, "Role deleted successfully")
+}
+
+// customRole finds the role of the name path parameter, refusing the
+// built-in ones.
+func (h *Handler) customRole(c *gin.Context) (models.Role, bool) {
+	r, ok := h.Storage.Roles.Get(c.Param("name"))
+	if !ok {
+		er := "role not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Role{}, false
+	}
+
+	if r.BuiltIn {
+		er := errors.Errorf("built-in role %s can't be changed", r.Name).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Role{}, false
+	}
+	return r, true
+}
+
+// saveRole validates the role's permissions and stores it under name.
+func (h *Handler) saveRole(c *gin.Context, name string, data models.NewRole, createdAt string) (models.Role, bool) {
+	data.Description = strings.TrimSpace(data.Description)
+
+	perms := []string{}
+	for _, p := range data.Permissions {
+		if !slices.Contains(models.Permissions, p) {
+			er := errors.Errorf("invalid role data: unknown permission %q", p).Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return models.Role{}, false
+		}
+		if !slices.Contains(perms, p) {
+			perms = append(perms, p)
+		}
+	}
+	data.Permissions = perms
+
+	now := time.Now().Format(time.RFC3339)
+	r := models.Role{
+		Name:      name,
+		NewRole:   data,
+		CreatedAt: cmp.Or(createdAt, now),
+		UpdatedAt: now,
+	}
+	h.Storage.Roles.Set(name, r)
+	return r, true
+}
+
+func seedRoles(s *storage.Storage) {
+	now := time.Now().Format(time.RFC3339)
+	for _, r := range models.BuiltInRoles {
+		r.CreatedAt, r.UpdatedAt = now, now
+		s.Roles.Set(r.Name, r)
+	}
+}
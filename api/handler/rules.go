@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/pricing"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxDeliveryDistanceKm = 100
+	// maxPrepLeadMinutes is a week.
+	maxPrepLeadMinutes = 7 * 24 * 60
+)
+
+// GetKitchenRules godoc
+// @Summary Gets kitchen ordering rules
+// @Description Tells the minimum order amount, delivery distance and preparation lead time orders at the kitchen must meet. Zero means no limit
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.KitchenRules
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Router /kitchens/{id}/rules [get]
+func (h *Handler) GetKitchenRules(c *gin.Context) {
+	h.Logger.Info("GetKitchenRules method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetKitchenRules method has finished successfully")
+	h.render(c, http.StatusOK, h.kitchenRules(kitchenID))
+}
+
+// SetKitchenRules godoc
+// @Summary Sets kitchen ordering rules
+// @Description Replaces the rules orders at the kitchen must meet; zero turns a rule off. Orders breaking a rule are refused with 422 and the rule's name. For the kitchen's owner and admins
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param rules body models.NewKitchenRules true "Ordering rules"
+// @Success 200 {object} models.KitchenRules
+// @Failure 400 {object} string "Invalid kitchen ID or rules"
+// @Failure 403 {object} string "Not the kitchen's owner"
+// @Router /kitchens/{id}/rules [put]
+func (h *Handler) SetKitchenRules(c *gin.Context) {
+	h.Logger.Info("SetKitchenRules method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+	userID, _, _ := h.caller(c)
+
+	var data models.NewKitchenRules
+	err = c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateKitchenRules(data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid rules").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	r := models.KitchenRules{
+		KitchenId:       kitchenID,
+		NewKitchenRules: data,
+		UpdatedBy:       userID,
+		UpdatedAt:       time.Now().Format(time.RFC3339),
+	}
+	h.Storage.KitchenRules.Set(kitchenID, r)
+
+	h.Logger.Info("SetKitchenRules method has finished successfully")
+	h.render(c, http.StatusOK, r)
+}
+
+func validateKitchenRules(data models.NewKitchenRules) error {
+	if data.MinOrderAmount < 0 {
+		return errors.New("min_order_amount must not be negative")
+	}
+	if data.MaxDistanceKm < 0 || data.MaxDistanceKm > maxDeliveryDistanceKm {
+		return errors.Errorf("max_distance_km must be between 0 and %d", maxDeliveryDistanceKm)
+	}
+	if data.PrepLeadMinutes < 0 || data.PrepLeadMinutes > maxPrepLeadMinutes {
+		return errors.Errorf("prep_lead_minutes must be between 0 and %d", maxPrepLeadMinutes)
+	}
+	return nil
+}
+
+func (h *Handler) kitchenRules(kitchenID string) models.KitchenRules {
+	r, ok := h.Storage.KitchenRules.Get(kitchenID)
+	if !ok {
+		r.KitchenId = kitchenID
+	}
+	return r
+}
+
+// checkOrderRules returns a *models.RuleError for the first rule of the
+// kitchen the quoted order breaks. Orders without a delivery time are
+// taken to be wanted as soon as possible and are not held to the lead
+// time.
+func (h *Handler) checkOrderRules(data models.NewOrder, quote pricing.Quote, now time.Time) error {
+	r := h.kitchenRules(data.KitchenId)
+
+	if amount := quote.Subtotal - quote.Discount; r.MinOrderAmount > 0 && amount+pricing.Tolerance < r.MinOrderAmount {
+		return &models.RuleError{Rule: models.RuleMinOrderAmount, Reason: fmt.Sprintf(
+			"the kitchen's minimum order is %.2f, the items come to %.2f", r.MinOrderAmount, amount)}
+	}
+
+	if r.MaxDistanceKm > 0 && data.DistanceKm > r.MaxDistanceKm {
+		return &models.RuleError{Rule: models.RuleMaxDistance, Reason: fmt.Sprintf(
+			"the kitchen delivers within %g km, the address is %g km away", r.MaxDistanceKm, data.DistanceKm)}
+	}
+
+	if r.PrepLeadMinutes > 0 && data.DeliveryTime != "" {
+		earliest := now.Add(time.Duration(r.PrepLeadMinutes) * time.Minute)
+		at, err := time.Parse(time.RFC3339, data.DeliveryTime)
+		if err == nil && at.Before(earliest) {
+			return &models.RuleError{Rule: models.RulePrepLeadTime, Reason: fmt.Sprintf(
+				"the kitchen needs %d minutes to prepare an order, the earliest delivery time is %s",
+				r.PrepLeadMinutes, earliest.Format(time.RFC3339))}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/routing"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// RecordSearch godoc
+// @Summary Records a search
+// @Description Adds a kitchen search to the user's recent searches. Repeating a search moves it to the top
+// @Tags search
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param search body models.SearchQuery true "Search parameters"
+// @Success 200 {object} models.SearchHistoryEntry
+// @Failure 400 {object} string "Invalid user ID or search data"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/searches/history [post]
+func (h *Handler) RecordSearch(c *gin.Context) {
+	h.Logger.Info("RecordSearch method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	query, err := bindSearchQuery(c)
+	if err != nil {
+		er := errors.Wrap(err, "invalid search data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	entry := models.SearchHistoryEntry{
+		SearchQuery: *query,
+		SearchedAt:  time.Now().Format(time.RFC3339),
+	}
+	h.Storage.SearchHistory.Update(userID, func(history []models.SearchHistoryEntry, _ bool) []models.SearchHistoryEntry {
+		history = slices.DeleteFunc(slices.Clone(history), func(e models.SearchHistoryEntry) bool {
+			return e.SearchQuery == entry.SearchQuery
+		})
+		history = append([]models.SearchHistoryEntry{entry}, history...)
+		if len(history) > models.MaxSearchHistory {
+			history = history[:models.MaxSearchHistory]
+		}
+		return history
+	})
+
+	h.Logger.Info("RecordSearch method has finished successfully")
+	h.render(c, http.StatusOK, entry)
+}
+
+// FetchSearchHistory godoc
+// @Summary Gets recent searches
+// @Description Lists the user's recent searches, newest first
+// @Tags search
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param limit query int false "Number of searches to return"
+// @Success 200 {object} models.SearchHistory
+// @Failure 400 {object} string "Invalid user ID or limit"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/searches/history [get]
+func (h *Handler) FetchSearchHistory(c *gin.Context) {
+	h.Logger.Info("FetchSearchHistory method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	limit, err := queryInt(c, "limit")
+	if err != nil || limit < 0 {
+		er := "invalid limit"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	history, _ := h.Storage.SearchHistory.Get(userID)
+	if limit > 0 && limit < len(history) {
+		history = history[:limit]
+	}
+	res := models.SearchHistory{Searches: append([]models.SearchHistoryEntry{}, history...)}
+
+	h.Logger.Info("FetchSearchHistory method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// ClearSearchHistory godoc
+// @Summary Clears recent searches
+// @Description Removes all recent searches of the user
+// @Tags search
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid user ID"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/searches/history [delete]
+func (h *Handler) ClearSearchHistory(c *gin.Context) {
+	h.Logger.Info("ClearSearchHistory method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	h.Storage.SearchHistory.Delete(userID)
+
+	h.Logger.Info("ClearSearchHistory method has finished successfully")
+	h.render(c, http.StatusOK, "Search history cleared successfully")
+}
+
+// SaveSearch godoc
+// @Summary Saves a search
+// @Description Saves a named kitchen search. With notify set, the user is notified when a new kitchen matching it opens. A search with radius_km matches kitchens within that distance of lat and lng, so it is notified of once the new kitchen sets its location
+// @Tags search
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param search body models.NewSavedSearch true "Saved search info"
+// @Success 200 {object} models.SavedSearch
+// @Failure 400 {object} string "Invalid user ID or search data"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/searches/saved [post]
+func (h *Handler) SaveSearch(c *gin.Context) {
+	h.Logger.Info("SaveSearch method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.bindSavedSearch(c)
+	if err != nil {
+		er := errors.Wrap(err, "invalid search data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	s := models.SavedSearch{
+		Id:          uuid.NewString(),
+		UserId:      userID,
+		Name:        data.Name,
+		SearchQuery: data.SearchQuery,
+		Notify:      data.Notify,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	}
+	h.Storage.SavedSearches.Set(s.Id, s)
+
+	h.Logger.Info("SaveSearch method has finished successfully")
+	h.render(c, http.StatusOK, s)
+}
+
+// FetchSavedSearches godoc
+// @Summary Gets saved searches
+// @Description Lists the user's saved searches, newest first
+// @Tags search
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.SavedSearches
+// @Failure 400 {object} string "Invalid user ID"
+// @Failure 403 {object} string "Access denied"
+// @Router /users/{id}/searches/saved [get]
+func (h *Handler) FetchSavedSearches(c *gin.Context) {
+	h.Logger.Info("FetchSavedSearches method is starting")
+
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	res := models.SavedSearches{Searches: []models.SavedSearch{}}
+	for _, s := range h.Storage.SavedSearches.List() {
+		if s.UserId == userID {
+			res.Searches = append(res.Searches, s)
+		}
+	}
+	slices.SortFunc(res.Searches, func(a, b models.SavedSearch) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchSavedSearches method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// UpdateSavedSearch godoc
+// @Summary Updates a saved search
+// @Description Changes the name, parameters or notification opt-in of a saved search
+// @Tags search
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param search_id path string true "Saved search ID"
+// @Param search body models.NewSavedSearch true "Saved search info"
+// @Success 200 {object} models.SavedSearch
+// @Failure 400 {object} string "Invalid user or search ID or search data"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Saved search not found"
+// @Router /users/{id}/searches/saved/{search_id} [put]
+func (h *Handler) UpdateSavedSearch(c *gin.Context) {
+	h.Logger.Info("UpdateSavedSearch method is starting")
+
+	s, ok := h.findSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.bindSavedSearch(c)
+	if err != nil {
+		er := errors.Wrap(err, "invalid search data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	s.Name = data.Name
+	s.SearchQuery = data.SearchQuery
+	s.Notify = data.Notify
+	h.Storage.SavedSearches.Set(s.Id, s)
+
+	h.Logger.Info("UpdateSavedSearch method has finished successfully")
+	h.render(c, http.StatusOK, s)
+}
+
+// DeleteSavedSearch godoc
+// @Summary Deletes a saved search
+// @Description Removes a saved search and stops its notifications
+// @Tags search
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param search_id path string true "Saved search ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid user or search ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Saved search not found"
+// @Router /users/{id}/searches/saved/{search_id} [delete]
+func (h *Handler) DeleteSavedSearch(c *gin.Context) {
+	h.Logger.Info("DeleteSavedSearch method is starting")
+
+	s, ok := h.findSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	h.Storage.SavedSearches.Delete(s.Id)
+
+	h.Logger.Info("DeleteSavedSearch method has finished successfully")
+	h.render(c, http.StatusOK, "Saved search deleted successfully")
+}
+
+// searchedKitchen is a kitchen as the kitchen service gives it back on
+// creation and on reads.
+type searchedKitchen interface {
+	GetId() string
+	GetName() string
+	GetDescription() string
+	GetCuisineType() string
+	GetRating() float32
+}
+
+// notifySavedSearches publishes a match for every saved search with
+// notifications on that would find the new kitchen, unless its user
+// turned off promo pushes. A new kitchen has no location yet, so searches
+// with a radius are matched only once it is located, and the others only
+// when it is created.
+func (h *Handler) notifySavedSearches(k searchedKitchen, located bool) {
+	loc, _ := h.Storage.KitchenLocations.Get(k.GetId())
+	for _, s := range h.Storage.SavedSearches.List() {
+		if !s.Notify || located != (s.RadiusKm > 0) {
+			continue
+		}
+		distance := -1.0
+		if located {
+			distance = routing.Haversine(s.Center(), loc.Location)
+		}
+		if !s.Matches(k.GetName(), k.GetDescription(), k.GetCuisineType(), k.GetRating(), distance) ||
+			!h.notifies(s.UserId, models.NotifyPromos, models.ChannelPush) {
+			continue
+		}
+
+		h.Events.Emit(models.EventSearchMatched, s.UserId, models.SearchMatch{
+			UserId:      s.UserId,
+			SearchId:    s.Id,
+			SearchName:  s.Name,
+			KitchenId:   k.GetId(),
+			KitchenName: k.GetName(),
+		})
+	}
+}
+
+func (h *Handler) findSavedSearch(c *gin.Context) (models.SavedSearch, bool) {
+	userID, ok := h.selfOrAdmin(c)
+	if !ok {
+		return models.SavedSearch{}, false
+	}
+	if _, err := pathUUID(c, "search_id", "search id"); err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.SavedSearch{}, false
+	}
+
+	s, ok := h.Storage.SavedSearches.Get(c.Param("search_id"))
+	if !ok || s.UserId != userID {
+		er := "saved search not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.SavedSearch{}, false
+	}
+
+	return s, true
+}
+
+func bindSearchQuery(c *gin.Context) (*models.SearchQuery, error) {
+	var q models.SearchQuery
+	if err := c.ShouldBindJSON(&q); err != nil {
+		return nil, err
+	}
+	q.Query = strings.TrimSpace(q.Query)
+	if q.Empty() {
+		return nil, errors.New("search parameters are empty")
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (h *Handler) bindSavedSearch(c *gin.Context) (*models.NewSavedSearch, error) {
+	var data models.NewSavedSearch
+	if err := c.ShouldBindJSON(&data); err != nil {
+		return nil, err
+	}
+	data.Name = strings.TrimSpace(data.Name)
+	data.Query = strings.TrimSpace(data.Query)
+	if data.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if data.Empty() {
+		return nil, errors.New("search parameters are empty")
+	}
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Kitchens are saved with canonical cuisine names, so matching new
+	// ones needs the canonical name too.
+	cuisineType, err := h.canonicalCuisine(data.CuisineType)
+	if err != nil {
+		return nil, err
+	}
+	data.CuisineType = cuisineType
+	return &data, nil
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetSitemap serves the sitemap of the web app's public pages. It is
+// served at the site root, where crawlers look for it.
+func (h *Handler) GetSitemap(c *gin.Context) {
+	h.Logger.Info("GetSitemap method is starting")
+
+	data, err := h.Site.Sitemap(c)
+	if err != nil {
+		er := errors.Wrap(err, "error building sitemap").Error()
+		c.AbortWithStatusJSON(http.StatusBadGateway,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+
+	h.Logger.Info("GetSitemap method has finished successfully")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+}
+
+// GetKitchenMeta godoc
+// @Summary Gets kitchen page metadata
+// @Description Returns the title, description and OpenGraph properties of the kitchen's public page, for the website to render link previews
+// @Tags kitchen
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.PageMeta
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 404 {object} string "Kitchen not found"
+// @Failure 503 {object} string "Gateway overloaded, retry later"
+// @Router /kitchens/{id}/meta [get]
+func (h *Handler) GetKitchenMeta(c *gin.Context) {
+	h.Logger.Info("GetKitchenMeta method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	meta, err := h.Site.KitchenMeta(c, kitchenID)
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting kitchen").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetKitchenMeta method has finished successfully")
+	h.render(c, http.StatusOK, meta)
+}
+
+// GetMenuPage serves the kitchen's menu as a plain HTML page in the
+// language of the lang query or the Accept-Language header. It is served
+// at the site root for menu QR codes, so scanning one needs no app.
+func (h *Handler) GetMenuPage(c *gin.Context) {
+	h.Logger.Info("GetMenuPage method is starting")
+
+	kitchenID, err := pathUUID(c, "kitchen_id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	menu, err := h.Site.Menu(c, kitchenID)
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting menu").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	lang := requestLang(c)
+	menu = h.localizeMenu(menu, lang)
+	cuisine := menu.Kitchen.CuisineType
+	for _, cu := range h.Storage.Cuisines.List() {
+		if cu.Matches(cuisine) {
+			cuisine = cu.Localized(lang).DisplayName
+			break
+		}
+	}
+
+	var page bytes.Buffer
+	if err := h.Site.RenderMenu(&page, menu, lang, cuisine, h.Payments.Currency()); err != nil {
+		er := errors.Wrap(err, "error rendering menu").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Header("Vary", "Accept-Language")
+
+	h.Logger.Info("GetMenuPage method has finished successfully")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
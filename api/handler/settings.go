@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/settings"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetSettings godoc
+// @Summary Gets platform settings
+// @Description Gets the fees, zone enforcement, maintenance mode, banners and oldest app versions served in effect, with their version
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Settings
+// @Router /admin/settings [get]
+func (h *Handler) GetSettings(c *gin.Context) {
+	h.Logger.Info("GetSettings method is starting")
+
+	res := h.Settings.Get()
+
+	h.Logger.Info("GetSettings method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// UpdateSettings godoc
+// @Summary Updates platform settings
+// @Description Replaces the sections given, leaving the others as they are, and records the change. Fees apply to orders priced from then on. With version set, the update is refused if the settings were changed since
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param settings body models.SettingsUpdate true "Sections to replace"
+// @Success 200 {object} models.Settings
+// @Failure 400 {object} string "Invalid settings"
+// @Failure 409 {object} string "Settings were changed meanwhile"
+// @Router /admin/settings [put]
+func (h *Handler) UpdateSettings(c *gin.Context) {
+	h.Logger.Info("UpdateSettings method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.SettingsUpdate
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid settings").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res, err := h.Settings.Update(data, userID, time.Now())
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Cause(err) == settings.ErrVersion {
+			status = http.StatusConflict
+		}
+		er := err.Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("UpdateSettings method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// FetchSettingsHistory godoc
+// @Summary Gets the history of platform settings
+// @Description Lists the changes of the settings, newest first, with each changed section before and after, optionally of one section
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param section query string false "fees, zones, maintenance, banners or app_versions"
+// @Success 200 {object} models.SettingsChanges
+// @Router /admin/settings/history [get]
+func (h *Handler) FetchSettingsHistory(c *gin.Context) {
+	h.Logger.Info("FetchSettingsHistory method is starting")
+
+	res := models.SettingsChanges{Changes: []models.SettingsChange{}}
+	res.Changes = append(res.Changes, h.Settings.History(c.Query("section"))...)
+
+	h.Logger.Info("FetchSettingsHistory method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// FetchBanners godoc
+// @Summary Gets platform banners
+// @Description Lists the banners to show at the top of the app now. Anyone may read them, before signing in too
+// @Tags settings
+// @Success 200 {object} models.PlatformBanners
+// @Router /banners [get]
+func (h *Handler) FetchBanners(c *gin.Context) {
+	h.Logger.Info("FetchBanners method is starting")
+
+	res := models.PlatformBanners{Banners: h.Settings.ActiveBanners(time.Now())}
+
+	h.Logger.Info("FetchBanners method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
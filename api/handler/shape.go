@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	formatLegacy   = "legacy"
+	formatStandard = "standard"
+)
+
+// moneyFields hold amounts of money; in the standard format they are sent
+// as decimal strings so clients never see float artifacts like 9.9899998.
+var moneyFields = map[protoreflect.Name]bool{
+	"price":         true,
+	"amount":        true,
+	"total_amount":  true,
+	"revenue":       true,
+	"total_revenue": true,
+	"total_spent":   true,
+}
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// apiFormat picks the response format from the X-API-Format header,
+// falling back to the configured default.
+func (h *Handler) apiFormat(c *gin.Context) string {
+	switch f := strings.ToLower(c.GetHeader("X-API-Format")); f {
+	case formatLegacy, formatStandard:
+		return f
+	}
+	return h.APIFormat
+}
+
+// shape converts a message to the standard format: timestamps as RFC3339,
+// enums as lowercase strings and money as decimal strings.
+func (h *Handler) shape(m protoreflect.Message) map[string]any {
+	res := make(map[string]any)
+
+	visit := func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := fd.JSONName()
+		if h.Marshaler.UseProtoNames {
+			name = string(fd.Name())
+		}
+
+		switch {
+		case fd.IsList():
+			list := make([]any, v.List().Len())
+			for i := range list {
+				list[i] = h.shapeValue(fd, v.List().Get(i))
+			}
+			res[name] = list
+		case fd.IsMap():
+			m := make(map[string]any)
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				m[k.String()] = h.shapeValue(fd.MapValue(), mv)
+				return true
+			})
+			res[name] = m
+		default:
+			res[name] = h.shapeValue(fd, v)
+		}
+		return true
+	}
+
+	if h.Marshaler.EmitUnpopulated {
+		fields := m.Descriptor().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			visit(fields.Get(i), m.Get(fields.Get(i)))
+		}
+	} else {
+		m.Range(visit)
+	}
+
+	return res
+}
+
+func (h *Handler) shapeValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		msg := v.Message()
+		if msg.Descriptor().FullName() == "google.protobuf.Timestamp" {
+			fields := msg.Descriptor().Fields()
+			sec := msg.Get(fields.ByName("seconds")).Int()
+			nsec := msg.Get(fields.ByName("nanos")).Int()
+			return time.Unix(sec, nsec).UTC().Format(time.RFC3339)
+		}
+		return h.shape(msg)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return strings.ToLower(string(ev.Name()))
+		}
+		return v.Enum()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		if moneyFields[fd.Name()] {
+			return strconv.FormatFloat(v.Float(), 'f', 2, 32)
+		}
+		return v.Interface()
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.String()
+	case protoreflect.StringKind:
+		if strings.HasSuffix(string(fd.Name()), "_at") {
+			return formatTimestamp(v.String())
+		}
+		// Statuses are enums in all but type in the service protos.
+		if fd.Name() == "status" {
+			return strings.ToLower(v.String())
+		}
+		return v.String()
+	case protoreflect.BytesKind:
+		return v.Bytes()
+	}
+	return v.Interface()
+}
+
+// formatTimestamp rewrites the timestamp strings the services send, which
+// come straight from the database, as RFC3339. Unknown formats are kept.
+func formatTimestamp(s string) string {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format(time.RFC3339)
+		}
+	}
+	return s
+}
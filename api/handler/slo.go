@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSLO godoc
+// @Summary Gets backend SLOs
+// @Description Tells where each backend service stands against its availability and latency SLOs: the share of good calls and of the error budget left over the SLO window, and how fast the budget burns over the last 5m, 30m, 1h and 6h. Counted since the gateway started
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 200 {object} models.SLOReport
+// @Router /admin/slo [get]
+func (h *Handler) GetSLO(c *gin.Context) {
+	h.Logger.Info("GetSLO method is starting")
+
+	res := h.SLO.Report(time.Now())
+
+	h.Logger.Info("GetSLO method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
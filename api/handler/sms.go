@@ -0,0 +1,388 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pbo "api-gateway/genproto/order"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/sms"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	otpLength      = 6
+	otpMaxAttempts = 5
+
+	maxCampaignPhones = 1000
+	maxSMSLength      = 640
+)
+
+// SMSCallback godoc
+// @Summary Receives SMS delivery reports
+// @Description Endpoint for eskiz, playmobile and twilio to report whether messages were delivered. Twilio signs its calls; the others must pass the callback secret in the secret query
+// @Tags sms
+// @Param provider path string true "Provider" Enums(eskiz, playmobile, twilio)
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid report"
+// @Failure 401 {object} string "Invalid signature"
+// @Failure 404 {object} string "Unknown provider"
+// @Router /sms/callbacks/{provider} [post]
+func (h *Handler) SMSCallback(c *gin.Context) {
+	h.Logger.Info("SMSCallback method is starting")
+
+	if err := h.SMS.Callback(c.Request, c.Param("provider")); err != nil {
+		status := http.StatusBadRequest
+		switch errors.Cause(err) {
+		case sms.ErrSignature:
+			status = http.StatusUnauthorized
+		case sms.ErrUnknownProvider:
+			status = http.StatusNotFound
+		}
+		er := errors.Wrap(err, "invalid delivery report").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("SMSCallback method has finished successfully")
+	// Providers only look at the status code, so the reply is plain.
+	c.String(http.StatusOK, "OK")
+}
+
+// SendOTP godoc
+// @Summary Sends a one-time code
+// @Description Texts a code to the phone to prove the caller owns it. A new code can be asked for once the resend wait has passed, and replaces the old one
+// @Tags sms
+// @Security ApiKeyAuth
+// @Param phone body models.NewOTP true "Phone in international format"
+// @Success 200 {object} models.OTPSent
+// @Failure 400 {object} string "Invalid phone"
+// @Failure 429 {object} string "Code was sent recently"
+// @Failure 502 {object} string "SMS could not be sent"
+// @Router /otp [post]
+func (h *Handler) SendOTP(c *gin.Context) {
+	h.Logger.Info("SendOTP method is starting")
+
+	userID, ok := h.user(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewOTP
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		data.Phone, err = sms.Normalize(data.Phone)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid phone").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now()
+	if cur, ok := h.Storage.OTPs.Get(data.Phone); ok {
+		sent, _ := time.Parse(time.RFC3339, cur.SentAt)
+		if wait := sent.Add(h.OTPResendWait).Sub(now); wait > 0 {
+			er := "a code was sent recently, try again later"
+			middleware.Throttle(c, http.StatusTooManyRequests,
+				gin.H{"error": er}, middleware.RetryAt(models.RetryResend, wait))
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	code, err := otpCode()
+	if err != nil {
+		er := errors.Wrap(err, "error creating code").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	text := fmt.Sprintf("Your Local Eats code is %s. It expires in %d minutes.",
+		code, int(h.OTPTTL.Minutes()))
+	if _, err := h.SMS.Send(ctx, data.Phone, text, models.SMSPurposeOTP, ""); err != nil {
+		er := errors.Wrap(err, "error sending code").Error()
+		c.AbortWithStatusJSON(http.StatusBadGateway,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	otp := models.OTP{
+		UserId:   userID,
+		Phone:    data.Phone,
+		CodeHash: otpHash(data.Phone, code),
+		SentAt:   now.Format(time.RFC3339),
+		Expires:  now.Add(h.OTPTTL).Format(time.RFC3339),
+	}
+	h.Storage.OTPs.Set(data.Phone, otp)
+
+	h.Logger.Info("SendOTP method has finished successfully")
+	h.render(c, http.StatusOK, models.OTPSent{
+		Phone:       data.Phone,
+		ExpiresAt:   otp.Expires,
+		ResendAfter: now.Add(h.OTPResendWait).Format(time.RFC3339),
+	})
+}
+
+// VerifyOTP godoc
+// @Summary Verifies a one-time code
+// @Description Checks the code texted to the phone. A code works once, and only for the user it was sent to; after five wrong tries a new one must be sent. Passing it lifts an automatic abuse challenge on the account and lets held-back orders and payments needing verification go through
+// @Tags sms
+// @Security ApiKeyAuth
+// @Param code body models.OTPVerification true "Phone and code"
+// @Success 200 {object} models.OTPVerified
+// @Failure 400 {object} string "Invalid phone or wrong code"
+// @Failure 404 {object} string "No code was sent to the phone"
+// @Failure 410 {object} string "Code expired"
+// @Failure 429 {object} string "Too many wrong tries"
+// @Router /otp/verify [post]
+func (h *Handler) VerifyOTP(c *gin.Context) {
+	h.Logger.Info("VerifyOTP method is starting")
+
+	userID, ok := h.user(c)
+	if !ok {
+		return
+	}
+
+	var data models.OTPVerification
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		data.Phone, err = sms.Normalize(data.Phone)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid phone").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if otp, ok := h.Storage.OTPs.Get(data.Phone); !ok || otp.UserId != userID {
+		er := "no code was sent to the phone"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	// Checking and counting the try happen in one update, so parallel
+	// guesses can't get past the limit.
+	var (
+		valid  bool
+		status = http.StatusBadRequest
+		er     = "wrong code"
+	)
+	h.Storage.OTPs.Update(data.Phone, func(otp models.OTP, _ bool) models.OTP {
+		expires, _ := time.Parse(time.RFC3339, otp.Expires)
+		switch {
+		case time.Now().After(expires):
+			status, er = http.StatusGone, "code has expired"
+		case otp.Attempts >= otpMaxAttempts:
+			status, er = http.StatusTooManyRequests, "too many wrong tries, ask for a new code"
+		default:
+			otp.Attempts++
+			valid = subtle.ConstantTimeCompare([]byte(otp.CodeHash),
+				[]byte(otpHash(data.Phone, strings.TrimSpace(data.Code)))) == 1
+		}
+		return otp
+	})
+	if !valid {
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	h.Storage.OTPs.Delete(data.Phone)
+	h.Abuse.Pass(userID, time.Now())
+	h.Fraud.Verified(userID, time.Now())
+
+	h.Logger.Info("VerifyOTP method has finished successfully")
+	h.render(c, http.StatusOK, models.OTPVerified{Phone: data.Phone, Verified: true})
+}
+
+// CreateSMSCampaign godoc
+// @Summary Sends a marketing SMS
+// @Description Texts a message to up to 1000 phones. Messages are sent in the background; their delivery can be followed by the campaign ID
+// @Tags sms
+// @Security ApiKeyAuth
+// @Param campaign body models.NewSMSCampaign true "Phones and text"
+// @Success 202 {object} models.SMSCampaign
+// @Failure 400 {object} string "Invalid campaign data"
+// @Failure 503 {object} string "SMS is not configured"
+// @Router /admin/sms/campaigns [post]
+func (h *Handler) CreateSMSCampaign(c *gin.Context) {
+	h.Logger.Info("CreateSMSCampaign method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	if !h.SMS.Enabled() {
+		er := sms.ErrDisabled.Error()
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var data models.NewSMSCampaign
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateCampaign(&data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid campaign data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	campaign := models.SMSCampaign{
+		Id:         uuid.NewString(),
+		Text:       data.Text,
+		Recipients: len(data.Phones),
+		CreatedBy:  userID,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	go h.sendCampaign(campaign, data.Phones)
+
+	h.Logger.Info("CreateSMSCampaign method has finished successfully")
+	h.render(c, http.StatusAccepted, campaign)
+}
+
+// FetchSMSMessages godoc
+// @Summary Gets sent SMS
+// @Description Lists sent text messages with their delivery status, newest first, optionally by campaign, purpose or status
+// @Tags sms
+// @Security ApiKeyAuth
+// @Param campaign query string false "Campaign ID"
+// @Param purpose query string false "otp, order_status or marketing"
+// @Param status query string false "sent, delivered or failed"
+// @Success 200 {object} models.SMSMessages
+// @Router /admin/sms/messages [get]
+func (h *Handler) FetchSMSMessages(c *gin.Context) {
+	h.Logger.Info("FetchSMSMessages method is starting")
+
+	campaign, purpose, status := c.Query("campaign"), c.Query("purpose"), c.Query("status")
+	res := models.SMSMessages{Messages: []models.SMSMessage{}}
+	for _, m := range h.Storage.SMSMessages.List() {
+		switch {
+		case campaign != "" && m.Campaign != campaign:
+		case purpose != "" && m.Purpose != purpose:
+		case status != "" && m.Status != status:
+		default:
+			// Codes must not be readable after they were sent.
+			if m.Purpose == models.SMSPurposeOTP {
+				m.Text = ""
+			}
+			res.Messages = append(res.Messages, m)
+		}
+	}
+	slices.SortFunc(res.Messages, func(a, b models.SMSMessage) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchSMSMessages method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+func (h *Handler) sendCampaign(campaign models.SMSCampaign, phones []string) {
+	for _, phone := range phones {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+		_, err := h.SMS.Send(ctx, phone, campaign.Text, models.SMSPurposeMarketing, campaign.Id)
+		cancel()
+		if err != nil {
+			h.Logger.Error(errors.Wrapf(err, "error sending campaign %s", campaign.Id).Error())
+		}
+	}
+}
+
+// smsOrderStatus texts the customer the new status of their order.
+func (h *Handler) smsOrderStatus(ctx context.Context, info *pbo.OrderInfo) {
+	if !h.SMSOrderStatus || !h.SMS.Enabled() ||
+		!h.notifies(info.UserId, models.NotifyOrderUpdates, models.ChannelSMS) {
+		return
+	}
+
+	profile, err := h.UserClient.GetProfile(ctx, &pbu.ID{Id: info.UserId})
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error getting customer for order SMS").Error())
+		return
+	}
+	phone, err := sms.Normalize(profile.PhoneNumber)
+	if err != nil {
+		return
+	}
+
+	text := fmt.Sprintf("Your order from %s is now %s.", info.KitchenName,
+		strings.ReplaceAll(info.Status, "_", " "))
+	if _, err := h.SMS.Send(ctx, phone, text, models.SMSPurposeOrderStatus, ""); err != nil {
+		h.Logger.Error(errors.Wrapf(err, "error texting status of order %s", info.Id).Error())
+	}
+}
+
+// validateCampaign checks the text and normalizes the phones, dropping
+// duplicates.
+func validateCampaign(data *models.NewSMSCampaign) error {
+	data.Text = strings.TrimSpace(data.Text)
+	if data.Text == "" || utf8.RuneCountInString(data.Text) > maxSMSLength {
+		return errors.Errorf("text must have 1 to %d characters", maxSMSLength)
+	}
+	if len(data.Phones) == 0 || len(data.Phones) > maxCampaignPhones {
+		return errors.Errorf("phones must have 1 to %d numbers", maxCampaignPhones)
+	}
+
+	phones := make([]string, 0, len(data.Phones))
+	for _, p := range data.Phones {
+		phone, err := sms.Normalize(p)
+		if err != nil {
+			return errors.Wrapf(err, "phone %q", p)
+		}
+		if !slices.Contains(phones, phone) {
+			phones = append(phones, phone)
+		}
+	}
+	data.Phones = phones
+	return nil
+}
+
+func otpCode() (string, error) {
+	n, err := rand.Int(rand.Reader, new(big.Int).Exp(big.NewInt(10), big.NewInt(otpLength), nil))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", otpLength, n.Int64()), nil
+}
+
+// otpHash ties the code to the phone, so stored hashes can't be reused
+// for another number.
+func otpHash(phone, code string) string {
+	sum := sha256.Sum256([]byte(phone + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,405 @@
+package handler
+
+import (
+	pbk "api-gateway/genproto/kitchen"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/email"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// InviteStaff godoc
+// @Summary Invites kitchen staff
+// @Description Invites a user to help run the kitchen with limited permissions: orders (orders, refunds and support tickets), menu (dish stock) and payouts (earnings, payouts and statistics reports). The user must accept the invitation. For the kitchen owner and admins
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param staff body models.NewStaffMember true "User and permissions"
+// @Success 200 {object} models.StaffMember
+// @Failure 400 {object} string "Invalid kitchen ID, user or permissions"
+// @Failure 403 {object} string "Not the kitchen's owner"
+// @Failure 404 {object} string "User not found"
+// @Failure 409 {object} string "User is already staff"
+// @Router /kitchens/{id}/staff [post]
+func (h *Handler) InviteStaff(c *gin.Context) {
+	h.Logger.Info("InviteStaff method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+	userID, _, _ := h.caller(c)
+
+	var data models.NewStaffMember
+	err = c.ShouldBindJSON(&data)
+	if err == nil {
+		_, err = uuid.Parse(data.UserId)
+	}
+	if err == nil {
+		data.Permissions, err = staffPermissions(data.Permissions)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid staff data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.staffMember(kitchenID, data.UserId); ok {
+		er := "user is already staff of the kitchen"
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	k, err := h.KitchenClient.Get(ctx, &pbk.ID{Id: kitchenID})
+	if err == nil && k.OwnerId == data.UserId {
+		er := "the kitchen's owner can't be its staff"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	var profile *pbu.Profile
+	if err == nil {
+		profile, err = h.UserClient.GetProfile(ctx, &pbu.ID{Id: data.UserId})
+	}
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting kitchen or user").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	m := models.StaffMember{
+		KitchenId:   kitchenID,
+		KitchenName: k.Name,
+		UserId:      data.UserId,
+		Username:    profile.Username,
+		Permissions: data.Permissions,
+		Status:      models.StaffInvited,
+		InvitedBy:   userID,
+		InvitedAt:   time.Now().Format(time.RFC3339),
+	}
+	h.Storage.KitchenStaff.Update(kitchenID, func(staff []models.StaffMember, _ bool) []models.StaffMember {
+		return append(slices.Clone(staff), m)
+	})
+	h.emailStaffInvite(profile.Email, k.Name, requestLang(c))
+
+	h.Logger.Info("InviteStaff method has finished successfully")
+	h.render(c, http.StatusOK, m)
+}
+
+// FetchStaff godoc
+// @Summary Gets kitchen staff
+// @Description Lists the kitchen's staff and pending invitations, oldest first. For the kitchen owner and admins
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.StaffMembers
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Not the kitchen's owner"
+// @Router /kitchens/{id}/staff [get]
+func (h *Handler) FetchStaff(c *gin.Context) {
+	h.Logger.Info("FetchStaff method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+
+	staff, _ := h.Storage.KitchenStaff.Get(kitchenID)
+	res := models.StaffMembers{Staff: append([]models.StaffMember{}, staff...)}
+
+	h.Logger.Info("FetchStaff method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// UpdateStaff godoc
+// @Summary Updates staff permissions
+// @Description Replaces the permissions of a staff member or invitation. For the kitchen owner and admins
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param user_id path string true "User ID"
+// @Param permissions body models.StaffPermissionsUpdate true "Permissions"
+// @Success 200 {object} models.StaffMember
+// @Failure 400 {object} string "Invalid ID or permissions"
+// @Failure 403 {object} string "Not the kitchen's owner"
+// @Failure 404 {object} string "User is not staff"
+// @Router /kitchens/{id}/staff/{user_id} [put]
+func (h *Handler) UpdateStaff(c *gin.Context) {
+	h.Logger.Info("UpdateStaff method is starting")
+
+	kitchenID, userID, ok := h.staffPath(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+
+	var data models.StaffPermissionsUpdate
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		data.Permissions, err = staffPermissions(data.Permissions)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid staff data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	m, ok := h.updateStaffMember(kitchenID, userID, func(m *models.StaffMember) {
+		m.Permissions = data.Permissions
+	})
+	if !ok {
+		er := "user is not staff of the kitchen"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("UpdateStaff method has finished successfully")
+	h.render(c, http.StatusOK, m)
+}
+
+// RemoveStaff godoc
+// @Summary Removes kitchen staff
+// @Description Removes a staff member or withdraws an invitation. Staff may also remove themselves to leave the kitchen or decline an invitation
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid ID"
+// @Failure 403 {object} string "Not the kitchen's owner or the staff member"
+// @Failure 404 {object} string "User is not staff"
+// @Router /kitchens/{id}/staff/{user_id} [delete]
+func (h *Handler) RemoveStaff(c *gin.Context) {
+	h.Logger.Info("RemoveStaff method is starting")
+
+	kitchenID, userID, ok := h.staffPath(c)
+	if !ok {
+		return
+	}
+
+	// The member themselves passes as the customer would.
+	if _, ok := h.accessRole(c, userID, kitchenID, ""); !ok {
+		return
+	}
+
+	if _, ok := h.staffMember(kitchenID, userID); !ok {
+		er := "user is not staff of the kitchen"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Storage.KitchenStaff.Update(kitchenID, func(staff []models.StaffMember, _ bool) []models.StaffMember {
+		return slices.DeleteFunc(slices.Clone(staff), func(m models.StaffMember) bool {
+			return m.UserId == userID
+		})
+	})
+
+	h.Logger.Info("RemoveStaff method has finished successfully")
+	h.render(c, http.StatusOK, "Staff member removed successfully")
+}
+
+// AcceptStaffInvite godoc
+// @Summary Accepts a staff invitation
+// @Description Joins the kitchen as staff, after which the invitation's permissions apply
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.StaffMember
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 404 {object} string "No invitation to the kitchen"
+// @Failure 409 {object} string "Already staff"
+// @Router /kitchens/{id}/staff/accept [post]
+func (h *Handler) AcceptStaffInvite(c *gin.Context) {
+	h.Logger.Info("AcceptStaffInvite method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	userID, ok := h.user(c)
+	if !ok {
+		return
+	}
+
+	cur, ok := h.staffMember(kitchenID, userID)
+	if !ok {
+		er := "no invitation to the kitchen"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	if cur.Status != models.StaffInvited {
+		er := "invitation was already accepted"
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	m, _ := h.updateStaffMember(kitchenID, userID, func(m *models.StaffMember) {
+		m.Status = models.StaffActive
+		m.JoinedAt = time.Now().Format(time.RFC3339)
+	})
+
+	h.Logger.Info("AcceptStaffInvite method has finished successfully")
+	h.render(c, http.StatusOK, m)
+}
+
+// FetchStaffKitchens godoc
+// @Summary Gets the user's staff kitchens
+// @Description Lists the kitchens the user is staff of or invited to
+// @Tags user
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.StaffMembers
+// @Failure 400 {object} string "Invalid user ID"
+// @Router /users/{id}/staff-kitchens [get]
+func (h *Handler) FetchStaffKitchens(c *gin.Context) {
+	h.Logger.Info("FetchStaffKitchens method is starting")
+
+	userID, err := pathUUID(c, "id", "user id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res := models.StaffMembers{Staff: []models.StaffMember{}}
+	for _, staff := range h.Storage.KitchenStaff.List() {
+		for _, m := range staff {
+			if m.UserId == userID {
+				res.Staff = append(res.Staff, m)
+			}
+		}
+	}
+	slices.SortFunc(res.Staff, func(a, b models.StaffMember) int {
+		return strings.Compare(a.InvitedAt, b.InvitedAt)
+	})
+
+	h.Logger.Info("FetchStaffKitchens method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// staffMember finds the user among the kitchen's staff and invitations.
+func (h *Handler) staffMember(kitchenID, userID string) (models.StaffMember, bool) {
+	staff, _ := h.Storage.KitchenStaff.Get(kitchenID)
+	i := slices.IndexFunc(staff, func(m models.StaffMember) bool {
+		return m.UserId == userID
+	})
+	if i < 0 {
+		return models.StaffMember{}, false
+	}
+	return staff[i], true
+}
+
+func (h *Handler) updateStaffMember(kitchenID, userID string, fn func(*models.StaffMember)) (models.StaffMember, bool) {
+	var (
+		res   models.StaffMember
+		found bool
+	)
+	h.Storage.KitchenStaff.Update(kitchenID, func(staff []models.StaffMember, _ bool) []models.StaffMember {
+		staff = slices.Clone(staff)
+		for i := range staff {
+			if staff[i].UserId == userID {
+				fn(&staff[i])
+				res, found = staff[i], true
+			}
+		}
+		return staff
+	})
+	return res, found
+}
+
+func (h *Handler) staffPath(c *gin.Context) (string, string, bool) {
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	var userID string
+	if err == nil {
+		userID, err = pathUUID(c, "user_id", "user id")
+	}
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return "", "", false
+	}
+	return kitchenID, userID, true
+}
+
+// staffPermissions checks the permissions, dropping duplicates.
+func staffPermissions(perms []string) ([]string, error) {
+	res := []string{}
+	for _, p := range perms {
+		if !slices.Contains(models.StaffPermissions, p) {
+			return nil, errors.Errorf("unknown permission %q", p)
+		}
+		if !slices.Contains(res, p) {
+			res = append(res, p)
+		}
+	}
+	if len(res) == 0 {
+		return nil, errors.New("at least one permission is required")
+	}
+	return res, nil
+}
+
+func (h *Handler) emailStaffInvite(to, kitchenName, lang string) {
+	if to == "" {
+		return
+	}
+	_, err := h.Mailer.Queue(to, lang, models.EmailStaffInvite, email.StaffInviteData{KitchenName: kitchenName})
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error emailing staff invitation").Error())
+	}
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/dish"
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// SetDishStock godoc
+// @Summary Sets dish stock
+// @Description Sets how many portions of the dish can be ordered per day (UTC). Portions sold today still count. The dish is marked sold out while none are left
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Param stock body models.NewDishStock true "Daily count"
+// @Success 200 {object} models.DishStock
+// @Failure 400 {object} string "Invalid dish ID or stock"
+// @Failure 403 {object} string "Not the kitchen's owner or menu staff"
+// @Failure 404 {object} string "Dish not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /dishes/{id}/stock [put]
+func (h *Handler) SetDishStock(c *gin.Context) {
+	h.Logger.Info("SetDishStock method is starting")
+
+	var data models.NewDishStock
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid stock").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if data.DailyCount < 0 {
+		er := "invalid stock: daily count must not be negative"
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	dish, ok := h.ownDish(c)
+	if !ok {
+		return
+	}
+
+	s := h.Stock.Set(models.DishStock{
+		DishId:     dish.Id,
+		DishName:   dish.Name,
+		KitchenId:  dish.KitchenId,
+		DailyCount: data.DailyCount,
+	}, time.Now())
+
+	if available := s.Remaining > 0; available != dish.Available {
+		ctx, cancel := callContext(c, defaultTimeout)
+		defer cancel()
+
+		if _, err := h.setDishAvailable(ctx, dish.Id, available); err != nil {
+			status, _ := errorStatus(err)
+			er := err.Error()
+			c.AbortWithStatusJSON(status,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	h.Logger.Info("SetDishStock method has finished successfully")
+	h.render(c, http.StatusOK, s)
+}
+
+// GetDishStock godoc
+// @Summary Gets dish stock
+// @Description Tells how many portions of the dish are left today
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Success 200 {object} models.DishStock
+// @Failure 400 {object} string "Invalid dish ID"
+// @Failure 404 {object} string "Dish stock is not limited"
+// @Router /dishes/{id}/stock [get]
+func (h *Handler) GetDishStock(c *gin.Context) {
+	h.Logger.Info("GetDishStock method is starting")
+
+	id, err := pathUUID(c, "id", "dish ID")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	s, ok := h.Stock.Get(id, time.Now())
+	if !ok {
+		er := "dish stock is not limited"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetDishStock method has finished successfully")
+	h.render(c, http.StatusOK, s)
+}
+
+// DeleteDishStock godoc
+// @Summary Removes dish stock
+// @Description Stops limiting the portions of the dish. A dish sold out by its stock is available again
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid dish ID"
+// @Failure 403 {object} string "Not the kitchen's owner or menu staff"
+// @Failure 404 {object} string "Dish or its stock not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /dishes/{id}/stock [delete]
+func (h *Handler) DeleteDishStock(c *gin.Context) {
+	h.Logger.Info("DeleteDishStock method is starting")
+
+	dish, ok := h.ownDish(c)
+	if !ok {
+		return
+	}
+
+	s, ok := h.Stock.Get(dish.Id, time.Now())
+	if !ok || !h.Stock.Delete(dish.Id) {
+		er := "dish stock is not limited"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if s.Remaining == 0 && !dish.Available {
+		ctx, cancel := callContext(c, defaultTimeout)
+		defer cancel()
+
+		if _, err := h.setDishAvailable(ctx, dish.Id, true); err != nil {
+			status, _ := errorStatus(err)
+			er := err.Error()
+			c.AbortWithStatusJSON(status,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	h.Logger.Info("DeleteDishStock method has finished successfully")
+	h.render(c, http.StatusOK, "Dish stock removed successfully")
+}
+
+// FetchKitchenStock godoc
+// @Summary Gets kitchen stock
+// @Description Lists today's stock of the kitchen's dishes that have one
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.DishStocks
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Not the kitchen's owner or menu staff"
+// @Router /kitchens/{id}/stock [get]
+func (h *Handler) FetchKitchenStock(c *gin.Context) {
+	h.Logger.Info("FetchKitchenStock method is starting")
+
+	id, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", id, models.StaffMenu); !ok {
+		return
+	}
+
+	res := models.DishStocks{Stocks: h.Stock.List(id, time.Now())}
+
+	h.Logger.Info("FetchKitchenStock method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// ownDish returns the dish of the path if the caller owns its kitchen,
+// manages its menu as staff, or is an admin.
+func (h *Handler) ownDish(c *gin.Context) (*pb.DishInfo, bool) {
+	id, err := pathUUID(c, "id", "dish ID")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return nil, false
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	dish, err := h.DishClient.Read(ctx, &pb.ID{Id: id})
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting dish").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return nil, false
+	}
+
+	if _, ok := h.accessRole(c, "", dish.KitchenId, models.StaffMenu); !ok {
+		return nil, false
+	}
+	return dish, true
+}
+
+// setDishAvailable marks the dish available or sold out in the dish
+// service and notifies the customers waiting for it.
+func (h *Handler) setDishAvailable(ctx context.Context, dishID string, available bool) (*pb.UpdatedData, error) {
+	// Update replaces name and price too, so they are carried over.
+	dish, err := h.DishClient.Read(ctx, &pb.ID{Id: dishID})
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting dish")
+	}
+
+	upd, err := h.DishClient.Update(ctx, &pb.NewData{
+		Id:        dishID,
+		Name:      dish.Name,
+		Price:     dish.Price,
+		Available: available,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error updating dish")
+	}
+	h.trackCatalog(models.EventDishUpdated, models.CatalogDish, upd.Id, upd.KitchenId, upd)
+	h.purgeDishes(upd.Id)
+	h.notifyDishAvailable(upd)
+	return upd, nil
+}
+
+// syncAvailability updates dishes whose stock changed outside a request.
+func (h *Handler) syncAvailability(dishIDs []string, available bool) {
+	for _, id := range dishIDs {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+		_, err := h.setDishAvailable(ctx, id, available)
+		cancel()
+
+		if err != nil {
+			h.Logger.Error(errors.Wrapf(err, "error syncing availability of dish %s", id).Error())
+		}
+	}
+}
+
+// restockDishes marks dishes that sold out yesterday available again.
+func (h *Handler) restockDishes(dishIDs []string) {
+	h.syncAvailability(dishIDs, true)
+}
+
+// orderQuantities sums the portions of each dish in the items.
+func orderQuantities(items []models.OrderItem) map[string]int32 {
+	res := make(map[string]int32)
+	for _, item := range items {
+		res[item.DishId] += item.Quantity
+	}
+	return res
+}
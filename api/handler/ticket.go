@@ -0,0 +1,378 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pbo "api-gateway/genproto/order"
+	"api-gateway/models"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// CreateTicket godoc
+// @Summary Opens a support ticket
+// @Description Opens a ticket against one of the caller's orders. Attachments are IDs of completed uploads with the attachment purpose
+// @Tags support
+// @Security ApiKeyAuth
+// @Param ticket body models.NewTicket true "Ticket info"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} string "Invalid ticket data"
+// @Failure 403 {object} string "Order belongs to another user"
+// @Failure 404 {object} string "Order not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /support/tickets [post]
+func (h *Handler) CreateTicket(c *gin.Context) {
+	h.Logger.Info("CreateTicket method is starting")
+
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewTicket
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid ticket data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	data.Subject = strings.TrimSpace(data.Subject)
+	err := h.validateAttachments(c, data.Attachments)
+	if err == nil && data.Subject == "" {
+		err = errors.New("subject is required")
+	}
+	if err == nil {
+		_, err = uuid.Parse(data.OrderId)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid ticket data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	order, err := h.OrderClient.GetOrderByID(ctx, &pbo.ID{Id: data.OrderId})
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting order").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if order.UserId != userID && role != models.RoleAdmin {
+		er := "order belongs to another user"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	t := models.Ticket{
+		Id:          uuid.NewString(),
+		OrderId:     order.Id,
+		KitchenId:   order.KitchenId,
+		UserId:      order.UserId,
+		Subject:     data.Subject,
+		Description: data.Description,
+		Status:      models.TicketOpen,
+		Attachments: nonNil(data.Attachments),
+		Messages:    []models.TicketMessage{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	h.Storage.Tickets.Set(t.Id, t)
+
+	h.Logger.Info("CreateTicket method has finished successfully")
+	h.render(c, http.StatusOK, t)
+}
+
+// FetchTickets godoc
+// @Summary Gets support tickets
+// @Description Lists the caller's tickets, the tickets of a kitchen they own, or any tickets for admins, newest first
+// @Tags support
+// @Security ApiKeyAuth
+// @Param kitchen_id query string false "Kitchen ID"
+// @Param order_id query string false "Order ID"
+// @Param status query string false "Ticket status" Enums(open, in_progress, resolved, closed)
+// @Success 200 {object} models.Tickets
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Kitchen belongs to another user"
+// @Router /support/tickets [get]
+func (h *Handler) FetchTickets(c *gin.Context) {
+	h.Logger.Info("FetchTickets method is starting")
+
+	userID, role, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	kitchenID := c.Query("kitchen_id")
+	if kitchenID != "" && role != models.RoleAdmin {
+		ctx, cancel := callContext(c, defaultTimeout)
+		defer cancel()
+
+		allowed, err := h.kitchenAccess(ctx, userID, kitchenID, models.StaffOrders)
+		if !allowed {
+			status, er := http.StatusForbidden, "kitchen belongs to another user"
+			if err != nil {
+				status, _ = errorStatus(err)
+				er = errors.Wrap(err, "error getting kitchen").Error()
+			}
+			c.AbortWithStatusJSON(status,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	res := models.Tickets{Tickets: []models.Ticket{}}
+	for _, t := range h.Storage.Tickets.List() {
+		switch {
+		case kitchenID != "" && t.KitchenId != kitchenID:
+		case kitchenID == "" && role != models.RoleAdmin && t.UserId != userID:
+		case c.Query("order_id") != "" && t.OrderId != c.Query("order_id"):
+		case c.Query("status") != "" && t.Status != c.Query("status"):
+		default:
+			res.Tickets = append(res.Tickets, t)
+		}
+	}
+	slices.SortFunc(res.Tickets, func(a, b models.Ticket) int {
+		return strings.Compare(b.CreatedAt, a.CreatedAt)
+	})
+
+	h.Logger.Info("FetchTickets method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetTicket godoc
+// @Summary Gets a support ticket
+// @Description Retrieves a ticket with its conversation
+// @Tags support
+// @Security ApiKeyAuth
+// @Param id path string true "Ticket ID"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} string "Invalid ticket ID"
+// @Failure 403 {object} string "No access to the ticket"
+// @Failure 404 {object} string "Ticket not found"
+// @Router /support/tickets/{id} [get]
+func (h *Handler) GetTicket(c *gin.Context) {
+	h.Logger.Info("GetTicket method is starting")
+
+	t, _, ok := h.findTicket(c)
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("GetTicket method has finished successfully")
+	h.render(c, http.StatusOK, t)
+}
+
+// ReplyToTicket godoc
+// @Summary Replies to a support ticket
+// @Description Adds a message to the ticket conversation. A reply from the kitchen or an admin moves an open ticket to in_progress
+// @Tags support
+// @Security ApiKeyAuth
+// @Param id path string true "Ticket ID"
+// @Param message body models.NewTicketMessage true "Message"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} string "Invalid ticket ID or message"
+// @Failure 403 {object} string "No access to the ticket"
+// @Failure 404 {object} string "Ticket not found"
+// @Failure 409 {object} string "Ticket is closed"
+// @Router /support/tickets/{id}/messages [post]
+func (h *Handler) ReplyToTicket(c *gin.Context) {
+	h.Logger.Info("ReplyToTicket method is starting")
+
+	t, role, ok := h.findTicket(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewTicketMessage
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid message").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	data.Body = strings.TrimSpace(data.Body)
+	err := h.validateAttachments(c, data.Attachments)
+	if err == nil && data.Body == "" && len(data.Attachments) == 0 {
+		err = errors.New("message is empty")
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid message").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if t.Status == models.TicketClosed {
+		er := "ticket is closed"
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	msg := models.TicketMessage{
+		Id:          uuid.NewString(),
+		AuthorId:    c.GetString(middleware.UserIDKey),
+		AuthorRole:  role,
+		Body:        data.Body,
+		Attachments: nonNil(data.Attachments),
+		CreatedAt:   now,
+	}
+	t = h.Storage.Tickets.Update(t.Id, func(t models.Ticket, _ bool) models.Ticket {
+		t.Messages = append(slices.Clone(t.Messages), msg)
+		if t.Status == models.TicketOpen && role != models.RoleCustomer {
+			t.Status = models.TicketInProgress
+		}
+		t.UpdatedAt = now
+		return t
+	})
+
+	h.Logger.Info("ReplyToTicket method has finished successfully")
+	h.render(c, http.StatusOK, t)
+}
+
+// ChangeTicketStatus godoc
+// @Summary Changes a ticket's status
+// @Description Moves a ticket along open, in_progress, resolved and closed. Customers may only close or reopen their tickets
+// @Tags support
+// @Security ApiKeyAuth
+// @Param id path string true "Ticket ID"
+// @Param status body models.TicketStatus true "New status"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} string "Invalid ticket ID or status"
+// @Failure 403 {object} string "No access to the ticket or the transition"
+// @Failure 404 {object} string "Ticket not found"
+// @Failure 409 {object} string "Transition is not allowed from the current status"
+// @Router /support/tickets/{id}/status [put]
+func (h *Handler) ChangeTicketStatus(c *gin.Context) {
+	h.Logger.Info("ChangeTicketStatus method is starting")
+
+	t, role, ok := h.findTicket(c)
+	if !ok {
+		return
+	}
+
+	var data models.TicketStatus
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid status").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, known := models.TicketTransitions[data.Status]; !known {
+		er := errors.Errorf("invalid status %q", data.Status).Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if role == models.RoleCustomer && data.Status != models.TicketClosed && data.Status != models.TicketOpen {
+		er := "customers can only close or reopen tickets"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if !slices.Contains(models.TicketTransitions[t.Status], data.Status) {
+		er := errors.Errorf("cannot change status from %s to %s", t.Status, data.Status).Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	t = h.Storage.Tickets.Update(t.Id, func(t models.Ticket, _ bool) models.Ticket {
+		t.Status = data.Status
+		t.UpdatedAt = now
+		return t
+	})
+
+	h.Logger.Info("ChangeTicketStatus method has finished successfully")
+	h.render(c, http.StatusOK, t)
+}
+
+// DeleteTicket godoc
+// @Summary Deletes a support ticket
+// @Description Removes a ticket. Only admins may delete tickets
+// @Tags support
+// @Security ApiKeyAuth
+// @Param id path string true "Ticket ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid ticket ID"
+// @Failure 403 {object} string "Admin role is required"
+// @Failure 404 {object} string "Ticket not found"
+// @Router /support/tickets/{id} [delete]
+func (h *Handler) DeleteTicket(c *gin.Context) {
+	h.Logger.Info("DeleteTicket method is starting")
+
+	t, role, ok := h.findTicket(c)
+	if !ok {
+		return
+	}
+
+	if role != models.RoleAdmin {
+		er := "Admin role is required"
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Storage.Tickets.Delete(t.Id)
+
+	h.Logger.Info("DeleteTicket method has finished successfully")
+	h.render(c, http.StatusOK, "Ticket deleted successfully")
+}
+
+// findTicket loads the ticket of the path and the role the caller has on
+// it.
+func (h *Handler) findTicket(c *gin.Context) (models.Ticket, string, bool) {
+	id, err := pathUUID(c, "id", "ticket id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Ticket{}, "", false
+	}
+
+	t, ok := h.Storage.Tickets.Get(id)
+	if !ok {
+		er := "ticket not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Ticket{}, "", false
+	}
+
+	role, ok := h.accessRole(c, t.UserId, t.KitchenId, models.StaffOrders)
+	return t, role, ok
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// timeZoneMetadata tells the services the zone of the kitchen a request
+// is about, in which they read its dates and hours.
+const timeZoneMetadata = "x-time-zone"
+
+// GetKitchenTimeZone godoc
+// @Summary Gets kitchen time zone
+// @Description Tells the IANA time zone the kitchen's working hours, statistics, earnings, discounts and meal plan deliveries are in. Default is set when the kitchen has not chosen one and is in the gateway's default zone
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.KitchenTimeZone
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Router /kitchens/{id}/time-zone [get]
+func (h *Handler) GetKitchenTimeZone(c *gin.Context) {
+	h.Logger.Info("GetKitchenTimeZone method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("GetKitchenTimeZone method has finished successfully")
+	h.render(c, http.StatusOK, h.TimeZones.Get(kitchenID))
+}
+
+// SetKitchenTimeZone godoc
+// @Summary Sets kitchen time zone
+// @Description Sets the IANA time zone, such as Asia/Tashkent, the kitchen's working hours, statistics, earnings, discounts and meal plan deliveries are read in. For the kitchen's owner and admins
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param time_zone body models.NewKitchenTimeZone true "Time zone"
+// @Success 200 {object} models.KitchenTimeZone
+// @Failure 400 {object} string "Invalid kitchen ID or time zone"
+// @Failure 403 {object} string "Not the kitchen's owner"
+// @Router /kitchens/{id}/time-zone [put]
+func (h *Handler) SetKitchenTimeZone(c *gin.Context) {
+	h.Logger.Info("SetKitchenTimeZone method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+	userID, _, _ := h.caller(c)
+
+	var data models.NewKitchenTimeZone
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid time zone").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	tz, err := h.TimeZones.Set(kitchenID, data.TimeZone, userID, time.Now())
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("SetKitchenTimeZone method has finished successfully")
+	h.render(c, http.StatusOK, tz)
+}
+
+// inZone returns midnight of t's date in loc.
+func inZone(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// inKitchenZone sends calls about a kitchen with its time zone as
+// x-time-zone metadata.
+func inKitchenZone[Req, Res any](h *Handler, kitchenID func(Req) string,
+	call func(ctx context.Context, in Req, opts ...grpc.CallOption) (Res, error),
+) func(ctx context.Context, in Req, opts ...grpc.CallOption) (Res, error) {
+	return func(ctx context.Context, in Req, opts ...grpc.CallOption) (Res, error) {
+		zone := h.TimeZones.Of(kitchenID(in)).String()
+		return call(metadata.AppendToOutgoingContext(ctx, timeZoneMetadata, zone), in, opts...)
+	}
+}
@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	pbd "api-gateway/genproto/dish"
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"api-gateway/pkg/cdn"
+	"api-gateway/pkg/seo"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	maxTranslatedName        = 100
+	maxTranslatedDescription = 2000
+)
+
+// SetKitchenTranslation godoc
+// @Summary Translates a kitchen
+// @Description Sets the kitchen's name and description in a locale, shown to users whose Accept-Language prefers it. An empty field leaves the kitchen's own. For the kitchen's owner and staff with the menu permission
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param locale path string true "uz, ru or en"
+// @Param translation body models.NewTranslation true "Translation"
+// @Success 200 {object} models.Translation
+// @Failure 400 {object} string "Invalid kitchen ID, locale or translation"
+// @Failure 403 {object} string "Not the kitchen's owner or menu staff"
+// @Router /kitchens/{id}/translations/{locale} [put]
+func (h *Handler) SetKitchenTranslation(c *gin.Context) {
+	h.Logger.Info("SetKitchenTranslation method is starting")
+
+	kitchenID, ok := h.ownKitchen(c)
+	if !ok {
+		return
+	}
+	t, ok := h.setTranslation(c, models.TranslationKitchen, kitchenID)
+	if !ok {
+		return
+	}
+	h.Edge.Purge(cdn.KitchenKey(kitchenID))
+
+	h.Logger.Info("SetKitchenTranslation method has finished successfully")
+	h.render(c, http.StatusOK, t)
+}
+
+// FetchKitchenTranslations godoc
+// @Summary Lists a kitchen's translations
+// @Description Lists the kitchen's name and description in every locale it was translated into. For the kitchen's owner and staff with the menu permission
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.Translations
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Not the kitchen's owner or menu staff"
+// @Router /kitchens/{id}/translations [get]
+func (h *Handler) FetchKitchenTranslations(c *gin.Context) {
+	h.Logger.Info("FetchKitchenTranslations method is starting")
+
+	kitchenID, ok := h.ownKitchen(c)
+	if !ok {
+		return
+	}
+	res := h.translations(models.TranslationKitchen, kitchenID)
+
+	h.Logger.Info("FetchKitchenTranslations method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// DeleteKitchenTranslation godoc
+// @Summary Deletes a kitchen's translation
+// @Description Removes the kitchen's translation into a locale, so its own name and description are shown again. For the kitchen's owner and staff with the menu permission
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param locale path string true "uz, ru or en"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Not the kitchen's owner or menu staff"
+// @Failure 404 {object} string "Translation not found"
+// @Router /kitchens/{id}/translations/{locale} [delete]
+func (h *Handler) DeleteKitchenTranslation(c *gin.Context) {
+	h.Logger.Info("DeleteKitchenTranslation method is starting")
+
+	kitchenID, ok := h.ownKitchen(c)
+	if !ok {
+		return
+	}
+	if !h.deleteTranslation(c, models.TranslationKitchen, kitchenID) {
+		return
+	}
+	h.Edge.Purge(cdn.KitchenKey(kitchenID))
+
+	h.Logger.Info("DeleteKitchenTranslation method has finished successfully")
+	h.render(c, http.StatusOK, "Translation deleted successfully")
+}
+
+// SetDishTranslation godoc
+// @Summary Translates a dish
+// @Description Sets the dish's name and description in a locale, shown to users whose Accept-Language prefers it. An empty field leaves the dish's own. For the kitchen's owner and staff with the menu permission
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Param locale path string true "uz, ru or en"
+// @Param translation body models.NewTranslation true "Translation"
+// @Success 200 {object} models.Translation
+// @Failure 400 {object} string "Invalid dish ID, locale or translation"
+// @Failure 403 {object} string "Not the kitchen's owner or menu staff"
+// @Failure 404 {object} string "Dish not found"
+// @Router /dishes/{id}/translations/{locale} [put]
+func (h *Handler) SetDishTranslation(c *gin.Context) {
+	h.Logger.Info("SetDishTranslation method is starting")
+
+	d, ok := h.ownDish(c)
+	if !ok {
+		return
+	}
+	t, ok := h.setTranslation(c, models.TranslationDish, d.Id)
+	if !ok {
+		return
+	}
+	h.purgeDishes(d.Id)
+
+	h.Logger.Info("SetDishTranslation method has finished successfully")
+	h.render(c, http.StatusOK, t)
+}
+
+// FetchDishTranslations godoc
+// @Summary Lists a dish's translations
+// @Description Lists the dish's name and description in every locale it was translated into. For the kitchen's owner and staff with the menu permission
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Success 200 {object} models.Translations
+// @Failure 400 {object} string "Invalid dish ID"
+// @Failure 403 {object} string "Not the kitchen's owner or menu staff"
+// @Failure 404 {object} string "Dish not found"
+// @Router /dishes/{id}/translations [get]
+func (h *Handler) FetchDishTranslations(c *gin.Context) {
+	h.Logger.Info("FetchDishTranslations method is starting")
+
+	d, ok := h.ownDish(c)
+	if !ok {
+		return
+	}
+	res := h.translations(models.TranslationDish, d.Id)
+
+	h.Logger.Info("FetchDishTranslations method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// DeleteDishTranslation godoc
+// @Summary Deletes a dish's translation
+// @Description Removes the dish's translation into a locale, so its own name and description are shown again. For the kitchen's owner and staff with the menu permission
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Param locale path string true "uz, ru or en"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid dish ID"
+// @Failure 403 {object} string "Not the kitchen's owner or menu staff"
+// @Failure 404 {object} string "Dish or translation not found"
+// @Router /dishes/{id}/translations/{locale} [delete]
+func (h *Handler) DeleteDishTranslation(c *gin.Context) {
+	h.Logger.Info("DeleteDishTranslation method is starting")
+
+	d, ok := h.ownDish(c)
+	if !ok {
+		return
+	}
+	if !h.deleteTranslation(c, models.TranslationDish, d.Id) {
+		return
+	}
+	h.purgeDishes(d.Id)
+
+	h.Logger.Info("DeleteDishTranslation method has finished successfully")
+	h.render(c, http.StatusOK, "Translation deleted successfully")
+}
+
+// ownKitchen returns the kitchen ID of the path if the caller owns the
+// kitchen, manages its menu as staff, or is an admin.
+func (h *Handler) ownKitchen(c *gin.Context) (string, bool) {
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return "", false
+	}
+
+	_, ok := h.accessRole(c, "", kitchenID, models.StaffMenu)
+	return kitchenID, ok
+}
+
+func (h *Handler) setTranslation(c *gin.Context, kind, id string) (models.Translation, bool) {
+	userID, _, _ := h.caller(c)
+
+	locale := c.Param("locale")
+	var data models.NewTranslation
+	err := c.ShouldBindJSON(&data)
+	if err == nil {
+		err = validateTranslation(locale, &data)
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid translation").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Translation{}, false
+	}
+
+	t := models.Translation{
+		Kind:           kind,
+		EntityId:       id,
+		Locale:         locale,
+		NewTranslation: data,
+		UpdatedBy:      userID,
+		UpdatedAt:      time.Now().Format(time.RFC3339),
+	}
+	h.Storage.Translations.Set(translationKey(kind, id, locale), t)
+	return t, true
+}
+
+func (h *Handler) translations(kind, id string) models.Translations {
+	res := models.Translations{Translations: []models.Translation{}}
+	for _, locale := range models.Locales {
+		if t, ok := h.Storage.Translations.Get(translationKey(kind, id, locale)); ok {
+			res.Translations = append(res.Translations, t)
+		}
+	}
+	return res
+}
+
+func (h *Handler) deleteTranslation(c *gin.Context, kind, id string) bool {
+	if !h.Storage.Translations.Delete(translationKey(kind, id, c.Param("locale"))) {
+		er := "translation not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return false
+	}
+	return true
+}
+
+func validateTranslation(locale string, data *models.NewTranslation) error {
+	if !slices.Contains(models.Locales, locale) {
+		return errors.Errorf("locale must be one of %s", strings.Join(models.Locales, ", "))
+	}
+	data.Name = strings.TrimSpace(data.Name)
+	data.Description = strings.TrimSpace(data.Description)
+	switch {
+	case data.Name == "" && data.Description == "":
+		return errors.New("name or description is required")
+	case utf8.RuneCountInString(data.Name) > maxTranslatedName:
+		return errors.Errorf("name must be at most %d characters", maxTranslatedName)
+	case utf8.RuneCountInString(data.Description) > maxTranslatedDescription:
+		return errors.Errorf("description must be at most %d characters", maxTranslatedDescription)
+	}
+	return nil
+}
+
+func translationKey(kind, id, locale string) string {
+	return kind + "|" + id + "|" + locale
+}
+
+// localize puts the translations into the request's locale of the
+// kitchens or dishes of res in place of their own names and descriptions.
+// Those without one are left as the services sent them.
+func (h *Handler) localize(c *gin.Context, res proto.Message) {
+	h.translate(res, c.GetString(middleware.LocaleKey))
+}
+
+// localizeMenu translates a menu, which is shared by the site's cache, so
+// the kitchen and the dishes are copied first.
+func (h *Handler) localizeMenu(menu seo.Menu, locale string) seo.Menu {
+	menu.Kitchen = proto.Clone(menu.Kitchen).(*pbk.Info)
+	h.translate(menu.Kitchen, locale)
+	categories := make([]seo.MenuCategory, len(menu.Categories))
+	for i, cat := range menu.Categories {
+		categories[i] = seo.MenuCategory{Name: cat.Name, Dishes: make([]*pbd.DishInfo, len(cat.Dishes))}
+		for j, d := range cat.Dishes {
+			categories[i].Dishes[j] = proto.Clone(d).(*pbd.DishInfo)
+			h.translate(categories[i].Dishes[j], locale)
+		}
+	}
+	menu.Categories = categories
+	return menu
+}
+
+func (h *Handler) translate(res proto.Message, locale string) {
+	if locale == "" {
+		return
+	}
+	apply := func(kind, id string, name, description *string) {
+		t, ok := h.Storage.Translations.Get(translationKey(kind, id, locale))
+		if !ok {
+			return
+		}
+		if t.Name != "" {
+			*name = t.Name
+		}
+		if t.Description != "" && description != nil {
+			*description = t.Description
+		}
+	}
+
+	switch res := res.(type) {
+	case *pbk.Info:
+		apply(models.TranslationKitchen, res.Id, &res.Name, &res.Description)
+	case *pbk.Kitchens:
+		for _, k := range res.Kitchens {
+			apply(models.TranslationKitchen, k.Id, &k.Name, nil)
+		}
+	case *pbd.DishInfo:
+		apply(models.TranslationDish, res.Id, &res.Name, &res.Description)
+	case *pbd.Dishes:
+		for _, d := range res.Dishes {
+			apply(models.TranslationDish, d.Id, &d.Name, nil)
+		}
+	}
+}
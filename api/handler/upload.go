@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"api-gateway/api/middleware"
+	"api-gateway/models"
+	"api-gateway/pkg/upload"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// CreateUpload godoc
+// @Summary Starts an upload
+// @Description Opens a resumable upload for a dish image or a bulk import file. Chunks are then sent with PATCH /uploads/{id}. Only the uploader and admins may change, delete or attach the upload
+// @Tags upload
+// @Security ApiKeyAuth
+// @Param upload body models.NewUpload true "Upload info"
+// @Success 200 {object} models.Upload
+// @Failure 400 {object} string "Invalid upload data"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /uploads [post]
+func (h *Handler) CreateUpload(c *gin.Context) {
+	h.Logger.Info("CreateUpload method is starting")
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewUpload
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid upload data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	u, err := h.Uploads.Create(userID, data)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Cause(err) == upload.ErrInvalid {
+			status = http.StatusBadRequest
+		}
+		er := errors.Wrap(err, "error creating upload").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	c.Header("Location", c.Request.URL.Path+"/"+u.Id)
+	h.Logger.Info("CreateUpload method has finished successfully")
+	h.render(c, http.StatusOK, u)
+}
+
+// GetUpload godoc
+// @Summary Gets an upload
+// @Description Reports upload progress; offset is where the next chunk has to start. HEAD returns the same in Upload-Offset and Upload-Length headers
+// @Tags upload
+// @Security ApiKeyAuth
+// @Param id path string true "Upload ID"
+// @Success 200 {object} models.Upload
+// @Failure 400 {object} string "Invalid upload ID"
+// @Failure 403 {object} string "Not the uploader"
+// @Failure 404 {object} string "Upload not found"
+// @Router /uploads/{id} [get]
+func (h *Handler) GetUpload(c *gin.Context) {
+	h.Logger.Info("GetUpload method is starting")
+
+	u, ok := h.findUpload(c, false)
+	if !ok {
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(u.Size, 10))
+	c.Header("Cache-Control", "no-store")
+
+	h.Logger.Info("GetUpload method has finished successfully")
+	if c.Request.Method == http.MethodHead {
+		c.Status(http.StatusOK)
+		return
+	}
+	h.render(c, http.StatusOK, u)
+}
+
+// UploadChunk godoc
+// @Summary Uploads a chunk
+// @Description Appends the request body at the position given by Content-Range (bytes start-end/size). A chunk must start at the current offset; otherwise 409 returns the offset to resume from
+// @Tags upload
+// @Security ApiKeyAuth
+// @Accept octet-stream
+// @Param id path string true "Upload ID"
+// @Param Content-Range header string true "bytes start-end/size"
+// @Success 200 {object} models.Upload
+// @Failure 400 {object} string "Invalid upload ID or Content-Range"
+// @Failure 403 {object} string "Not the uploader"
+// @Failure 404 {object} string "Upload not found"
+// @Failure 409 {object} string "Chunk does not start at the current offset"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /uploads/{id} [patch]
+func (h *Handler) UploadChunk(c *gin.Context) {
+	h.Logger.Info("UploadChunk method is starting")
+
+	u, ok := h.findUpload(c, false)
+	if !ok {
+		return
+	}
+
+	var start, end, size int64
+	_, err := fmt.Sscanf(c.GetHeader("Content-Range"), "bytes %d-%d/%d", &start, &end, &size)
+	if err != nil || start < 0 || end < start || size != u.Size {
+		er := errors.New("invalid Content-Range header").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	u, err = h.Uploads.Append(u.Id, start, io.LimitReader(c.Request.Body, end-start+1))
+	c.Header("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch errors.Cause(err) {
+		case upload.ErrOffsetMismatch:
+			status = http.StatusConflict
+		case upload.ErrInvalid:
+			status = http.StatusBadRequest
+		}
+		er := errors.Wrap(err, "error uploading chunk").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er, "offset": u.Offset})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("UploadChunk method has finished successfully")
+	h.render(c, http.StatusOK, u)
+}
+
+// DownloadUpload godoc
+// @Summary Downloads an upload
+// @Description Serves a completed upload. Range requests are supported, so interrupted downloads can be resumed. For the uploader, admins and those with access to the ticket, refund request or kitchen verification it is attached to; inspection documents are for everyone
+// @Tags upload
+// @Security ApiKeyAuth
+// @Produce octet-stream
+// @Param id path string true "Upload ID"
+// @Success 200 {file} file
+// @Failure 400 {object} string "Invalid upload ID"
+// @Failure 403 {object} string "No access to the upload"
+// @Failure 404 {object} string "Upload not found"
+// @Failure 409 {object} string "Upload is not completed"
+// @Router /uploads/{id}/content [get]
+func (h *Handler) DownloadUpload(c *gin.Context) {
+	h.Logger.Info("DownloadUpload method is starting")
+
+	u, ok := h.findUpload(c, true)
+	if !ok {
+		return
+	}
+
+	if u.Status != models.UploadCompleted {
+		er := errors.New("upload is not completed").Error()
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er, "offset": u.Offset})
+		h.Logger.Error(er)
+		return
+	}
+
+	f, err := h.Uploads.Open(u.Id)
+	if err != nil {
+		er := errors.Wrap(err, "error opening upload").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	defer f.Close()
+
+	modified, _ := time.Parse(time.RFC3339, u.UpdatedAt)
+	c.Header("Content-Type", u.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", u.Filename))
+
+	h.Logger.Info("DownloadUpload method has finished successfully")
+	http.ServeContent(c.Writer, c.Request, u.Filename, modified, f)
+}
+
+// DeleteUpload godoc
+// @Summary Deletes an upload
+// @Description Cancels an upload or removes a completed one
+// @Tags upload
+// @Security ApiKeyAuth
+// @Param id path string true "Upload ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid upload ID"
+// @Failure 403 {object} string "Not the uploader"
+// @Failure 404 {object} string "Upload not found"
+// @Router /uploads/{id} [delete]
+func (h *Handler) DeleteUpload(c *gin.Context) {
+	h.Logger.Info("DeleteUpload method is starting")
+
+	u, ok := h.findUpload(c, false)
+	if !ok {
+		return
+	}
+
+	if err := h.Uploads.Delete(u.Id); err != nil {
+		er := errors.Wrap(err, "error deleting upload").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("DeleteUpload method has finished successfully")
+	h.render(c, http.StatusOK, "Upload deleted successfully")
+}
+
+// findUpload loads the upload of the path for its uploader or an admin
+// and, when attached is set, for those with access to a record it is
+// attached to.
+func (h *Handler) findUpload(c *gin.Context, attached bool) (models.Upload, bool) {
+	id, err := pathUUID(c, "id", "upload id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Upload{}, false
+	}
+
+	u, err := h.ownUpload(c, id)
+	if err == errNotUploader && attached {
+		if u, err = h.Uploads.Get(id); err == nil && !h.attachedFor(c, u) {
+			err = errNotUploader
+		}
+	}
+	if err != nil {
+		status := http.StatusNotFound
+		if err == errNotUploader {
+			status = http.StatusForbidden
+		}
+		er := err.Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Upload{}, false
+	}
+
+	return u, true
+}
+
+var errNotUploader = errors.New("upload belongs to another user")
+
+// ownUpload returns the upload if the caller uploaded it or is an admin.
+func (h *Handler) ownUpload(c *gin.Context, id string) (models.Upload, error) {
+	u, err := h.Uploads.Get(id)
+	if err != nil {
+		return u, err
+	}
+	userID, role := c.GetString(middleware.UserIDKey), c.GetString(middleware.RoleKey)
+	if role != models.RoleAdmin && (userID == "" || u.UserId != userID) {
+		return models.Upload{}, errNotUploader
+	}
+	return u, nil
+}
+
+// attachedFor tells whether the upload is attached to a record the
+// caller has access to: a ticket or refund request of their order or
+// kitchen, their kitchen's verification, or any inspection, since
+// inspections are shown with the kitchen.
+func (h *Handler) attachedFor(c *gin.Context, u models.Upload) bool {
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	allowed := func(customerID, kitchenID, permission string) bool {
+		ok, err := h.canAccess(ctx, c, customerID, kitchenID, permission)
+		if err != nil {
+			h.Logger.Error(errors.Wrap(err, "error getting kitchen").Error())
+		}
+		return ok
+	}
+
+	switch u.Purpose {
+	case models.UploadAttachment:
+		for _, t := range h.Storage.Tickets.List() {
+			attached := slices.Contains(t.Attachments, u.Id) ||
+				slices.ContainsFunc(t.Messages, func(m models.TicketMessage) bool {
+					return slices.Contains(m.Attachments, u.Id)
+				})
+			if attached && allowed(t.UserId, t.KitchenId, models.StaffOrders) {
+				return true
+			}
+		}
+		for _, r := range h.Storage.RefundRequests.List() {
+			if slices.Contains(r.Evidence, u.Id) && allowed(r.UserId, r.KitchenId, models.StaffOrders) {
+				return true
+			}
+		}
+	case models.UploadKitchenDoc:
+		for _, v := range h.Storage.Verifications.List() {
+			attached := slices.ContainsFunc(v.Documents, func(d models.KitchenDocument) bool {
+				return d.UploadId == u.Id
+			})
+			if attached && allowed("", v.KitchenId, "") {
+				return true
+			}
+		}
+	case models.UploadInspection:
+		for _, in := range h.Storage.Inspections.List() {
+			if slices.ContainsFunc(in.Documents, func(d models.InspectionDocument) bool {
+				return d.UploadId == u.Id
+			}) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/version"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// defaultUsageDays is how many days an API usage report covers by default.
+const defaultUsageDays = 30
+
+// GetAPIUsage godoc
+// @Summary Gets API usage
+// @Description Tells how often each route was called and how it was answered, the most called first, with a breakdown by app platform and version from the X-Platform and X-App-Version headers. With below, only the calls of app versions older than it are counted, to tell which routes old apps still rely on. Covers the last 30 days by default
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param from query string false "First day, as YYYY-MM-DD"
+// @Param to query string false "Last day, as YYYY-MM-DD"
+// @Param route query string false "Route as registered, such as /local-eats/orders/:id"
+// @Param platform query string false "ios, android, web or other"
+// @Param below query string false "Only app versions older than this one, such as 2.0"
+// @Success 200 {object} models.APIUsageReport
+// @Failure 400 {object} string "Invalid dates"
+// @Router /admin/api-usage [get]
+func (h *Handler) GetAPIUsage(c *gin.Context) {
+	h.Logger.Info("GetAPIUsage method is starting")
+
+	f, err := usageFilter(c)
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	res := h.Usage.Report(f)
+
+	h.Logger.Info("GetAPIUsage method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetAppVersions godoc
+// @Summary Gets app version usage
+// @Description Tells how often each app version called the gateway and how many of its calls were answered with 426 Upgrade Required, by platform and newest first, with the oldest versions served. Covers the last 30 days by default
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param from query string false "First day, as YYYY-MM-DD"
+// @Param to query string false "Last day, as YYYY-MM-DD"
+// @Param platform query string false "ios, android, web or other"
+// @Success 200 {object} models.AppVersionReport
+// @Failure 400 {object} string "Invalid dates"
+// @Router /admin/app-versions [get]
+func (h *Handler) GetAppVersions(c *gin.Context) {
+	h.Logger.Info("GetAppVersions method is starting")
+
+	f, err := usageFilter(c)
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+	f.Route, f.Below = "", ""
+
+	mins := h.Settings.AppVersions()
+	res := models.AppVersionReport{From: f.From, To: f.To, Minimums: mins, Versions: []models.AppVersionUsage{}}
+	for _, v := range h.Usage.Versions(f) {
+		min := mins.Minimum(v.Platform)
+		res.Versions = append(res.Versions, models.AppVersionUsage{
+			VersionUsage: v,
+			Supported:    min == "" || v.Version == models.AppUnknown || version.Compare(v.Version, min) >= 0,
+		})
+	}
+
+	h.Logger.Info("GetAppVersions method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// usageFilter reads the filter of a usage report from the query, the last
+// defaultUsageDays days unless from or to are given.
+func usageFilter(c *gin.Context) (models.UsageFilter, error) {
+	now := time.Now().UTC()
+	f := models.UsageFilter{
+		From:     now.AddDate(0, 0, 1-defaultUsageDays).Format(time.DateOnly),
+		To:       now.Format(time.DateOnly),
+		Route:    c.Query("route"),
+		Platform: c.Query("platform"),
+		Below:    c.Query("below"),
+	}
+	var err error
+	if c.Query("from") != "" {
+		f.From, err = dateQuery(c, "from", "from date")
+	}
+	if err == nil && c.Query("to") != "" {
+		f.To, err = dateQuery(c, "to", "to date")
+	}
+	if err == nil && f.From > f.To {
+		err = errors.New("invalid date range: from is after to")
+	}
+	return f, err
+}
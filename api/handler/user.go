@@ -2,13 +2,8 @@ package handler
 
 import (
 	pb "api-gateway/genproto/user"
-	"context"
-	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-	"github.com/pkg/errors"
 )
 
 // GetUser godoc
@@ -22,32 +17,15 @@ import (
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /users/{id} [get]
 func (h *Handler) GetUser(c *gin.Context) {
-	h.Logger.Info("GetUser method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid user id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	profile, err := h.UserClient.GetProfile(ctx, &pb.ID{Id: id})
-	if err != nil {
-		er := errors.Wrap(err, "error getting user").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("GetUser method has finished successfully")
-	c.JSON(http.StatusOK, profile)
+	serve(h, c, Proxy[*pb.ID, *pb.Profile]{
+		Name: "GetUser",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "user id")
+			return &pb.ID{Id: id}, err
+		},
+		Call:  h.UserClient.GetProfile,
+		Error: "error getting user",
+	})
 }
 
 // UpdateUser godoc
@@ -62,46 +40,29 @@ func (h *Handler) GetUser(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /users/{id} [put]
 func (h *Handler) UpdateUser(c *gin.Context) {
-	h.Logger.Info("UpdateUser method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid user id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	var newData pb.NewInfoNoID
-	if err := c.ShouldBindJSON(&newData); err != nil {
-		er := errors.Wrap(err, "invalid user data").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	upd, err := h.UserClient.UpdateProfile(ctx, &pb.NewInfo{
-		Id:          id,
-		FullName:    newData.FullName,
-		Address:     newData.Address,
-		PhoneNumber: newData.PhoneNumber,
+	serve(h, c, Proxy[*pb.NewInfo, *pb.Details]{
+		Name: "UpdateUser",
+		Bind: func(c *gin.Context) (*pb.NewInfo, error) {
+			id, err := pathUUID(c, "id", "user id")
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := bindJSON[pb.NewInfoNoID](c, "user data")
+			if err != nil {
+				return nil, err
+			}
+
+			return &pb.NewInfo{
+				Id:          id,
+				FullName:    data.FullName,
+				Address:     data.Address,
+				PhoneNumber: data.PhoneNumber,
+			}, nil
+		},
+		Call:  h.UserClient.UpdateProfile,
+		Error: "error updating user",
 	})
-	if err != nil {
-		er := errors.Wrap(err, "error updating user").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("UpdateUser method has finished successfully")
-	c.JSON(http.StatusOK, upd)
 }
 
 // DeleteUser godoc
@@ -115,30 +76,14 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /users/{id} [delete]
 func (h *Handler) DeleteUser(c *gin.Context) {
-	h.Logger.Info("DeleteUser method is starting")
-
-	id := c.Param("id")
-	_, err := uuid.Parse(id)
-	if err != nil {
-		er := errors.Wrap(err, "invalid user id").Error()
-		c.AbortWithStatusJSON(http.StatusBadRequest,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(c, time.Second*5)
-	defer cancel()
-
-	_, err = h.UserClient.DeleteProfile(ctx, &pb.ID{Id: id})
-	if err != nil {
-		er := errors.Wrap(err, "error deleting user").Error()
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			gin.H{"error": er})
-		h.Logger.Error(er)
-		return
-	}
-
-	h.Logger.Info("DeleteUser method has finished successfully")
-	c.JSON(http.StatusOK, "User deleted successfully")
+	serve(h, c, Proxy[*pb.ID, *pb.Void]{
+		Name: "DeleteUser",
+		Bind: func(c *gin.Context) (*pb.ID, error) {
+			id, err := pathUUID(c, "id", "user id")
+			return &pb.ID{Id: id}, err
+		},
+		Call:    h.UserClient.DeleteProfile,
+		Error:   "error deleting user",
+		Message: "User deleted successfully",
+	})
 }
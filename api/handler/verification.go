@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/cdn"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// SubmitKitchenDocument godoc
+// @Summary Submits a kitchen document
+// @Description Adds a licence or health certificate, uploaded with the kitchen_document purpose, to the kitchen's verification. An unverified kitchen then waits for an admin's review
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param document body models.NewKitchenDocument true "Document info"
+// @Success 200 {object} models.KitchenVerification
+// @Failure 400 {object} string "Invalid kitchen ID or document"
+// @Failure 403 {object} string "Not the kitchen's owner"
+// @Router /kitchens/{id}/documents [post]
+func (h *Handler) SubmitKitchenDocument(c *gin.Context) {
+	h.Logger.Info("SubmitKitchenDocument method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+	userID, _, _ := h.caller(c)
+
+	var data models.NewKitchenDocument
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid document").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	doc, err := h.kitchenDocument(c, data)
+	if err != nil {
+		er := errors.Wrap(err, "invalid document").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	doc.Id = uuid.NewString()
+	doc.KitchenId = kitchenID
+	doc.UploadedBy = userID
+	doc.CreatedAt = now
+
+	v := h.Storage.Verifications.Update(kitchenID, func(v models.KitchenVerification, ok bool) models.KitchenVerification {
+		if !ok {
+			v = models.KitchenVerification{KitchenId: kitchenID}
+		}
+		// A verified kitchen keeps its badge while it renews documents.
+		if v.Status != models.VerificationVerified {
+			v.Status = models.VerificationPending
+			v.SubmittedAt = now
+		}
+		v.Documents = append(slices.Clone(v.Documents), doc)
+		return v
+	})
+
+	h.Logger.Info("SubmitKitchenDocument method has finished successfully")
+	h.render(c, http.StatusOK, v)
+}
+
+// GetKitchenVerification godoc
+// @Summary Gets kitchen verification
+// @Description Retrieves the kitchen's verification status and submitted documents
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.KitchenVerification
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Not the kitchen's owner"
+// @Router /kitchens/{id}/verification [get]
+func (h *Handler) GetKitchenVerification(c *gin.Context) {
+	h.Logger.Info("GetKitchenVerification method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+
+	v, ok := h.Storage.Verifications.Get(kitchenID)
+	if !ok {
+		v = models.KitchenVerification{
+			KitchenId: kitchenID,
+			Status:    models.VerificationNone,
+			Documents: []models.KitchenDocument{},
+		}
+	}
+
+	h.Logger.Info("GetKitchenVerification method has finished successfully")
+	h.render(c, http.StatusOK, v)
+}
+
+// FetchVerifications godoc
+// @Summary Gets kitchen verifications
+// @Description Lists kitchens that submitted documents, oldest submission first, optionally by status
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param status query string false "pending, verified or rejected"
+// @Success 200 {object} models.KitchenVerifications
+// @Router /admin/verifications [get]
+func (h *Handler) FetchVerifications(c *gin.Context) {
+	h.Logger.Info("FetchVerifications method is starting")
+
+	status := c.Query("status")
+	res := models.KitchenVerifications{Verifications: []models.KitchenVerification{}}
+	for _, v := range h.Storage.Verifications.List() {
+		if status == "" || v.Status == status {
+			res.Verifications = append(res.Verifications, v)
+		}
+	}
+	slices.SortFunc(res.Verifications, func(a, b models.KitchenVerification) int {
+		return strings.Compare(a.SubmittedAt, b.SubmittedAt)
+	})
+
+	h.Logger.Info("FetchVerifications method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// ReviewVerification godoc
+// @Summary Reviews a kitchen verification
+// @Description Grants the verified badge to a kitchen, or refuses or revokes it. The kitchen owner is emailed the decision
+// @Tags kitchen
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param decision body models.VerificationDecision true "Decision"
+// @Success 200 {object} models.KitchenVerification
+// @Failure 400 {object} string "Invalid kitchen ID or decision"
+// @Failure 404 {object} string "Kitchen submitted no documents"
+// @Router /admin/verifications/{id} [put]
+func (h *Handler) ReviewVerification(c *gin.Context) {
+	h.Logger.Info("ReviewVerification method is starting")
+
+	kitchenID, err := pathUUID(c, "id", "kitchen id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	userID, _, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	var data models.VerificationDecision
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid decision").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if data.Status != models.VerificationVerified && data.Status != models.VerificationRejected {
+		er := errors.Errorf("invalid decision: status must be %s or %s",
+			models.VerificationVerified, models.VerificationRejected).Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.Storage.Verifications.Get(kitchenID); !ok {
+		er := "kitchen submitted no documents"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	v := h.Storage.Verifications.Update(kitchenID, func(v models.KitchenVerification, _ bool) models.KitchenVerification {
+		v.Status = data.Status
+		v.Note = data.Note
+		v.ReviewedBy = userID
+		v.ReviewedAt = time.Now().Format(time.RFC3339)
+		return v
+	})
+	h.Edge.Purge(cdn.KitchenKey(kitchenID))
+	go h.emailVerification(v)
+
+	h.Logger.Info("ReviewVerification method has finished successfully")
+	h.render(c, http.StatusOK, v)
+}
+
+// kitchenDocument checks the submitted document and its upload.
+func (h *Handler) kitchenDocument(c *gin.Context, data models.NewKitchenDocument) (models.KitchenDocument, error) {
+	if data.Type != models.KitchenDocLicense && data.Type != models.KitchenDocHealthCertificate {
+		return models.KitchenDocument{}, errors.Errorf("type must be %s or %s",
+			models.KitchenDocLicense, models.KitchenDocHealthCertificate)
+	}
+
+	if data.ExpiresAt != "" {
+		expires, err := time.Parse("2006-01-02", data.ExpiresAt)
+		if err != nil {
+			return models.KitchenDocument{}, errors.Wrap(err, "invalid expiry date")
+		}
+		if expires.Before(time.Now().Truncate(24 * time.Hour)) {
+			return models.KitchenDocument{}, errors.New("document has expired")
+		}
+	}
+
+	u, err := h.ownUpload(c, data.UploadId)
+	if err != nil {
+		return models.KitchenDocument{}, errors.Wrapf(err, "upload %s", data.UploadId)
+	}
+	if u.Purpose != models.UploadKitchenDoc || u.Status != models.UploadCompleted {
+		return models.KitchenDocument{}, errors.Errorf("upload %s must be a completed %s upload",
+			data.UploadId, models.UploadKitchenDoc)
+	}
+
+	return models.KitchenDocument{
+		UploadId:  u.Id,
+		Type:      data.Type,
+		Filename:  u.Filename,
+		ExpiresAt: data.ExpiresAt,
+	}, nil
+}
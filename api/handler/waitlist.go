@@ -0,0 +1,216 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/dish"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/email"
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// NotifyMe godoc
+// @Summary Waits for a sold-out dish
+// @Description Notifies the caller by push, email or both once the dish is available again. Asking again replaces the channels
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Param alert body models.NewDishAlert false "Notification channels"
+// @Success 200 {object} models.DishAlert
+// @Failure 400 {object} string "Invalid dish ID or channels"
+// @Failure 403 {object} string "Token has no user id"
+// @Failure 404 {object} string "Dish not found"
+// @Failure 409 {object} string "Dish is available"
+// @Router /dishes/{id}/notify-me [post]
+func (h *Handler) NotifyMe(c *gin.Context) {
+	h.Logger.Info("NotifyMe method is starting")
+
+	userID, ok := h.user(c)
+	if !ok {
+		return
+	}
+
+	dishID, err := pathUUID(c, "id", "dish ID")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	var data models.NewDishAlert
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&data); err != nil {
+			er := errors.Wrap(err, "invalid channels").Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				gin.H{"error": er})
+			h.Logger.Error(er)
+			return
+		}
+	}
+
+	channels, err := h.alertChannels(data.Channels)
+	if err != nil {
+		er := errors.Wrap(err, "invalid channels").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+
+	dish, err := h.DishClient.Read(ctx, &pb.ID{Id: dishID})
+	if err != nil {
+		status, _ := errorStatus(err)
+		er := errors.Wrap(err, "error getting dish").Error()
+		c.AbortWithStatusJSON(status,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if dish.Available {
+		er := "dish is available"
+		c.AbortWithStatusJSON(http.StatusConflict,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	a := models.DishAlert{
+		UserId:    userID,
+		DishId:    dishID,
+		Channels:  channels,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	h.Storage.DishAlerts.Set(dishAlertKey(dishID, userID), a)
+
+	h.Logger.Info("NotifyMe method has finished successfully")
+	h.render(c, http.StatusOK, a)
+}
+
+// CancelNotifyMe godoc
+// @Summary Stops waiting for a dish
+// @Description Cancels the caller's notification for the dish
+// @Tags dish
+// @Security ApiKeyAuth
+// @Param id path string true "Dish ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid dish ID"
+// @Failure 403 {object} string "Token has no user id"
+// @Failure 404 {object} string "Not waiting for the dish"
+// @Router /dishes/{id}/notify-me [delete]
+func (h *Handler) CancelNotifyMe(c *gin.Context) {
+	h.Logger.Info("CancelNotifyMe method is starting")
+
+	userID, ok := h.user(c)
+	if !ok {
+		return
+	}
+
+	dishID, err := pathUUID(c, "id", "dish ID")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if !h.Storage.DishAlerts.Delete(dishAlertKey(dishID, userID)) {
+		er := "not waiting for the dish"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	h.Logger.Info("CancelNotifyMe method has finished successfully")
+	h.render(c, http.StatusOK, "Notification canceled successfully")
+}
+
+// notifyDishAvailable tells everyone waiting for the dish that it is
+// available, once: each alert is removed as it is sent. Channels the
+// user turned promos off for are skipped.
+func (h *Handler) notifyDishAvailable(dish *pb.UpdatedData) {
+	if !dish.Available {
+		return
+	}
+
+	for _, a := range h.Storage.DishAlerts.List() {
+		if a.DishId != dish.Id || !h.Storage.DishAlerts.Delete(dishAlertKey(a.DishId, a.UserId)) {
+			continue
+		}
+
+		channels := slices.DeleteFunc(slices.Clone(a.Channels), func(ch string) bool {
+			return !h.notifies(a.UserId, models.NotifyPromos, ch)
+		})
+		if len(channels) == 0 {
+			continue
+		}
+
+		h.Events.Emit(models.EventDishAvailable, a.UserId, models.DishAvailable{
+			UserId:    a.UserId,
+			DishId:    dish.Id,
+			DishName:  dish.Name,
+			KitchenId: dish.KitchenId,
+			Channels:  channels,
+		})
+		if slices.Contains(channels, models.DishAlertEmail) {
+			go h.emailDishAvailable(a.UserId, dish.Name)
+		}
+	}
+}
+
+func (h *Handler) emailDishAvailable(userID, dishName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	profile, err := h.UserClient.GetProfile(ctx, &pbu.ID{Id: userID})
+	if err == nil && profile.Email == "" {
+		err = errors.New("user has no email")
+	}
+	if err == nil {
+		_, err = h.Mailer.Queue(profile.Email, "en", models.EmailDishAvailable,
+			email.DishAvailableData{DishName: dishName})
+	}
+	if err != nil {
+		h.Logger.Error(errors.Wrapf(err, "error emailing user %s about available dish", userID).Error())
+	}
+}
+
+// alertChannels checks the requested channels, defaulting to push.
+func (h *Handler) alertChannels(channels []string) ([]string, error) {
+	if len(channels) == 0 {
+		return []string{models.DishAlertPush}, nil
+	}
+
+	var res []string
+	for _, ch := range channels {
+		switch ch {
+		case models.DishAlertPush:
+		case models.DishAlertEmail:
+			if !h.Mailer.Enabled() {
+				return nil, email.ErrDisabled
+			}
+		default:
+			return nil, errors.Errorf("channel must be %s or %s", models.DishAlertPush, models.DishAlertEmail)
+		}
+		if !slices.Contains(res, ch) {
+			res = append(res, ch)
+		}
+	}
+	return res, nil
+}
+
+func dishAlertKey(dishID, userID string) string {
+	return dishID + ":" + userID
+}
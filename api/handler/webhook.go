@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/webhook"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// CreateWebhook godoc
+// @Summary Creates a webhook
+// @Description Registers a callback URL for kitchen order events. Only the kitchen's owner manages its webhooks, and their URLs must lead to public addresses. The signing secret is only returned here
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param webhook body models.NewWebhook true "Webhook info"
+// @Success 200 {object} models.Webhook
+// @Failure 400 {object} string "Invalid kitchen ID or webhook data"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /kitchens/{id}/webhooks [post]
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	h.Logger.Info("CreateWebhook method is starting")
+
+	kitchenID := c.Param("id")
+	_, err := uuid.Parse(kitchenID)
+	if err != nil {
+		er := errors.Wrap(err, "invalid kitchen id").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+
+	var data models.NewWebhook
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid webhook data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if err := validateWebhook(c, &data); err != nil {
+		er := errors.Wrap(err, "invalid webhook data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		er := errors.Wrap(err, "error generating webhook secret").Error()
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	w := models.Webhook{
+		Id:        uuid.NewString(),
+		KitchenId: kitchenID,
+		Url:       data.Url,
+		Events:    data.Events,
+		Secret:    hex.EncodeToString(secret),
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	h.Storage.Webhooks.Set(w.Id, w)
+
+	h.Logger.Info("CreateWebhook method has finished successfully")
+	h.render(c, http.StatusOK, w)
+}
+
+// FetchWebhooks godoc
+// @Summary Gets webhooks
+// @Description Lists webhooks registered for a kitchen
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Success 200 {object} models.Webhooks
+// @Failure 400 {object} string "Invalid kitchen ID"
+// @Failure 403 {object} string "Access denied"
+// @Router /kitchens/{id}/webhooks [get]
+func (h *Handler) FetchWebhooks(c *gin.Context) {
+	h.Logger.Info("FetchWebhooks method is starting")
+
+	kitchenID := c.Param("id")
+	_, err := uuid.Parse(kitchenID)
+	if err != nil {
+		er := errors.Wrap(err, "invalid kitchen id").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return
+	}
+
+	res := models.Webhooks{Webhooks: []models.Webhook{}}
+	for _, w := range h.Storage.Webhooks.List() {
+		if w.KitchenId == kitchenID {
+			w.Secret = ""
+			res.Webhooks = append(res.Webhooks, w)
+		}
+	}
+
+	h.Logger.Info("FetchWebhooks method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// GetWebhook godoc
+// @Summary Gets a webhook
+// @Description Retrieves a webhook of a kitchen
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Success 200 {object} models.Webhook
+// @Failure 400 {object} string "Invalid kitchen or webhook ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Webhook not found"
+// @Router /kitchens/{id}/webhooks/{webhook_id} [get]
+func (h *Handler) GetWebhook(c *gin.Context) {
+	h.Logger.Info("GetWebhook method is starting")
+
+	w, ok := h.findWebhook(c)
+	if !ok {
+		return
+	}
+	w.Secret = ""
+
+	h.Logger.Info("GetWebhook method has finished successfully")
+	h.render(c, http.StatusOK, w)
+}
+
+// UpdateWebhook godoc
+// @Summary Updates a webhook
+// @Description Updates URL and subscribed events of a webhook
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Param webhook body models.NewWebhook true "Webhook info"
+// @Success 200 {object} models.Webhook
+// @Failure 400 {object} string "Invalid kitchen or webhook ID or webhook data"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Webhook not found"
+// @Router /kitchens/{id}/webhooks/{webhook_id} [put]
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	h.Logger.Info("UpdateWebhook method is starting")
+
+	w, ok := h.findWebhook(c)
+	if !ok {
+		return
+	}
+
+	var data models.NewWebhook
+	if err := c.ShouldBindJSON(&data); err != nil {
+		er := errors.Wrap(err, "invalid webhook data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	if err := validateWebhook(c, &data); err != nil {
+		er := errors.Wrap(err, "invalid webhook data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	w.Url = data.Url
+	w.Events = data.Events
+	w.UpdatedAt = time.Now().Format(time.RFC3339)
+	h.Storage.Webhooks.Set(w.Id, w)
+
+	w.Secret = ""
+
+	h.Logger.Info("UpdateWebhook method has finished successfully")
+	h.render(c, http.StatusOK, w)
+}
+
+// DeleteWebhook godoc
+// @Summary Deletes a webhook
+// @Description Removes a webhook and its delivery log
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid kitchen or webhook ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Webhook not found"
+// @Router /kitchens/{id}/webhooks/{webhook_id} [delete]
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	h.Logger.Info("DeleteWebhook method is starting")
+
+	w, ok := h.findWebhook(c)
+	if !ok {
+		return
+	}
+
+	h.Storage.Webhooks.Delete(w.Id)
+	h.Storage.WebhookDeliveries.Delete(w.Id)
+
+	h.Logger.Info("DeleteWebhook method has finished successfully")
+	h.render(c, http.StatusOK, "Webhook deleted successfully")
+}
+
+// FetchWebhookDeliveries godoc
+// @Summary Gets webhook deliveries
+// @Description Lists the most recent delivery attempts of a webhook
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Kitchen ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Success 200 {object} models.WebhookDeliveries
+// @Failure 400 {object} string "Invalid kitchen or webhook ID"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Webhook not found"
+// @Router /kitchens/{id}/webhooks/{webhook_id}/deliveries [get]
+func (h *Handler) FetchWebhookDeliveries(c *gin.Context) {
+	h.Logger.Info("FetchWebhookDeliveries method is starting")
+
+	w, ok := h.findWebhook(c)
+	if !ok {
+		return
+	}
+
+	deliveries, _ := h.Storage.WebhookDeliveries.Get(w.Id)
+	res := models.WebhookDeliveries{Deliveries: []models.WebhookDelivery{}}
+	for i := len(deliveries) - 1; i >= 0; i-- {
+		res.Deliveries = append(res.Deliveries, deliveries[i])
+	}
+
+	h.Logger.Info("FetchWebhookDeliveries method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// findWebhook looks up the webhook from the path and writes the error
+// response itself when it is missing.
+func (h *Handler) findWebhook(c *gin.Context) (models.Webhook, bool) {
+	kitchenID := c.Param("id")
+	webhookID := c.Param("webhook_id")
+
+	_, err := uuid.Parse(kitchenID)
+	if err != nil {
+		er := errors.Wrap(err, "invalid kitchen id").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Webhook{}, false
+	}
+
+	_, err = uuid.Parse(webhookID)
+	if err != nil {
+		er := errors.Wrap(err, "invalid webhook id").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Webhook{}, false
+	}
+
+	if _, ok := h.accessRole(c, "", kitchenID, ""); !ok {
+		return models.Webhook{}, false
+	}
+
+	w, ok := h.Storage.Webhooks.Get(webhookID)
+	if !ok || w.KitchenId != kitchenID {
+		er := errors.New("webhook not found").Error()
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Webhook{}, false
+	}
+
+	return w, true
+}
+
+func validateWebhook(c *gin.Context, data *models.NewWebhook) error {
+	u, err := url.Parse(data.Url)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http(s) URL")
+	}
+
+	ctx, cancel := callContext(c, defaultTimeout)
+	defer cancel()
+	if err := webhook.CheckURL(ctx, data.Url); err != nil {
+		return err
+	}
+
+	if len(data.Events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	for _, e := range data.Events {
+		if !slices.Contains(models.WebhookEvents, e) {
+			return errors.Errorf("unknown event %q", e)
+		}
+	}
+
+	slices.Sort(data.Events)
+	data.Events = slices.Compact(data.Events)
+	return nil
+}
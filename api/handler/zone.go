@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// CheckCoverage godoc
+// @Summary Checks delivery coverage
+// @Description Tells whether the service delivers to a location and in which zone. While no zones are defined every location is covered
+// @Tags zone
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Success 200 {object} models.Coverage
+// @Failure 400 {object} string "Invalid location"
+// @Router /coverage [get]
+func (h *Handler) CheckCoverage(c *gin.Context) {
+	h.Logger.Info("CheckCoverage method is starting")
+
+	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	var lng float64
+	if err == nil {
+		lng, err = strconv.ParseFloat(c.Query("lng"), 64)
+	}
+	pt := models.Point{Lat: lat, Lng: lng}
+	if err == nil {
+		err = pt.Validate()
+	}
+	if err != nil {
+		er := errors.Wrap(err, "invalid location").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	zone, covered := h.zoneAt(pt)
+	res := models.Coverage{Covered: covered}
+	if zone != nil {
+		res.Zone = &models.CoverageZone{Id: zone.Id, Name: zone.Name, City: zone.City}
+	}
+
+	h.Logger.Info("CheckCoverage method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// FetchZones godoc
+// @Summary Gets delivery zones
+// @Description Lists delivery zones, optionally of one city
+// @Tags zone
+// @Security ApiKeyAuth
+// @Param city query string false "City"
+// @Success 200 {object} models.Zones
+// @Router /admin/zones [get]
+func (h *Handler) FetchZones(c *gin.Context) {
+	h.Logger.Info("FetchZones method is starting")
+
+	city := c.Query("city")
+	res := models.Zones{Zones: []models.Zone{}}
+	for _, z := range h.Storage.Zones.List() {
+		if city == "" || strings.EqualFold(z.City, city) {
+			res.Zones = append(res.Zones, z)
+		}
+	}
+	slices.SortFunc(res.Zones, cmpZones)
+
+	h.Logger.Info("FetchZones method has finished successfully")
+	h.render(c, http.StatusOK, res)
+}
+
+// CreateZone godoc
+// @Summary Creates a delivery zone
+// @Description Defines an area the service delivers to as a polygon of lat/lng points
+// @Tags zone
+// @Security ApiKeyAuth
+// @Param zone body models.NewZone true "Zone info"
+// @Success 200 {object} models.Zone
+// @Failure 400 {object} string "Invalid zone data"
+// @Router /admin/zones [post]
+func (h *Handler) CreateZone(c *gin.Context) {
+	h.Logger.Info("CreateZone method is starting")
+
+	data, err := bindZone(c)
+	if err != nil {
+		er := errors.Wrap(err, "invalid zone data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	z := models.Zone{
+		Id:        uuid.NewString(),
+		Name:      data.Name,
+		City:      data.City,
+		Polygon:   data.Polygon,
+		Active:    data.Active == nil || *data.Active,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	h.Storage.Zones.Set(z.Id, z)
+
+	h.Logger.Info("CreateZone method has finished successfully")
+	h.render(c, http.StatusOK, z)
+}
+
+// GetZone godoc
+// @Summary Gets a delivery zone
+// @Description Retrieves a delivery zone with its polygon
+// @Tags zone
+// @Security ApiKeyAuth
+// @Param id path string true "Zone ID"
+// @Success 200 {object} models.Zone
+// @Failure 400 {object} string "Invalid zone ID"
+// @Failure 404 {object} string "Zone not found"
+// @Router /admin/zones/{id} [get]
+func (h *Handler) GetZone(c *gin.Context) {
+	h.Logger.Info("GetZone method is starting")
+
+	z, ok := h.findZone(c)
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("GetZone method has finished successfully")
+	h.render(c, http.StatusOK, z)
+}
+
+// UpdateZone godoc
+// @Summary Updates a delivery zone
+// @Description Replaces the name, city, polygon and active flag of a delivery zone
+// @Tags zone
+// @Security ApiKeyAuth
+// @Param id path string true "Zone ID"
+// @Param zone body models.NewZone true "Zone info"
+// @Success 200 {object} models.Zone
+// @Failure 400 {object} string "Invalid zone ID or data"
+// @Failure 404 {object} string "Zone not found"
+// @Router /admin/zones/{id} [put]
+func (h *Handler) UpdateZone(c *gin.Context) {
+	h.Logger.Info("UpdateZone method is starting")
+
+	z, ok := h.findZone(c)
+	if !ok {
+		return
+	}
+
+	data, err := bindZone(c)
+	if err != nil {
+		er := errors.Wrap(err, "invalid zone data").Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return
+	}
+
+	z.Name = data.Name
+	z.City = data.City
+	z.Polygon = data.Polygon
+	z.Active = data.Active == nil || *data.Active
+	z.UpdatedAt = time.Now().Format(time.RFC3339)
+	h.Storage.Zones.Set(z.Id, z)
+	h.Routes.Forget("", z.Id)
+
+	h.Logger.Info("UpdateZone method has finished successfully")
+	h.render(c, http.StatusOK, z)
+}
+
+// DeleteZone godoc
+// @Summary Deletes a delivery zone
+// @Description Removes a delivery zone
+// @Tags zone
+// @Security ApiKeyAuth
+// @Param id path string true "Zone ID"
+// @Success 200 {object} string
+// @Failure 400 {object} string "Invalid zone ID"
+// @Failure 404 {object} string "Zone not found"
+// @Router /admin/zones/{id} [delete]
+func (h *Handler) DeleteZone(c *gin.Context) {
+	h.Logger.Info("DeleteZone method is starting")
+
+	z, ok := h.findZone(c)
+	if !ok {
+		return
+	}
+
+	h.Storage.Zones.Delete(z.Id)
+	h.Routes.Forget("", z.Id)
+
+	h.Logger.Info("DeleteZone method has finished successfully")
+	h.render(c, http.StatusOK, "Zone deleted successfully")
+}
+
+// zoneAt returns the active zone containing pt and whether the service
+// delivers there, which it does everywhere while no zones are defined or
+// admins turned zone enforcement off.
+func (h *Handler) zoneAt(pt models.Point) (*models.Zone, bool) {
+	zones := h.Storage.Zones.List()
+
+	// Zones may overlap; sorting makes the zone reported stable.
+	slices.SortFunc(zones, cmpZones)
+	for _, z := range zones {
+		if z.Active && z.Polygon.Contains(pt) {
+			return &z, true
+		}
+	}
+	return nil, len(zones) == 0 || !h.Settings.ZonesEnforced()
+}
+
+func (h *Handler) findZone(c *gin.Context) (models.Zone, bool) {
+	id, err := pathUUID(c, "id", "zone id")
+	if err != nil {
+		er := err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Zone{}, false
+	}
+
+	z, ok := h.Storage.Zones.Get(id)
+	if !ok {
+		er := "zone not found"
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			gin.H{"error": er})
+		h.Logger.Error(er)
+		return models.Zone{}, false
+	}
+	return z, true
+}
+
+func bindZone(c *gin.Context) (*models.NewZone, error) {
+	var data models.NewZone
+	if err := c.ShouldBindJSON(&data); err != nil {
+		return nil, err
+	}
+	data.Name = strings.TrimSpace(data.Name)
+	data.City = strings.TrimSpace(data.City)
+	if data.Name == "" || data.City == "" {
+		return nil, errors.New("name and city are required")
+	}
+	if err := data.Polygon.Validate(); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func cmpZones(a, b models.Zone) int {
+	if n := strings.Compare(a.City, b.City); n != 0 {
+		return n
+	}
+	return strings.Compare(a.Name, b.Name)
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/abuse"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AbuseStatusKey holds the abuse status of the request's account.
+const AbuseStatusKey = "abuse_status"
+
+// Screen scores every request for abuse and tags it with its account's
+// status. Challenged accounts get 403 until they pass an OTP, which is
+// all they may do meanwhile; shadow-banned ones go on, leaving it to the
+// handlers to hold what they write. It goes after the token is checked;
+// requests without a user are not scored.
+func Screen(d *abuse.Detector, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString(UserIDKey)
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		status := d.Inspect(abuse.Request{
+			UserId:    userID,
+			RequestId: c.GetString(RequestIDKey),
+			UserAgent: c.Request.UserAgent(),
+		}, time.Now())
+		c.Set(AbuseStatusKey, status)
+		if status == models.AbuseClear {
+			c.Next()
+			return
+		}
+
+		logger.Warn("request " + c.GetString(RequestIDKey) + " " + c.Request.Method + " " +
+			c.FullPath() + " of user " + userID + " is tagged " + status)
+		if status == models.AbuseChallenge && !strings.HasSuffix(c.FullPath(), "/otp") &&
+			!strings.HasSuffix(c.FullPath(), "/otp/verify") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":     "Unusual activity on this account, verify your phone to go on",
+				"challenge": "otp",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
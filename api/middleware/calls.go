@@ -0,0 +1,291 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// CallsKey holds the backend calls of a request whose timing is reported
+// back to the client.
+const CallsKey = "backend_calls"
+
+// maxTimedCalls bounds the calls reported in a header, as proxies refuse
+// headers that are too large.
+const maxTimedCalls = 64
+
+// Call is a call to a backend service. Sent and Received are the sizes of
+// the request and reply payloads in bytes.
+type Call struct {
+	Method   string
+	Start    time.Time
+	Duration time.Duration
+	Status   string
+	Sent     int
+	Received int
+}
+
+// Calls are the backend calls made while serving a request, which may be
+// made concurrently.
+type Calls struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (cs *Calls) add(c Call) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.calls = append(cs.calls, c)
+}
+
+// List returns the calls in the order they ended.
+func (cs *Calls) List() []Call {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	return append([]Call(nil), cs.calls...)
+}
+
+// CallLog logs every call to the services at debug level, with the ID of
+// the request it was made for. Clients sending X-Debug-Timing get the
+// calls of their request back in the X-Debug-Timing response header, when
+// debug timing is enabled, and every response has a Server-Timing header
+// for browser devtools when server timing is. Both show which backend
+// methods the gateway calls, so they are best kept off in production.
+type CallLog struct {
+	logger       *slog.Logger
+	debugTiming  bool
+	serverTiming bool
+}
+
+func NewCallLog(logger *slog.Logger, debugTiming, serverTiming bool) *CallLog {
+	return &CallLog{logger: logger, debugTiming: debugTiming, serverTiming: serverTiming}
+}
+
+// Observe is a gRPC client interceptor logging the calls to the services.
+// Handlers call the services with contexts of the request, through which
+// the request ID and its Calls are found.
+func (l *CallLog) Observe(ctx context.Context, method string, req, reply any,
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	call := Call{
+		Method:   strings.TrimPrefix(method, "/"),
+		Start:    start,
+		Duration: time.Since(start),
+		Status:   status.Code(err).String(),
+		Sent:     size(req),
+	}
+	if err == nil {
+		call.Received = size(reply)
+	}
+
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	l.logger.LogAttrs(ctx, slog.LevelDebug, "grpc call",
+		slog.String("request_id", requestID),
+		slog.String("method", call.Method),
+		slog.Duration("duration", call.Duration),
+		slog.String("status", call.Status),
+		slog.Int("request_bytes", call.Sent),
+		slog.Int("response_bytes", call.Received),
+	)
+
+	if calls, ok := ctx.Value(CallsKey).(*Calls); ok {
+		calls.add(call)
+	}
+	return err
+}
+
+// ObserveStream is a gRPC client interceptor logging the streams opened
+// to the services once they end, with the messages received.
+func (l *CallLog) ObserveStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	s := &loggedStream{log: l, ctx: ctx, method: strings.TrimPrefix(method, "/"), start: time.Now()}
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		s.end(err)
+		return nil, err
+	}
+	s.ClientStream = cs
+	return s, nil
+}
+
+type loggedStream struct {
+	grpc.ClientStream
+	log      *CallLog
+	ctx      context.Context
+	method   string
+	start    time.Time
+	received int
+	once     sync.Once
+}
+
+func (s *loggedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.end(err)
+		return err
+	}
+	s.received++
+	return nil
+}
+
+func (s *loggedStream) end(err error) {
+	if err == io.EOF {
+		err = nil
+	}
+	s.once.Do(func() {
+		requestID, _ := s.ctx.Value(RequestIDKey).(string)
+		s.log.logger.LogAttrs(s.ctx, slog.LevelDebug, "grpc stream",
+			slog.String("request_id", requestID),
+			slog.String("method", s.method),
+			slog.Duration("duration", time.Since(s.start)),
+			slog.String("status", status.Code(err).String()),
+			slog.Int("messages", s.received),
+		)
+	})
+}
+
+// Timing collects the backend calls of requests and reports them in
+// response headers, with durations in milliseconds:
+//   - X-Debug-Timing, to requests sending it, one entry per call:
+//     "order.Order/GetOrderByID;dur=12.5;status=OK;sent=38;received=412";
+//   - Server-Timing, the time taken in all, the time the gateway spent
+//     not waiting on the services, then each call:
+//     `total;dur=20.1, gateway;dur=5.3, order;dur=12.5;desc="order.Order/GetOrderByID"`.
+func (l *CallLog) Timing(c *gin.Context) {
+	debug := l.debugTiming && c.GetHeader("X-Debug-Timing") != ""
+	if !debug && !l.serverTiming {
+		c.Next()
+		return
+	}
+
+	start := time.Now()
+	calls := &Calls{}
+	c.Set(CallsKey, calls)
+	c.Writer = &headerWriter{ResponseWriter: c.Writer, before: func(h gin.ResponseWriter) {
+		list := calls.List()
+		if debug {
+			h.Header().Set("X-Debug-Timing", debugTiming(list))
+		}
+		if l.serverTiming {
+			h.Header().Set("Server-Timing", serverTiming(list, time.Since(start)))
+		}
+	}}
+
+	c.Next()
+}
+
+func debugTiming(calls []Call) string {
+	entries := make([]string, 0, min(len(calls), maxTimedCalls))
+	for _, call := range calls[:min(len(calls), maxTimedCalls)] {
+		entries = append(entries, fmt.Sprintf("%s;dur=%.1f;status=%s;sent=%d;received=%d",
+			call.Method, ms(call.Duration), call.Status, call.Sent, call.Received))
+	}
+	return strings.Join(entries, ", ")
+}
+
+func serverTiming(calls []Call, total time.Duration) string {
+	calls = calls[:min(len(calls), maxTimedCalls)]
+	entries := make([]string, 0, len(calls)+2)
+	entries = append(entries,
+		fmt.Sprintf("total;dur=%.1f", ms(total)),
+		fmt.Sprintf("gateway;dur=%.1f", ms(max(total-waited(calls), 0))))
+	for _, call := range calls {
+		backend, _, _ := strings.Cut(call.Method, ".")
+		entries = append(entries, fmt.Sprintf("%s;dur=%.1f;desc=%q", backend, ms(call.Duration), call.Method))
+	}
+	return strings.Join(entries, ", ")
+}
+
+// waited is how long some call was in flight, counting calls made at
+// the same time once.
+func waited(calls []Call) time.Duration {
+	calls = slices.Clone(calls)
+	slices.SortFunc(calls, func(a, b Call) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	var total time.Duration
+	var until time.Time
+	for _, call := range calls {
+		end := call.Start.Add(call.Duration)
+		if !end.After(until) {
+			continue
+		}
+		total += end.Sub(maxTime(call.Start, until))
+		until = end
+	}
+	return total
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func size(m any) int {
+	if msg, ok := m.(proto.Message); ok {
+		return proto.Size(msg)
+	}
+	return 0
+}
+
+// headerWriter calls before once, right before the response headers are
+// written, so headers can be set from what happened while serving.
+type headerWriter struct {
+	gin.ResponseWriter
+	before func(gin.ResponseWriter)
+	done   bool
+}
+
+func (w *headerWriter) writeHeaders() {
+	if !w.done && !w.Written() {
+		w.done = true
+		w.before(w.ResponseWriter)
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection, to lift the
+// write deadline of streams.
+func (w *headerWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *headerWriter) Flush() {
+	w.writeHeaders()
+	w.ResponseWriter.Flush()
+}
+
+func (w *headerWriter) WriteHeaderNow() {
+	w.writeHeaders()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *headerWriter) Write(b []byte) (int, error) {
+	w.writeHeaders()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *headerWriter) WriteString(s string) (int, error) {
+	w.writeHeaders()
+	return w.ResponseWriter.WriteString(s)
+}
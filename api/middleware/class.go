@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Route classes, from the most to the least important.
+const (
+	ClassCheckout    = "checkout"
+	ClassOrderStatus = "order_status"
+	ClassBrowse      = "browse"
+	ClassAnalytics   = "analytics"
+)
+
+// classWaits is how long a request waits for a free slot of its class
+// before it gets 503. More important classes queue longer rather than
+// fail.
+var classWaits = map[string]time.Duration{
+	ClassCheckout:    10 * time.Second,
+	ClassOrderStatus: 5 * time.Second,
+	ClassBrowse:      time.Second,
+	ClassAnalytics:   0,
+}
+
+// Classes limits how many requests of each route class are served at
+// once, so that one class, such as an analytics scrape, cannot take the
+// capacity another, such as order placement, needs.
+type Classes struct {
+	slots map[string]chan struct{}
+}
+
+// NewClasses takes the limit of each class. Classes without a positive
+// limit are not limited.
+func NewClasses(limits map[string]int) *Classes {
+	c := &Classes{slots: make(map[string]chan struct{})}
+	for class, limit := range limits {
+		if limit > 0 {
+			c.slots[class] = make(chan struct{}, limit)
+		}
+	}
+	return c
+}
+
+// Limit admits requests of the class while it has a free slot, queuing
+// them for the class's wait otherwise.
+func (cl *Classes) Limit(class string) gin.HandlerFunc {
+	slots, ok := cl.slots[class]
+	if !ok {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	wait := classWaits[class]
+
+	return func(c *gin.Context) {
+		select {
+		case slots <- struct{}{}:
+		default:
+			if !acquire(c, slots, wait) {
+				return
+			}
+		}
+		defer func() { <-slots }()
+
+		c.Next()
+	}
+}
+
+// acquire waits for a slot until wait is over or the client leaves.
+func acquire(c *gin.Context, slots chan struct{}, wait time.Duration) bool {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-c.Request.Context().Done():
+		c.Abort()
+		return false
+	case <-timer.C:
+		Throttle(c, http.StatusServiceUnavailable, gin.H{
+			"error": "Too many requests of this kind, try again later",
+		}, Backoff(models.RetryConcurrency, time.Second))
+		return false
+	}
+}
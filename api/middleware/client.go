@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/version"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// platforms maps the X-Platform values to the platforms they count as.
+var platforms = map[string]string{
+	models.PlatformIOS:       models.PlatformIOS,
+	models.PlatformAndroid:   models.PlatformAndroid,
+	models.PlatformAndroidGo: models.PlatformAndroid,
+	models.PlatformWeb:       models.PlatformWeb,
+}
+
+// ClientOf tells which app the request came from by its X-Platform and
+// X-App-Version headers. Unknown platforms are PlatformOther and
+// malformed versions AppUnknown, so clients can't make up new ones
+// without bound.
+func ClientOf(c *gin.Context) models.ClientApp {
+	app := models.ClientApp{Platform: models.PlatformOther, Version: models.AppUnknown}
+	if p, ok := platforms[platformHeader(c)]; ok {
+		app.Platform = p
+	}
+	if v := strings.TrimSpace(c.GetHeader("X-App-Version")); version.Valid(v) {
+		app.Version = v
+	}
+	return app
+}
+
+// ProfileOf picks the profile responses to the request are shaped with:
+// the lite one for the Android Go app, the full one for everyone else.
+func ProfileOf(c *gin.Context) models.ResponseProfile {
+	if platformHeader(c) == models.PlatformAndroidGo {
+		return models.ResponseProfiles[models.ProfileLite]
+	}
+	return models.ResponseProfiles[models.ProfileFull]
+}
+
+func platformHeader(c *gin.Context) string {
+	return strings.ToLower(strings.TrimSpace(c.GetHeader("X-Platform")))
+}
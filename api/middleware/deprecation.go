@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// changelogPath is where deprecations are explained.
+const changelogPath = "/local-eats/changelog"
+
+type deprecation struct {
+	since     time.Time
+	sunset    time.Time
+	successor string
+}
+
+// Deprecation marks the answers of the routes the changelog deprecates
+// with the Deprecation and Sunset headers, and a Link to the changelog
+// and to their successor. Past its sunset a route answers 410. Malformed
+// dates in the changelog make it panic, so they are caught at startup.
+func Deprecation(changelog []models.APIVersion) gin.HandlerFunc {
+	routes := make(map[string]deprecation)
+	for _, v := range changelog {
+		for _, ch := range v.Changes {
+			if ch.Kind != models.ChangeDeprecated {
+				continue
+			}
+			d := deprecation{since: mustDate(ch.Date), successor: ch.Successor}
+			if ch.Sunset != "" {
+				d.sunset = mustDate(ch.Sunset)
+			}
+			routes[ch.Method+" "+ch.Path] = d
+		}
+	}
+
+	return func(c *gin.Context) {
+		d, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		// RFC 9745 and RFC 8594.
+		c.Header("Deprecation", "@"+strconv.FormatInt(d.since.Unix(), 10))
+		successor := successorPath(c, d.successor)
+		links := []string{"<" + changelogPath + `>; rel="deprecation"`}
+		if successor != "" {
+			links = append(links, "<"+successor+`>; rel="successor-version"`)
+		}
+		c.Header("Link", strings.Join(links, ", "))
+		if d.sunset.IsZero() {
+			c.Next()
+			return
+		}
+
+		c.Header("Sunset", d.sunset.Format(http.TimeFormat))
+		if time.Now().Before(d.sunset) {
+			c.Next()
+			return
+		}
+		res := gin.H{"error": "This endpoint was removed on " + d.sunset.Format(time.DateOnly)}
+		if successor != "" {
+			res["successor"] = successor
+		}
+		c.AbortWithStatusJSON(http.StatusGone, res)
+	}
+}
+
+// successorPath fills the parameters of the successor route with those
+// of the request: "/orders/:id/status" for order 42 is "/orders/42/status".
+func successorPath(c *gin.Context, route string) string {
+	parts := strings.Split(route, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") {
+			parts[i] = c.Param(p[1:])
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func mustDate(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic("invalid changelog date " + strconv.Quote(s))
+	}
+	return t
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// LocaleKey holds the locale the request is served in.
+const LocaleKey = "locale"
+
+// localeMetadata carries the locale to the services.
+const localeMetadata = "x-locale"
+
+// Locale picks the locale of the request from Accept-Language, the one
+// of models.Locales the client prefers most, or fallback when it accepts
+// none of them, and tells it back in Content-Language.
+func Locale(fallback string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := negotiate(c.GetHeader("Accept-Language"), fallback)
+		c.Set(LocaleKey, locale)
+		c.Header("Content-Language", locale)
+
+		c.Next()
+	}
+}
+
+// negotiate picks the supported locale of the highest q in header, the
+// earliest of them on a tie. Regions are ignored, so ru-RU is ru.
+func negotiate(header, fallback string) string {
+	best, bestQ := fallback, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !slices.Contains(models.Locales, tag) {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// ForwardLocale passes the locale of the request to the services in the
+// x-locale metadata, so they can answer in it.
+func ForwardLocale(ctx context.Context, method string, req, reply any,
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(withLocale(ctx), method, req, reply, cc, opts...)
+}
+
+// ForwardLocaleStream is ForwardLocale for streams.
+func ForwardLocaleStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(withLocale(ctx), desc, cc, method, opts...)
+}
+
+func withLocale(ctx context.Context) context.Context {
+	if locale, ok := ctx.Value(LocaleKey).(string); ok {
+		return metadata.AppendToOutgoingContext(ctx, localeMetadata, locale)
+	}
+	return ctx
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/settings"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maintenancePoll is how often clients are asked to come back while
+// maintenance has no announced end.
+const maintenancePoll = time.Minute
+
+// Maintenance turns requests away with 503 while maintenance is on,
+// telling when it is expected to end. Admin routes, webhooks and callbacks
+// are not behind it, so ops can still work and providers are not lost.
+func Maintenance(m *settings.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mt := m.Maintenance()
+		if !mt.Enabled {
+			c.Next()
+			return
+		}
+
+		wait := maintenancePoll
+		if end, err := time.Parse(time.RFC3339, mt.EndsAt); err == nil && time.Until(end) > 0 {
+			wait = time.Until(end)
+		}
+		Throttle(c, http.StatusServiceUnavailable, gin.H{
+			"error":   mt.Message,
+			"ends_at": mt.EndsAt,
+		}, RetryAt(models.RetryMaintenance, wait))
+	}
+}
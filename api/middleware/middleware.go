@@ -9,16 +9,48 @@ import (
 
 const (
 	signingkey = "hello world"
+
+	// UserIDKey and RoleKey hold the caller's token claims in the context.
+	UserIDKey = "user_id"
+	RoleKey   = "role"
 )
 
 func Check(c *gin.Context) {
+	if _, ok := parseToken(c); !ok {
+		return
+	}
+
+	c.Next()
+}
+
+// Admin lets through only tokens whose role claim is "admin".
+func Admin(c *gin.Context) {
+	token, ok := parseToken(c)
+	if !ok {
+		return
+	}
+
+	claims, _ := token.Claims.(jwt.MapClaims)
+	if role, _ := claims["role"].(string); role != "admin" {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "Admin role is required",
+		})
+		return
+	}
+
+	c.Next()
+}
+
+// parseToken validates the Authorization header and aborts the request
+// when it is missing or invalid.
+func parseToken(c *gin.Context) (*jwt.Token, bool) {
 	accessToken := c.GetHeader("Authorization")
 
 	if accessToken == "" {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 			"error": "Authorization header is required",
 		})
-		return
+		return nil, false
 	}
 
 	token, err := jwt.Parse(accessToken, func(t *jwt.Token) (interface{}, error) {
@@ -29,15 +61,23 @@ func Check(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 			"error": "Token could not be parsed",
 		})
-		return
+		return nil, false
 	}
 
 	if !token.Valid {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 			"error": "Invalid token provided",
 		})
-		return
+		return nil, false
 	}
 
-	c.Next()
+	claims, _ := token.Claims.(jwt.MapClaims)
+	if id, ok := claims["user_id"].(string); ok {
+		c.Set(UserIDKey, id)
+	}
+	if role, ok := claims["role"].(string); ok {
+		c.Set(RoleKey, role)
+	}
+
+	return token, true
 }
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+)
+
+// BenchmarkAuth checks tokens as the routes behind Check and Admin do,
+// which every authenticated request pays for.
+func BenchmarkAuth(b *testing.B) {
+	gin.SetMode(gin.ReleaseMode)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": uuid.NewString(),
+		"role":    "admin",
+	}).SignedString([]byte(signingkey))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, bench := range []struct {
+		name  string
+		check gin.HandlerFunc
+		token string
+		code  int
+	}{
+		{"check", Check, token, http.StatusOK},
+		{"admin", Admin, token, http.StatusOK},
+		{"invalid", Check, token + "x", http.StatusUnauthorized},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest(http.MethodGet, "/local-eats/orders", nil)
+				c.Request.Header.Set("Authorization", bench.token)
+				bench.check(c)
+				if !c.IsAborted() {
+					c.Status(http.StatusOK)
+				}
+				if c.Writer.Status() != bench.code {
+					b.Fatalf("answered %d", c.Writer.Status())
+				}
+			}
+		})
+	}
+}
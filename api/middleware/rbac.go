@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RBAC authorizes requests by the permissions of the token's role.
+type RBAC struct {
+	roles *storage.Store[models.Role]
+}
+
+func NewRBAC(roles *storage.Store[models.Role]) *RBAC {
+	return &RBAC{roles: roles}
+}
+
+// Allows reports whether role grants permission. Unknown roles grant
+// nothing.
+func (r *RBAC) Allows(role, permission string) bool {
+	ro, ok := r.roles.Get(role)
+	return ok && ro.Allows(permission)
+}
+
+// Require lets through only tokens whose role grants permission.
+func (r *RBAC) Require(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := parseToken(c); !ok {
+			return
+		}
+
+		if !r.Allows(c.GetString(RoleKey), permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Permission " + permission + " is required",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"api-gateway/pkg/recording"
+	"bytes"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordingsPath is where recordings are managed; those calls are not
+// recorded.
+const recordingsPath = "/local-eats/admin/recordings"
+
+// Recording captures the requests served while a recording runs, for
+// the recorder to anonymize. Requests matching no route are not
+// recorded, and only the start of large bodies is read ahead.
+func Recording(r *recording.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := r.Active(time.Now())
+		if !ok || strings.HasPrefix(c.Request.URL.Path, recordingsPath) {
+			c.Next()
+			return
+		}
+
+		var (
+			body      []byte
+			truncated bool
+		)
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(io.LimitReader(c.Request.Body, int64(r.MaxBody())+1))
+			truncated = err != nil || len(body) > r.MaxBody()
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+		}
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			return
+		}
+		params := make(map[string]string, len(c.Params))
+		for _, p := range c.Params {
+			params[p.Key] = p.Value
+		}
+		r.Record(id, recording.Request{
+			Method:    c.Request.Method,
+			Route:     route,
+			Path:      c.Request.URL.EscapedPath(),
+			Params:    params,
+			Query:     c.Request.URL.Query(),
+			Header:    c.Request.Header,
+			Body:      body,
+			Truncated: truncated,
+			Status:    c.Writer.Status(),
+			Duration:  time.Since(start),
+			At:        start,
+		})
+	}
+}
+
+// readCloser reads the body read ahead and then the rest, closing the
+// original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
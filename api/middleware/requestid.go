@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDKey = "request_id"
+
+// RequestID tags every request with the caller's X-Request-ID, or a new
+// one, and echoes it back so clients can quote it in bug reports.
+func RequestID(c *gin.Context) {
+	id := c.GetHeader("X-Request-ID")
+	if id == "" || len(id) > 128 {
+		id = uuid.NewString()
+	}
+
+	c.Set(RequestIDKey, id)
+	c.Header("X-Request-ID", id)
+
+	c.Next()
+}
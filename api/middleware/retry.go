@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRetryAfter is sent with 429 and 503 answers that don't tell when
+// to retry, such as those relaying a backend that is unavailable or whose
+// circuit breaker is open.
+const defaultRetryAfter = "1"
+
+// maxBackoff caps the exponential backoff the gateway asks clients for.
+const maxBackoff = 30
+
+// Backoff hints at retrying after wait, rounded up to whole seconds, and
+// backing off exponentially from there.
+func Backoff(reason string, wait time.Duration) models.RetryHint {
+	after := max(int(math.Ceil(wait.Seconds())), 1)
+	return models.RetryHint{
+		Reason:       reason,
+		AfterSeconds: after,
+		Backoff:      models.BackoffExponential,
+		MaxSeconds:   max(after, maxBackoff),
+		Jitter:       true,
+	}
+}
+
+// RetryAt hints at retrying after wait, when the client is known to be
+// turned away until then, backing off no further.
+func RetryAt(reason string, wait time.Duration) models.RetryHint {
+	after := max(int(math.Ceil(wait.Seconds())), 1)
+	return models.RetryHint{
+		Reason:       reason,
+		AfterSeconds: after,
+		Backoff:      models.BackoffFixed,
+		MaxSeconds:   after,
+	}
+}
+
+// Throttle turns the request away with status, 429 or 503, telling the
+// client when and how to retry in Retry-After and the body's "retry".
+func Throttle(c *gin.Context, status int, body gin.H, hint models.RetryHint) {
+	body["retry"] = hint
+	c.Header("Retry-After", strconv.Itoa(hint.AfterSeconds))
+	c.AbortWithStatusJSON(status, body)
+}
+
+// RetryAfter makes sure every 429 and 503 answer has a Retry-After
+// header, so clients relaying backend errors back off too.
+func RetryAfter(c *gin.Context) {
+	c.Writer = &headerWriter{ResponseWriter: c.Writer, before: func(w gin.ResponseWriter) {
+		status := w.Status()
+		if (status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable) &&
+			w.Header().Get("Retry-After") == "" {
+			w.Header().Set("Retry-After", defaultRetryAfter)
+		}
+	}}
+
+	c.Next()
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SandboxKey marks requests made in the sandbox, whose orders are test
+// data paid with fake money.
+const SandboxKey = "sandbox"
+
+// Sandbox puts requests sent with X-Sandbox: true in the sandbox when
+// header is set. Partner keys made for the sandbox put their requests
+// there whatever the header says.
+func Sandbox(header bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !header {
+			c.Next()
+			return
+		}
+		if on, _ := strconv.ParseBool(c.GetHeader("X-Sandbox")); on {
+			setSandbox(c)
+		}
+		c.Next()
+	}
+}
+
+// InSandbox tells whether the request was made in the sandbox.
+func InSandbox(c *gin.Context) bool {
+	return c.GetBool(SandboxKey)
+}
+
+// setSandbox puts the request in the sandbox and tells the client so.
+func setSandbox(c *gin.Context) {
+	c.Set(SandboxKey, true)
+	c.Header("X-Sandbox", "true")
+}
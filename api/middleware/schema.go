@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"api-gateway/pkg/schema"
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchemaVersionKey holds the schema version the request's client speaks.
+const SchemaVersionKey = "schema_version"
+
+// schemaResponseKey holds the migration of the request's response back
+// to the client's version, if it has one.
+const schemaResponseKey = "schema_response"
+
+// maxMigratedBody is the largest request body migrated. The bodies of
+// migrated routes are JSON, so this is well above any of them.
+const maxMigratedBody = 1 << 20
+
+// Schema migrates the JSON bodies of requests made in an older schema
+// version to the current one, and sets up their responses to be migrated
+// back by MigrateResponse. The version is the X-Schema-Version of the
+// request, else the one of the app release it came from.
+func Schema(m *schema.Migrator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, err := m.Version(ClientOf(c), c.GetHeader("X-Schema-Version"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(SchemaVersionKey, v)
+		c.Header("X-Schema-Version", strconv.Itoa(v))
+
+		route := c.Request.Method + " " + c.FullPath()
+		request, response := m.Changes(route, v)
+		if response {
+			c.Set(schemaResponseKey, func(data []byte) ([]byte, error) {
+				return m.Response(route, v, data)
+			})
+		}
+		if !request || c.Request.Body == nil || !jsonBody(c) {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxMigratedBody+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "error reading body"})
+			return
+		}
+		if len(body) > maxMigratedBody {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "body is too large"})
+			return
+		}
+		if len(bytes.TrimSpace(body)) > 0 {
+			if body, err = m.Request(route, v, body); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Request.ContentLength = int64(len(body))
+
+		c.Next()
+	}
+}
+
+// MigrateResponse migrates data, a JSON response, back to the schema
+// version of the request's client. Responses of routes that didn't
+// change since are returned as they are.
+func MigrateResponse(c *gin.Context, data []byte) ([]byte, error) {
+	v, _ := c.Get(schemaResponseKey)
+	migrate, ok := v.(func([]byte) ([]byte, error))
+	if !ok {
+		return data, nil
+	}
+	return migrate(data)
+}
+
+// jsonBody tells whether the request body is JSON, as bodies without a
+// Content-Type are taken to be.
+func jsonBody(c *gin.Context) bool {
+	ct := c.ContentType()
+	return ct == "" || ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+)
+
+// latencyWindow is how long backend calls are averaged over.
+const latencyWindow = time.Second
+
+// Shedder turns away low-priority requests, browsing and search, while
+// the gateway is overloaded: more requests are in flight than it can take,
+// or the services answered slowly on average over the last second. The
+// rest, checkout and payments above all, keep the capacity freed. Zero
+// limits are not checked.
+type Shedder struct {
+	maxInFlight int64
+	maxLatency  time.Duration
+	inFlight    atomic.Int64
+
+	mu      sync.Mutex
+	start   time.Time
+	sum     time.Duration
+	calls   int64
+	latency time.Duration
+}
+
+func NewShedder(maxInFlight int, maxLatency time.Duration) *Shedder {
+	return &Shedder{
+		maxInFlight: int64(maxInFlight),
+		maxLatency:  maxLatency,
+		start:       time.Now(),
+	}
+}
+
+// Track counts the requests in flight. WebSockets, event streams and long
+// polls are left out, as they stay open without taking up the gateway.
+func (s *Shedder) Track(c *gin.Context) {
+	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") ||
+		strings.Contains(c.GetHeader("Accept"), "text/event-stream") || strings.HasSuffix(c.FullPath(), "/poll") {
+		c.Next()
+		return
+	}
+
+	s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	c.Next()
+}
+
+// Shed rejects the request with 503 while the gateway is overloaded.
+func (s *Shedder) Shed(c *gin.Context) {
+	if s.Overloaded() {
+		Throttle(c, http.StatusServiceUnavailable, gin.H{
+			"error": "The service is overloaded, try again later",
+		}, Backoff(models.RetryOverloaded, time.Second))
+		return
+	}
+
+	c.Next()
+}
+
+// Overloaded tells whether low-priority requests are being shed.
+func (s *Shedder) Overloaded() bool {
+	if s.maxInFlight > 0 && s.inFlight.Load() > s.maxInFlight {
+		return true
+	}
+	if s.maxLatency <= 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roll(time.Now())
+	return s.latency > s.maxLatency
+}
+
+// Observe is a gRPC client interceptor timing the calls to the services.
+func (s *Shedder) Observe(ctx context.Context, method string, req, reply any,
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	end := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roll(end)
+	s.sum += end.Sub(start)
+	s.calls++
+	return err
+}
+
+// roll starts a new window once the current one is over, keeping its
+// average. A window without calls, or one that ended long ago, averages
+// zero, so shedding stops by itself once the services fall quiet.
+func (s *Shedder) roll(now time.Time) {
+	elapsed := now.Sub(s.start)
+	if elapsed < latencyWindow {
+		return
+	}
+
+	s.latency = 0
+	if s.calls > 0 && elapsed < 2*latencyWindow {
+		s.latency = s.sum / time.Duration(s.calls)
+	}
+	s.start, s.sum, s.calls = now, 0, 0
+}
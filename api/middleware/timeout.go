@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeadlineKey holds the time.Time by which the client wants its request
+// served, when it sent X-Timeout-Ms.
+const DeadlineKey = "deadline"
+
+// Timeout reads the budget the client gives its request, in milliseconds,
+// from X-Timeout-Ms: short for quick UI interactions, long for background
+// syncs. It is clamped between floor and ceiling, and the services are
+// called with it instead of the handlers' own timeouts.
+func Timeout(floor, ceiling time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("X-Timeout-Ms")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		ms, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || ms <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "invalid X-Timeout-Ms: must be a positive number of milliseconds",
+			})
+			return
+		}
+
+		// Comparing milliseconds, huge budgets don't overflow.
+		budget := ceiling
+		if ms < ceiling.Milliseconds() {
+			budget = max(time.Duration(ms)*time.Millisecond, floor)
+		}
+		c.Set(DeadlineKey, time.Now().Add(budget))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/settings"
+	"api-gateway/pkg/version"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVersion answers the requests of apps older than the oldest
+// version of their platform served with 426 Upgrade Required, telling
+// where to upgrade from. Clients that don't tell their platform and
+// version, such as partners' integrations, are served whatever it is.
+func RequireVersion(m *settings.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		app := ClientOf(c)
+		if app.Version == models.AppUnknown {
+			c.Next()
+			return
+		}
+
+		v := m.AppVersions()
+		min := v.Minimum(app.Platform)
+		if min == "" || version.Compare(app.Version, min) >= 0 {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusUpgradeRequired, models.UpgradeRequired{
+			Error:      "this version of the app is no longer supported, please upgrade",
+			Message:    v.Message,
+			Platform:   app.Platform,
+			Version:    app.Version,
+			MinVersion: min,
+			StoreUrl:   v.StoreUrl(app.Platform),
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"api-gateway/pkg/usage"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usage counts the calls of every route by app version, with how they
+// were answered. Requests matching no route are not counted.
+func Usage(r *usage.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if route := c.FullPath(); route != "" {
+			r.Record(c.Request.Method, route, ClientOf(c), c.Writer.Status(), time.Now())
+		}
+	}
+}
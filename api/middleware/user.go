@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// forgetRejectedAfter is how long a user with nothing in flight is kept
+// after their last rejection.
+const forgetRejectedAfter = 10 * time.Minute
+
+type userLoad struct {
+	inFlight     int
+	rejected     int64
+	lastRejected time.Time
+}
+
+// UserLimits caps the requests each user has in flight, so a single
+// client stuck in a retry storm cannot take up the whole gateway. Users
+// are only kept while they have requests in flight or were rejected in
+// the last forgetRejectedAfter, which is what Loads reports.
+type UserLimits struct {
+	limit int
+
+	mu    sync.Mutex
+	users map[string]*userLoad
+	// swept is when idle users were last forgotten.
+	swept time.Time
+}
+
+// NewUserLimits caps each user at limit requests; zero turns the cap off.
+func NewUserLimits(limit int) *UserLimits {
+	return &UserLimits{limit: limit, users: make(map[string]*userLoad)}
+}
+
+// Limit rejects the request with 429 while its user has as many requests
+// in flight as the cap. It goes after the token is checked; requests
+// without a user are not limited.
+func (u *UserLimits) Limit(c *gin.Context) {
+	userID := c.GetString(UserIDKey)
+	if u.limit <= 0 || userID == "" {
+		c.Next()
+		return
+	}
+
+	if !u.acquire(userID) {
+		Throttle(c, http.StatusTooManyRequests, gin.H{
+			"error": "Too many requests in flight for this user",
+		}, Backoff(models.RetryUserLimit, time.Second))
+		return
+	}
+	defer u.release(userID)
+
+	c.Next()
+}
+
+func (u *UserLimits) acquire(userID string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := time.Now()
+	u.sweep(now)
+	l, ok := u.users[userID]
+	if !ok {
+		l = &userLoad{}
+		u.users[userID] = l
+	}
+	if l.inFlight >= u.limit {
+		l.rejected++
+		l.lastRejected = now
+		return false
+	}
+	l.inFlight++
+	return true
+}
+
+func (u *UserLimits) release(userID string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	l := u.users[userID]
+	l.inFlight--
+	if l.idle(time.Now()) {
+		delete(u.users, userID)
+	}
+}
+
+// sweep forgets the idle users, at most once every forgetRejectedAfter,
+// as users rejected once may never come back to be released.
+func (u *UserLimits) sweep(now time.Time) {
+	if now.Sub(u.swept) < forgetRejectedAfter {
+		return
+	}
+	u.swept = now
+	for id, l := range u.users {
+		if l.idle(now) {
+			delete(u.users, id)
+		}
+	}
+}
+
+// idle tells whether the user has nothing in flight and no recent
+// rejection to report.
+func (l *userLoad) idle(now time.Time) bool {
+	return l.inFlight == 0 && (l.rejected == 0 || now.Sub(l.lastRejected) >= forgetRejectedAfter)
+}
+
+// Loads reports the cap and the users it is tracking.
+func (u *UserLimits) Loads() models.UserLoads {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := time.Now()
+	res := models.UserLoads{Limit: u.limit, Users: make([]models.UserLoad, 0, len(u.users))}
+	for id, l := range u.users {
+		if l.idle(now) {
+			continue
+		}
+		load := models.UserLoad{UserId: id, InFlight: l.inFlight, Rejected: l.rejected}
+		if !l.lastRejected.IsZero() {
+			load.LastRejectedAt = l.lastRejected.Format(time.RFC3339)
+		}
+		res.Users = append(res.Users, load)
+	}
+	return res
+}
@@ -4,31 +4,92 @@ import (
 	"api-gateway/api/handler"
 	"api-gateway/api/middleware"
 	"api-gateway/config"
+	"api-gateway/models"
+	"api-gateway/pkg/openapi"
+	"api-gateway/pkg/schema"
+	"log"
+	"net/http"
+	"strings"
 
-	_ "api-gateway/api/docs"
+	"api-gateway/api/docs"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
 )
 
 // @title Local Eats
 // @version 1.0
 // @description API Gateway of Local Eats
-// @host localhost:8080
 // @BasePath /local-eats
-// @schemes http
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
-func NewRouter(cfg *config.Config) *gin.Engine {
-	h := handler.NewHandler(cfg)
+func NewRouter(cfg *config.Config, opts ...grpc.DialOption) *gin.Engine {
+	h := handler.NewHandler(cfg, opts...)
 
 	router := gin.Default()
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Requests are recorded as clients sent them, before any middleware
+	// changes them.
+	router.Use(middleware.RequestID, middleware.Recording(h.Recorder), middleware.Usage(h.Usage),
+		middleware.RetryAfter, h.Shedder.Track, h.Calls.Timing,
+		middleware.Timeout(cfg.REQUEST_TIMEOUT_MIN, cfg.REQUEST_TIMEOUT_MAX),
+		middleware.Deprecation(models.APIChangelog), middleware.Locale(cfg.DEFAULT_LOCALE),
+		middleware.Sandbox(cfg.SANDBOX_HEADER),
+		middleware.Schema(schema.New(models.SchemaVersion, models.SchemaMigrations, models.SchemaApps,
+			models.Enums)))
+	// Browsing and search are turned away first when the gateway is
+	// overloaded, leaving capacity to orders and payments.
+	shed := h.Shedder.Shed
+	// Each route class has its own concurrency limit, so a flood of one
+	// cannot starve another.
+	checkout := h.Classes.Limit(middleware.ClassCheckout)
+	status := h.Classes.Limit(middleware.ClassOrderStatus)
+	browse := h.Classes.Limit(middleware.ClassBrowse)
+	analytics := h.Classes.Limit(middleware.ClassAnalytics)
 
+	// Prometheus scrapes the metrics from inside the cluster.
+	if cfg.METRICS_PATH != "" {
+		router.GET(cfg.METRICS_PATH, gin.WrapH(h.SLO.Handler()))
+	}
+
+	// Images are public so they can be used directly in <img> tags.
+	router.GET("/local-eats/images/*key", h.GetImage)
+	// The app checks coverage at startup, before the user signs in.
+	router.GET("/local-eats/coverage", h.CheckCoverage)
+	router.GET("/local-eats/banners", h.FetchBanners)
+	router.GET("/local-eats/changelog", h.GetChangelog)
+	// Payment providers authenticate with their own signatures.
+	router.POST("/local-eats/payments/webhooks/:provider", h.PaymentWebhook)
+	router.POST("/local-eats/payments/chargebacks/:provider", h.ChargebackWebhook)
+	// Short links are opened by anyone they are shared with.
+	router.GET("/l/:code", h.FollowLink)
+	// The marketing website and crawlers read these without signing in.
+	router.GET("/sitemap.xml", shed, browse, h.GetSitemap)
+	router.GET("/local-eats/kitchens/:id/meta", shed, browse, h.GetKitchenMeta)
+	// Menu QR codes open this page.
+	router.GET("/m/:kitchen_id", shed, browse, h.GetMenuPage)
+	// Invitees open their invitation before they have an account.
+	router.GET("/local-eats/invitations/:token", h.GetInvitation)
+	// SMS gateways authenticate with their own signatures or secret.
+	router.POST("/local-eats/sms/callbacks/:provider", h.SMSCallback)
+
+	// Partner aggregators sign in with API keys rather than tokens.
+	pt := router.Group("/local-eats/partners")
+	pt.Use(middleware.Maintenance(h.Settings), h.PartnerKeys.Require)
+	{
+		pt.GET("/kitchens/delta", shed, browse, h.GetCatalogDelta)
+		// Orders are signed as well, so a leaked key alone can't place
+		// them.
+		pt.POST("/orders", h.PartnerKeys.Signed, checkout, h.CreatePartnerOrder)
+		pt.GET("/orders/:external_id", h.PartnerKeys.Signed, h.GetPartnerOrder)
+	}
+
+	// Admin routes are not behind maintenance, so ops can end it.
 	api := router.Group("/local-eats")
-	api.Use(middleware.Check)
+	api.Use(middleware.Maintenance(h.Settings), middleware.RequireVersion(h.Settings), middleware.Check,
+		middleware.Screen(h.Abuse, h.Logger), h.UserLimits.Limit)
 
 	u := api.Group("/users")
 	{
@@ -36,38 +97,154 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 		u.PUT(":id", h.UpdateUser)
 		u.DELETE(":id", h.DeleteUser)
 		u.GET(":id/activity", h.TrackActivity)
+		u.GET(":id/nutrition-summary", h.GetNutritionSummary)
+		u.GET(":id/allergies", h.GetAllergyProfile)
+		u.PUT(":id/allergies", h.SetAllergyProfile)
+		u.GET(":id/feed", shed, browse, h.GetFeed)
+		u.POST(":id/blocked-kitchens", h.BlockKitchen)
+		u.GET(":id/blocked-kitchens", h.FetchBlockedKitchens)
+		u.DELETE(":id/blocked-kitchens/:kitchen_id", h.UnblockKitchen)
+		u.GET(":id/staff-kitchens", h.FetchStaffKitchens)
+		u.GET(":id/notification-settings", h.GetNotificationSettings)
+		u.PUT(":id/notification-settings", h.UpdateNotificationSettings)
+		u.POST(":id/searches/history", h.RecordSearch)
+		u.GET(":id/searches/history", h.FetchSearchHistory)
+		u.DELETE(":id/searches/history", h.ClearSearchHistory)
+		u.POST(":id/searches/saved", h.SaveSearch)
+		u.GET(":id/searches/saved", h.FetchSavedSearches)
+		u.PUT(":id/searches/saved/:search_id", h.UpdateSavedSearch)
+		u.DELETE(":id/searches/saved/:search_id", h.DeleteSavedSearch)
 	}
 
 	k := api.Group("/kitchens")
 	{
 		k.POST("", h.CreateKitchen)
-		k.GET(":id", h.GetKitchen)
+		k.GET(":id", shed, browse, h.GetKitchen)
 		k.PUT(":id", h.UpdateKitchen)
 		k.DELETE(":id", h.DeleteKitchen)
-		k.GET("", h.FetchKitchens)
-		k.GET("/search", h.SearchKitchens)
-		k.GET(":id/dishes", h.FetchDishes)
-		k.GET(":id/orders", h.FetchOrdersForKitchen)
-		k.GET(":id/reviews", h.GetReviews)
-		k.GET(":id/statistics", h.GetStatistics)
+		k.GET("", shed, browse, h.FetchKitchens)
+		k.GET("/search", shed, browse, h.SearchKitchens)
+		k.GET(":id/dishes", shed, browse, h.FetchDishes)
+		k.GET(":id/stock", h.FetchKitchenStock)
+		k.POST(":id/documents", h.SubmitKitchenDocument)
+		k.GET(":id/verification", h.GetKitchenVerification)
+		k.GET(":id/inspections", h.GetKitchenInspections)
+		k.GET(":id/qr", h.GetKitchenQR)
+		k.POST(":id/staff", h.InviteStaff)
+		k.GET(":id/staff", h.FetchStaff)
+		k.POST(":id/staff/accept", h.AcceptStaffInvite)
+		k.PUT(":id/staff/:user_id", h.UpdateStaff)
+		k.DELETE(":id/staff/:user_id", h.RemoveStaff)
+		k.GET(":id/orders", status, h.FetchOrdersForKitchen)
+		k.GET(":id/reviews", shed, browse, h.GetReviews)
+		k.GET(":id/refund-requests", h.FetchKitchenRefundRequests)
+		k.GET(":id/earnings", h.GetEarnings)
+		k.GET(":id/payouts", h.FetchPayouts)
+		k.POST(":id/payouts", h.RBAC.Require(models.PermPayouts), h.CreatePayout)
+		k.GET(":id/statistics", analytics, h.GetStatistics)
+		k.GET(":id/statistics/export", analytics, h.ExportStatistics)
+		k.POST(":id/reports", h.ScheduleReport)
+		k.GET(":id/reports", h.FetchReports)
+		k.GET(":id/reports/schedules", h.FetchReportSchedules)
+		k.DELETE(":id/reports/schedules/:schedule_id", h.DeleteReportSchedule)
+		k.GET(":id/reports/:report_id", h.DownloadReport)
 		k.POST(":id/working-hours", h.SetWorkingHours)
+		k.GET(":id/rules", h.GetKitchenRules)
+		k.PUT(":id/rules", h.SetKitchenRules)
+		k.GET(":id/time-zone", h.GetKitchenTimeZone)
+		k.PUT(":id/time-zone", h.SetKitchenTimeZone)
+		k.GET(":id/location", h.GetKitchenLocation)
+		k.PUT(":id/location", h.SetKitchenLocation)
+		k.GET(":id/eta", h.EstimateDelivery)
+		k.GET(":id/zones/eta", h.FetchZoneEstimates)
+		k.GET(":id/order-timer", h.GetOrderTimer)
+		k.PUT(":id/order-timer", h.SetOrderTimer)
+		k.POST(":id/announcements", h.CreateAnnouncement)
+		k.GET(":id/announcements", h.FetchAnnouncements)
+		k.PUT(":id/announcements/:announcement_id", h.UpdateAnnouncement)
+		k.DELETE(":id/announcements/:announcement_id", h.DeleteAnnouncement)
+		k.GET(":id/translations", h.FetchKitchenTranslations)
+		k.PUT(":id/translations/:locale", h.SetKitchenTranslation)
+		k.DELETE(":id/translations/:locale", h.DeleteKitchenTranslation)
+		k.POST(":id/webhooks", h.CreateWebhook)
+		k.GET(":id/webhooks", h.FetchWebhooks)
+		k.GET(":id/webhooks/:webhook_id", h.GetWebhook)
+		k.PUT(":id/webhooks/:webhook_id", h.UpdateWebhook)
+		k.DELETE(":id/webhooks/:webhook_id", h.DeleteWebhook)
+		k.GET(":id/webhooks/:webhook_id/deliveries", h.FetchWebhookDeliveries)
 	}
 
 	d := api.Group("/dishes")
 	{
 		d.POST("", h.CreateDish)
-		d.GET(":id", h.GetDish)
+		d.POST("/batch", h.ImportDishes)
+		d.PUT("/availability", h.SetAvailability)
+		d.GET("/search", shed, browse, h.SearchDishes)
+		d.GET(":id", shed, browse, h.GetDish)
 		d.PUT(":id", h.UpdateDish)
 		d.DELETE(":id", h.DeleteDish)
-		d.GET(":id/nutrition", h.GetNutrition)
+		d.GET(":id/nutrition", shed, browse, h.GetNutrition)
+		d.GET(":id/ingredients", shed, browse, h.GetDishIngredients)
+		d.GET(":id/modifiers", h.GetModifiers)
+		d.PUT(":id/modifiers", h.SetModifiers)
+		d.POST(":id/notify-me", h.NotifyMe)
+		d.DELETE(":id/notify-me", h.CancelNotifyMe)
+		d.GET(":id/stock", h.GetDishStock)
+		d.PUT(":id/stock", h.SetDishStock)
+		d.DELETE(":id/stock", h.DeleteDishStock)
+		d.POST(":id/discounts", h.CreateDishDiscount)
+		d.GET(":id/discounts", h.FetchDishDiscounts)
+		d.PUT(":id/discounts/:discount_id", h.UpdateDishDiscount)
+		d.DELETE(":id/discounts/:discount_id", h.DeleteDishDiscount)
+		d.GET(":id/translations", h.FetchDishTranslations)
+		d.PUT(":id/translations/:locale", h.SetDishTranslation)
+		d.DELETE(":id/translations/:locale", h.DeleteDishTranslation)
 	}
 
+	api.GET("/cuisines", h.FetchCuisines)
+	api.GET("/allergens", h.FetchAllergens)
+	api.POST("/invitations/:token/accept", h.AcceptInvitation)
+	api.POST("/otp", h.SendOTP)
+	api.POST("/otp/verify", h.VerifyOTP)
+
 	o := api.Group("/orders")
 	{
-		o.POST("", h.CreateOrder)
-		o.GET(":id", h.GetOrderByID)
-		o.PUT(":id/status", h.ChangeStatus)
-		o.GET("", h.FetchOrdersForCustomer)
+		o.POST("", checkout, h.CreateOrder)
+		o.POST("/quote", checkout, h.QuoteOrder)
+		o.GET("/placements/:token", status, h.GetOrderPlacement)
+		o.GET(":id", status, h.GetOrderByID)
+		o.PUT(":id/status", status, h.ChangeStatus)
+		// Long polls wait idle, so they are not held to the class limit.
+		o.GET(":id/status/poll", h.PollOrderStatus)
+		o.POST(":id/refund-request", h.RequestRefund)
+		o.GET(":id/refund-requests", h.FetchOrderRefundRequests)
+		o.POST(":id/receipt", h.SendReceipt)
+		o.GET(":id/delivery", status, h.GetOrderDelivery)
+		o.PUT(":id/delivery-preferences", h.SetDeliveryPreferences)
+		o.GET("", status, h.FetchOrdersForCustomer)
+	}
+
+	g := api.Group("/group-orders")
+	{
+		g.POST("", h.CreateGroupOrder)
+		g.POST("/join/:code", h.JoinGroupOrder)
+		g.GET(":id", h.GetGroupOrder)
+		g.DELETE(":id", h.CancelGroupOrder)
+		g.GET(":id/ws", h.WatchGroupOrder)
+		g.POST(":id/items", h.AddGroupItem)
+		g.PUT(":id/items/:item_id", h.UpdateGroupItem)
+		g.DELETE(":id/items/:item_id", h.RemoveGroupItem)
+		g.POST(":id/checkout", checkout, h.CheckoutGroupOrder)
+	}
+
+	mp := api.Group("/meal-plans")
+	{
+		mp.POST("", h.CreateMealPlan)
+		mp.GET("", h.FetchMealPlans)
+		mp.GET(":id", h.GetMealPlan)
+		mp.PUT(":id", h.UpdateMealPlan)
+		mp.PUT(":id/status", h.ChangeMealPlanStatus)
+		mp.GET(":id/runs", h.FetchMealPlanRuns)
 	}
 
 	r := api.Group("/reviews")
@@ -76,10 +253,291 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 	}
 
 	p := api.Group("/payments")
+	p.Use(checkout)
 	{
 		p.POST("", h.CreatePayment)
+		p.POST("/tokenize", h.TokenizeCard)
+		p.GET("/providers", h.FetchPaymentProviders)
 		p.GET(":id", h.GetPayment)
+		p.POST(":id/confirm", h.ConfirmPayment)
+	}
+
+	a := router.Group("/local-eats/admin/analytics")
+	a.Use(h.RBAC.Require(models.PermAnalytics), shed, analytics)
+	{
+		a.GET("/orders-per-hour", h.OrdersPerHour)
+		a.GET("/revenue-by-city", h.RevenueByCity)
+		a.GET("/top-kitchens", h.TopKitchens)
+		a.GET("/churn", h.Churn)
+	}
+
+	cm := router.Group("/local-eats/admin/commissions")
+	cm.Use(h.RBAC.Require(models.PermCommissions))
+	{
+		cm.GET("", h.FetchCommissionRates)
+		cm.GET("/history", h.FetchCommissionHistory)
+		cm.GET("/kitchens/:id/effective", h.GetEffectiveCommission)
+		cm.PUT(":scope/:key", h.SetCommissionRate)
+		cm.DELETE(":scope/:key", h.DeleteCommissionRate)
+	}
+
+	cu := router.Group("/local-eats/admin/cuisines")
+	cu.Use(h.RBAC.Require(models.PermCuisines))
+	{
+		cu.GET("", h.FetchCuisineTaxonomy)
+		cu.POST("", h.CreateCuisine)
+		cu.PUT(":code", h.UpdateCuisine)
+		cu.DELETE(":code", h.DeleteCuisine)
+	}
+
+	z := router.Group("/local-eats/admin/zones")
+	z.Use(h.RBAC.Require(models.PermZones))
+	{
+		z.GET("", h.FetchZones)
+		z.POST("", h.CreateZone)
+		z.GET(":id", h.GetZone)
+		z.PUT(":id", h.UpdateZone)
+		z.DELETE(":id", h.DeleteZone)
+	}
+
+	kv := router.Group("/local-eats/admin/verifications")
+	kv.Use(h.RBAC.Require(models.PermVerifications))
+	{
+		kv.GET("", h.FetchVerifications)
+		kv.PUT(":id", h.ReviewVerification)
+	}
+
+	ki := router.Group("/local-eats/admin/inspections")
+	ki.Use(h.RBAC.Require(models.PermInspections))
+	{
+		ki.POST("", h.CreateInspection)
+		ki.DELETE(":id", h.DeleteInspection)
+	}
+
+	rt := router.Group("/local-eats/admin/retention")
+	rt.Use(h.RBAC.Require(models.PermRetention))
+	{
+		rt.GET("", h.FetchRetentionRuns)
+		rt.POST("", h.RunRetention)
+		rt.GET(":id", h.GetRetentionRun)
+	}
+
+	dr := router.Group("/local-eats/admin/data-requests")
+	dr.Use(h.RBAC.Require(models.PermDataRequests))
+	{
+		dr.POST("", h.CreateDataRequest)
+		dr.GET("", h.FetchDataRequests)
+		dr.GET(":id", h.GetDataRequest)
+		dr.GET(":id/package", h.DownloadDataRequest)
+		dr.DELETE(":id", h.DeleteDataRequest)
+	}
+
+	en := router.Group("/local-eats/admin/encryption")
+	en.Use(h.RBAC.Require(models.PermEncryption))
+	{
+		en.GET("", h.GetEncryptionStatus)
+		en.POST("rotate", h.RotateEncryptionKey)
+	}
+
+	pk := router.Group("/local-eats/admin/partners/keys")
+	pk.Use(h.RBAC.Require(models.PermPartners))
+	{
+		pk.POST("", h.CreatePartnerKey)
+		pk.GET("", h.FetchPartnerKeys)
+		pk.PUT(":id", h.UpdatePartnerKey)
+		pk.DELETE(":id", h.RevokePartnerKey)
+	}
+
+	rc := router.Group("/local-eats/admin/recordings")
+	rc.Use(h.RBAC.Require(models.PermRecordings))
+	{
+		rc.POST("", h.StartRecording)
+		rc.GET("", h.FetchRecordings)
+		rc.GET(":id", h.GetRecording)
+		rc.POST(":id/stop", h.StopRecording)
+		rc.DELETE(":id", h.DeleteRecording)
+	}
+
+	md := router.Group("/local-eats/admin/moderation")
+	md.Use(h.RBAC.Require(models.PermModeration))
+	{
+		md.GET("", h.FetchHeldReviews)
+		md.PUT(":id", h.ModerateReview)
+	}
+
+	ro := router.Group("/local-eats/admin/roles")
+	ro.Use(h.RBAC.Require(models.PermRoles))
+	{
+		ro.GET("", h.FetchRoles)
+		ro.GET("/permissions", h.FetchPermissions)
+		ro.POST("", h.CreateRole)
+		ro.PUT(":name", h.UpdateRole)
+		ro.DELETE(":name", h.DeleteRole)
+	}
+
+	in := router.Group("/local-eats/admin/invitations")
+	in.Use(h.RBAC.Require(models.PermInvitations))
+	{
+		in.POST("", h.CreateInvitation)
+		in.GET("", h.FetchInvitations)
+	}
+
+	sm := router.Group("/local-eats/admin/sms")
+	sm.Use(h.RBAC.Require(models.PermMarketing))
+	{
+		sm.POST("/campaigns", h.CreateSMSCampaign)
+		sm.GET("/messages", h.FetchSMSMessages)
+	}
+
+	bc := router.Group("/local-eats/admin/broadcasts")
+	bc.Use(h.RBAC.Require(models.PermMarketing))
+	{
+		bc.POST("", h.CreateBroadcast)
+		bc.GET("", h.FetchBroadcasts)
+		bc.GET(":id", h.GetBroadcast)
+		bc.PUT(":id", h.UpdateBroadcast)
+		bc.POST(":id/cancel", h.CancelBroadcast)
+	}
+
+	lm := router.Group("/local-eats/admin/limits")
+	lm.Use(h.RBAC.Require(models.PermAnalytics))
+	{
+		lm.GET("/users", h.FetchUserLoads)
+	}
+
+	sl := router.Group("/local-eats/admin/slo")
+	sl.Use(h.RBAC.Require(models.PermAnalytics))
+	{
+		sl.GET("", h.GetSLO)
+	}
+
+	ab := router.Group("/local-eats/admin/abuse")
+	ab.Use(h.RBAC.Require(models.PermAbuse))
+	{
+		ab.GET("", h.FetchAbuseProfiles)
+		ab.GET(":user_id", h.GetAbuseProfile)
+		ab.PUT(":user_id", h.ReviewAbuse)
+	}
+
+	fr := router.Group("/local-eats/admin/fraud")
+	fr.Use(h.RBAC.Require(models.PermFraud))
+	{
+		fr.GET("", h.FetchFraudCases)
+		fr.GET(":id", h.GetFraudCase)
+		fr.PUT(":id", h.ReviewFraudCase)
+	}
+
+	cb := router.Group("/local-eats/admin/chargebacks")
+	cb.Use(h.RBAC.Require(models.PermFraud))
+	{
+		cb.GET("", h.FetchChargebacks)
+	}
+
+	se := router.Group("/local-eats/admin/settings")
+	se.Use(h.RBAC.Require(models.PermSettings))
+	{
+		se.GET("", h.GetSettings)
+		se.PUT("", h.UpdateSettings)
+		se.GET("/history", h.FetchSettingsHistory)
+	}
+
+	em := router.Group("/local-eats/admin/emails")
+	em.Use(h.RBAC.Require(models.PermEmails))
+	{
+		em.GET("", h.FetchEmails)
+	}
+
+	au := router.Group("/local-eats/admin/api-usage")
+	au.Use(h.RBAC.Require(models.PermAnalytics))
+	{
+		au.GET("", h.GetAPIUsage)
+	}
+
+	av := router.Group("/local-eats/admin/app-versions")
+	av.Use(h.RBAC.Require(models.PermAnalytics))
+	{
+		av.GET("", h.GetAppVersions)
+	}
+
+	pc := router.Group("/local-eats/admin/postman-collection")
+	pc.Use(h.RBAC.Require(models.PermDocs))
+	{
+		pc.GET("", h.ExportPostmanCollection)
+	}
+
+	rr := api.Group("/refund-requests")
+	{
+		rr.POST(":id/approve", h.ApproveRefund)
+		rr.POST(":id/reject", h.RejectRefund)
+	}
+
+	st := api.Group("/support/tickets")
+	{
+		st.POST("", h.CreateTicket)
+		st.GET("", h.FetchTickets)
+		st.GET(":id", h.GetTicket)
+		st.POST(":id/messages", h.ReplyToTicket)
+		st.PUT(":id/status", h.ChangeTicketStatus)
+		st.DELETE(":id", h.DeleteTicket)
+	}
+
+	l := api.Group("/links")
+	{
+		l.POST("", h.CreateLink)
+		l.GET("", h.FetchLinks)
+		l.GET(":code", h.GetLink)
+		l.DELETE(":code", h.DeleteLink)
+	}
+
+	up := api.Group("/uploads")
+	{
+		up.POST("", h.CreateUpload)
+		up.GET(":id", h.GetUpload)
+		up.HEAD(":id", h.GetUpload)
+		up.PATCH(":id", h.UploadChunk)
+		up.DELETE(":id", h.DeleteUpload)
+		up.GET(":id/content", h.DownloadUpload)
+	}
+
+	spec, err := openapi.Generate([]byte(docs.SwaggerInfo.ReadDoc()),
+		router.Routes(), docs.SwaggerInfo.BasePath)
+	if err != nil {
+		log.Fatalf("error generating openapi spec: %v", err)
+	}
+	h.Spec = spec
+
+	if cfg.SWAGGER_ENABLED {
+		registerDocs(cfg, router, spec)
 	}
 
 	return router
 }
+
+// registerDocs serves the OpenAPI 3 spec generated from the routes, its
+// per-version snapshots, and Swagger UI over it.
+func registerDocs(cfg *config.Config, router *gin.Engine, spec []byte) {
+	// An empty host makes Swagger UI use the host the page was served from.
+	docs.SwaggerInfo.Host = cfg.SWAGGER_HOST
+	docs.SwaggerInfo.Schemes = strings.Split(cfg.SWAGGER_SCHEMES, ",")
+
+	snapshots := openapi.NewSnapshots(cfg.OPENAPI_SNAPSHOT_DIR)
+	if err := snapshots.Save(docs.SwaggerInfo.Version, spec); err != nil {
+		log.Printf("error saving openapi snapshot: %v", err)
+	}
+
+	sw := router.Group("")
+	switch cfg.SWAGGER_AUTH {
+	case "basic":
+		sw.Use(gin.BasicAuth(gin.Accounts{cfg.SWAGGER_USER: cfg.SWAGGER_PASSWORD}))
+	case "admin":
+		sw.Use(middleware.Admin)
+	}
+
+	sw.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	})
+	sw.GET("/openapi/versions", snapshots.VersionsHandler)
+	sw.GET("/openapi/:version", snapshots.Handler)
+	sw.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler,
+		ginSwagger.URL("/openapi.json")))
+}
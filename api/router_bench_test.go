@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BenchmarkOverhead serves hot routes whose services answer at once, so
+// the time taken is what the gateway adds to every request: middleware,
+// binding, logging and rendering. Besides the mean it reports the p95,
+// which must stay within BENCH_P95_BUDGET, such as 2ms, when it is set.
+func BenchmarkOverhead(b *testing.B) {
+	// One caller sending thousands of requests a minute is not to be
+	// taken for a bot.
+	b.Setenv("ABUSE_VELOCITY_LIMIT", "0")
+	g := newTestGateway(b, func(context.Context) error { return nil })
+
+	var budget time.Duration
+	if s := os.Getenv("BENCH_P95_BUDGET"); s != "" {
+		var err error
+		if budget, err = time.ParseDuration(s); err != nil {
+			b.Fatalf("invalid BENCH_P95_BUDGET: %v", err)
+		}
+	}
+
+	id := uuid.NewString()
+	for _, route := range []struct {
+		name   string
+		method string
+		target string
+	}{
+		{"kitchen", http.MethodGet, "/local-eats/kitchens/" + id},
+		{"kitchens", http.MethodGet, "/local-eats/kitchens?page=1&limit=10"},
+		{"dish", http.MethodGet, "/local-eats/dishes/" + id},
+		{"order", http.MethodGet, "/local-eats/orders/" + id},
+	} {
+		b.Run(route.name, func(b *testing.B) {
+			took := make([]time.Duration, b.N)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				req := httptest.NewRequest(route.method, route.target, nil)
+				req.Header.Set("Authorization", g.token)
+				w := httptest.NewRecorder()
+
+				start := time.Now()
+				g.router.ServeHTTP(w, req)
+				took[i] = time.Since(start)
+
+				if w.Code != http.StatusOK {
+					b.Fatalf("%s %s answered %d: %s", route.method, route.target, w.Code, w.Body.String())
+				}
+			}
+			b.StopTimer()
+
+			slices.Sort(took)
+			p95 := took[(len(took)*95+99)/100-1]
+			b.ReportMetric(float64(p95.Nanoseconds()), "p95-ns/op")
+
+			// The first rounds are too short for their p95 to tell.
+			if budget > 0 && b.N >= 100 && p95 > budget {
+				b.Errorf("p95 of %s is over the budget of %s", p95, budget)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"api-gateway/config"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// signingKey is the key middleware checks tokens with.
+const signingKey = "hello world"
+
+// reachedKey holds a flag on the context of a fuzzed request that is set
+// once a service is called, which means the request was bound.
+type reachedKey struct{}
+
+// testGateway is the gateway with its services answered by the test.
+type testGateway struct {
+	router *gin.Engine
+	routes []gin.RouteInfo
+	token  string
+}
+
+// newFuzzGateway answers every call to the services with an error.
+func newFuzzGateway(f *testing.F) *testGateway {
+	return newTestGateway(f, fail)
+}
+
+// newTestGateway answers the calls to the services with answer, leaving
+// their replies empty when it returns nil.
+func newTestGateway(tb testing.TB, answer func(ctx context.Context) error) *testGateway {
+	tb.Helper()
+
+	// config.Load reads .env, and the gateway writes its log and uploads
+	// to the working directory.
+	dir := tb.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(dir+"/.env", nil, 0644); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { os.Chdir(wd) })
+
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter, gin.DefaultErrorWriter = io.Discard, io.Discard
+
+	unary := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return answer(ctx)
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		// Streams are never answered, having no reply to leave empty.
+		return nil, fail(ctx)
+	}
+	router := NewRouter(config.Load(), grpc.WithChainUnaryInterceptor(unary),
+		grpc.WithChainStreamInterceptor(stream))
+
+	// Handlers call the services with the gin context, which then holds
+	// the request's flag.
+	router.ContextWithFallback = true
+
+	routes := router.Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Method+routes[i].Path < routes[j].Method+routes[j].Path
+	})
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": uuid.NewString(),
+		"role":    "admin",
+	}).SignedString([]byte(signingKey))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return &testGateway{router: router, routes: routes, token: token}
+}
+
+func fail(ctx context.Context) error {
+	if reached, ok := ctx.Value(reachedKey{}).(*atomic.Bool); ok {
+		reached.Store(true)
+	}
+	return status.Error(codes.Unavailable, "fuzzing")
+}
+
+// serve sends a request as an admin and fails the test when it panics
+// or is answered with 500 before any service was called. Other 5xx tell
+// of services that are not configured, such as SMS or email.
+func (g *testGateway) serve(t *testing.T, method, target string, body []byte) {
+	reached := new(atomic.Bool)
+	ctx := context.WithValue(context.Background(), reachedKey{}, reached)
+	// Fuzzed targets are not always valid URLs, so they are not parsed.
+	req := httptest.NewRequest(method, "/", bytes.NewReader(body)).WithContext(ctx)
+	p, query, _ := strings.Cut(target, "?")
+	req.URL = &url.URL{Path: p, RawQuery: query}
+	req.Header.Set("Authorization", g.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	g.router.ServeHTTP(w, req)
+
+	if w.Code == http.StatusInternalServerError && !reached.Load() {
+		t.Fatalf("%s %s answered %d before calling a service: %s", method, target, w.Code, w.Body.String())
+	}
+}
+
+// path fills the parameters of a route's path with param, or with a
+// UUID when param is empty, so both IDs and garbage are tried.
+func path(route, param string) string {
+	if param == "" {
+		param = uuid.NewString()
+	}
+	parts := strings.Split(route, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			parts[i] = param
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// FuzzRoutes sends every route malformed path parameters, queries and
+// bodies.
+func FuzzRoutes(f *testing.F) {
+	g := newFuzzGateway(f)
+
+	for i := range g.routes {
+		f.Add(uint16(i), "", "page=1&limit=10", []byte(`{}`))
+		f.Add(uint16(i), "x", "page=-1&limit=abc", []byte(`{"id":1}`))
+	}
+	f.Add(uint16(0), "%00", "since=2026-13-40&lat=NaN&lng=1e999", []byte(`[`))
+	f.Add(uint16(0), "", "page=99999999999&limit=0", []byte(`null`))
+
+	f.Fuzz(func(t *testing.T, i uint16, param, query string, body []byte) {
+		route := g.routes[int(i)%len(g.routes)]
+		if strings.Contains(param, "/") {
+			return
+		}
+		g.serve(t, route.Method, path(route.Path, param)+"?"+query, body)
+	})
+}
+
+// FuzzCreatePayment sends malformed payments, whose card details are
+// checked before the order is looked up.
+func FuzzCreatePayment(f *testing.F) {
+	g := newFuzzGateway(f)
+
+	f.Add([]byte(`{"order_id":"` + uuid.NewString() + `","payment_method":"card","card_number":"4111111111111111","expiry_date":"12/30","cvv":"123"}`))
+	f.Add([]byte(`{"order_id":"` + uuid.NewString() + `","provider":"stripe","payment_token":"pm_1"}`))
+	f.Add([]byte(`{"order_id":"x","provider":"sandbox","card_number":4111111111111111}`))
+	f.Add([]byte(`{"order_id":"` + uuid.NewString() + `","provider":"payme","return_url":"javascript:alert(1)"}`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		g.serve(t, http.MethodPost, "/local-eats/payments", body)
+		g.serve(t, http.MethodPost, "/local-eats/payments/tokenize", body)
+	})
+}
+
+// FuzzSetWorkingHours sends malformed working hours, bound into a map
+// of days.
+func FuzzSetWorkingHours(f *testing.F) {
+	g := newFuzzGateway(f)
+
+	f.Add([]byte(`{"monday":{"open":"09:00","close":"18:00"}}`))
+	f.Add([]byte(`{"monday":null,"":{}}`))
+	f.Add([]byte(`{"monday":{"open":9}}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		g.serve(t, http.MethodPost, "/local-eats/kitchens/"+uuid.NewString()+"/working-hours", body)
+	})
+}
@@ -3,11 +3,22 @@ package main
 import (
 	"api-gateway/api"
 	"api-gateway/config"
+	"log"
+	"net/http"
 )
 
 func main() {
 	cfg := config.Load()
 
 	router := api.NewRouter(cfg)
-	router.Run(cfg.HTTP_PORT)
+	server := &http.Server{
+		Addr:              cfg.HTTP_PORT,
+		Handler:           router,
+		ReadHeaderTimeout: cfg.HTTP_READ_HEADER_TIMEOUT,
+		ReadTimeout:       cfg.HTTP_READ_TIMEOUT,
+		WriteTimeout:      cfg.HTTP_WRITE_TIMEOUT,
+		IdleTimeout:       cfg.HTTP_IDLE_TIMEOUT,
+		MaxHeaderBytes:    cfg.HTTP_MAX_HEADER_BYTES,
+	}
+	log.Fatal(server.ListenAndServe())
 }
@@ -0,0 +1,97 @@
+// Command replay sends the requests of a recording, read from a file or
+// from a gateway's /admin/recordings/{id}, to a staging gateway in the
+// order they were served, and reports those answered with another
+// status than when they were recorded. It exits with 1 if any were.
+package main
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/recording"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func main() {
+	source := flag.String("recording", "recording.json", "recording file or URL")
+	adminToken := flag.String("admin-token", "", "Authorization to read the recording from a URL with")
+	target := flag.String("target", "http://localhost:8080", "gateway to replay against")
+	token := flag.String("token", "", "Authorization to send the replayed requests with")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout of each request")
+	flag.Parse()
+
+	data, err := read(*source, *adminToken)
+	if err != nil {
+		log.Fatalf("error reading recording: %v", err)
+	}
+
+	trace, err := decode(data)
+	if err != nil {
+		log.Fatalf("error reading recording: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report := recording.Replay(ctx, &http.Client{Timeout: *timeout}, *target, *token, trace.Requests)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatalf("error writing report: %v", err)
+	}
+	if len(report.Mismatches) > 0 {
+		os.Exit(1)
+	}
+}
+
+func read(source, token string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s answered %s", source, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// decode reads a recording, whether the gateway wrapped it in an
+// envelope or not.
+func decode(data []byte) (models.RecordingTrace, error) {
+	var env models.Envelope
+	if err := json.Unmarshal(data, &env); err == nil && len(env.Data) > 0 {
+		data = env.Data
+	}
+
+	var trace models.RecordingTrace
+	if err := json.Unmarshal(data, &trace); err != nil {
+		return trace, err
+	}
+	if trace.Recording.Id == "" {
+		return trace, errors.New("not a recording")
+	}
+	return trace, nil
+}
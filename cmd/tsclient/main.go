@@ -0,0 +1,56 @@
+// Command tsclient generates the TypeScript client of the gateway from its
+// OpenAPI spec, read from a file, such as a snapshot, or from a running
+// gateway's /openapi.json.
+package main
+
+import (
+	"api-gateway/pkg/openapi"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func main() {
+	spec := flag.String("spec", "openapi.json", "OpenAPI spec file or URL")
+	out := flag.String("out", "sdk/typescript/src/index.ts", "file to write the client to")
+	flag.Parse()
+
+	data, err := read(*spec)
+	if err != nil {
+		log.Fatalf("error reading spec: %v", err)
+	}
+
+	client, err := openapi.TypeScript(data)
+	if err != nil {
+		log.Fatalf("error generating client: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(*out), 0755); err != nil {
+		log.Fatalf("error writing client: %v", err)
+	}
+	if err := os.WriteFile(*out, client, 0644); err != nil {
+		log.Fatalf("error writing client: %v", err)
+	}
+}
+
+func read(spec string) ([]byte, error) {
+	if !strings.HasPrefix(spec, "http://") && !strings.HasPrefix(spec, "https://") {
+		return os.ReadFile(spec)
+	}
+
+	res, err := http.Get(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s answered %s", spec, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
@@ -1,17 +1,260 @@
 package config
 
 import (
+	"api-gateway/pkg/version"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cast"
 )
 
 type Config struct {
+	ENVIRONMENT        string
 	HTTP_PORT          string
 	AUTH_SERVICE_PORT  string
 	ORDER_SERVICE_PORT string
+
+	HTTP_READ_HEADER_TIMEOUT time.Duration
+	HTTP_READ_TIMEOUT        time.Duration
+	HTTP_WRITE_TIMEOUT       time.Duration
+	HTTP_IDLE_TIMEOUT        time.Duration
+	HTTP_MAX_HEADER_BYTES    int
+
+	GRPC_MAX_RECV_MSG_SIZE       int
+	GRPC_MAX_SEND_MSG_SIZE       int
+	GRPC_KEEPALIVE_TIME          time.Duration
+	GRPC_KEEPALIVE_TIMEOUT       time.Duration
+	GRPC_KEEPALIVE_WITHOUT_CALLS bool
+
+	REQUEST_TIMEOUT_MIN time.Duration
+	REQUEST_TIMEOUT_MAX time.Duration
+
+	SHED_MAX_IN_FLIGHT int
+	SHED_MAX_LATENCY   time.Duration
+
+	CLASS_CHECKOUT_LIMIT     int
+	CLASS_ORDER_STATUS_LIMIT int
+	CLASS_BROWSE_LIMIT       int
+	CLASS_ANALYTICS_LIMIT    int
+
+	USER_MAX_IN_FLIGHT int
+
+	ABUSE_WINDOW           time.Duration
+	ABUSE_VELOCITY_LIMIT   int
+	ABUSE_MAX_TRAVEL_KMH   float64
+	ABUSE_CHALLENGE_SCORE  int
+	ABUSE_SHADOW_BAN_SCORE int
+
+	FRAUD_ORDERS_PER_HOUR     int
+	FRAUD_PAYMENTS_PER_HOUR   int
+	FRAUD_VELOCITY_ACTION     string
+	FRAUD_GEO_MISMATCH_KM     float64
+	FRAUD_GEO_MISMATCH_ACTION string
+	FRAUD_FIRST_ORDER_AMOUNT  float64
+	FRAUD_FIRST_ORDER_ACTION  string
+	FRAUD_CHARGEBACK_ACTION   string
+	FRAUD_VERIFIED_TTL        time.Duration
+
+	SLO_AVAILABILITY_TARGET float64
+	SLO_LATENCY_TARGET      float64
+	SLO_LATENCY_THRESHOLD   time.Duration
+	SLO_WINDOW              time.Duration
+	METRICS_PATH            string
+
+	DEBUG_TIMING  bool
+	SERVER_TIMING bool
+
+	USAGE_RETENTION_DAYS int
+
+	PARTNER_DELTA_RETENTION_DAYS int
+
+	SANDBOX_HEADER bool
+
+	RECORDING_MAX_MINUTES  int
+	RECORDING_MAX_REQUESTS int
+	RECORDING_MAX_BODY     int
+
+	RETENTION_ENABLED        bool
+	RETENTION_RECORD_DAYS    int
+	RETENTION_LOG_DAYS       int
+	RETENTION_CHECK_INTERVAL time.Duration
+
+	MIN_IOS_VERSION     string
+	MIN_ANDROID_VERSION string
+	MIN_WEB_VERSION     string
+	IOS_STORE_URL       string
+	ANDROID_STORE_URL   string
+
+	DEFAULT_LOCALE    string
+	DEFAULT_TIME_ZONE string
+
+	DELIVERY_FEE        float32
+	DELIVERY_FEE_PER_KM float32
+	SERVICE_FEE_PERCENT float32
+	TAX_PERCENT         float32
+
+	KITCHEN_COMMISSION_PERCENT float32
+
+	EVENT_BROKER     string
+	EVENT_BROKER_URL string
+	EVENT_TOPIC      string
+
+	OUTBOX_PATH           string
+	OUTBOX_RETRY_INTERVAL time.Duration
+
+	SWAGGER_ENABLED  bool
+	SWAGGER_AUTH     string
+	SWAGGER_USER     string
+	SWAGGER_PASSWORD string
+	SWAGGER_HOST     string
+	SWAGGER_SCHEMES  string
+
+	OPENAPI_SNAPSHOT_DIR string
+
+	RESPONSE_PROTO_NAMES      bool
+	RESPONSE_ENUM_NUMBERS     bool
+	RESPONSE_EMIT_UNPOPULATED bool
+	DEFAULT_API_FORMAT        string
+	RESPONSE_ENVELOPE         bool
+	// RESPONSE_STREAM_MIN_ITEMS is how long a list must be to be streamed.
+	RESPONSE_STREAM_MIN_ITEMS int
+
+	UPLOAD_DIR      string
+	UPLOAD_MAX_SIZE int64
+
+	IMAGE_STORAGE_URL   string
+	IMAGE_MAX_DIMENSION int
+	IMAGE_CACHE_MAX_AGE time.Duration
+
+	REPORT_DIR            string
+	REPORT_CHECK_INTERVAL time.Duration
+
+	DATA_REQUEST_DIR string
+	DATA_REQUEST_TTL time.Duration
+
+	// ENCRYPTION_KEY_PROVIDER is file or aws-kms, or empty to keep
+	// personal data in the clear.
+	ENCRYPTION_KEY_PROVIDER string
+	ENCRYPTION_KEY_FILE     string
+	// ENCRYPTION_KMS_API_URL overrides the endpoint of ENCRYPTION_KMS_REGION.
+	ENCRYPTION_KMS_API_URL           string
+	ENCRYPTION_KMS_REGION            string
+	ENCRYPTION_KMS_KEY_ID            string
+	ENCRYPTION_KMS_ACCESS_KEY_ID     string
+	ENCRYPTION_KMS_SECRET_ACCESS_KEY string
+
+	// EMAIL_DRIVER is smtp, sendgrid or ses.
+	EMAIL_DRIVER        string
+	EMAIL_FROM          string
+	EMAIL_WORKERS       int
+	EMAIL_MAX_ATTEMPTS  int
+	EMAIL_RETRY_BACKOFF time.Duration
+	// ADMIN_ALERT_EMAILS is a comma-separated list of who is emailed
+	// about chargebacks.
+	ADMIN_ALERT_EMAILS string
+
+	SMTP_ADDR     string
+	SMTP_USER     string
+	SMTP_PASSWORD string
+
+	SENDGRID_API_URL string
+	SENDGRID_API_KEY string
+
+	// SES_API_URL overrides the endpoint of SES_REGION.
+	SES_API_URL           string
+	SES_REGION            string
+	SES_ACCESS_KEY_ID     string
+	SES_SECRET_ACCESS_KEY string
+
+	ANALYTICS_CACHE_TTL   time.Duration
+	ANALYTICS_CONCURRENCY int
+
+	PAYMENT_PROVIDER string
+	PAYMENT_CURRENCY string
+
+	STRIPE_API_URL        string
+	STRIPE_SECRET_KEY     string
+	STRIPE_WEBHOOK_SECRET string
+
+	PAYME_CHECKOUT_URL string
+	PAYME_MERCHANT_ID  string
+	PAYME_KEY          string
+
+	CLICK_API_URL          string
+	CLICK_SERVICE_ID       string
+	CLICK_MERCHANT_ID      string
+	CLICK_MERCHANT_USER_ID string
+	CLICK_SECRET_KEY       string
+
+	GROUP_ORDER_URL string
+
+	WEB_APP_URL    string
+	SHORT_LINK_URL string
+	MENU_URL       string
+	SEO_CACHE_TTL  time.Duration
+	WARM_KITCHENS  int
+	WARM_INTERVAL  time.Duration
+
+	MEAL_PLAN_CHECK_INTERVAL time.Duration
+	MEAL_PLAN_LEAD_TIME      time.Duration
+
+	MODERATION_WORDS_PATH string
+	MODERATION_MAX_LINKS  int
+	MODERATION_API_URL    string
+	MODERATION_API_KEY    string
+
+	INVITATION_SECRET string
+	INVITATION_TTL    time.Duration
+
+	SMS_PROVIDERS       string
+	SMS_CALLBACK_URL    string
+	SMS_CALLBACK_SECRET string
+	SMS_ORDER_STATUS    bool
+
+	TWILIO_API_URL     string
+	TWILIO_ACCOUNT_SID string
+	TWILIO_AUTH_TOKEN  string
+	TWILIO_FROM        string
+
+	PLAYMOBILE_API_URL    string
+	PLAYMOBILE_LOGIN      string
+	PLAYMOBILE_PASSWORD   string
+	PLAYMOBILE_ORIGINATOR string
+
+	ESKIZ_API_URL  string
+	ESKIZ_EMAIL    string
+	ESKIZ_PASSWORD string
+	ESKIZ_FROM     string
+
+	OTP_TTL         time.Duration
+	OTP_RESEND_WAIT time.Duration
+
+	BROADCAST_CHECK_INTERVAL time.Duration
+	BROADCAST_RATE           int
+
+	// ROUTING_PROVIDER is osrm or google; without one distances are
+	// estimated from straight lines.
+	ROUTING_PROVIDER  string
+	ROUTING_CACHE_TTL time.Duration
+	ROUTING_SPEED_KMH float64
+	ROUTING_DETOUR    float64
+	DEFAULT_PREP_TIME time.Duration
+	OSRM_API_URL      string
+	GOOGLE_MAPS_URL   string
+	GOOGLE_MAPS_KEY   string
+
+	ORDER_TIMER_CHECK_INTERVAL time.Duration
+
+	// CDN_PROVIDER is fastly or cloudflare; CDN_SERVICE_ID is the Fastly
+	// service or the Cloudflare zone.
+	CDN_PROVIDER   string
+	CDN_API_URL    string
+	CDN_SERVICE_ID string
+	CDN_API_TOKEN  string
+	CDN_MAX_AGE    time.Duration
 }
 
 func Load() *Config {
@@ -22,10 +265,549 @@ func Load() *Config {
 
 	cfg := Config{}
 
+	cfg.ENVIRONMENT = cast.ToString(coalesce("ENVIRONMENT", "development"))
 	cfg.HTTP_PORT = cast.ToString(coalesce("HTTP_PORT", ":8080"))
 	cfg.AUTH_SERVICE_PORT = cast.ToString(coalesce("AUTH_SERVICE_PORT", ":8081"))
 	cfg.ORDER_SERVICE_PORT = cast.ToString(coalesce("ORDER_SERVICE_PORT", ":8082"))
 
+	// HTTP_READ_TIMEOUT covers reading the whole request, uploads
+	// included, and HTTP_WRITE_TIMEOUT serving it, so it has to outlast
+	// the slowest handler. WebSockets are not bound by either.
+	cfg.HTTP_READ_HEADER_TIMEOUT = cast.ToDuration(coalesce("HTTP_READ_HEADER_TIMEOUT", "10s"))
+	cfg.HTTP_READ_TIMEOUT = cast.ToDuration(coalesce("HTTP_READ_TIMEOUT", "60s"))
+	cfg.HTTP_WRITE_TIMEOUT = cast.ToDuration(coalesce("HTTP_WRITE_TIMEOUT", "90s"))
+	cfg.HTTP_IDLE_TIMEOUT = cast.ToDuration(coalesce("HTTP_IDLE_TIMEOUT", "120s"))
+	cfg.HTTP_MAX_HEADER_BYTES = cast.ToInt(coalesce("HTTP_MAX_HEADER_BYTES", 1<<20))
+
+	// Connections to the services are pinged after GRPC_KEEPALIVE_TIME
+	// without activity and dropped when no answer comes within
+	// GRPC_KEEPALIVE_TIMEOUT. gRPC servers refuse pings more often than
+	// every 5 minutes unless they allow it, so shorter times need the
+	// services to be configured along.
+	cfg.GRPC_MAX_RECV_MSG_SIZE = cast.ToInt(coalesce("GRPC_MAX_RECV_MSG_SIZE", 16<<20))
+	cfg.GRPC_MAX_SEND_MSG_SIZE = cast.ToInt(coalesce("GRPC_MAX_SEND_MSG_SIZE", 16<<20))
+	cfg.GRPC_KEEPALIVE_TIME = cast.ToDuration(coalesce("GRPC_KEEPALIVE_TIME", "5m"))
+	cfg.GRPC_KEEPALIVE_TIMEOUT = cast.ToDuration(coalesce("GRPC_KEEPALIVE_TIMEOUT", "20s"))
+	cfg.GRPC_KEEPALIVE_WITHOUT_CALLS = cast.ToBool(coalesce("GRPC_KEEPALIVE_WITHOUT_CALLS", false))
+
+	// Clients may give requests a budget in X-Timeout-Ms, which the
+	// services are called with, clamped between REQUEST_TIMEOUT_MIN and
+	// REQUEST_TIMEOUT_MAX. The maximum has to stay below
+	// HTTP_WRITE_TIMEOUT for the response to make it out.
+	cfg.REQUEST_TIMEOUT_MIN = cast.ToDuration(coalesce("REQUEST_TIMEOUT_MIN", "100ms"))
+	cfg.REQUEST_TIMEOUT_MAX = cast.ToDuration(coalesce("REQUEST_TIMEOUT_MAX", "30s"))
+
+	// Browsing and search get 503 while more than SHED_MAX_IN_FLIGHT
+	// requests are being served or the services took longer than
+	// SHED_MAX_LATENCY on average over the last second. Zero turns either
+	// check off.
+	cfg.SHED_MAX_IN_FLIGHT = cast.ToInt(coalesce("SHED_MAX_IN_FLIGHT", 512))
+	cfg.SHED_MAX_LATENCY = cast.ToDuration(coalesce("SHED_MAX_LATENCY", "1s"))
+
+	// At most CLASS_*_LIMIT requests of each route class are served at
+	// once; the rest queue, for longer the more important the class, then
+	// get 503. Zero leaves a class unlimited.
+	cfg.CLASS_CHECKOUT_LIMIT = cast.ToInt(coalesce("CLASS_CHECKOUT_LIMIT", 256))
+	cfg.CLASS_ORDER_STATUS_LIMIT = cast.ToInt(coalesce("CLASS_ORDER_STATUS_LIMIT", 256))
+	cfg.CLASS_BROWSE_LIMIT = cast.ToInt(coalesce("CLASS_BROWSE_LIMIT", 384))
+	cfg.CLASS_ANALYTICS_LIMIT = cast.ToInt(coalesce("CLASS_ANALYTICS_LIMIT", 8))
+
+	// Each user may have USER_MAX_IN_FLIGHT requests in flight; more get
+	// 429. Zero turns the cap off.
+	cfg.USER_MAX_IN_FLIGHT = cast.ToInt(coalesce("USER_MAX_IN_FLIGHT", 32))
+
+	// Accounts are scored by the abuse signals they raised in the last
+	// ABUSE_WINDOW: more than ABUSE_VELOCITY_LIMIT requests a minute, bot
+	// or rotating user agents, and orders further apart than
+	// ABUSE_MAX_TRAVEL_KMH allows. At ABUSE_CHALLENGE_SCORE they must pass
+	// an OTP to go on, at ABUSE_SHADOW_BAN_SCORE their reviews are held.
+	// Zero turns a limit off.
+	cfg.ABUSE_WINDOW = cast.ToDuration(coalesce("ABUSE_WINDOW", "1h"))
+	cfg.ABUSE_VELOCITY_LIMIT = cast.ToInt(coalesce("ABUSE_VELOCITY_LIMIT", 300))
+	cfg.ABUSE_MAX_TRAVEL_KMH = cast.ToFloat64(coalesce("ABUSE_MAX_TRAVEL_KMH", 900))
+	cfg.ABUSE_CHALLENGE_SCORE = cast.ToInt(coalesce("ABUSE_CHALLENGE_SCORE", 50))
+	cfg.ABUSE_SHADOW_BAN_SCORE = cast.ToInt(coalesce("ABUSE_SHADOW_BAN_SCORE", 100))
+
+	// Orders and payments are screened for fraud before they are made.
+	// Each rule takes its *_ACTION when it matches: allow only logs it,
+	// verify makes the customer pass an OTP, unless they did in the last
+	// FRAUD_VERIFIED_TTL, and review holds the order or payment for an
+	// admin. The rules match more than FRAUD_ORDERS_PER_HOUR orders or
+	// FRAUD_PAYMENTS_PER_HOUR payment attempts, an order delivered more
+	// than FRAUD_GEO_MISMATCH_KM from the phone's X-Device-Location, and
+	// a first order or payment of FRAUD_FIRST_ORDER_AMOUNT or more, and
+	// every order or payment of customers who charged a payment back. Zero
+	// turns a rule off.
+	cfg.FRAUD_ORDERS_PER_HOUR = cast.ToInt(coalesce("FRAUD_ORDERS_PER_HOUR", 10))
+	cfg.FRAUD_PAYMENTS_PER_HOUR = cast.ToInt(coalesce("FRAUD_PAYMENTS_PER_HOUR", 10))
+	cfg.FRAUD_VELOCITY_ACTION = cast.ToString(coalesce("FRAUD_VELOCITY_ACTION", "verify"))
+	cfg.FRAUD_GEO_MISMATCH_KM = cast.ToFloat64(coalesce("FRAUD_GEO_MISMATCH_KM", 50))
+	cfg.FRAUD_GEO_MISMATCH_ACTION = cast.ToString(coalesce("FRAUD_GEO_MISMATCH_ACTION", "verify"))
+	cfg.FRAUD_FIRST_ORDER_AMOUNT = cast.ToFloat64(coalesce("FRAUD_FIRST_ORDER_AMOUNT", 1000000))
+	cfg.FRAUD_FIRST_ORDER_ACTION = cast.ToString(coalesce("FRAUD_FIRST_ORDER_ACTION", "review"))
+	cfg.FRAUD_CHARGEBACK_ACTION = cast.ToString(coalesce("FRAUD_CHARGEBACK_ACTION", "review"))
+	cfg.FRAUD_VERIFIED_TTL = cast.ToDuration(coalesce("FRAUD_VERIFIED_TTL", "30m"))
+
+	// Each backend service should answer SLO_AVAILABILITY_TARGET of the
+	// gateway's calls without failing and SLO_LATENCY_TARGET of them
+	// within SLO_LATENCY_THRESHOLD, over SLO_WINDOW. Prometheus scrapes
+	// the metrics at METRICS_PATH, which empty leaves unserved.
+	cfg.SLO_AVAILABILITY_TARGET = cast.ToFloat64(coalesce("SLO_AVAILABILITY_TARGET", 0.999))
+	cfg.SLO_LATENCY_TARGET = cast.ToFloat64(coalesce("SLO_LATENCY_TARGET", 0.99))
+	cfg.SLO_LATENCY_THRESHOLD = cast.ToDuration(coalesce("SLO_LATENCY_THRESHOLD", "500ms"))
+	cfg.SLO_WINDOW = cast.ToDuration(coalesce("SLO_WINDOW", "720h"))
+	cfg.METRICS_PATH = cast.ToString(coalesce("METRICS_PATH", "/metrics"))
+
+	// Requests sending X-Debug-Timing get the timing of their backend
+	// calls back while DEBUG_TIMING is on. It tells which methods of the
+	// services are called, so it is off unless developers need it.
+	cfg.DEBUG_TIMING = cast.ToBool(coalesce("DEBUG_TIMING", false))
+	// Every response tells where its time went in a Server-Timing header,
+	// which browser devtools show, while SERVER_TIMING is on.
+	cfg.SERVER_TIMING = cast.ToBool(coalesce("SERVER_TIMING", false))
+
+	// Calls are counted by route and app version for a day, and the days
+	// kept for USAGE_RETENTION_DAYS.
+	cfg.USAGE_RETENTION_DAYS = cast.ToInt(coalesce("USAGE_RETENTION_DAYS", 90))
+
+	// Partners sync the kitchens and dishes changed in the last
+	// PARTNER_DELTA_RETENTION_DAYS; older syncs need a full crawl.
+	cfg.PARTNER_DELTA_RETENTION_DAYS = cast.ToInt(coalesce("PARTNER_DELTA_RETENTION_DAYS", 30))
+
+	// With SANDBOX_HEADER any caller may send X-Sandbox: true to place
+	// test orders and pay them with fake money, so it is meant for
+	// staging. Sandbox partner keys work without it.
+	cfg.SANDBOX_HEADER = cast.ToBool(coalesce("SANDBOX_HEADER", false))
+
+	// Admins record up to RECORDING_MAX_MINUTES of traffic to replay on
+	// staging, keeping the first RECORDING_MAX_REQUESTS requests with
+	// JSON bodies of up to RECORDING_MAX_BODY bytes.
+	cfg.RECORDING_MAX_MINUTES = cast.ToInt(coalesce("RECORDING_MAX_MINUTES", 60))
+	cfg.RECORDING_MAX_REQUESTS = cast.ToInt(coalesce("RECORDING_MAX_REQUESTS", 10000))
+	cfg.RECORDING_MAX_BODY = cast.ToInt(coalesce("RECORDING_MAX_BODY", 64<<10))
+
+	// While RETENTION_ENABLED, every RETENTION_CHECK_INTERVAL orders and
+	// payments older than RETENTION_RECORD_DAYS are anonymized in the
+	// services, and the emails, SMS, webhook deliveries and search history
+	// the gateway logged more than RETENTION_LOG_DAYS ago are purged.
+	cfg.RETENTION_ENABLED = cast.ToBool(coalesce("RETENTION_ENABLED", false))
+	cfg.RETENTION_RECORD_DAYS = cast.ToInt(coalesce("RETENTION_RECORD_DAYS", 1095))
+	cfg.RETENTION_LOG_DAYS = cast.ToInt(coalesce("RETENTION_LOG_DAYS", 90))
+	cfg.RETENTION_CHECK_INTERVAL = cast.ToDuration(coalesce("RETENTION_CHECK_INTERVAL", "24h"))
+
+	// Apps older than these versions are asked to upgrade from their
+	// store, until admins change them in the settings. Empty serves every
+	// version.
+	cfg.MIN_IOS_VERSION = cast.ToString(coalesce("MIN_IOS_VERSION", ""))
+	cfg.MIN_ANDROID_VERSION = cast.ToString(coalesce("MIN_ANDROID_VERSION", ""))
+	cfg.MIN_WEB_VERSION = cast.ToString(coalesce("MIN_WEB_VERSION", ""))
+	cfg.IOS_STORE_URL = cast.ToString(coalesce("IOS_STORE_URL", ""))
+	cfg.ANDROID_STORE_URL = cast.ToString(coalesce("ANDROID_STORE_URL", ""))
+
+	// Requests are served in the locale of their Accept-Language, one of
+	// uz, ru and en, or else in DEFAULT_LOCALE.
+	cfg.DEFAULT_LOCALE = cast.ToString(coalesce("DEFAULT_LOCALE", "en"))
+	// Kitchens' working hours, statistics and scheduled orders are in
+	// their own time zone, or else in DEFAULT_TIME_ZONE, an IANA name.
+	cfg.DEFAULT_TIME_ZONE = cast.ToString(coalesce("DEFAULT_TIME_ZONE", "UTC"))
+
+	cfg.DELIVERY_FEE = cast.ToFloat32(coalesce("DELIVERY_FEE", 0))
+	cfg.DELIVERY_FEE_PER_KM = cast.ToFloat32(coalesce("DELIVERY_FEE_PER_KM", 0))
+	cfg.SERVICE_FEE_PERCENT = cast.ToFloat32(coalesce("SERVICE_FEE_PERCENT", 0))
+	cfg.TAX_PERCENT = cast.ToFloat32(coalesce("TAX_PERCENT", 0))
+
+	cfg.KITCHEN_COMMISSION_PERCENT = cast.ToFloat32(coalesce("KITCHEN_COMMISSION_PERCENT", 0))
+
+	cfg.EVENT_BROKER = cast.ToString(coalesce("EVENT_BROKER", ""))
+	cfg.EVENT_BROKER_URL = cast.ToString(coalesce("EVENT_BROKER_URL", "localhost:9092"))
+	cfg.EVENT_TOPIC = cast.ToString(coalesce("EVENT_TOPIC", "local-eats.orders"))
+
+	cfg.OUTBOX_PATH = cast.ToString(coalesce("OUTBOX_PATH", "outbox.db"))
+	cfg.OUTBOX_RETRY_INTERVAL = cast.ToDuration(coalesce("OUTBOX_RETRY_INTERVAL", "10s"))
+
+	cfg.SWAGGER_ENABLED = cast.ToBool(coalesce("SWAGGER_ENABLED", cfg.ENVIRONMENT != "production"))
+	cfg.SWAGGER_AUTH = cast.ToString(coalesce("SWAGGER_AUTH", "none"))
+	cfg.SWAGGER_USER = cast.ToString(coalesce("SWAGGER_USER", ""))
+	cfg.SWAGGER_PASSWORD = cast.ToString(coalesce("SWAGGER_PASSWORD", ""))
+	cfg.SWAGGER_HOST = cast.ToString(coalesce("SWAGGER_HOST", ""))
+	cfg.SWAGGER_SCHEMES = cast.ToString(coalesce("SWAGGER_SCHEMES", "http"))
+
+	cfg.OPENAPI_SNAPSHOT_DIR = cast.ToString(coalesce("OPENAPI_SNAPSHOT_DIR", "openapi"))
+
+	cfg.RESPONSE_PROTO_NAMES = cast.ToBool(coalesce("RESPONSE_PROTO_NAMES", true))
+	cfg.RESPONSE_ENUM_NUMBERS = cast.ToBool(coalesce("RESPONSE_ENUM_NUMBERS", false))
+	cfg.RESPONSE_EMIT_UNPOPULATED = cast.ToBool(coalesce("RESPONSE_EMIT_UNPOPULATED", false))
+	cfg.DEFAULT_API_FORMAT = cast.ToString(coalesce("DEFAULT_API_FORMAT", "legacy"))
+	cfg.RESPONSE_ENVELOPE = cast.ToBool(coalesce("RESPONSE_ENVELOPE", false))
+	// Large lists are encoded and sent an item at a time rather than
+	// assembled in memory first. 0 turns streaming off.
+	cfg.RESPONSE_STREAM_MIN_ITEMS = cast.ToInt(coalesce("RESPONSE_STREAM_MIN_ITEMS", 200))
+
+	cfg.UPLOAD_DIR = cast.ToString(coalesce("UPLOAD_DIR", "uploads"))
+	cfg.UPLOAD_MAX_SIZE = cast.ToInt64(coalesce("UPLOAD_MAX_SIZE", 20<<20))
+
+	cfg.IMAGE_STORAGE_URL = cast.ToString(coalesce("IMAGE_STORAGE_URL", "http://localhost:9000/local-eats"))
+	cfg.IMAGE_MAX_DIMENSION = cast.ToInt(coalesce("IMAGE_MAX_DIMENSION", 2048))
+	cfg.IMAGE_CACHE_MAX_AGE = cast.ToDuration(coalesce("IMAGE_CACHE_MAX_AGE", "24h"))
+
+	cfg.REPORT_DIR = cast.ToString(coalesce("REPORT_DIR", "reports"))
+	cfg.REPORT_CHECK_INTERVAL = cast.ToDuration(coalesce("REPORT_CHECK_INTERVAL", "1m"))
+
+	// Data packages compiled for data-access requests are kept encrypted
+	// in DATA_REQUEST_DIR for DATA_REQUEST_TTL.
+	cfg.DATA_REQUEST_DIR = cast.ToString(coalesce("DATA_REQUEST_DIR", "data-requests"))
+	cfg.DATA_REQUEST_TTL = cast.ToDuration(coalesce("DATA_REQUEST_TTL", "720h"))
+
+	// The personal data the gateway keeps, in memory and in the outbox, is
+	// encrypted under data keys wrapped by the keys of
+	// ENCRYPTION_KEY_PROVIDER: the last key of ENCRYPTION_KEY_FILE, or the
+	// KMS key ENCRYPTION_KMS_KEY_ID.
+	cfg.ENCRYPTION_KEY_PROVIDER = cast.ToString(coalesce("ENCRYPTION_KEY_PROVIDER", ""))
+	cfg.ENCRYPTION_KEY_FILE = cast.ToString(coalesce("ENCRYPTION_KEY_FILE", ""))
+	cfg.ENCRYPTION_KMS_API_URL = cast.ToString(coalesce("ENCRYPTION_KMS_API_URL", ""))
+	cfg.ENCRYPTION_KMS_REGION = cast.ToString(coalesce("ENCRYPTION_KMS_REGION", "us-east-1"))
+	cfg.ENCRYPTION_KMS_KEY_ID = cast.ToString(coalesce("ENCRYPTION_KMS_KEY_ID", ""))
+	cfg.ENCRYPTION_KMS_ACCESS_KEY_ID = cast.ToString(coalesce("ENCRYPTION_KMS_ACCESS_KEY_ID", ""))
+	cfg.ENCRYPTION_KMS_SECRET_ACCESS_KEY = cast.ToString(coalesce("ENCRYPTION_KMS_SECRET_ACCESS_KEY", ""))
+
+	cfg.EMAIL_DRIVER = cast.ToString(coalesce("EMAIL_DRIVER", "smtp"))
+	cfg.EMAIL_FROM = cast.ToString(coalesce("EMAIL_FROM", "Local Eats <noreply@localhost>"))
+	cfg.EMAIL_WORKERS = cast.ToInt(coalesce("EMAIL_WORKERS", 4))
+	cfg.EMAIL_MAX_ATTEMPTS = cast.ToInt(coalesce("EMAIL_MAX_ATTEMPTS", 5))
+	cfg.EMAIL_RETRY_BACKOFF = cast.ToDuration(coalesce("EMAIL_RETRY_BACKOFF", "30s"))
+	cfg.ADMIN_ALERT_EMAILS = cast.ToString(coalesce("ADMIN_ALERT_EMAILS", ""))
+
+	cfg.SMTP_ADDR = cast.ToString(coalesce("SMTP_ADDR", ""))
+	cfg.SMTP_USER = cast.ToString(coalesce("SMTP_USER", ""))
+	cfg.SMTP_PASSWORD = cast.ToString(coalesce("SMTP_PASSWORD", ""))
+
+	cfg.SENDGRID_API_URL = cast.ToString(coalesce("SENDGRID_API_URL", "https://api.sendgrid.com"))
+	cfg.SENDGRID_API_KEY = cast.ToString(coalesce("SENDGRID_API_KEY", ""))
+
+	cfg.SES_API_URL = cast.ToString(coalesce("SES_API_URL", ""))
+	cfg.SES_REGION = cast.ToString(coalesce("SES_REGION", "us-east-1"))
+	cfg.SES_ACCESS_KEY_ID = cast.ToString(coalesce("SES_ACCESS_KEY_ID", ""))
+	cfg.SES_SECRET_ACCESS_KEY = cast.ToString(coalesce("SES_SECRET_ACCESS_KEY", ""))
+
+	cfg.ANALYTICS_CACHE_TTL = cast.ToDuration(coalesce("ANALYTICS_CACHE_TTL", "5m"))
+	cfg.ANALYTICS_CONCURRENCY = cast.ToInt(coalesce("ANALYTICS_CONCURRENCY", 8))
+
+	cfg.PAYMENT_PROVIDER = cast.ToString(coalesce("PAYMENT_PROVIDER", "internal"))
+	cfg.PAYMENT_CURRENCY = cast.ToString(coalesce("PAYMENT_CURRENCY", "UZS"))
+
+	cfg.STRIPE_API_URL = cast.ToString(coalesce("STRIPE_API_URL", "https://api.stripe.com"))
+	cfg.STRIPE_SECRET_KEY = cast.ToString(coalesce("STRIPE_SECRET_KEY", ""))
+	cfg.STRIPE_WEBHOOK_SECRET = cast.ToString(coalesce("STRIPE_WEBHOOK_SECRET", ""))
+
+	cfg.PAYME_CHECKOUT_URL = cast.ToString(coalesce("PAYME_CHECKOUT_URL", "https://checkout.paycom.uz"))
+	cfg.PAYME_MERCHANT_ID = cast.ToString(coalesce("PAYME_MERCHANT_ID", ""))
+	cfg.PAYME_KEY = cast.ToString(coalesce("PAYME_KEY", ""))
+
+	cfg.CLICK_API_URL = cast.ToString(coalesce("CLICK_API_URL", "https://api.click.uz/v2/merchant"))
+	cfg.CLICK_SERVICE_ID = cast.ToString(coalesce("CLICK_SERVICE_ID", ""))
+	cfg.CLICK_MERCHANT_ID = cast.ToString(coalesce("CLICK_MERCHANT_ID", ""))
+	cfg.CLICK_MERCHANT_USER_ID = cast.ToString(coalesce("CLICK_MERCHANT_USER_ID", ""))
+	cfg.CLICK_SECRET_KEY = cast.ToString(coalesce("CLICK_SECRET_KEY", ""))
+
+	// Share links point to the web app page that joins a group order.
+	cfg.GROUP_ORDER_URL = cast.ToString(coalesce("GROUP_ORDER_URL", "http://localhost:3000/group"))
+
+	// Short links redirect from SHORT_LINK_URL/{code} to kitchen and dish
+	// pages of the web app.
+	cfg.WEB_APP_URL = cast.ToString(coalesce("WEB_APP_URL", "http://localhost:3000"))
+	cfg.SHORT_LINK_URL = cast.ToString(coalesce("SHORT_LINK_URL", "http://localhost:8080/l"))
+	// Menu QR codes open MENU_URL/{kitchen_id}, the menu page the gateway
+	// renders itself so scanning works without the app.
+	cfg.MENU_URL = cast.ToString(coalesce("MENU_URL", "http://localhost:8080/m"))
+	cfg.SEO_CACHE_TTL = cast.ToDuration(coalesce("SEO_CACHE_TTL", "1h"))
+	// The pages and menus of the WARM_KITCHENS most popular kitchens are
+	// cached on startup and refreshed every WARM_INTERVAL, which should be
+	// below SEO_CACHE_TTL. Zero kitchens turns warming off.
+	cfg.WARM_KITCHENS = cast.ToInt(coalesce("WARM_KITCHENS", 50))
+	cfg.WARM_INTERVAL = cast.ToDuration(coalesce("WARM_INTERVAL", "50m"))
+
+	// Meal plan orders are placed MEAL_PLAN_LEAD_TIME before delivery so
+	// kitchens have time to prepare them.
+	cfg.MEAL_PLAN_CHECK_INTERVAL = cast.ToDuration(coalesce("MEAL_PLAN_CHECK_INTERVAL", "1m"))
+	cfg.MEAL_PLAN_LEAD_TIME = cast.ToDuration(coalesce("MEAL_PLAN_LEAD_TIME", "12h"))
+
+	// The words file maps languages to profanity lists, e.g.
+	// {"en": ["..."], "ru": ["..."]}. Reviews are also sent to the
+	// moderation API when its URL is set.
+	cfg.MODERATION_WORDS_PATH = cast.ToString(coalesce("MODERATION_WORDS_PATH", ""))
+	cfg.MODERATION_MAX_LINKS = cast.ToInt(coalesce("MODERATION_MAX_LINKS", 0))
+	cfg.MODERATION_API_URL = cast.ToString(coalesce("MODERATION_API_URL", ""))
+	cfg.MODERATION_API_KEY = cast.ToString(coalesce("MODERATION_API_KEY", ""))
+
+	// Invitation links open WEB_APP_URL/register with a token signed with
+	// INVITATION_SECRET.
+	cfg.INVITATION_SECRET = cast.ToString(coalesce("INVITATION_SECRET", ""))
+	cfg.INVITATION_TTL = cast.ToDuration(coalesce("INVITATION_TTL", "168h"))
+
+	// SMS_PROVIDERS lists the SMS gateways to try in order, e.g.
+	// "eskiz,twilio"; by default every configured one is used. Gateways
+	// post delivery reports to SMS_CALLBACK_URL/{provider}, and those
+	// without signatures must pass SMS_CALLBACK_SECRET.
+	cfg.SMS_PROVIDERS = cast.ToString(coalesce("SMS_PROVIDERS", ""))
+	cfg.SMS_CALLBACK_URL = cast.ToString(coalesce("SMS_CALLBACK_URL", "http://localhost:8080/local-eats/sms/callbacks"))
+	cfg.SMS_CALLBACK_SECRET = cast.ToString(coalesce("SMS_CALLBACK_SECRET", ""))
+	cfg.SMS_ORDER_STATUS = cast.ToBool(coalesce("SMS_ORDER_STATUS", true))
+
+	cfg.TWILIO_API_URL = cast.ToString(coalesce("TWILIO_API_URL", "https://api.twilio.com"))
+	cfg.TWILIO_ACCOUNT_SID = cast.ToString(coalesce("TWILIO_ACCOUNT_SID", ""))
+	cfg.TWILIO_AUTH_TOKEN = cast.ToString(coalesce("TWILIO_AUTH_TOKEN", ""))
+	cfg.TWILIO_FROM = cast.ToString(coalesce("TWILIO_FROM", ""))
+
+	cfg.PLAYMOBILE_API_URL = cast.ToString(coalesce("PLAYMOBILE_API_URL", "https://send.smsxabar.uz/broker-api"))
+	cfg.PLAYMOBILE_LOGIN = cast.ToString(coalesce("PLAYMOBILE_LOGIN", ""))
+	cfg.PLAYMOBILE_PASSWORD = cast.ToString(coalesce("PLAYMOBILE_PASSWORD", ""))
+	cfg.PLAYMOBILE_ORIGINATOR = cast.ToString(coalesce("PLAYMOBILE_ORIGINATOR", "3700"))
+
+	cfg.ESKIZ_API_URL = cast.ToString(coalesce("ESKIZ_API_URL", "https://notify.eskiz.uz/api"))
+	cfg.ESKIZ_EMAIL = cast.ToString(coalesce("ESKIZ_EMAIL", ""))
+	cfg.ESKIZ_PASSWORD = cast.ToString(coalesce("ESKIZ_PASSWORD", ""))
+	cfg.ESKIZ_FROM = cast.ToString(coalesce("ESKIZ_FROM", "4546"))
+
+	cfg.OTP_TTL = cast.ToDuration(coalesce("OTP_TTL", "5m"))
+	cfg.OTP_RESEND_WAIT = cast.ToDuration(coalesce("OTP_RESEND_WAIT", "1m"))
+
+	// Broadcasts reach at most BROADCAST_RATE users a second, shared by
+	// every broadcast being sent.
+	cfg.BROADCAST_CHECK_INTERVAL = cast.ToDuration(coalesce("BROADCAST_CHECK_INTERVAL", "1m"))
+	cfg.BROADCAST_RATE = cast.ToInt(coalesce("BROADCAST_RATE", 20))
+
+	// Road distances from kitchens to delivery zones are cached for
+	// ROUTING_CACHE_TTL. Straight line distances are stretched by
+	// ROUTING_DETOUR for the roads and driven at ROUTING_SPEED_KMH.
+	// Kitchens without a preparation lead time are taken to need
+	// DEFAULT_PREP_TIME.
+	cfg.ROUTING_PROVIDER = cast.ToString(coalesce("ROUTING_PROVIDER", ""))
+	cfg.ROUTING_CACHE_TTL = cast.ToDuration(coalesce("ROUTING_CACHE_TTL", "6h"))
+	cfg.ROUTING_SPEED_KMH = cast.ToFloat64(coalesce("ROUTING_SPEED_KMH", 25))
+	cfg.ROUTING_DETOUR = cast.ToFloat64(coalesce("ROUTING_DETOUR", 1.3))
+	cfg.DEFAULT_PREP_TIME = cast.ToDuration(coalesce("DEFAULT_PREP_TIME", "20m"))
+	cfg.OSRM_API_URL = cast.ToString(coalesce("OSRM_API_URL", "https://router.project-osrm.org"))
+	cfg.GOOGLE_MAPS_URL = cast.ToString(coalesce("GOOGLE_MAPS_URL", "https://maps.googleapis.com"))
+	cfg.GOOGLE_MAPS_KEY = cast.ToString(coalesce("GOOGLE_MAPS_KEY", ""))
+
+	cfg.ORDER_TIMER_CHECK_INTERVAL = cast.ToDuration(coalesce("ORDER_TIMER_CHECK_INTERVAL", "30s"))
+
+	// Responses the edge caches are purged when they change; CDN_MAX_AGE
+	// bounds how stale they get when a purge fails or the change, such as
+	// a discount starting, has no request of its own. CDN_API_URL
+	// defaults to the provider's API.
+	cfg.CDN_PROVIDER = cast.ToString(coalesce("CDN_PROVIDER", ""))
+	cfg.CDN_API_URL = cast.ToString(coalesce("CDN_API_URL", ""))
+	cfg.CDN_SERVICE_ID = cast.ToString(coalesce("CDN_SERVICE_ID", ""))
+	cfg.CDN_API_TOKEN = cast.ToString(coalesce("CDN_API_TOKEN", ""))
+	cfg.CDN_MAX_AGE = cast.ToDuration(coalesce("CDN_MAX_AGE", "5m"))
+
+	if cfg.DEFAULT_API_FORMAT != "legacy" && cfg.DEFAULT_API_FORMAT != "standard" {
+		log.Fatalf("unknown DEFAULT_API_FORMAT %q", cfg.DEFAULT_API_FORMAT)
+	}
+
+	switch cfg.SWAGGER_AUTH {
+	case "none", "admin":
+	case "basic":
+		if cfg.SWAGGER_USER == "" || cfg.SWAGGER_PASSWORD == "" {
+			log.Fatalf("SWAGGER_USER and SWAGGER_PASSWORD are required for basic swagger auth")
+		}
+	default:
+		log.Fatalf("unknown SWAGGER_AUTH %q", cfg.SWAGGER_AUTH)
+	}
+
+	for name, d := range map[string]time.Duration{
+		"HTTP_READ_HEADER_TIMEOUT": cfg.HTTP_READ_HEADER_TIMEOUT,
+		"HTTP_READ_TIMEOUT":        cfg.HTTP_READ_TIMEOUT,
+		"HTTP_WRITE_TIMEOUT":       cfg.HTTP_WRITE_TIMEOUT,
+		"HTTP_IDLE_TIMEOUT":        cfg.HTTP_IDLE_TIMEOUT,
+		"GRPC_KEEPALIVE_TIMEOUT":   cfg.GRPC_KEEPALIVE_TIMEOUT,
+	} {
+		if d <= 0 {
+			log.Fatalf("%s must be positive", name)
+		}
+	}
+	if cfg.HTTP_READ_HEADER_TIMEOUT > cfg.HTTP_READ_TIMEOUT {
+		log.Fatalf("HTTP_READ_HEADER_TIMEOUT must not exceed HTTP_READ_TIMEOUT")
+	}
+	if cfg.HTTP_MAX_HEADER_BYTES < 4<<10 || cfg.HTTP_MAX_HEADER_BYTES > 16<<20 {
+		log.Fatalf("HTTP_MAX_HEADER_BYTES must be between 4KB and 16MB")
+	}
+	for name, n := range map[string]int{
+		"GRPC_MAX_RECV_MSG_SIZE": cfg.GRPC_MAX_RECV_MSG_SIZE,
+		"GRPC_MAX_SEND_MSG_SIZE": cfg.GRPC_MAX_SEND_MSG_SIZE,
+	} {
+		if n < 1<<20 || n > 1<<30 {
+			log.Fatalf("%s must be between 1MB and 1GB", name)
+		}
+	}
+	if cfg.REQUEST_TIMEOUT_MIN <= 0 || cfg.REQUEST_TIMEOUT_MIN > cfg.REQUEST_TIMEOUT_MAX {
+		log.Fatalf("REQUEST_TIMEOUT_MIN must be positive and at most REQUEST_TIMEOUT_MAX")
+	}
+	if cfg.REQUEST_TIMEOUT_MAX >= cfg.HTTP_WRITE_TIMEOUT {
+		log.Fatalf("REQUEST_TIMEOUT_MAX must be below HTTP_WRITE_TIMEOUT")
+	}
+	if cfg.SHED_MAX_IN_FLIGHT < 0 || cfg.SHED_MAX_LATENCY < 0 {
+		log.Fatalf("SHED_MAX_IN_FLIGHT and SHED_MAX_LATENCY must not be negative")
+	}
+	if cfg.CLASS_CHECKOUT_LIMIT < 0 || cfg.CLASS_ORDER_STATUS_LIMIT < 0 ||
+		cfg.CLASS_BROWSE_LIMIT < 0 || cfg.CLASS_ANALYTICS_LIMIT < 0 {
+		log.Fatalf("CLASS_*_LIMIT must not be negative")
+	}
+	if cfg.USER_MAX_IN_FLIGHT < 0 {
+		log.Fatalf("USER_MAX_IN_FLIGHT must not be negative")
+	}
+	// Signals are flagged at most every 10 minutes, so a shorter window
+	// could never add them up.
+	if cfg.ABUSE_WINDOW < 10*time.Minute {
+		log.Fatalf("ABUSE_WINDOW must be at least 10m")
+	}
+	if cfg.ABUSE_VELOCITY_LIMIT < 0 || cfg.ABUSE_MAX_TRAVEL_KMH < 0 ||
+		cfg.ABUSE_CHALLENGE_SCORE < 0 || cfg.ABUSE_SHADOW_BAN_SCORE < 0 {
+		log.Fatalf("ABUSE_* limits must not be negative")
+	}
+	if cfg.FRAUD_ORDERS_PER_HOUR < 0 || cfg.FRAUD_PAYMENTS_PER_HOUR < 0 ||
+		cfg.FRAUD_GEO_MISMATCH_KM < 0 || cfg.FRAUD_FIRST_ORDER_AMOUNT < 0 || cfg.FRAUD_VERIFIED_TTL < 0 {
+		log.Fatalf("FRAUD_* limits must not be negative")
+	}
+	// Payment webhooks are verified with the gateway's secret, so a gateway
+	// set up without one would take anyone's word that a payment succeeded.
+	if cfg.STRIPE_SECRET_KEY != "" && cfg.STRIPE_WEBHOOK_SECRET == "" {
+		log.Fatalf("STRIPE_WEBHOOK_SECRET is required with STRIPE_SECRET_KEY")
+	}
+	if cfg.PAYME_MERCHANT_ID != "" && cfg.PAYME_KEY == "" {
+		log.Fatalf("PAYME_KEY is required with PAYME_MERCHANT_ID")
+	}
+	if cfg.CLICK_SERVICE_ID != "" && cfg.CLICK_SECRET_KEY == "" {
+		log.Fatalf("CLICK_SECRET_KEY is required with CLICK_SERVICE_ID")
+	}
+	for name, action := range map[string]string{
+		"FRAUD_VELOCITY_ACTION":     cfg.FRAUD_VELOCITY_ACTION,
+		"FRAUD_GEO_MISMATCH_ACTION": cfg.FRAUD_GEO_MISMATCH_ACTION,
+		"FRAUD_FIRST_ORDER_ACTION":  cfg.FRAUD_FIRST_ORDER_ACTION,
+		"FRAUD_CHARGEBACK_ACTION":   cfg.FRAUD_CHARGEBACK_ACTION,
+	} {
+		if action != "allow" && action != "verify" && action != "review" {
+			log.Fatalf("%s must be allow, verify or review", name)
+		}
+	}
+	for name, target := range map[string]float64{
+		"SLO_AVAILABILITY_TARGET": cfg.SLO_AVAILABILITY_TARGET,
+		"SLO_LATENCY_TARGET":      cfg.SLO_LATENCY_TARGET,
+	} {
+		if target <= 0 || target >= 1 {
+			log.Fatalf("%s must be between 0 and 1", name)
+		}
+	}
+	if cfg.SLO_LATENCY_THRESHOLD <= 0 {
+		log.Fatalf("SLO_LATENCY_THRESHOLD must be positive")
+	}
+	// Calls are counted by the hour over the window.
+	if cfg.SLO_WINDOW < time.Hour || cfg.SLO_WINDOW > 90*24*time.Hour {
+		log.Fatalf("SLO_WINDOW must be between 1h and 2160h")
+	}
+	if cfg.METRICS_PATH != "" && !strings.HasPrefix(cfg.METRICS_PATH, "/") {
+		log.Fatalf("METRICS_PATH must start with /")
+	}
+	if cfg.USAGE_RETENTION_DAYS < 1 || cfg.USAGE_RETENTION_DAYS > 400 {
+		log.Fatalf("USAGE_RETENTION_DAYS must be between 1 and 400")
+	}
+	if cfg.PARTNER_DELTA_RETENTION_DAYS < 1 || cfg.PARTNER_DELTA_RETENTION_DAYS > 400 {
+		log.Fatalf("PARTNER_DELTA_RETENTION_DAYS must be between 1 and 400")
+	}
+	if cfg.RECORDING_MAX_MINUTES < 1 || cfg.RECORDING_MAX_REQUESTS < 1 || cfg.RECORDING_MAX_BODY < 0 {
+		log.Fatalf("RECORDING_MAX_MINUTES and RECORDING_MAX_REQUESTS must be positive and RECORDING_MAX_BODY not negative")
+	}
+	// Orders are kept at least as long as refunds and chargebacks may
+	// come in.
+	if cfg.RETENTION_RECORD_DAYS < 180 {
+		log.Fatalf("RETENTION_RECORD_DAYS must be at least 180")
+	}
+	if cfg.RETENTION_LOG_DAYS < 1 || cfg.RETENTION_LOG_DAYS > cfg.RETENTION_RECORD_DAYS {
+		log.Fatalf("RETENTION_LOG_DAYS must be between 1 and RETENTION_RECORD_DAYS")
+	}
+	if cfg.DATA_REQUEST_TTL < time.Hour {
+		log.Fatalf("DATA_REQUEST_TTL must be at least 1h")
+	}
+	if cfg.RETENTION_CHECK_INTERVAL < time.Minute {
+		log.Fatalf("RETENTION_CHECK_INTERVAL must be at least 1m")
+	}
+	if cfg.RESPONSE_STREAM_MIN_ITEMS < 0 {
+		log.Fatalf("RESPONSE_STREAM_MIN_ITEMS must not be negative")
+	}
+	switch cfg.ENCRYPTION_KEY_PROVIDER {
+	case "":
+	case "file":
+		if cfg.ENCRYPTION_KEY_FILE == "" {
+			log.Fatalf("ENCRYPTION_KEY_FILE must be set for the file key provider")
+		}
+	case "aws-kms":
+		if cfg.ENCRYPTION_KMS_KEY_ID == "" {
+			log.Fatalf("ENCRYPTION_KMS_KEY_ID must be set for the aws-kms key provider")
+		}
+	default:
+		log.Fatalf("ENCRYPTION_KEY_PROVIDER must be file or aws-kms")
+	}
+	for name, v := range map[string]string{
+		"MIN_IOS_VERSION":     cfg.MIN_IOS_VERSION,
+		"MIN_ANDROID_VERSION": cfg.MIN_ANDROID_VERSION,
+		"MIN_WEB_VERSION":     cfg.MIN_WEB_VERSION,
+	} {
+		if v != "" && !version.Valid(v) {
+			log.Fatalf("%s must be a version such as 2.3.1", name)
+		}
+	}
+	switch cfg.DEFAULT_LOCALE {
+	case "uz", "ru", "en":
+	default:
+		log.Fatalf("DEFAULT_LOCALE must be uz, ru or en")
+	}
+	if _, err := time.LoadLocation(cfg.DEFAULT_TIME_ZONE); err != nil || cfg.DEFAULT_TIME_ZONE == "" {
+		log.Fatalf("DEFAULT_TIME_ZONE must be an IANA time zone such as Asia/Tashkent")
+	}
+	// gRPC raises shorter times to 10 seconds anyway.
+	if cfg.GRPC_KEEPALIVE_TIME < 10*time.Second {
+		log.Fatalf("GRPC_KEEPALIVE_TIME must be at least 10s")
+	}
+
+	switch cfg.PAYMENT_PROVIDER {
+	case "internal":
+	case "stripe":
+		if cfg.STRIPE_SECRET_KEY == "" {
+			log.Fatalf("STRIPE_SECRET_KEY is required for the stripe payment provider")
+		}
+	case "payme":
+		if cfg.PAYME_MERCHANT_ID == "" {
+			log.Fatalf("PAYME_MERCHANT_ID is required for the payme payment provider")
+		}
+	case "click":
+		if cfg.CLICK_SERVICE_ID == "" {
+			log.Fatalf("CLICK_SERVICE_ID is required for the click payment provider")
+		}
+	default:
+		log.Fatalf("unknown PAYMENT_PROVIDER %q", cfg.PAYMENT_PROVIDER)
+	}
+
+	switch cfg.ROUTING_PROVIDER {
+	case "", "osrm":
+	case "google":
+		if cfg.GOOGLE_MAPS_KEY == "" {
+			log.Fatalf("GOOGLE_MAPS_KEY is required for the google routing provider")
+		}
+	default:
+		log.Fatalf("unknown ROUTING_PROVIDER %q", cfg.ROUTING_PROVIDER)
+	}
+
+	switch cfg.CDN_PROVIDER {
+	case "":
+	case "fastly", "cloudflare":
+		if cfg.CDN_SERVICE_ID == "" || cfg.CDN_API_TOKEN == "" {
+			log.Fatalf("CDN_SERVICE_ID and CDN_API_TOKEN are required for the %s CDN provider", cfg.CDN_PROVIDER)
+		}
+	default:
+		log.Fatalf("unknown CDN_PROVIDER %q", cfg.CDN_PROVIDER)
+	}
+
 	return &cfg
 }
 
@@ -0,0 +1,56 @@
+package models
+
+// Signals of abuse a request or order can raise.
+const (
+	SignalVelocity         = "velocity"
+	SignalNoUserAgent      = "no_user_agent"
+	SignalBotUserAgent     = "bot_user_agent"
+	SignalUserAgentChurn   = "user_agent_churn"
+	SignalImpossibleTravel = "impossible_travel"
+)
+
+// Abuse statuses, from the mildest. Challenged accounts must verify a
+// phone with an OTP before they go on; shadow-banned ones go on as usual
+// but what they write is held from others.
+const (
+	AbuseClear     = "clear"
+	AbuseChallenge = "challenge"
+	AbuseShadowBan = "shadow_ban"
+)
+
+// AbuseFlag is a signal an account raised, worth Weight to its score.
+type AbuseFlag struct {
+	Signal    string `json:"signal"`
+	Detail    string `json:"detail"`
+	Weight    int    `json:"weight"`
+	RequestId string `json:"request_id,omitempty"`
+	At        string `json:"at"`
+}
+
+// AbuseProfile is how suspicious an account is. The score adds up the
+// flags of the last window and sets the status, unless an admin set it.
+type AbuseProfile struct {
+	UserId string      `json:"user_id"`
+	Score  int         `json:"score"`
+	Status string      `json:"status"`
+	Flags  []AbuseFlag `json:"flags"`
+	// Manual tells whether the status was set by an admin, who keeps it
+	// until setting it back to automatic.
+	Manual     bool   `json:"manual"`
+	Note       string `json:"note,omitempty"`
+	ReviewedBy string `json:"reviewed_by,omitempty"`
+	ReviewedAt string `json:"reviewed_at,omitempty"`
+	// PassedAt is when the account last passed a challenge.
+	PassedAt  string `json:"passed_at,omitempty"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type AbuseProfiles struct {
+	Profiles []AbuseProfile `json:"profiles"`
+}
+
+// AbuseReview sets an account's status; auto hands it back to scoring.
+type AbuseReview struct {
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
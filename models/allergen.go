@@ -0,0 +1,96 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// Allergens are the allergen tags dishes and allergy profiles are
+// declared with, the fourteen major food allergens.
+var Allergens = []string{
+	"celery", "crustaceans", "eggs", "fish", "gluten", "lupin", "milk",
+	"molluscs", "mustard", "peanuts", "sesame", "soy", "sulphites", "tree_nuts",
+}
+
+// allergenAliases are other spellings accepted for the tags.
+var allergenAliases = map[string]string{
+	"celeriac":  "celery",
+	"shellfish": "crustaceans",
+	"egg":       "eggs",
+	"wheat":     "gluten",
+	"dairy":     "milk",
+	"lactose":   "milk",
+	"mollusks":  "molluscs",
+	"peanut":    "peanuts",
+	"soya":      "soy",
+	"sulfites":  "sulphites",
+	"nuts":      "tree_nuts",
+	"tree_nut":  "tree_nuts",
+}
+
+// CanonicalAllergen returns the tag s names, ignoring case and taking
+// spaces and dashes for underscores.
+func CanonicalAllergen(s string) (string, bool) {
+	s = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(s)))
+	if a, ok := allergenAliases[s]; ok {
+		return a, true
+	}
+	return s, slices.Contains(Allergens, s)
+}
+
+// NewDish is a dish as created through the gateway. The dish service
+// has no place for declared allergens, so the gateway keeps them.
+type NewDish struct {
+	KitchenId   string   `json:"kitchen_id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Price       float32  `json:"price"`
+	Category    string   `json:"category"`
+	Ingredients []string `json:"ingredients"`
+	Available   bool     `json:"available"`
+	// Allergens are tags from GET /allergens and are required; an empty
+	// list declares a dish without any.
+	Allergens []string `json:"allergens"`
+}
+
+// DishAllergens are the allergens declared for a dish when it was
+// created.
+type DishAllergens struct {
+	DishId    string   `json:"dish_id"`
+	Allergens []string `json:"allergens"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// DishIngredients disclose what a dish is made of. Allergens joins the
+// declared tags with those the dish service lists.
+type DishIngredients struct {
+	DishId      string   `json:"dish_id"`
+	Name        string   `json:"name"`
+	Ingredients []string `json:"ingredients"`
+	Allergens   []string `json:"allergens"`
+	// Declared tells whether the allergens were declared when the dish
+	// was created; others were only listed by the dish service.
+	Declared bool `json:"declared"`
+}
+
+type NewAllergyProfile struct {
+	Allergens []string `json:"allergens"`
+}
+
+// AllergyProfile holds the allergens a user avoids. Quotes warn about
+// dishes that contain any of them.
+type AllergyProfile struct {
+	UserId string `json:"user_id"`
+	NewAllergyProfile
+	UpdatedAt string `json:"updated_at"`
+}
+
+// AllergenWarning tells that a dish holds allergens the user avoids.
+type AllergenWarning struct {
+	DishId    string   `json:"dish_id"`
+	Allergens []string `json:"allergens"`
+}
+
+type AllergenList struct {
+	Allergens []string `json:"allergens"`
+}
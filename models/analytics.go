@@ -0,0 +1,45 @@
+package models
+
+type HourlyOrders struct {
+	Hour   string `json:"hour"`
+	Orders int32  `json:"orders"`
+}
+
+type OrdersPerHour struct {
+	Hours []HourlyOrders `json:"hours"`
+}
+
+type CityRevenue struct {
+	City     string  `json:"city"`
+	Kitchens int32   `json:"kitchens"`
+	Orders   int32   `json:"orders"`
+	Revenue  float32 `json:"revenue"`
+}
+
+type RevenueByCity struct {
+	Cities []CityRevenue `json:"cities"`
+}
+
+type KitchenRank struct {
+	KitchenId string  `json:"kitchen_id"`
+	Name      string  `json:"name"`
+	City      string  `json:"city"`
+	Orders    int32   `json:"orders"`
+	Revenue   float32 `json:"revenue"`
+	Rating    float32 `json:"rating"`
+}
+
+type TopKitchens struct {
+	Kitchens []KitchenRank `json:"kitchens"`
+}
+
+type Churn struct {
+	PreviousStartDate string  `json:"previous_start_date"`
+	PreviousEndDate   string  `json:"previous_end_date"`
+	PreviousCustomers int32   `json:"previous_customers"`
+	CurrentCustomers  int32   `json:"current_customers"`
+	RetainedCustomers int32   `json:"retained_customers"`
+	ChurnedCustomers  int32   `json:"churned_customers"`
+	NewCustomers      int32   `json:"new_customers"`
+	ChurnRate         float32 `json:"churn_rate"`
+}
@@ -0,0 +1,39 @@
+package models
+
+const (
+	// Banners are shown above the kitchen's other announcements.
+	AnnouncementNotice = "notice"
+	AnnouncementBanner = "banner"
+
+	AnnouncementScheduled = "scheduled"
+	AnnouncementActive    = "active"
+	AnnouncementExpired   = "expired"
+)
+
+type NewAnnouncement struct {
+	// Kind is notice or banner, notice by default.
+	Kind  string `json:"kind,omitempty"`
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+	// StartsAt defaults to now. Without EndsAt the announcement stays
+	// until it is deleted.
+	StartsAt string `json:"starts_at,omitempty"`
+	EndsAt   string `json:"ends_at,omitempty"`
+}
+
+// Announcement is news a kitchen shows in its detail and in the feed of
+// its customers while it is active.
+type Announcement struct {
+	Id        string `json:"id"`
+	KitchenId string `json:"kitchen_id"`
+	NewAnnouncement
+	// Status follows from the announcement's times when it is read.
+	Status    string `json:"status"`
+	CreatedBy string `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type Announcements struct {
+	Announcements []Announcement `json:"announcements"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// ItemResult is the outcome of one item of a batch request.
+type ItemResult struct {
+	Index   int    `json:"index"`
+	Id      string `json:"id,omitempty"`
+	Status  int    `json:"status"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+type BatchResult struct {
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []ItemResult `json:"results"`
+}
+
+type Availability struct {
+	DishId    string `json:"dish_id"`
+	Available bool   `json:"available"`
+}
@@ -0,0 +1,17 @@
+package models
+
+type NewBlockedKitchen struct {
+	KitchenId string `json:"kitchen_id"`
+}
+
+// BlockedKitchen is a kitchen a user hid from their kitchen lists,
+// searches and feed.
+type BlockedKitchen struct {
+	KitchenId   string `json:"kitchen_id"`
+	KitchenName string `json:"kitchen_name"`
+	BlockedAt   string `json:"blocked_at"`
+}
+
+type BlockedKitchens struct {
+	Kitchens []BlockedKitchen `json:"kitchens"`
+}
@@ -0,0 +1,82 @@
+package models
+
+const (
+	BroadcastScheduled = "scheduled"
+	BroadcastSending   = "sending"
+	BroadcastSent      = "sent"
+	BroadcastCanceled  = "canceled"
+)
+
+// BroadcastSegment selects the users a broadcast goes to. Users must
+// match every filter given; at least one is required. The gateway only
+// knows users that have activity, searches, meal plans or settings with
+// it.
+type BroadcastSegment struct {
+	UserIds []string `json:"user_ids,omitempty"`
+	// KitchenIds selects users who ordered from, reviewed or have a meal
+	// plan at any of the kitchens.
+	KitchenIds []string `json:"kitchen_ids,omitempty"`
+	// ActiveSince selects users with activity at or after the time, in
+	// RFC 3339 or as a date.
+	ActiveSince string `json:"active_since,omitempty"`
+	// Cuisines selects users who searched for any of the cuisine types.
+	Cuisines []string `json:"cuisines,omitempty"`
+}
+
+type NewBroadcast struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	// Channels are push, email or both.
+	Channels []string         `json:"channels"`
+	Segment  BroadcastSegment `json:"segment"`
+	// SendAt defaults to now.
+	SendAt string `json:"send_at,omitempty"`
+}
+
+// BroadcastStats counts what became of a broadcast. Pushed, Emailed,
+// Skipped and Failed count deliveries, one per user and channel. Users
+// are skipped on a channel when they turned promos off for it or, for
+// email, have no email.
+type BroadcastStats struct {
+	Recipients int `json:"recipients"`
+	Processed  int `json:"processed"`
+	Pushed     int `json:"pushed"`
+	Emailed    int `json:"emailed"`
+	Skipped    int `json:"skipped"`
+	Failed     int `json:"failed"`
+}
+
+func (s BroadcastStats) Add(o BroadcastStats) BroadcastStats {
+	s.Recipients += o.Recipients
+	s.Processed += o.Processed
+	s.Pushed += o.Pushed
+	s.Emailed += o.Emailed
+	s.Skipped += o.Skipped
+	s.Failed += o.Failed
+	return s
+}
+
+type Broadcast struct {
+	Id string `json:"id"`
+	NewBroadcast
+	Status     string         `json:"status"`
+	Stats      BroadcastStats `json:"stats"`
+	CreatedBy  string         `json:"created_by"`
+	CreatedAt  string         `json:"created_at"`
+	UpdatedAt  string         `json:"updated_at"`
+	StartedAt  string         `json:"started_at,omitempty"`
+	FinishedAt string         `json:"finished_at,omitempty"`
+}
+
+type Broadcasts struct {
+	Broadcasts []Broadcast `json:"broadcasts"`
+}
+
+// BroadcastMessage is published for every user a broadcast is pushed
+// to. Push notifications are sent by the consumers of this event.
+type BroadcastMessage struct {
+	BroadcastId string `json:"broadcast_id"`
+	UserId      string `json:"user_id"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// CDN providers.
+const (
+	CDNFastly     = "fastly"
+	CDNCloudflare = "cloudflare"
+)
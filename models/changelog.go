@@ -0,0 +1,102 @@
+package models
+
+// Kinds of API changes.
+const (
+	ChangeAdded      = "added"
+	ChangeChanged    = "changed"
+	ChangeDeprecated = "deprecated"
+	ChangeRemoved    = "removed"
+)
+
+// APIChange is a change of the API contract, made on Date (YYYY-MM-DD).
+// Changes to a route name it by Method and Path, as it is registered.
+// Deprecated routes answer with Deprecation and Sunset headers until
+// Sunset, and with 410 after it; Successor is the route to move to.
+type APIChange struct {
+	Kind        string `json:"kind"`
+	Method      string `json:"method,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Description string `json:"description"`
+	Date        string `json:"date"`
+	Sunset      string `json:"sunset,omitempty"`
+	Successor   string `json:"successor,omitempty"`
+}
+
+type APIVersion struct {
+	Version string      `json:"version"`
+	Changes []APIChange `json:"changes"`
+}
+
+// Changelog lists the API versions, the latest first.
+type Changelog struct {
+	Versions []APIVersion `json:"versions"`
+}
+
+// APIChangelog is the changelog of the API. Changes to the contract get
+// an entry here, and routes are deprecated by adding one.
+var APIChangelog = []APIVersion{
+	{Version: "1.0", Changes: []APIChange{
+		{Kind: ChangeAdded, Method: "GET", Path: "/local-eats/changelog",
+			Description: "Lists the changes of the API, deprecated routes and when they sunset.", Date: "2026-10-18"},
+		{Kind: ChangeAdded, Method: "GET", Path: "/local-eats/admin/postman-collection",
+			Description: "Exports a Postman collection of the routes.", Date: "2026-10-18"},
+		{Kind: ChangeChanged,
+			Description: "429 and 503 answers have a Retry-After header and a retry hint telling how to back off.", Date: "2026-10-18"},
+		{Kind: ChangeChanged,
+			Description: "Requests may give their deadline in X-Timeout-Ms.", Date: "2026-10-18"},
+		{Kind: ChangeAdded, Method: "GET", Path: "/local-eats/orders/:id/status/poll",
+			Description: "Long polls an order's status.", Date: "2026-10-18"},
+		{Kind: ChangeAdded, Method: "GET", Path: "/local-eats/orders/placements/:token",
+			Description: "Follows an order placed in the background with POST /orders?async=true.", Date: "2026-10-18"},
+		{Kind: ChangeChanged,
+			Description: "Requests may give the schema version of their bodies in X-Schema-Version; bodies of older versions are migrated to the current one and answered in theirs.", Date: "2026-10-18"},
+		{Kind: ChangeChanged,
+			Description: "Order statuses a client's schema version doesn't know are sent as the closest status it does, with the status as it is under extensions.status.", Date: "2026-10-18"},
+		{Kind: ChangeChanged,
+			Description: "Paginated responses link the first, previous, next and last pages in Link headers.", Date: "2026-10-18"},
+		{Kind: ChangeAdded, Method: "GET", Path: "/local-eats/partners/kitchens/delta",
+			Description: "Partner aggregators sync the kitchens and dishes changed since a time, with API keys issued under /admin/partners/keys.", Date: "2026-10-18"},
+		{Kind: ChangeAdded, Method: "POST", Path: "/local-eats/partners/orders",
+			Description: "Approved partner aggregators place signed, idempotent orders for their customers by their own IDs and get status changes at their webhook.", Date: "2026-10-18"},
+		{Kind: ChangeAdded,
+			Description: "Sandbox partner keys, and X-Sandbox: true where enabled, place test orders paid with fake money by the sandbox payment provider and left out of statistics.", Date: "2026-10-18"},
+		{Kind: ChangeAdded, Method: "POST", Path: "/local-eats/admin/recordings",
+			Description: "Admins record anonymized traces of the requests served for a while, to replay against a staging gateway with the replay command.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/kitchens/:id/webhooks",
+			Description: "Only a kitchen's owner manages its webhooks, and webhook URLs must lead to public addresses.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "GET", Path: "/local-eats/kitchens/:id/statistics/export",
+			Description: "Kitchen reports and statistics exports are for the kitchen's owner, staff with the payouts permission and admins.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "PUT", Path: "/local-eats/dishes/:id/modifiers",
+			Description: "A dish's modifiers are set by its kitchen's owner, staff with the menu permission and admins.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/orders",
+			Description: "The total_amount of a placed order is the quoted total, fees included, as total_amount in the request is checked against.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/admin/encryption/rotate",
+			Description: "With encryption at rest on, upload and report files are sealed on disk too, and key rotation reseals them and counts them in files_resealed.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "GET", Path: "/local-eats/orders/:id",
+			Description: "An order, with its notes and delivery preferences, is for its customer, its kitchen's owner and staff with the orders permission, and admins; so are a kitchen's orders.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "PUT", Path: "/local-eats/dishes/availability",
+			Description: "Batch dish imports and availability changes only touch the kitchens the caller owns or is menu staff of; other items fail with 403.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "GET", Path: "/local-eats/uploads/:id/content",
+			Description: "Uploads carry their uploader's user_id. Only the uploader and admins may change, delete or attach an upload; it is downloaded by them and by those with access to the record it is attached to.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/group-orders/:id/checkout",
+			Description: "Group orders checked out and meal plans made in the sandbox place test orders, left out of statistics and earnings like other sandbox orders.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/group-orders/:id/checkout",
+			Description: "Group checkouts are screened for fraud as the host's orders, and may answer 403 with a challenge or 202 with a hold like POST /orders.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/orders",
+			Description: "Orders need a delivery location and the delivery fee is priced by the road distance to it; distance_km is no longer read. Partner orders, group orders and meal plans take a location in place of distance_km too.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/refund-requests/:id/approve",
+			Description: "Approving a refund answers 409 when it is more than is left of the payment after the refunds approved before it.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/admin/data-requests",
+			Description: "Sections found through the activity feed (orders, reviews, order notes and payments) are marked partial with the reason, and a package with them is not complete.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/admin/retention",
+			Description: "Retention runs also purge group orders and meal plans that ended and tickets that were settled before the record window, and clear the comments and evidence of refund requests decided before it.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/users/:id/searches/saved",
+			Description: "Searches take lat, lng and radius_km to match only kitchens nearby; saved ones with a radius are notified of a new kitchen once it sets its location.", Date: "2026-10-18"},
+		{Kind: ChangeChanged,
+			Description: "Routes passed through to a service answer with the status of the service's error, such as 404 for a missing record or 403 when access is denied, instead of always 500.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "GET", Path: "/local-eats/admin/limits/users",
+			Description: "Users with nothing in flight are listed for 10 minutes after their last rejection instead of until the gateway restarts.", Date: "2026-10-18"},
+		{Kind: ChangeChanged, Method: "POST", Path: "/local-eats/kitchens/:id/payouts",
+			Description: "Payouts are recorded by roles granted the new payouts:manage permission instead of admins only.", Date: "2026-10-18"},
+	}},
+}
@@ -0,0 +1,29 @@
+package models
+
+// Chargeback statuses. A chargeback stays open while the provider or the
+// card issuer decides it.
+const (
+	ChargebackOpen = "open"
+	ChargebackWon  = "won"
+	ChargebackLost = "lost"
+)
+
+// Chargeback is a payment the customer disputed with their card issuer,
+// as the payment provider reported it. Id is the provider's dispute ID.
+type Chargeback struct {
+	Id        string  `json:"id"`
+	Provider  string  `json:"provider"`
+	PaymentId string  `json:"payment_id"`
+	OrderId   string  `json:"order_id"`
+	UserId    string  `json:"user_id,omitempty"`
+	Amount    float32 `json:"amount"`
+	Currency  string  `json:"currency,omitempty"`
+	Reason    string  `json:"reason,omitempty"`
+	Status    string  `json:"status"`
+	OpenedAt  string  `json:"opened_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type Chargebacks struct {
+	Chargebacks []Chargeback `json:"chargebacks"`
+}
@@ -0,0 +1,54 @@
+package models
+
+// Commission rates are set for a single kitchen or for all kitchens of a
+// cuisine type. A kitchen rate wins over a cuisine rate, which wins over
+// the configured default.
+const (
+	CommissionKitchen = "kitchen"
+	CommissionCuisine = "cuisine"
+	CommissionDefault = "default"
+)
+
+type NewCommissionRate struct {
+	Percent float32 `json:"percent"`
+	// Note explains the change in the audit trail.
+	Note string `json:"note"`
+}
+
+type CommissionRate struct {
+	Scope     string  `json:"scope"`
+	Key       string  `json:"key"`
+	Percent   float32 `json:"percent"`
+	UpdatedBy string  `json:"updated_by,omitempty"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type CommissionRates struct {
+	DefaultPercent float32          `json:"default_percent"`
+	Rates          []CommissionRate `json:"rates"`
+}
+
+// EffectiveCommission is the rate charged on a kitchen's new orders and
+// where it comes from.
+type EffectiveCommission struct {
+	KitchenId string  `json:"kitchen_id"`
+	Percent   float32 `json:"percent"`
+	Source    string  `json:"source"`
+}
+
+// CommissionChange is an audit trail entry. OldPercent is empty when a
+// rate is first set and NewPercent when it is removed.
+type CommissionChange struct {
+	Id         string   `json:"id"`
+	Scope      string   `json:"scope"`
+	Key        string   `json:"key"`
+	OldPercent *float32 `json:"old_percent,omitempty"`
+	NewPercent *float32 `json:"new_percent,omitempty"`
+	Note       string   `json:"note,omitempty"`
+	ChangedBy  string   `json:"changed_by,omitempty"`
+	ChangedAt  string   `json:"changed_at"`
+}
+
+type CommissionHistory struct {
+	Changes []CommissionChange `json:"changes"`
+}
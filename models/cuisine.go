@@ -0,0 +1,87 @@
+package models
+
+import "strings"
+
+type NewCuisine struct {
+	// Name is the canonical name kitchens are stored and searched with.
+	Name string `json:"name"`
+	// Names holds translations of the name by language, e.g. "ru".
+	Names map[string]string `json:"names,omitempty"`
+	// Aliases are other spellings accepted for the cuisine.
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+type NewCuisineWithCode struct {
+	Code string `json:"code"`
+	NewCuisine
+}
+
+// Cuisine is an entry of the cuisine type taxonomy.
+type Cuisine struct {
+	Code string `json:"code"`
+	NewCuisine
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Matches reports whether s names the cuisine by its code, name, a
+// translation or an alias, ignoring case.
+func (c Cuisine) Matches(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, c.Code) || strings.EqualFold(s, c.Name) {
+		return true
+	}
+	for _, n := range c.Names {
+		if strings.EqualFold(s, n) {
+			return true
+		}
+	}
+	for _, a := range c.Aliases {
+		if strings.EqualFold(s, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// Localized returns the name of the cuisine in lang, falling back to the
+// canonical name.
+func (c Cuisine) Localized(lang string) LocalizedCuisine {
+	name, ok := c.Names[lang]
+	if !ok {
+		name = c.Name
+	}
+	return LocalizedCuisine{Code: c.Code, Name: c.Name, DisplayName: name}
+}
+
+// LocalizedCuisine is a cuisine as shown to app users. Name is the value
+// to filter kitchens by.
+type LocalizedCuisine struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+type LocalizedCuisines struct {
+	Lang     string             `json:"lang"`
+	Cuisines []LocalizedCuisine `json:"cuisines"`
+}
+
+type Cuisines struct {
+	Cuisines []Cuisine `json:"cuisines"`
+}
+
+// DefaultCuisines is the taxonomy the gateway starts with.
+var DefaultCuisines = []NewCuisineWithCode{
+	{"uzbek", NewCuisine{"Uzbek", map[string]string{"ru": "Узбекская", "uz": "O'zbek"}, []string{"uzbek cuisine"}}},
+	{"russian", NewCuisine{"Russian", map[string]string{"ru": "Русская", "uz": "Rus"}, nil}},
+	{"turkish", NewCuisine{"Turkish", map[string]string{"ru": "Турецкая", "uz": "Turk"}, nil}},
+	{"korean", NewCuisine{"Korean", map[string]string{"ru": "Корейская", "uz": "Koreys"}, nil}},
+	{"chinese", NewCuisine{"Chinese", map[string]string{"ru": "Китайская", "uz": "Xitoy"}, nil}},
+	{"japanese", NewCuisine{"Japanese", map[string]string{"ru": "Японская", "uz": "Yapon"}, []string{"sushi"}}},
+	{"georgian", NewCuisine{"Georgian", map[string]string{"ru": "Грузинская", "uz": "Gruzin"}, nil}},
+	{"indian", NewCuisine{"Indian", map[string]string{"ru": "Индийская", "uz": "Hind"}, nil}},
+	{"italian", NewCuisine{"Italian", map[string]string{"ru": "Итальянская", "uz": "Italyan"}, []string{"pizza"}}},
+	{"fast_food", NewCuisine{"Fast food", map[string]string{"ru": "Фастфуд", "uz": "Fast-fud"}, []string{"fastfood", "burgers"}}},
+	{"desserts", NewCuisine{"Desserts", map[string]string{"ru": "Десерты", "uz": "Shirinliklar"}, []string{"bakery", "sweets"}}},
+}
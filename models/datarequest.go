@@ -0,0 +1,90 @@
+package models
+
+// Data request kinds.
+const (
+	// DataRequestSubject is a user's own request for their data.
+	DataRequestSubject = "subject_access"
+	// DataRequestLegal is a subpoena or other legal order.
+	DataRequestLegal = "legal_order"
+)
+
+// Data access log actions.
+const (
+	DataAccessCompiled   = "compiled"
+	DataAccessDownloaded = "downloaded"
+	DataAccessDeleted    = "deleted"
+)
+
+type NewDataRequest struct {
+	UserId string `json:"user_id"`
+	// Kind is subject_access or legal_order.
+	Kind string `json:"kind"`
+	// Reference is the case, ticket or court order number the package is
+	// compiled for.
+	Reference string `json:"reference"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// DataSection is a part of a data package. Error is set when its data
+// could not be read in full, and Partial when it holds only what the
+// gateway saw, saying what may be missing; either way the package is
+// known to be incomplete.
+type DataSection struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+	Error   string `json:"error,omitempty"`
+	Partial string `json:"partial,omitempty"`
+}
+
+// DataRequest is a data package compiled for a user. The package is kept
+// encrypted until it expires or is deleted.
+type DataRequest struct {
+	Id          string        `json:"id"`
+	UserId      string        `json:"user_id"`
+	Kind        string        `json:"kind"`
+	Reference   string        `json:"reference"`
+	Reason      string        `json:"reason,omitempty"`
+	RequestedBy string        `json:"requested_by"`
+	Sections    []DataSection `json:"sections"`
+	Complete    bool          `json:"complete"`
+	// Size and Sha256 are of the encrypted package, so the recipient can
+	// check it.
+	Size      int64  `json:"size"`
+	Sha256    string `json:"sha256"`
+	CreatedAt string `json:"created_at"`
+	ExpiresAt string `json:"expires_at"`
+	DeletedAt string `json:"deleted_at,omitempty"`
+}
+
+// SealedDataRequest is a compiled data request with the key of its
+// package, which is given only once.
+type SealedDataRequest struct {
+	DataRequest
+	// Key is the base64 AES-256-GCM key of the package. The package is
+	// the 12-byte nonce followed by the ciphertext of a zip archive, with
+	// the request ID as additional data.
+	Key string `json:"key"`
+}
+
+// DataAccessEntry is an audit entry of a data package being compiled,
+// downloaded or deleted.
+type DataAccessEntry struct {
+	Id        string `json:"id"`
+	RequestId string `json:"request_id"`
+	UserId    string `json:"user_id"`
+	Action    string `json:"action"`
+	ActorId   string `json:"actor_id"`
+	Reference string `json:"reference"`
+	At        string `json:"at"`
+}
+
+// DataRequestDetails is a data request with its audit trail, earliest
+// first.
+type DataRequestDetails struct {
+	DataRequest
+	Audit []DataAccessEntry `json:"audit"`
+}
+
+type DataRequests struct {
+	Requests []DataRequest `json:"requests"`
+}
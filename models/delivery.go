@@ -0,0 +1,20 @@
+package models
+
+// DeliveryPreferences tell the courier how the customer wants the order
+// handed over.
+type DeliveryPreferences struct {
+	// LeaveAtDoor asks for contact-free delivery.
+	LeaveAtDoor   bool   `json:"leave_at_door,omitempty"`
+	CallOnArrival bool   `json:"call_on_arrival,omitempty"`
+	IntercomCode  string `json:"intercom_code,omitempty"`
+}
+
+// OrderDelivery is what whoever delivers an order needs to know.
+type OrderDelivery struct {
+	OrderId         string              `json:"order_id"`
+	Status          string              `json:"status"`
+	DeliveryAddress string              `json:"delivery_address"`
+	DeliveryTime    string              `json:"delivery_time"`
+	Preferences     DeliveryPreferences `json:"preferences"`
+	Notes           *OrderNotes         `json:"notes,omitempty"`
+}
@@ -0,0 +1,54 @@
+package models
+
+// NewDishDiscount takes Percent off a dish's price while it is active.
+// Every limit given must hold: the time is between StartsAt and EndsAt,
+// the day is one of Days and the time of day is from From until Until.
+type NewDishDiscount struct {
+	Percent float32 `json:"percent"`
+	// StartsAt and EndsAt are in RFC 3339.
+	StartsAt string `json:"starts_at,omitempty"`
+	EndsAt   string `json:"ends_at,omitempty"`
+	// Days are weekday names such as "friday", in the kitchen's time
+	// zone.
+	Days []string `json:"days,omitempty"`
+	// From and Until are times of day as HH:MM in the kitchen's time
+	// zone, the happy hour. Until may be before From for windows that
+	// pass midnight.
+	From  string `json:"from,omitempty"`
+	Until string `json:"until,omitempty"`
+}
+
+type DishDiscount struct {
+	Id        string `json:"id"`
+	DishId    string `json:"dish_id"`
+	KitchenId string `json:"kitchen_id"`
+	NewDishDiscount
+	// Active tells whether the discount applies when it is read.
+	Active    bool   `json:"active"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type DishDiscounts struct {
+	Discounts []DishDiscount `json:"discounts"`
+}
+
+// PricedItem is an order item at its unit price, modifiers included,
+// before and after the dish's discount.
+type PricedItem struct {
+	DishId          string  `json:"dish_id"`
+	Quantity        int32   `json:"quantity"`
+	UnitPrice       float32 `json:"unit_price"`
+	DiscountId      string  `json:"discount_id,omitempty"`
+	DiscountPercent float32 `json:"discount_percent,omitempty"`
+	DiscountedPrice float32 `json:"discounted_price"`
+	Total           float32 `json:"total"`
+}
+
+// OrderPricing is kept for orders that got dish discounts, which the
+// order service does not know about.
+type OrderPricing struct {
+	Subtotal float32      `json:"subtotal"`
+	Discount float32      `json:"discount"`
+	Items    []PricedItem `json:"items"`
+}
@@ -0,0 +1,75 @@
+package models
+
+const (
+	GroupDay   = "day"
+	GroupWeek  = "week"
+	GroupMonth = "month"
+)
+
+// EarningsSummary adds up the earnings of a period: what customers paid,
+// the platform commission and approved refunds deducted, and the rest
+// that the kitchen earns.
+type EarningsSummary struct {
+	Period     string  `json:"period,omitempty"`
+	Orders     int32   `json:"orders"`
+	Gross      float32 `json:"gross"`
+	Commission float32 `json:"commission"`
+	Refunds    float32 `json:"refunds"`
+	Net        float32 `json:"net"`
+}
+
+type OrderEarnings struct {
+	OrderId      string  `json:"order_id"`
+	Status       string  `json:"status"`
+	DeliveryTime string  `json:"delivery_time"`
+	Gross        float32 `json:"gross"`
+	Commission   float32 `json:"commission"`
+	Refunds      float32 `json:"refunds"`
+	Net          float32 `json:"net"`
+}
+
+type Earnings struct {
+	KitchenId         string            `json:"kitchen_id"`
+	StartDate         string            `json:"start_date"`
+	EndDate           string            `json:"end_date"`
+	Group             string            `json:"group"`
+	CommissionPercent float32           `json:"commission_percent"`
+	Totals            EarningsSummary   `json:"totals"`
+	Periods           []EarningsSummary `json:"periods"`
+	// Orders is one page of the per-order breakdown, newest first; Total
+	// counts all orders of the period.
+	Orders []OrderEarnings `json:"orders"`
+	Total  int32           `json:"total"`
+}
+
+type NewPayout struct {
+	Amount    float32 `json:"amount"`
+	Reference string  `json:"reference"`
+	StartDate string  `json:"start_date"`
+	EndDate   string  `json:"end_date"`
+}
+
+// Payout is money transferred to a kitchen, as recorded by an admin.
+type Payout struct {
+	Id        string  `json:"id"`
+	KitchenId string  `json:"kitchen_id"`
+	Amount    float32 `json:"amount"`
+	Currency  string  `json:"currency"`
+	Reference string  `json:"reference,omitempty"`
+	StartDate string  `json:"start_date,omitempty"`
+	EndDate   string  `json:"end_date,omitempty"`
+	CreatedBy string  `json:"created_by,omitempty"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// PayoutBalance compares all-time net earnings with what was paid out.
+type PayoutBalance struct {
+	Earned    float32 `json:"earned"`
+	PaidOut   float32 `json:"paid_out"`
+	Available float32 `json:"available"`
+}
+
+type Payouts struct {
+	Payouts []Payout      `json:"payouts"`
+	Balance PayoutBalance `json:"balance"`
+}
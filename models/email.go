@@ -0,0 +1,46 @@
+package models
+
+const (
+	EmailSMTP     = "smtp"
+	EmailSendGrid = "sendgrid"
+	EmailSES      = "ses"
+)
+
+const (
+	EmailQueued = "queued"
+	EmailSent   = "sent"
+	EmailFailed = "failed"
+)
+
+// Email templates.
+const (
+	EmailReceipt       = "receipt"
+	EmailVerification  = "verification"
+	EmailInvitation    = "invitation"
+	EmailStaffInvite   = "staff_invite"
+	EmailDishAvailable = "dish_available"
+	EmailReport        = "report"
+	EmailBroadcast     = "broadcast"
+	EmailAlert         = "alert"
+)
+
+// Email is a message sent by the gateway. Queued emails are retried with
+// backoff until they are sent or run out of attempts.
+type Email struct {
+	Id        string `json:"id"`
+	To        string `json:"to"`
+	Template  string `json:"template"`
+	Lang      string `json:"lang"`
+	Subject   string `json:"subject"`
+	Driver    string `json:"driver"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	Error     string `json:"error,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	SentAt    string `json:"sent_at,omitempty"`
+}
+
+type Emails struct {
+	Emails []Email `json:"emails"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// Key providers of encryption at rest.
+const (
+	KeyProviderFile = "file"
+	KeyProviderKMS  = "aws-kms"
+)
+
+// EncryptionStatus describes how the personal data the gateway keeps is
+// encrypted. KeyId is the key encryption key of the provider and
+// DataKeyId the data key values are sealed with now; DataKeys counts the
+// data keys held to open older values.
+type EncryptionStatus struct {
+	Enabled   bool   `json:"enabled"`
+	Provider  string `json:"provider,omitempty"`
+	KeyId     string `json:"key_id,omitempty"`
+	DataKeyId string `json:"data_key_id,omitempty"`
+	DataKeys  int    `json:"data_keys,omitempty"`
+	RotatedAt string `json:"rotated_at,omitempty"`
+}
+
+// KeyRotation is the result of rotating the keys: the values resealed
+// with the new data key, in memory and in the outbox, and the upload and
+// report files resealed on disk.
+type KeyRotation struct {
+	EncryptionStatus
+	Resealed       int `json:"resealed"`
+	OutboxResealed int `json:"outbox_resealed"`
+	FilesResealed  int `json:"files_resealed"`
+}
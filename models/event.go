@@ -0,0 +1,23 @@
+package models
+
+const (
+	EventOrderCreated       = "order.created"
+	EventOrderStatusChanged = "order.status_changed"
+	EventPaymentCreated     = "payment.created"
+	EventPaymentUpdated     = "payment.updated"
+	EventSearchMatched      = "search.matched"
+	EventReportGenerated    = "report.generated"
+	EventRefundRequested    = "refund.requested"
+	EventRefundApproved     = "refund.approved"
+	EventMealPlanFailed     = "meal_plan.order_failed"
+	EventDishAvailable      = "dish.available"
+	EventBroadcast          = "marketing.broadcast"
+	EventChargebackOpened   = "chargeback.opened"
+	EventChargebackUpdated  = "chargeback.updated"
+	EventKitchenCreated     = "kitchen.created"
+	EventKitchenUpdated     = "kitchen.updated"
+	EventKitchenDeleted     = "kitchen.deleted"
+	EventDishCreated        = "dish.created"
+	EventDishUpdated        = "dish.updated"
+	EventDishDeleted        = "dish.deleted"
+)
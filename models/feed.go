@@ -0,0 +1,84 @@
+package models
+
+const (
+	FeedOrderPlaced    = "order_placed"
+	FeedReviewWritten  = "review_written"
+	FeedMealPlanFailed = "meal_plan_failed"
+	// FeedAnnouncement items are not stored. The active announcements of
+	// the kitchens in a user's activity are added when the feed is read.
+	FeedAnnouncement = "kitchen_announcement"
+
+	// MaxFeedItems is how many activities are kept per user.
+	MaxFeedItems = 500
+)
+
+type FeedOrder struct {
+	Id          string  `json:"id"`
+	KitchenId   string  `json:"kitchen_id"`
+	KitchenName string  `json:"kitchen_name,omitempty"`
+	TotalAmount float32 `json:"total_amount"`
+	Status      string  `json:"status"`
+}
+
+type FeedReview struct {
+	Id          string  `json:"id"`
+	OrderId     string  `json:"order_id"`
+	KitchenId   string  `json:"kitchen_id"`
+	KitchenName string  `json:"kitchen_name,omitempty"`
+	Rating      float32 `json:"rating"`
+	Comment     string  `json:"comment"`
+}
+
+// FeedMealPlan tells about a scheduled order that could not be placed.
+type FeedMealPlan struct {
+	Id           string `json:"id"`
+	KitchenId    string `json:"kitchen_id"`
+	DeliveryTime string `json:"delivery_time"`
+	Error        string `json:"error"`
+	// Paused is set when the failure paused the plan.
+	Paused bool `json:"paused,omitempty"`
+}
+
+type FeedKitchenAnnouncement struct {
+	Id          string `json:"id"`
+	KitchenId   string `json:"kitchen_id"`
+	KitchenName string `json:"kitchen_name,omitempty"`
+	Kind        string `json:"kind"`
+	Title       string `json:"title"`
+	Body        string `json:"body,omitempty"`
+	EndsAt      string `json:"ends_at,omitempty"`
+}
+
+// FeedItem is one entry of a user's activity stream. Exactly one of the
+// detail fields is set, depending on Type.
+type FeedItem struct {
+	Type       string        `json:"type"`
+	OccurredAt string        `json:"occurred_at"`
+	Order      *FeedOrder    `json:"order,omitempty"`
+	Review     *FeedReview   `json:"review,omitempty"`
+	MealPlan   *FeedMealPlan `json:"meal_plan,omitempty"`
+	// Announcement items occur when the announcement starts.
+	Announcement *FeedKitchenAnnouncement `json:"announcement,omitempty"`
+}
+
+// KitchenId returns the ID of the kitchen the activity took place at.
+func (it FeedItem) KitchenId() string {
+	switch {
+	case it.Order != nil:
+		return it.Order.KitchenId
+	case it.Review != nil:
+		return it.Review.KitchenId
+	case it.MealPlan != nil:
+		return it.MealPlan.KitchenId
+	case it.Announcement != nil:
+		return it.Announcement.KitchenId
+	}
+	return ""
+}
+
+type Feed struct {
+	Items []FeedItem `json:"items"`
+	Total int32      `json:"total"`
+	Page  int32      `json:"page"`
+	Limit int32      `json:"limit"`
+}
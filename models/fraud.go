@@ -0,0 +1,87 @@
+package models
+
+// Fraud rules checked before an order is placed or paid for.
+const (
+	FraudRuleVelocity       = "velocity"
+	FraudRuleGeoMismatch    = "geo_mismatch"
+	FraudRuleHighValueFirst = "high_value_first_order"
+	FraudRuleChargeback     = "chargeback"
+)
+
+// Actions a fraud rule takes when it matches, from the mildest. Verify
+// makes the customer pass an OTP first; review holds the order or
+// payment for an admin.
+const (
+	FraudAllow  = "allow"
+	FraudVerify = "verify"
+	FraudReview = "review"
+)
+
+// What a fraud case is about.
+const (
+	FraudCaseOrder   = "order"
+	FraudCasePayment = "payment"
+)
+
+// Fraud case statuses.
+const (
+	FraudPending  = "pending"
+	FraudApproved = "approved"
+	FraudRejected = "rejected"
+)
+
+// FraudHit is a rule that matched and the action it takes.
+type FraudHit struct {
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+	Detail string `json:"detail"`
+}
+
+// FraudCase is an order or payment held for an admin's review. A held
+// order is kept whole and placed when approved; a held payment is tried
+// again by the customer once approved, as card details are never kept.
+type FraudCase struct {
+	Id      string     `json:"id"`
+	Kind    string     `json:"kind"`
+	UserId  string     `json:"user_id"`
+	OrderId string     `json:"order_id,omitempty"`
+	Amount  float32    `json:"amount"`
+	Hits    []FraudHit `json:"hits"`
+	Status  string     `json:"status"`
+	// Order is the held order, for order cases.
+	Order      *NewOrder `json:"order,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	ReviewedBy string    `json:"reviewed_by,omitempty"`
+	ReviewedAt string    `json:"reviewed_at,omitempty"`
+	CreatedAt  string    `json:"created_at"`
+}
+
+// FraudHold tells the customer their order or payment was held.
+type FraudHold struct {
+	CaseId  string `json:"case_id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type FraudCases struct {
+	Cases []FraudCase `json:"cases"`
+}
+
+// FraudDecision approves or rejects a held order or payment.
+type FraudDecision struct {
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
+
+// FraudHistory is what the fraud rules remember of a customer: when they
+// tried to order and pay recently, how many orders they placed and paid
+// for through the gateway, how many payments they charged back and when
+// they last passed an OTP.
+type FraudHistory struct {
+	Orders      []string `json:"orders"`
+	Payments    []string `json:"payments"`
+	Placed      int      `json:"placed"`
+	Paid        int      `json:"paid"`
+	Chargebacks int      `json:"chargebacks"`
+	VerifiedAt  string   `json:"verified_at,omitempty"`
+}
@@ -0,0 +1,80 @@
+package models
+
+const (
+	GroupOpen        = "open"
+	GroupCheckingOut = "checking_out"
+	GroupCheckedOut  = "checked_out"
+	GroupCanceled    = "canceled"
+)
+
+type NewGroupOrder struct {
+	KitchenId       string `json:"kitchen_id"`
+	DeliveryAddress string `json:"delivery_address"`
+	DeliveryTime    string `json:"delivery_time"`
+	Location        *Point `json:"location"`
+}
+
+// GroupOrder is a cart shared through its ShareCode. Everyone who joins
+// adds their own items, and the host places one order for all of them.
+type GroupOrder struct {
+	Id              string      `json:"id"`
+	ShareCode       string      `json:"share_code"`
+	ShareUrl        string      `json:"share_url"`
+	HostId          string      `json:"host_id"`
+	KitchenId       string      `json:"kitchen_id"`
+	DeliveryAddress string      `json:"delivery_address"`
+	DeliveryTime    string      `json:"delivery_time"`
+	Location        *Point      `json:"location"`
+	Status          string      `json:"status"`
+	Participants    []string    `json:"participants"`
+	Items           []GroupItem `json:"items"`
+	Subtotal        float32     `json:"subtotal"`
+	OrderId         string      `json:"order_id,omitempty"`
+	CreatedAt       string      `json:"created_at"`
+	UpdatedAt       string      `json:"updated_at"`
+}
+
+// GroupItem is an item one participant put into the group cart, priced
+// when it was added.
+type GroupItem struct {
+	Id        string      `json:"id"`
+	UserId    string      `json:"user_id"`
+	DishId    string      `json:"dish_id"`
+	Quantity  int32       `json:"quantity"`
+	Modifiers []Selection `json:"modifiers,omitempty"`
+	UnitPrice float32     `json:"unit_price"`
+	AddedAt   string      `json:"added_at"`
+}
+
+type NewGroupItem struct {
+	DishId    string      `json:"dish_id"`
+	Quantity  int32       `json:"quantity"`
+	Modifiers []Selection `json:"modifiers,omitempty"`
+}
+
+type GroupItemQuantity struct {
+	Quantity int32 `json:"quantity"`
+}
+
+type GroupCheckout struct {
+	// TotalAmount, if sent, must match the total of current prices.
+	TotalAmount *float32 `json:"total_amount,omitempty"`
+}
+
+// GroupUpdate is sent to the group's WebSocket clients on every change.
+type GroupUpdate struct {
+	Type  string     `json:"type"`
+	Group GroupOrder `json:"group"`
+}
+
+const (
+	GroupUpdateSnapshot       = "snapshot"
+	GroupUpdateJoined         = "participant_joined"
+	GroupUpdateItemAdded      = "item_added"
+	GroupUpdateItemChanged    = "item_updated"
+	GroupUpdateItemRemoved    = "item_removed"
+	GroupUpdateCheckingOut    = "checkout_started"
+	GroupUpdateCheckoutFailed = "checkout_failed"
+	GroupUpdateCheckedOut     = "checked_out"
+	GroupUpdateCanceled       = "canceled"
+)
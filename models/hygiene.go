@@ -0,0 +1,53 @@
+package models
+
+// MaxHygieneScore is the best score an inspection can give, on the
+// 0 to 5 scale of food hygiene ratings.
+const MaxHygieneScore = 5
+
+type NewInspection struct {
+	KitchenId string `json:"kitchen_id"`
+	// InspectedAt is the date of the inspection, YYYY-MM-DD.
+	InspectedAt string `json:"inspected_at"`
+	// Score is from 0, urgent improvement necessary, to 5, very good.
+	Score     int32  `json:"score"`
+	Authority string `json:"authority"`
+	Notes     string `json:"notes,omitempty"`
+	// UploadIds are completed inspection_report uploads, the report and
+	// any certificates.
+	UploadIds []string `json:"upload_ids,omitempty"`
+}
+
+type InspectionDocument struct {
+	UploadId string `json:"upload_id"`
+	Filename string `json:"filename"`
+}
+
+// Inspection is a record of a hygiene inspection of a kitchen, attached
+// by an admin.
+type Inspection struct {
+	Id          string               `json:"id"`
+	KitchenId   string               `json:"kitchen_id"`
+	InspectedAt string               `json:"inspected_at"`
+	Score       int32                `json:"score"`
+	Authority   string               `json:"authority"`
+	Notes       string               `json:"notes,omitempty"`
+	Documents   []InspectionDocument `json:"documents"`
+	RecordedBy  string               `json:"recorded_by"`
+	CreatedAt   string               `json:"created_at"`
+}
+
+// HygieneRating is the score of a kitchen's latest inspection, shown with
+// the kitchen.
+type HygieneRating struct {
+	Score        int32  `json:"score"`
+	InspectedAt  string `json:"inspected_at"`
+	Authority    string `json:"authority"`
+	InspectionId string `json:"inspection_id"`
+}
+
+// KitchenInspections are a kitchen's inspections, latest first.
+type KitchenInspections struct {
+	KitchenId   string         `json:"kitchen_id"`
+	Rating      *HygieneRating `json:"rating"`
+	Inspections []Inspection   `json:"inspections"`
+}
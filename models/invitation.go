@@ -0,0 +1,61 @@
+package models
+
+const (
+	InviteKitchenOwner = "kitchen_owner"
+	InviteStaff        = "staff"
+
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+)
+
+type NewInvitation struct {
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+	// Type is kitchen_owner or staff. Staff invitations name the kitchen
+	// and the staff permissions to grant.
+	Type        string   `json:"type"`
+	KitchenId   string   `json:"kitchen_id"`
+	Permissions []string `json:"permissions"`
+}
+
+// Invitation asks someone to register as a kitchen owner or as staff of
+// a kitchen. The emailed link carries a signed token that expires.
+type Invitation struct {
+	Id          string   `json:"id"`
+	Email       string   `json:"email"`
+	FullName    string   `json:"full_name"`
+	Type        string   `json:"type"`
+	KitchenId   string   `json:"kitchen_id,omitempty"`
+	KitchenName string   `json:"kitchen_name,omitempty"`
+	Permissions []string `json:"permissions"`
+	Status      string   `json:"status"`
+	InvitedBy   string   `json:"invited_by"`
+	CreatedAt   string   `json:"created_at"`
+	ExpiresAt   string   `json:"expires_at"`
+	AcceptedBy  string   `json:"accepted_by,omitempty"`
+	AcceptedAt  string   `json:"accepted_at,omitempty"`
+}
+
+// CreatedInvitation is returned once, when the invitation is made; the
+// token is not stored.
+type CreatedInvitation struct {
+	Invitation
+	Token string `json:"token"`
+	Url   string `json:"url"`
+}
+
+type Invitations struct {
+	Invitations []Invitation `json:"invitations"`
+}
+
+// InvitationPrefill is what the registration form is filled with when
+// opened from an invitation.
+type InvitationPrefill struct {
+	Email       string   `json:"email"`
+	FullName    string   `json:"full_name"`
+	Type        string   `json:"type"`
+	KitchenId   string   `json:"kitchen_id,omitempty"`
+	KitchenName string   `json:"kitchen_name,omitempty"`
+	Permissions []string `json:"permissions"`
+	ExpiresAt   string   `json:"expires_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// UserLoad is how many requests a user has in flight, and how many were
+// turned away for going over the per-user cap.
+type UserLoad struct {
+	UserId         string `json:"user_id"`
+	InFlight       int    `json:"in_flight"`
+	Rejected       int64  `json:"rejected"`
+	LastRejectedAt string `json:"last_rejected_at,omitempty"`
+}
+
+// UserLoads lists the users with requests in flight or rejected, the
+// most rejected first.
+type UserLoads struct {
+	Limit int        `json:"limit"`
+	Users []UserLoad `json:"users"`
+}
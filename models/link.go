@@ -0,0 +1,32 @@
+package models
+
+const (
+	LinkKitchen = "kitchen"
+	LinkDish    = "dish"
+)
+
+type NewLink struct {
+	// Type is kitchen or dish.
+	Type     string `json:"type"`
+	TargetId string `json:"target_id"`
+	// Campaign is passed on to the web app as utm_campaign.
+	Campaign string `json:"campaign,omitempty"`
+}
+
+// Link is a short share link that redirects to a kitchen or dish page.
+type Link struct {
+	Code          string `json:"code"`
+	ShortUrl      string `json:"short_url"`
+	Type          string `json:"type"`
+	TargetId      string `json:"target_id"`
+	Campaign      string `json:"campaign,omitempty"`
+	TargetUrl     string `json:"target_url"`
+	Clicks        int64  `json:"clicks"`
+	LastClickedAt string `json:"last_clicked_at,omitempty"`
+	CreatedBy     string `json:"created_by"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type Links struct {
+	Links []Link `json:"links"`
+}
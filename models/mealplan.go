@@ -0,0 +1,80 @@
+package models
+
+const (
+	MealPlanActive    = "active"
+	MealPlanPaused    = "paused"
+	MealPlanCanceled  = "canceled"
+	MealPlanCompleted = "completed"
+
+	MealPlanRunPlaced = "placed"
+	MealPlanRunFailed = "failed"
+
+	// MaxMealPlanFailures is how many orders in a row may fail before the
+	// plan is paused.
+	MaxMealPlanFailures = 3
+)
+
+// MealPlanDelivery is a weekly delivery slot. Time is HH:MM in the
+// kitchen's time zone.
+type MealPlanDelivery struct {
+	Weekday string      `json:"weekday"`
+	Time    string      `json:"time"`
+	Items   []OrderItem `json:"items"`
+}
+
+type NewMealPlan struct {
+	KitchenId       string             `json:"kitchen_id"`
+	DeliveryAddress string             `json:"delivery_address"`
+	Location        *Point             `json:"location"`
+	Deliveries      []MealPlanDelivery `json:"deliveries"`
+	// StartDate and EndDate (YYYY-MM-DD) bound the deliveries; without
+	// them the plan starts now and runs until canceled.
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+}
+
+// MealPlan is a weekly subscription to a kitchen. The gateway places an
+// order for every delivery ahead of its time.
+type MealPlan struct {
+	Id              string             `json:"id"`
+	UserId          string             `json:"user_id"`
+	KitchenId       string             `json:"kitchen_id"`
+	DeliveryAddress string             `json:"delivery_address"`
+	Location        *Point             `json:"location"`
+	Deliveries      []MealPlanDelivery `json:"deliveries"`
+	StartDate       string             `json:"start_date,omitempty"`
+	EndDate         string             `json:"end_date,omitempty"`
+	Status          string             `json:"status"`
+	// NextDeliveryAt is the next delivery an order will be placed for.
+	NextDeliveryAt string `json:"next_delivery_at,omitempty"`
+	// Failures counts orders that failed in a row.
+	Failures int `json:"failures"`
+	// Sandbox marks plans made in the sandbox, whose orders are test
+	// orders too.
+	Sandbox   bool   `json:"sandbox,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type MealPlans struct {
+	MealPlans []MealPlan `json:"meal_plans"`
+}
+
+type MealPlanStatus struct {
+	Status string `json:"status"`
+}
+
+// MealPlanRun records one order the scheduler placed, or tried to.
+type MealPlanRun struct {
+	Id           string `json:"id"`
+	PlanId       string `json:"plan_id"`
+	DeliveryTime string `json:"delivery_time"`
+	Status       string `json:"status"`
+	OrderId      string `json:"order_id,omitempty"`
+	Error        string `json:"error,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type MealPlanRuns struct {
+	Runs []MealPlanRun `json:"runs"`
+}
@@ -0,0 +1,37 @@
+package models
+
+const (
+	ModerationPending  = "pending"
+	ModerationApproved = "approved"
+	ModerationRejected = "rejected"
+)
+
+// HeldReview is a review moderation found suspicious. It is published
+// only once an admin approves it.
+type HeldReview struct {
+	Id      string  `json:"id"`
+	UserId  string  `json:"user_id,omitempty"`
+	OrderId string  `json:"order_id"`
+	Rating  float32 `json:"rating"`
+	Comment string  `json:"comment"`
+	// Reason names the check that held the review and Detail explains it.
+	Reason     string `json:"reason"`
+	Detail     string `json:"detail"`
+	Status     string `json:"status"`
+	Note       string `json:"note,omitempty"`
+	ReviewedBy string `json:"reviewed_by,omitempty"`
+	ReviewedAt string `json:"reviewed_at,omitempty"`
+	// ReviewId is the published review's ID once the review is approved.
+	ReviewId  string `json:"review_id,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type HeldReviews struct {
+	Reviews []HeldReview `json:"reviews"`
+}
+
+type ModerationDecision struct {
+	// Status is approved to publish the review or rejected to drop it.
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
@@ -0,0 +1,126 @@
+package models
+
+import "github.com/pkg/errors"
+
+var ErrInvalidModifier = errors.New("invalid modifier selection")
+
+type ModifierOption struct {
+	Name  string  `json:"name"`
+	Price float32 `json:"price"`
+}
+
+type ModifierGroup struct {
+	Name      string           `json:"name"`
+	Required  bool             `json:"required"`
+	MaxSelect int              `json:"max_select"`
+	Options   []ModifierOption `json:"options"`
+}
+
+type Modifiers struct {
+	DishId string          `json:"dish_id"`
+	Groups []ModifierGroup `json:"groups"`
+}
+
+type ModifiersNoID struct {
+	Groups []ModifierGroup `json:"groups"`
+}
+
+type Selection struct {
+	Group   string   `json:"group"`
+	Options []string `json:"options"`
+}
+
+// Validate checks that group and option names are present and unique.
+func (m *Modifiers) Validate() error {
+	groups := make(map[string]bool)
+	for _, g := range m.Groups {
+		if g.Name == "" || groups[g.Name] {
+			return errors.Errorf("group name %q is empty or duplicated", g.Name)
+		}
+		groups[g.Name] = true
+
+		if len(g.Options) == 0 {
+			return errors.Errorf("group %q has no options", g.Name)
+		}
+		if g.MaxSelect < 0 {
+			return errors.Errorf("group %q has negative max_select", g.Name)
+		}
+
+		options := make(map[string]bool)
+		for _, o := range g.Options {
+			if o.Name == "" || options[o.Name] {
+				return errors.Errorf("option name %q in group %q is empty or duplicated", o.Name, g.Name)
+			}
+			if o.Price < 0 {
+				return errors.Errorf("option %q in group %q has negative price", o.Name, g.Name)
+			}
+			options[o.Name] = true
+		}
+	}
+	return nil
+}
+
+// Price validates selections against the schema and returns the sum of
+// the chosen options' prices.
+func (m *Modifiers) Price(selections []Selection) (float32, error) {
+	var price float32
+	selected := make(map[string]bool)
+
+	for _, s := range selections {
+		if selected[s.Group] {
+			return 0, errors.Wrapf(ErrInvalidModifier, "group %q selected twice", s.Group)
+		}
+		selected[s.Group] = true
+
+		g := m.group(s.Group)
+		if g == nil {
+			return 0, errors.Wrapf(ErrInvalidModifier, "unknown group %q", s.Group)
+		}
+		if len(s.Options) == 0 {
+			return 0, errors.Wrapf(ErrInvalidModifier, "no options selected in group %q", s.Group)
+		}
+		if g.MaxSelect > 0 && len(s.Options) > g.MaxSelect {
+			return 0, errors.Wrapf(ErrInvalidModifier, "at most %d options allowed in group %q", g.MaxSelect, s.Group)
+		}
+
+		options := make(map[string]bool)
+		for _, name := range s.Options {
+			if options[name] {
+				return 0, errors.Wrapf(ErrInvalidModifier, "option %q selected twice in group %q", name, s.Group)
+			}
+			options[name] = true
+
+			o := g.option(name)
+			if o == nil {
+				return 0, errors.Wrapf(ErrInvalidModifier, "unknown option %q in group %q", name, s.Group)
+			}
+			price += o.Price
+		}
+	}
+
+	for _, g := range m.Groups {
+		if g.Required && !selected[g.Name] {
+			return 0, errors.Wrapf(ErrInvalidModifier, "group %q is required", g.Name)
+		}
+	}
+
+	return price, nil
+}
+
+func (m *Modifiers) group(name string) *ModifierGroup {
+	for i := range m.Groups {
+		if m.Groups[i].Name == name {
+			return &m.Groups[i]
+		}
+	}
+	return nil
+}
+
+func (g *ModifierGroup) option(name string) *ModifierOption {
+	for i := range g.Options {
+		if g.Options[i].Name == name {
+			return &g.Options[i]
+		}
+	}
+	return nil
+}
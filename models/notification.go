@@ -0,0 +1,76 @@
+package models
+
+// Notification channels.
+const (
+	ChannelPush  = "push"
+	ChannelSMS   = "sms"
+	ChannelEmail = "email"
+)
+
+// Kinds of notification a user can turn on or off.
+const (
+	NotifyOrderUpdates = "order_updates"
+	NotifyPromos       = "promos"
+	NotifyReviews      = "reviews"
+)
+
+// NotificationChannels tells which channels a kind of notification is
+// sent through.
+type NotificationChannels struct {
+	Push  bool `json:"push"`
+	SMS   bool `json:"sms"`
+	Email bool `json:"email"`
+}
+
+type NewNotificationSettings struct {
+	OrderUpdates NotificationChannels `json:"order_updates"`
+	// Promos covers saved search matches and dish alerts.
+	Promos  NotificationChannels `json:"promos"`
+	Reviews NotificationChannels `json:"reviews"`
+}
+
+type NotificationSettings struct {
+	UserId string `json:"user_id"`
+	NewNotificationSettings
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// DefaultNotificationSettings are the settings of users who haven't
+// changed them: everything is on.
+func DefaultNotificationSettings(userID string) NotificationSettings {
+	all := NotificationChannels{Push: true, SMS: true, Email: true}
+	return NotificationSettings{
+		UserId: userID,
+		NewNotificationSettings: NewNotificationSettings{
+			OrderUpdates: all,
+			Promos:       all,
+			Reviews:      all,
+		},
+	}
+}
+
+// Allows tells whether the kind of notification may be sent through the
+// channel.
+func (s NotificationSettings) Allows(kind, channel string) bool {
+	var ch NotificationChannels
+	switch kind {
+	case NotifyOrderUpdates:
+		ch = s.OrderUpdates
+	case NotifyPromos:
+		ch = s.Promos
+	case NotifyReviews:
+		ch = s.Reviews
+	default:
+		return true
+	}
+
+	switch channel {
+	case ChannelPush:
+		return ch.Push
+	case ChannelSMS:
+		return ch.SMS
+	case ChannelEmail:
+		return ch.Email
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package models
+
+// Nutrients are the calories and the grams of protein, fat and carbs of
+// a dish or a sum of dishes.
+type Nutrients struct {
+	Calories int32 `json:"calories"`
+	Protein  int32 `json:"protein"`
+	Fat      int32 `json:"fat"`
+	Carbs    int32 `json:"carbs"`
+}
+
+// Add adds the nutrients of n servings.
+func (s *Nutrients) Add(n Nutrients, servings int32) {
+	s.Calories += n.Calories * servings
+	s.Protein += n.Protein * servings
+	s.Fat += n.Fat * servings
+	s.Carbs += n.Carbs * servings
+}
+
+type NutritionDish struct {
+	Id          string    `json:"id"`
+	KitchenId   string    `json:"kitchen_id"`
+	Name        string    `json:"name"`
+	Category    string    `json:"category,omitempty"`
+	Price       float32   `json:"price"`
+	Available   bool      `json:"available"`
+	Nutrition   Nutrients `json:"nutrition"`
+	Allergens   []string  `json:"allergens,omitempty"`
+	DietaryInfo []string  `json:"dietary_info,omitempty"`
+}
+
+type NutritionDishes struct {
+	Dishes []NutritionDish `json:"dishes"`
+	Total  int32           `json:"total"`
+	Page   int32           `json:"page"`
+	Limit  int32           `json:"limit"`
+}
+
+type NutritionItem struct {
+	DishId    string    `json:"dish_id"`
+	Name      string    `json:"name"`
+	Quantity  int32     `json:"quantity"`
+	Nutrition Nutrients `json:"nutrition"`
+}
+
+type NutritionOrder struct {
+	OrderId      string          `json:"order_id"`
+	KitchenId    string          `json:"kitchen_id"`
+	KitchenName  string          `json:"kitchen_name,omitempty"`
+	DeliveryTime string          `json:"delivery_time"`
+	Nutrition    Nutrients       `json:"nutrition"`
+	Items        []NutritionItem `json:"items"`
+}
+
+// NutritionSummary sums what a user's orders delivered on a day hold.
+// Dishes whose nutrition is unknown are listed in UnknownDishes and
+// left out of the sums.
+type NutritionSummary struct {
+	UserId        string           `json:"user_id"`
+	Date          string           `json:"date"`
+	TimeZone      string           `json:"time_zone"`
+	Totals        Nutrients        `json:"totals"`
+	Orders        []NutritionOrder `json:"orders"`
+	UnknownDishes []string         `json:"unknown_dishes,omitempty"`
+}
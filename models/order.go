@@ -0,0 +1,59 @@
+package models
+
+type OrderItem struct {
+	DishId    string      `json:"dish_id"`
+	Quantity  int32       `json:"quantity"`
+	Modifiers []Selection `json:"modifiers,omitempty"`
+	// Note is for the kitchen, such as "no onions".
+	Note string `json:"note,omitempty"`
+}
+
+type NewOrder struct {
+	UserId          string      `json:"user_id"`
+	KitchenId       string      `json:"kitchen_id"`
+	Items           []OrderItem `json:"items"`
+	DeliveryAddress string      `json:"delivery_address"`
+	DeliveryTime    string      `json:"delivery_time"`
+	TotalAmount     *float32    `json:"total_amount,omitempty"`
+	// Location is where the order is delivered. The delivery fee is
+	// priced by the road distance to it from the kitchen.
+	Location *Point `json:"location"`
+	// DistanceKm is the road distance the gateway works out to Location.
+	DistanceKm float32 `json:"-"`
+	// Note holds special instructions for the whole order.
+	Note                string               `json:"note,omitempty"`
+	DeliveryPreferences *DeliveryPreferences `json:"delivery_preferences,omitempty"`
+	// Sandbox marks test orders, set for requests made in the sandbox.
+	Sandbox bool `json:"-"`
+}
+
+// SandboxOrder is what is kept of a test order placed in the sandbox, to
+// take it out of the kitchen's statistics, which the statistics service
+// counts it in.
+type SandboxOrder struct {
+	KitchenId    string       `json:"kitchen_id"`
+	Amount       float32      `json:"amount"`
+	Items        []PricedItem `json:"items"`
+	DeliveryTime string       `json:"delivery_time"`
+}
+
+// OrderNotes are the notes of an order, which the order service has no
+// place for yet.
+type OrderNotes struct {
+	Note  string     `json:"note,omitempty"`
+	Items []ItemNote `json:"items,omitempty"`
+}
+
+type ItemNote struct {
+	DishId string `json:"dish_id"`
+	Note   string `json:"note"`
+}
+
+type QuoteRequest struct {
+	Items      []OrderItem `json:"items"`
+	DistanceKm float32     `json:"distance_km"`
+	// With the kitchen and the delivery location the distance is worked
+	// out from the road route instead.
+	KitchenId string `json:"kitchen_id,omitempty"`
+	Location  *Point `json:"location,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// OrderStatus is an order's status as the gateway saw it change. Version
+// goes up with every change made through the gateway, so clients can
+// wait for the next one.
+type OrderStatus struct {
+	OrderId   string `json:"order_id"`
+	Status    string `json:"status"`
+	Version   int    `json:"version"`
+	UpdatedAt string `json:"updated_at"`
+}
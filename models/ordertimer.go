@@ -0,0 +1,41 @@
+package models
+
+// What a kitchen's order timer does with an order left without a
+// response.
+const (
+	AutoAccept = "accept"
+	AutoReject = "reject"
+)
+
+// Statuses order timers set.
+const (
+	OrderAccepted = "accepted"
+	OrderRejected = "rejected"
+)
+
+// NewOrderTimer tells what happens to orders the kitchen doesn't respond
+// to. An empty action turns the timer off.
+type NewOrderTimer struct {
+	Action string `json:"action"`
+	// AfterMinutes is how long an order may wait for the kitchen. Orders
+	// are accepted at once when it is zero.
+	AfterMinutes int32 `json:"after_minutes"`
+}
+
+type OrderTimer struct {
+	KitchenId string `json:"kitchen_id"`
+	NewOrderTimer
+	UpdatedBy string `json:"updated_by,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// PendingOrder is an order whose kitchen's timer runs until DueAt. The
+// timer acts only while the order still has the status it was placed
+// with.
+type PendingOrder struct {
+	OrderId  string `json:"order_id"`
+	Status   string `json:"status"`
+	Action   string `json:"action"`
+	DueAt    string `json:"due_at"`
+	Attempts int    `json:"attempts"`
+}
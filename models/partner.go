@@ -0,0 +1,120 @@
+package models
+
+import "encoding/json"
+
+// Entities whose changes are tracked for partners.
+const (
+	CatalogKitchen = "kitchen"
+	CatalogDish    = "dish"
+)
+
+type NewPartnerKey struct {
+	// Partner names the aggregator the key is issued to; its orders are
+	// kept under this name.
+	Partner string `json:"partner"`
+	PartnerKeySettings
+}
+
+// PartnerKeySettings approve a key's partner to place orders, and tell
+// where the statuses of its orders are sent.
+type PartnerKeySettings struct {
+	Orders     bool   `json:"orders"`
+	WebhookUrl string `json:"webhook_url,omitempty"`
+	// Sandbox keys place test orders, paid with fake money and left out
+	// of statistics, for partners to integrate with.
+	Sandbox bool `json:"sandbox"`
+}
+
+// PartnerKey lets an aggregator call the partner routes with the key in
+// X-API-Key. Only a hash of the key is stored. Order requests are also
+// signed with Secret, which signs the webhooks sent back too.
+type PartnerKey struct {
+	Id      string `json:"id"`
+	Partner string `json:"partner"`
+	Prefix  string `json:"prefix"`
+	PartnerKeySettings
+	CreatedBy  string `json:"created_by"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	RevokedAt  string `json:"revoked_at,omitempty"`
+	Hash       string `json:"-"`
+	Secret     string `json:"-"`
+}
+
+// CreatedPartnerKey is returned once, when the key is issued.
+type CreatedPartnerKey struct {
+	PartnerKey
+	Key    string `json:"key"`
+	Secret string `json:"secret"`
+}
+
+type PartnerKeys struct {
+	Keys []PartnerKey `json:"keys"`
+}
+
+// CatalogChange is a kitchen or dish created, updated or deleted. Data is
+// the entity as it was after the change, and empty for deletions.
+type CatalogChange struct {
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	Entity    string          `json:"entity"`
+	EntityId  string          `json:"entity_id"`
+	KitchenId string          `json:"kitchen_id,omitempty"`
+	ChangedAt string          `json:"changed_at"`
+	Data      json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+// CatalogDelta is a page of the changes since a time, oldest first.
+// SyncedAt is the since of the next sync once every page was read.
+type CatalogDelta struct {
+	Changes  []CatalogChange `json:"changes"`
+	Total    int32           `json:"total"`
+	Since    string          `json:"since"`
+	SyncedAt string          `json:"synced_at"`
+}
+
+// Statuses of partner orders besides those of the order service.
+const (
+	// PartnerOrderPlacing is an order whose first request is still being
+	// placed.
+	PartnerOrderPlacing = "placing"
+)
+
+// NewPartnerOrder is an order a partner places for one of its customers,
+// named by the partner's own IDs. ExternalOrderId is unique per partner:
+// sending it again returns the order placed the first time.
+type NewPartnerOrder struct {
+	ExternalOrderId    string      `json:"external_order_id"`
+	ExternalCustomerId string      `json:"external_customer_id"`
+	KitchenId          string      `json:"kitchen_id"`
+	Items              []OrderItem `json:"items"`
+	DeliveryAddress    string      `json:"delivery_address"`
+	DeliveryTime       string      `json:"delivery_time"`
+	TotalAmount        *float32    `json:"total_amount,omitempty"`
+	Location           *Point      `json:"location"`
+	Note               string      `json:"note,omitempty"`
+	// DeliveryPreferences are kept with the order like a customer's.
+	DeliveryPreferences *DeliveryPreferences `json:"delivery_preferences,omitempty"`
+}
+
+// PartnerOrder maps a partner's order to the order placed for it. UserId
+// is the user the partner's customer is mapped to, the same for every
+// order of the customer.
+type PartnerOrder struct {
+	Partner            string  `json:"partner"`
+	ExternalOrderId    string  `json:"external_order_id"`
+	ExternalCustomerId string  `json:"external_customer_id"`
+	OrderId            string  `json:"order_id,omitempty"`
+	UserId             string  `json:"user_id"`
+	KitchenId          string  `json:"kitchen_id"`
+	Status             string  `json:"status"`
+	TotalAmount        float32 `json:"total_amount,omitempty"`
+	CreatedAt          string  `json:"created_at"`
+	UpdatedAt          string  `json:"updated_at,omitempty"`
+	// KeyId is the key the order was placed with, whose webhook gets
+	// its statuses.
+	KeyId string `json:"-"`
+	// RequestHash tells a retry from a different order reusing the
+	// external ID.
+	RequestHash string `json:"-"`
+}
@@ -0,0 +1,89 @@
+package models
+
+const (
+	ProviderInternal = "internal"
+	ProviderStripe   = "stripe"
+	ProviderPayme    = "payme"
+	ProviderClick    = "click"
+	// ProviderSandbox simulates payments of sandbox orders.
+	ProviderSandbox = "sandbox"
+)
+
+const (
+	PaymentPending        = "pending"
+	PaymentRequiresAction = "requires_action"
+	PaymentSucceeded      = "succeeded"
+	PaymentFailed         = "failed"
+	PaymentCanceled       = "canceled"
+	PaymentRefunded       = "refunded"
+)
+
+type NewPayment struct {
+	OrderId string `json:"order_id"`
+	// Provider selects the payment gateway; empty uses the configured
+	// default.
+	Provider      string `json:"provider,omitempty"`
+	PaymentMethod string `json:"payment_method"`
+	CardNumber    string `json:"card_number,omitempty"`
+	ExpiryDate    string `json:"expiry_date,omitempty"`
+	Cvv           string `json:"cvv,omitempty"`
+	// PaymentToken is a payment method created client-side by the
+	// provider's SDK, e.g. a Stripe PaymentMethod ID.
+	PaymentToken string `json:"payment_token,omitempty"`
+	// ReturnUrl is where hosted checkout pages send the customer back.
+	ReturnUrl string `json:"return_url,omitempty"`
+}
+
+type Payment struct {
+	Id            string  `json:"id"`
+	OrderId       string  `json:"order_id"`
+	Provider      string  `json:"provider"`
+	Amount        float32 `json:"amount"`
+	Currency      string  `json:"currency,omitempty"`
+	Status        string  `json:"status"`
+	TransactionId string  `json:"transaction_id,omitempty"`
+	// RedirectUrl is the hosted checkout page to send the customer to.
+	RedirectUrl string `json:"redirect_url,omitempty"`
+	// Challenge is set while the card issuer asks the customer to
+	// authenticate the payment (3-D Secure).
+	Challenge      *PaymentChallenge `json:"challenge,omitempty"`
+	RefundedAmount float32           `json:"refunded_amount,omitempty"`
+	CreatedAt      string            `json:"created_at"`
+	PaidAt         string            `json:"paid_at,omitempty"`
+	CanceledAt     string            `json:"canceled_at,omitempty"`
+	// AnonymizedAt is set once the payment's personal data was removed
+	// under the retention policy.
+	AnonymizedAt string `json:"anonymized_at,omitempty"`
+}
+
+// PaymentChallenge tells the client how to let the customer
+// authenticate: open RedirectUrl, or pass ClientSecret to the provider's
+// SDK. Either way the client calls the confirm endpoint afterwards.
+type PaymentChallenge struct {
+	RedirectUrl  string `json:"redirect_url,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type NewCardToken struct {
+	// Provider that will charge the token; empty uses the configured
+	// default.
+	Provider   string `json:"provider,omitempty"`
+	CardNumber string `json:"card_number"`
+	ExpiryDate string `json:"expiry_date"`
+	Cvv        string `json:"cvv"`
+}
+
+// CardToken stands in for a card in CreatePayment's payment_token, so
+// clients never send the card number again.
+type CardToken struct {
+	Token      string `json:"token"`
+	Provider   string `json:"provider"`
+	Last4      string `json:"last4"`
+	ExpiryDate string `json:"expiry_date"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+}
+
+type PaymentProviders struct {
+	Default   string   `json:"default"`
+	Providers []string `json:"providers"`
+}
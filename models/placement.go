@@ -0,0 +1,26 @@
+package models
+
+// Statuses of an order placed in the background.
+const (
+	PlacementPending = "pending"
+	PlacementPlaced  = "placed"
+	// PlacementHeld orders were held for fraud review; Response tells
+	// the case.
+	PlacementHeld   = "held"
+	PlacementFailed = "failed"
+)
+
+// OrderPlacement tracks an order placed in the background by its token.
+// Once it is done, ResponseStatus and Response are what placing it
+// synchronously would have answered with, bar the placed order itself,
+// which is got by OrderId.
+type OrderPlacement struct {
+	Token          string         `json:"token"`
+	UserId         string         `json:"user_id"`
+	Status         string         `json:"status"`
+	OrderId        string         `json:"order_id,omitempty"`
+	ResponseStatus int            `json:"response_status,omitempty"`
+	Response       map[string]any `json:"response,omitempty"`
+	CreatedAt      string         `json:"created_at"`
+	UpdatedAt      string         `json:"updated_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+// Response profiles, which shape responses for the devices asking.
+const (
+	// ProfileFull sends responses as they are.
+	ProfileFull = "full"
+	// ProfileLite trims responses and shrinks images for low-end devices
+	// on slow networks, such as Android Go phones.
+	ProfileLite = "lite"
+)
+
+// PlatformAndroidGo is the X-Platform of the Android Go build of the app.
+// It is counted as PlatformAndroid, but gets the lite profile.
+const PlatformAndroidGo = "android-go"
+
+// ResponseProfile is how responses are shaped for a kind of device. Trim
+// are the fields left out of JSON responses at any depth, by their
+// snake_case names, whichever naming the response uses. Images are scaled
+// down to fit MaxImageDimension and converted to ImageFormat unless the
+// request asks for smaller or another format.
+type ResponseProfile struct {
+	Name              string   `json:"name"`
+	Trim              []string `json:"trim,omitempty"`
+	MaxImageDimension int      `json:"max_image_dimension,omitempty"`
+	ImageFormat       string   `json:"image_format,omitempty"`
+}
+
+// ResponseProfiles are the profiles by name.
+var ResponseProfiles = map[string]ResponseProfile{
+	ProfileFull: {Name: ProfileFull},
+	ProfileLite: {
+		Name: ProfileLite,
+		// Details the menus don't show until a dish is opened, and
+		// bookkeeping. Allergens are kept.
+		Trim: []string{
+			"ingredients", "nutrition_info", "dietary_info",
+			"created_at", "updated_at", "owner_id",
+		},
+		MaxImageDimension: 480,
+		ImageFormat:       "webp",
+	},
+}
@@ -0,0 +1,77 @@
+package models
+
+import "encoding/json"
+
+// NewRecording starts recording the requests served for Minutes.
+type NewRecording struct {
+	Minutes int `json:"minutes"`
+	// Note tells what the recording is for, e.g. the release it tests.
+	Note string `json:"note,omitempty"`
+}
+
+// Recording captures anonymized traces of the requests the gateway
+// serves from StartedAt until EndsAt, or until it is stopped, to replay
+// against a staging gateway before releases. Salt keys the pseudonyms
+// personal data is replaced with, the same within a recording.
+type Recording struct {
+	Id        string `json:"id"`
+	Note      string `json:"note,omitempty"`
+	StartedBy string `json:"started_by"`
+	StartedAt string `json:"started_at"`
+	EndsAt    string `json:"ends_at"`
+	StoppedAt string `json:"stopped_at,omitempty"`
+	Requests  int    `json:"requests"`
+	// Dropped counts the requests served past the most a recording keeps.
+	Dropped int    `json:"dropped"`
+	Salt    []byte `json:"-"`
+}
+
+type Recordings struct {
+	Recordings []Recording `json:"recordings"`
+}
+
+// RecordedRequest is an anonymized request of a recording, with how it
+// was answered. Route is as it is registered, "/local-eats/orders/:id".
+// Only headers that change how requests are answered are kept, never
+// credentials.
+type RecordedRequest struct {
+	Seq    int               `json:"seq"`
+	Method string            `json:"method"`
+	Route  string            `json:"route"`
+	Path   string            `json:"path"`
+	Query  string            `json:"query,omitempty"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty" swaggertype:"object"`
+	// BodyOmitted is set when the body was not JSON or too large to keep.
+	BodyOmitted bool   `json:"body_omitted,omitempty"`
+	Status      int    `json:"status"`
+	DurationMs  int64  `json:"duration_ms"`
+	At          string `json:"at"`
+}
+
+// RecordingTrace is a recording with its requests in the order they
+// were served, what the replay command reads.
+type RecordingTrace struct {
+	Recording Recording         `json:"recording"`
+	Requests  []RecordedRequest `json:"requests"`
+}
+
+// ReplayResult is how a recorded request was answered when replayed.
+type ReplayResult struct {
+	Seq      int    `json:"seq"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Recorded int    `json:"recorded"`
+	Status   int    `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ReplayReport lists the replayed requests answered differently than
+// when they were recorded.
+type ReplayReport struct {
+	Target     string         `json:"target"`
+	Requests   int            `json:"requests"`
+	Matched    int            `json:"matched"`
+	Skipped    int            `json:"skipped"`
+	Mismatches []ReplayResult `json:"mismatches"`
+}
@@ -0,0 +1,53 @@
+package models
+
+const (
+	RefundPending  = "pending"
+	RefundApproved = "approved"
+	RefundRejected = "rejected"
+)
+
+// RefundReasons are the reason codes a customer can give for a refund.
+var RefundReasons = []string{
+	"missing_items",
+	"wrong_order",
+	"quality_issue",
+	"late_delivery",
+	"not_delivered",
+	"other",
+}
+
+type NewRefundRequest struct {
+	Reason  string `json:"reason"`
+	Comment string `json:"comment"`
+	// Amount to refund; zero asks for the full payment.
+	Amount float32 `json:"amount"`
+	// PaymentId is needed only when the payment was not made through
+	// this gateway.
+	PaymentId string   `json:"payment_id,omitempty"`
+	Evidence  []string `json:"evidence"`
+}
+
+type RefundRequest struct {
+	Id              string   `json:"id"`
+	OrderId         string   `json:"order_id"`
+	PaymentId       string   `json:"payment_id"`
+	KitchenId       string   `json:"kitchen_id"`
+	UserId          string   `json:"user_id"`
+	Reason          string   `json:"reason"`
+	Comment         string   `json:"comment"`
+	Amount          float32  `json:"amount"`
+	Evidence        []string `json:"evidence"`
+	Status          string   `json:"status"`
+	DecisionComment string   `json:"decision_comment,omitempty"`
+	DecidedBy       string   `json:"decided_by,omitempty"`
+	CreatedAt       string   `json:"created_at"`
+	DecidedAt       string   `json:"decided_at,omitempty"`
+}
+
+type RefundRequests struct {
+	Requests []RefundRequest `json:"requests"`
+}
+
+type RefundDecision struct {
+	Comment string `json:"comment"`
+}
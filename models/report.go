@@ -0,0 +1,53 @@
+package models
+
+const (
+	ReportWeekly  = "weekly"
+	ReportMonthly = "monthly"
+
+	ReportCSV = "csv"
+	ReportPDF = "pdf"
+
+	ReportDeliveryEmail   = "email"
+	ReportDeliveryWebhook = "webhook"
+
+	ReportDelivered = "delivered"
+	ReportFailed    = "failed"
+)
+
+type NewReportSchedule struct {
+	Frequency string `json:"frequency"`
+	Format    string `json:"format"`
+	Delivery  string `json:"delivery"`
+	Email     string `json:"email,omitempty"`
+}
+
+type ReportSchedule struct {
+	Id        string `json:"id"`
+	KitchenId string `json:"kitchen_id"`
+	Frequency string `json:"frequency"`
+	Format    string `json:"format"`
+	Delivery  string `json:"delivery"`
+	Email     string `json:"email,omitempty"`
+	NextRunAt string `json:"next_run_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type ReportSchedules struct {
+	Schedules []ReportSchedule `json:"schedules"`
+}
+
+type Report struct {
+	Id         string `json:"id"`
+	ScheduleId string `json:"schedule_id"`
+	KitchenId  string `json:"kitchen_id"`
+	Format     string `json:"format"`
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type Reports struct {
+	Reports []Report `json:"reports"`
+}
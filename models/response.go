@@ -0,0 +1,20 @@
+package models
+
+import "encoding/json"
+
+// Envelope wraps successful responses when RESPONSE_ENVELOPE is enabled.
+type Envelope struct {
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+	Meta Meta            `json:"meta"`
+}
+
+type Meta struct {
+	RequestId  string          `json:"request_id,omitempty"`
+	Pagination *PaginationMeta `json:"pagination,omitempty"`
+}
+
+type PaginationMeta struct {
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+	Total int64 `json:"total,omitempty"`
+}
@@ -0,0 +1,50 @@
+package models
+
+// Retention run triggers.
+const (
+	RetentionScheduled = "scheduled"
+	RetentionManual    = "manual"
+)
+
+// RetentionPolicy is how long personal data is kept. Orders and their
+// payments are anonymized in the services after RecordDays; the logs the
+// gateway keeps, such as sent emails, SMS and webhook deliveries, are
+// purged after LogDays.
+type RetentionPolicy struct {
+	Enabled    bool   `json:"enabled"`
+	RecordDays int    `json:"record_days"`
+	LogDays    int    `json:"log_days"`
+	Interval   string `json:"interval"`
+}
+
+// RetentionFailure is a record the services could not anonymize. It is
+// tried again on the next run.
+type RetentionFailure struct {
+	Kind  string `json:"kind"`
+	Id    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// RetentionRun reports what a retention run anonymized and purged.
+type RetentionRun struct {
+	Id         string `json:"id"`
+	Trigger    string `json:"trigger"`
+	StartedBy  string `json:"started_by,omitempty"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at"`
+	// RecordsBefore and LogsBefore are the cutoffs of the run.
+	RecordsBefore      string `json:"records_before"`
+	LogsBefore         string `json:"logs_before"`
+	AnonymizedOrders   int    `json:"anonymized_orders"`
+	AnonymizedPayments int    `json:"anonymized_payments"`
+	// Purged counts the gateway records removed, by kind.
+	Purged       map[string]int `json:"purged"`
+	FailureCount int            `json:"failure_count"`
+	// Failures lists the first failures of the run.
+	Failures []RetentionFailure `json:"failures"`
+}
+
+type RetentionRuns struct {
+	Policy RetentionPolicy `json:"policy"`
+	Runs   []RetentionRun  `json:"runs"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// Why a request was turned away with 429 or 503.
+const (
+	RetryOverloaded  = "overloaded"
+	RetryConcurrency = "concurrency"
+	RetryUserLimit   = "user_limit"
+	RetryMaintenance = "maintenance"
+	RetryResend      = "resend_wait"
+)
+
+// Backoff policies of a RetryHint.
+const (
+	// BackoffExponential doubles the wait on every retry, from
+	// AfterSeconds up to MaxSeconds.
+	BackoffExponential = "exponential"
+	// BackoffFixed waits AfterSeconds between retries.
+	BackoffFixed = "fixed"
+)
+
+// RetryHint tells clients turned away with 429 or 503 how to retry
+// politely. AfterSeconds is also sent as Retry-After. With Jitter, clients
+// wait a random time between AfterSeconds and the computed backoff, so
+// they don't all come back at once.
+type RetryHint struct {
+	Reason       string `json:"reason"`
+	AfterSeconds int    `json:"after_seconds"`
+	Backoff      string `json:"backoff"`
+	MaxSeconds   int    `json:"max_seconds"`
+	Jitter       bool   `json:"jitter"`
+}
@@ -0,0 +1,101 @@
+package models
+
+// Permissions granted by roles. PermAll grants every permission.
+const (
+	PermAll           = "*"
+	PermAnalytics     = "analytics:read"
+	PermCommissions   = "commissions:manage"
+	PermCuisines      = "cuisines:manage"
+	PermZones         = "zones:manage"
+	PermVerifications = "verifications:manage"
+	PermModeration    = "reviews:moderate"
+	PermRefunds       = "refunds:decide"
+	PermRoles         = "roles:manage"
+	PermInvitations   = "invitations:manage"
+	PermMarketing     = "marketing:send"
+	PermEmails        = "emails:read"
+	PermAbuse         = "abuse:review"
+	PermFraud         = "fraud:review"
+	PermSettings      = "settings:manage"
+	PermDocs          = "docs:read"
+	PermInspections   = "inspections:manage"
+	PermRetention     = "retention:manage"
+	PermDataRequests  = "data_requests:manage"
+	PermEncryption    = "encryption:manage"
+	PermPartners      = "partners:manage"
+	PermRecordings    = "recordings:manage"
+	PermPayouts       = "payouts:manage"
+)
+
+// Permissions lists the permissions a role may be granted.
+var Permissions = []string{
+	PermAnalytics,
+	PermCommissions,
+	PermCuisines,
+	PermZones,
+	PermVerifications,
+	PermModeration,
+	PermRefunds,
+	PermRoles,
+	PermInvitations,
+	PermMarketing,
+	PermEmails,
+	PermAbuse,
+	PermFraud,
+	PermSettings,
+	PermDocs,
+	PermInspections,
+	PermRetention,
+	PermDataRequests,
+	PermEncryption,
+	PermPartners,
+	PermRecordings,
+	PermPayouts,
+}
+
+type NewRole struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type NewRoleWithName struct {
+	// Name is the value of the token's role claim, up to 32 lowercase
+	// letters, digits or underscores.
+	Name string `json:"name"`
+	NewRole
+}
+
+// Role grants permissions to the tokens whose role claim names it.
+// Built-in roles can't be changed.
+type Role struct {
+	Name string `json:"name"`
+	NewRole
+	BuiltIn   bool   `json:"built_in"`
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// Allows reports whether the role grants permission.
+func (r Role) Allows(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == PermAll || p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type Roles struct {
+	Roles []Role `json:"roles"`
+}
+
+type PermissionList struct {
+	Permissions []string `json:"permissions"`
+}
+
+// BuiltInRoles are the roles the auth service issues tokens for.
+var BuiltInRoles = []Role{
+	{Name: RoleAdmin, NewRole: NewRole{"Full access", []string{PermAll}}, BuiltIn: true},
+	{Name: RoleKitchen, NewRole: NewRole{"Kitchen owner", []string{}}, BuiltIn: true},
+	{Name: RoleCustomer, NewRole: NewRole{"Customer", []string{}}, BuiltIn: true},
+}
@@ -0,0 +1,44 @@
+package models
+
+import "github.com/pkg/errors"
+
+// Routing providers.
+const (
+	RoutingOSRM   = "osrm"
+	RoutingGoogle = "google"
+)
+
+var (
+	ErrNoKitchenLocation = errors.New("the kitchen has not set its location")
+	ErrNotCovered        = errors.New("the location is outside every delivery zone")
+	ErrNoRoute           = errors.New("no road route to the location")
+)
+
+// KitchenLocation is where a kitchen's orders are picked up, which the
+// kitchen service has no place for yet.
+type KitchenLocation struct {
+	KitchenId string `json:"kitchen_id"`
+	Location  Point  `json:"location"`
+	UpdatedBy string `json:"updated_by"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// DeliveryEstimate tells how far an order travels from the kitchen, when
+// it arrives if placed now and what delivery costs.
+type DeliveryEstimate struct {
+	KitchenId     string        `json:"kitchen_id"`
+	Zone          *CoverageZone `json:"zone,omitempty"`
+	DistanceKm    float32       `json:"distance_km"`
+	TravelMinutes int           `json:"travel_minutes"`
+	PrepMinutes   int           `json:"prep_minutes"`
+	EtaMinutes    int           `json:"eta_minutes"`
+	ArrivesAt     string        `json:"arrives_at"`
+	DeliveryFee   float32       `json:"delivery_fee"`
+	// Provider is the routing service the distance came from, or
+	// straight_line when it was estimated without one.
+	Provider string `json:"provider"`
+}
+
+type DeliveryEstimates struct {
+	Estimates []DeliveryEstimate `json:"estimates"`
+}
@@ -0,0 +1,39 @@
+package models
+
+// Ordering rules a kitchen can set, named as their fields.
+const (
+	RuleMinOrderAmount = "min_order_amount"
+	RuleMaxDistance    = "max_distance_km"
+	RulePrepLeadTime   = "prep_lead_minutes"
+)
+
+// NewKitchenRules are checked before an order is placed at the kitchen.
+// Zero turns a rule off.
+type NewKitchenRules struct {
+	// MinOrderAmount is the least the items must cost after dish
+	// discounts, fees not included.
+	MinOrderAmount float32 `json:"min_order_amount"`
+	// MaxDistanceKm is how far the kitchen delivers. Orders must give
+	// their distance once it is set.
+	MaxDistanceKm float32 `json:"max_distance_km"`
+	// PrepLeadMinutes is how long before its delivery time an order
+	// must be placed.
+	PrepLeadMinutes int32 `json:"prep_lead_minutes"`
+}
+
+type KitchenRules struct {
+	KitchenId string `json:"kitchen_id"`
+	NewKitchenRules
+	UpdatedBy string `json:"updated_by,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// RuleError is returned when an order breaks one of its kitchen's rules.
+type RuleError struct {
+	Rule   string
+	Reason string
+}
+
+func (e *RuleError) Error() string {
+	return e.Reason
+}
@@ -0,0 +1,114 @@
+package models
+
+// SchemaVersion is the version of the request and response bodies the
+// handlers speak. It goes up when the services' protos rename or move
+// fields, with a SchemaMigration for each route that changed, so clients
+// on older versions keep working.
+const SchemaVersion = 1
+
+// FieldRename moves a field from its name in the older version to its
+// name in the newer one. Paths are dotted, with [] for every element of
+// an array, "items[].qty"; both paths must be within the same array
+// elements. Names are matched whichever naming the body uses, as with
+// response profiles.
+type FieldRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// FieldDefault fills a field the newer version requires but the older
+// one has no such field for.
+type FieldDefault struct {
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// SchemaMigration migrates the bodies of a route, named by Method and
+// Path as it is registered, from Version to the version after it.
+// Requests are migrated forward with Request and Defaults; responses
+// are migrated back with Response, so its renames are also written from
+// the older name to the newer one.
+type SchemaMigration struct {
+	Version     int            `json:"version"`
+	Method      string         `json:"method"`
+	Path        string         `json:"path"`
+	Description string         `json:"description"`
+	Request     []FieldRename  `json:"request,omitempty"`
+	Defaults    []FieldDefault `json:"defaults,omitempty"`
+	Response    []FieldRename  `json:"response,omitempty"`
+}
+
+// SchemaApp tells which schema version the releases of an app older than
+// Before speak, for the apps released before they sent X-Schema-Version.
+type SchemaApp struct {
+	Platform string `json:"platform"`
+	Before   string `json:"before"`
+	Version  int    `json:"version"`
+}
+
+// SchemaMigrations are the migrations of every route whose bodies changed
+// since version 1, kept for as long as clients on older versions are
+// served. A migration is added with the change that bumps SchemaVersion.
+var SchemaMigrations = []SchemaMigration{}
+
+// SchemaApps are the app releases that don't send X-Schema-Version but
+// speak an older version than SchemaVersion.
+var SchemaApps = []SchemaApp{}
+
+// EnumValue is a value of an enum clients know since schema version
+// Since. Clients on older versions are sent Fallback instead.
+type EnumValue struct {
+	Value    string `json:"value"`
+	Since    int    `json:"since"`
+	Fallback string `json:"fallback,omitempty"`
+}
+
+// EnumField is a field of a route's responses holding an enum, by a path
+// as in FieldRename.
+type EnumField struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Field  string `json:"field"`
+}
+
+// Enum is a string field of responses the services may add values to.
+// Values a client doesn't know are replaced with the value's fallback,
+// or with Fallback if the gateway doesn't know them either, and the
+// value as the service sent it is put under extensions next to the
+// field, by the field's name, so clients can still tell it.
+type Enum struct {
+	Name     string      `json:"name"`
+	Values   []EnumValue `json:"values"`
+	Fallback string      `json:"fallback"`
+	Fields   []EnumField `json:"fields"`
+}
+
+// Enums are the enums clients switch on and crash on values they don't
+// know.
+var Enums = []Enum{
+	{
+		Name: "order_status",
+		Values: []EnumValue{
+			{Value: "pending", Since: 1},
+			{Value: OrderAccepted, Since: 1},
+			{Value: OrderRejected, Since: 1},
+			{Value: "delivered", Since: 1},
+			{Value: "completed", Since: 1},
+			{Value: "cancelled", Since: 1},
+			{Value: "canceled", Since: 1},
+		},
+		// Statuses the services add come between the kitchen accepting an
+		// order and its delivery, such as preparing or on the way.
+		Fallback: OrderAccepted,
+		Fields: []EnumField{
+			{Method: "POST", Path: "/local-eats/orders", Field: "status"},
+			{Method: "GET", Path: "/local-eats/orders", Field: "orders[].status"},
+			{Method: "GET", Path: "/local-eats/orders/:id", Field: "status"},
+			{Method: "PUT", Path: "/local-eats/orders/:id/status", Field: "status"},
+			{Method: "GET", Path: "/local-eats/orders/:id/status/poll", Field: "status"},
+			{Method: "GET", Path: "/local-eats/kitchens/:id/orders", Field: "orders[].status"},
+			{Method: "POST", Path: "/local-eats/group-orders/:id/checkout", Field: "status"},
+			{Method: "GET", Path: "/local-eats/users/:id/feed", Field: "items[].order.status"},
+		},
+	},
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxSearchHistory is how many recent searches are kept per user.
+	MaxSearchHistory = 50
+	// MaxSearchRadiusKm bounds how far around a point a search looks.
+	MaxSearchRadiusKm = 100
+)
+
+type SearchQuery struct {
+	Query       string  `json:"query"`
+	CuisineType string  `json:"cuisine_type"`
+	Rating      float32 `json:"rating"`
+	// With RadiusKm set only kitchens within that distance of Lat and
+	// Lng match.
+	Lat      float64 `json:"lat,omitempty"`
+	Lng      float64 `json:"lng,omitempty"`
+	RadiusKm float64 `json:"radius_km,omitempty"`
+}
+
+func (q SearchQuery) Empty() bool {
+	return q.Query == "" && q.CuisineType == "" && q.Rating == 0 && q.RadiusKm == 0
+}
+
+// Center is the point a search with a radius looks around.
+func (q SearchQuery) Center() Point {
+	return Point{Lat: q.Lat, Lng: q.Lng}
+}
+
+func (q SearchQuery) Validate() error {
+	if q.RadiusKm < 0 || q.RadiusKm > MaxSearchRadiusKm {
+		return errors.Errorf("radius_km must be between 0 and %d", MaxSearchRadiusKm)
+	}
+	if q.RadiusKm == 0 && (q.Lat != 0 || q.Lng != 0) {
+		return errors.New("radius_km is required with lat and lng")
+	}
+	return q.Center().Validate()
+}
+
+// Matches reports whether a kitchen with the given details satisfies
+// every criterion of the query. distanceKm is how far the kitchen is from
+// the query's center, negative when its location is not known.
+func (q SearchQuery) Matches(name, description, cuisineType string, rating float32, distanceKm float64) bool {
+	if q.CuisineType != "" && !strings.EqualFold(q.CuisineType, cuisineType) {
+		return false
+	}
+	if rating < q.Rating {
+		return false
+	}
+	if q.RadiusKm > 0 && (distanceKm < 0 || distanceKm > q.RadiusKm) {
+		return false
+	}
+	if q.Query == "" {
+		return true
+	}
+	query := strings.ToLower(q.Query)
+	return strings.Contains(strings.ToLower(name), query) ||
+		strings.Contains(strings.ToLower(description), query)
+}
+
+type SearchHistoryEntry struct {
+	SearchQuery
+	SearchedAt string `json:"searched_at"`
+}
+
+type NewSavedSearch struct {
+	Name string `json:"name"`
+	SearchQuery
+	Notify bool `json:"notify"`
+}
+
+type SavedSearch struct {
+	Id     string `json:"id"`
+	UserId string `json:"user_id"`
+	Name   string `json:"name"`
+	SearchQuery
+	Notify    bool   `json:"notify"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SearchMatch is published when a new kitchen satisfies a saved search
+// whose owner opted in to notifications.
+type SearchMatch struct {
+	UserId      string `json:"user_id"`
+	SearchId    string `json:"search_id"`
+	SearchName  string `json:"search_name"`
+	KitchenId   string `json:"kitchen_id"`
+	KitchenName string `json:"kitchen_name"`
+}
+
+type SearchHistory struct {
+	Searches []SearchHistoryEntry `json:"searches"`
+}
+
+type SavedSearches struct {
+	Searches []SavedSearch `json:"searches"`
+}
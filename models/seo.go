@@ -0,0 +1,10 @@
+package models
+
+// PageMeta describes a public page for search engines and link previews.
+type PageMeta struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Url         string `json:"url"`
+	// OpenGraph holds og: properties, ready to be written as meta tags.
+	OpenGraph map[string]string `json:"open_graph"`
+}
@@ -0,0 +1,131 @@
+package models
+
+// Settings sections, as named in the change history.
+const (
+	SettingsFees        = "fees"
+	SettingsZones       = "zones"
+	SettingsMaintenance = "maintenance"
+	SettingsBanners     = "banners"
+	SettingsAppVersions = "app_versions"
+)
+
+// Settings are the platform settings ops change at runtime. They start
+// from the config and every change bumps Version.
+type Settings struct {
+	Fees        FeeSettings      `json:"fees"`
+	Zones       ZoneSettings     `json:"zones"`
+	Maintenance Maintenance      `json:"maintenance"`
+	Banners     []PlatformBanner `json:"banners"`
+	AppVersions AppVersions      `json:"app_versions"`
+	Version     int              `json:"version"`
+	UpdatedBy   string           `json:"updated_by,omitempty"`
+	UpdatedAt   string           `json:"updated_at,omitempty"`
+}
+
+// FeeSettings price every order. CommissionPercent applies to kitchens
+// without a rate of their own or of their cuisine.
+type FeeSettings struct {
+	DeliveryFee       float32 `json:"delivery_fee"`
+	DeliveryFeePerKm  float32 `json:"delivery_fee_per_km"`
+	ServiceFeePercent float32 `json:"service_fee_percent"`
+	TaxPercent        float32 `json:"tax_percent"`
+	CommissionPercent float32 `json:"commission_percent"`
+}
+
+// ZoneSettings tell whether delivery is limited to the delivery zones.
+// When not enforced every location is covered, whatever the zones.
+type ZoneSettings struct {
+	Enforced bool `json:"enforced"`
+}
+
+// Maintenance turns customers and kitchens away with 503 while enabled,
+// showing Message. EndsAt, when set, is when it is expected to end.
+type Maintenance struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+	EndsAt  string `json:"ends_at,omitempty"`
+}
+
+// AppVersions are the oldest versions of the apps still served. Older
+// ones are answered with 426 and asked to upgrade from their store, with
+// Message shown. An empty minimum serves every version of the platform.
+type AppVersions struct {
+	MinIOS          string `json:"min_ios,omitempty"`
+	MinAndroid      string `json:"min_android,omitempty"`
+	MinWeb          string `json:"min_web,omitempty"`
+	IOSStoreUrl     string `json:"ios_store_url,omitempty"`
+	AndroidStoreUrl string `json:"android_store_url,omitempty"`
+	Message         string `json:"message,omitempty"`
+}
+
+// Minimum is the oldest version of platform still served, "" if all are.
+func (v AppVersions) Minimum(platform string) string {
+	switch platform {
+	case PlatformIOS:
+		return v.MinIOS
+	case PlatformAndroid:
+		return v.MinAndroid
+	case PlatformWeb:
+		return v.MinWeb
+	}
+	return ""
+}
+
+// StoreUrl is where the app of platform is upgraded from, "" for the web
+// app, which upgrades by reloading.
+func (v AppVersions) StoreUrl(platform string) string {
+	switch platform {
+	case PlatformIOS:
+		return v.IOSStoreUrl
+	case PlatformAndroid:
+		return v.AndroidStoreUrl
+	}
+	return ""
+}
+
+// PlatformBanner is shown at the top of the app from StartsAt until
+// EndsAt, or until removed when EndsAt is empty.
+type PlatformBanner struct {
+	Id       string `json:"id"`
+	Title    string `json:"title"`
+	Body     string `json:"body,omitempty"`
+	LinkUrl  string `json:"link_url,omitempty"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at,omitempty"`
+}
+
+type PlatformBanners struct {
+	Banners []PlatformBanner `json:"banners"`
+}
+
+// SettingsUpdate replaces the sections it has. With Version set, it
+// only applies to that version of the settings, so two admins can't
+// overwrite each other's changes unknowingly.
+type SettingsUpdate struct {
+	Version     *int              `json:"version,omitempty"`
+	Fees        *FeeSettings      `json:"fees,omitempty"`
+	Zones       *ZoneSettings     `json:"zones,omitempty"`
+	Maintenance *Maintenance      `json:"maintenance,omitempty"`
+	Banners     *[]PlatformBanner `json:"banners,omitempty"`
+	AppVersions *AppVersions      `json:"app_versions,omitempty"`
+}
+
+// SettingsChange is an update of the settings, with each changed section
+// before and after it.
+type SettingsChange struct {
+	Id        string          `json:"id"`
+	Version   int             `json:"version"`
+	Sections  []SectionChange `json:"sections"`
+	ChangedBy string          `json:"changed_by,omitempty"`
+	ChangedAt string          `json:"changed_at"`
+}
+
+type SectionChange struct {
+	Section string `json:"section"`
+	Before  any    `json:"before"`
+	After   any    `json:"after"`
+}
+
+type SettingsChanges struct {
+	Changes []SettingsChange `json:"changes"`
+}
@@ -0,0 +1,38 @@
+package models
+
+// SLOs kept for every backend service.
+const (
+	SLOAvailability = "availability"
+	SLOLatency      = "latency"
+)
+
+// SLOReport is where each backend service stands against its SLOs over
+// Window, as measured by the gateway since it started.
+type SLOReport struct {
+	Window   string       `json:"window"`
+	Backends []BackendSLO `json:"backends"`
+}
+
+type BackendSLO struct {
+	Backend      string    `json:"backend"`
+	Availability SLOStatus `json:"availability"`
+	Latency      SLOStatus `json:"latency"`
+}
+
+// SLOStatus is an SLO of a backend. SLI is the share of good calls over
+// the window; BudgetRemaining the share of the error budget left, below
+// zero once it is overspent. A burn rate of 1 spends the budget exactly
+// over the window.
+type SLOStatus struct {
+	Objective       float64    `json:"objective"`
+	Calls           int64      `json:"calls"`
+	Good            int64      `json:"good"`
+	SLI             float64    `json:"sli"`
+	BudgetRemaining float64    `json:"budget_remaining"`
+	BurnRates       []BurnRate `json:"burn_rates"`
+}
+
+type BurnRate struct {
+	Window string  `json:"window"`
+	Rate   float64 `json:"rate"`
+}
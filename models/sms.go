@@ -0,0 +1,89 @@
+package models
+
+const (
+	SMSEskiz      = "eskiz"
+	SMSPlaymobile = "playmobile"
+	SMSTwilio     = "twilio"
+)
+
+const (
+	SMSSent      = "sent"
+	SMSDelivered = "delivered"
+	SMSFailed    = "failed"
+
+	SMSPurposeOTP         = "otp"
+	SMSPurposeOrderStatus = "order_status"
+	SMSPurposeMarketing   = "marketing"
+)
+
+// SMSMessage is a text message sent by the gateway. Attempts records the
+// providers that failed before one accepted it.
+type SMSMessage struct {
+	Id         string       `json:"id"`
+	To         string       `json:"to"`
+	Text       string       `json:"text"`
+	Purpose    string       `json:"purpose"`
+	Campaign   string       `json:"campaign,omitempty"`
+	Provider   string       `json:"provider,omitempty"`
+	ProviderId string       `json:"provider_id,omitempty"`
+	Status     string       `json:"status"`
+	Error      string       `json:"error,omitempty"`
+	Attempts   []SMSAttempt `json:"attempts"`
+	CreatedAt  string       `json:"created_at"`
+	UpdatedAt  string       `json:"updated_at"`
+}
+
+type SMSAttempt struct {
+	Provider string `json:"provider"`
+	Error    string `json:"error"`
+	At       string `json:"at"`
+}
+
+type SMSMessages struct {
+	Messages []SMSMessage `json:"messages"`
+}
+
+type NewSMSCampaign struct {
+	Phones []string `json:"phones"`
+	Text   string   `json:"text"`
+}
+
+// SMSCampaign is a marketing message sent to many phones. Its messages
+// are sent in the background and listed by campaign ID.
+type SMSCampaign struct {
+	Id         string `json:"id"`
+	Text       string `json:"text"`
+	Recipients int    `json:"recipients"`
+	CreatedBy  string `json:"created_by"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type NewOTP struct {
+	Phone string `json:"phone"`
+}
+
+type OTPSent struct {
+	Phone       string `json:"phone"`
+	ExpiresAt   string `json:"expires_at"`
+	ResendAfter string `json:"resend_after"`
+}
+
+type OTPVerification struct {
+	Phone string `json:"phone"`
+	Code  string `json:"code"`
+}
+
+type OTPVerified struct {
+	Phone    string `json:"phone"`
+	Verified bool   `json:"verified"`
+}
+
+// OTP is a one-time code sent to a phone. Only the code's hash is kept.
+type OTP struct {
+	UserId   string
+	Phone    string
+	CodeHash string
+	Attempts int
+	SentAt   string
+	Expires  string
+}
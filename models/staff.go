@@ -0,0 +1,50 @@
+package models
+
+import "slices"
+
+// Staff permissions. Staff act as the kitchen on the routes their
+// permissions cover; kitchen settings and verification stay with the
+// owner.
+const (
+	StaffOrders  = "orders"
+	StaffMenu    = "menu"
+	StaffPayouts = "payouts"
+
+	StaffInvited = "invited"
+	StaffActive  = "active"
+)
+
+// StaffPermissions lists the permissions staff may be granted.
+var StaffPermissions = []string{StaffOrders, StaffMenu, StaffPayouts}
+
+type NewStaffMember struct {
+	UserId      string   `json:"user_id"`
+	Permissions []string `json:"permissions"`
+}
+
+type StaffPermissionsUpdate struct {
+	Permissions []string `json:"permissions"`
+}
+
+// StaffMember is a user the kitchen's owner invited to help run the
+// kitchen. The invitation must be accepted before its permissions apply.
+type StaffMember struct {
+	KitchenId   string   `json:"kitchen_id"`
+	KitchenName string   `json:"kitchen_name"`
+	UserId      string   `json:"user_id"`
+	Username    string   `json:"username"`
+	Permissions []string `json:"permissions"`
+	Status      string   `json:"status"`
+	InvitedBy   string   `json:"invited_by"`
+	InvitedAt   string   `json:"invited_at"`
+	JoinedAt    string   `json:"joined_at,omitempty"`
+}
+
+// Allows reports whether the member has joined and holds permission.
+func (m StaffMember) Allows(permission string) bool {
+	return m.Status == StaffActive && slices.Contains(m.Permissions, permission)
+}
+
+type StaffMembers struct {
+	Staff []StaffMember `json:"staff"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "fmt"
+
+type NewDishStock struct {
+	DailyCount int32 `json:"daily_count"`
+}
+
+// DishStock limits how many portions of a dish can be ordered per day
+// (UTC). Sold starts over every day; dishes without a stock are not
+// limited.
+type DishStock struct {
+	DishId     string `json:"dish_id"`
+	DishName   string `json:"dish_name"`
+	KitchenId  string `json:"kitchen_id"`
+	DailyCount int32  `json:"daily_count"`
+	Sold       int32  `json:"sold"`
+	Remaining  int32  `json:"remaining"`
+	Date       string `json:"date"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+type DishStocks struct {
+	Stocks []DishStock `json:"stocks"`
+}
+
+// StockError is returned when an order asks for more portions of a dish
+// than are left today.
+type StockError struct {
+	DishId    string
+	DishName  string
+	Remaining int32
+}
+
+func (e *StockError) Error() string {
+	if e.Remaining == 0 {
+		return fmt.Sprintf("%s is sold out", e.DishName)
+	}
+	return fmt.Sprintf("only %d left of %s", e.Remaining, e.DishName)
+}
@@ -0,0 +1,64 @@
+package models
+
+const (
+	TicketOpen       = "open"
+	TicketInProgress = "in_progress"
+	TicketResolved   = "resolved"
+	TicketClosed     = "closed"
+
+	RoleAdmin    = "admin"
+	RoleKitchen  = "kitchen"
+	RoleCustomer = "customer"
+)
+
+// TicketTransitions lists the statuses a ticket may move to from each
+// status. Resolved tickets can be reopened; closed ones are final.
+var TicketTransitions = map[string][]string{
+	TicketOpen:       {TicketInProgress, TicketResolved, TicketClosed},
+	TicketInProgress: {TicketResolved, TicketClosed},
+	TicketResolved:   {TicketOpen, TicketClosed},
+	TicketClosed:     {},
+}
+
+type NewTicket struct {
+	OrderId     string   `json:"order_id"`
+	Subject     string   `json:"subject"`
+	Description string   `json:"description"`
+	Attachments []string `json:"attachments"`
+}
+
+type Ticket struct {
+	Id          string          `json:"id"`
+	OrderId     string          `json:"order_id"`
+	KitchenId   string          `json:"kitchen_id"`
+	UserId      string          `json:"user_id"`
+	Subject     string          `json:"subject"`
+	Description string          `json:"description"`
+	Status      string          `json:"status"`
+	Attachments []string        `json:"attachments"`
+	Messages    []TicketMessage `json:"messages"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+}
+
+type Tickets struct {
+	Tickets []Ticket `json:"tickets"`
+}
+
+type NewTicketMessage struct {
+	Body        string   `json:"body"`
+	Attachments []string `json:"attachments"`
+}
+
+type TicketMessage struct {
+	Id          string   `json:"id"`
+	AuthorId    string   `json:"author_id"`
+	AuthorRole  string   `json:"author_role"`
+	Body        string   `json:"body"`
+	Attachments []string `json:"attachments"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+type TicketStatus struct {
+	Status string `json:"status"`
+}
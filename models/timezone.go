@@ -0,0 +1,18 @@
+package models
+
+// NewKitchenTimeZone is the IANA name of a kitchen's time zone, such as
+// "Asia/Tashkent".
+type NewKitchenTimeZone struct {
+	TimeZone string `json:"time_zone"`
+}
+
+// KitchenTimeZone is the zone a kitchen's working hours, statistics and
+// scheduled orders are in. Default tells that the kitchen has not set
+// one, so it is in the gateway's default zone.
+type KitchenTimeZone struct {
+	KitchenId string `json:"kitchen_id"`
+	TimeZone  string `json:"time_zone"`
+	Default   bool   `json:"default,omitempty"`
+	UpdatedBy string `json:"updated_by,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
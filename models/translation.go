@@ -0,0 +1,39 @@
+package models
+
+// Locales the apps are translated into, from Accept-Language.
+const (
+	LocaleUzbek   = "uz"
+	LocaleRussian = "ru"
+	LocaleEnglish = "en"
+)
+
+var Locales = []string{LocaleUzbek, LocaleRussian, LocaleEnglish}
+
+// What a translation is of.
+const (
+	TranslationKitchen = "kitchen"
+	TranslationDish    = "dish"
+)
+
+// NewTranslation is a kitchen's or a dish's name and description in a
+// locale. An empty field leaves the one the services send.
+type NewTranslation struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Translation is shown instead of a kitchen's or a dish's own name and
+// description to users of its locale, for services that don't translate
+// them themselves.
+type Translation struct {
+	Kind     string `json:"kind"`
+	EntityId string `json:"entity_id"`
+	Locale   string `json:"locale"`
+	NewTranslation
+	UpdatedBy string `json:"updated_by,omitempty"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type Translations struct {
+	Translations []Translation `json:"translations"`
+}
@@ -0,0 +1,43 @@
+package models
+
+const (
+	UploadDishImage  = "dish_image"
+	UploadBulkImport = "bulk_import"
+	UploadAttachment = "attachment"
+	UploadKitchenDoc = "kitchen_document"
+	UploadInspection = "inspection_report"
+
+	UploadInProgress = "in_progress"
+	UploadCompleted  = "completed"
+)
+
+// UploadTypes lists the content types accepted for every upload purpose.
+var UploadTypes = map[string][]string{
+	UploadDishImage:  {"image/jpeg", "image/png", "image/webp"},
+	UploadBulkImport: {"text/csv", "application/json"},
+	UploadAttachment: {"image/jpeg", "image/png", "image/webp", "application/pdf"},
+	UploadKitchenDoc: {"image/jpeg", "image/png", "application/pdf"},
+	UploadInspection: {"image/jpeg", "image/png", "application/pdf"},
+}
+
+type NewUpload struct {
+	Purpose     string `json:"purpose"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+type Upload struct {
+	Id string `json:"id"`
+	// UserId is the uploader, the only one besides admins to change,
+	// delete or attach the upload.
+	UserId      string `json:"user_id"`
+	Purpose     string `json:"purpose"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Offset      int64  `json:"offset"`
+	Status      string `json:"status"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
@@ -0,0 +1,109 @@
+package models
+
+// Platforms of the apps calling the gateway, from X-Platform.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWeb     = "web"
+	// PlatformOther are clients sending no or an unknown platform.
+	PlatformOther = "other"
+)
+
+// AppUnknown is the version of clients sending none, or a malformed one.
+const AppUnknown = "unknown"
+
+// ClientApp is the app a request came from.
+type ClientApp struct {
+	Platform string `json:"platform"`
+	Version  string `json:"version"`
+}
+
+// UpgradeRequired answers the requests of apps older than the oldest
+// version of their platform served, with 426. StoreUrl is where to
+// upgrade from, empty for the web app, which upgrades by reloading.
+type UpgradeRequired struct {
+	Error      string `json:"error"`
+	Message    string `json:"message,omitempty"`
+	Platform   string `json:"platform"`
+	Version    string `json:"version"`
+	MinVersion string `json:"min_version"`
+	StoreUrl   string `json:"store_url,omitempty"`
+}
+
+// APIUsage counts the calls of a route by an app version on a day
+// (YYYY-MM-DD). Route is as it is registered, "/local-eats/orders/:id".
+// Upgrades are the calls answered with 426, the app being too old to be
+// served.
+type APIUsage struct {
+	Date         string `json:"date"`
+	Method       string `json:"method"`
+	Route        string `json:"route"`
+	Platform     string `json:"platform"`
+	Version      string `json:"version"`
+	Calls        int64  `json:"calls"`
+	ClientErrors int64  `json:"client_errors"`
+	ServerErrors int64  `json:"server_errors"`
+	Upgrades     int64  `json:"upgrades"`
+	LastSeenAt   string `json:"last_seen_at"`
+}
+
+// UsageFilter narrows an API usage report. Below keeps the app versions
+// older than it, to tell which routes old apps still call.
+type UsageFilter struct {
+	From     string
+	To       string
+	Route    string
+	Platform string
+	Below    string
+}
+
+// VersionUsage is how an app version called a route.
+type VersionUsage struct {
+	Platform     string  `json:"platform"`
+	Version      string  `json:"version"`
+	Calls        int64   `json:"calls"`
+	ClientErrors int64   `json:"client_errors"`
+	ServerErrors int64   `json:"server_errors"`
+	Upgrades     int64   `json:"upgrades"`
+	ErrorRate    float64 `json:"error_rate"`
+	LastSeenAt   string  `json:"last_seen_at"`
+}
+
+// RouteUsage is how a route was called, with a breakdown by app version,
+// the most calling first. ErrorRate is the share of calls answered with
+// a server error.
+type RouteUsage struct {
+	Method       string         `json:"method"`
+	Route        string         `json:"route"`
+	Calls        int64          `json:"calls"`
+	ClientErrors int64          `json:"client_errors"`
+	ServerErrors int64          `json:"server_errors"`
+	Upgrades     int64          `json:"upgrades"`
+	ErrorRate    float64        `json:"error_rate"`
+	LastSeenAt   string         `json:"last_seen_at"`
+	Versions     []VersionUsage `json:"versions"`
+}
+
+// APIUsageReport lists the routes called from From to To, the most called
+// first.
+type APIUsageReport struct {
+	From   string       `json:"from"`
+	To     string       `json:"to"`
+	Routes []RouteUsage `json:"routes"`
+}
+
+// AppVersionUsage is how often an app version called the gateway, and
+// whether it is still served.
+type AppVersionUsage struct {
+	VersionUsage
+	Supported bool `json:"supported"`
+}
+
+// AppVersionReport lists the app versions that called the gateway from
+// From to To, by platform and newest first, with the oldest served.
+type AppVersionReport struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Minimums AppVersions       `json:"minimums"`
+	Versions []AppVersionUsage `json:"versions"`
+}
@@ -0,0 +1,55 @@
+package models
+
+const (
+	KitchenDocLicense           = "license"
+	KitchenDocHealthCertificate = "health_certificate"
+
+	VerificationNone     = "unverified"
+	VerificationPending  = "pending"
+	VerificationVerified = "verified"
+	VerificationRejected = "rejected"
+)
+
+type NewKitchenDocument struct {
+	// UploadId is a completed kitchen_document upload.
+	UploadId string `json:"upload_id"`
+	Type     string `json:"type"`
+	// ExpiresAt is the document's expiry date (YYYY-MM-DD), if it has one.
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// KitchenDocument is a licence or certificate a kitchen submitted for
+// verification. Its file is downloaded through the upload.
+type KitchenDocument struct {
+	Id         string `json:"id"`
+	KitchenId  string `json:"kitchen_id"`
+	UploadId   string `json:"upload_id"`
+	Type       string `json:"type"`
+	Filename   string `json:"filename"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	UploadedBy string `json:"uploaded_by"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type VerificationDecision struct {
+	// Status is verified to grant the badge or rejected to refuse or
+	// revoke it.
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
+
+// KitchenVerification is where a kitchen stands in getting the verified
+// badge.
+type KitchenVerification struct {
+	KitchenId   string            `json:"kitchen_id"`
+	Status      string            `json:"status"`
+	Note        string            `json:"note,omitempty"`
+	ReviewedBy  string            `json:"reviewed_by,omitempty"`
+	ReviewedAt  string            `json:"reviewed_at,omitempty"`
+	SubmittedAt string            `json:"submitted_at,omitempty"`
+	Documents   []KitchenDocument `json:"documents"`
+}
+
+type KitchenVerifications struct {
+	Verifications []KitchenVerification `json:"verifications"`
+}
@@ -0,0 +1,31 @@
+package models
+
+const (
+	DishAlertPush  = "push"
+	DishAlertEmail = "email"
+)
+
+type NewDishAlert struct {
+	// Channels defaults to push only.
+	Channels []string `json:"channels,omitempty"`
+}
+
+// DishAlert is a customer waiting for a sold-out dish. It is removed once
+// the dish is available again and the customer has been notified.
+type DishAlert struct {
+	UserId    string   `json:"user_id"`
+	DishId    string   `json:"dish_id"`
+	Channels  []string `json:"channels"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// DishAvailable is published for every customer waiting for a dish that
+// has become available. Push notifications are sent by the consumers of
+// this event.
+type DishAvailable struct {
+	UserId    string   `json:"user_id"`
+	DishId    string   `json:"dish_id"`
+	DishName  string   `json:"dish_name"`
+	KitchenId string   `json:"kitchen_id"`
+	Channels  []string `json:"channels"`
+}
@@ -0,0 +1,38 @@
+package models
+
+var WebhookEvents = []string{EventOrderCreated, EventOrderStatusChanged, EventReportGenerated,
+	EventRefundRequested}
+
+type Webhook struct {
+	Id        string   `json:"id"`
+	KitchenId string   `json:"kitchen_id"`
+	Url       string   `json:"url"`
+	Events    []string `json:"events"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at,omitempty"`
+}
+
+type NewWebhook struct {
+	Url    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+type Webhooks struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+type WebhookDelivery struct {
+	Id         string `json:"id"`
+	WebhookId  string `json:"webhook_id"`
+	Event      string `json:"event"`
+	Attempts   int    `json:"attempts"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Success    bool   `json:"success"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type WebhookDeliveries struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
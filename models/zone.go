@@ -0,0 +1,94 @@
+package models
+
+import "github.com/pkg/errors"
+
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+func (p Point) Validate() error {
+	if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+		return errors.Errorf("point (%g, %g) is out of range", p.Lat, p.Lng)
+	}
+	return nil
+}
+
+// Polygon is a ring of points whose last point connects back to the
+// first. Zones are small enough to treat coordinates as planar.
+type Polygon []Point
+
+func (p Polygon) Validate() error {
+	if len(p) < 3 {
+		return errors.New("polygon needs at least 3 points")
+	}
+	for _, pt := range p {
+		if err := pt.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Contains reports whether pt lies inside the polygon by the even-odd
+// rule: a ray cast from pt crosses the boundary an odd number of times.
+func (p Polygon) Contains(pt Point) bool {
+	in := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Lat > pt.Lat) != (b.Lat > pt.Lat) &&
+			pt.Lng < (b.Lng-a.Lng)*(pt.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			in = !in
+		}
+	}
+	return in
+}
+
+type NewZone struct {
+	Name    string  `json:"name"`
+	City    string  `json:"city"`
+	Polygon Polygon `json:"polygon"`
+	// Active zones count for coverage. It defaults to true.
+	Active *bool `json:"active,omitempty"`
+}
+
+// Zone is an area the service delivers to.
+type Zone struct {
+	Id        string  `json:"id"`
+	Name      string  `json:"name"`
+	City      string  `json:"city"`
+	Polygon   Polygon `json:"polygon"`
+	Active    bool    `json:"active"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type Zones struct {
+	Zones []Zone `json:"zones"`
+}
+
+type CoverageZone struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	City string `json:"city"`
+}
+
+// Coverage tells whether the service operates at a location.
+type Coverage struct {
+	Covered bool          `json:"covered"`
+	Zone    *CoverageZone `json:"zone,omitempty"`
+}
+
+// Centroid is the mean of the polygon's points, a point inside the
+// small, roughly convex shapes zones have.
+func (p Polygon) Centroid() Point {
+	var c Point
+	for _, pt := range p {
+		c.Lat += pt.Lat
+		c.Lng += pt.Lng
+	}
+	if n := float64(len(p)); n > 0 {
+		c.Lat, c.Lng = c.Lat/n, c.Lng/n
+	}
+	return c
+}
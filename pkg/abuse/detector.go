@@ -0,0 +1,362 @@
+package abuse
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"api-gateway/pkg/routing"
+	"api-gateway/storage"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// cooldown is how long a signal isn't flagged again for the same
+	// account, so a sustained burst adds up slowly rather than at once.
+	cooldown = 10 * time.Minute
+	// maxFlags bounds the flags kept per account.
+	maxFlags = 50
+	// maxUserAgents is how many user agents an account may use in a
+	// window before it looks like a rotating bot.
+	maxUserAgents = 5
+	// maxTracked bounds the accounts whose requests are being counted;
+	// idle ones are dropped past it.
+	maxTracked = 100000
+	// travelHorizon is how long an order's location is kept; at any
+	// sensible speed, later orders can be anywhere.
+	travelHorizon = 24 * time.Hour
+	// minTravelKm keeps orders close together, which GPS noise or a
+	// moved pin can place anywhere, from looking like travel.
+	minTravelKm = 100
+)
+
+var (
+	ErrUnknownStatus = errors.New("unknown abuse status")
+
+	// weights are what each signal adds to an account's score.
+	weights = map[string]int{
+		models.SignalVelocity:         20,
+		models.SignalNoUserAgent:      10,
+		models.SignalBotUserAgent:     30,
+		models.SignalUserAgentChurn:   20,
+		models.SignalImpossibleTravel: 50,
+	}
+
+	// botAgents are user agent markers of HTTP libraries and headless
+	// browsers, which the apps never send.
+	botAgents = []string{
+		"curl/", "wget/", "python-requests", "python-urllib", "aiohttp",
+		"go-http-client", "java/", "libwww-perl", "scrapy", "httpclient",
+		"headlesschrome", "phantomjs", "selenium", "puppeteer", "bot/", "spider", "crawler",
+	}
+)
+
+// Request is what the detector looks at in a request.
+type Request struct {
+	UserId    string
+	RequestId string
+	UserAgent string
+}
+
+// activity is an account's recent requests.
+type activity struct {
+	start    time.Time
+	requests int
+	agents   []string
+	lastSeen time.Time
+}
+
+// location is where an account's last order went.
+type location struct {
+	point models.Point
+	at    time.Time
+}
+
+// Detector scores accounts by the signals their requests and orders
+// raise: too many requests a minute, user agents of bots or rotating
+// ones, and orders to places too far apart for the time between them.
+// Accounts whose score in the last window reaches the thresholds are
+// challenged or shadow-banned. Request counts are kept in memory; only
+// flagged accounts get a profile.
+type Detector struct {
+	store     *storage.Store[models.AbuseProfile]
+	velocity  int
+	challenge int
+	shadowBan int
+	window    time.Duration
+	maxKmh    float64
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	activity map[string]*activity
+	orders   map[string]location
+}
+
+func NewDetector(cfg *config.Config, store *storage.Store[models.AbuseProfile], logger *slog.Logger) *Detector {
+	return &Detector{
+		store:     store,
+		velocity:  cfg.ABUSE_VELOCITY_LIMIT,
+		challenge: cfg.ABUSE_CHALLENGE_SCORE,
+		shadowBan: cfg.ABUSE_SHADOW_BAN_SCORE,
+		window:    cfg.ABUSE_WINDOW,
+		maxKmh:    cfg.ABUSE_MAX_TRAVEL_KMH,
+		logger:    logger,
+		activity:  make(map[string]*activity),
+		orders:    make(map[string]location),
+	}
+}
+
+// Inspect scores the request and tells its account's status.
+func (d *Detector) Inspect(r Request, now time.Time) string {
+	flags := d.requestFlags(r, now)
+	if len(flags) > 0 {
+		return d.flag(r.UserId, flags, now).Status
+	}
+	return d.Status(r.UserId, now)
+}
+
+// Order checks how far the account's order goes from its last one, given
+// where it is delivered.
+func (d *Detector) Order(userID, requestID string, to models.Point, now time.Time) {
+	d.mu.Lock()
+	last, ok := d.orders[userID]
+	if !ok && len(d.orders) >= maxTracked {
+		for id, l := range d.orders {
+			if now.Sub(l.at) >= travelHorizon {
+				delete(d.orders, id)
+			}
+		}
+	}
+	d.orders[userID] = location{point: to, at: now}
+	d.mu.Unlock()
+	if !ok || d.maxKmh <= 0 || now.Sub(last.at) >= travelHorizon {
+		return
+	}
+
+	km := routing.Haversine(last.point, to)
+	hours := now.Sub(last.at).Hours()
+	if km < minTravelKm || hours > 0 && km/hours <= d.maxKmh {
+		return
+	}
+	d.flag(userID, []models.AbuseFlag{{
+		Signal:    models.SignalImpossibleTravel,
+		Detail:    fmt.Sprintf("ordered %.0f km away from the last order %s before", km, now.Sub(last.at).Round(time.Minute)),
+		RequestId: requestID,
+	}}, now)
+}
+
+// Status tells the account's status. Automatic statuses lapse once the
+// flags that set them are out of the window.
+func (d *Detector) Status(userID string, now time.Time) string {
+	p, ok := d.store.Get(userID)
+	if !ok {
+		return models.AbuseClear
+	}
+	if p.Manual || p.Status == models.AbuseClear || d.score(p.Flags, now) == p.Score {
+		return p.Status
+	}
+	return d.flag(userID, nil, now).Status
+}
+
+// Pass clears the account's flags once it passed a challenge. Statuses
+// set by an admin stay.
+func (d *Detector) Pass(userID string, now time.Time) {
+	if _, ok := d.store.Get(userID); !ok {
+		return
+	}
+	d.store.Update(userID, func(p models.AbuseProfile, _ bool) models.AbuseProfile {
+		p.PassedAt = now.Format(time.RFC3339)
+		p.UpdatedAt = p.PassedAt
+		if !p.Manual {
+			p.Flags, p.Score, p.Status = nil, 0, models.AbuseClear
+		}
+		return p
+	})
+	d.logger.Info("user " + userID + " passed the abuse challenge")
+}
+
+// Review sets the account's status on an admin's behalf, or hands it
+// back to scoring when status is auto.
+func (d *Detector) Review(userID string, data models.AbuseReview, reviewer string, now time.Time) (models.AbuseProfile, error) {
+	switch data.Status {
+	case "auto", models.AbuseClear, models.AbuseChallenge, models.AbuseShadowBan:
+	default:
+		return models.AbuseProfile{}, errors.Wrapf(ErrUnknownStatus, "%q", data.Status)
+	}
+
+	return d.store.Update(userID, func(p models.AbuseProfile, _ bool) models.AbuseProfile {
+		p.UserId = userID
+		p.Flags = d.recent(p.Flags, now)
+		p.Score = d.score(p.Flags, now)
+		p.Manual = data.Status != "auto"
+		p.Status = data.Status
+		if !p.Manual {
+			p.Status = d.status(p.Score)
+		}
+		p.Note = data.Note
+		p.ReviewedBy = reviewer
+		p.ReviewedAt = now.Format(time.RFC3339)
+		p.UpdatedAt = p.ReviewedAt
+		return p
+	}), nil
+}
+
+// requestFlags raises the signals of a request that are not cooling down.
+func (d *Detector) requestFlags(r Request, now time.Time) []models.AbuseFlag {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, ok := d.activity[r.UserId]
+	if !ok {
+		if len(d.activity) >= maxTracked {
+			d.sweep(now)
+		}
+		a = &activity{start: now}
+		d.activity[r.UserId] = a
+	}
+	if now.Sub(a.start) >= time.Minute {
+		a.start, a.requests = now, 0
+	}
+	if now.Sub(a.lastSeen) >= d.window {
+		a.agents = nil
+	}
+	a.requests++
+	a.lastSeen = now
+
+	var flags []models.AbuseFlag
+	if d.velocity > 0 && a.requests == d.velocity+1 {
+		flags = append(flags, models.AbuseFlag{
+			Signal: models.SignalVelocity,
+			Detail: fmt.Sprintf("more than %d requests a minute", d.velocity),
+		})
+	}
+
+	ua := strings.ToLower(strings.TrimSpace(r.UserAgent))
+	switch {
+	case ua == "":
+		flags = append(flags, models.AbuseFlag{Signal: models.SignalNoUserAgent, Detail: "no user agent"})
+	case slices.ContainsFunc(botAgents, func(m string) bool { return strings.Contains(ua, m) }):
+		flags = append(flags, models.AbuseFlag{Signal: models.SignalBotUserAgent, Detail: "user agent " + r.UserAgent})
+	}
+	if ua != "" && !slices.Contains(a.agents, ua) {
+		a.agents = append(a.agents, ua)
+		if len(a.agents) == maxUserAgents+1 {
+			flags = append(flags, models.AbuseFlag{
+				Signal: models.SignalUserAgentChurn,
+				Detail: fmt.Sprintf("more than %d user agents", maxUserAgents),
+			})
+		}
+	}
+
+	for i := range flags {
+		flags[i].RequestId = r.RequestId
+	}
+	return flags
+}
+
+// sweep drops the accounts idle for a window.
+func (d *Detector) sweep(now time.Time) {
+	for id, a := range d.activity {
+		if now.Sub(a.lastSeen) >= d.window {
+			delete(d.activity, id)
+		}
+	}
+}
+
+// flag adds the flags not cooling down to the account's profile and
+// scores it again.
+func (d *Detector) flag(userID string, flags []models.AbuseFlag, now time.Time) models.AbuseProfile {
+	var added []string
+	p := d.store.Update(userID, func(p models.AbuseProfile, _ bool) models.AbuseProfile {
+		p.UserId = userID
+		p.Flags = d.recent(p.Flags, now)
+		for _, f := range flags {
+			if d.cooling(p.Flags, f.Signal, now) {
+				continue
+			}
+			f.Weight = weights[f.Signal]
+			f.At = now.Format(time.RFC3339)
+			p.Flags = append(p.Flags, f)
+			added = append(added, f.Signal)
+		}
+		if len(p.Flags) > maxFlags {
+			p.Flags = p.Flags[len(p.Flags)-maxFlags:]
+		}
+		p.Score = d.score(p.Flags, now)
+		if !p.Manual {
+			p.Status = d.status(p.Score)
+		}
+		p.UpdatedAt = now.Format(time.RFC3339)
+		return p
+	})
+
+	if len(added) > 0 {
+		d.logger.Warn(fmt.Sprintf("user %s flagged for %s, abuse score %d, status %s",
+			userID, strings.Join(added, ", "), p.Score, p.Status))
+	}
+	return p
+}
+
+// recent keeps the flags of the last window.
+func (d *Detector) recent(flags []models.AbuseFlag, now time.Time) []models.AbuseFlag {
+	return slices.DeleteFunc(slices.Clone(flags), func(f models.AbuseFlag) bool {
+		return now.Sub(parseTime(f.At)) >= d.window
+	})
+}
+
+func (d *Detector) cooling(flags []models.AbuseFlag, signal string, now time.Time) bool {
+	return slices.ContainsFunc(flags, func(f models.AbuseFlag) bool {
+		return f.Signal == signal && now.Sub(parseTime(f.At)) < cooldown
+	})
+}
+
+func (d *Detector) score(flags []models.AbuseFlag, now time.Time) int {
+	score := 0
+	for _, f := range flags {
+		if now.Sub(parseTime(f.At)) < d.window {
+			score += f.Weight
+		}
+	}
+	return score
+}
+
+func (d *Detector) status(score int) string {
+	switch {
+	case d.shadowBan > 0 && score >= d.shadowBan:
+		return models.AbuseShadowBan
+	case d.challenge > 0 && score >= d.challenge:
+		return models.AbuseChallenge
+	}
+	return models.AbuseClear
+}
+
+// Profile returns the account's profile, scored again if flags lapsed.
+func (d *Detector) Profile(userID string, now time.Time) (models.AbuseProfile, bool) {
+	p, ok := d.store.Get(userID)
+	if !ok || d.score(p.Flags, now) == p.Score {
+		return p, ok
+	}
+	return d.flag(userID, nil, now), true
+}
+
+// List returns the profiles of the accounts that were ever flagged or
+// reviewed, scored again if flags lapsed.
+func (d *Detector) List(now time.Time) []models.AbuseProfile {
+	res := d.store.List()
+	for i, p := range res {
+		if d.score(p.Flags, now) != p.Score {
+			res[i] = d.flag(p.UserId, nil, now)
+		}
+	}
+	return res
+}
+
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
@@ -0,0 +1,405 @@
+package analytics
+
+import (
+	pbe "api-gateway/genproto/extra"
+	pbk "api-gateway/genproto/kitchen"
+	pbo "api-gateway/genproto/order"
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const pageSize = 100
+
+// timeLayouts are the formats the order service uses for delivery times.
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05"}
+
+type kitchen struct {
+	Id   string
+	Name string
+	City string
+}
+
+type order struct {
+	Id        string
+	KitchenId string
+	Customer  string
+	Status    string
+	Amount    float32
+	Time      time.Time
+}
+
+// Aggregator computes platform-wide figures by fanning out to the
+// kitchen, order and extra services. Intermediate results are cached, so
+// a dashboard polling several endpoints costs one round of calls.
+// Sandbox orders are test data and left out of every figure.
+type Aggregator struct {
+	kitchens    pbk.KitchenClient
+	orders      pbo.OrderClient
+	extra       pbe.ExtraClient
+	sandbox     *storage.Store[models.SandboxOrder]
+	ttl         time.Duration
+	concurrency int
+
+	mu    sync.Mutex
+	cache map[string]*entry
+}
+
+type entry struct {
+	done    chan struct{}
+	value   any
+	err     error
+	expires time.Time
+}
+
+func NewAggregator(kitchens pbk.KitchenClient, orders pbo.OrderClient, extra pbe.ExtraClient,
+	sandbox *storage.Store[models.SandboxOrder], ttl time.Duration, concurrency int) *Aggregator {
+	return &Aggregator{
+		kitchens:    kitchens,
+		orders:      orders,
+		extra:       extra,
+		sandbox:     sandbox,
+		ttl:         ttl,
+		concurrency: max(1, concurrency),
+		cache:       make(map[string]*entry),
+	}
+}
+
+// OrdersPerHour counts orders by the hour of their delivery time.
+func (a *Aggregator) OrdersPerHour(ctx context.Context, start, end time.Time) ([]models.HourlyOrders, error) {
+	orders, err := a.allOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[time.Time]int32)
+	for _, o := range orders {
+		if inPeriod(o.Time, start, end) {
+			counts[o.Time.Truncate(time.Hour)]++
+		}
+	}
+
+	res := []models.HourlyOrders{}
+	for h := start; h.Before(end.AddDate(0, 0, 1)); h = h.Add(time.Hour) {
+		res = append(res, models.HourlyOrders{Hour: h.Format(time.RFC3339), Orders: counts[h]})
+	}
+	return res, nil
+}
+
+// RevenueByCity sums the statistics of kitchens per city of their address.
+func (a *Aggregator) RevenueByCity(ctx context.Context, start, end time.Time) ([]models.CityRevenue, error) {
+	stats, err := a.statistics(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	cities := make(map[string]*models.CityRevenue)
+	for _, s := range stats {
+		c, ok := cities[s.City]
+		if !ok {
+			c = &models.CityRevenue{City: s.City}
+			cities[s.City] = c
+		}
+		c.Kitchens++
+		c.Orders += s.Orders
+		c.Revenue += s.Revenue
+	}
+
+	res := make([]models.CityRevenue, 0, len(cities))
+	for _, c := range cities {
+		res = append(res, *c)
+	}
+	slices.SortFunc(res, func(a, b models.CityRevenue) int {
+		return compareDesc(a.Revenue, b.Revenue, a.City, b.City)
+	})
+	return res, nil
+}
+
+// TopKitchens ranks kitchens by revenue, or by order count when byOrders
+// is set.
+func (a *Aggregator) TopKitchens(ctx context.Context, start, end time.Time, limit int, byOrders bool) ([]models.KitchenRank, error) {
+	stats, err := a.statistics(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	res := slices.Clone(stats)
+	slices.SortFunc(res, func(a, b models.KitchenRank) int {
+		if byOrders && a.Orders != b.Orders {
+			return compareDesc(a.Orders, b.Orders, a.KitchenId, b.KitchenId)
+		}
+		return compareDesc(a.Revenue, b.Revenue, a.KitchenId, b.KitchenId)
+	})
+	if limit < len(res) {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// Churn compares the customers of the period with those of the period of
+// the same length right before it. Customers are told apart by user name,
+// the only customer field kitchen order lists carry.
+func (a *Aggregator) Churn(ctx context.Context, start, end time.Time) (models.Churn, error) {
+	orders, err := a.allOrders(ctx)
+	if err != nil {
+		return models.Churn{}, err
+	}
+
+	length := end.Sub(start) + 24*time.Hour
+	prevStart, prevEnd := start.Add(-length), start.AddDate(0, 0, -1)
+
+	previous, current := make(map[string]bool), make(map[string]bool)
+	for _, o := range orders {
+		switch {
+		case inPeriod(o.Time, prevStart, prevEnd):
+			previous[o.Customer] = true
+		case inPeriod(o.Time, start, end):
+			current[o.Customer] = true
+		}
+	}
+
+	res := models.Churn{
+		PreviousStartDate: prevStart.Format("2006-01-02"),
+		PreviousEndDate:   prevEnd.Format("2006-01-02"),
+		PreviousCustomers: int32(len(previous)),
+		CurrentCustomers:  int32(len(current)),
+	}
+	for c := range previous {
+		if current[c] {
+			res.RetainedCustomers++
+		}
+	}
+	res.ChurnedCustomers = res.PreviousCustomers - res.RetainedCustomers
+	res.NewCustomers = res.CurrentCustomers - res.RetainedCustomers
+	if res.PreviousCustomers > 0 {
+		res.ChurnRate = float32(res.ChurnedCustomers) / float32(res.PreviousCustomers)
+	}
+	return res, nil
+}
+
+func (a *Aggregator) statistics(ctx context.Context, start, end time.Time) ([]models.KitchenRank, error) {
+	key := "statistics:" + start.Format("2006-01-02") + ":" + end.Format("2006-01-02")
+	return cached(a, key, func() ([]models.KitchenRank, error) {
+		kitchens, err := a.allKitchens(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make([]models.KitchenRank, len(kitchens))
+		err = a.each(len(kitchens), func(i int) error {
+			k := kitchens[i]
+			in := &pbe.Period{
+				Id:        k.Id,
+				StartDate: start.Format("2006-01-02"),
+				EndDate:   end.Format("2006-01-02"),
+			}
+			s, err := a.extra.GetStatistics(ctx, in)
+			if err != nil {
+				return errors.Wrapf(err, "error getting statistics of kitchen %s", k.Id)
+			}
+			s = WithoutSandbox(a.sandbox, in, start.Location(), s)
+
+			res[i] = models.KitchenRank{
+				KitchenId: k.Id,
+				Name:      k.Name,
+				City:      k.City,
+				Orders:    s.TotalOrders,
+				Revenue:   s.TotalRevenue,
+				Rating:    s.AverageRating,
+			}
+			return nil
+		})
+		return res, err
+	})
+}
+
+func (a *Aggregator) allKitchens(ctx context.Context) ([]kitchen, error) {
+	return cached(a, "kitchens", func() ([]kitchen, error) {
+		var list []*pbk.KitchenDetails
+		for offset := int32(0); ; offset += pageSize {
+			page, err := a.kitchens.Fetch(ctx, &pbk.Pagination{Limit: pageSize, Offset: offset})
+			if err != nil {
+				return nil, errors.Wrap(err, "error fetching kitchens")
+			}
+			list = append(list, page.Kitchens...)
+			if len(page.Kitchens) < pageSize {
+				break
+			}
+		}
+
+		// Kitchen lists carry no address, so every kitchen is read for its city.
+		res := make([]kitchen, len(list))
+		err := a.each(len(list), func(i int) error {
+			info, err := a.kitchens.Get(ctx, &pbk.ID{Id: list[i].Id})
+			if err != nil {
+				return errors.Wrapf(err, "error getting kitchen %s", list[i].Id)
+			}
+			res[i] = kitchen{Id: info.Id, Name: info.Name, City: City(info.Address)}
+			return nil
+		})
+		return res, err
+	})
+}
+
+func (a *Aggregator) allOrders(ctx context.Context) ([]order, error) {
+	return cached(a, "orders", func() ([]order, error) {
+		kitchens, err := a.allKitchens(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		perKitchen := make([][]order, len(kitchens))
+		err = a.each(len(kitchens), func(i int) error {
+			var err error
+			perKitchen[i], err = a.kitchenOrders(ctx, kitchens[i].Id)
+			return err
+		})
+		return slices.Concat(perKitchen...), err
+	})
+}
+
+func (a *Aggregator) kitchenOrders(ctx context.Context, kitchenID string) ([]order, error) {
+	return cached(a, "orders:"+kitchenID, func() ([]order, error) {
+		var res []order
+		for offset := int32(0); ; offset += pageSize {
+			page, err := a.orders.FetchOrdersForKitchen(ctx, &pbo.Filter{
+				KitchenId:  kitchenID,
+				Pagination: &pbo.Pagination{Limit: pageSize, Offset: offset},
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "error fetching orders of kitchen %s", kitchenID)
+			}
+
+			for _, o := range page.Orders {
+				if _, test := a.sandbox.Get(o.Id); test {
+					continue
+				}
+				t, ok := parseTime(o.DeliveryTime)
+				if !ok {
+					continue
+				}
+				res = append(res, order{
+					Id:        o.Id,
+					KitchenId: kitchenID,
+					Customer:  o.UserName,
+					Status:    o.Status,
+					Amount:    o.TotalAmount,
+					Time:      t,
+				})
+			}
+			if len(page.Orders) < pageSize {
+				return res, nil
+			}
+		}
+	})
+}
+
+// each runs fn for indexes 0..n-1 with bounded concurrency and returns
+// the first error.
+func (a *Aggregator) each(n int, fn func(i int) error) error {
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, a.concurrency)
+
+	for i := range n {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			if err := fn(i); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}()
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// cached returns the value stored under key, computing it with fn when
+// missing or expired. Concurrent callers share a single computation;
+// failures are not cached.
+func cached[T any](a *Aggregator, key string, fn func() (T, error)) (T, error) {
+	a.mu.Lock()
+	e, ok := a.cache[key]
+	if ok {
+		select {
+		case <-e.done:
+			if e.err != nil || time.Now().After(e.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		e = &entry{done: make(chan struct{})}
+		a.cache[key] = e
+		a.mu.Unlock()
+
+		e.value, e.err = fn()
+		e.expires = time.Now().Add(a.ttl)
+		close(e.done)
+	} else {
+		a.mu.Unlock()
+		<-e.done
+	}
+
+	if e.err != nil {
+		var zero T
+		return zero, e.err
+	}
+	return e.value.(T), nil
+}
+
+// City guesses the city of an address such as "12 Navoi St, Tashkent,
+// Uzbekistan": the part before the country when there are three or more
+// comma separated parts, otherwise the last part.
+func City(address string) string {
+	var parts []string
+	for _, p := range strings.Split(address, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	switch {
+	case len(parts) == 0:
+		return "unknown"
+	case len(parts) >= 3:
+		return parts[len(parts)-2]
+	}
+	return parts[len(parts)-1]
+}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// inPeriod reports whether t falls on one of the days from start to end.
+func inPeriod(t, start, end time.Time) bool {
+	return !t.Before(start) && t.Before(end.AddDate(0, 0, 1))
+}
+
+func compareDesc[N int32 | float32](a, b N, tieA, tieB string) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return strings.Compare(tieA, tieB)
+}
@@ -0,0 +1,132 @@
+package analytics
+
+import (
+	"api-gateway/models"
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Fees are deducted from what customers paid to get kitchen earnings.
+type Fees struct {
+	// Commission returns the platform's share of an order's amount.
+	Commission func(orderID string, amount float32) float32
+	// Refunds holds approved refund amounts by order ID.
+	Refunds map[string]float32
+}
+
+// canceled tells the order statuses that earn the kitchen nothing.
+func canceled(status string) bool {
+	s := strings.ToLower(status)
+	return s == "cancelled" || s == "canceled" || s == "rejected"
+}
+
+// Earnings breaks down the earnings of a kitchen for the days from start
+// to end into group periods and orders. Days begin at midnight in start's
+// location. Canceled orders are left out.
+func (a *Aggregator) Earnings(ctx context.Context, kitchenID string, start, end time.Time,
+	group string, fees Fees) (models.Earnings, error) {
+	orders, err := a.kitchenOrders(ctx, kitchenID)
+	if err != nil {
+		return models.Earnings{}, err
+	}
+
+	res := models.Earnings{
+		KitchenId: kitchenID,
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.Format("2006-01-02"),
+		Group:     group,
+		Periods:   []models.EarningsSummary{},
+		Orders:    []models.OrderEarnings{},
+	}
+
+	periods := make(map[string]*models.EarningsSummary)
+	for p := periodStart(start, group); !p.After(end); p = nextPeriod(p, group) {
+		key := p.Format("2006-01-02")
+		res.Periods = append(res.Periods, models.EarningsSummary{Period: key})
+		periods[key] = &res.Periods[len(res.Periods)-1]
+	}
+
+	for _, o := range orders {
+		if !inPeriod(o.Time, start, end) || canceled(o.Status) {
+			continue
+		}
+
+		e := orderEarnings(o, fees)
+		res.Orders = append(res.Orders, e)
+		add(&res.Totals, e)
+		add(periods[periodStart(o.Time.In(start.Location()), group).Format("2006-01-02")], e)
+	}
+
+	slices.SortFunc(res.Orders, func(a, b models.OrderEarnings) int {
+		return strings.Compare(b.DeliveryTime, a.DeliveryTime)
+	})
+	res.Total = int32(len(res.Orders))
+	return res, nil
+}
+
+// NetEarnings sums the net earnings of all orders of a kitchen.
+func (a *Aggregator) NetEarnings(ctx context.Context, kitchenID string, fees Fees) (float32, error) {
+	orders, err := a.kitchenOrders(ctx, kitchenID)
+	if err != nil {
+		return 0, err
+	}
+
+	var total models.EarningsSummary
+	for _, o := range orders {
+		if !canceled(o.Status) {
+			add(&total, orderEarnings(o, fees))
+		}
+	}
+	return total.Net, nil
+}
+
+func orderEarnings(o order, fees Fees) models.OrderEarnings {
+	e := models.OrderEarnings{
+		OrderId:      o.Id,
+		Status:       o.Status,
+		DeliveryTime: o.Time.Format(time.RFC3339),
+		Gross:        o.Amount,
+		Commission:   fees.Commission(o.Id, o.Amount),
+		Refunds:      fees.Refunds[o.Id],
+	}
+	e.Net = round(e.Gross - e.Commission - e.Refunds)
+	return e
+}
+
+func add(s *models.EarningsSummary, e models.OrderEarnings) {
+	s.Orders++
+	s.Gross = round(s.Gross + e.Gross)
+	s.Commission = round(s.Commission + e.Commission)
+	s.Refunds = round(s.Refunds + e.Refunds)
+	s.Net = round(s.Net + e.Net)
+}
+
+// periodStart returns the first day of the day, Monday-based week or
+// month that t falls in, in t's location.
+func periodStart(t time.Time, group string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch group {
+	case models.GroupWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case models.GroupMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+func nextPeriod(t time.Time, group string) time.Time {
+	switch group {
+	case models.GroupWeek:
+		return t.AddDate(0, 0, 7)
+	case models.GroupMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+func round(v float32) float32 {
+	return float32(math.Round(float64(v)*100) / 100)
+}
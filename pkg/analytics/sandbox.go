@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	pbe "api-gateway/genproto/extra"
+	"api-gateway/models"
+	"api-gateway/storage"
+	"cmp"
+	"slices"
+	"time"
+)
+
+// WithoutSandbox takes the sandbox orders of the period's kitchen that
+// are delivered on its days, in loc, out of stats, which the statistics
+// service counts them in. The average rating is left as it is, since
+// sandbox orders are not reviewed.
+func WithoutSandbox(sandbox *storage.Store[models.SandboxOrder], in *pbe.Period, loc *time.Location, stats *pbe.Statistics) *pbe.Statistics {
+	if stats == nil {
+		return stats
+	}
+	start, err := time.ParseInLocation("2006-01-02", in.StartDate, loc)
+	if err != nil {
+		return stats
+	}
+	end, err := time.ParseInLocation("2006-01-02", in.EndDate, loc)
+	if err != nil {
+		return stats
+	}
+
+	dishes := map[string]*pbe.Dish{}
+	for _, d := range stats.TopDishes {
+		dishes[d.Id] = d
+	}
+
+	removed := false
+	for _, o := range sandbox.List() {
+		t, ok := parseTime(o.DeliveryTime)
+		if o.KitchenId != in.Id || !ok || !inPeriod(t.In(loc), start, end) {
+			continue
+		}
+		removed = true
+		stats.TotalOrders = max(stats.TotalOrders-1, 0)
+		stats.TotalRevenue = max(stats.TotalRevenue-o.Amount, 0)
+
+		// A dish counts once per order, however many lines it is on.
+		counted := map[string]bool{}
+		for _, item := range o.Items {
+			d, ok := dishes[item.DishId]
+			if !ok {
+				continue
+			}
+			d.Revenue = max(d.Revenue-item.Total, 0)
+			if !counted[item.DishId] {
+				counted[item.DishId] = true
+				d.OrdersCount = max(d.OrdersCount-1, 0)
+			}
+		}
+	}
+	if !removed {
+		return stats
+	}
+
+	stats.TopDishes = slices.DeleteFunc(stats.TopDishes, func(d *pbe.Dish) bool {
+		return d.OrdersCount == 0
+	})
+	slices.SortStableFunc(stats.TopDishes, func(a, b *pbe.Dish) int {
+		return cmp.Compare(b.OrdersCount, a.OrdersCount)
+	})
+	return stats
+}
@@ -0,0 +1,111 @@
+package broadcast
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Audience returns the IDs of the users the segment selects, sorted.
+// The user service can't list users, so without explicit user IDs the
+// candidates are the users the gateway keeps anything for.
+func Audience(s *storage.Storage, seg models.BroadcastSegment) []string {
+	candidates := seg.UserIds
+	if len(candidates) == 0 {
+		candidates = knownUsers(s)
+	}
+
+	since, _ := ParseTime(seg.ActiveSince)
+	var res []string
+	for _, id := range candidates {
+		if len(seg.KitchenIds) > 0 && !customerOf(s, id, seg.KitchenIds) {
+			continue
+		}
+		if !since.IsZero() && !activeSince(s, id, since) {
+			continue
+		}
+		if len(seg.Cuisines) > 0 && !searched(s, id, seg.Cuisines) {
+			continue
+		}
+		res = append(res, id)
+	}
+	slices.Sort(res)
+	return slices.Compact(res)
+}
+
+// ParseTime parses a time in RFC 3339 or a date, which is taken as its
+// midnight UTC. An empty string is the zero time.
+func ParseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func knownUsers(s *storage.Storage) []string {
+	ids := s.Activity.Keys()
+	ids = append(ids, s.SearchHistory.Keys()...)
+	ids = append(ids, s.NotifySettings.Keys()...)
+	ids = append(ids, s.BlockedKitchens.Keys()...)
+	for _, ss := range s.SavedSearches.List() {
+		ids = append(ids, ss.UserId)
+	}
+	for _, p := range s.MealPlans.List() {
+		ids = append(ids, p.UserId)
+	}
+	for _, a := range s.DishAlerts.List() {
+		ids = append(ids, a.UserId)
+	}
+	return ids
+}
+
+func customerOf(s *storage.Storage, userID string, kitchenIDs []string) bool {
+	items, _ := s.Activity.Get(userID)
+	for _, it := range items {
+		if slices.Contains(kitchenIDs, it.KitchenId()) {
+			return true
+		}
+	}
+	for _, p := range s.MealPlans.List() {
+		if p.UserId == userID && slices.Contains(kitchenIDs, p.KitchenId) {
+			return true
+		}
+	}
+	return false
+}
+
+func activeSince(s *storage.Storage, userID string, since time.Time) bool {
+	items, _ := s.Activity.Get(userID)
+	for _, it := range items {
+		if t, err := time.Parse(time.RFC3339, it.OccurredAt); err == nil && !t.Before(since) {
+			return true
+		}
+	}
+	return false
+}
+
+func searched(s *storage.Storage, userID string, cuisines []string) bool {
+	matches := func(cuisine string) bool {
+		return cuisine != "" && slices.ContainsFunc(cuisines, func(c string) bool {
+			return strings.EqualFold(c, cuisine)
+		})
+	}
+
+	history, _ := s.SearchHistory.Get(userID)
+	for _, e := range history {
+		if matches(e.CuisineType) {
+			return true
+		}
+	}
+	for _, ss := range s.SavedSearches.List() {
+		if ss.UserId == userID && matches(ss.CuisineType) {
+			return true
+		}
+	}
+	return false
+}
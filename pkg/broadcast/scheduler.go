@@ -0,0 +1,108 @@
+package broadcast
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const sendTimeout = 10 * time.Second
+
+// Sender sends the broadcast to one user and counts the outcome.
+type Sender func(ctx context.Context, b models.Broadcast, userID string) models.BroadcastStats
+
+// Scheduler sends broadcasts once their time comes. Users are reached at
+// a limited rate shared by all broadcasts, and the stats of a broadcast
+// are updated as it goes.
+type Scheduler struct {
+	storage  *storage.Storage
+	send     Sender
+	throttle *time.Ticker
+	logger   *slog.Logger
+}
+
+// NewScheduler starts checking for due broadcasts every interval. rate
+// is how many users are reached a second.
+func NewScheduler(s *storage.Storage, send Sender, rate int, interval time.Duration,
+	logger *slog.Logger) *Scheduler {
+	sc := &Scheduler{
+		storage:  s,
+		send:     send,
+		throttle: time.NewTicker(time.Second / time.Duration(max(rate, 1))),
+		logger:   logger,
+	}
+
+	go func() {
+		for now := range time.Tick(interval) {
+			sc.RunDue(now)
+		}
+	}()
+
+	return sc
+}
+
+// RunDue starts sending every scheduled broadcast whose time has come.
+func (s *Scheduler) RunDue(now time.Time) {
+	for _, b := range s.storage.Broadcasts.List() {
+		at, err := time.Parse(time.RFC3339, b.SendAt)
+		if b.Status != models.BroadcastScheduled || err != nil || at.After(now) {
+			continue
+		}
+
+		// Claim the broadcast first so it is not sent twice. It may have
+		// been edited or canceled since it was listed.
+		claimed := false
+		b = s.storage.Broadcasts.Update(b.Id, func(cur models.Broadcast, _ bool) models.Broadcast {
+			if cur.Status != models.BroadcastScheduled || cur.SendAt != b.SendAt {
+				return cur
+			}
+			claimed = true
+			cur.Status = models.BroadcastSending
+			cur.StartedAt = now.Format(time.RFC3339)
+			cur.UpdatedAt = cur.StartedAt
+			return cur
+		})
+		if claimed {
+			go s.run(b)
+		}
+	}
+}
+
+func (s *Scheduler) run(b models.Broadcast) {
+	users := Audience(s.storage, b.Segment)
+	s.record(b.Id, models.BroadcastStats{Recipients: len(users)})
+	s.logger.Info(fmt.Sprintf("sending broadcast %s to %d users", b.Id, len(users)))
+
+	for _, userID := range users {
+		<-s.throttle.C
+		// Canceling stops a broadcast being sent.
+		if cur, _ := s.storage.Broadcasts.Get(b.Id); cur.Status != models.BroadcastSending {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		stats := s.send(ctx, b, userID)
+		cancel()
+		stats.Processed = 1
+		s.record(b.Id, stats)
+	}
+
+	s.storage.Broadcasts.Update(b.Id, func(cur models.Broadcast, _ bool) models.Broadcast {
+		if cur.Status == models.BroadcastSending {
+			cur.Status = models.BroadcastSent
+			cur.FinishedAt = time.Now().Format(time.RFC3339)
+			cur.UpdatedAt = cur.FinishedAt
+		}
+		return cur
+	})
+}
+
+func (s *Scheduler) record(id string, stats models.BroadcastStats) {
+	s.storage.Broadcasts.Update(id, func(cur models.Broadcast, _ bool) models.Broadcast {
+		cur.Stats = cur.Stats.Add(stats)
+		return cur
+	})
+}
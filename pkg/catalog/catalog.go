@@ -0,0 +1,107 @@
+// Package catalog tracks the changes of kitchens and dishes, so partner
+// aggregators can sync what changed since they last did instead of
+// crawling the whole catalog.
+package catalog
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/events"
+	"api-gateway/storage"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// stamp is how change times start their keys: fixed width, so keys sort
+// by time as strings.
+const stamp = "2006-01-02T15:04:05.000000000Z"
+
+// Tracker records changes as events and keeps them for the retention.
+type Tracker struct {
+	store     *storage.Store[models.CatalogChange]
+	events    *events.Emitter
+	retention int
+	// started is when tracking started; what changed before is unknown.
+	started time.Time
+
+	mu     sync.Mutex
+	pruned string
+}
+
+// NewTracker keeps retention days of changes in store and emits them to
+// events.
+func NewTracker(store *storage.Store[models.CatalogChange], events *events.Emitter, retention int) *Tracker {
+	return &Tracker{store: store, events: events, retention: retention, started: time.Now().UTC().Truncate(time.Second)}
+}
+
+// Record tracks a change of the entity id of kitchen kitchenID. data is
+// the entity after the change, nil for deletions.
+func (t *Tracker) Record(eventType, entity, id, kitchenID string, data json.RawMessage, now time.Time) {
+	now = now.UTC()
+	t.prune(now)
+
+	ch := models.CatalogChange{
+		Id:        uuid.NewString(),
+		Type:      eventType,
+		Entity:    entity,
+		EntityId:  id,
+		KitchenId: kitchenID,
+		ChangedAt: now.Format(time.RFC3339Nano),
+		Data:      data,
+	}
+	t.store.Set(now.Format(stamp)+"|"+ch.Id, ch)
+	t.events.Emit(eventType, id, ch)
+}
+
+// Since returns the changes made at or after since, oldest first.
+func (t *Tracker) Since(since time.Time) []models.CatalogChange {
+	from := since.UTC().Format(stamp)
+	keys := t.store.Keys()
+	slices.Sort(keys)
+	i, _ := slices.BinarySearch(keys, from)
+
+	res := make([]models.CatalogChange, 0, len(keys)-i)
+	for _, key := range keys[i:] {
+		if ch, ok := t.store.Get(key); ok {
+			res = append(res, ch)
+		}
+	}
+	return res
+}
+
+// Oldest is the earliest time changes are known from: the start of the
+// retention, or when tracking started if that was later.
+func (t *Tracker) Oldest(now time.Time) time.Time {
+	y, m, d := now.UTC().AddDate(0, 0, 1-t.retention).Date()
+	return later(time.Date(y, m, d, 0, 0, 0, 0, time.UTC), t.started)
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// prune drops the changes past the retention, once a day.
+func (t *Tracker) prune(now time.Time) {
+	day := now.Format(time.DateOnly)
+	t.mu.Lock()
+	if t.pruned == day {
+		t.mu.Unlock()
+		return
+	}
+	t.pruned = day
+	t.mu.Unlock()
+
+	y, m, d := now.AddDate(0, 0, 1-t.retention).Date()
+	oldest := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format(stamp)
+	for _, key := range t.store.Keys() {
+		if key < oldest {
+			t.store.Delete(key)
+		}
+	}
+}
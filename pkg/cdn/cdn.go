@@ -0,0 +1,107 @@
+package cdn
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const timeout = 10 * time.Second
+
+var ErrUnknownProvider = errors.New("unknown CDN provider")
+
+// Surrogate keys of the responses the edge may cache.
+const KeyDishes = "dishes"
+
+func KitchenKey(id string) string {
+	return "kitchen:" + id
+}
+
+func DishKey(id string) string {
+	return "dish:" + id
+}
+
+// Provider tags responses and purges them with one CDN's API.
+type Provider interface {
+	Name() string
+	// Tag sets the headers that let the edge cache a response for ttl
+	// under the keys.
+	Tag(h http.Header, keys []string, ttl time.Duration)
+	// Purge drops the cached responses with any of the keys.
+	Purge(ctx context.Context, keys []string) error
+	// MaxKeys is the most keys one purge request may carry.
+	MaxKeys() int
+}
+
+// Edge lets the CDN in front of the gateway cache responses that are the
+// same for every caller, and purges them when what they show changes.
+// Browsers are told not to cache them, so a purge takes effect at once.
+// Without a provider nothing is tagged or purged.
+type Edge struct {
+	provider Provider
+	ttl      time.Duration
+	logger   *slog.Logger
+}
+
+func NewEdge(cfg *config.Config, logger *slog.Logger) *Edge {
+	e := &Edge{ttl: cfg.CDN_MAX_AGE, logger: logger}
+
+	client := &http.Client{Timeout: timeout}
+	switch cfg.CDN_PROVIDER {
+	case "":
+	case models.CDNFastly:
+		e.provider = NewFastly(cfg.CDN_API_URL, cfg.CDN_SERVICE_ID, cfg.CDN_API_TOKEN, client)
+	case models.CDNCloudflare:
+		e.provider = NewCloudflare(cfg.CDN_API_URL, cfg.CDN_SERVICE_ID, cfg.CDN_API_TOKEN, client)
+	default:
+		logger.Error(errors.Wrapf(ErrUnknownProvider, "%q", cfg.CDN_PROVIDER).Error())
+	}
+	return e
+}
+
+func (e *Edge) Enabled() bool {
+	return e.provider != nil
+}
+
+// Tag marks a response as cacheable at the edge under the keys.
+func (e *Edge) Tag(h http.Header, keys ...string) {
+	if e.provider == nil || len(keys) == 0 {
+		return
+	}
+	h.Set("Cache-Control", "public, max-age=0")
+	e.provider.Tag(h, keys, e.ttl)
+}
+
+// Purge drops the responses with any of the keys from the edge in the
+// background. Failures are logged; the responses then expire with their
+// TTL.
+func (e *Edge) Purge(keys ...string) {
+	if e.provider == nil || len(keys) == 0 {
+		return
+	}
+	keys = slices.Clone(keys)
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		for len(keys) > 0 {
+			n := min(len(keys), e.provider.MaxKeys())
+			batch := keys[:n]
+			keys = keys[n:]
+			if err := e.provider.Purge(ctx, batch); err != nil {
+				e.logger.Error(errors.Wrapf(err, "error purging %s from %s",
+					strings.Join(batch, " "), e.provider.Name()).Error())
+			}
+		}
+	}()
+}
@@ -0,0 +1,87 @@
+package cdn
+
+import (
+	"api-gateway/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Cloudflare tags responses with the Cache-Tag header, which Cloudflare
+// strips before responding, and purges them by tag.
+type Cloudflare struct {
+	apiURL string
+	zoneID string
+	token  string
+	client *http.Client
+}
+
+func NewCloudflare(apiURL, zoneID, token string, client *http.Client) *Cloudflare {
+	if apiURL == "" {
+		apiURL = "https://api.cloudflare.com"
+	}
+	return &Cloudflare{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		zoneID: zoneID,
+		token:  token,
+		client: client,
+	}
+}
+
+func (cf *Cloudflare) Name() string {
+	return models.CDNCloudflare
+}
+
+func (cf *Cloudflare) MaxKeys() int {
+	return 30
+}
+
+func (cf *Cloudflare) Tag(h http.Header, keys []string, ttl time.Duration) {
+	h.Set("Cache-Tag", strings.Join(keys, ","))
+	h.Set("CDN-Cache-Control", fmt.Sprintf("public, max-age=%d", int(ttl.Seconds())))
+}
+
+func (cf *Cloudflare) Purge(ctx context.Context, keys []string) error {
+	body, err := json.Marshal(map[string][]string{"tags": keys})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		cf.apiURL+"/client/v4/zones/"+cf.zoneID+"/purge_cache", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+cf.token)
+
+	resp, err := cf.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return errors.Wrapf(err, "error decoding Cloudflare response (%s)", resp.Status)
+	}
+	if !out.Success {
+		msgs := make([]string, len(out.Errors))
+		for i, e := range out.Errors {
+			msgs[i] = e.Message
+		}
+		return errors.Errorf("Cloudflare returned %s: %s", resp.Status, strings.Join(msgs, "; "))
+	}
+	return nil
+}
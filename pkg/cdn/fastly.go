@@ -0,0 +1,79 @@
+package cdn
+
+import (
+	"api-gateway/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Fastly tags responses with the Surrogate-Key header and purges them
+// by key, which marks them stale at every POP.
+type Fastly struct {
+	apiURL    string
+	serviceID string
+	token     string
+	client    *http.Client
+}
+
+func NewFastly(apiURL, serviceID, token string, client *http.Client) *Fastly {
+	if apiURL == "" {
+		apiURL = "https://api.fastly.com"
+	}
+	return &Fastly{
+		apiURL:    strings.TrimSuffix(apiURL, "/"),
+		serviceID: serviceID,
+		token:     token,
+		client:    client,
+	}
+}
+
+func (f *Fastly) Name() string {
+	return models.CDNFastly
+}
+
+func (f *Fastly) MaxKeys() int {
+	return 256
+}
+
+func (f *Fastly) Tag(h http.Header, keys []string, ttl time.Duration) {
+	h.Set("Surrogate-Key", strings.Join(keys, " "))
+	h.Set("Surrogate-Control", fmt.Sprintf("max-age=%d", int(ttl.Seconds())))
+}
+
+func (f *Fastly) Purge(ctx context.Context, keys []string) error {
+	body, err := json.Marshal(map[string][]string{"surrogate_keys": keys})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		f.apiURL+"/service/"+f.serviceID+"/purge", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Fastly-Key", f.token)
+	// Soft purges serve stale content while the origin is asked again,
+	// sparing it a stampede.
+	req.Header.Set("Fastly-Soft-Purge", "1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.Errorf("Fastly returned %s: %s", resp.Status, msg)
+	}
+	return nil
+}
@@ -9,16 +9,35 @@ import (
 	pbp "api-gateway/genproto/payment"
 	pbr "api-gateway/genproto/review"
 	pbu "api-gateway/genproto/user"
+	"api-gateway/pkg/retention"
 	"log"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
-func NewUserClient(cfg *config.Config) pbu.UserClient {
-	conn, err := grpc.NewClient(cfg.AUTH_SERVICE_PORT,
+// dialOptions are shared by the connections to every service, followed
+// by the caller's opts.
+func dialOptions(cfg *config.Config, opts []grpc.DialOption) []grpc.DialOption {
+	return append([]grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(cfg.GRPC_MAX_RECV_MSG_SIZE),
+			grpc.MaxCallSendMsgSize(cfg.GRPC_MAX_SEND_MSG_SIZE),
+		),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.GRPC_KEEPALIVE_TIME,
+			Timeout:             cfg.GRPC_KEEPALIVE_TIMEOUT,
+			PermitWithoutStream: cfg.GRPC_KEEPALIVE_WITHOUT_CALLS,
+		}),
+	}, opts...)
+}
+
+func NewUserClient(cfg *config.Config, opts ...grpc.DialOption) pbu.UserClient {
+	conn, err := grpc.NewClient(cfg.AUTH_SERVICE_PORT,
+		dialOptions(cfg, opts)...,
 	)
 
 	if err != nil {
@@ -29,9 +48,9 @@ func NewUserClient(cfg *config.Config) pbu.UserClient {
 	return pbu.NewUserClient(conn)
 }
 
-func NewKitchenClient(cfg *config.Config) pbk.KitchenClient {
+func NewKitchenClient(cfg *config.Config, opts ...grpc.DialOption) pbk.KitchenClient {
 	conn, err := grpc.NewClient(cfg.AUTH_SERVICE_PORT,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		dialOptions(cfg, opts)...,
 	)
 
 	if err != nil {
@@ -42,9 +61,9 @@ func NewKitchenClient(cfg *config.Config) pbk.KitchenClient {
 	return pbk.NewKitchenClient(conn)
 }
 
-func NewDishClient(cfg *config.Config) pbd.DishClient {
+func NewDishClient(cfg *config.Config, opts ...grpc.DialOption) pbd.DishClient {
 	conn, err := grpc.NewClient(cfg.ORDER_SERVICE_PORT,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		dialOptions(cfg, opts)...,
 	)
 
 	if err != nil {
@@ -55,9 +74,9 @@ func NewDishClient(cfg *config.Config) pbd.DishClient {
 	return pbd.NewDishClient(conn)
 }
 
-func NewOrderClient(cfg *config.Config) pbo.OrderClient {
+func NewOrderClient(cfg *config.Config, opts ...grpc.DialOption) pbo.OrderClient {
 	conn, err := grpc.NewClient(cfg.ORDER_SERVICE_PORT,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		dialOptions(cfg, opts)...,
 	)
 
 	if err != nil {
@@ -68,9 +87,9 @@ func NewOrderClient(cfg *config.Config) pbo.OrderClient {
 	return pbo.NewOrderClient(conn)
 }
 
-func NewReviewClient(cfg *config.Config) pbr.ReviewClient {
+func NewReviewClient(cfg *config.Config, opts ...grpc.DialOption) pbr.ReviewClient {
 	conn, err := grpc.NewClient(cfg.ORDER_SERVICE_PORT,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		dialOptions(cfg, opts)...,
 	)
 
 	if err != nil {
@@ -81,9 +100,9 @@ func NewReviewClient(cfg *config.Config) pbr.ReviewClient {
 	return pbr.NewReviewClient(conn)
 }
 
-func NewPaymentClient(cfg *config.Config) pbp.PaymentClient {
+func NewPaymentClient(cfg *config.Config, opts ...grpc.DialOption) pbp.PaymentClient {
 	conn, err := grpc.NewClient(cfg.ORDER_SERVICE_PORT,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		dialOptions(cfg, opts)...,
 	)
 
 	if err != nil {
@@ -94,9 +113,9 @@ func NewPaymentClient(cfg *config.Config) pbp.PaymentClient {
 	return pbp.NewPaymentClient(conn)
 }
 
-func NewExtraClient(cfg *config.Config) pbe.ExtraClient {
+func NewExtraClient(cfg *config.Config, opts ...grpc.DialOption) pbe.ExtraClient {
 	conn, err := grpc.NewClient(cfg.ORDER_SERVICE_PORT,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		dialOptions(cfg, opts)...,
 	)
 
 	if err != nil {
@@ -106,3 +125,18 @@ func NewExtraClient(cfg *config.Config) pbe.ExtraClient {
 
 	return pbe.NewExtraClient(conn)
 }
+
+// NewRetentionClient calls the order service, which serves the payments
+// too, to anonymize records past the retention window.
+func NewRetentionClient(cfg *config.Config, opts ...grpc.DialOption) *retention.Client {
+	conn, err := grpc.NewClient(cfg.ORDER_SERVICE_PORT,
+		dialOptions(cfg, opts)...,
+	)
+
+	if err != nil {
+		log.Println(errors.Wrap(err, "failed to connect to the address"))
+		return nil
+	}
+
+	return retention.NewClient(conn)
+}
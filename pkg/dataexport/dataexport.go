@@ -0,0 +1,113 @@
+// Package dataexport seals the data packages compiled for legal
+// data-access requests. A package is a zip archive of JSON files,
+// encrypted with AES-256-GCM under a key of its own that is handed out
+// once and never stored: the file on disk is the 12-byte nonce followed
+// by the ciphertext.
+package dataexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// File is a file of a package, written as indented JSON.
+type File struct {
+	Name string
+	Data any
+}
+
+// Sealed describes a sealed package. Key is the base64 key it was
+// encrypted with.
+type Sealed struct {
+	Key    string
+	Size   int64
+	SHA256 string
+}
+
+// Store keeps the sealed packages in a directory.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Path returns where the package with the ID is kept.
+func (s *Store) Path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+".zip.enc")
+}
+
+// Seal zips the files, encrypts the archive under a new key and keeps it
+// under the ID.
+func (s *Store) Seal(id string, files []File) (Sealed, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.Name)
+		if err != nil {
+			return Sealed{}, errors.Wrapf(err, "error adding %s", f.Name)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.Data); err != nil {
+			return Sealed{}, errors.Wrapf(err, "error writing %s", f.Name)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return Sealed{}, errors.Wrap(err, "error closing archive")
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return Sealed{}, errors.Wrap(err, "error generating key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return Sealed{}, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return Sealed{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return Sealed{}, errors.Wrap(err, "error generating nonce")
+	}
+	// The ID is authenticated with the archive, so a package can't be
+	// passed off as another.
+	sealed := gcm.Seal(nonce, nonce, buf.Bytes(), []byte(id))
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return Sealed{}, errors.Wrap(err, "error creating directory")
+	}
+	if err := os.WriteFile(s.Path(id), sealed, 0o600); err != nil {
+		return Sealed{}, errors.Wrap(err, "error writing package")
+	}
+
+	sum := sha256.Sum256(sealed)
+	return Sealed{
+		Key:    base64.StdEncoding.EncodeToString(key),
+		Size:   int64(len(sealed)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// Remove deletes the package with the ID, if it is kept.
+func (s *Store) Remove(id string) error {
+	if err := os.Remove(s.Path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package email
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	timeout   = 30 * time.Second
+	queueSize = 1000
+)
+
+var (
+	ErrDisabled = errors.New("email delivery is not configured")
+	ErrQueue    = errors.New("email queue is full")
+)
+
+// Driver sends messages through one email service.
+type Driver interface {
+	Name() string
+	Send(ctx context.Context, m Message) error
+}
+
+// Message is a rendered email. Text is the plain text alternative of
+// HTML.
+type Message struct {
+	From        string
+	To          string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type job struct {
+	id  string
+	msg Message
+}
+
+// Mailer renders templated emails and sends them in the background.
+// Failed sends are retried with exponential backoff; every email is
+// recorded with its status.
+type Mailer struct {
+	driver   Driver
+	from     string
+	store    *storage.Store[models.Email]
+	queue    chan job
+	attempts int
+	backoff  time.Duration
+	logger   *slog.Logger
+}
+
+// NewMailer starts the workers sending the queued emails with the driver
+// EMAIL_DRIVER names. Sending is disabled when the driver isn't
+// configured.
+func NewMailer(cfg *config.Config, store *storage.Store[models.Email], logger *slog.Logger) *Mailer {
+	m := &Mailer{
+		from:     cfg.EMAIL_FROM,
+		store:    store,
+		queue:    make(chan job, queueSize),
+		attempts: max(cfg.EMAIL_MAX_ATTEMPTS, 1),
+		backoff:  cfg.EMAIL_RETRY_BACKOFF,
+		logger:   logger,
+	}
+
+	switch cfg.EMAIL_DRIVER {
+	case models.EmailSMTP:
+		if cfg.SMTP_ADDR != "" {
+			m.driver = NewSMTP(cfg.SMTP_ADDR, cfg.SMTP_USER, cfg.SMTP_PASSWORD)
+		}
+	case models.EmailSendGrid:
+		if cfg.SENDGRID_API_KEY != "" {
+			m.driver = NewSendGrid(cfg.SENDGRID_API_URL, cfg.SENDGRID_API_KEY)
+		}
+	case models.EmailSES:
+		if cfg.SES_ACCESS_KEY_ID != "" {
+			m.driver = NewSES(cfg.SES_API_URL, cfg.SES_REGION, cfg.SES_ACCESS_KEY_ID, cfg.SES_SECRET_ACCESS_KEY)
+		}
+	default:
+		logger.Error(errors.Errorf("unknown email driver %q", cfg.EMAIL_DRIVER).Error())
+	}
+
+	for range max(cfg.EMAIL_WORKERS, 1) {
+		go m.work()
+	}
+	return m
+}
+
+func (m *Mailer) Enabled() bool {
+	return m.driver != nil
+}
+
+// Queue renders the template in lang and queues the email for sending.
+func (m *Mailer) Queue(to, lang, template string, data any, attachments ...Attachment) (models.Email, error) {
+	if !m.Enabled() {
+		return models.Email{}, ErrDisabled
+	}
+
+	lang = Lang(lang)
+	subject, html, err := Render(template, lang, data)
+	if err != nil {
+		return models.Email{}, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	e := models.Email{
+		Id:        uuid.NewString(),
+		To:        to,
+		Template:  template,
+		Lang:      lang,
+		Subject:   subject,
+		Driver:    m.driver.Name(),
+		Status:    models.EmailQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	m.store.Set(e.Id, e)
+
+	msg := Message{
+		From:        m.from,
+		To:          to,
+		Subject:     subject,
+		HTML:        html,
+		Text:        PlainText(html),
+		Attachments: attachments,
+	}
+	if !m.enqueue(job{id: e.Id, msg: msg}) {
+		return m.finish(e.Id, ErrQueue), ErrQueue
+	}
+	return e, nil
+}
+
+func (m *Mailer) enqueue(j job) bool {
+	select {
+	case m.queue <- j:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *Mailer) work() {
+	for j := range m.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := m.driver.Send(ctx, j.msg)
+		cancel()
+
+		e := m.store.Update(j.id, func(e models.Email, _ bool) models.Email {
+			e.Attempts++
+			e.UpdatedAt = time.Now().Format(time.RFC3339)
+			return e
+		})
+		if err == nil {
+			m.finish(j.id, nil)
+			continue
+		}
+
+		m.logger.Error(errors.Wrapf(err, "email %s attempt %d failed", j.id, e.Attempts).Error())
+		if e.Attempts >= m.attempts {
+			m.finish(j.id, err)
+			continue
+		}
+		m.store.Update(j.id, func(e models.Email, _ bool) models.Email {
+			e.Error = err.Error()
+			return e
+		})
+		time.AfterFunc(m.backoff<<(e.Attempts-1), func() {
+			if !m.enqueue(j) {
+				m.finish(j.id, ErrQueue)
+			}
+		})
+	}
+}
+
+// finish records the final status of the email: sent when err is nil,
+// failed otherwise.
+func (m *Mailer) finish(id string, err error) models.Email {
+	return m.store.Update(id, func(e models.Email, _ bool) models.Email {
+		now := time.Now().Format(time.RFC3339)
+		e.Status, e.Error, e.UpdatedAt = models.EmailSent, "", now
+		if err != nil {
+			e.Status, e.Error = models.EmailFailed, err.Error()
+		} else {
+			e.SentAt = now
+		}
+		return e
+	})
+}
@@ -0,0 +1,96 @@
+package email
+
+// labels are the email texts by language. Texts with verbs are used with
+// printf.
+var labels = map[string]map[string]string{
+	"en": {
+		"footer":             "You're receiving this email because you use Local Eats.",
+		"receipt_subject":    "Your receipt from %s",
+		"receipt_title":      "Thanks for your order!",
+		"receipt_intro":      "Here is the receipt of your order from %s.",
+		"order":              "Order",
+		"date":               "Date",
+		"dish":               "Dish",
+		"quantity":           "Qty",
+		"price":              "Price",
+		"total":              "Total",
+		"address":            "Delivery address",
+		"verified_subject":   "%s is verified",
+		"verified_text":      "%s passed the document check and now shows the verified badge.",
+		"rejected_subject":   "%s wasn't verified",
+		"rejected_text":      "We couldn't verify %s from the submitted documents. Submit new documents in the app to try again.",
+		"note":               "Note",
+		"invitation_subject": "You're invited to Local Eats",
+		"invitation_text":    "You're invited to open your kitchen on Local Eats.",
+		"staff_subject":      "You're invited to %s",
+		"staff_text":         "You're invited to join the staff of %s on Local Eats.",
+		"greeting":           "Hi %s,",
+		"register":           "Register",
+		"expires":            "The invitation expires at %s.",
+		"staff_accept":       "Accept the invitation in the app to start.",
+		"dish_subject":       "%s is available again",
+		"dish_text":          "%s is back on the menu. Order it before it sells out again.",
+		"report_subject":     "Local Eats %s report %s - %s",
+		"report_text":        "Your kitchen statistics report for %s - %s is attached.",
+	},
+	"ru": {
+		"footer":             "Вы получили это письмо, потому что пользуетесь Local Eats.",
+		"receipt_subject":    "Ваш чек от %s",
+		"receipt_title":      "Спасибо за заказ!",
+		"receipt_intro":      "Чек вашего заказа в %s.",
+		"order":              "Заказ",
+		"date":               "Дата",
+		"dish":               "Блюдо",
+		"quantity":           "Кол-во",
+		"price":              "Цена",
+		"total":              "Итого",
+		"address":            "Адрес доставки",
+		"verified_subject":   "%s подтверждена",
+		"verified_text":      "%s прошла проверку документов и теперь отмечена как проверенная.",
+		"rejected_subject":   "%s не подтверждена",
+		"rejected_text":      "Не удалось подтвердить %s по отправленным документам. Отправьте новые документы в приложении, чтобы попробовать снова.",
+		"note":               "Комментарий",
+		"invitation_subject": "Приглашение в Local Eats",
+		"invitation_text":    "Вас приглашают открыть свою кухню в Local Eats.",
+		"staff_subject":      "Приглашение в %s",
+		"staff_text":         "Вас приглашают в команду %s в Local Eats.",
+		"greeting":           "Здравствуйте, %s!",
+		"register":           "Зарегистрироваться",
+		"expires":            "Приглашение действует до %s.",
+		"staff_accept":       "Примите приглашение в приложении, чтобы начать.",
+		"dish_subject":       "%s снова в наличии",
+		"dish_text":          "%s снова в меню. Закажите, пока не закончилось.",
+		"report_subject":     "Отчёт Local Eats (%s) %s - %s",
+		"report_text":        "Отчёт по статистике вашей кухни за %s - %s во вложении.",
+	},
+	"uz": {
+		"footer":             "Bu xat sizga Local Eats'dan foydalanganingiz uchun yuborildi.",
+		"receipt_subject":    "%s chekingiz",
+		"receipt_title":      "Buyurtmangiz uchun rahmat!",
+		"receipt_intro":      "%s dagi buyurtmangiz cheki.",
+		"order":              "Buyurtma",
+		"date":               "Sana",
+		"dish":               "Taom",
+		"quantity":           "Soni",
+		"price":              "Narxi",
+		"total":              "Jami",
+		"address":            "Yetkazish manzili",
+		"verified_subject":   "%s tasdiqlandi",
+		"verified_text":      "%s hujjatlar tekshiruvidan o'tdi va endi tasdiqlangan belgisiga ega.",
+		"rejected_subject":   "%s tasdiqlanmadi",
+		"rejected_text":      "Yuborilgan hujjatlar bo'yicha %s ni tasdiqlab bo'lmadi. Qayta urinish uchun ilovada yangi hujjatlarni yuboring.",
+		"note":               "Izoh",
+		"invitation_subject": "Local Eats'ga taklif",
+		"invitation_text":    "Sizni Local Eats'da oshxonangizni ochishga taklif qilamiz.",
+		"staff_subject":      "%s ga taklif",
+		"staff_text":         "Sizni Local Eats'dagi %s jamoasiga taklif qilamiz.",
+		"greeting":           "Salom, %s!",
+		"register":           "Ro'yxatdan o'tish",
+		"expires":            "Taklif %s gacha amal qiladi.",
+		"staff_accept":       "Boshlash uchun taklifni ilovada qabul qiling.",
+		"dish_subject":       "%s yana mavjud",
+		"dish_text":          "%s yana menyuda. Tugab qolmasidan buyurtma bering.",
+		"report_subject":     "Local Eats %s hisoboti %s - %s",
+		"report_text":        "Oshxonangizning %s - %s davridagi statistika hisoboti ilova qilingan.",
+	},
+}
@@ -0,0 +1,97 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	blockTags = regexp.MustCompile(`(?i)<(br|/p|/h[1-6]|/tr|/li|/div)[^>]*>`)
+	cellTags  = regexp.MustCompile(`(?i)</t[dh]>`)
+	tags      = regexp.MustCompile(`(?s)<style.*?</style>|<[^>]*>`)
+	blanks    = regexp.MustCompile(`[ \t]+`)
+	newlines  = regexp.MustCompile(`\n\s*\n\s*`)
+)
+
+// PlainText turns a rendered HTML email into its plain text alternative.
+func PlainText(s string) string {
+	s = blockTags.ReplaceAllString(s, "$0\n")
+	s = cellTags.ReplaceAllString(s, "$0 ")
+	s = html.UnescapeString(tags.ReplaceAllString(s, ""))
+	s = blanks.ReplaceAllString(s, " ")
+	s = newlines.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// Build encodes the message as MIME: the text and HTML as alternatives,
+// followed by the attachments.
+func Build(m Message) ([]byte, error) {
+	var buf bytes.Buffer
+	mixed := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\n"+
+		"Content-Type: multipart/mixed; boundary=%s\r\n\r\n", m.From, m.To,
+		mime.QEncoding.Encode("utf-8", m.Subject), time.Now().Format(time.RFC1123Z), mixed.Boundary())
+
+	var alt bytes.Buffer
+	altw := multipart.NewWriter(&alt)
+	for _, p := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		part, err := altw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(part)
+		io.WriteString(qp, p.body)
+		qp.Close()
+	}
+	if err := altw.Close(); err != nil {
+		return nil, err
+	}
+
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + altw.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	part.Write(alt.Bytes())
+
+	for _, a := range m.Attachments {
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", a.Filename)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		// MIME limits encoded lines to 76 characters.
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		fmt.Fprintf(part, "%s\r\n", encoded)
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,108 @@
+package email
+
+import (
+	"api-gateway/models"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SendGrid sends messages with the SendGrid v3 mail send API.
+type SendGrid struct {
+	apiURL string
+	apiKey string
+	client *http.Client
+}
+
+func NewSendGrid(apiURL, apiKey string) *SendGrid {
+	return &SendGrid{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		apiKey: apiKey,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *SendGrid) Name() string {
+	return models.EmailSendGrid
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridAttachment struct {
+	Content  string `json:"content"`
+	Type     string `json:"type"`
+	Filename string `json:"filename"`
+}
+
+type sendGridMail struct {
+	Personalizations []struct {
+		To []sendGridAddress `json:"to"`
+	} `json:"personalizations"`
+	From        sendGridAddress      `json:"from"`
+	Subject     string               `json:"subject"`
+	Content     []sendGridContent    `json:"content"`
+	Attachments []sendGridAttachment `json:"attachments,omitempty"`
+}
+
+func (s *SendGrid) Send(ctx context.Context, m Message) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return errors.Wrap(err, "invalid sender")
+	}
+
+	in := sendGridMail{
+		From:    sendGridAddress{Email: from.Address, Name: from.Name},
+		Subject: m.Subject,
+		// The plain text must come first.
+		Content: []sendGridContent{{"text/plain", m.Text}, {"text/html", m.HTML}},
+	}
+	in.Personalizations = make([]struct {
+		To []sendGridAddress `json:"to"`
+	}, 1)
+	in.Personalizations[0].To = []sendGridAddress{{Email: m.To}}
+	for _, a := range m.Attachments {
+		in.Attachments = append(in.Attachments, sendGridAttachment{
+			Content:  base64.StdEncoding.EncodeToString(a.Data),
+			Type:     a.ContentType,
+			Filename: a.Filename,
+		})
+	}
+
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiURL+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		out, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("SendGrid returned %s: %s", resp.Status, out)
+	}
+	return nil
+}
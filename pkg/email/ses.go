@@ -0,0 +1,81 @@
+package email
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/awssig"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SES sends raw MIME messages with the Amazon SES v2 API, signing the
+// requests with AWS Signature Version 4.
+type SES struct {
+	apiURL string
+	cred   awssig.Credentials
+	client *http.Client
+}
+
+// NewSES returns a driver for the SES endpoint of the region unless
+// apiURL overrides it.
+func NewSES(apiURL, region, accessKey, secretKey string) *SES {
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("https://email.%s.amazonaws.com", region)
+	}
+	return &SES{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		cred:   awssig.Credentials{Region: region, AccessKey: accessKey, SecretKey: secretKey},
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *SES) Name() string {
+	return models.EmailSES
+}
+
+func (s *SES) Send(ctx context.Context, m Message) error {
+	raw, err := Build(m)
+	if err != nil {
+		return err
+	}
+
+	var in struct {
+		Content struct {
+			Raw struct {
+				// Data is base64 encoded by encoding/json.
+				Data []byte `json:"Data"`
+			} `json:"Raw"`
+		} `json:"Content"`
+	}
+	in.Content.Raw.Data = raw
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiURL+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	awssig.Sign(req, body, "ses", s.cred, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		out, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("SES returned %s: %s", resp.Status, out)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package email
+
+import (
+	"api-gateway/models"
+	"context"
+	"net"
+	"net/mail"
+	"net/smtp"
+
+	"github.com/pkg/errors"
+)
+
+// SMTP sends messages through an SMTP server. Authentication is skipped
+// when no user is set.
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+}
+
+func NewSMTP(addr, user, password string) *SMTP {
+	s := &SMTP{addr: addr}
+	if user != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		s.auth = smtp.PlainAuth("", user, password, host)
+	}
+	return s
+}
+
+func (s *SMTP) Name() string {
+	return models.EmailSMTP
+}
+
+// Send ignores the context: net/smtp has no way to cancel a session.
+func (s *SMTP) Send(_ context.Context, m Message) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return errors.Wrap(err, "invalid sender")
+	}
+	body, err := Build(m)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(smtp.SendMail(s.addr, s.auth, from.Address, []string{m.To}, body),
+		"error sending email")
+}
@@ -0,0 +1,146 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed templates/*.html
+var files embed.FS
+
+var funcs = template.FuncMap{
+	"price": func(p float32) string {
+		if p == float32(int64(p)) {
+			return fmt.Sprintf("%d", int64(p))
+		}
+		return fmt.Sprintf("%.2f", p)
+	},
+}
+
+// templates holds every email template, each parsed together with the
+// layout. A template defines its "subject" and "content".
+var templates = func() map[string]*template.Template {
+	layout := template.Must(template.New("layout.html").Funcs(funcs).ParseFS(files, "templates/layout.html"))
+
+	entries, err := files.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+	res := map[string]*template.Template{}
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".html")
+		if name == "layout" {
+			continue
+		}
+		res[name] = template.Must(template.Must(layout.Clone()).ParseFS(files, "templates/"+e.Name()))
+	}
+	return res
+}()
+
+// ReceiptData is shown by the receipt template.
+type ReceiptData struct {
+	OrderId     string
+	KitchenName string
+	Items       []ReceiptItem
+	Total       float32
+	Currency    string
+	Address     string
+	Date        string
+}
+
+type ReceiptItem struct {
+	Name     string
+	Quantity int32
+	Price    float32
+	Total    float32
+}
+
+// VerificationData is shown by the verification template.
+type VerificationData struct {
+	KitchenName string
+	Verified    bool
+	Note        string
+}
+
+// InvitationData is shown by the invitation template. KitchenName is
+// empty for kitchen owner invitations.
+type InvitationData struct {
+	FullName    string
+	KitchenName string
+	Url         string
+	ExpiresAt   string
+}
+
+// StaffInviteData is shown by the staff_invite template.
+type StaffInviteData struct {
+	KitchenName string
+}
+
+// DishAvailableData is shown by the dish_available template.
+type DishAvailableData struct {
+	DishName string
+}
+
+// ReportData is shown by the report template.
+type ReportData struct {
+	Frequency string
+	StartDate string
+	EndDate   string
+}
+
+// BroadcastData is shown by the broadcast template.
+type BroadcastData struct {
+	Title string
+	Body  string
+}
+
+// AlertData is shown by the alert template, which tells admins about
+// something that needs their attention.
+type AlertData struct {
+	Title string
+	Body  string
+}
+
+// page is what a template is executed with.
+type page struct {
+	Lang string
+	L    map[string]string
+	Data any
+}
+
+// Lang returns the supported language closest to lang, English by
+// default.
+func Lang(lang string) string {
+	lang, _, _ = strings.Cut(strings.ToLower(lang), "-")
+	if _, ok := labels[lang]; ok {
+		return lang
+	}
+	return "en"
+}
+
+// Render executes the template in lang, returning the subject and HTML
+// body.
+func Render(name, lang string, data any) (string, string, error) {
+	t, ok := templates[name]
+	if !ok {
+		return "", "", errors.Errorf("unknown email template %q", name)
+	}
+	lang = Lang(lang)
+	p := page{Lang: lang, L: labels[lang], Data: data}
+
+	var subject, body bytes.Buffer
+	if err := t.ExecuteTemplate(&subject, "subject", p); err != nil {
+		return "", "", errors.Wrap(err, "error rendering email subject")
+	}
+	if err := t.ExecuteTemplate(&body, "layout", p); err != nil {
+		return "", "", errors.Wrap(err, "error rendering email")
+	}
+	// The subject is a header, not HTML.
+	return strings.TrimSpace(html.UnescapeString(subject.String())), body.String(), nil
+}
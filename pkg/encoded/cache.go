@@ -0,0 +1,83 @@
+package encoded
+
+import (
+	"bytes"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxEntries bounds the cache; it is emptied when full, which with one
+// entry per kitchen and format only happens as kitchens come and go.
+const maxEntries = 10000
+
+type key struct {
+	message protoreflect.FullName
+	id      string
+	variant string
+}
+
+type entry struct {
+	wire []byte
+	json []byte
+}
+
+// Cache keeps the JSON encoding of protobuf messages by type, ID and
+// variant, such as the response format, together with their wire
+// encoding. A message whose wire encoding is unchanged is not encoded again, so the
+// cache is never stale and needs no invalidation. The wire encoding is
+// cheap next to JSON and is made in a pooled buffer, so hits allocate
+// nothing.
+type Cache struct {
+	mu      sync.RWMutex
+	entries map[key]entry
+	wires   sync.Pool
+}
+
+func NewCache() *Cache {
+	return &Cache{
+		entries: make(map[key]entry),
+		wires: sync.Pool{New: func() any {
+			b := make([]byte, 0, 1024)
+			return &b
+		}},
+	}
+}
+
+var wireOptions = proto.MarshalOptions{Deterministic: true}
+
+// Append appends the JSON of m, cached under id and variant, to dst.
+// encode makes it when m changed since it was cached; its result is kept,
+// so it must not be reused by the caller.
+func (c *Cache) Append(dst []byte, id, variant string, m proto.Message, encode func() ([]byte, error)) ([]byte, error) {
+	wp := c.wires.Get().(*[]byte)
+	defer c.wires.Put(wp)
+
+	wire, err := wireOptions.MarshalAppend((*wp)[:0], m)
+	if err != nil {
+		return dst, err
+	}
+	*wp = wire
+
+	k := key{message: m.ProtoReflect().Descriptor().FullName(), id: id, variant: variant}
+	c.mu.RLock()
+	e, ok := c.entries[k]
+	c.mu.RUnlock()
+	if ok && bytes.Equal(e.wire, wire) {
+		return append(dst, e.json...), nil
+	}
+
+	data, err := encode()
+	if err != nil {
+		return dst, err
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= maxEntries {
+		clear(c.entries)
+	}
+	c.entries[k] = entry{wire: bytes.Clone(wire), json: data}
+	c.mu.Unlock()
+	return append(dst, data...), nil
+}
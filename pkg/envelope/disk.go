@@ -0,0 +1,52 @@
+package envelope
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// WriteFile writes data to the file at path, sealed with keys unless
+// keys is nil. The file and its directory are for the gateway's user
+// only.
+func WriteFile(keys *Keyring, path string, data []byte) error {
+	if keys != nil {
+		var err error
+		if data, err = keys.Seal(data); err != nil {
+			return errors.Wrap(err, "error sealing file")
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	// The file is written aside and renamed, so resealing it never leaves
+	// it half written.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadFile reads a file written by WriteFile. Files written before
+// encryption was turned on are read as they are.
+func ReadFile(keys *Keyring, path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || keys == nil || !Sealed(data) {
+		return data, err
+	}
+	data, err = keys.Open(data)
+	return data, errors.Wrap(err, "error opening file")
+}
+
+// ResealFile seals the file at path again with the current data key.
+func ResealFile(keys *Keyring, path string) error {
+	data, err := ReadFile(keys, path)
+	if err != nil {
+		return err
+	}
+	return WriteFile(keys, path, data)
+}
@@ -0,0 +1,262 @@
+// Package envelope encrypts the personal data the gateway keeps with
+// envelope encryption: values are sealed with AES-256-GCM under a data
+// key, and the data key is wrapped by a key encryption key from a key
+// file or a KMS, so only the wrapped data key is ever written down.
+package envelope
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// version starts every sealed value, followed by the 16-byte ID of
+	// its data key, the nonce and the ciphertext.
+	version    = 0xE1
+	headerSize = 1 + 16
+	keyTimeout = 10 * time.Second
+)
+
+var (
+	ErrUnknownKey = errors.New("sealed with an unknown data key")
+	ErrMalformed  = errors.New("malformed sealed value")
+	ErrRotating   = errors.New("keys are being rotated")
+)
+
+// KeyProvider wraps data keys with key encryption keys.
+type KeyProvider interface {
+	// Name is the provider as configured, file or aws-kms.
+	Name() string
+	// KeyID is the key data keys are wrapped with now.
+	KeyID() string
+	Wrap(ctx context.Context, key []byte) ([]byte, error)
+	// Unwrap unwraps a data key wrapped with the key keyID.
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	// Reload picks up a new key encryption key when keys are rotated.
+	Reload() error
+}
+
+// WrappedKey is a data key as written down: wrapped by the key
+// encryption key KeyId.
+type WrappedKey struct {
+	Id      string `json:"id"`
+	KeyId   string `json:"key_id"`
+	Wrapped []byte `json:"wrapped"`
+}
+
+type dataKey struct {
+	WrappedKey
+	aead cipher.AEAD
+}
+
+// Keyring seals and opens values with its current data key, and opens
+// those sealed with the data keys it still holds.
+type Keyring struct {
+	provider KeyProvider
+	rotating sync.Mutex
+
+	mu        sync.RWMutex
+	current   *dataKey
+	keys      map[string]*dataKey
+	rotatedAt time.Time
+}
+
+// New returns the keyring of the configured provider, or nil if
+// encryption at rest is off.
+func New(cfg *config.Config) (*Keyring, error) {
+	var provider KeyProvider
+	switch cfg.ENCRYPTION_KEY_PROVIDER {
+	case "":
+		return nil, nil
+	case models.KeyProviderFile:
+		keys, err := NewFileKeys(cfg.ENCRYPTION_KEY_FILE)
+		if err != nil {
+			return nil, err
+		}
+		provider = keys
+	case models.KeyProviderKMS:
+		provider = NewKMS(cfg.ENCRYPTION_KMS_API_URL, cfg.ENCRYPTION_KMS_REGION, cfg.ENCRYPTION_KMS_KEY_ID,
+			cfg.ENCRYPTION_KMS_ACCESS_KEY_ID, cfg.ENCRYPTION_KMS_SECRET_ACCESS_KEY)
+	default:
+		return nil, errors.Errorf("unknown key provider %q", cfg.ENCRYPTION_KEY_PROVIDER)
+	}
+	return NewKeyring(provider)
+}
+
+// NewKeyring starts a keyring with a new data key wrapped by provider.
+func NewKeyring(provider KeyProvider) (*Keyring, error) {
+	k := &Keyring{provider: provider, keys: make(map[string]*dataKey)}
+	if err := k.newDataKey(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *Keyring) newDataKey() error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return errors.Wrap(err, "error generating data key")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyTimeout)
+	defer cancel()
+	wrapped, err := k.provider.Wrap(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "error wrapping data key")
+	}
+	dk, err := newDataKey(WrappedKey{Id: uuid.NewString(), KeyId: k.provider.KeyID(), Wrapped: wrapped}, key)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.current = dk
+	k.keys[dk.Id] = dk
+	k.rotatedAt = time.Now()
+	return nil
+}
+
+func newDataKey(w WrappedKey, key []byte) (*dataKey, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data key")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &dataKey{WrappedKey: w, aead: aead}, nil
+}
+
+// Seal encrypts plaintext with the current data key.
+func (k *Keyring) Seal(plaintext []byte) ([]byte, error) {
+	k.mu.RLock()
+	dk := k.current
+	k.mu.RUnlock()
+
+	id := uuid.MustParse(dk.Id)
+	header := append([]byte{version}, id[:]...)
+	nonce := make([]byte, dk.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "error generating nonce")
+	}
+	out := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+dk.aead.Overhead())
+	out = append(append(out, header...), nonce...)
+	// The header is authenticated, so a value can't be moved to another
+	// data key.
+	return dk.aead.Seal(out, nonce, plaintext, header), nil
+}
+
+// Open decrypts a value sealed with any data key the keyring holds.
+func (k *Keyring) Open(sealed []byte) ([]byte, error) {
+	if !Sealed(sealed) {
+		return nil, ErrMalformed
+	}
+	id, _ := uuid.FromBytes(sealed[1:headerSize])
+	k.mu.RLock()
+	dk, ok := k.keys[id.String()]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, errors.Wrap(ErrUnknownKey, id.String())
+	}
+
+	n := dk.aead.NonceSize()
+	if len(sealed) < headerSize+n {
+		return nil, ErrMalformed
+	}
+	nonce := sealed[headerSize : headerSize+n]
+	return dk.aead.Open(nil, nonce, sealed[headerSize+n:], sealed[:headerSize])
+}
+
+// Sealed tells whether b looks like a sealed value, so values written
+// before encryption was turned on can be told apart.
+func Sealed(b []byte) bool {
+	return len(b) > headerSize && b[0] == version
+}
+
+// Current returns the current data key, wrapped.
+func (k *Keyring) Current() WrappedKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current.WrappedKey
+}
+
+// Import unwraps a data key that was written down, so the values sealed
+// with it can be opened.
+func (k *Keyring) Import(w WrappedKey) error {
+	k.mu.RLock()
+	_, ok := k.keys[w.Id]
+	k.mu.RUnlock()
+	if ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyTimeout)
+	defer cancel()
+	key, err := k.provider.Unwrap(ctx, w.KeyId, w.Wrapped)
+	if err != nil {
+		return errors.Wrapf(err, "error unwrapping data key %s", w.Id)
+	}
+	dk, err := newDataKey(w, key)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[w.Id] = dk
+	return nil
+}
+
+// Rotate reloads the key encryption key, starts sealing with a new data
+// key and calls reseal to seal what is kept with it. The older data keys
+// are dropped once reseal succeeds; if it fails they are kept, so what
+// is still sealed with them can be opened and rotating tried again.
+func (k *Keyring) Rotate(reseal func() error) error {
+	if !k.rotating.TryLock() {
+		return ErrRotating
+	}
+	defer k.rotating.Unlock()
+
+	if err := k.provider.Reload(); err != nil {
+		return errors.Wrap(err, "error reloading key encryption key")
+	}
+	if err := k.newDataKey(); err != nil {
+		return err
+	}
+	if err := reseal(); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for id := range k.keys {
+		if id != k.current.Id {
+			delete(k.keys, id)
+		}
+	}
+	return nil
+}
+
+// Status describes the keys in use.
+func (k *Keyring) Status() models.EncryptionStatus {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return models.EncryptionStatus{
+		Enabled:   true,
+		Provider:  k.provider.Name(),
+		KeyId:     k.current.KeyId,
+		DataKeyId: k.current.Id,
+		DataKeys:  len(k.keys),
+		RotatedAt: k.rotatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,218 @@
+package envelope
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// keyring starts a keyring on a key file in a temporary directory and
+// returns the path of the file, so tests can append keys to it.
+func keyring(t *testing.T) (*Keyring, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys")
+	addKey(t, path, "kek-1")
+	keys, err := NewFileKeys(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := NewKeyring(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k, path
+}
+
+func addKey(t *testing.T, path, id string) {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(id + " " + base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	k, _ := keyring(t)
+	plain := []byte(`{"note":"leave at the door"}`)
+
+	sealed, err := k.Seal(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Sealed(sealed) || bytes.Contains(sealed, plain) {
+		t.Fatalf("value is not sealed: %q", sealed)
+	}
+	again, err := k.Seal(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sealed, again) {
+		t.Error("sealing twice gives the same value")
+	}
+
+	got, err := k.Open(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("Open = %q, want %q", got, plain)
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	k, _ := keyring(t)
+	sealed, err := k.Seal([]byte("call on arrival"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, _ := keyring(t)
+	otherSealed, err := other.Seal([]byte("call on arrival"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flip := func(i int) []byte {
+		b := bytes.Clone(sealed)
+		b[i] ^= 1
+		return b
+	}
+	tests := map[string]struct {
+		sealed []byte
+		want   error
+	}{
+		"ciphertext":    {flip(len(sealed) - 1), nil},
+		"nonce":         {flip(headerSize), nil},
+		"key id":        {flip(1), ErrUnknownKey},
+		"version":       {flip(0), ErrMalformed},
+		"truncated":     {sealed[:headerSize+4], ErrMalformed},
+		"other keyring": {otherSealed, ErrUnknownKey},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := k.Open(tt.sealed)
+			if err == nil {
+				t.Fatal("tampered value opened")
+			}
+			if tt.want != nil && errors.Cause(err) != tt.want {
+				t.Errorf("Open error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	k, path := keyring(t)
+	old, err := k.Seal([]byte("allergic to peanuts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKey := k.Current()
+
+	// A failed reseal keeps the old data key, so rotating can be tried
+	// again.
+	if err := k.Rotate(func() error { return errors.New("outbox is down") }); err == nil {
+		t.Fatal("Rotate ignored the reseal error")
+	}
+	if _, err := k.Open(old); err != nil {
+		t.Fatalf("old value can't be opened after a failed rotation: %v", err)
+	}
+
+	addKey(t, path, "kek-2")
+	var resealed []byte
+	err = k.Rotate(func() error {
+		plain, err := k.Open(old)
+		if err != nil {
+			return err
+		}
+		resealed, err = k.Seal(plain)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := k.Current()
+	if current.Id == oldKey.Id {
+		t.Error("Rotate kept the data key")
+	}
+	if current.KeyId != "kek-2" {
+		t.Errorf("data key is wrapped with %q, want the appended kek-2", current.KeyId)
+	}
+	if _, err := k.Open(old); errors.Cause(err) != ErrUnknownKey {
+		t.Errorf("value sealed with the dropped data key: Open error = %v, want %v", err, ErrUnknownKey)
+	}
+	got, err := k.Open(resealed)
+	if err != nil || string(got) != "allergic to peanuts" {
+		t.Errorf("Open(resealed) = %q, %v", got, err)
+	}
+	if s := k.Status(); s.DataKeys != 1 {
+		t.Errorf("%d data keys held after rotation, want 1", s.DataKeys)
+	}
+
+	// Data keys written down before the rotation are still unwrapped by
+	// the key encryption key they were wrapped with.
+	if err := k.Import(oldKey); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Open(old); err != nil {
+		t.Errorf("Open after Import: %v", err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	k, _ := keyring(t)
+	path := filepath.Join(t.TempDir(), "reports", "r.csv")
+	data := []byte("date,orders,revenue\n2026-10-17,12,840000\n")
+
+	if err := WriteFile(k, path, data); err != nil {
+		t.Fatal(err)
+	}
+	disk, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(disk, data) {
+		t.Error("file is written in the clear")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("file mode = %v, want 0600", mode)
+	}
+
+	if err := k.Rotate(func() error { return ResealFile(k, path) }); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(k, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("ReadFile = %q, want %q", got, data)
+	}
+
+	// Files written before encryption was turned on are read as they are.
+	plain := filepath.Join(t.TempDir(), "plain.csv")
+	if err := WriteFile(nil, plain, data); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadFile(k, plain); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("ReadFile(plain) = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,117 @@
+package envelope
+
+import (
+	"api-gateway/models"
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// FileKeys are key encryption keys read from a file, one per line as an
+// ID and a base64 256-bit key separated by a space. The last key wraps
+// new data keys; the others still unwrap those they wrapped. Keys are
+// rotated by appending a key and reloading.
+type FileKeys struct {
+	path string
+
+	mu      sync.RWMutex
+	keys    map[string]cipher.AEAD
+	current string
+}
+
+func NewFileKeys(path string) (*FileKeys, error) {
+	f := &FileKeys{path: path}
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *FileKeys) Name() string {
+	return models.KeyProviderFile
+}
+
+func (f *FileKeys) KeyID() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.current
+}
+
+// Reload reads the file again.
+func (f *FileKeys) Reload() error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return errors.Wrap(err, "error opening key file")
+	}
+	defer file.Close()
+
+	keys := make(map[string]cipher.AEAD)
+	var current string
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(line, " ")
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if !ok || err != nil || len(key) != 32 {
+			return errors.Errorf("key file line %d must be an ID and a base64 256-bit key", n)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return err
+		}
+		if keys[id], err = cipher.NewGCM(block); err != nil {
+			return err
+		}
+		current = id
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "error reading key file")
+	}
+	if current == "" {
+		return errors.New("key file has no keys")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys, f.current = keys, current
+	return nil
+}
+
+func (f *FileKeys) Wrap(ctx context.Context, key []byte) ([]byte, error) {
+	f.mu.RLock()
+	aead := f.keys[f.current]
+	id := f.current
+	f.mu.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, key, []byte(id)), nil
+}
+
+func (f *FileKeys) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	f.mu.RLock()
+	aead, ok := f.keys[keyID]
+	f.mu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("key %q is not in the key file", keyID)
+	}
+
+	n := aead.NonceSize()
+	if len(wrapped) < n {
+		return nil, ErrMalformed
+	}
+	return aead.Open(nil, wrapped[:n], wrapped[n:], []byte(keyID))
+}
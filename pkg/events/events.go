@@ -0,0 +1,210 @@
+package events
+
+import (
+	"api-gateway/config"
+	"api-gateway/pkg/envelope"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	bufferSize     = 1024
+	batchSize      = 100
+	publishTimeout = 5 * time.Second
+)
+
+type Event struct {
+	Id        string `json:"id"`
+	Type      string `json:"type"`
+	Key       string `json:"key"`
+	CreatedAt string `json:"created_at"`
+	Data      any    `json:"data"`
+}
+
+// Publisher writes an encoded event to a topic of a message broker.
+type Publisher interface {
+	Publish(ctx context.Context, topic, key string, payload []byte) error
+	Close() error
+}
+
+// Emitter publishes events in the background so that request handlers
+// never wait for the broker. Events that cannot be published are kept in
+// the outbox and retried until the broker accepts them.
+type Emitter struct {
+	publisher Publisher
+	outbox    *Outbox
+	topic     string
+	interval  time.Duration
+	logger    *slog.Logger
+	queue     chan Event
+}
+
+// NewEmitter connects to the broker selected by EVENT_BROKER ("kafka" or
+// "nats"). Events are dropped when no broker is configured. The outbox is
+// sealed with keys unless they are nil.
+func NewEmitter(cfg *config.Config, keys *envelope.Keyring, logger *slog.Logger) *Emitter {
+	e := &Emitter{
+		topic:    cfg.EVENT_TOPIC,
+		interval: cfg.OUTBOX_RETRY_INTERVAL,
+		logger:   logger,
+		queue:    make(chan Event, bufferSize),
+	}
+
+	var err error
+	switch cfg.EVENT_BROKER {
+	case "kafka":
+		e.publisher = NewKafkaPublisher(cfg.EVENT_BROKER_URL)
+	case "nats":
+		e.publisher, err = NewNATSPublisher(cfg.EVENT_BROKER_URL)
+	case "":
+	default:
+		err = errors.Errorf("unknown event broker %q", cfg.EVENT_BROKER)
+	}
+	if err != nil {
+		logger.Error(errors.Wrap(err, "events will not be published").Error())
+		e.publisher = nil
+	}
+
+	if e.publisher == nil {
+		return e
+	}
+
+	e.outbox, err = OpenOutbox(cfg.OUTBOX_PATH, keys)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "undelivered events will be lost").Error())
+	}
+
+	go e.run()
+	return e
+}
+
+// Emit queues an event of the given type. key groups events of the same
+// entity, e.g. an order ID, so consumers see them in order.
+func (e *Emitter) Emit(eventType, key string, data any) {
+	if e.publisher == nil {
+		return
+	}
+
+	ev := Event{
+		Id:        uuid.NewString(),
+		Type:      eventType,
+		Key:       key,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Data:      data,
+	}
+
+	select {
+	case e.queue <- ev:
+	default:
+		if r, err := encode(ev); err == nil {
+			e.store(r)
+		} else {
+			e.logger.Error(errors.Wrap(err, "error encoding event").Error())
+		}
+	}
+}
+
+func (e *Emitter) run() {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ev := <-e.queue:
+			e.handle(ev)
+		case <-ticker.C:
+			e.flush()
+		}
+	}
+}
+
+func (e *Emitter) handle(ev Event) {
+	r, err := encode(ev)
+	if err != nil {
+		e.logger.Error(errors.Wrap(err, "error encoding event").Error())
+		return
+	}
+
+	// While older events wait in the outbox, newer ones queue up behind
+	// them to keep the order consumers see.
+	if e.outbox != nil && !e.outbox.Empty() {
+		e.store(r)
+		return
+	}
+
+	if err := e.publish(r); err != nil {
+		e.logger.Error(errors.Wrapf(err, "error publishing %s event", r.Type).Error())
+		e.store(r)
+	}
+}
+
+// flush republishes stored events oldest first and stops at the first
+// failure, leaving the rest for the next tick.
+func (e *Emitter) flush() {
+	if e.outbox == nil {
+		return
+	}
+
+	for {
+		records, err := e.outbox.Pending(batchSize)
+		if err != nil {
+			e.logger.Error(errors.Wrap(err, "error reading outbox").Error())
+			return
+		}
+		if len(records) == 0 {
+			return
+		}
+
+		for _, r := range records {
+			if err := e.publish(r); err != nil {
+				e.logger.Error(errors.Wrapf(err, "error republishing %s event", r.Type).Error())
+				return
+			}
+			if err := e.outbox.Delete(r); err != nil {
+				e.logger.Error(errors.Wrap(err, "error deleting event from outbox").Error())
+				return
+			}
+		}
+	}
+}
+
+// Reseal seals the events in the outbox again with the current data key
+// and returns how many it resealed.
+func (e *Emitter) Reseal() (int, error) {
+	if e.outbox == nil {
+		return 0, nil
+	}
+	return e.outbox.Reseal()
+}
+
+func (e *Emitter) publish(r record) error {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	return e.publisher.Publish(ctx, e.topic, r.Key, r.Payload)
+}
+
+func (e *Emitter) store(r record) {
+	if e.outbox == nil {
+		e.logger.Error("no outbox, dropping " + r.Type + " event")
+		return
+	}
+
+	if err := e.outbox.Add(r); err != nil {
+		e.logger.Error(errors.Wrapf(err, "error storing %s event, dropping it", r.Type).Error())
+	}
+}
+
+func encode(ev Event) (record, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return record{}, err
+	}
+
+	return record{Type: ev.Type, Key: ev.Key, Payload: payload}, nil
+}
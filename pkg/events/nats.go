@@ -0,0 +1,36 @@
+package events
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+)
+
+type NATSPublisher struct {
+	conn *nats.Conn
+}
+
+func NewNATSPublisher(url string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url,
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATSPublisher{conn: conn}, nil
+}
+
+// Publish ignores the key: NATS subjects have no partitions to order by.
+func (p *NATSPublisher) Publish(ctx context.Context, topic, key string, payload []byte) error {
+	if err := p.conn.Publish(topic, payload); err != nil {
+		return err
+	}
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
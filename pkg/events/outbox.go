@@ -0,0 +1,223 @@
+package events
+
+import (
+	"api-gateway/pkg/envelope"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	outboxBucket = []byte("events")
+	// keysBucket holds the wrapped data keys records are sealed with, by
+	// ID.
+	keysBucket = []byte("keys")
+)
+
+// Outbox persists events that could not be published so they survive
+// broker outages and gateway restarts. Records are kept in emit order.
+// With a keyring, records are sealed, and records stored before
+// encryption was turned on are still read.
+type Outbox struct {
+	db   *bolt.DB
+	keys *envelope.Keyring
+}
+
+type record struct {
+	seq     uint64
+	Type    string `json:"type"`
+	Key     string `json:"key"`
+	Payload []byte `json:"payload"`
+}
+
+// OpenOutbox opens the outbox at path. keys may be nil to store records
+// in the clear.
+func OpenOutbox(path string, keys *envelope.Keyring) (*Outbox, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening outbox")
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(keysBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(outboxBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "error creating outbox bucket")
+	}
+
+	o := &Outbox{db: db, keys: keys}
+	if keys != nil {
+		// The data keys of records stored before a restart are unwrapped
+		// so the records can be read.
+		err = db.View(func(tx *bolt.Tx) error {
+			return tx.Bucket(keysBucket).ForEach(func(_, v []byte) error {
+				var w envelope.WrappedKey
+				if err := json.Unmarshal(v, &w); err != nil {
+					return err
+				}
+				return keys.Import(w)
+			})
+		})
+		if err != nil {
+			db.Close()
+			return nil, errors.Wrap(err, "error reading outbox keys")
+		}
+	}
+
+	return o, nil
+}
+
+func (o *Outbox) Add(r record) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return o.db.Update(func(tx *bolt.Tx) error {
+		// Sealing in the transaction keeps a record from being sealed with
+		// a data key a concurrent Reseal is about to forget.
+		if o.keys != nil {
+			if err := o.putKey(tx); err != nil {
+				return err
+			}
+			if value, err = o.keys.Seal(value); err != nil {
+				return err
+			}
+		}
+		b := tx.Bucket(outboxBucket)
+
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		return b.Put(itob(seq), value)
+	})
+}
+
+// Pending returns up to limit of the oldest records.
+func (o *Outbox) Pending(limit int) ([]record, error) {
+	var records []record
+
+	err := o.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(outboxBucket).Cursor()
+		for k, v := c.First(); k != nil && len(records) < limit; k, v = c.Next() {
+			v, err := o.open(v)
+			if err != nil {
+				return err
+			}
+			var r record
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			r.seq = binary.BigEndian.Uint64(k)
+			records = append(records, r)
+		}
+		return nil
+	})
+
+	return records, err
+}
+
+func (o *Outbox) Empty() bool {
+	empty := true
+	o.db.View(func(tx *bolt.Tx) error {
+		k, _ := tx.Bucket(outboxBucket).Cursor().First()
+		empty = k == nil
+		return nil
+	})
+	return empty
+}
+
+func (o *Outbox) Delete(r record) error {
+	return o.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(outboxBucket).Delete(itob(r.seq))
+	})
+}
+
+// Reseal seals every record again with the current data key, forgets
+// the other data keys and returns how many records it resealed.
+func (o *Outbox) Reseal() (int, error) {
+	if o.keys == nil {
+		return 0, nil
+	}
+
+	n := 0
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(outboxBucket)
+		resealed := make(map[string][]byte)
+		err := b.ForEach(func(k, v []byte) error {
+			v, err := o.open(v)
+			if err != nil {
+				return err
+			}
+			if resealed[string(k)], err = o.keys.Seal(v); err != nil {
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// Buckets can't be written to while they are iterated.
+		for k, v := range resealed {
+			if err := b.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		n = len(resealed)
+
+		if err := tx.DeleteBucket(keysBucket); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(keysBucket); err != nil {
+			return err
+		}
+		return o.putKey(tx)
+	})
+	return n, err
+}
+
+// putKey stores the current data key, wrapped, if records are sealed.
+func (o *Outbox) putKey(tx *bolt.Tx) error {
+	if o.keys == nil {
+		return nil
+	}
+	current := o.keys.Current()
+	b := tx.Bucket(keysBucket)
+	if b.Get([]byte(current.Id)) != nil {
+		return nil
+	}
+	value, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(current.Id), value)
+}
+
+// open opens a sealed record. Records stored in the clear are returned
+// as they are.
+func (o *Outbox) open(v []byte) ([]byte, error) {
+	if o.keys == nil || !envelope.Sealed(v) {
+		return v, nil
+	}
+	v, err := o.keys.Open(v)
+	return v, errors.Wrap(err, "error opening outbox record")
+}
+
+func (o *Outbox) Close() error {
+	return o.db.Close()
+}
+
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
@@ -0,0 +1,230 @@
+package fraud
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"api-gateway/pkg/routing"
+	"api-gateway/storage"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// velocityWindow is what the velocity rules count attempts over.
+const velocityWindow = time.Hour
+
+// severity orders the actions, so the strictest of the rules that match
+// is taken.
+var severity = map[string]int{
+	models.FraudAllow:  0,
+	models.FraudVerify: 1,
+	models.FraudReview: 2,
+}
+
+// Order is an order about to be placed. Device is where the customer's
+// phone is, when the app tells.
+type Order struct {
+	UserId   string
+	Amount   float32
+	Delivery *models.Point
+	Device   *models.Point
+}
+
+// Payment is a payment about to be charged.
+type Payment struct {
+	UserId  string
+	OrderId string
+	Amount  float32
+}
+
+// Decision is the strictest action of the rules that matched.
+type Decision struct {
+	Action string
+	Hits   []models.FraudHit
+}
+
+// Screener checks orders and payments before they are made against rules
+// set in the config:
+//   - velocity: more orders or payment attempts an hour than allowed;
+//   - geo mismatch: an order delivered further from the customer's phone
+//     than allowed;
+//   - high-value first order: a customer's first order, or first payment,
+//     of at least an amount;
+//   - chargeback: any order or payment of a customer who charged a payment
+//     back.
+//
+// Each rule takes its own action. Customers who recently passed an OTP
+// are not asked to verify again. History is kept from when the gateway
+// started.
+type Screener struct {
+	history        *storage.Store[models.FraudHistory]
+	ordersPerHour  int
+	paysPerHour    int
+	velocityAction string
+	geoKm          float64
+	geoAction      string
+	firstAmount    float32
+	firstAction    string
+	cbAction       string
+	verifiedTTL    time.Duration
+	logger         *slog.Logger
+}
+
+func NewScreener(cfg *config.Config, history *storage.Store[models.FraudHistory], logger *slog.Logger) *Screener {
+	return &Screener{
+		history:        history,
+		ordersPerHour:  cfg.FRAUD_ORDERS_PER_HOUR,
+		paysPerHour:    cfg.FRAUD_PAYMENTS_PER_HOUR,
+		velocityAction: cfg.FRAUD_VELOCITY_ACTION,
+		geoKm:          cfg.FRAUD_GEO_MISMATCH_KM,
+		geoAction:      cfg.FRAUD_GEO_MISMATCH_ACTION,
+		firstAmount:    float32(cfg.FRAUD_FIRST_ORDER_AMOUNT),
+		firstAction:    cfg.FRAUD_FIRST_ORDER_ACTION,
+		cbAction:       cfg.FRAUD_CHARGEBACK_ACTION,
+		verifiedTTL:    cfg.FRAUD_VERIFIED_TTL,
+		logger:         logger,
+	}
+}
+
+// Order counts the attempt and checks the order.
+func (s *Screener) Order(o Order, now time.Time) Decision {
+	h := s.history.Update(o.UserId, func(h models.FraudHistory, _ bool) models.FraudHistory {
+		h.Orders = append(recent(h.Orders, now), now.Format(time.RFC3339))
+		return h
+	})
+
+	var hits []models.FraudHit
+	if s.ordersPerHour > 0 && len(h.Orders) > s.ordersPerHour {
+		hits = append(hits, models.FraudHit{
+			Rule:   models.FraudRuleVelocity,
+			Action: s.velocityAction,
+			Detail: fmt.Sprintf("%d orders in the last hour, more than %d", len(h.Orders), s.ordersPerHour),
+		})
+	}
+	if s.geoKm > 0 && o.Delivery != nil && o.Device != nil {
+		if km := routing.Haversine(*o.Device, *o.Delivery); km > s.geoKm {
+			hits = append(hits, models.FraudHit{
+				Rule:   models.FraudRuleGeoMismatch,
+				Action: s.geoAction,
+				Detail: fmt.Sprintf("delivered %.0f km from the customer's phone, more than %.0f", km, s.geoKm),
+			})
+		}
+	}
+	if s.firstAmount > 0 && h.Placed == 0 && o.Amount >= s.firstAmount {
+		hits = append(hits, models.FraudHit{
+			Rule:   models.FraudRuleHighValueFirst,
+			Action: s.firstAction,
+			Detail: fmt.Sprintf("first order of %.2f, at least %.2f", o.Amount, s.firstAmount),
+		})
+	}
+	hits = s.chargebacks(h, hits)
+	return s.decide("order", o.UserId, h, hits, now)
+}
+
+// Payment counts the attempt and checks the payment.
+func (s *Screener) Payment(p Payment, now time.Time) Decision {
+	h := s.history.Update(p.UserId, func(h models.FraudHistory, _ bool) models.FraudHistory {
+		h.Payments = append(recent(h.Payments, now), now.Format(time.RFC3339))
+		return h
+	})
+
+	var hits []models.FraudHit
+	if s.paysPerHour > 0 && len(h.Payments) > s.paysPerHour {
+		hits = append(hits, models.FraudHit{
+			Rule:   models.FraudRuleVelocity,
+			Action: s.velocityAction,
+			Detail: fmt.Sprintf("%d payment attempts in the last hour, more than %d", len(h.Payments), s.paysPerHour),
+		})
+	}
+	if s.firstAmount > 0 && h.Paid == 0 && p.Amount >= s.firstAmount {
+		hits = append(hits, models.FraudHit{
+			Rule:   models.FraudRuleHighValueFirst,
+			Action: s.firstAction,
+			Detail: fmt.Sprintf("first payment of %.2f, at least %.2f", p.Amount, s.firstAmount),
+		})
+	}
+	hits = s.chargebacks(h, hits)
+	return s.decide("payment of order "+p.OrderId, p.UserId, h, hits, now)
+}
+
+func (s *Screener) chargebacks(h models.FraudHistory, hits []models.FraudHit) []models.FraudHit {
+	if h.Chargebacks == 0 {
+		return hits
+	}
+	return append(hits, models.FraudHit{
+		Rule:   models.FraudRuleChargeback,
+		Action: s.cbAction,
+		Detail: fmt.Sprintf("charged back %d payments", h.Chargebacks),
+	})
+}
+
+// decide takes the strictest action of the hits, letting customers who
+// recently passed an OTP off verifying again.
+func (s *Screener) decide(what, userID string, h models.FraudHistory, hits []models.FraudHit, now time.Time) Decision {
+	d := Decision{Action: models.FraudAllow, Hits: hits}
+	for _, hit := range hits {
+		if severity[hit.Action] > severity[d.Action] {
+			d.Action = hit.Action
+		}
+	}
+	if d.Action == models.FraudVerify && s.verified(h, now) {
+		d.Action = models.FraudAllow
+	}
+
+	if len(hits) > 0 {
+		rules := make([]string, len(hits))
+		for i, hit := range hits {
+			rules[i] = hit.Rule
+		}
+		s.logger.Warn(fmt.Sprintf("%s by user %s matched fraud rules %s: %s",
+			what, userID, strings.Join(rules, ", "), d.Action))
+	}
+	return d
+}
+
+func (s *Screener) verified(h models.FraudHistory, now time.Time) bool {
+	at, err := time.Parse(time.RFC3339, h.VerifiedAt)
+	return err == nil && now.Sub(at) < s.verifiedTTL
+}
+
+// Verified records that the customer passed an OTP.
+func (s *Screener) Verified(userID string, now time.Time) {
+	s.history.Update(userID, func(h models.FraudHistory, _ bool) models.FraudHistory {
+		h.VerifiedAt = now.Format(time.RFC3339)
+		return h
+	})
+}
+
+// Placed records that the customer placed an order.
+func (s *Screener) Placed(userID string) {
+	s.history.Update(userID, func(h models.FraudHistory, _ bool) models.FraudHistory {
+		h.Placed++
+		return h
+	})
+}
+
+// ChargedBack records that the customer disputed a payment.
+func (s *Screener) ChargedBack(userID string) {
+	s.history.Update(userID, func(h models.FraudHistory, _ bool) models.FraudHistory {
+		h.Chargebacks++
+		return h
+	})
+}
+
+// Paid records that the customer paid for an order.
+func (s *Screener) Paid(userID string) {
+	s.history.Update(userID, func(h models.FraudHistory, _ bool) models.FraudHistory {
+		h.Paid++
+		return h
+	})
+}
+
+// recent keeps the times of the last velocity window.
+func recent(times []string, now time.Time) []string {
+	return slices.DeleteFunc(slices.Clone(times), func(s string) bool {
+		t, _ := time.Parse(time.RFC3339, s)
+		return now.Sub(t) >= velocityWindow
+	})
+}
@@ -0,0 +1,58 @@
+// Package hub fans out messages to the clients watching a topic, such
+// as the WebSocket connections of a group order.
+package hub
+
+import "sync"
+
+type Hub[T any] struct {
+	mu   sync.Mutex
+	subs map[string]map[chan T]struct{}
+}
+
+func New[T any]() *Hub[T] {
+	return &Hub[T]{subs: make(map[string]map[chan T]struct{})}
+}
+
+// Subscribe returns the messages published to topic from now on and a
+// function that stops them. Messages are states rather than deltas, so
+// a subscriber that falls behind gets only the latest one.
+func (h *Hub[T]) Subscribe(topic string) (<-chan T, func()) {
+	ch := make(chan T, 1)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subs[topic] == nil {
+		h.subs[topic] = make(map[chan T]struct{})
+	}
+	h.subs[topic][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		delete(h.subs[topic], ch)
+		if len(h.subs[topic]) == 0 {
+			delete(h.subs, topic)
+		}
+	}
+}
+
+// Publish sends msg to the subscribers of topic without waiting for them.
+func (h *Hub[T]) Publish(topic string, msg T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[topic] {
+		select {
+		case ch <- msg:
+		default:
+			// Replace the message the subscriber has not read yet.
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- msg
+		}
+	}
+}
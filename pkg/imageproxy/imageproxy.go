@@ -0,0 +1,255 @@
+package imageproxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/pkg/errors"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// maxSourceSize caps how much of an object is read from storage.
+	maxSourceSize = 32 << 20
+	// maxPixels caps the images decoded to be resized, since a small,
+	// highly compressed file can decode to gigabytes.
+	maxPixels = 25_000_000
+	// maxCacheSize bounds the bytes of resized images kept; the cache is
+	// emptied when it is full.
+	maxCacheSize = 64 << 20
+)
+
+var (
+	ErrNotFound = errors.New("image not found")
+	ErrInvalid  = errors.New("invalid image request")
+)
+
+var contentTypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"webp": "image/webp",
+}
+
+// Options describe the transformation applied to the stored image. Zero
+// values keep the original dimensions and format.
+type Options struct {
+	Width  int
+	Height int
+	Format string
+}
+
+type Image struct {
+	Data         []byte
+	ContentType  string
+	ETag         string
+	LastModified string
+}
+
+// Proxy reads images from an HTTP object storage bucket and resizes or
+// converts them on the way out. Resized images are cached by the source's
+// ETag, so a source that is unchanged is not decoded again.
+type Proxy struct {
+	baseURL      string
+	maxDimension int
+	client       *http.Client
+
+	mu       sync.Mutex
+	variants map[string]Image
+	size     int
+}
+
+func NewProxy(baseURL string, maxDimension int) *Proxy {
+	return &Proxy{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		maxDimension: maxDimension,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		variants:     make(map[string]Image),
+	}
+}
+
+// MaxDimension is the largest width and height images are resized to.
+func (p *Proxy) MaxDimension() int {
+	return p.maxDimension
+}
+
+func (p *Proxy) Fetch(ctx context.Context, key string, opts Options) (Image, error) {
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+	if key == "" {
+		return Image{}, errors.Wrap(ErrInvalid, "empty image key")
+	}
+	if opts.Width < 0 || opts.Height < 0 ||
+		opts.Width > p.maxDimension || opts.Height > p.maxDimension {
+		return Image{}, errors.Wrapf(ErrInvalid, "width and height must be between 0 and %d", p.maxDimension)
+	}
+	if _, ok := contentTypes[opts.Format]; opts.Format != "" && !ok {
+		return Image{}, errors.Wrapf(ErrInvalid, "unknown format %q", opts.Format)
+	}
+
+	src, err := p.get(ctx, key)
+	if err != nil {
+		return Image{}, err
+	}
+
+	if opts == (Options{}) {
+		return src, nil
+	}
+
+	variant := src.ETag + "/" + fmt.Sprintf("%dx%d.%s", opts.Width, opts.Height, opts.Format)
+	if img, ok := p.cached(variant); ok {
+		return img, nil
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(src.Data))
+	if err != nil {
+		return Image{}, errors.Wrapf(ErrInvalid, "%s is not a supported image: %v", key, err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return Image{}, errors.Wrapf(ErrInvalid, "%s is too large to resize", key)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(src.Data))
+	if err != nil {
+		return Image{}, errors.Wrapf(ErrInvalid, "%s is not a supported image: %v", key, err)
+	}
+	if opts.Format == "" {
+		opts.Format = format
+	}
+	if _, ok := contentTypes[opts.Format]; !ok {
+		opts.Format = "jpeg"
+	}
+
+	img = resize(img, opts.Width, opts.Height)
+
+	var buf bytes.Buffer
+	switch opts.Format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	case "png":
+		err = png.Encode(&buf, img)
+	case "webp":
+		err = nativewebp.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		return Image{}, errors.Wrap(err, "error encoding image")
+	}
+
+	res := Image{
+		Data:         buf.Bytes(),
+		ContentType:  contentTypes[opts.Format],
+		ETag:         etag(src.ETag, fmt.Sprintf("%dx%d.%s", opts.Width, opts.Height, opts.Format)),
+		LastModified: src.LastModified,
+	}
+	p.cache(variant, res)
+	return res, nil
+}
+
+func (p *Proxy) cached(variant string) (Image, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	img, ok := p.variants[variant]
+	return img, ok
+}
+
+func (p *Proxy) cache(variant string, img Image) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(img.Data) > maxCacheSize {
+		return
+	}
+	if p.size+len(img.Data) > maxCacheSize {
+		clear(p.variants)
+		p.size = 0
+	}
+	if _, ok := p.variants[variant]; !ok {
+		p.size += len(img.Data)
+	}
+	p.variants[variant] = img
+}
+
+func (p *Proxy) get(ctx context.Context, key string) (Image, error) {
+	u := p.baseURL + "/" + (&url.URL{Path: key}).EscapedPath()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return Image{}, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Image{}, errors.Wrap(err, "error fetching image")
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return Image{}, errors.Wrap(ErrNotFound, key)
+	case resp.StatusCode != http.StatusOK:
+		return Image{}, errors.Errorf("object storage returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSourceSize))
+	if err != nil {
+		return Image{}, errors.Wrap(err, "error reading image")
+	}
+
+	tag := resp.Header.Get("ETag")
+	if tag == "" {
+		sum := sha256.Sum256(data)
+		tag = hex.EncodeToString(sum[:])
+	}
+
+	ct := resp.Header.Get("Content-Type")
+	if ct == "" {
+		ct = http.DetectContentType(data)
+	}
+
+	return Image{
+		Data:         data,
+		ContentType:  ct,
+		ETag:         etag(tag, ""),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// resize scales img down to fit in width x height, keeping its aspect
+// ratio. A zero bound is unconstrained; images are never scaled up.
+func resize(img image.Image, width, height int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	scale := 1.0
+	if width > 0 && width < w {
+		scale = float64(width) / float64(w)
+	}
+	if height > 0 && height < h {
+		scale = min(scale, float64(height)/float64(h))
+	}
+	if scale == 1 {
+		return img
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0,
+		max(1, int(float64(w)*scale+0.5)), max(1, int(float64(h)*scale+0.5))))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+	return dst
+}
+
+// etag derives a strong validator for a variant of the source object.
+func etag(source, variant string) string {
+	sum := sha256.Sum256([]byte(strings.Trim(source, `"`) + "/" + variant))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
@@ -0,0 +1,66 @@
+package invitation
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalid = errors.New("invalid invitation token")
+	ErrExpired = errors.New("invitation has expired")
+)
+
+// Signer issues and checks invitation tokens: JWTs signed with HS256
+// whose ID claim is the invitation ID.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewSigner returns a signer for tokens valid for ttl. Without a secret
+// a random one is used, so tokens stop working when the gateway restarts.
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	s := &Signer{secret: []byte(secret), ttl: ttl}
+	if secret == "" {
+		b := make([]byte, 32)
+		rand.Read(b)
+		s.secret = []byte(hex.EncodeToString(b))
+	}
+	return s
+}
+
+// Sign returns a token for the invitation and the time it expires.
+func (s *Signer) Sign(id, email string) (string, time.Time, error) {
+	expires := time.Now().Add(s.ttl)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
+		Id:        id,
+		Subject:   email,
+		ExpiresAt: expires.Unix(),
+		IssuedAt:  time.Now().Unix(),
+	}).SignedString(s.secret)
+	return token, expires, err
+}
+
+// Parse checks the token and returns the invitation ID it was issued for.
+func (s *Signer) Parse(token string) (string, error) {
+	var claims jwt.StandardClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, ErrInvalid
+		}
+		return s.secret, nil
+	})
+
+	var verr *jwt.ValidationError
+	switch {
+	case errors.As(err, &verr) && verr.Errors == jwt.ValidationErrorExpired:
+		return "", ErrExpired
+	case err != nil || claims.Id == "":
+		return "", ErrInvalid
+	}
+	return claims.Id, nil
+}
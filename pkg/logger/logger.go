@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"api-gateway/pkg/redact"
+	"context"
 	"log"
 	"log/slog"
 	"os"
@@ -11,13 +13,66 @@ func NewLogger() *slog.Logger {
 		Level: slog.LevelDebug,
 	}
 
-	file, err := os.OpenFile("app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	file, err := os.OpenFile("app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
 	if err != nil {
 		log.Fatalf("error opening file: %v", err)
 		return nil
 	}
 
-	logger := slog.New(slog.NewTextHandler(file, opts))
+	logger := slog.New(Redact(slog.NewTextHandler(file, opts)))
 
 	return logger
 }
+
+// Redact wraps h so that card numbers in messages and attributes are
+// masked before they are written.
+func Redact(h slog.Handler) slog.Handler {
+	return redactHandler{h}
+}
+
+type redactHandler struct {
+	slog.Handler
+}
+
+func (h redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, redact.PAN(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, out)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(a)
+	}
+	return redactHandler{h.Handler.WithAttrs(redacted)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{h.Handler.WithGroup(name)}
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redact.PAN(v.String()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		group := make([]any, len(attrs))
+		for i, ga := range attrs {
+			group[i] = redactAttr(ga)
+		}
+		return slog.Group(a.Key, group...)
+	case slog.KindAny:
+		// Errors and other values are logged through their text, which
+		// may carry a card number.
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, redact.PAN(err.Error()))
+		}
+	}
+	return a
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(Redact(slog.NewTextHandler(&buf, nil))).
+		With("card", "4111 1111 1111 1111")
+
+	log.Error("charging 4242424242424242 failed",
+		"error", errors.New("rpc error: card 5500000000000004 declined"),
+		slog.Group("payment", "card_number", "378282246310005"),
+		"order", "4111111111111112")
+
+	out := buf.String()
+	for _, pan := range []string{"4111 1111 1111 1111", "4242424242424242", "5500000000000004", "378282246310005"} {
+		if strings.Contains(out, pan) {
+			t.Errorf("log leaks %s: %s", pan, out)
+		}
+	}
+	for _, masked := range []string{"************1111", "************4242", "************0004", "***********0005"} {
+		if !strings.Contains(out, masked) {
+			t.Errorf("log lacks %s: %s", masked, out)
+		}
+	}
+	if !strings.Contains(out, "4111111111111112") {
+		t.Errorf("log masks a number that is not a card: %s", out)
+	}
+}
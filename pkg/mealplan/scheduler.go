@@ -0,0 +1,228 @@
+package mealplan
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const placeTimeout = 10 * time.Second
+
+// Placer places the order of a delivery and returns the order ID.
+type Placer func(ctx context.Context, order models.NewOrder) (string, error)
+
+// Notifier is told about a delivery whose order could not be placed,
+// with the plan as it is after the failure.
+type Notifier func(plan models.MealPlan, run models.MealPlanRun)
+
+// Zone returns the time zone of a kitchen, which its plans' delivery
+// times are in.
+type Zone func(kitchenID string) *time.Location
+
+// Scheduler places the orders of active meal plans lead before each
+// delivery and keeps a record of every attempt.
+type Scheduler struct {
+	storage *storage.Storage
+	place   Placer
+	notify  Notifier
+	zone    Zone
+	lead    time.Duration
+	logger  *slog.Logger
+}
+
+// NewScheduler starts checking for due deliveries every interval.
+func NewScheduler(s *storage.Storage, place Placer, notify Notifier, zone Zone,
+	lead, interval time.Duration, logger *slog.Logger) *Scheduler {
+	sc := &Scheduler{
+		storage: s,
+		place:   place,
+		notify:  notify,
+		zone:    zone,
+		lead:    lead,
+		logger:  logger,
+	}
+
+	go func() {
+		for now := range time.Tick(interval) {
+			sc.RunDue(now)
+		}
+	}()
+
+	return sc
+}
+
+// Lead is how long before a delivery its order is placed.
+func (s *Scheduler) Lead() time.Duration {
+	return s.lead
+}
+
+// Advance moves the plan to its first delivery after t, in the time zone
+// of its kitchen.
+func (s *Scheduler) Advance(p *models.MealPlan, t time.Time) {
+	Advance(p, t.In(s.zone(p.KitchenId)))
+}
+
+// RunDue places the orders of all deliveries whose order time has come.
+func (s *Scheduler) RunDue(now time.Time) {
+	for _, p := range s.storage.MealPlans.List() {
+		at, err := time.Parse(time.RFC3339, p.NextDeliveryAt)
+		if p.Status != models.MealPlanActive || err != nil || now.Before(at.Add(-s.lead)) {
+			continue
+		}
+
+		// Move the plan to its next delivery first so a slow order is not
+		// placed twice. The plan may have changed since it was listed.
+		claimed := false
+		p = s.storage.MealPlans.Update(p.Id, func(cur models.MealPlan, _ bool) models.MealPlan {
+			if cur.Status != models.MealPlanActive || cur.NextDeliveryAt != p.NextDeliveryAt {
+				return cur
+			}
+			claimed = true
+			s.Advance(&cur, at)
+			return cur
+		})
+		if !claimed {
+			continue
+		}
+
+		go s.Run(p, at, now)
+	}
+}
+
+// Run places the order of the plan's delivery at the given time and
+// records the attempt. Deliveries whose time has passed, e.g. while the
+// gateway was down, are recorded as failed instead of ordered late.
+func (s *Scheduler) Run(p models.MealPlan, at, now time.Time) models.MealPlanRun {
+	run := models.MealPlanRun{
+		Id:           uuid.NewString(),
+		PlanId:       p.Id,
+		DeliveryTime: at.Format(time.RFC3339),
+		Status:       models.MealPlanRunPlaced,
+		CreatedAt:    time.Now().Format(time.RFC3339),
+	}
+
+	var err error
+	if at.After(now) {
+		run.OrderId, err = s.order(p, at)
+	} else {
+		err = errors.New("delivery time passed before the order was placed")
+	}
+	if err != nil {
+		run.Status, run.Error = models.MealPlanRunFailed, err.Error()
+		s.logger.Error(errors.Wrapf(err, "error placing order of meal plan %s", p.Id).Error())
+	}
+	s.storage.MealPlanRuns.Set(run.Id, run)
+
+	p = s.storage.MealPlans.Update(p.Id, func(p models.MealPlan, _ bool) models.MealPlan {
+		p.Failures = 0
+		if err != nil {
+			p.Failures++
+		}
+		if p.Failures >= models.MaxMealPlanFailures && p.Status == models.MealPlanActive {
+			p.Status = models.MealPlanPaused
+		}
+		p.UpdatedAt = time.Now().Format(time.RFC3339)
+		return p
+	})
+	if err != nil {
+		s.notify(p, run)
+	}
+	return run
+}
+
+func (s *Scheduler) order(p models.MealPlan, at time.Time) (string, error) {
+	d, ok := Delivery(p, at.In(s.zone(p.KitchenId)))
+	if !ok {
+		return "", errors.New("plan has no delivery at this time anymore")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), placeTimeout)
+	defer cancel()
+
+	return s.place(ctx, models.NewOrder{
+		UserId:          p.UserId,
+		KitchenId:       p.KitchenId,
+		Items:           d.Items,
+		DeliveryAddress: p.DeliveryAddress,
+		DeliveryTime:    at.Format(time.RFC3339),
+		Location:        p.Location,
+		Sandbox:         p.Sandbox,
+	})
+}
+
+// Advance moves the plan to its first delivery after t, completing it
+// when there is none left. Delivery times are taken in t's location.
+func Advance(p *models.MealPlan, t time.Time) {
+	next, ok := NextDelivery(*p, t)
+	if !ok {
+		p.Status, p.NextDeliveryAt = models.MealPlanCompleted, ""
+		return
+	}
+	p.NextDeliveryAt = next.Format(time.RFC3339)
+}
+
+// NextDelivery returns the time of the plan's first delivery after t
+// within its start and end dates. Dates, weekdays and times of day are
+// taken in t's location.
+func NextDelivery(p models.MealPlan, t time.Time) (time.Time, bool) {
+	if start, err := time.ParseInLocation("2006-01-02", p.StartDate, t.Location()); err == nil && t.Before(start) {
+		t = start.Add(-time.Nanosecond)
+	}
+	end, err := time.ParseInLocation("2006-01-02", p.EndDate, t.Location())
+	hasEnd := err == nil
+
+	var next time.Time
+	for _, d := range p.Deliveries {
+		at, ok := weekly(d, t)
+		if ok && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+	if next.IsZero() || hasEnd && !next.Before(end.AddDate(0, 0, 1)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// Delivery finds the delivery of the plan that falls at t, taking times
+// of day in t's location.
+func Delivery(p models.MealPlan, t time.Time) (models.MealPlanDelivery, bool) {
+	for _, d := range p.Deliveries {
+		if at, ok := weekly(d, t.Add(-time.Nanosecond)); ok && at.Equal(t) {
+			return d, true
+		}
+	}
+	return models.MealPlanDelivery{}, false
+}
+
+// weekly returns the first time after t that the delivery falls on.
+func weekly(d models.MealPlanDelivery, t time.Time) (time.Time, bool) {
+	day, ok := Weekday(d.Weekday)
+	clock, err := time.Parse("15:04", d.Time)
+	if !ok || err != nil {
+		return time.Time{}, false
+	}
+
+	at := time.Date(t.Year(), t.Month(), t.Day(), clock.Hour(), clock.Minute(), 0, 0, t.Location())
+	at = at.AddDate(0, 0, (int(day)-int(at.Weekday())+7)%7)
+	if !at.After(t) {
+		at = at.AddDate(0, 0, 7)
+	}
+	return at, true
+}
+
+// Weekday parses a weekday name such as "monday".
+func Weekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(name, d.String()) {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,240 @@
+package moderation
+
+import (
+	"api-gateway/config"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	Allow  = "allow"
+	Reject = "reject"
+	Review = "review"
+)
+
+// Reasons tell which check flagged a text.
+const (
+	ReasonProfanity      = "profanity"
+	ReasonLinks          = "links"
+	ReasonContactDetails = "contact_details"
+	ReasonRepetition     = "repetition"
+	ReasonShouting       = "shouting"
+	ReasonExternal       = "external"
+	// ReasonAbuse holds what shadow-banned accounts write, whatever it says.
+	ReasonAbuse = "abuse"
+)
+
+const (
+	// maxRepeat is the longest run of one character a text may have.
+	maxRepeat = 5
+	// shoutingLetters is how many letters a text needs before its case
+	// is judged.
+	shoutingLetters = 20
+	shoutingShare   = 0.7
+
+	apiTimeout = 3 * time.Second
+)
+
+var (
+	linkPattern  = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:com|net|org|info|biz|io|me|ru|uz|xyz)\b`)
+	mailPattern  = regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)
+	phonePattern = regexp.MustCompile(`\+?\d[\d\s()-]{8,}\d`)
+)
+
+// leet undoes the character swaps used to sneak words past the lists.
+var leet = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+// Result is the outcome of a check. Rejected texts break a rule outright;
+// texts held for review only look suspicious.
+type Result struct {
+	Action string
+	Reason string
+	Detail string
+}
+
+// Moderator checks user-written text before it is published: against
+// profanity lists per language, for links and spam, and with an external
+// moderation API when one is configured.
+type Moderator struct {
+	words    map[string]string // word to language
+	maxLinks int
+	apiURL   string
+	apiKey   string
+	client   *http.Client
+	logger   *slog.Logger
+}
+
+func NewModerator(cfg *config.Config, logger *slog.Logger) *Moderator {
+	m := &Moderator{
+		words:    map[string]string{},
+		maxLinks: cfg.MODERATION_MAX_LINKS,
+		apiURL:   cfg.MODERATION_API_URL,
+		apiKey:   cfg.MODERATION_API_KEY,
+		client:   &http.Client{Timeout: apiTimeout},
+		logger:   logger,
+	}
+
+	if cfg.MODERATION_WORDS_PATH != "" {
+		if err := m.loadWords(cfg.MODERATION_WORDS_PATH); err != nil {
+			logger.Error(errors.Wrap(err, "reviews will not be checked for profanity").Error())
+		}
+	}
+	return m
+}
+
+func (m *Moderator) loadWords(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "error reading words file")
+	}
+
+	var lists map[string][]string
+	if err := json.Unmarshal(data, &lists); err != nil {
+		return errors.Wrap(err, "error decoding words file")
+	}
+	for lang, words := range lists {
+		for _, w := range words {
+			if w = normalize(w); w != "" {
+				m.words[w] = lang
+			}
+		}
+	}
+	return nil
+}
+
+// Check moderates text. The external API is asked last, and only about
+// texts that passed the local checks; if it can't be reached the text
+// is allowed, so an outage doesn't hold every review.
+func (m *Moderator) Check(ctx context.Context, text string) Result {
+	if strings.TrimSpace(text) == "" {
+		return Result{Action: Allow}
+	}
+
+	for _, w := range strings.FieldsFunc(text, isSeparator) {
+		if lang, ok := m.words[normalize(w)]; ok {
+			return Result{Action: Reject, Reason: ReasonProfanity,
+				Detail: fmt.Sprintf("contains profanity (%s)", lang)}
+		}
+	}
+
+	if n := len(linkPattern.FindAllString(text, -1)); n > m.maxLinks {
+		detail := "contains links"
+		if m.maxLinks > 0 {
+			detail = fmt.Sprintf("contains more than %d links", m.maxLinks)
+		}
+		return Result{Action: Reject, Reason: ReasonLinks, Detail: detail}
+	}
+
+	if mailPattern.MatchString(text) || phonePattern.MatchString(text) {
+		return Result{Action: Review, Reason: ReasonContactDetails, Detail: "contains contact details"}
+	}
+	if repeats(text) {
+		return Result{Action: Review, Reason: ReasonRepetition, Detail: "repeats characters"}
+	}
+	if shouting(text) {
+		return Result{Action: Review, Reason: ReasonShouting, Detail: "is mostly capital letters"}
+	}
+
+	if m.apiURL == "" {
+		return Result{Action: Allow}
+	}
+	res, err := m.ask(ctx, text)
+	if err != nil {
+		m.logger.Error(errors.Wrap(err, "error calling moderation API").Error())
+		return Result{Action: Allow}
+	}
+	return res
+}
+
+type apiResponse struct {
+	Flagged bool   `json:"flagged"`
+	Reason  string `json:"reason"`
+}
+
+func (m *Moderator) ask(ctx context.Context, text string) (Result, error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.apiURL, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, errors.Errorf("moderation API returned %s", resp.Status)
+	}
+
+	var out apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return Result{}, errors.Wrap(err, "error decoding moderation API response")
+	}
+	if !out.Flagged {
+		return Result{Action: Allow}, nil
+	}
+	detail := "was flagged by the moderation service"
+	if out.Reason != "" {
+		detail += ": " + out.Reason
+	}
+	return Result{Action: Review, Reason: ReasonExternal, Detail: detail}, nil
+}
+
+// isSeparator splits words, keeping the digits and symbols leet uses.
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '@' && r != '$'
+}
+
+func normalize(w string) string {
+	return leet.Replace(strings.ToLower(strings.TrimSpace(w)))
+}
+
+func repeats(text string) bool {
+	var prev rune
+	run := 0
+	for _, r := range text {
+		if r == prev && !unicode.IsSpace(r) {
+			run++
+			if run > maxRepeat {
+				return true
+			}
+			continue
+		}
+		prev, run = r, 1
+	}
+	return false
+}
+
+func shouting(text string) bool {
+	letters, upper := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= shoutingLetters && float64(upper) >= shoutingShare*float64(letters)
+}
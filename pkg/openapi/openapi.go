@@ -0,0 +1,311 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+var (
+	pathParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+	specParam = regexp.MustCompile(`{([^}]+)}`)
+	version   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	// handlerName finds the method of routes served by a Handler method,
+	// as gin names them: "api-gateway/api/handler.(*Handler).GetOrderByID-fm".
+	handlerName = regexp.MustCompile(`\.\(\*Handler\)\.([A-Za-z0-9_]+)-fm$`)
+)
+
+// errorSchema is the envelope every handler uses for failed requests.
+var errorSchema = map[string]any{
+	"type":     "object",
+	"required": []string{"error"},
+	"properties": map[string]any{
+		"error": map[string]any{"type": "string"},
+	},
+}
+
+// Generate builds an OpenAPI 3 document for the routes registered under
+// basePath. Operation details and type schemas are taken from the
+// Swagger 2 document produced from the handler annotations; routes that
+// are registered but not annotated still get a minimal operation.
+func Generate(swagger []byte, routes gin.RoutesInfo, basePath string) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(swagger, &doc); err != nil {
+		return nil, errors.Wrap(err, "invalid swagger document")
+	}
+
+	annotated, _ := doc["paths"].(map[string]any)
+	paths := make(map[string]any)
+	ids := make(map[string]int)
+
+	for _, r := range routes {
+		if !strings.HasPrefix(r.Path, basePath+"/") {
+			continue
+		}
+		path := pathParam.ReplaceAllString(strings.TrimPrefix(r.Path, basePath), "{$1}")
+		method := strings.ToLower(r.Method)
+
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+
+		var res map[string]any
+		if op, _ := lookup(annotated, path, method); op != nil {
+			res = convertOperation(op)
+		} else {
+			res = minimalOperation(path)
+		}
+		if id := operationID(r.Handler, ids); id != "" {
+			res["operationId"] = id
+		}
+		item[method] = res
+	}
+
+	components := map[string]any{
+		"schemas": map[string]any{"Error": errorSchema},
+	}
+	if defs, ok := doc["definitions"].(map[string]any); ok {
+		for name, def := range defs {
+			components["schemas"].(map[string]any)[name] = def
+		}
+	}
+	if sec, ok := doc["securityDefinitions"].(map[string]any); ok {
+		components["securitySchemes"] = sec
+	}
+
+	spec := map[string]any{
+		"openapi":    "3.0.3",
+		"info":       doc["info"],
+		"servers":    []any{map[string]any{"url": basePath}},
+		"paths":      paths,
+		"components": components,
+	}
+
+	out, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.ReplaceAll(string(out), "#/definitions/", "#/components/schemas/")), nil
+}
+
+// operationID names the operation of a route after its handler method,
+// in lowerCamelCase, numbering handlers serving several routes. Routes
+// served otherwise get none.
+func operationID(handler string, ids map[string]int) string {
+	m := handlerName.FindStringSubmatch(handler)
+	if m == nil {
+		return ""
+	}
+	id := strings.ToLower(m[1][:1]) + m[1][1:]
+	ids[id]++
+	if n := ids[id]; n > 1 {
+		id += strconv.Itoa(n)
+	}
+	return id
+}
+
+func lookup(paths map[string]any, path, method string) (map[string]any, bool) {
+	item, ok := paths[path].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	op, ok := item[method].(map[string]any)
+	return op, ok
+}
+
+func convertOperation(op map[string]any) map[string]any {
+	res := make(map[string]any)
+	for _, key := range []string{"summary", "description", "tags", "security"} {
+		if v, ok := op[key]; ok {
+			res[key] = v
+		}
+	}
+
+	var params []any
+	list, _ := op["parameters"].([]any)
+	for _, p := range list {
+		param, _ := p.(map[string]any)
+		if param["in"] == "body" {
+			res["requestBody"] = map[string]any{
+				"description": param["description"],
+				"required":    param["required"],
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": param["schema"]},
+				},
+			}
+			continue
+		}
+
+		schema := make(map[string]any)
+		for _, key := range []string{"type", "format", "items", "enum", "default"} {
+			if v, ok := param[key]; ok {
+				schema[key] = v
+			}
+		}
+		params = append(params, map[string]any{
+			"name":        param["name"],
+			"in":          param["in"],
+			"description": param["description"],
+			"required":    param["required"] == true,
+			"schema":      schema,
+		})
+	}
+	if len(params) > 0 {
+		res["parameters"] = params
+	}
+
+	responses := make(map[string]any)
+	annotated, _ := op["responses"].(map[string]any)
+	for code, r := range annotated {
+		resp, _ := r.(map[string]any)
+		schema := resp["schema"]
+		if code >= "400" {
+			schema = map[string]any{"$ref": "#/components/schemas/Error"}
+		}
+
+		out := map[string]any{"description": resp["description"]}
+		if schema != nil {
+			out["content"] = map[string]any{
+				"application/json": map[string]any{"schema": schema},
+			}
+		}
+		responses[code] = out
+	}
+	responses["401"] = errorResponse("Missing or invalid access token")
+	res["responses"] = responses
+
+	return res
+}
+
+func minimalOperation(path string) map[string]any {
+	var params []any
+	for _, m := range specParam.FindAllStringSubmatch(path, -1) {
+		params = append(params, map[string]any{
+			"name":     m[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+
+	op := map[string]any{
+		"responses": map[string]any{
+			"200":     map[string]any{"description": "OK"},
+			"default": errorResponse("Error"),
+		},
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	return op
+}
+
+func errorResponse(description string) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/Error"},
+			},
+		},
+	}
+}
+
+// Snapshots keeps a copy of the spec of every released API version so
+// clients pinned to an older version can still fetch its contract.
+type Snapshots struct {
+	dir string
+}
+
+func NewSnapshots(dir string) *Snapshots {
+	return &Snapshots{dir: dir}
+}
+
+// Save writes the spec for the version. A snapshot of the same version
+// is replaced when the spec changed without the version being bumped,
+// so it always holds the latest contract served under that version.
+func (s *Snapshots) Save(ver string, spec []byte) error {
+	if !version.MatchString(ver) {
+		return errors.Errorf("invalid version %q", ver)
+	}
+	if old, err := os.ReadFile(s.file(ver)); err == nil && bytes.Equal(old, spec) {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	// The snapshot is written aside and renamed over the old one, so it is
+	// never served half written.
+	f, err := os.CreateTemp(s.dir, ver+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(spec); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.file(ver))
+}
+
+func (s *Snapshots) Versions() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(files))
+	for _, f := range files {
+		versions = append(versions, strings.TrimSuffix(filepath.Base(f), ".json"))
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+func (s *Snapshots) Handler(c *gin.Context) {
+	ver := c.Param("version")
+	if !version.MatchString(ver) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	spec, err := os.ReadFile(s.file(ver))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "unknown version"})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json", spec)
+}
+
+func (s *Snapshots) VersionsHandler(c *gin.Context) {
+	versions, err := s.Versions()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+func (s *Snapshots) file(ver string) string {
+	return filepath.Join(s.dir, ver+".json")
+}
@@ -0,0 +1,33 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotsSave(t *testing.T) {
+	dir := t.TempDir()
+	s := NewSnapshots(dir)
+
+	for _, spec := range []string{`{"paths":{}}`, `{"paths":{"/orders":{}}}`} {
+		if err := s.Save("1.0", []byte(spec)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, "1.0.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != spec {
+			t.Errorf("snapshot = %s, want %s", got, spec)
+		}
+	}
+
+	versions, err := s.Versions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0] != "1.0" {
+		t.Errorf("Versions() = %v, want [1.0]", versions)
+	}
+}
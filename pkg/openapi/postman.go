@@ -0,0 +1,280 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// postmanSchema is the format of the collections Postman and Insomnia
+// import.
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// maxExampleDepth bounds how deep example bodies of nested schemas go.
+const maxExampleDepth = 6
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Auth     postmanAuth       `json:"auth"`
+	Variable []postmanVariable `json:"variable"`
+	Item     []postmanFolder   `json:"item"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	APIKey []postmanVariable `json:"apikey"`
+}
+
+type postmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type postmanFolder struct {
+	Name string        `json:"name"`
+	Item []postmanItem `json:"item"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Description string            `json:"description,omitempty"`
+	Header      []postmanVariable `json:"header"`
+	Url         postmanUrl        `json:"url"`
+	Body        *postmanBody      `json:"body,omitempty"`
+}
+
+type postmanUrl struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanVariable `json:"query,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string         `json:"mode"`
+	Raw     string         `json:"raw"`
+	Options map[string]any `json:"options"`
+}
+
+// Postman builds a Postman collection, which Insomnia imports too, from
+// the OpenAPI 3 spec Generate builds: a folder per tag with a request per
+// operation, its parameters and an example body made up from its schema.
+// Requests go to the {{baseUrl}} variable, baseURL by default, signed
+// with the {{token}} one.
+func Postman(spec []byte, baseURL string) ([]byte, error) {
+	var doc struct {
+		Info struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Version     string `json:"version"`
+		} `json:"info"`
+		Servers []struct {
+			Url string `json:"url"`
+		} `json:"servers"`
+		Paths      map[string]map[string]postmanOperation `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, errors.Wrap(err, "invalid openapi spec")
+	}
+
+	basePath := ""
+	if len(doc.Servers) > 0 {
+		basePath = doc.Servers[0].Url
+	}
+
+	folders := make(map[string][]postmanItem)
+	for _, path := range sortedKeys(doc.Paths) {
+		for _, method := range sortedKeys(doc.Paths[path]) {
+			op := doc.Paths[path][method]
+			tag := "other"
+			if len(op.Tags) > 0 {
+				tag = op.Tags[0]
+			}
+			folders[tag] = append(folders[tag],
+				postmanRequestItem(basePath+path, method, op, doc.Components.Schemas))
+		}
+	}
+
+	collection := postmanCollection{
+		Info: postmanInfo{
+			Name:        doc.Info.Title + " " + doc.Info.Version,
+			Description: doc.Info.Description,
+			Schema:      postmanSchema,
+		},
+		Auth: postmanAuth{Type: "apikey", APIKey: []postmanVariable{
+			{Key: "key", Value: "Authorization", Type: "string"},
+			{Key: "value", Value: "{{token}}", Type: "string"},
+			{Key: "in", Value: "header", Type: "string"},
+		}},
+		Variable: []postmanVariable{
+			{Key: "baseUrl", Value: baseURL, Type: "string"},
+			{Key: "token", Value: "", Type: "string", Description: "Access token"},
+		},
+		Item: make([]postmanFolder, 0, len(folders)),
+	}
+	for _, tag := range sortedKeys(folders) {
+		collection.Item = append(collection.Item, postmanFolder{Name: tag, Item: folders[tag]})
+	}
+	return json.MarshalIndent(collection, "", "  ")
+}
+
+type postmanOperation struct {
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Parameters  []struct {
+		Name        string         `json:"name"`
+		In          string         `json:"in"`
+		Description string         `json:"description"`
+		Required    bool           `json:"required"`
+		Schema      map[string]any `json:"schema"`
+	} `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema map[string]any `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+func postmanRequestItem(path, method string, op postmanOperation, schemas map[string]map[string]any) postmanItem {
+	// Postman marks path variables with a colon, as gin does.
+	path = specParam.ReplaceAllString(path, ":$1")
+	req := postmanRequest{
+		Method:      strings.ToUpper(method),
+		Description: op.Description,
+		Header:      []postmanVariable{},
+		Url: postmanUrl{
+			Host: []string{"{{baseUrl}}"},
+			Path: strings.Split(strings.TrimPrefix(path, "/"), "/"),
+		},
+	}
+
+	var query []string
+	for _, p := range op.Parameters {
+		value := ""
+		if ex := example(p.Schema, schemas, 0); ex != nil {
+			value = fmt.Sprint(ex)
+		}
+		v := postmanVariable{Key: p.Name, Value: value, Description: p.Description, Disabled: !p.Required}
+		switch p.In {
+		case "path":
+			v.Value, v.Disabled = "", false
+			req.Url.Variable = append(req.Url.Variable, v)
+		case "query":
+			req.Url.Query = append(req.Url.Query, v)
+			if p.Required {
+				query = append(query, p.Name+"="+value)
+			}
+		case "header":
+			req.Header = append(req.Header, v)
+		}
+	}
+	req.Url.Raw = "{{baseUrl}}" + path
+	if len(query) > 0 {
+		req.Url.Raw += "?" + strings.Join(query, "&")
+	}
+
+	if op.RequestBody != nil {
+		if c, ok := op.RequestBody.Content["application/json"]; ok {
+			raw, _ := json.MarshalIndent(example(c.Schema, schemas, 0), "", "  ")
+			req.Header = append(req.Header, postmanVariable{Key: "Content-Type", Value: "application/json"})
+			req.Body = &postmanBody{
+				Mode:    "raw",
+				Raw:     string(raw),
+				Options: map[string]any{"raw": map[string]any{"language": "json"}},
+			}
+		}
+	}
+
+	name := op.Summary
+	if name == "" {
+		name = req.Method + " " + path
+	}
+	return postmanItem{Name: name, Request: req}
+}
+
+// example makes up a value of schema, from its example or default when it
+// has one.
+func example(schema map[string]any, schemas map[string]map[string]any, depth int) any {
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+	for _, key := range []string{"example", "default"} {
+		if v, ok := schema[key]; ok {
+			return v
+		}
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		return example(schemas[ref[strings.LastIndex(ref, "/")+1:]], schemas, depth+1)
+	}
+	if all, ok := schema["allOf"].([]any); ok {
+		res := make(map[string]any)
+		for _, s := range all {
+			m, _ := s.(map[string]any)
+			if ex, ok := example(m, schemas, depth+1).(map[string]any); ok {
+				for k, v := range ex {
+					res[k] = v
+				}
+			}
+		}
+		return res
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	switch schema["type"] {
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			return "2024-01-01T12:00:00Z"
+		case "date":
+			return "2024-01-01"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		}
+		return "string"
+	case "integer", "number":
+		// Not 0, which pages and limits refuse.
+		return 1
+	case "boolean":
+		return false
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		if ex := example(items, schemas, depth+1); ex != nil {
+			return []any{ex}
+		}
+		return []any{}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	res := make(map[string]any, len(props))
+	for name, p := range props {
+		m, _ := p.(map[string]any)
+		res[name] = example(m, schemas, depth+1)
+	}
+	if extra, ok := schema["additionalProperties"].(map[string]any); ok && len(props) == 0 {
+		res["key"] = example(extra, schemas, depth+1)
+	}
+	return res
+}
@@ -0,0 +1,419 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	nonIdent = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	tsIdent  = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	// tsGlobals are names the schemas must not shadow, such as the Error
+	// schema of failed requests.
+	tsGlobals = map[string]bool{"Error": true, "Object": true, "Date": true, "Record": true, "Promise": true, "Blob": true}
+)
+
+// tsRuntime is the part of the client that does not depend on the spec:
+// signing requests, unwrapping the response envelope and errors.
+const tsRuntime = `export interface RetryHint {
+  reason: string;
+  after_seconds: number;
+  backoff: "exponential" | "fixed";
+  max_seconds: number;
+  jitter: boolean;
+}
+
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly body: Record<string, unknown>,
+    readonly requestId: string | null,
+  ) {
+    super(typeof body.error === "string" ? body.error : "HTTP " + status);
+  }
+
+  /** How the gateway asked to be retried, when it answered 429 or 503. */
+  get retry(): RetryHint | undefined {
+    return this.body.retry as RetryHint | undefined;
+  }
+}
+
+export interface ClientOptions {
+  /** The gateway's address, such as "https://api.example.com". */
+  baseUrl: string;
+  /** The access token, or a function giving a fresh one for each request. */
+  token?: string | (() => string | Promise<string>);
+  fetch?: typeof fetch;
+}
+
+type Query = Record<string, string | number | boolean | undefined>;
+
+export class LocalEatsClient {
+  private readonly baseUrl: string;
+  private readonly fetch: typeof fetch;
+
+  constructor(private readonly options: ClientOptions) {
+    this.baseUrl = options.baseUrl.replace(/\/$/, "") + BASE_PATH;
+    this.fetch = options.fetch ?? fetch.bind(globalThis);
+  }
+
+  private async request<T>(
+    method: string,
+    path: string,
+    query: Query,
+    headers: Query,
+    body?: unknown,
+    form?: Record<string, string | Blob | undefined>,
+  ): Promise<T> {
+    const url = new URL(this.baseUrl + path);
+    for (const [k, v] of Object.entries(query)) {
+      if (v !== undefined) url.searchParams.set(k, String(v));
+    }
+
+    const init: RequestInit = { method, headers: { Accept: "application/json" } };
+    const h = init.headers as Record<string, string>;
+    for (const [k, v] of Object.entries(headers)) {
+      if (v !== undefined) h[k] = String(v);
+    }
+    const token = typeof this.options.token === "function" ? await this.options.token() : this.options.token;
+    if (token) h.Authorization = token;
+    if (form) {
+      const data = new FormData();
+      for (const [k, v] of Object.entries(form)) {
+        if (v !== undefined) data.append(k, v);
+      }
+      init.body = data;
+    } else if (body !== undefined) {
+      h["Content-Type"] = "application/json";
+      init.body = JSON.stringify(body);
+    }
+
+    const res = await this.fetch(url, init);
+    const text = await res.text();
+    let data: any = undefined;
+    try {
+      data = text ? JSON.parse(text) : undefined;
+    } catch {
+      data = text;
+    }
+    if (!res.ok) {
+      const err = typeof data === "object" && data !== null ? data : { error: text };
+      throw new ApiError(res.status, err, res.headers.get("X-Request-ID"));
+    }
+    // Unwraps the {"data", "meta"} envelope.
+    if (data && typeof data === "object" && "data" in data && "meta" in data) {
+      return data.data as T;
+    }
+    return data as T;
+  }
+`
+
+// TypeScript generates a typed TypeScript client of the gateway from its
+// OpenAPI 3 spec, as Generate builds it: an interface for every schema and
+// a LocalEatsClient method for every operation with an operationId. The
+// methods take the path, query and header parameters in one object, and
+// the body, if any, after it.
+func TypeScript(spec []byte) ([]byte, error) {
+	var doc struct {
+		Info struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Servers []struct {
+			Url string `json:"url"`
+		} `json:"servers"`
+		Paths      map[string]map[string]tsOperation `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, errors.Wrap(err, "invalid openapi spec")
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated from the OpenAPI spec of %s %s. DO NOT EDIT.\n\n",
+		doc.Info.Title, doc.Info.Version)
+	basePath := ""
+	if len(doc.Servers) > 0 {
+		basePath = doc.Servers[0].Url
+	}
+	fmt.Fprintf(&b, "export const API_VERSION = %q;\n", doc.Info.Version)
+	fmt.Fprintf(&b, "const BASE_PATH = %q;\n\n", basePath)
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		schema := doc.Components.Schemas[name]
+		if desc, ok := schema["description"].(string); ok && desc != "" {
+			writeDoc(&b, "", desc)
+		}
+		if _, ok := schema["properties"]; ok || schema["type"] == "object" {
+			fmt.Fprintf(&b, "export interface %s %s\n\n", typeName(name), tsType(schema, ""))
+			continue
+		}
+		fmt.Fprintf(&b, "export type %s = %s;\n\n", typeName(name), tsType(schema, ""))
+	}
+
+	b.WriteString(tsRuntime)
+	for _, path := range sortedKeys(doc.Paths) {
+		for _, method := range sortedKeys(doc.Paths[path]) {
+			op := doc.Paths[path][method]
+			if op.OperationId == "" {
+				continue
+			}
+			b.WriteString("\n")
+			writeOperation(&b, path, method, op)
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes(), nil
+}
+
+type tsOperation struct {
+	OperationId string `json:"operationId"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	Parameters  []struct {
+		Name        string         `json:"name"`
+		In          string         `json:"in"`
+		Description string         `json:"description"`
+		Required    bool           `json:"required"`
+		Schema      map[string]any `json:"schema"`
+	} `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema map[string]any `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema map[string]any `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+func writeOperation(b *bytes.Buffer, path, method string, op tsOperation) {
+	writeDoc(b, "  ", strings.TrimSpace(op.Summary+"\n\n"+op.Description))
+
+	var fields, query, headers, form []string
+	required := false
+	for _, p := range op.Parameters {
+		key := tsKey(p.Name)
+		opt := "?"
+		if p.Required || p.In == "path" {
+			opt, required = "", true
+		}
+		typ := tsType(p.Schema, "  ")
+		if p.Schema["type"] == "file" {
+			typ = "Blob"
+		}
+		fields = append(fields, fmt.Sprintf("%s%s: %s", key, opt, typ))
+
+		value := "params" + tsAccess(p.Name)
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", "${encodeURIComponent(String("+value+"))}")
+		case "query":
+			query = append(query, fmt.Sprintf("%s: %s", key, value))
+		case "header":
+			headers = append(headers, fmt.Sprintf("%s: %s", key, value))
+		case "formData":
+			if typ != "Blob" {
+				value = fmt.Sprintf("%s === undefined ? undefined : String(%s)", value, value)
+			}
+			form = append(form, fmt.Sprintf("%s: %s", key, value))
+		}
+	}
+
+	args := []string{}
+	if len(fields) > 0 {
+		arg := "params: { " + strings.Join(fields, "; ") + " }"
+		if !required {
+			arg += " = {}"
+		}
+		args = append(args, arg)
+	}
+	body := "undefined"
+	if op.RequestBody != nil {
+		if c, ok := op.RequestBody.Content["application/json"]; ok {
+			args = append(args, "body: "+tsType(c.Schema, "  "))
+			body = "body"
+		}
+	}
+	formArg := ""
+	if len(form) > 0 {
+		formArg = ", " + tsObject(form)
+	}
+
+	fmt.Fprintf(b, "  %s(%s): Promise<%s> {\n", op.OperationId, strings.Join(args, ", "), responseType(op))
+	fmt.Fprintf(b, "    return this.request(%q, `%s`, %s, %s, %s%s);\n",
+		strings.ToUpper(method), path, tsObject(query), tsObject(headers), body, formArg)
+	b.WriteString("  }\n")
+}
+
+// responseType is the type of the successful responses with a body, such
+// as an order or the fraud hold it is placed under.
+func responseType(op tsOperation) string {
+	var types []string
+	for _, code := range sortedKeys(op.Responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if c, ok := op.Responses[code].Content["application/json"]; ok && c.Schema != nil {
+			if typ := tsType(c.Schema, "  "); !slices.Contains(types, typ) {
+				types = append(types, typ)
+			}
+		}
+	}
+	if len(types) == 0 {
+		return "unknown"
+	}
+	return strings.Join(types, " | ")
+}
+
+func tsObject(fields []string) string {
+	if len(fields) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(fields, ", ") + " }"
+}
+
+// tsType is the TypeScript type of a JSON schema, indented for nesting in
+// a block at indent.
+func tsType(schema map[string]any, indent string) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return typeName(ref[strings.LastIndex(ref, "/")+1:])
+	}
+	if all, ok := schema["allOf"].([]any); ok {
+		var types []string
+		for _, s := range all {
+			if m, ok := s.(map[string]any); ok {
+				types = append(types, tsType(m, indent))
+			}
+		}
+		return strings.Join(types, " & ")
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		var values []string
+		for _, v := range enum {
+			data, _ := json.Marshal(v)
+			values = append(values, string(data))
+		}
+		return strings.Join(values, " | ")
+	}
+
+	switch schema["type"] {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		typ := tsType(items, indent)
+		if strings.ContainsAny(typ, " |&") {
+			typ = "(" + typ + ")"
+		}
+		return typ + "[]"
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	if len(props) == 0 {
+		if extra, ok := schema["additionalProperties"].(map[string]any); ok {
+			return "Record<string, " + tsType(extra, indent) + ">"
+		}
+		if schema["type"] == "object" {
+			return "Record<string, unknown>"
+		}
+		return "unknown"
+	}
+
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]any); ok {
+		for _, r := range list {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range sortedKeys(props) {
+		prop, _ := props[name].(map[string]any)
+		if desc, ok := prop["description"].(string); ok && desc != "" {
+			var doc bytes.Buffer
+			writeDoc(&doc, indent+"  ", desc)
+			b.Write(doc.Bytes())
+		}
+		opt := "?"
+		if required[name] {
+			opt = ""
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, tsKey(name), opt, tsType(prop, indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// typeName makes a schema name such as "kitchen.Kitchens" a TypeScript
+// type name: "KitchenKitchens".
+func typeName(name string) string {
+	var b strings.Builder
+	for _, part := range nonIdent.Split(name, -1) {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	if tsGlobals[b.String()] {
+		return b.String() + "Body"
+	}
+	return b.String()
+}
+
+func tsKey(name string) string {
+	if tsIdent.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func tsAccess(name string) string {
+	if tsIdent.MatchString(name) {
+		return "." + name
+	}
+	return "[" + strconv.Quote(name) + "]"
+}
+
+func writeDoc(b *bytes.Buffer, indent, text string) {
+	if text == "" {
+		return
+	}
+	text = strings.ReplaceAll(text, "*/", "*\\/")
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, text)
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s *%s\n", indent, strings.TrimRight(" "+line, " "))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
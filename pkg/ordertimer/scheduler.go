@@ -0,0 +1,92 @@
+package ordertimer
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	respondTimeout = 10 * time.Second
+	// maxAttempts bounds the retries of an order whose status could not
+	// be changed.
+	maxAttempts = 5
+)
+
+// Responder accepts or rejects a pending order for its kitchen.
+type Responder func(ctx context.Context, p models.PendingOrder) error
+
+// Scheduler runs the order timers of kitchens: orders the kitchen leaves
+// without a response are accepted or rejected once their timer is due.
+type Scheduler struct {
+	store   *storage.Store[models.PendingOrder]
+	respond Responder
+	logger  *slog.Logger
+}
+
+// NewScheduler starts checking for due timers every interval.
+func NewScheduler(store *storage.Store[models.PendingOrder], respond Responder,
+	interval time.Duration, logger *slog.Logger) *Scheduler {
+	s := &Scheduler{store: store, respond: respond, logger: logger}
+
+	go func() {
+		for now := range time.Tick(interval) {
+			s.RunDue(now)
+		}
+	}()
+
+	return s
+}
+
+// Start runs the kitchen's timer on a newly placed order, if it has one.
+func (s *Scheduler) Start(t models.OrderTimer, orderID, status string, now time.Time) {
+	if t.Action == "" {
+		return
+	}
+	s.store.Set(orderID, models.PendingOrder{
+		OrderId: orderID,
+		Status:  status,
+		Action:  t.Action,
+		DueAt:   now.Add(time.Duration(t.AfterMinutes) * time.Minute).Format(time.RFC3339),
+	})
+}
+
+// Stop drops the timer of an order the kitchen responded to.
+func (s *Scheduler) Stop(orderID string) {
+	s.store.Delete(orderID)
+}
+
+// RunDue responds to every order whose timer is due.
+func (s *Scheduler) RunDue(now time.Time) {
+	for _, p := range s.store.List() {
+		at, err := time.Parse(time.RFC3339, p.DueAt)
+		if err == nil && now.Before(at) {
+			continue
+		}
+		// Taking the timer first keeps a slow response from being sent
+		// twice.
+		s.store.Delete(p.OrderId)
+		go s.run(p)
+	}
+}
+
+func (s *Scheduler) run(p models.PendingOrder) {
+	ctx, cancel := context.WithTimeout(context.Background(), respondTimeout)
+	defer cancel()
+
+	err := s.respond(ctx, p)
+	if err == nil {
+		return
+	}
+	s.logger.Error(errors.Wrapf(err, "error running order timer of order %s", p.OrderId).Error())
+
+	// Tried again on the next check. The responder leaves alone orders
+	// the kitchen answered meanwhile.
+	if p.Attempts++; p.Attempts < maxAttempts {
+		s.store.Set(p.OrderId, p)
+	}
+}
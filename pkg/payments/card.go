@@ -0,0 +1,110 @@
+package payments
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/redact"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// tokenTTL bounds how long a vaulted card can wait for its payment.
+const tokenTTL = 15 * time.Minute
+
+var (
+	ErrInvalidCard  = errors.New("invalid card details")
+	ErrInvalidToken = errors.New("payment token is unknown, used or expired")
+)
+
+// Tokenizer is implemented by providers that can exchange card details
+// for a token, so that raw card numbers are sent only once.
+type Tokenizer interface {
+	Tokenize(ctx context.Context, card models.NewCardToken) (models.CardToken, error)
+}
+
+// validateCard checks card details without ever quoting them, since
+// error messages end up in responses and logs.
+func validateCard(card models.NewCardToken) error {
+	if n := len(card.CardNumber); n < 13 || n > 19 || !redact.Luhn(card.CardNumber) {
+		return errors.Wrap(ErrInvalidCard, "invalid card number")
+	}
+
+	exp, err := time.Parse("01/06", card.ExpiryDate)
+	if err != nil {
+		return errors.Wrap(ErrInvalidCard, "invalid expiry date, MM/YY expected")
+	}
+	// Cards are valid through the last day of the expiry month.
+	if !time.Now().Before(exp.AddDate(0, 1, 0)) {
+		return errors.Wrap(ErrInvalidCard, "card has expired")
+	}
+
+	if n := len(card.Cvv); n < 3 || n > 4 || !isDigits(card.Cvv) {
+		return errors.Wrap(ErrInvalidCard, "invalid CVV")
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// vault keeps card details in memory for single-use tokens of the
+// internal provider. Cards are never written anywhere else.
+type vault struct {
+	mu    sync.Mutex
+	cards map[string]vaulted
+}
+
+type vaulted struct {
+	card    models.NewCardToken
+	expires time.Time
+}
+
+func newVault() *vault {
+	return &vault{cards: map[string]vaulted{}}
+}
+
+func (v *vault) put(card models.NewCardToken) (string, time.Time, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	token := "tok_" + hex.EncodeToString(b)
+	expires := time.Now().Add(tokenTTL)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.sweep()
+	v.cards[token] = vaulted{card: card, expires: expires}
+	return token, expires, nil
+}
+
+// take returns the card of the token and forgets it.
+func (v *vault) take(token string) (models.NewCardToken, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.sweep()
+	c, ok := v.cards[token]
+	delete(v.cards, token)
+	return c.card, ok
+}
+
+func (v *vault) sweep() {
+	now := time.Now()
+	for token, c := range v.cards {
+		if now.After(c.expires) {
+			delete(v.cards, token)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package payments
+
+import (
+	"api-gateway/genproto/payment"
+	"api-gateway/models"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+type fakePaymentClient struct {
+	payment.PaymentClient
+	got *payment.NewPayment
+}
+
+func (f *fakePaymentClient) MakePayment(ctx context.Context, in *payment.NewPayment, _ ...grpc.CallOption) (*payment.NewPaymentResp, error) {
+	f.got = in
+	return &payment.NewPaymentResp{Id: "p1", OrderId: in.OrderId, Status: "completed"}, nil
+}
+
+func expiry() string {
+	return time.Now().AddDate(1, 0, 0).Format("01/06")
+}
+
+func TestInternalTokenize(t *testing.T) {
+	client := &fakePaymentClient{}
+	p := NewInternal(client)
+	ctx := context.Background()
+
+	card := models.NewCardToken{CardNumber: "4111111111111111", ExpiryDate: expiry(), Cvv: "123"}
+	token, err := p.Tokenize(ctx, card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token.Token, "tok_") || token.Last4 != "1111" {
+		t.Fatalf("unexpected token %+v", token)
+	}
+
+	req := &models.NewPayment{OrderId: "o1", PaymentToken: token.Token}
+	if err := p.Validate(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Charge(ctx, &models.Payment{}, req); err != nil {
+		t.Fatal(err)
+	}
+	if client.got.CardNumber != card.CardNumber || client.got.Cvv != card.Cvv {
+		t.Fatalf("card was not sent to the payment service: %+v", client.got)
+	}
+
+	if err := p.Charge(ctx, &models.Payment{}, req); errors.Cause(err) != ErrInvalidToken {
+		t.Fatalf("token was accepted twice: %v", err)
+	}
+}
+
+func TestTokenizeErrorsHidePAN(t *testing.T) {
+	p := NewInternal(&fakePaymentClient{})
+
+	cards := []models.NewCardToken{
+		{CardNumber: "4111111111111112", ExpiryDate: expiry(), Cvv: "123"},
+		{CardNumber: "4111111111111111", ExpiryDate: "13/99", Cvv: "123"},
+		{CardNumber: "4111111111111111", ExpiryDate: "01/20", Cvv: "123"},
+		{CardNumber: "4111111111111111", ExpiryDate: expiry(), Cvv: "12a"},
+	}
+	for _, card := range cards {
+		_, err := p.Tokenize(context.Background(), card)
+		if errors.Cause(err) != ErrInvalidCard {
+			t.Fatalf("card %+v: want ErrInvalidCard, got %v", card, err)
+		}
+		if strings.Contains(err.Error(), card.CardNumber) || strings.Contains(err.Error(), card.Cvv) {
+			t.Errorf("error quotes the card: %v", err)
+		}
+	}
+}
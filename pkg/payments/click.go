@@ -0,0 +1,222 @@
+package payments
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Error codes of the Click Prepare and Complete calls.
+const (
+	clickErrSignature   = -1
+	clickErrAmount      = -2
+	clickErrAction      = -3
+	clickErrPaid        = -4
+	clickErrNotFound    = -5
+	clickErrTransaction = -6
+	clickErrRequest     = -8
+	clickErrCanceled    = -9
+)
+
+const (
+	clickActionPrepare  = 0
+	clickActionComplete = 1
+)
+
+// clickPayURL is the Click hosted checkout page.
+const clickPayURL = "https://my.click.uz/services/pay"
+
+// Click takes payments on the Click hosted checkout. Click reports them
+// with signed Prepare and Complete calls to the webhook.
+type Click struct {
+	apiURL         string
+	serviceID      string
+	merchantID     string
+	merchantUserID string
+	secretKey      string
+	client         *http.Client
+}
+
+func NewClick(apiURL, serviceID, merchantID, merchantUserID, secretKey string) *Click {
+	return &Click{
+		apiURL:         strings.TrimSuffix(apiURL, "/"),
+		serviceID:      serviceID,
+		merchantID:     merchantID,
+		merchantUserID: merchantUserID,
+		secretKey:      secretKey,
+		client:         &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Click) Name() string {
+	return models.ProviderClick
+}
+
+func (c *Click) Validate(req *models.NewPayment) error {
+	if err := noCard(req); err != nil {
+		return err
+	}
+	if req.PaymentToken != "" {
+		return errors.New("payment tokens are not supported by this provider")
+	}
+	return validReturnURL(req)
+}
+
+// Charge builds the checkout link; the payment stays pending until Click
+// completes it.
+func (c *Click) Charge(ctx context.Context, p *models.Payment, req *models.NewPayment) error {
+	q := url.Values{
+		"service_id":        {c.serviceID},
+		"merchant_id":       {c.merchantID},
+		"amount":            {clickAmount(p.Amount)},
+		"transaction_param": {p.Id},
+	}
+	if req.ReturnUrl != "" {
+		q.Set("return_url", req.ReturnUrl)
+	}
+
+	p.Status = models.PaymentPending
+	p.RedirectUrl = clickPayURL + "?" + q.Encode()
+	return nil
+}
+
+// Refund reverses the payment. Click cannot return part of a payment.
+func (c *Click) Refund(ctx context.Context, p models.Payment, id string, amount float32) error {
+	if minor(amount) != minor(p.Amount) {
+		return ErrPartialRefund
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete,
+		fmt.Sprintf("%s/payment/reversal/%s/%s", c.apiURL, c.serviceID, p.TransactionId), nil)
+	if err != nil {
+		return err
+	}
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	digest := sha1.Sum([]byte(ts + c.secretKey))
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Auth", c.merchantUserID+":"+hex.EncodeToString(digest[:])+":"+ts)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error reversing click payment")
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		ErrorCode int    `json:"error_code"`
+		ErrorNote string `json:"error_note"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return errors.Wrap(err, "error decoding click response")
+	}
+	if res.ErrorCode != 0 {
+		return errors.Errorf("click refused the refund: %s", res.ErrorNote)
+	}
+	return nil
+}
+
+// Webhook answers a Prepare or Complete call. Click expects every
+// answer, errors included, as JSON with status 200.
+func (c *Click) Webhook(r *http.Request, store *storage.Store[models.Payment]) (Notification, any, error) {
+	if err := r.ParseForm(); err != nil {
+		return Notification{}, nil, errors.Wrap(err, "invalid click request")
+	}
+	f := r.PostForm
+
+	paymentID := f.Get("merchant_trans_id")
+	prepareID := clickPrepareID(paymentID)
+	reply := map[string]any{
+		"click_trans_id":    f.Get("click_trans_id"),
+		"merchant_trans_id": paymentID,
+	}
+	fail := func(code int, note string) (Notification, any, error) {
+		reply["error"] = code
+		reply["error_note"] = note
+		return Notification{}, reply, nil
+	}
+
+	action, err := strconv.Atoi(f.Get("action"))
+	if err != nil || (action != clickActionPrepare && action != clickActionComplete) {
+		return fail(clickErrAction, "Action not found")
+	}
+
+	sign := f.Get("click_trans_id") + f.Get("service_id") + c.secretKey + paymentID
+	if action == clickActionComplete {
+		sign += f.Get("merchant_prepare_id")
+	}
+	sign += f.Get("amount") + f.Get("action") + f.Get("sign_time")
+	sum := md5.Sum([]byte(sign))
+	if c.secretKey == "" || subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(f.Get("sign_string"))) != 1 {
+		return fail(clickErrSignature, "SIGN CHECK FAILED!")
+	}
+
+	p, ok := store.Get(paymentID)
+	if !ok || p.Provider != models.ProviderClick {
+		return fail(clickErrNotFound, "Payment not found")
+	}
+
+	amount, err := strconv.ParseFloat(f.Get("amount"), 64)
+	if err != nil {
+		return fail(clickErrRequest, "Error in request from click")
+	}
+	if math.Round(amount*100) != float64(minor(p.Amount)) {
+		return fail(clickErrAmount, "Incorrect parameter amount")
+	}
+
+	switch p.Status {
+	case models.PaymentSucceeded, models.PaymentRefunded:
+		return fail(clickErrPaid, "Already paid")
+	case models.PaymentCanceled, models.PaymentFailed:
+		return fail(clickErrCanceled, "Transaction cancelled")
+	}
+
+	reply["error"] = 0
+	reply["error_note"] = "Success"
+	n := Notification{PaymentId: p.Id, TransactionId: f.Get("click_paydoc_id"), At: time.Now()}
+
+	if action == clickActionPrepare {
+		reply["merchant_prepare_id"] = prepareID
+		n.Status = models.PaymentPending
+		return n, reply, nil
+	}
+
+	if f.Get("merchant_prepare_id") != strconv.FormatUint(uint64(prepareID), 10) {
+		return fail(clickErrTransaction, "Transaction does not exist")
+	}
+	// A negative error means the customer's payment failed on Click's side.
+	if code, _ := strconv.Atoi(f.Get("error")); code < 0 {
+		n.Status = models.PaymentCanceled
+		_, res, _ := fail(clickErrCanceled, "Transaction cancelled")
+		return n, res, nil
+	}
+
+	reply["merchant_confirm_id"] = prepareID
+	n.Status = models.PaymentSucceeded
+	return n, reply, nil
+}
+
+// clickPrepareID derives the numeric ID Click wants for a prepared
+// payment, so it need not be stored.
+func clickPrepareID(paymentID string) uint32 {
+	return crc32.ChecksumIEEE([]byte(paymentID))
+}
+
+func clickAmount(amount float32) string {
+	return strconv.FormatFloat(float64(minor(amount))/100, 'f', 2, 64)
+}
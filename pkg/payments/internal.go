@@ -0,0 +1,108 @@
+package payments
+
+import (
+	"api-gateway/genproto/payment"
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Internal charges cards through the Local Eats payment service. The
+// service cannot tokenize cards, so tokens are kept in a gateway vault
+// until the payment they are made for.
+type Internal struct {
+	client payment.PaymentClient
+	vault  *vault
+}
+
+func NewInternal(client payment.PaymentClient) *Internal {
+	return &Internal{client: client, vault: newVault()}
+}
+
+func (i *Internal) Name() string {
+	return models.ProviderInternal
+}
+
+func (i *Internal) Validate(req *models.NewPayment) error {
+	if req.CardNumber != "" && len(req.CardNumber) != 16 {
+		return errors.New("invalid card number")
+	}
+	if req.ExpiryDate != "" && len(req.ExpiryDate) != 5 {
+		return errors.New("invalid expiry date")
+	}
+	if req.Cvv != "" && len(req.Cvv) != 3 {
+		return errors.New("invalid CVV")
+	}
+	if req.PaymentToken != "" && req.CardNumber+req.ExpiryDate+req.Cvv != "" {
+		return errors.New("send either card details or a payment_token")
+	}
+	return nil
+}
+
+// Tokenize vaults the card for a single payment within tokenTTL.
+func (i *Internal) Tokenize(ctx context.Context, card models.NewCardToken) (models.CardToken, error) {
+	if err := validateCard(card); err != nil {
+		return models.CardToken{}, err
+	}
+
+	token, expires, err := i.vault.put(card)
+	if err != nil {
+		return models.CardToken{}, errors.Wrap(err, "error creating token")
+	}
+
+	return models.CardToken{
+		Token:      token,
+		Provider:   i.Name(),
+		Last4:      card.CardNumber[len(card.CardNumber)-4:],
+		ExpiryDate: card.ExpiryDate,
+		ExpiresAt:  expires.Format(time.RFC3339),
+	}, nil
+}
+
+// Charge lets the payment service create the payment, so its ID, amount
+// and status replace the gateway's.
+// A payment token is used up even when the charge fails.
+func (i *Internal) Charge(ctx context.Context, p *models.Payment, req *models.NewPayment) error {
+	in := &payment.NewPayment{
+		OrderId:       req.OrderId,
+		PaymentMethod: req.PaymentMethod,
+		CardNumber:    req.CardNumber,
+		ExpiryDate:    req.ExpiryDate,
+		Cvv:           req.Cvv,
+	}
+	if req.PaymentToken != "" {
+		card, ok := i.vault.take(req.PaymentToken)
+		if !ok {
+			return ErrInvalidToken
+		}
+		in.CardNumber, in.ExpiryDate, in.Cvv = card.CardNumber, card.ExpiryDate, card.Cvv
+	}
+
+	res, err := i.client.MakePayment(ctx, in)
+	if err != nil {
+		return err
+	}
+
+	p.Id = res.Id
+	p.Amount = res.Amount
+	p.Status = res.Status
+	p.TransactionId = res.TransactionId
+	if res.CreatedAt != "" {
+		p.CreatedAt = res.CreatedAt
+	}
+	return nil
+}
+
+// Refund is a no-op: the payment service has no refund RPC and refunds
+// when it receives the refund.approved event.
+func (i *Internal) Refund(ctx context.Context, p models.Payment, id string, amount float32) error {
+	return nil
+}
+
+func (i *Internal) Webhook(r *http.Request, store *storage.Store[models.Payment]) (Notification, any, error) {
+	return Notification{}, nil, ErrNoWebhooks
+}
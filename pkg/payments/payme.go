@@ -0,0 +1,269 @@
+package payments
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Error codes and transaction states of the Payme merchant API.
+const (
+	paymeErrAuth           = -32504
+	paymeErrMethod         = -32601
+	paymeErrAmount         = -31001
+	paymeErrNoTransaction  = -31003
+	paymeErrCannotPerform  = -31008
+	paymeErrAccount        = -31050
+	paymeStatePending      = 1
+	paymeStatePaid         = 2
+	paymeStateCanceled     = -1
+	paymeStateCanceledPaid = -2
+)
+
+// Payme takes payments on the Payme hosted checkout. Payme then drives
+// the payment through the merchant API, calling the webhook with
+// JSON-RPC requests authorized by the merchant key.
+type Payme struct {
+	checkoutURL string
+	merchantID  string
+	key         string
+	client      *http.Client
+}
+
+func NewPayme(checkoutURL, merchantID, key string) *Payme {
+	return &Payme{
+		checkoutURL: strings.TrimSuffix(checkoutURL, "/"),
+		merchantID:  merchantID,
+		key:         key,
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *Payme) Name() string {
+	return models.ProviderPayme
+}
+
+func (p *Payme) Validate(req *models.NewPayment) error {
+	if err := noCard(req); err != nil {
+		return err
+	}
+	if req.PaymentToken != "" {
+		return errors.New("payment tokens are not supported by this provider")
+	}
+	return validReturnURL(req)
+}
+
+// Charge builds the checkout link; the payment stays pending until Payme
+// performs the transaction.
+func (p *Payme) Charge(ctx context.Context, pay *models.Payment, req *models.NewPayment) error {
+	params := fmt.Sprintf("m=%s;ac.payment_id=%s;a=%d", p.merchantID, pay.Id, minor(pay.Amount))
+	if req.ReturnUrl != "" {
+		params += ";c=" + req.ReturnUrl
+	}
+
+	pay.Status = models.PaymentPending
+	pay.RedirectUrl = p.checkoutURL + "/" + base64.StdEncoding.EncodeToString([]byte(params))
+	return nil
+}
+
+// Refund cancels the paid receipt. Payme cannot return part of a payment.
+func (p *Payme) Refund(ctx context.Context, pay models.Payment, id string, amount float32) error {
+	if minor(amount) != minor(pay.Amount) {
+		return ErrPartialRefund
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"id":     id,
+		"method": "receipts.cancel",
+		"params": map[string]string{"id": pay.TransactionId},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.checkoutURL+"/api", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Auth", p.merchantID+":"+p.key)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error cancelling payme receipt")
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Error *struct {
+			Message any `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return errors.Wrap(err, "error decoding payme response")
+	}
+	if res.Error != nil {
+		return errors.Errorf("payme refused the refund: %v", res.Error.Message)
+	}
+	return nil
+}
+
+type paymeRequest struct {
+	Id     any    `json:"id"`
+	Method string `json:"method"`
+	Params struct {
+		Id      string `json:"id"`
+		Time    int64  `json:"time"`
+		Amount  int64  `json:"amount"`
+		Account struct {
+			PaymentId string `json:"payment_id"`
+		} `json:"account"`
+		Reason *int `json:"reason"`
+	} `json:"params"`
+}
+
+type paymeError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+type paymeReply struct {
+	Id     any         `json:"id"`
+	Result any         `json:"result,omitempty"`
+	Error  *paymeError `json:"error,omitempty"`
+}
+
+// Webhook answers a merchant API call. Payme expects every answer,
+// errors included, as a JSON-RPC reply with status 200.
+func (p *Payme) Webhook(r *http.Request, store *storage.Store[models.Payment]) (Notification, any, error) {
+	var req paymeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return Notification{}, nil, errors.Wrap(err, "invalid payme request")
+	}
+
+	fail := func(code int, msg string) (Notification, any, error) {
+		return Notification{}, paymeReply{Id: req.Id, Error: &paymeError{Code: code, Message: msg}}, nil
+	}
+	ok := func(n Notification, result any) (Notification, any, error) {
+		return n, paymeReply{Id: req.Id, Result: result}, nil
+	}
+
+	if !p.authorized(r) {
+		return fail(paymeErrAuth, "insufficient privileges")
+	}
+
+	prm := req.Params
+	now := time.Now()
+
+	switch req.Method {
+	case "CheckPerformTransaction", "CreateTransaction":
+		pay, found := store.Get(prm.Account.PaymentId)
+		switch {
+		case !found || pay.Provider != models.ProviderPayme:
+			return fail(paymeErrAccount, "payment not found")
+		case minor(pay.Amount) != prm.Amount:
+			return fail(paymeErrAmount, "wrong amount")
+		case pay.Status != models.PaymentPending:
+			return fail(paymeErrCannotPerform, "payment is not pending")
+		}
+
+		if req.Method == "CheckPerformTransaction" {
+			return ok(Notification{}, map[string]bool{"allow": true})
+		}
+		if pay.TransactionId != "" && pay.TransactionId != prm.Id {
+			return fail(paymeErrAccount, "payment is awaited in another transaction")
+		}
+		return ok(Notification{PaymentId: pay.Id, TransactionId: prm.Id, Status: models.PaymentPending, At: now},
+			map[string]any{"create_time": millis(pay.CreatedAt, now), "transaction": pay.Id, "state": paymeStatePending})
+	}
+
+	pay, found := ByTransaction(store, models.ProviderPayme, prm.Id)
+	if !found {
+		return fail(paymeErrNoTransaction, "transaction not found")
+	}
+
+	switch req.Method {
+	case "PerformTransaction":
+		var n Notification
+		switch pay.Status {
+		case models.PaymentPending:
+			n = Notification{PaymentId: pay.Id, Status: models.PaymentSucceeded, At: now}
+			pay.PaidAt = now.Format(time.RFC3339)
+		case models.PaymentSucceeded:
+		default:
+			return fail(paymeErrCannotPerform, "transaction is cancelled")
+		}
+		return ok(n, map[string]any{"transaction": pay.Id, "perform_time": millis(pay.PaidAt, now),
+			"state": paymeStatePaid})
+
+	case "CancelTransaction":
+		var n Notification
+		switch pay.Status {
+		case models.PaymentPending:
+			n = Notification{PaymentId: pay.Id, Status: models.PaymentCanceled, At: now}
+			pay.Status = models.PaymentCanceled
+			pay.CanceledAt = now.Format(time.RFC3339)
+		case models.PaymentSucceeded:
+			n = Notification{PaymentId: pay.Id, Status: models.PaymentRefunded, At: now}
+			pay.Status = models.PaymentRefunded
+			pay.CanceledAt = now.Format(time.RFC3339)
+		}
+		return ok(n, map[string]any{"transaction": pay.Id, "cancel_time": millis(pay.CanceledAt, now),
+			"state": paymeState(pay.Status)})
+
+	case "CheckTransaction":
+		return ok(Notification{}, map[string]any{
+			"create_time":  millis(pay.CreatedAt, now),
+			"perform_time": millis(pay.PaidAt, time.Time{}),
+			"cancel_time":  millis(pay.CanceledAt, time.Time{}),
+			"transaction":  pay.Id,
+			"state":        paymeState(pay.Status),
+			"reason":       nil,
+		})
+	}
+
+	return fail(paymeErrMethod, "method not found")
+}
+
+// authorized checks the Basic credentials Payme signs calls with: the
+// login "Paycom" and the merchant key, which must be set.
+func (p *Payme) authorized(r *http.Request) bool {
+	user, pass, ok := r.BasicAuth()
+	return ok && p.key != "" && user == "Paycom" && subtle.ConstantTimeCompare([]byte(pass), []byte(p.key)) == 1
+}
+
+func paymeState(status string) int {
+	switch status {
+	case models.PaymentSucceeded:
+		return paymeStatePaid
+	case models.PaymentCanceled, models.PaymentFailed:
+		return paymeStateCanceled
+	case models.PaymentRefunded:
+		return paymeStateCanceledPaid
+	}
+	return paymeStatePending
+}
+
+// millis converts an RFC 3339 timestamp to Unix milliseconds, using def
+// when it is not set; a zero def gives 0.
+func millis(ts string, def time.Time) int64 {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		t = def
+	}
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
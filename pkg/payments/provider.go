@@ -0,0 +1,198 @@
+package payments
+
+import (
+	"api-gateway/config"
+	"api-gateway/genproto/payment"
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const timeout = 10 * time.Second
+
+var (
+	ErrUnknownProvider = errors.New("unknown payment provider")
+	ErrDeclined        = errors.New("payment declined")
+	ErrPartialRefund   = errors.New("provider supports full refunds only")
+	ErrNoWebhooks      = errors.New("provider sends no webhooks")
+	ErrNoChargebacks   = errors.New("provider sends no chargebacks")
+	ErrSignature       = errors.New("invalid webhook signature")
+)
+
+// Provider charges and refunds payments through one payment gateway, so
+// handlers never deal with a gateway's API directly.
+type Provider interface {
+	Name() string
+	// Validate checks the gateway-specific fields of a payment request.
+	Validate(req *models.NewPayment) error
+	// Charge starts the payment p and fills in its status, transaction
+	// and, for hosted checkouts, the redirect URL.
+	Charge(ctx context.Context, p *models.Payment, req *models.NewPayment) error
+	// Refund returns amount of the payment to the customer. id identifies
+	// the refund so that retries are not paid out twice.
+	Refund(ctx context.Context, p models.Payment, id string, amount float32) error
+	// Webhook authenticates and decodes a notification sent by the
+	// gateway. The returned reply is sent back as JSON; gateways that
+	// report failures in the body get them there instead of as an error.
+	Webhook(r *http.Request, store *storage.Store[models.Payment]) (Notification, any, error)
+}
+
+// Challenger is implemented by providers whose payments can require
+// strong customer authentication (3-D Secure) before they complete.
+type Challenger interface {
+	// Confirm completes a payment after the customer has answered its
+	// challenge, updating its status.
+	Confirm(ctx context.Context, p *models.Payment) error
+}
+
+// Disputer is implemented by providers that report chargebacks, the
+// payments customers dispute with their card issuer.
+type Disputer interface {
+	// Chargeback authenticates and decodes a chargeback notification sent
+	// by the gateway, like Webhook. An empty PaymentId means it is about
+	// no payment made through the gateway.
+	Chargeback(r *http.Request, store *storage.Store[models.Payment]) (Chargeback, any, error)
+}
+
+// Chargeback is a dispute of a payment reported by its gateway.
+type Chargeback struct {
+	Id        string
+	PaymentId string
+	Amount    float32
+	Currency  string
+	Reason    string
+	Status    string
+	At        time.Time
+}
+
+// Notification is a change of a payment reported by its gateway. An
+// empty PaymentId means the call changed nothing, e.g. a pre-check.
+type Notification struct {
+	PaymentId     string
+	TransactionId string
+	Status        string
+	At            time.Time
+}
+
+// Registry holds the providers that are configured, keyed by name, and
+// the sandbox, which only sandbox requests are offered.
+type Registry struct {
+	providers map[string]Provider
+	sandbox   Provider
+	def       string
+	currency  string
+}
+
+// NewRegistry sets up the internal payment service and every external
+// gateway whose credentials are configured.
+func NewRegistry(cfg *config.Config, client payment.PaymentClient) *Registry {
+	r := &Registry{
+		providers: map[string]Provider{},
+		sandbox:   NewSandbox(),
+		def:       cfg.PAYMENT_PROVIDER,
+		currency:  cfg.PAYMENT_CURRENCY,
+	}
+
+	r.add(NewInternal(client))
+	if cfg.STRIPE_SECRET_KEY != "" {
+		r.add(NewStripe(cfg.STRIPE_API_URL, cfg.STRIPE_SECRET_KEY, cfg.STRIPE_WEBHOOK_SECRET))
+	}
+	if cfg.PAYME_MERCHANT_ID != "" {
+		r.add(NewPayme(cfg.PAYME_CHECKOUT_URL, cfg.PAYME_MERCHANT_ID, cfg.PAYME_KEY))
+	}
+	if cfg.CLICK_SERVICE_ID != "" {
+		r.add(NewClick(cfg.CLICK_API_URL, cfg.CLICK_SERVICE_ID, cfg.CLICK_MERCHANT_ID,
+			cfg.CLICK_MERCHANT_USER_ID, cfg.CLICK_SECRET_KEY))
+	}
+
+	return r
+}
+
+func (r *Registry) add(p Provider) {
+	r.providers[p.Name()] = p
+}
+
+// Get returns the provider called name, or the default one when name is
+// empty. The sandbox is found by name for the payments made with it.
+func (r *Registry) Get(name string) (Provider, error) {
+	if name == "" {
+		name = r.def
+	}
+	if name == models.ProviderSandbox {
+		return r.sandbox, nil
+	}
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownProvider, "%q is not one of %v", name, r.Names())
+	}
+	return p, nil
+}
+
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Sandbox returns the provider sandbox orders are paid with.
+func (r *Registry) Sandbox() Provider {
+	return r.sandbox
+}
+
+func (r *Registry) Default() string {
+	return r.def
+}
+
+func (r *Registry) Currency() string {
+	return r.currency
+}
+
+// ByTransaction finds the payment a gateway knows under transactionID.
+func ByTransaction(store *storage.Store[models.Payment], provider, transactionID string) (models.Payment, bool) {
+	if transactionID == "" {
+		return models.Payment{}, false
+	}
+	for _, p := range store.List() {
+		if p.Provider == provider && p.TransactionId == transactionID {
+			return p, true
+		}
+	}
+	return models.Payment{}, false
+}
+
+// minor converts an amount to the currency's minor units, e.g. cents or
+// tiyin.
+func minor(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
+
+// noCard rejects raw card details for gateways that collect them on
+// their own pages.
+func noCard(req *models.NewPayment) error {
+	if req.CardNumber != "" || req.ExpiryDate != "" || req.Cvv != "" {
+		return errors.New("card details must not be sent for this provider")
+	}
+	return nil
+}
+
+// validReturnURL checks the optional URL a hosted checkout returns to.
+func validReturnURL(req *models.NewPayment) error {
+	if req.ReturnUrl == "" {
+		return nil
+	}
+	u, err := url.Parse(req.ReturnUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("return_url must be an absolute http(s) URL")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package payments
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Test cards of the sandbox. Any other card or payment token is paid.
+const (
+	SandboxDeclinedCard  = "4000000000000002"
+	SandboxChallengeCard = "4000000000003220"
+)
+
+// Sandbox simulates payments for sandbox orders, so partners can
+// integrate without charging anyone. Nothing leaves the gateway.
+type Sandbox struct{}
+
+func NewSandbox() *Sandbox {
+	return &Sandbox{}
+}
+
+func (s *Sandbox) Name() string {
+	return models.ProviderSandbox
+}
+
+func (s *Sandbox) Validate(req *models.NewPayment) error {
+	if req.PaymentToken != "" && req.CardNumber+req.ExpiryDate+req.Cvv != "" {
+		return errors.New("send either card details or a payment_token")
+	}
+	if req.PaymentToken == "" && req.CardNumber == "" {
+		return errors.New("card details or a payment_token are required")
+	}
+	return nil
+}
+
+// Charge declines SandboxDeclinedCard, asks SandboxChallengeCard to
+// authenticate, and pays the rest.
+func (s *Sandbox) Charge(ctx context.Context, p *models.Payment, req *models.NewPayment) error {
+	switch req.CardNumber {
+	case SandboxDeclinedCard:
+		return ErrDeclined
+	case SandboxChallengeCard:
+		p.Status = models.PaymentRequiresAction
+		p.Challenge = &models.PaymentChallenge{ClientSecret: "sandbox_" + p.Id}
+	default:
+		p.Status = models.PaymentSucceeded
+		p.PaidAt = p.CreatedAt
+	}
+	p.TransactionId = "sandbox_" + uuid.NewString()
+	return nil
+}
+
+// Confirm passes every challenge.
+func (s *Sandbox) Confirm(ctx context.Context, p *models.Payment) error {
+	p.Status = models.PaymentSucceeded
+	p.Challenge = nil
+	return nil
+}
+
+func (s *Sandbox) Refund(ctx context.Context, p models.Payment, id string, amount float32) error {
+	return nil
+}
+
+func (s *Sandbox) Webhook(r *http.Request, store *storage.Store[models.Payment]) (Notification, any, error) {
+	return Notification{}, nil, ErrNoWebhooks
+}
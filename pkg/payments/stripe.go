@@ -0,0 +1,386 @@
+package payments
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// signatureTolerance bounds how old a signed Stripe webhook may be.
+const signatureTolerance = 5 * time.Minute
+
+// Stripe charges cards with Stripe PaymentIntents. Card details never
+// pass through the gateway: clients create a PaymentMethod with
+// Stripe.js and send its ID as the payment token.
+type Stripe struct {
+	apiURL        string
+	secretKey     string
+	webhookSecret string
+	client        *http.Client
+}
+
+func NewStripe(apiURL, secretKey, webhookSecret string) *Stripe {
+	return &Stripe{
+		apiURL:        strings.TrimSuffix(apiURL, "/"),
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *Stripe) Name() string {
+	return models.ProviderStripe
+}
+
+func (s *Stripe) Validate(req *models.NewPayment) error {
+	if err := noCard(req); err != nil {
+		return errors.Wrap(err, "use a Stripe.js payment_token instead")
+	}
+	if !strings.HasPrefix(req.PaymentToken, "pm_") {
+		return errors.New("payment_token must be a Stripe PaymentMethod ID")
+	}
+	return validReturnURL(req)
+}
+
+type stripeIntent struct {
+	Id           string `json:"id"`
+	Status       string `json:"status"`
+	ClientSecret string `json:"client_secret"`
+	NextAction   *struct {
+		Type          string `json:"type"`
+		RedirectToUrl struct {
+			Url string `json:"url"`
+		} `json:"redirect_to_url"`
+	} `json:"next_action"`
+}
+
+type stripeError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (s *Stripe) Charge(ctx context.Context, p *models.Payment, req *models.NewPayment) error {
+	form := url.Values{
+		"amount":               {strconv.FormatInt(minor(p.Amount), 10)},
+		"currency":             {strings.ToLower(p.Currency)},
+		"payment_method":       {req.PaymentToken},
+		"confirm":              {"true"},
+		"metadata[order_id]":   {p.OrderId},
+		"metadata[payment_id]": {p.Id},
+	}
+	if req.ReturnUrl != "" {
+		form.Set("return_url", req.ReturnUrl)
+	} else {
+		form.Set("automatic_payment_methods[enabled]", "true")
+		form.Set("automatic_payment_methods[allow_redirects]", "never")
+	}
+
+	var intent stripeIntent
+	if err := s.do(ctx, http.MethodPost, "/v1/payment_intents", p.Id, form, &intent); err != nil {
+		return errors.Wrap(err, "error creating stripe payment intent")
+	}
+
+	p.TransactionId = intent.Id
+	s.apply(p, intent)
+	return nil
+}
+
+// Tokenize creates a Stripe PaymentMethod for the card. Stripe accepts
+// raw card numbers only from accounts enabled for it; clients that can
+// should create the PaymentMethod with Stripe.js instead.
+func (s *Stripe) Tokenize(ctx context.Context, card models.NewCardToken) (models.CardToken, error) {
+	if err := validateCard(card); err != nil {
+		return models.CardToken{}, err
+	}
+
+	month, year, _ := strings.Cut(card.ExpiryDate, "/")
+	form := url.Values{
+		"type":            {"card"},
+		"card[number]":    {card.CardNumber},
+		"card[exp_month]": {month},
+		"card[exp_year]":  {"20" + year},
+		"card[cvc]":       {card.Cvv},
+	}
+
+	var pm struct {
+		Id   string `json:"id"`
+		Card struct {
+			Last4 string `json:"last4"`
+		} `json:"card"`
+	}
+	if err := s.do(ctx, http.MethodPost, "/v1/payment_methods", "", form, &pm); err != nil {
+		return models.CardToken{}, errors.Wrap(err, "error creating stripe payment method")
+	}
+
+	return models.CardToken{
+		Token:      pm.Id,
+		Provider:   s.Name(),
+		Last4:      pm.Card.Last4,
+		ExpiryDate: card.ExpiryDate,
+	}, nil
+}
+
+// Confirm completes a payment once the customer has gone through 3-D
+// Secure. Stripe usually confirms the intent itself after the challenge;
+// intents that still wait for confirmation are confirmed here.
+func (s *Stripe) Confirm(ctx context.Context, p *models.Payment) error {
+	path := "/v1/payment_intents/" + url.PathEscape(p.TransactionId)
+
+	var intent stripeIntent
+	if err := s.do(ctx, http.MethodGet, path, "", nil, &intent); err != nil {
+		return errors.Wrap(err, "error getting stripe payment intent")
+	}
+
+	if intent.Status == "requires_confirmation" {
+		if err := s.do(ctx, http.MethodPost, path+"/confirm", p.Id+"-confirm", url.Values{}, &intent); err != nil {
+			return errors.Wrap(err, "error confirming stripe payment intent")
+		}
+	}
+
+	s.apply(p, intent)
+	return nil
+}
+
+// apply copies the state of a PaymentIntent to the payment, including
+// the challenge when the issuer requires authentication.
+func (s *Stripe) apply(p *models.Payment, intent stripeIntent) {
+	p.Status = stripeStatus(intent.Status)
+	p.Challenge = nil
+	if p.Status != models.PaymentRequiresAction {
+		return
+	}
+
+	p.Challenge = &models.PaymentChallenge{ClientSecret: intent.ClientSecret}
+	if intent.NextAction != nil && intent.NextAction.Type == "redirect_to_url" {
+		p.Challenge.RedirectUrl = intent.NextAction.RedirectToUrl.Url
+	}
+}
+
+func (s *Stripe) Refund(ctx context.Context, p models.Payment, id string, amount float32) error {
+	form := url.Values{
+		"payment_intent": {p.TransactionId},
+		"amount":         {strconv.FormatInt(minor(amount), 10)},
+	}
+	return errors.Wrap(s.do(ctx, http.MethodPost, "/v1/refunds", "refund-"+id, form, nil),
+		"error creating stripe refund")
+}
+
+// do calls the Stripe API, sending form as the body when it is not nil.
+// The idempotency key makes retried requests return the first result
+// instead of charging again.
+func (s *Stripe) do(ctx context.Context, method, path, key string, form url.Values, v any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.secretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e stripeError
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		if e.Error.Type == "card_error" {
+			return errors.Wrap(ErrDeclined, e.Error.Message)
+		}
+		return errors.Errorf("stripe responded %d: %s", resp.StatusCode, e.Error.Message)
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+type stripeEvent struct {
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			Id            string            `json:"id"`
+			PaymentIntent string            `json:"payment_intent"`
+			Refunded      bool              `json:"refunded"`
+			Metadata      map[string]string `json:"metadata"`
+			// Disputes have these too.
+			Amount   int64  `json:"amount"`
+			Currency string `json:"currency"`
+			Reason   string `json:"reason"`
+			Status   string `json:"status"`
+			Created  int64  `json:"created"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+func (s *Stripe) Webhook(r *http.Request, store *storage.Store[models.Payment]) (Notification, any, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return Notification{}, nil, err
+	}
+	if err := s.verify(r.Header.Get("Stripe-Signature"), body); err != nil {
+		return Notification{}, nil, err
+	}
+
+	var e stripeEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return Notification{}, nil, errors.Wrap(err, "invalid stripe event")
+	}
+
+	reply := map[string]any{"received": true}
+	obj := e.Data.Object
+
+	var status string
+	switch e.Type {
+	case "payment_intent.succeeded":
+		status = models.PaymentSucceeded
+	case "payment_intent.payment_failed":
+		status = models.PaymentFailed
+	case "payment_intent.canceled":
+		status = models.PaymentCanceled
+	case "charge.refunded":
+		// Partial refunds are recorded when the gateway makes them; this
+		// catches full refunds issued from the Stripe dashboard.
+		p, ok := ByTransaction(store, models.ProviderStripe, obj.PaymentIntent)
+		if !ok || !obj.Refunded {
+			return Notification{}, reply, nil
+		}
+		return Notification{PaymentId: p.Id, Status: models.PaymentRefunded, At: time.Now()}, reply, nil
+	default:
+		return Notification{}, reply, nil
+	}
+
+	p, ok := store.Get(obj.Metadata["payment_id"])
+	if !ok || p.TransactionId != obj.Id {
+		return Notification{}, reply, nil
+	}
+	return Notification{PaymentId: p.Id, Status: status, At: time.Now()}, reply, nil
+}
+
+// Chargeback decodes Stripe's charge.dispute.* events, which are signed
+// like the other webhook events. Other events are acknowledged and
+// ignored.
+func (s *Stripe) Chargeback(r *http.Request, store *storage.Store[models.Payment]) (Chargeback, any, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return Chargeback{}, nil, err
+	}
+	if err := s.verify(r.Header.Get("Stripe-Signature"), body); err != nil {
+		return Chargeback{}, nil, err
+	}
+
+	var e stripeEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return Chargeback{}, nil, errors.Wrap(err, "invalid stripe event")
+	}
+
+	reply := map[string]any{"received": true}
+	obj := e.Data.Object
+	if !strings.HasPrefix(e.Type, "charge.dispute.") {
+		return Chargeback{}, reply, nil
+	}
+	p, ok := ByTransaction(store, models.ProviderStripe, obj.PaymentIntent)
+	if !ok {
+		return Chargeback{}, reply, nil
+	}
+
+	return Chargeback{
+		Id:        obj.Id,
+		PaymentId: p.Id,
+		Amount:    float32(obj.Amount) / 100,
+		Currency:  strings.ToUpper(obj.Currency),
+		Reason:    obj.Reason,
+		Status:    stripeDisputeStatus(obj.Status),
+		At:        time.Unix(obj.Created, 0),
+	}, reply, nil
+}
+
+// stripeDisputeStatus maps a dispute status to a chargeback status.
+// Inquiries (warning_*) that close without a dispute are won.
+func stripeDisputeStatus(status string) string {
+	switch status {
+	case "won", "warning_closed":
+		return models.ChargebackWon
+	case "lost":
+		return models.ChargebackLost
+	}
+	return models.ChargebackOpen
+}
+
+// verify checks the Stripe-Signature header, which holds a timestamp and
+// the hex HMAC-SHA256 of "<timestamp>.<body>". Nothing verifies without
+// a webhook secret.
+func (s *Stripe) verify(header string, body []byte) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || s.webhookSecret == "" || time.Since(time.Unix(sec, 0)).Abs() > signatureTolerance {
+		return ErrSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	want := mac.Sum(nil)
+
+	for _, sig := range sigs {
+		got, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(got, want) {
+			return nil
+		}
+	}
+	return ErrSignature
+}
+
+// stripeStatus maps a PaymentIntent status to a payment status. Intents
+// still processing stay pending until a webhook settles them.
+func stripeStatus(status string) string {
+	switch status {
+	case "succeeded":
+		return models.PaymentSucceeded
+	case "requires_action":
+		return models.PaymentRequiresAction
+	case "canceled":
+		return models.PaymentCanceled
+	case "requires_payment_method":
+		return models.PaymentFailed
+	}
+	return models.PaymentPending
+}
@@ -0,0 +1,39 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhooksNeedSecrets(t *testing.T) {
+	body := []byte(`{"type":"payment_intent.succeeded"}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	sign := func(secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(ts + "."))
+		mac.Write(body)
+		return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	if err := NewStripe("", "sk", "").verify(sign(""), body); err == nil {
+		t.Error("Stripe verified a signature without a webhook secret")
+	}
+	if err := NewStripe("", "sk", "whsec").verify(sign("whsec"), body); err != nil {
+		t.Errorf("Stripe rejected a valid signature: %v", err)
+	}
+
+	r := httptest.NewRequest("POST", "/", nil)
+	r.SetBasicAuth("Paycom", "")
+	if NewPayme("", "m1", "").authorized(r) {
+		t.Error("Payme authorized a call without a merchant key")
+	}
+	r.SetBasicAuth("Paycom", "key")
+	if !NewPayme("", "m1", "key").authorized(r) {
+		t.Error("Payme rejected the merchant key")
+	}
+}
@@ -0,0 +1,75 @@
+package pricing
+
+import (
+	"api-gateway/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DiscountActive reports whether the discount applies at t. Times of day
+// and weekdays are taken in t's location.
+func DiscountActive(d models.NewDishDiscount, t time.Time) bool {
+	if at, err := time.Parse(time.RFC3339, d.StartsAt); err == nil && t.Before(at) {
+		return false
+	}
+	if at, err := time.Parse(time.RFC3339, d.EndsAt); err == nil && !t.Before(at) {
+		return false
+	}
+	if len(d.Days) > 0 && !slices.ContainsFunc(d.Days, func(day string) bool {
+		return strings.EqualFold(day, t.Weekday().String())
+	}) {
+		return false
+	}
+
+	from, err1 := time.Parse("15:04", d.From)
+	until, err2 := time.Parse("15:04", d.Until)
+	if err1 != nil || err2 != nil {
+		return true
+	}
+	now := t.Hour()*60 + t.Minute()
+	start, end := from.Hour()*60+from.Minute(), until.Hour()*60+until.Minute()
+	if end < start {
+		return now >= start || now < end
+	}
+	return now >= start && now < end
+}
+
+// BestDiscount returns the active discount with the largest percent, or
+// nil when none is active.
+func BestDiscount(discounts []models.DishDiscount, t time.Time) *models.DishDiscount {
+	var best *models.DishDiscount
+	for i, d := range discounts {
+		if DiscountActive(d.NewDishDiscount, t) && (best == nil || d.Percent > best.Percent) {
+			best = &discounts[i]
+		}
+	}
+	return best
+}
+
+// Apply prices the item with the discount, or at its unit price when
+// the discount is nil.
+func Apply(it models.PricedItem, d *models.DishDiscount) models.PricedItem {
+	it.DiscountId, it.DiscountPercent, it.DiscountedPrice = "", 0, it.UnitPrice
+	if d != nil {
+		it.DiscountId, it.DiscountPercent = d.Id, d.Percent
+		it.DiscountedPrice = Discounted(it.UnitPrice, d.Percent)
+	}
+	it.Total = round(it.DiscountedPrice * float32(it.Quantity))
+	return it
+}
+
+// Discounted returns price less percent, rounded to cents.
+func Discounted(price, percent float32) float32 {
+	return round(price * (100 - percent) / 100)
+}
+
+// Totals sums what the items cost before their discounts and how much
+// the discounts take off.
+func Totals(items []models.PricedItem) (subtotal, discount float32) {
+	for _, it := range items {
+		subtotal += it.UnitPrice * float32(it.Quantity)
+		discount += (it.UnitPrice - it.DiscountedPrice) * float32(it.Quantity)
+	}
+	return subtotal, discount
+}
@@ -0,0 +1,116 @@
+package pricing
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"math"
+	"sync"
+)
+
+// Tolerance is the largest difference between two amounts that is still
+// considered equal, to absorb float rounding on the client side.
+const Tolerance = 0.01
+
+type Input struct {
+	Subtotal   float32
+	DistanceKm float32
+	Discount   float32
+	// Items are shown in the quote as they were priced.
+	Items []models.PricedItem
+}
+
+type Quote struct {
+	Items       []models.PricedItem `json:"items,omitempty"`
+	Subtotal    float32             `json:"subtotal"`
+	Discount    float32             `json:"discount"`
+	DeliveryFee float32             `json:"delivery_fee"`
+	ServiceFee  float32             `json:"service_fee"`
+	Tax         float32             `json:"tax"`
+	Total       float32             `json:"total"`
+	// Warnings name the dishes holding allergens the customer avoids.
+	Warnings []models.AllergenWarning `json:"warnings,omitempty"`
+}
+
+// Calculator prices orders with fees that start from the config and
+// may be changed at runtime with SetFees.
+type Calculator struct {
+	mu   sync.RWMutex
+	fees models.FeeSettings
+}
+
+func NewCalculator(cfg *config.Config) *Calculator {
+	return &Calculator{fees: models.FeeSettings{
+		DeliveryFee:       cfg.DELIVERY_FEE,
+		DeliveryFeePerKm:  cfg.DELIVERY_FEE_PER_KM,
+		ServiceFeePercent: cfg.SERVICE_FEE_PERCENT,
+		TaxPercent:        cfg.TAX_PERCENT,
+		CommissionPercent: cfg.KITCHEN_COMMISSION_PERCENT,
+	}}
+}
+
+// Fees are the fees orders are priced with.
+func (c *Calculator) Fees() models.FeeSettings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.fees
+}
+
+// SetFees prices orders with fees from now on. Orders already placed
+// keep what they were charged.
+func (c *Calculator) SetFees(fees models.FeeSettings) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fees = fees
+}
+
+// Quote itemizes what the customer pays for an order: the discounted
+// subtotal, a distance based delivery fee, the service fee and tax.
+func (c *Calculator) Quote(in Input) Quote {
+	fees := c.Fees()
+	discount := min(in.Discount, in.Subtotal)
+	net := in.Subtotal - discount
+
+	q := Quote{
+		Items:       in.Items,
+		Subtotal:    round(in.Subtotal),
+		Discount:    round(discount),
+		DeliveryFee: deliveryFee(fees, in.DistanceKm),
+		ServiceFee:  round(net * fees.ServiceFeePercent / 100),
+		Tax:         round(net * fees.TaxPercent / 100),
+	}
+	q.Total = round(q.Subtotal - q.Discount + q.DeliveryFee + q.ServiceFee + q.Tax)
+
+	return q
+}
+
+// DeliveryFee is the base delivery fee plus the fee per km of distance.
+func (c *Calculator) DeliveryFee(distanceKm float32) float32 {
+	return deliveryFee(c.Fees(), distanceKm)
+}
+
+func deliveryFee(fees models.FeeSettings, distanceKm float32) float32 {
+	return round(fees.DeliveryFee + fees.DeliveryFeePerKm*max(distanceKm, 0))
+}
+
+// Commission is the platform's share of an order's amount at percent,
+// deducted from what the kitchen earns.
+func (c *Calculator) Commission(amount, percent float32) float32 {
+	return round(amount * percent / 100)
+}
+
+// CommissionPercent is the rate for kitchens that have no rate of their
+// own or of their cuisine.
+func (c *Calculator) CommissionPercent() float32 {
+	return c.Fees().CommissionPercent
+}
+
+// Matches reports whether total equals the quoted total.
+func (q Quote) Matches(total float32) bool {
+	return math.Abs(float64(q.Total-total)) < Tolerance
+}
+
+func round(v float32) float32 {
+	return float32(math.Round(float64(v)*100) / 100)
+}
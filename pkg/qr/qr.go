@@ -0,0 +1,67 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	MinSize = 128
+	MaxSize = 2048
+)
+
+// Encode draws content as a size by size pixel QR code in the given
+// format. Medium error correction keeps codes readable when printed
+// cards get worn.
+func Encode(content, format string, size int) ([]byte, error) {
+	if size < MinSize || size > MaxSize {
+		return nil, errors.Errorf("size must be from %d to %d", MinSize, MaxSize)
+	}
+
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding QR code")
+	}
+
+	switch format {
+	case FormatPNG:
+		return code.PNG(size)
+	case FormatSVG:
+		return svg(code.Bitmap(), size), nil
+	}
+	return nil, errors.Errorf("format must be %s or %s", FormatPNG, FormatSVG)
+}
+
+// svg draws the modules of a QR code, quiet zone included, as one path
+// scaled to size.
+func svg(bitmap [][]bool, size int) []byte {
+	n := len(bitmap)
+
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x := 0; x < n; x++ {
+			if !row[x] {
+				continue
+			}
+			// Runs of dark modules become one rectangle.
+			start := x
+			for x < n && row[x] {
+				x++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">
+<rect width="100%%" height="100%%" fill="#fff"/>
+<path fill="#000" d="%s"/>
+</svg>
+`, size, size, n, n, path.String()))
+}
@@ -0,0 +1,206 @@
+package recording
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/redact"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// testCard replaces card numbers, so replayed payments charge a test
+// card on staging.
+const testCard = "4111111111111111"
+
+// headers are the headers kept: those that change how a request is
+// answered. Credentials, device locations and cache validators are not.
+var headers = []string{
+	"Accept", "Accept-Language", "Content-Type", "X-API-Format", "X-App-Version",
+	"X-Platform", "X-Schema-Version", "X-Timeout-Ms", "X-Sandbox",
+}
+
+// secretParams are the path parameters that are secrets, such as
+// invitation tokens and short link codes.
+var secretParams = []string{"token", "code"}
+
+// personal are the fields, other than emails, phones and cards, whose
+// values identify or belong to a person.
+var personal = []string{
+	"password", "token", "secret", "code", "otp", "comment", "message", "text",
+	"expiry_date", "external_customer_id", "intercom_code",
+}
+
+// personalParts are parts of field names, such as delivery_address or
+// full_name, whose values identify or belong to a person.
+var personalParts = []string{"address", "name", "note"}
+
+// coordinates are rounded to two decimals, about a kilometre, so
+// replayed orders still route without telling where anyone lives.
+var coordinates = []string{"lat", "lng", "latitude", "longitude"}
+
+// anonymizer replaces personal data with pseudonyms keyed by the
+// recording's salt, so the same value stays the same throughout a
+// recording but can't be guessed back.
+type anonymizer struct {
+	salt []byte
+}
+
+func anonymize(salt []byte, req Request) models.RecordedRequest {
+	a := anonymizer{salt: salt}
+	res := models.RecordedRequest{
+		Method:     req.Method,
+		Route:      req.Route,
+		Path:       a.path(req.Path, req.Params),
+		Query:      a.query(req.Query),
+		Status:     req.Status,
+		DurationMs: req.Duration.Milliseconds(),
+		At:         req.At.UTC().Format(time.RFC3339Nano),
+	}
+
+	for _, name := range headers {
+		if v := req.Header.Get(name); v != "" {
+			if res.Header == nil {
+				res.Header = map[string]string{}
+			}
+			res.Header[name] = v
+		}
+	}
+
+	if len(req.Body) > 0 {
+		res.Body, res.BodyOmitted = a.body(req.Body, req.Truncated)
+	}
+	return res
+}
+
+// path replaces the values of secret parameters.
+func (a anonymizer) path(path string, params map[string]string) string {
+	for _, key := range secretParams {
+		if v := params[key]; v != "" {
+			path = strings.Replace(path, "/"+url.PathEscape(v), "/"+a.pseudonym(v), 1)
+		}
+	}
+	return path
+}
+
+func (a anonymizer) query(q url.Values) string {
+	if len(q) == 0 {
+		return ""
+	}
+	res := url.Values{}
+	for key, values := range q {
+		for _, v := range values {
+			res.Add(key, a.string(strings.ToLower(key), v))
+		}
+	}
+	return res.Encode()
+}
+
+// body anonymizes a JSON body. Other bodies, and those cut short, are
+// omitted.
+func (a anonymizer) body(body []byte, truncated bool) (json.RawMessage, bool) {
+	if truncated {
+		return nil, true
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, true
+	}
+
+	data, err := json.Marshal(a.value("", v))
+	if err != nil {
+		return nil, true
+	}
+	return data, false
+}
+
+// value anonymizes v, the value of the field key or an item of it.
+func (a anonymizer) value(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			v[k] = a.value(strings.ToLower(k), field)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = a.value(key, item)
+		}
+		return v
+	case string:
+		return a.string(key, v)
+	case json.Number:
+		if slices.Contains(coordinates, key) {
+			if f, err := v.Float64(); err == nil {
+				return json.Number(round(f))
+			}
+		}
+		if strings.Contains(key, "phone") || key == "card_number" {
+			return a.string(key, v.String())
+		}
+	}
+	return v
+}
+
+// string anonymizes s, the value of the field key. Card numbers are
+// masked wherever they are.
+func (a anonymizer) string(key, s string) string {
+	if s == "" {
+		return s
+	}
+	switch {
+	case strings.Contains(key, "email"):
+		return "user-" + a.pseudonym(s) + "@example.com"
+	case strings.Contains(key, "phone"):
+		return "+99890" + a.digits(s, 7)
+	case key == "card_number":
+		return testCard
+	case key == "cvv":
+		return "123"
+	case slices.Contains(coordinates, key):
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return round(f)
+		}
+		return "0"
+	case slices.Contains(personal, key) || strings.HasSuffix(key, "password") ||
+		strings.HasSuffix(key, "token") || strings.HasSuffix(key, "secret") ||
+		slices.ContainsFunc(personalParts, func(part string) bool { return strings.Contains(key, part) }):
+		return "redacted-" + a.pseudonym(s)
+	}
+	return redact.PAN(s)
+}
+
+// pseudonym is what s is replaced with throughout the recording.
+func (a anonymizer) pseudonym(s string) string {
+	return hex.EncodeToString(a.sum(s)[:6])
+}
+
+// digits is a pseudonym of s made of n digits.
+func (a anonymizer) digits(s string, n int) string {
+	var b strings.Builder
+	for _, c := range a.sum(s)[:n] {
+		b.WriteByte('0' + c%10)
+	}
+	return b.String()
+}
+
+func (a anonymizer) sum(s string) []byte {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
+
+// round rounds a coordinate to two decimals.
+func round(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
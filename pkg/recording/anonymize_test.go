@@ -0,0 +1,89 @@
+package recording
+
+import (
+	"api-gateway/models"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnonymizeOrder(t *testing.T) {
+	total := float32(84000)
+	body, err := json.Marshal(models.NewOrder{
+		UserId:    "5f0c1b1e-3c4a-4a5e-9d6b-1f2e3d4c5b6a",
+		KitchenId: "0b7e6d5c-4b3a-4291-8f7e-6d5c4b3a2918",
+		Items: []models.OrderItem{{
+			DishId:    "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d",
+			Quantity:  2,
+			Modifiers: []models.Selection{{Group: "Size", Options: []string{"Large"}}},
+			Note:      "no onions, my son Timur is allergic",
+		}},
+		DeliveryAddress: "Amir Temur street 15, apt 42, Tashkent",
+		DeliveryTime:    "2026-10-18T19:30:00Z",
+		TotalAmount:     &total,
+		Location:        &models.Point{Lat: 41.311081, Lng: 69.240562},
+		Note:            "call Dilnoza at the gate",
+		DeliveryPreferences: &models.DeliveryPreferences{
+			CallOnArrival: true,
+			IntercomCode:  "4217",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := Request{
+		Method: http.MethodPost,
+		Route:  "/local-eats/orders",
+		Path:   "/local-eats/orders",
+		Header: http.Header{
+			"Authorization": {"Bearer secret-token"},
+			"Content-Type":  {"application/json"},
+		},
+		Body:   body,
+		Status: http.StatusOK,
+		At:     time.Now(),
+	}
+	salt := []byte("salt")
+	res := anonymize(salt, req)
+
+	out := string(res.Body)
+	for _, secret := range []string{"Amir Temur", "Timur", "Dilnoza", "4217", "41.311081", "69.240562"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("body keeps %q: %s", secret, out)
+		}
+	}
+	if _, ok := res.Header["Authorization"]; ok {
+		t.Errorf("header keeps Authorization: %v", res.Header)
+	}
+
+	var got models.NewOrder
+	if err := json.Unmarshal(res.Body, &got); err != nil {
+		t.Fatalf("body is not an order: %v", err)
+	}
+	if got.KitchenId != "0b7e6d5c-4b3a-4291-8f7e-6d5c4b3a2918" || len(got.Items) != 1 ||
+		got.Items[0].DishId != "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d" || got.Items[0].Quantity != 2 {
+		t.Errorf("order is not replayable: %s", out)
+	}
+	if got.Location == nil || got.Location.Lat != 41.31 || got.Location.Lng != 69.24 {
+		t.Errorf("location is not rounded: %s", out)
+	}
+	if got.TotalAmount == nil || *got.TotalAmount != total {
+		t.Errorf("total amount changed: %s", out)
+	}
+
+	// The same address is the same pseudonym throughout a recording, and
+	// another in the next.
+	if again := anonymize(salt, req); string(again.Body) != out {
+		t.Errorf("pseudonyms differ within a recording: %s and %s", out, again.Body)
+	}
+	var other models.NewOrder
+	if err := json.Unmarshal(anonymize([]byte("other"), req).Body, &other); err != nil {
+		t.Fatal(err)
+	}
+	if other.DeliveryAddress == got.DeliveryAddress {
+		t.Errorf("pseudonyms repeat across recordings: %s", got.DeliveryAddress)
+	}
+}
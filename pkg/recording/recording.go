@@ -0,0 +1,206 @@
+// Package recording captures anonymized traces of the requests the
+// gateway serves while an admin records, and replays them against a
+// staging gateway, so releases are checked against real traffic before
+// they ship.
+package recording
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ErrRecording is returned while another recording is running.
+var ErrRecording = errors.New("a recording is already running")
+
+// Request is a request as it was served, before it is anonymized.
+// Truncated is set when Body holds only the start of the body.
+type Request struct {
+	Method    string
+	Route     string
+	Path      string
+	Params    map[string]string
+	Query     url.Values
+	Header    http.Header
+	Body      []byte
+	Truncated bool
+	Status    int
+	Duration  time.Duration
+	At        time.Time
+}
+
+// Recorder runs one recording at a time and keeps the recorded
+// requests.
+type Recorder struct {
+	recordings  *storage.Store[models.Recording]
+	requests    *storage.Store[models.RecordedRequest]
+	maxRequests int
+	maxBody     int
+
+	mu     sync.Mutex
+	active string
+	until  time.Time
+}
+
+// NewRecorder keeps up to maxRequests requests of a recording, with
+// bodies of up to maxBody bytes.
+func NewRecorder(recordings *storage.Store[models.Recording], requests *storage.Store[models.RecordedRequest],
+	maxRequests, maxBody int) *Recorder {
+	return &Recorder{recordings: recordings, requests: requests, maxRequests: maxRequests, maxBody: maxBody}
+}
+
+// MaxBody is the most of a body that is recorded.
+func (r *Recorder) MaxBody() int {
+	return r.maxBody
+}
+
+// Start records the requests served from now for window.
+func (r *Recorder) Start(by, note string, window time.Duration, now time.Time) (models.Recording, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running(now) {
+		return models.Recording{}, ErrRecording
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return models.Recording{}, errors.Wrap(err, "error generating salt")
+	}
+
+	rec := models.Recording{
+		Id:        uuid.NewString(),
+		Note:      note,
+		StartedBy: by,
+		StartedAt: now.Format(time.RFC3339),
+		EndsAt:    now.Add(window).Format(time.RFC3339),
+		Salt:      salt,
+	}
+	r.recordings.Set(rec.Id, rec)
+	r.active, r.until = rec.Id, now.Add(window)
+	return rec, nil
+}
+
+// Active returns the ID of the recording running at now.
+func (r *Recorder) Active(now time.Time) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.running(now) {
+		return "", false
+	}
+	return r.active, true
+}
+
+// running tells whether a recording runs at now. r.mu must be held.
+func (r *Recorder) running(now time.Time) bool {
+	if r.active != "" && !now.Before(r.until) {
+		r.active = ""
+	}
+	return r.active != ""
+}
+
+// Stop ends the recording id early. Recordings that already ended are
+// left as they are.
+func (r *Recorder) Stop(id string, now time.Time) (models.Recording, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.recordings.Get(id)
+	if !ok {
+		return rec, false
+	}
+	if r.active == id {
+		r.active = ""
+	}
+
+	return r.recordings.Update(id, func(rec models.Recording, _ bool) models.Recording {
+		if ends, err := time.Parse(time.RFC3339, rec.EndsAt); rec.StoppedAt == "" && err == nil && now.Before(ends) {
+			rec.StoppedAt = now.Format(time.RFC3339)
+		}
+		return rec
+	}), true
+}
+
+// Delete drops the recording id and its requests, stopping it first.
+func (r *Recorder) Delete(id string) bool {
+	r.mu.Lock()
+	if r.active == id {
+		r.active = ""
+	}
+	ok := r.recordings.Delete(id)
+	r.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	prefix := id + "|"
+	for _, key := range r.requests.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			r.requests.Delete(key)
+		}
+	}
+	return true
+}
+
+// Record anonymizes req and adds it to the recording id, unless the
+// recording is full.
+func (r *Recorder) Record(id string, req Request) {
+	var (
+		seq  int
+		salt []byte
+	)
+	// Holding r.mu keeps a deleted recording from being recreated.
+	r.mu.Lock()
+	if _, ok := r.recordings.Get(id); ok {
+		r.recordings.Update(id, func(rec models.Recording, _ bool) models.Recording {
+			if rec.Requests >= r.maxRequests {
+				rec.Dropped++
+				return rec
+			}
+			rec.Requests++
+			seq, salt = rec.Requests, rec.Salt
+			return rec
+		})
+	}
+	r.mu.Unlock()
+	if seq == 0 {
+		return
+	}
+
+	res := anonymize(salt, req)
+	res.Seq = seq
+	r.requests.Set(requestKey(id, seq), res)
+}
+
+// Requests returns the requests of the recording id in the order they
+// were served.
+func (r *Recorder) Requests(id string) []models.RecordedRequest {
+	prefix := id + "|"
+	keys := slices.DeleteFunc(r.requests.Keys(), func(key string) bool {
+		return !strings.HasPrefix(key, prefix)
+	})
+	slices.Sort(keys)
+
+	res := make([]models.RecordedRequest, 0, len(keys))
+	for _, key := range keys {
+		if req, ok := r.requests.Get(key); ok {
+			res = append(res, req)
+		}
+	}
+	return res
+}
+
+func requestKey(id string, seq int) string {
+	return fmt.Sprintf("%s|%08d", id, seq)
+}
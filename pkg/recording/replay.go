@@ -0,0 +1,75 @@
+package recording
+
+import (
+	"api-gateway/models"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Replay sends the requests to the gateway at target one after another,
+// in the order they were served, and reports those answered with
+// another status than when they were recorded. token, when given, is
+// sent as the Authorization of every request, since the callers'
+// credentials are not recorded. Requests whose body was not kept are
+// skipped.
+func Replay(ctx context.Context, client *http.Client, target, token string, reqs []models.RecordedRequest) models.ReplayReport {
+	target = strings.TrimSuffix(target, "/")
+	report := models.ReplayReport{Target: target, Mismatches: []models.ReplayResult{}}
+
+	for _, req := range reqs {
+		if req.BodyOmitted {
+			report.Skipped++
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		report.Requests++
+
+		res := models.ReplayResult{Seq: req.Seq, Method: req.Method, Path: req.Path, Recorded: req.Status}
+		status, err := send(ctx, client, target, token, req)
+		res.Status = status
+		if err != nil {
+			res.Error = err.Error()
+		}
+		if err == nil && status == req.Status {
+			report.Matched++
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, res)
+	}
+	return report
+}
+
+func send(ctx context.Context, client *http.Client, target, token string, req models.RecordedRequest) (int, error) {
+	u := target + req.Path
+	if req.Query != "" {
+		u += "?" + req.Query
+	}
+
+	var body io.Reader
+	if len(req.Body) > 0 {
+		body = bytes.NewReader(req.Body)
+	}
+	r, err := http.NewRequestWithContext(ctx, req.Method, u, body)
+	if err != nil {
+		return 0, err
+	}
+	for name, v := range req.Header {
+		r.Header.Set(name, v)
+	}
+	if token != "" {
+		r.Header.Set("Authorization", token)
+	}
+
+	res, err := client.Do(r)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+	return res.StatusCode, nil
+}
@@ -0,0 +1,58 @@
+// Package redact hides card numbers (PANs) so that they never reach logs,
+// error messages or responses.
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// candidate matches 13 to 19 digits, optionally grouped with single
+// spaces or dashes as card numbers are often written.
+var candidate = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+
+// PAN masks every card number in s. Digit runs that fail the Luhn check
+// are left alone, so IDs and amounts stay readable.
+func PAN(s string) string {
+	return candidate.ReplaceAllStringFunc(s, func(m string) string {
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(m)
+		if !Luhn(digits) {
+			return m
+		}
+		return Card(digits)
+	})
+}
+
+// Card masks a known card number, keeping its last four digits.
+func Card(number string) string {
+	if len(number) <= 4 {
+		return strings.Repeat("*", len(number))
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
+
+// Luhn reports whether number is a string of digits with a valid Luhn
+// check digit.
+func Luhn(number string) bool {
+	if number == "" {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
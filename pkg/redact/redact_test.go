@@ -0,0 +1,58 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPAN(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "card 4111111111111111 declined", "card ************1111 declined"},
+		{"spaced", "card 4111 1111 1111 1111", "card ************1111"},
+		{"dashed", "card 5500-0000-0000-0004", "card ************0004"},
+		{"json", `{"card_number":"4242424242424242","cvv":"123"}`, `{"card_number":"************4242","cvv":"123"}`},
+		{"amex", "378282246310005", "***********0005"},
+		{"several", "4111111111111111 and 4242424242424242", "************1111 and ************4242"},
+		{"not luhn", "order 4111111111111112", "order 4111111111111112"},
+		{"too short", "id 411111111111", "id 411111111111"},
+		{"uuid", "44444444-4444-4444-4444-444444444444", "44444444-4444-4444-4444-444444444444"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PAN(tt.in)
+			if got != tt.want {
+				t.Errorf("PAN(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if strings.Contains(got, "4111111111111111") || strings.Contains(got, "4242424242424242") {
+				t.Errorf("PAN(%q) leaks the card number: %q", tt.in, got)
+			}
+		})
+	}
+}
+
+func TestCard(t *testing.T) {
+	if got := Card("4111111111111111"); got != "************1111" {
+		t.Errorf("Card = %q", got)
+	}
+	if got := Card("123"); got != "***" {
+		t.Errorf("Card of a short number = %q", got)
+	}
+}
+
+func TestLuhn(t *testing.T) {
+	for _, n := range []string{"4111111111111111", "5500000000000004", "378282246310005"} {
+		if !Luhn(n) {
+			t.Errorf("Luhn(%q) = false", n)
+		}
+	}
+	for _, n := range []string{"", "4111111111111112", "41111111a1111111"} {
+		if Luhn(n) {
+			t.Errorf("Luhn(%q) = true", n)
+		}
+	}
+}
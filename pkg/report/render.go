@@ -0,0 +1,170 @@
+package report
+
+import (
+	pb "api-gateway/genproto/extra"
+	"api-gateway/models"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ContentTypes = map[string]string{
+	models.ReportCSV: "text/csv",
+	models.ReportPDF: "application/pdf",
+}
+
+// Period is the date range a report covers, both ends inclusive.
+type Period struct {
+	KitchenId string
+	StartDate string
+	EndDate   string
+}
+
+// Render formats the statistics of a kitchen as a CSV or PDF document.
+func Render(format string, p Period, stats *pb.Statistics) ([]byte, error) {
+	switch format {
+	case models.ReportCSV:
+		return renderCSV(p, stats)
+	case models.ReportPDF:
+		return renderPDF(p, stats), nil
+	}
+	return nil, errors.Errorf("unknown report format %q", format)
+}
+
+func renderCSV(p Period, stats *pb.Statistics) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{
+		{"metric", "value"},
+		{"kitchen_id", p.KitchenId},
+		{"start_date", p.StartDate},
+		{"end_date", p.EndDate},
+		{"total_orders", strconv.Itoa(int(stats.TotalOrders))},
+		{"total_revenue", money(stats.TotalRevenue)},
+		{"average_rating", money(stats.AverageRating)},
+		{},
+		{"dish_id", "dish_name", "orders_count", "revenue"},
+	}
+	for _, d := range stats.TopDishes {
+		rows = append(rows, []string{d.Id, d.Name, strconv.Itoa(int(d.OrdersCount)), money(d.Revenue)})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, errors.Wrap(err, "error writing csv report")
+	}
+	return buf.Bytes(), nil
+}
+
+func renderPDF(p Period, stats *pb.Statistics) []byte {
+	lines := []string{
+		fmt.Sprintf("Kitchen: %s", p.KitchenId),
+		fmt.Sprintf("Period: %s - %s", p.StartDate, p.EndDate),
+		"",
+		fmt.Sprintf("Total orders: %d", stats.TotalOrders),
+		fmt.Sprintf("Total revenue: %s", money(stats.TotalRevenue)),
+		fmt.Sprintf("Average rating: %s", money(stats.AverageRating)),
+		"",
+		"Top dishes",
+	}
+	for i, d := range stats.TopDishes {
+		lines = append(lines, fmt.Sprintf("%d. %s - %d orders, %s revenue",
+			i+1, d.Name, d.OrdersCount, money(d.Revenue)))
+	}
+
+	return pdf("Kitchen statistics report", lines)
+}
+
+func money(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', 2, 32)
+}
+
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	margin       = 50
+	lineHeight   = 16
+	linesPerPage = (pageHeight - 2*margin) / lineHeight
+)
+
+// pdf lays out a title and plain text lines on A4 pages using the
+// standard Helvetica fonts, which need no embedding.
+func pdf(title string, lines []string) []byte {
+	var pages [][]string
+	for len(lines) > linesPerPage-2 {
+		pages = append(pages, lines[:linesPerPage-2])
+		lines = lines[linesPerPage-2:]
+	}
+	pages = append(pages, lines)
+
+	// Objects 1-4 are fixed; every page adds a page and a content object.
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	var kids []string
+	for i, lines := range pages {
+		var content strings.Builder
+		y := pageHeight - margin
+		if i == 0 {
+			fmt.Fprintf(&content, "BT /F2 16 Tf %d %d Td (%s) Tj ET\n", margin, y, pdfText(title))
+			y -= 2 * lineHeight
+		}
+		for _, line := range lines {
+			fmt.Fprintf(&content, "BT /F1 11 Tf %d %d Td (%s) Tj ET\n", margin, y, pdfText(line))
+			y -= lineHeight
+		}
+
+		pageID := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+				"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pdfText escapes a string for a PDF literal. Characters outside of
+// printable ASCII are replaced, as the standard fonts cannot show them.
+func pdfText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,197 @@
+package report
+
+import (
+	pb "api-gateway/genproto/extra"
+	"api-gateway/models"
+	"api-gateway/pkg/analytics"
+	"api-gateway/pkg/email"
+	"api-gateway/pkg/envelope"
+	"api-gateway/pkg/webhook"
+	"api-gateway/storage"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const generateTimeout = 30 * time.Second
+
+// Scheduler generates the reports of every schedule once its period is
+// over and delivers them by email or through the kitchen's webhooks.
+// Generated files are kept on disk so they can be downloaded later,
+// sealed when there is a keyring.
+type Scheduler struct {
+	extra    pb.ExtraClient
+	storage  *storage.Storage
+	webhooks *webhook.Dispatcher
+	mailer   *email.Mailer
+	keys     *envelope.Keyring
+	dir      string
+	logger   *slog.Logger
+}
+
+// NewScheduler starts checking for due schedules every interval. keys
+// may be nil to keep report files in the clear.
+func NewScheduler(extra pb.ExtraClient, s *storage.Storage, webhooks *webhook.Dispatcher,
+	mailer *email.Mailer, keys *envelope.Keyring, dir string, interval time.Duration, logger *slog.Logger) *Scheduler {
+	sc := &Scheduler{
+		extra:    extra,
+		storage:  s,
+		webhooks: webhooks,
+		mailer:   mailer,
+		keys:     keys,
+		dir:      dir,
+		logger:   logger,
+	}
+
+	go func() {
+		for now := range time.Tick(interval) {
+			sc.RunDue(now)
+		}
+	}()
+
+	return sc
+}
+
+func (s *Scheduler) EmailEnabled() bool {
+	return s.mailer.Enabled()
+}
+
+// NextRun returns the first period boundary after t: the next Monday for
+// weekly reports and the first day of the next month for monthly ones,
+// both at midnight UTC.
+func NextRun(frequency string, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	if frequency == models.ReportMonthly {
+		return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days := (8 - int(day.Weekday())) % 7
+	if days == 0 {
+		days = 7
+	}
+	return day.AddDate(0, 0, days)
+}
+
+// RunDue generates the reports of all schedules whose run time has come.
+func (s *Scheduler) RunDue(now time.Time) {
+	for _, sc := range s.storage.ReportSchedules.List() {
+		at, err := time.Parse(time.RFC3339, sc.NextRunAt)
+		if err != nil || at.After(now) {
+			continue
+		}
+
+		// Move the schedule forward first so a slow run is not started twice.
+		sc.NextRunAt = NextRun(sc.Frequency, now).Format(time.RFC3339)
+		s.storage.ReportSchedules.Set(sc.Id, sc)
+
+		go s.Generate(sc, at)
+	}
+}
+
+// Generate creates the report of the period that ended at the given time
+// and delivers it. Failures are recorded on the report.
+func (s *Scheduler) Generate(sc models.ReportSchedule, at time.Time) models.Report {
+	start := at.AddDate(0, 0, -7)
+	if sc.Frequency == models.ReportMonthly {
+		start = at.AddDate(0, -1, 0)
+	}
+
+	r := models.Report{
+		Id:         uuid.NewString(),
+		ScheduleId: sc.Id,
+		KitchenId:  sc.KitchenId,
+		Format:     sc.Format,
+		StartDate:  start.Format("2006-01-02"),
+		EndDate:    at.AddDate(0, 0, -1).Format("2006-01-02"),
+		Status:     models.ReportDelivered,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+
+	if err := s.generate(r, sc); err != nil {
+		r.Status = models.ReportFailed
+		r.Error = err.Error()
+		s.logger.Error(errors.Wrapf(err, "error generating report %s", r.Id).Error())
+	}
+
+	s.storage.Reports.Set(r.Id, r)
+	return r
+}
+
+func (s *Scheduler) generate(r models.Report, sc models.ReportSchedule) error {
+	ctx, cancel := context.WithTimeout(context.Background(), generateTimeout)
+	defer cancel()
+
+	in := &pb.Period{
+		Id:        r.KitchenId,
+		StartDate: r.StartDate,
+		EndDate:   r.EndDate,
+	}
+	stats, err := s.extra.GetStatistics(ctx, in)
+	if err != nil {
+		return errors.Wrap(err, "error getting statistics")
+	}
+	// The statistics are asked for without a time zone, in UTC days.
+	stats = analytics.WithoutSandbox(s.storage.SandboxOrders, in, time.UTC, stats)
+
+	data, err := Render(r.Format, Period{r.KitchenId, r.StartDate, r.EndDate}, stats)
+	if err != nil {
+		return err
+	}
+
+	if err := envelope.WriteFile(s.keys, s.Path(r), data); err != nil {
+		return errors.Wrap(err, "error saving report")
+	}
+
+	switch sc.Delivery {
+	case models.ReportDeliveryEmail:
+		_, err := s.mailer.Queue(sc.Email, "en", models.EmailReport,
+			email.ReportData{Frequency: sc.Frequency, StartDate: r.StartDate, EndDate: r.EndDate},
+			email.Attachment{Filename: Filename(r), ContentType: ContentTypes[r.Format], Data: data})
+		return err
+	case models.ReportDeliveryWebhook:
+		s.webhooks.Dispatch(r.KitchenId, models.EventReportGenerated, r)
+	}
+	return nil
+}
+
+// Path is where the file of a generated report is stored.
+func (s *Scheduler) Path(r models.Report) string {
+	return filepath.Join(s.dir, r.Id+"."+r.Format)
+}
+
+// Read returns the file of a generated report.
+func (s *Scheduler) Read(r models.Report) ([]byte, error) {
+	return envelope.ReadFile(s.keys, s.Path(r))
+}
+
+// Reseal seals the file of every report again with the current data key
+// and returns how many it resealed.
+func (s *Scheduler) Reseal() (int, error) {
+	if s.keys == nil {
+		return 0, nil
+	}
+	n := 0
+	for _, r := range s.storage.Reports.List() {
+		err := envelope.ResealFile(s.keys, s.Path(r))
+		if os.IsNotExist(errors.Cause(err)) {
+			// Failed reports have no file.
+			continue
+		}
+		if err != nil {
+			return n, errors.Wrapf(err, "error resealing report %s", r.Id)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func Filename(r models.Report) string {
+	return fmt.Sprintf("report_%s_%s.%s", r.StartDate, r.EndDate, r.Format)
+}
@@ -0,0 +1,34 @@
+package retention
+
+import (
+	pbo "api-gateway/genproto/order"
+	pbp "api-gateway/genproto/payment"
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Client calls the RPCs that anonymize orders and payments. The
+// generated clients don't have them yet, so they are invoked by name; a
+// service without them answers Unimplemented and the records are tried
+// again on the next run.
+type Client struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewClient calls the order and payment services through cc.
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{cc: cc}
+}
+
+// AnonymizeOrder strips the delivery address and other personal data of
+// the order, keeping what the statistics need.
+func (c *Client) AnonymizeOrder(ctx context.Context, orderID string) error {
+	return c.cc.Invoke(ctx, "/order.Order/AnonymizeOrder", &pbo.ID{Id: orderID}, &emptypb.Empty{})
+}
+
+// AnonymizePayment strips the card data of the payment.
+func (c *Client) AnonymizePayment(ctx context.Context, paymentID string) error {
+	return c.cc.Invoke(ctx, "/payment.Payment/AnonymizePayment", &pbp.ID{Id: paymentID}, &emptypb.Empty{})
+}
@@ -0,0 +1,315 @@
+// Package retention enforces the data retention policy: orders and
+// payments past the retention window are anonymized in the services, and
+// the personal data the gateway keeps past it is purged.
+package retention
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	anonymizeTimeout = 10 * time.Second
+	// maxFailures bounds the failures a run report lists.
+	maxFailures = 100
+	// maxRuns is how many run reports are kept.
+	maxRuns = 100
+)
+
+// ErrRunning is returned while another run is in progress.
+var ErrRunning = errors.New("a retention run is already in progress")
+
+// Backend anonymizes the records the services keep.
+type Backend interface {
+	AnonymizeOrder(ctx context.Context, orderID string) error
+	AnonymizePayment(ctx context.Context, paymentID string) error
+}
+
+// Enforcer runs the retention policy, on schedule while it is enabled and
+// whenever admins ask for a run.
+type Enforcer struct {
+	storage *storage.Storage
+	backend Backend
+	policy  models.RetentionPolicy
+	logger  *slog.Logger
+
+	// mu is held for a whole run, so runs never overlap.
+	mu sync.Mutex
+}
+
+// NewEnforcer starts a run every interval if the policy is enabled.
+func NewEnforcer(s *storage.Storage, backend Backend, enabled bool, recordDays, logDays int,
+	interval time.Duration, logger *slog.Logger) *Enforcer {
+	e := &Enforcer{
+		storage: s,
+		backend: backend,
+		policy: models.RetentionPolicy{
+			Enabled:    enabled,
+			RecordDays: recordDays,
+			LogDays:    logDays,
+			Interval:   interval.String(),
+		},
+		logger: logger,
+	}
+
+	if enabled {
+		go func() {
+			for now := range time.Tick(interval) {
+				if _, err := e.Run(now, models.RetentionScheduled, ""); err != nil {
+					e.logger.Error(errors.Wrap(err, "error running retention").Error())
+				}
+			}
+		}()
+	}
+
+	return e
+}
+
+func (e *Enforcer) Policy() models.RetentionPolicy {
+	return e.policy
+}
+
+// Runs returns the reports of the kept runs, latest first.
+func (e *Enforcer) Runs() []models.RetentionRun {
+	runs := e.storage.RetentionRuns.List()
+	slices.SortFunc(runs, func(a, b models.RetentionRun) int {
+		return cmp.Compare(b.StartedAt, a.StartedAt)
+	})
+	return runs
+}
+
+// Run anonymizes and purges what is past the retention window at now and
+// returns the report of the run, which is kept.
+func (e *Enforcer) Run(now time.Time, trigger, startedBy string) (models.RetentionRun, error) {
+	if !e.mu.TryLock() {
+		return models.RetentionRun{}, ErrRunning
+	}
+	defer e.mu.Unlock()
+
+	now = now.UTC()
+	recordsBefore := now.AddDate(0, 0, -e.policy.RecordDays)
+	logsBefore := now.AddDate(0, 0, -e.policy.LogDays)
+	run := models.RetentionRun{
+		Id:            uuid.NewString(),
+		Trigger:       trigger,
+		StartedBy:     startedBy,
+		StartedAt:     now.Format(time.RFC3339),
+		RecordsBefore: recordsBefore.Format(time.RFC3339),
+		LogsBefore:    logsBefore.Format(time.RFC3339),
+		Purged:        make(map[string]int),
+		Failures:      []models.RetentionFailure{},
+	}
+
+	failed := e.anonymize(&run, recordsBefore)
+	e.purgeActivity(&run, recordsBefore, failed)
+	e.purgeRecords(&run, recordsBefore)
+	e.purgeLogs(&run, logsBefore, now)
+
+	run.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	e.storage.RetentionRuns.Set(run.Id, run)
+	runs := e.Runs()
+	for _, old := range runs[min(maxRuns, len(runs)):] {
+		e.storage.RetentionRuns.Delete(old.Id)
+	}
+
+	e.logger.Info("retention run finished", "id", run.Id, "trigger", trigger,
+		"orders", run.AnonymizedOrders, "payments", run.AnonymizedPayments, "failures", run.FailureCount)
+	return run, nil
+}
+
+// oldOrders finds the orders placed before the cutoff that are not
+// anonymized yet, from the customers' activity and from the payments,
+// with the ID of their payment if they have one.
+func (e *Enforcer) oldOrders(before time.Time) map[string]string {
+	orders := make(map[string]string)
+	for _, items := range e.storage.Activity.List() {
+		for _, it := range items {
+			if it.Type == models.FeedOrderPlaced && it.Order != nil && older(it.OccurredAt, before) {
+				orders[it.Order.Id] = ""
+			}
+		}
+	}
+	for _, p := range e.storage.Payments.List() {
+		if p.AnonymizedAt == "" && older(p.CreatedAt, before) {
+			orders[p.OrderId] = ""
+		}
+	}
+	for id := range orders {
+		orders[id], _ = e.storage.OrderPayments.Get(id)
+	}
+	return orders
+}
+
+// anonymize anonymizes the old orders and their payments, and drops the
+// notes and delivery preferences the gateway keeps for them. It returns
+// the orders that failed, which are tried again on the next run.
+func (e *Enforcer) anonymize(run *models.RetentionRun, before time.Time) map[string]bool {
+	failed := make(map[string]bool)
+	fail := func(kind, id string, err error) {
+		run.FailureCount++
+		if len(run.Failures) < maxFailures {
+			run.Failures = append(run.Failures, models.RetentionFailure{Kind: kind, Id: id, Error: err.Error()})
+		}
+		e.logger.Error(errors.Wrapf(err, "error anonymizing %s %s", kind, id).Error())
+	}
+
+	for orderID, paymentID := range e.oldOrders(before) {
+		if err := e.call(e.backend.AnonymizeOrder, orderID); err != nil {
+			fail("order", orderID, err)
+			failed[orderID] = true
+			continue
+		}
+		run.AnonymizedOrders++
+		if e.storage.OrderNotes.Delete(orderID) {
+			run.Purged["order_notes"]++
+		}
+		if e.storage.OrderDelivery.Delete(orderID) {
+			run.Purged["delivery_preferences"]++
+		}
+
+		p, ok := e.storage.Payments.Get(paymentID)
+		if !ok || p.AnonymizedAt != "" {
+			continue
+		}
+		// Only the payment service keeps card data; the providers keep
+		// theirs under their own policies.
+		if p.Provider == models.ProviderInternal {
+			if err := e.call(e.backend.AnonymizePayment, p.Id); err != nil {
+				fail("payment", p.Id, err)
+				failed[orderID] = true
+				continue
+			}
+		}
+		e.storage.Payments.Update(p.Id, func(p models.Payment, _ bool) models.Payment {
+			p.RedirectUrl, p.Challenge = "", nil
+			p.AnonymizedAt = time.Now().UTC().Format(time.RFC3339)
+			return p
+		})
+		run.AnonymizedPayments++
+	}
+	return failed
+}
+
+func (e *Enforcer) call(fn func(context.Context, string) error, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), anonymizeTimeout)
+	defer cancel()
+	return fn(ctx, id)
+}
+
+// purgeActivity drops the activity items that occurred before the cutoff,
+// but for those of orders that failed to be anonymized, so they are
+// found again.
+func (e *Enforcer) purgeActivity(run *models.RetentionRun, before time.Time, failed map[string]bool) {
+	run.Purged["activity"] += trim(e.storage.Activity, func(it models.FeedItem) bool {
+		return older(it.OccurredAt, before) && (it.Order == nil || !failed[it.Order.Id])
+	})
+}
+
+// purgeRecords drops the group orders and meal plans that ended and the
+// tickets that were settled before the cutoff, which hold delivery
+// addresses and what customers wrote, and clears what was written on
+// refund requests decided before it. The requests themselves are kept,
+// as the kitchens' earnings are counted from them.
+func (e *Enforcer) purgeRecords(run *models.RetentionRun, before time.Time) {
+	s := e.storage
+	run.Purged["group_orders"] += purge(s.GroupOrders, func(g models.GroupOrder) bool {
+		return (g.Status == models.GroupCheckedOut || g.Status == models.GroupCanceled) && older(g.UpdatedAt, before)
+	})
+	purge(s.GroupShareCodes, func(id string) bool {
+		_, ok := s.GroupOrders.Get(id)
+		return !ok
+	})
+	run.Purged["meal_plans"] += purge(s.MealPlans, func(p models.MealPlan) bool {
+		return (p.Status == models.MealPlanCanceled || p.Status == models.MealPlanCompleted) && older(p.UpdatedAt, before)
+	})
+	run.Purged["meal_plan_runs"] += purge(s.MealPlanRuns, func(r models.MealPlanRun) bool {
+		_, ok := s.MealPlans.Get(r.PlanId)
+		return !ok
+	})
+	run.Purged["tickets"] += purge(s.Tickets, func(t models.Ticket) bool {
+		return (t.Status == models.TicketResolved || t.Status == models.TicketClosed) && older(t.UpdatedAt, before)
+	})
+	for _, r := range s.RefundRequests.List() {
+		if r.Status == models.RefundPending || !older(r.DecidedAt, before) ||
+			r.Comment == "" && r.DecisionComment == "" && len(r.Evidence) == 0 {
+			continue
+		}
+		s.RefundRequests.Update(r.Id, func(r models.RefundRequest, _ bool) models.RefundRequest {
+			r.Comment, r.DecisionComment, r.Evidence = "", "", []string{}
+			return r
+		})
+		run.Purged["refund_comments"]++
+	}
+}
+
+// purgeLogs drops the logs kept before the cutoff and the expired
+// one-time passwords.
+func (e *Enforcer) purgeLogs(run *models.RetentionRun, before, now time.Time) {
+	s := e.storage
+	run.Purged["emails"] += purge(s.Emails, func(m models.Email) bool {
+		return older(m.CreatedAt, before)
+	})
+	run.Purged["sms_messages"] += purge(s.SMSMessages, func(m models.SMSMessage) bool {
+		return older(m.CreatedAt, before)
+	})
+	run.Purged["webhook_deliveries"] += trim(s.WebhookDeliveries, func(d models.WebhookDelivery) bool {
+		return older(d.CreatedAt, before)
+	})
+	run.Purged["search_history"] += trim(s.SearchHistory, func(h models.SearchHistoryEntry) bool {
+		return older(h.SearchedAt, before)
+	})
+	run.Purged["order_placements"] += purge(s.OrderPlacements, func(p models.OrderPlacement) bool {
+		return older(p.CreatedAt, before)
+	})
+	run.Purged["recorded_requests"] += purge(s.RecordedRequests, func(r models.RecordedRequest) bool {
+		return older(r.At, before)
+	})
+	run.Purged["otps"] += purge(s.OTPs, func(o models.OTP) bool {
+		return older(o.Expires, now)
+	})
+}
+
+// purge deletes the records of store that are old and returns how many
+// it deleted.
+func purge[T any](store *storage.Store[T], old func(T) bool) int {
+	n := 0
+	for _, key := range store.Keys() {
+		if v, ok := store.Get(key); ok && old(v) && store.Delete(key) {
+			n++
+		}
+	}
+	return n
+}
+
+// trim drops the old entries of every list in store, and the lists left
+// empty, and returns how many entries it dropped.
+func trim[T any](store *storage.Store[[]T], old func(T) bool) int {
+	n := 0
+	for _, key := range store.Keys() {
+		list := store.Update(key, func(list []T, _ bool) []T {
+			kept := slices.DeleteFunc(slices.Clone(list), old)
+			n += len(list) - len(kept)
+			return kept
+		})
+		if len(list) == 0 {
+			store.Delete(key)
+		}
+	}
+	return n
+}
+
+// older tells whether the RFC 3339 time s is before t. Times that can't
+// be read are kept.
+func older(s string, t time.Time) bool {
+	at, err := time.Parse(time.RFC3339, s)
+	return err == nil && at.Before(t)
+}
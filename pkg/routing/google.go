@@ -0,0 +1,96 @@
+package routing
+
+import (
+	"api-gateway/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Google asks the Google Maps Distance Matrix API, which prices routes
+// like the Directions API, for driving distances.
+type Google struct {
+	apiURL string
+	key    string
+	client *http.Client
+}
+
+func NewGoogle(apiURL, key string, client *http.Client) *Google {
+	return &Google{apiURL: strings.TrimSuffix(apiURL, "/"), key: key, client: client}
+}
+
+func (g *Google) Name() string {
+	return models.RoutingGoogle
+}
+
+func (g *Google) Matrix(ctx context.Context, origin models.Point, destinations []models.Point) ([]Leg, error) {
+	dests := make([]string, len(destinations))
+	for i, p := range destinations {
+		dests[i] = latLng(p)
+	}
+	query := url.Values{
+		"origins":      {latLng(origin)},
+		"destinations": {strings.Join(dests, "|")},
+		"mode":         {"driving"},
+		"key":          {g.key},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		g.apiURL+"/maps/api/distancematrix/json?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	type value struct {
+		Value float64 `json:"value"`
+	}
+	var out struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Rows         []struct {
+			Elements []struct {
+				Status   string `json:"status"`
+				Distance value  `json:"distance"`
+				Duration value  `json:"duration"`
+			} `json:"elements"`
+		} `json:"rows"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, errors.Wrapf(err, "error decoding Google response (%s)", resp.Status)
+	}
+	if out.Status != "OK" || len(out.Rows) != 1 {
+		return nil, errors.Errorf("Google returned %s: %s %s", resp.Status, out.Status, out.ErrorMessage)
+	}
+
+	elements := out.Rows[0].Elements
+	if len(elements) != len(destinations) {
+		return nil, errors.Errorf("Google returned %d elements for %d destinations", len(elements), len(destinations))
+	}
+	legs := make([]Leg, len(destinations))
+	for i, e := range elements {
+		// Other statuses, such as ZERO_RESULTS, mean there is no route.
+		if e.Status == "OK" {
+			legs[i] = Leg{
+				DistanceKm: e.Distance.Value / 1000,
+				Duration:   time.Duration(e.Duration.Value * float64(time.Second)),
+				Found:      true,
+			}
+		}
+	}
+	return legs, nil
+}
+
+func latLng(p models.Point) string {
+	return fmt.Sprintf("%f,%f", p.Lat, p.Lng)
+}
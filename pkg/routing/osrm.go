@@ -0,0 +1,74 @@
+package routing
+
+import (
+	"api-gateway/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OSRM asks an OSRM server's table service for driving distances.
+type OSRM struct {
+	apiURL string
+	client *http.Client
+}
+
+func NewOSRM(apiURL string, client *http.Client) *OSRM {
+	return &OSRM{apiURL: strings.TrimSuffix(apiURL, "/"), client: client}
+}
+
+func (o *OSRM) Name() string {
+	return models.RoutingOSRM
+}
+
+func (o *OSRM) Matrix(ctx context.Context, origin models.Point, destinations []models.Point) ([]Leg, error) {
+	// OSRM takes coordinates as lng,lat; the origin is the only source.
+	coords := make([]string, 0, len(destinations)+1)
+	for _, p := range append([]models.Point{origin}, destinations...) {
+		coords = append(coords, fmt.Sprintf("%f,%f", p.Lng, p.Lat))
+	}
+	url := fmt.Sprintf("%s/table/v1/driving/%s?sources=0&annotations=distance,duration",
+		o.apiURL, strings.Join(coords, ";"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Code      string       `json:"code"`
+		Message   string       `json:"message"`
+		Distances [][]*float64 `json:"distances"`
+		Durations [][]*float64 `json:"durations"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, errors.Wrapf(err, "error decoding OSRM response (%s)", resp.Status)
+	}
+	if out.Code != "Ok" || len(out.Distances) != 1 || len(out.Durations) != 1 {
+		return nil, errors.Errorf("OSRM returned %s: %s %s", resp.Status, out.Code, out.Message)
+	}
+
+	// The first column is the origin itself.
+	distances, durations := out.Distances[0], out.Durations[0]
+	if len(distances) != len(coords) || len(durations) != len(coords) {
+		return nil, errors.Errorf("OSRM returned %d distances for %d points", len(distances), len(coords))
+	}
+	legs := make([]Leg, len(destinations))
+	for i := range legs {
+		d, t := distances[i+1], durations[i+1]
+		if d != nil && t != nil {
+			legs[i] = Leg{DistanceKm: *d / 1000, Duration: time.Duration(*t * float64(time.Second)), Found: true}
+		}
+	}
+	return legs, nil
+}
@@ -0,0 +1,192 @@
+package routing
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// StraightLine names estimates made without a routing provider.
+	StraightLine = "straight_line"
+
+	timeout     = 5 * time.Second
+	earthRadius = 6371.0
+)
+
+var ErrUnknownProvider = errors.New("unknown routing provider")
+
+// Leg is the road distance and driving time from an origin to one
+// destination. Destinations no road leads to are not Found.
+type Leg struct {
+	DistanceKm float64
+	Duration   time.Duration
+	Found      bool
+}
+
+// Provider asks a routing service for road distances.
+type Provider interface {
+	Name() string
+	// Matrix returns the legs from origin to each destination, in the
+	// destinations' order.
+	Matrix(ctx context.Context, origin models.Point, destinations []models.Point) ([]Leg, error)
+}
+
+// Estimate is a leg and where it came from.
+type Estimate struct {
+	Leg
+	Provider string
+}
+
+type pair struct {
+	kitchenID string
+	zoneID    string
+}
+
+type entry struct {
+	estimate Estimate
+	expires  time.Time
+}
+
+// Router estimates how far orders travel. Distances from a kitchen to a
+// delivery zone are asked for a whole matrix at a time and cached per
+// kitchen-zone pair, since every address in a zone is priced alike. When
+// no provider is configured, or it fails, legs are estimated from the
+// straight line so quotes keep working.
+type Router struct {
+	provider Provider
+	ttl      time.Duration
+	speedKmh float64
+	detour   float64
+	logger   *slog.Logger
+
+	mu    sync.Mutex
+	cache map[pair]entry
+}
+
+func NewRouter(cfg *config.Config, logger *slog.Logger) *Router {
+	r := &Router{
+		ttl:      cfg.ROUTING_CACHE_TTL,
+		speedKmh: cfg.ROUTING_SPEED_KMH,
+		detour:   cfg.ROUTING_DETOUR,
+		logger:   logger,
+		cache:    make(map[pair]entry),
+	}
+
+	client := &http.Client{Timeout: timeout}
+	switch cfg.ROUTING_PROVIDER {
+	case "":
+	case models.RoutingOSRM:
+		r.provider = NewOSRM(cfg.OSRM_API_URL, client)
+	case models.RoutingGoogle:
+		r.provider = NewGoogle(cfg.GOOGLE_MAPS_URL, cfg.GOOGLE_MAPS_KEY, client)
+	default:
+		logger.Error(errors.Wrapf(ErrUnknownProvider, "%q", cfg.ROUTING_PROVIDER).Error())
+	}
+	return r
+}
+
+// Route estimates the leg between two points, uncached.
+func (r *Router) Route(ctx context.Context, from, to models.Point) Estimate {
+	return r.matrix(ctx, from, []models.Point{to})[0]
+}
+
+// Zones estimates the legs from the kitchen, at origin, to the centroid
+// of each zone. Pairs missing from the cache are asked for in a single
+// request.
+func (r *Router) Zones(ctx context.Context, kitchenID string, origin models.Point, zones []models.Zone) []Estimate {
+	res := make([]Estimate, len(zones))
+
+	var missing []int
+	now := time.Now()
+	r.mu.Lock()
+	for i, z := range zones {
+		e, ok := r.cache[pair{kitchenID, z.Id}]
+		if ok && now.Before(e.expires) {
+			res[i] = e.estimate
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	r.mu.Unlock()
+	if len(missing) == 0 {
+		return res
+	}
+
+	destinations := make([]models.Point, len(missing))
+	for i, zi := range missing {
+		destinations[i] = zones[zi].Polygon.Centroid()
+	}
+	estimates := r.matrix(ctx, origin, destinations)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, zi := range missing {
+		res[zi] = estimates[i]
+		// Straight line estimates stand in for an outage and are not
+		// kept, so the provider is asked again next time.
+		if estimates[i].Provider != StraightLine || r.provider == nil {
+			r.cache[pair{kitchenID, zones[zi].Id}] = entry{estimate: estimates[i], expires: now.Add(r.ttl)}
+		}
+	}
+	return res
+}
+
+// Forget drops the cached legs of a kitchen whose location changed, or
+// of a zone that was redrawn. An empty ID matches any.
+func (r *Router) Forget(kitchenID, zoneID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for p := range r.cache {
+		if (kitchenID == "" || p.kitchenID == kitchenID) && (zoneID == "" || p.zoneID == zoneID) {
+			delete(r.cache, p)
+		}
+	}
+}
+
+func (r *Router) matrix(ctx context.Context, origin models.Point, destinations []models.Point) []Estimate {
+	res := make([]Estimate, len(destinations))
+	if r.provider != nil {
+		legs, err := r.provider.Matrix(ctx, origin, destinations)
+		if err == nil && len(legs) == len(destinations) {
+			for i, l := range legs {
+				res[i] = Estimate{Leg: l, Provider: r.provider.Name()}
+			}
+			return res
+		}
+		if err == nil {
+			err = errors.Errorf("%d legs returned for %d destinations", len(legs), len(destinations))
+		}
+		r.logger.Error(errors.Wrapf(err, "error routing with %s", r.provider.Name()).Error())
+	}
+
+	for i, d := range destinations {
+		res[i] = Estimate{Leg: r.straightLine(origin, d), Provider: StraightLine}
+	}
+	return res
+}
+
+func (r *Router) straightLine(from, to models.Point) Leg {
+	km := Haversine(from, to) * r.detour
+	return Leg{
+		DistanceKm: km,
+		Duration:   time.Duration(km / r.speedKmh * float64(time.Hour)),
+		Found:      true,
+	}
+}
+
+// Haversine is the great-circle distance between two points in km.
+func Haversine(a, b models.Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat, dLng := lat2-lat1, (b.Lng-a.Lng)*math.Pi/180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
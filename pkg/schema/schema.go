@@ -0,0 +1,420 @@
+// Package schema migrates the JSON bodies of clients on older versions
+// of the schema: requests forward to the version the handlers speak, and
+// responses back to the version of the client, with the enum values it
+// doesn't know replaced by ones it does.
+package schema
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/version"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// segment is a part of a field path: a field, the elements of an array,
+// or both, "items[]". The elements of a body that is an array are the
+// segment with no name, "[]".
+type segment struct {
+	name string
+	each bool
+}
+
+// path is a field path split where its last [] ends: scope leads to the
+// objects the field is in and rest to the field within each of them.
+type path struct {
+	scope []segment
+	rest  []string
+}
+
+type rename struct {
+	from, to path
+}
+
+type fill struct {
+	at    path
+	value []byte
+}
+
+// step migrates a route's bodies between a version and the next.
+type step struct {
+	version  int
+	request  []rename
+	defaults []fill
+	response []rename
+}
+
+// enumField is a field of a route's responses holding an enum.
+type enumField struct {
+	at       path
+	values   map[string]models.EnumValue
+	fallback string
+}
+
+// Migrator migrates bodies by the migrations of their routes.
+type Migrator struct {
+	current int
+	// routes are the steps of each route by method and path, the oldest
+	// version first.
+	routes map[string][]step
+	apps   []models.SchemaApp
+	// enums are the enum fields of each route's responses.
+	enums map[string][]enumField
+}
+
+// New returns a migrator to version current. Malformed migrations and
+// enums make it panic, so they are caught at startup.
+func New(current int, migrations []models.SchemaMigration, apps []models.SchemaApp, enums []models.Enum) *Migrator {
+	m := &Migrator{
+		current: current,
+		routes:  make(map[string][]step),
+		apps:    apps,
+		enums:   make(map[string][]enumField),
+	}
+	for _, mg := range migrations {
+		route := mg.Method + " " + mg.Path
+		if mg.Version < 1 || mg.Version >= current {
+			panic(fmt.Sprintf("migration of %s from version %d is not before version %d", route, mg.Version, current))
+		}
+		if slices.ContainsFunc(m.routes[route], func(s step) bool { return s.version == mg.Version }) {
+			panic(fmt.Sprintf("migration of %s from version %d is given twice", route, mg.Version))
+		}
+
+		s := step{version: mg.Version}
+		for _, r := range mg.Request {
+			s.request = append(s.request, mustRename(route, r))
+		}
+		for _, r := range mg.Response {
+			// Responses are migrated back, from the newer name to the older.
+			s.response = append(s.response, mustRename(route, models.FieldRename{From: r.To, To: r.From}))
+		}
+		for _, d := range mg.Defaults {
+			value, err := json.Marshal(d.Value)
+			if err != nil {
+				panic(fmt.Sprintf("default of %s in %s: %v", d.Path, route, err))
+			}
+			s.defaults = append(s.defaults, fill{at: mustPath(route, d.Path), value: value})
+		}
+		m.routes[route] = append(m.routes[route], s)
+	}
+	for _, steps := range m.routes {
+		slices.SortFunc(steps, func(a, b step) int { return a.version - b.version })
+	}
+	for _, a := range apps {
+		if a.Version < 1 || a.Version > current || !version.Valid(a.Before) {
+			panic(fmt.Sprintf("invalid schema version %d of %s apps before %q", a.Version, a.Platform, a.Before))
+		}
+	}
+	for _, e := range enums {
+		values := mustValues(e)
+		for _, f := range e.Fields {
+			route := f.Method + " " + f.Path
+			m.enums[route] = append(m.enums[route], enumField{
+				at:       mustPath(route, f.Field),
+				values:   values,
+				fallback: e.Fallback,
+			})
+		}
+	}
+	return m
+}
+
+// mustValues returns the values of the enum by value. Every client must
+// know its fallback, and those of the values they don't.
+func mustValues(e models.Enum) map[string]models.EnumValue {
+	values := make(map[string]models.EnumValue, len(e.Values))
+	for _, v := range e.Values {
+		values[v.Value] = v
+	}
+	if values[e.Fallback].Since != 1 {
+		panic(fmt.Sprintf("fallback %q of %s is not known since version 1", e.Fallback, e.Name))
+	}
+	for _, v := range e.Values {
+		if v.Since < 1 {
+			panic(fmt.Sprintf("value %q of %s has no version", v.Value, e.Name))
+		}
+		if fb, ok := values[v.Fallback]; v.Since > 1 && (!ok || fb.Since >= v.Since) {
+			panic(fmt.Sprintf("value %q of %s has no fallback older than it", v.Value, e.Name))
+		}
+	}
+	return values
+}
+
+func mustRename(route string, r models.FieldRename) rename {
+	from, to := mustPath(route, r.From), mustPath(route, r.To)
+	if !slices.Equal(from.scope, to.scope) {
+		panic(fmt.Sprintf("renaming %s to %s in %s leaves its array", r.From, r.To, route))
+	}
+	return rename{from: from, to: to}
+}
+
+func mustPath(route, p string) path {
+	var parsed path
+	parts := strings.Split(p, ".")
+	last := -1
+	for i, part := range parts {
+		if strings.HasSuffix(part, "[]") {
+			last = i
+		}
+	}
+	for i, part := range parts {
+		name, each := strings.CutSuffix(part, "[]")
+		if (name == "" && !(each && i == 0)) || (i > last && each) {
+			panic(fmt.Sprintf("invalid field path %q in %s", p, route))
+		}
+		if i <= last {
+			parsed.scope = append(parsed.scope, segment{name: fieldKey(name), each: each})
+		} else {
+			parsed.rest = append(parsed.rest, name)
+		}
+	}
+	if len(parsed.rest) == 0 {
+		panic(fmt.Sprintf("field path %q in %s names no field", p, route))
+	}
+	return parsed
+}
+
+// Version tells the schema version of a request: the one it gives in
+// X-Schema-Version, else the one of the app it came from, else the
+// current one.
+func (m *Migrator) Version(app models.ClientApp, header string) (int, error) {
+	if header = strings.TrimSpace(header); header != "" {
+		v, err := strconv.Atoi(header)
+		if err != nil || v < 1 || v > m.current {
+			return 0, errors.Errorf("X-Schema-Version must be between 1 and %d", m.current)
+		}
+		return v, nil
+	}
+
+	v := m.current
+	if app.Version == models.AppUnknown {
+		return v, nil
+	}
+	for _, a := range m.apps {
+		if a.Platform == app.Platform && a.Version < v && version.Compare(app.Version, a.Before) < 0 {
+			v = a.Version
+		}
+	}
+	return v, nil
+}
+
+// Changes tells whether the requests and the responses of route, by
+// method and path, change for a client on version v.
+func (m *Migrator) Changes(route string, v int) (request, response bool) {
+	for _, s := range m.steps(route, v) {
+		request = request || len(s.request) > 0 || len(s.defaults) > 0
+		response = response || len(s.response) > 0
+	}
+	// Enums may have values unknown to any client.
+	response = response || len(m.enums[route]) > 0
+	return request, response
+}
+
+// Request migrates the request body of route from version v to the
+// current one.
+func (m *Migrator) Request(route string, v int, body []byte) ([]byte, error) {
+	steps := m.steps(route, v)
+	if request, _ := m.Changes(route, v); !request {
+		return body, nil
+	}
+
+	doc, err := decode(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid JSON body")
+	}
+	for _, s := range steps {
+		for _, r := range s.request {
+			visit(doc, r.from.scope, func(obj map[string]any) { move(obj, r.from.rest, r.to.rest) })
+		}
+		for _, d := range s.defaults {
+			visit(doc, d.at.scope, func(obj map[string]any) { setDefault(obj, d.at.rest, d.value) })
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// Response migrates the response body of route from the current version
+// back to version v, replacing the values of enums that v doesn't know.
+func (m *Migrator) Response(route string, v int, body []byte) ([]byte, error) {
+	steps := m.steps(route, v)
+	if _, response := m.Changes(route, v); !response {
+		return body, nil
+	}
+
+	doc, err := decode(body)
+	if err != nil {
+		return nil, err
+	}
+	// Enum fields are where the current version has them.
+	for _, f := range m.enums[route] {
+		visit(doc, f.at.scope, func(obj map[string]any) { f.tolerate(obj, v) })
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		for _, r := range steps[i].response {
+			visit(doc, r.from.scope, func(obj map[string]any) { move(obj, r.from.rest, r.to.rest) })
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// tolerate replaces the value of the field in obj with what a client on
+// version v knows it as, keeping the value under extensions.
+func (f enumField) tolerate(obj map[string]any, v int) {
+	parent, key := find(obj, f.at.rest, false)
+	if parent == nil {
+		return
+	}
+	raw, ok := parent[key].(string)
+	if !ok {
+		return
+	}
+
+	value := raw
+	for {
+		known, ok := f.values[value]
+		if !ok {
+			value = f.fallback
+			continue
+		}
+		if known.Since <= v {
+			break
+		}
+		value = known.Fallback
+	}
+	if value == raw {
+		return
+	}
+
+	parent[key] = value
+	ext, ok := parent[lookup(parent, "extensions")].(map[string]any)
+	if !ok {
+		ext = map[string]any{}
+		parent[lookup(parent, "extensions")] = ext
+	}
+	ext[key] = raw
+}
+
+// steps returns the steps of route from version v on.
+func (m *Migrator) steps(route string, v int) []step {
+	steps := m.routes[route]
+	i, _ := slices.BinarySearchFunc(steps, v, func(s step, v int) int { return s.version - v })
+	return steps[i:]
+}
+
+func decode(body []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	// Numbers are kept as they were, 64-bit IDs included.
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// visit calls fn with each object scope leads to from v.
+func visit(v any, scope []segment, fn func(map[string]any)) {
+	if len(scope) == 0 {
+		if obj, ok := v.(map[string]any); ok {
+			fn(obj)
+		}
+		return
+	}
+
+	seg := scope[0]
+	if seg.name != "" {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return
+		}
+		if v, ok = obj[lookup(obj, seg.name)]; !ok {
+			return
+		}
+	}
+	if !seg.each {
+		visit(v, scope[1:], fn)
+		return
+	}
+	list, _ := v.([]any)
+	for _, item := range list {
+		visit(item, scope[1:], fn)
+	}
+}
+
+// move moves the field at from to to within obj. A field already at to
+// is kept, and the one at from dropped.
+func move(obj map[string]any, from, to []string) {
+	src, skey := find(obj, from, false)
+	if src == nil {
+		return
+	}
+	v, ok := src[skey]
+	if !ok {
+		return
+	}
+	dst, dkey := find(obj, to, true)
+	if dst == nil {
+		return
+	}
+
+	delete(src, skey)
+	if _, ok := dst[dkey]; !ok {
+		dst[dkey] = v
+	}
+}
+
+func setDefault(obj map[string]any, at []string, value []byte) {
+	parent, key := find(obj, at, true)
+	if parent == nil {
+		return
+	}
+	if _, ok := parent[key]; ok {
+		return
+	}
+	// Each body gets a copy of its own.
+	parent[key], _ = decode(value)
+}
+
+// find returns the object holding the field at p within obj and the
+// field's key in it, by the name it has there if it is set. Missing
+// objects on the way are made if create is set; nil is returned if they
+// are missing otherwise, or aren't objects.
+func find(obj map[string]any, p []string, create bool) (map[string]any, string) {
+	for _, name := range p[:len(p)-1] {
+		key := lookup(obj, name)
+		next, ok := obj[key]
+		if !ok && create {
+			next = map[string]any{}
+			obj[key] = next
+		}
+		if obj, ok = next.(map[string]any); !ok {
+			return nil, ""
+		}
+	}
+	return obj, lookup(obj, p[len(p)-1])
+}
+
+// lookup returns the key of obj that names the field, whichever naming it
+// uses, or name itself if there is none.
+func lookup(obj map[string]any, name string) string {
+	if _, ok := obj[name]; ok {
+		return name
+	}
+	for k := range obj {
+		if fieldKey(k) == fieldKey(name) {
+			return k
+		}
+	}
+	return name
+}
+
+// fieldKey is a field's name with its naming ignored, so nutrition_info
+// and nutritionInfo match.
+func fieldKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
@@ -0,0 +1,346 @@
+package schema
+
+import (
+	"api-gateway/models"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const route = "POST /local-eats/orders"
+
+// migrations take the orders route through two versions: version 2
+// renamed the address and the items' quantities, version 3 moved the
+// address into a delivery object and required a currency.
+var migrations = []models.SchemaMigration{
+	{Version: 1, Method: "POST", Path: "/local-eats/orders",
+		Request: []models.FieldRename{
+			{From: "address", To: "delivery_address"},
+			{From: "items[].qty", To: "items[].quantity"},
+		},
+		Response: []models.FieldRename{{From: "items[].qty", To: "items[].quantity"}},
+	},
+	{Version: 2, Method: "POST", Path: "/local-eats/orders",
+		Request:  []models.FieldRename{{From: "delivery_address", To: "delivery.address"}},
+		Defaults: []models.FieldDefault{{Path: "currency", Value: "UZS"}},
+		Response: []models.FieldRename{{From: "total", To: "totals.amount"}},
+	},
+}
+
+func TestRequest(t *testing.T) {
+	m := New(3, migrations, nil, nil)
+	tests := []struct {
+		name    string
+		version int
+		in      string
+		want    string
+	}{
+		{"from version 1", 1,
+			`{"address":"Main St 1","items":[{"dish_id":"d1","qty":2},{"dish_id":"d2","qty":1}]}`,
+			`{"currency":"UZS","delivery":{"address":"Main St 1"},"items":[{"dish_id":"d1","quantity":2},{"dish_id":"d2","quantity":1}]}`},
+		{"from version 2", 2,
+			`{"delivery_address":"Main St 1","items":[{"dish_id":"d1","quantity":2}]}`,
+			`{"currency":"UZS","delivery":{"address":"Main St 1"},"items":[{"dish_id":"d1","quantity":2}]}`},
+		{"camel case", 1,
+			`{"address":"Main St 1","items":[{"dishId":"d1","Qty":2}]}`,
+			`{"currency":"UZS","delivery":{"address":"Main St 1"},"items":[{"dishId":"d1","quantity":2}]}`},
+		{"new name kept", 1,
+			`{"address":"old","delivery_address":"new"}`,
+			`{"currency":"UZS","delivery":{"address":"new"}}`},
+		{"default not overridden", 1,
+			`{"currency":"USD"}`,
+			`{"currency":"USD"}`},
+		{"items not an array", 1,
+			`{"items":"none"}`,
+			`{"currency":"UZS","items":"none"}`},
+		{"big numbers kept", 2,
+			`{"kitchen_id":9007199254740993}`,
+			`{"currency":"UZS","kitchen_id":9007199254740993}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Request(route, tt.version, []byte(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameJSON(t, got, tt.want) {
+				t.Errorf("Request(%s) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestCurrent(t *testing.T) {
+	m := New(3, migrations, nil, nil)
+	in := []byte(`{"address": "kept as it is"}`)
+	for _, tt := range []struct {
+		route   string
+		version int
+	}{
+		{route, 3},
+		{"GET /local-eats/orders/:id", 1},
+	} {
+		got, err := m.Request(tt.route, tt.version, in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(in) {
+			t.Errorf("Request of %s at version %d = %s, want the body as it is", tt.route, tt.version, got)
+		}
+	}
+}
+
+func TestRequestInvalid(t *testing.T) {
+	m := New(3, migrations, nil, nil)
+	if _, err := m.Request(route, 1, []byte(`{"address":`)); err == nil {
+		t.Error("Request of malformed JSON succeeded")
+	}
+}
+
+func TestResponse(t *testing.T) {
+	m := New(3, migrations, nil, nil)
+	current := `{"id":"o1","items":[{"dish_id":"d1","quantity":2}],"totals":{"amount":50}}`
+	tests := []struct {
+		version int
+		want    string
+	}{
+		{1, `{"id":"o1","items":[{"dish_id":"d1","qty":2}],"total":50,"totals":{}}`},
+		{2, `{"id":"o1","items":[{"dish_id":"d1","quantity":2}],"total":50,"totals":{}}`},
+		{3, current},
+	}
+
+	for _, tt := range tests {
+		got, err := m.Response(route, tt.version, []byte(current))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameJSON(t, got, tt.want) {
+			t.Errorf("Response at version %d = %s, want %s", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestChanges(t *testing.T) {
+	m := New(3, []models.SchemaMigration{
+		{Version: 1, Method: "GET", Path: "/local-eats/orders/:id",
+			Response: []models.FieldRename{{From: "total", To: "amount"}}},
+	}, nil, nil)
+	request, response := m.Changes("GET /local-eats/orders/:id", 1)
+	if request || !response {
+		t.Errorf("Changes at version 1 = %v, %v, want false, true", request, response)
+	}
+	request, response = m.Changes("GET /local-eats/orders/:id", 2)
+	if request || response {
+		t.Errorf("Changes at version 2 = %v, %v, want false, false", request, response)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	m := New(3, migrations, []models.SchemaApp{
+		{Platform: models.PlatformIOS, Before: "2.0", Version: 1},
+		{Platform: models.PlatformIOS, Before: "2.5", Version: 2},
+		{Platform: models.PlatformAndroid, Before: "3.1", Version: 2},
+	}, nil)
+	tests := []struct {
+		name    string
+		app     models.ClientApp
+		header  string
+		want    int
+		wantErr bool
+	}{
+		{"header", models.ClientApp{Platform: models.PlatformIOS, Version: "1.0"}, "3", 3, false},
+		{"header spaced", models.ClientApp{Platform: models.PlatformOther, Version: models.AppUnknown}, " 2 ", 2, false},
+		{"oldest app", models.ClientApp{Platform: models.PlatformIOS, Version: "1.9.3"}, "", 1, false},
+		{"older app", models.ClientApp{Platform: models.PlatformIOS, Version: "2.4"}, "", 2, false},
+		{"current app", models.ClientApp{Platform: models.PlatformIOS, Version: "2.5"}, "", 3, false},
+		{"other platform", models.ClientApp{Platform: models.PlatformAndroid, Version: "3.0.9"}, "", 2, false},
+		{"unknown version", models.ClientApp{Platform: models.PlatformIOS, Version: models.AppUnknown}, "", 3, false},
+		{"not a number", models.ClientApp{}, "v1", 0, true},
+		{"too old", models.ClientApp{}, "0", 0, true},
+		{"too new", models.ClientApp{}, "4", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Version(tt.app, tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Version error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Version = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRejectsMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		mg   models.SchemaMigration
+	}{
+		{"current version", models.SchemaMigration{Version: 3,
+			Request: []models.FieldRename{{From: "a", To: "b"}}}},
+		{"leaves array", models.SchemaMigration{Version: 1,
+			Request: []models.FieldRename{{From: "items[].qty", To: "qty"}}}},
+		{"no field", models.SchemaMigration{Version: 1,
+			Request: []models.FieldRename{{From: "items[]", To: "lines[]"}}}},
+		{"empty segment", models.SchemaMigration{Version: 1,
+			Defaults: []models.FieldDefault{{Path: "delivery..address", Value: 1}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("New did not panic")
+				}
+			}()
+			New(3, []models.SchemaMigration{tt.mg}, nil, nil)
+		})
+	}
+}
+
+func TestRootArray(t *testing.T) {
+	m := New(2, []models.SchemaMigration{
+		{Version: 1, Method: "POST", Path: "/local-eats/batch",
+			Request: []models.FieldRename{{From: "[].qty", To: "[].quantity"}}},
+	}, nil, nil)
+	got, err := m.Request("POST /local-eats/batch", 1, []byte(`[{"qty":1},{"qty":2}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"quantity":1},{"quantity":2}]`; !sameJSON(t, got, want) {
+		t.Errorf("Request = %s, want %s", got, want)
+	}
+}
+
+var statuses = models.Enum{
+	Name: "order_status",
+	Values: []models.EnumValue{
+		{Value: "pending", Since: 1},
+		{Value: "accepted", Since: 1},
+		{Value: "delivered", Since: 1},
+		{Value: "preparing", Since: 2, Fallback: "accepted"},
+		{Value: "ready", Since: 3, Fallback: "preparing"},
+	},
+	Fallback: "accepted",
+	Fields: []models.EnumField{
+		{Method: "GET", Path: "/local-eats/orders/:id", Field: "status"},
+		{Method: "GET", Path: "/local-eats/orders", Field: "orders[].status"},
+		{Method: "GET", Path: "/local-eats/users/:id/feed", Field: "items[].order.status"},
+	},
+}
+
+func TestEnums(t *testing.T) {
+	m := New(3, nil, nil, []models.Enum{statuses})
+	tests := []struct {
+		name    string
+		route   string
+		version int
+		in      string
+		want    string
+	}{
+		{"known", "GET /local-eats/orders/:id", 1,
+			`{"id":"o1","status":"delivered"}`,
+			`{"id":"o1","status":"delivered"}`},
+		{"unknown", "GET /local-eats/orders/:id", 3,
+			`{"id":"o1","status":"out_for_delivery"}`,
+			`{"id":"o1","status":"accepted","extensions":{"status":"out_for_delivery"}}`},
+		{"newer than the client", "GET /local-eats/orders/:id", 2,
+			`{"status":"ready"}`,
+			`{"status":"preparing","extensions":{"status":"ready"}}`},
+		{"fallbacks chained", "GET /local-eats/orders/:id", 1,
+			`{"status":"ready"}`,
+			`{"status":"accepted","extensions":{"status":"ready"}}`},
+		{"known to the client", "GET /local-eats/orders/:id", 3,
+			`{"status":"ready"}`,
+			`{"status":"ready"}`},
+		{"extensions kept", "GET /local-eats/orders/:id", 1,
+			`{"status":"preparing","extensions":{"eta":"12:30"}}`,
+			`{"status":"accepted","extensions":{"eta":"12:30","status":"preparing"}}`},
+		{"list", "GET /local-eats/orders", 1,
+			`{"orders":[{"status":"pending"},{"status":"on_hold"}],"total":2}`,
+			`{"orders":[{"status":"pending"},{"status":"accepted","extensions":{"status":"on_hold"}}],"total":2}`},
+		{"nested", "GET /local-eats/users/:id/feed", 3,
+			`{"items":[{"type":"review"},{"type":"order","order":{"status":"on_hold"}}]}`,
+			`{"items":[{"type":"review"},{"type":"order","order":{"status":"accepted","extensions":{"status":"on_hold"}}}]}`},
+		{"not a string", "GET /local-eats/orders/:id", 1,
+			`{"status":3}`,
+			`{"status":3}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, response := m.Changes(tt.route, tt.version); !response {
+				t.Fatalf("Changes of %s = false", tt.route)
+			}
+			got, err := m.Response(tt.route, tt.version, []byte(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameJSON(t, got, tt.want) {
+				t.Errorf("Response(%s) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnumsMigrated(t *testing.T) {
+	// Enum fields are where the current version has them, and are moved
+	// back with them; their extensions keep the current name.
+	m := New(2, []models.SchemaMigration{
+		{Version: 1, Method: "GET", Path: "/local-eats/orders/:id",
+			Response: []models.FieldRename{{From: "state", To: "status"}}},
+	}, nil, []models.Enum{statuses})
+	got, err := m.Response("GET /local-eats/orders/:id", 1, []byte(`{"status":"preparing"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"state":"accepted","extensions":{"status":"preparing"}}`; !sameJSON(t, got, want) {
+		t.Errorf("Response = %s, want %s", got, want)
+	}
+}
+
+func TestNewRejectsMalformedEnums(t *testing.T) {
+	tests := []struct {
+		name string
+		e    models.Enum
+	}{
+		{"unknown fallback", models.Enum{Values: []models.EnumValue{{Value: "a", Since: 1}}, Fallback: "b"}},
+		{"newer fallback", models.Enum{Values: []models.EnumValue{{Value: "a", Since: 2}}, Fallback: "a"}},
+		{"value without fallback", models.Enum{Values: []models.EnumValue{
+			{Value: "a", Since: 1}, {Value: "b", Since: 2}}, Fallback: "a"}},
+		{"value falls back to a newer one", models.Enum{Values: []models.EnumValue{
+			{Value: "a", Since: 1}, {Value: "b", Since: 2, Fallback: "c"}, {Value: "c", Since: 2, Fallback: "a"}}, Fallback: "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("New did not panic")
+				}
+			}()
+			New(3, nil, nil, []models.Enum{tt.e})
+		})
+	}
+}
+
+func TestCurrentSchema(t *testing.T) {
+	// The migrations of the API are checked as the gateway starts; this
+	// catches malformed ones before that.
+	New(models.SchemaVersion, models.SchemaMigrations, models.SchemaApps, models.Enums)
+}
+
+func sameJSON(t *testing.T, got []byte, want string) bool {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid JSON %s: %v", want, err)
+	}
+	return reflect.DeepEqual(g, w)
+}
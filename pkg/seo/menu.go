@@ -0,0 +1,210 @@
+package seo
+
+import (
+	pbd "api-gateway/genproto/dish"
+	pbk "api-gateway/genproto/kitchen"
+	"cmp"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// readConcurrency bounds the dish reads made while indexing dishes.
+const readConcurrency = 8
+
+//go:embed menu.html
+var menuHTML string
+
+var menuTemplate = template.Must(template.New("menu").Funcs(template.FuncMap{
+	"price": func(p float32) string {
+		if p == float32(int64(p)) {
+			return fmt.Sprintf("%d", int64(p))
+		}
+		return fmt.Sprintf("%.2f", p)
+	},
+	"join": strings.Join,
+}).Parse(menuHTML))
+
+// labels are the menu page texts by language.
+var labels = map[string]map[string]string{
+	"en": {
+		"menu":      "Menu",
+		"rating":    "Rating",
+		"sold_out":  "Sold out",
+		"allergens": "Allergens",
+		"other":     "Other",
+		"empty":     "No dishes yet.",
+		"order":     "Order in the Local Eats app",
+	},
+	"ru": {
+		"menu":      "Меню",
+		"rating":    "Рейтинг",
+		"sold_out":  "Нет в наличии",
+		"allergens": "Аллергены",
+		"other":     "Прочее",
+		"empty":     "Блюд пока нет.",
+		"order":     "Заказать в приложении Local Eats",
+	},
+	"uz": {
+		"menu":      "Menyu",
+		"rating":    "Reyting",
+		"sold_out":  "Tugagan",
+		"allergens": "Allergenlar",
+		"other":     "Boshqa",
+		"empty":     "Hozircha taomlar yo'q.",
+		"order":     "Local Eats ilovasida buyurtma bering",
+	},
+}
+
+// Menu is a kitchen with its dishes grouped by category.
+type Menu struct {
+	Kitchen    *pbk.Info
+	Categories []MenuCategory
+}
+
+type MenuCategory struct {
+	Name   string
+	Dishes []*pbd.DishInfo
+}
+
+// MenuPage is what the menu page shows, in the language Lang.
+type MenuPage struct {
+	Menu
+	Lang        string
+	Cuisine     string
+	Currency    string
+	Url         string
+	Description string
+	Labels      map[string]string
+}
+
+// Menu gets the kitchen and its dishes.
+func (s *Site) Menu(ctx context.Context, kitchenID string) (Menu, error) {
+	return cached(s, "menu:"+kitchenID, func() (Menu, error) {
+		return s.menu(ctx, kitchenID)
+	})
+}
+
+func (s *Site) menu(ctx context.Context, kitchenID string) (Menu, error) {
+	k, err := s.kitchens.Get(ctx, &pbk.ID{Id: kitchenID})
+	if err != nil {
+		return Menu{}, err
+	}
+
+	dishes, err := s.allDishes(ctx)
+	if err != nil {
+		return Menu{}, err
+	}
+
+	menu := Menu{Kitchen: k}
+	for _, d := range dishes {
+		if d.KitchenId != kitchenID {
+			continue
+		}
+		i := slices.IndexFunc(menu.Categories, func(c MenuCategory) bool {
+			return c.Name == d.Category
+		})
+		if i < 0 {
+			menu.Categories = append(menu.Categories, MenuCategory{Name: d.Category})
+			i = len(menu.Categories) - 1
+		}
+		menu.Categories[i].Dishes = append(menu.Categories[i].Dishes, d)
+	}
+
+	// Uncategorized dishes go last.
+	slices.SortFunc(menu.Categories, func(a, b MenuCategory) int {
+		if (a.Name == "") != (b.Name == "") {
+			if a.Name == "" {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, c := range menu.Categories {
+		slices.SortFunc(c.Dishes, func(a, b *pbd.DishInfo) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+	}
+	return menu, nil
+}
+
+// allDishes reads every dish. The dish service lists dishes without
+// their kitchen, so each one is read once per cache period and shared
+// by the menus of all kitchens.
+func (s *Site) allDishes(ctx context.Context) ([]*pbd.DishInfo, error) {
+	return cached(s, "dishes", func() ([]*pbd.DishInfo, error) {
+		return s.readDishes(ctx)
+	})
+}
+
+// Dishes returns every dish with its details, as cached for the menus.
+// The dishes are shared and must not be modified.
+func (s *Site) Dishes(ctx context.Context) ([]*pbd.DishInfo, error) {
+	return s.allDishes(ctx)
+}
+
+func (s *Site) readDishes(ctx context.Context) ([]*pbd.DishInfo, error) {
+	var list []*pbd.DishDetails
+	for offset := int32(0); ; offset += pageSize {
+		page, err := s.dishes.Fetch(ctx, &pbd.Pagination{Limit: pageSize, Offset: offset})
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching dishes")
+		}
+		list = append(list, page.Dishes...)
+		if len(page.Dishes) < pageSize {
+			break
+		}
+	}
+
+	res := make([]*pbd.DishInfo, len(list))
+	errs := make([]error, len(list))
+	sem := make(chan struct{}, readConcurrency)
+	var wg sync.WaitGroup
+	for i, d := range list {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			res[i], errs[i] = s.dishes.Read(ctx, &pbd.ID{Id: d.Id})
+			if errs[i] != nil {
+				errs[i] = errors.Wrapf(errs[i], "error reading dish %s", d.Id)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// RenderMenu writes the menu page. Languages without texts fall back
+// to English.
+func (s *Site) RenderMenu(w io.Writer, menu Menu, lang, cuisine, currency string) error {
+	l, ok := labels[lang]
+	if !ok {
+		lang, l = "en", labels["en"]
+	}
+
+	description := strings.Join(strings.Fields(menu.Kitchen.Description), " ")
+	return menuTemplate.Execute(w, MenuPage{
+		Menu:        menu,
+		Lang:        lang,
+		Cuisine:     cuisine,
+		Currency:    currency,
+		Url:         s.KitchenURL(menu.Kitchen.Id),
+		Description: truncate(description, descriptionLen),
+		Labels:      l,
+	})
+}
@@ -0,0 +1,201 @@
+package seo
+
+import (
+	pbd "api-gateway/genproto/dish"
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	SiteName = "Local Eats"
+
+	pageSize = 100
+	// maxURLs is the most URLs one sitemap file may list.
+	maxURLs = 50000
+	// descriptionLen is about what search results show of a description.
+	descriptionLen = 160
+)
+
+// Site describes the public pages of the web app, built from kitchen
+// service data. Results are cached, as crawlers and link previews ask
+// for the same pages over and over.
+type Site struct {
+	kitchens pbk.KitchenClient
+	dishes   pbd.DishClient
+	webURL   string
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*entry
+}
+
+type entry struct {
+	done    chan struct{}
+	value   any
+	err     error
+	expires time.Time
+}
+
+func NewSite(kitchens pbk.KitchenClient, dishes pbd.DishClient, webURL string, ttl time.Duration) *Site {
+	return &Site{
+		kitchens: kitchens,
+		dishes:   dishes,
+		webURL:   strings.TrimSuffix(webURL, "/"),
+		ttl:      ttl,
+		cache:    make(map[string]*entry),
+	}
+}
+
+// KitchenURL is the web app page of a kitchen.
+func (s *Site) KitchenURL(kitchenID string) string {
+	return s.webURL + "/kitchens/" + kitchenID
+}
+
+type urlset struct {
+	XMLName xml.Name   `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []location `xml:"url"`
+}
+
+type location struct {
+	Loc      string `xml:"loc"`
+	Priority string `xml:"priority,omitempty"`
+}
+
+// Sitemap lists the home page and every kitchen page.
+func (s *Site) Sitemap(ctx context.Context) ([]byte, error) {
+	return cached(s, "sitemap", func() ([]byte, error) {
+		set := urlset{URLs: []location{{Loc: s.webURL + "/", Priority: "1.0"}}}
+		for offset := int32(0); ; offset += pageSize {
+			page, err := s.kitchens.Fetch(ctx, &pbk.Pagination{Limit: pageSize, Offset: offset})
+			if err != nil {
+				return nil, errors.Wrap(err, "error fetching kitchens")
+			}
+			for _, k := range page.Kitchens {
+				set.URLs = append(set.URLs, location{Loc: s.KitchenURL(k.Id)})
+			}
+			if len(page.Kitchens) < pageSize || len(set.URLs) >= maxURLs {
+				break
+			}
+		}
+		set.URLs = set.URLs[:min(len(set.URLs), maxURLs)]
+
+		data, err := xml.MarshalIndent(set, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(xml.Header), data...), nil
+	})
+}
+
+// KitchenMeta describes a kitchen's page.
+func (s *Site) KitchenMeta(ctx context.Context, kitchenID string) (models.PageMeta, error) {
+	return cached(s, "kitchen:"+kitchenID, func() (models.PageMeta, error) {
+		return s.kitchenMeta(ctx, kitchenID)
+	})
+}
+
+func (s *Site) kitchenMeta(ctx context.Context, kitchenID string) (models.PageMeta, error) {
+	k, err := s.kitchens.Get(ctx, &pbk.ID{Id: kitchenID})
+	if err != nil {
+		return models.PageMeta{}, err
+	}
+
+	description := strings.Join(strings.Fields(k.Description), " ")
+	if description == "" {
+		description = fmt.Sprintf("Order home-cooked %s food from %s on %s.",
+			k.CuisineType, k.Name, SiteName)
+	}
+	description = truncate(description, descriptionLen)
+
+	meta := models.PageMeta{
+		Title:       k.Name + " | " + SiteName,
+		Description: description,
+		Url:         s.KitchenURL(k.Id),
+	}
+	meta.OpenGraph = map[string]string{
+		"og:type":        "restaurant.restaurant",
+		"og:site_name":   SiteName,
+		"og:title":       k.Name,
+		"og:description": meta.Description,
+		"og:url":         meta.Url,
+	}
+	if k.Address != "" {
+		meta.OpenGraph["restaurant:contact_info:street_address"] = k.Address
+	}
+	if k.PhoneNumber != "" {
+		meta.OpenGraph["restaurant:contact_info:phone_number"] = k.PhoneNumber
+	}
+	return meta, nil
+}
+
+// truncate shortens s to at most n runes, cutting at a word boundary.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	s = string([]rune(s)[:n-1])
+	if i := strings.LastIndexByte(s, ' '); i > 0 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, " ,.;:") + "…"
+}
+
+// cached returns the value stored under key, computing it with fn when
+// missing or expired. Concurrent callers share a single computation;
+// failures are not cached.
+func cached[T any](s *Site, key string, fn func() (T, error)) (T, error) {
+	s.mu.Lock()
+	e, ok := s.cache[key]
+	if ok {
+		select {
+		case <-e.done:
+			if e.err != nil || time.Now().After(e.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		e = &entry{done: make(chan struct{})}
+		s.cache[key] = e
+		s.mu.Unlock()
+
+		e.value, e.err = fn()
+		e.expires = time.Now().Add(s.ttl)
+		close(e.done)
+	} else {
+		s.mu.Unlock()
+		<-e.done
+	}
+
+	if e.err != nil {
+		var zero T
+		return zero, e.err
+	}
+	return e.value.(T), nil
+}
+
+// refresh computes the value of key anew and replaces the cached one
+// once it succeeds, so readers keep the old value meanwhile.
+func refresh[T any](s *Site, key string, fn func() (T, error)) error {
+	v, err := fn()
+	if err != nil {
+		return err
+	}
+
+	e := &entry{done: make(chan struct{}), value: v, expires: time.Now().Add(s.ttl)}
+	close(e.done)
+	s.mu.Lock()
+	s.cache[key] = e
+	s.mu.Unlock()
+	return nil
+}
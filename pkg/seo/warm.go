@@ -0,0 +1,112 @@
+package seo
+
+import (
+	pbd "api-gateway/genproto/dish"
+	pbk "api-gateway/genproto/kitchen"
+	"api-gateway/models"
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// warmTimeout bounds a round of warming, which reads every dish.
+const warmTimeout = 5 * time.Minute
+
+// StartWarming warms the pages of the n most popular kitchens at once
+// and then every interval, so the first visitors after a deploy or a
+// cache expiry don't wait for every dish to be read. Interval should be
+// below the cache TTL for entries to be replaced before they expire.
+func (s *Site) StartWarming(n int, interval time.Duration, logger *slog.Logger) {
+	if n <= 0 {
+		return
+	}
+
+	warm := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), warmTimeout)
+		defer cancel()
+
+		start := time.Now()
+		warmed, err := s.Warm(ctx, n)
+		if err != nil {
+			logger.Error(errors.Wrap(err, "error warming kitchen pages").Error())
+			return
+		}
+		logger.Info(fmt.Sprintf("warmed the pages of %d kitchens in %s", warmed, time.Since(start).Round(time.Millisecond)))
+	}
+
+	go func() {
+		warm()
+		for range time.Tick(interval) {
+			warm()
+		}
+	}()
+}
+
+// Warm reads anew the dishes and the page and menu, with its rating, of
+// the n most popular kitchens, replacing the cached ones. Readers keep
+// the old entries until the new ones are ready. It returns how many
+// kitchens were warmed; a kitchen that fails doesn't stop the others.
+func (s *Site) Warm(ctx context.Context, n int) (int, error) {
+	ids, err := s.popular(ctx, n)
+	if err != nil {
+		return 0, err
+	}
+	if err := refresh(s, "dishes", func() ([]*pbd.DishInfo, error) {
+		return s.readDishes(ctx)
+	}); err != nil {
+		return 0, err
+	}
+
+	warmed := 0
+	for _, id := range ids {
+		err := refresh(s, "kitchen:"+id, func() (models.PageMeta, error) {
+			return s.kitchenMeta(ctx, id)
+		})
+		if err == nil {
+			err = refresh(s, "menu:"+id, func() (Menu, error) {
+				return s.menu(ctx, id)
+			})
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return warmed, ctx.Err()
+			}
+			continue
+		}
+		warmed++
+	}
+	return warmed, nil
+}
+
+// popular lists the n kitchens with the most orders, the better rated
+// first among equals.
+func (s *Site) popular(ctx context.Context, n int) ([]string, error) {
+	var list []*pbk.KitchenDetails
+	for offset := int32(0); ; offset += pageSize {
+		page, err := s.kitchens.Fetch(ctx, &pbk.Pagination{Limit: pageSize, Offset: offset})
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching kitchens")
+		}
+		list = append(list, page.Kitchens...)
+		if len(page.Kitchens) < pageSize {
+			break
+		}
+	}
+
+	slices.SortFunc(list, func(a, b *pbk.KitchenDetails) int {
+		if c := cmp.Compare(b.TotalOrders, a.TotalOrders); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Rating, a.Rating)
+	})
+	ids := make([]string, min(n, len(list)))
+	for i := range ids {
+		ids[i] = list[i].Id
+	}
+	return ids, nil
+}
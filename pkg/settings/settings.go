@@ -0,0 +1,330 @@
+package settings
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"api-gateway/pkg/version"
+	"api-gateway/storage"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// key is the only key of the settings store.
+const key = "platform"
+
+// maxBanners bounds the banners shown at once, which the apps show one
+// after another.
+const maxBanners = 10
+
+var (
+	ErrInvalid = errors.New("invalid settings")
+	// ErrVersion is returned for an update of a version other than the
+	// current one.
+	ErrVersion = errors.New("settings were changed meanwhile")
+)
+
+// Manager keeps the platform settings and their change history. Fees
+// and the oldest app versions served start from the config; delivery
+// zones are enforced, maintenance is off and there are no banners until
+// an admin says otherwise.
+type Manager struct {
+	mu        sync.Mutex
+	store     *storage.Store[models.Settings]
+	history   *storage.Store[models.SettingsChange]
+	listeners []func(models.Settings)
+	logger    *slog.Logger
+}
+
+func NewManager(cfg *config.Config, store *storage.Store[models.Settings],
+	history *storage.Store[models.SettingsChange], logger *slog.Logger) *Manager {
+	store.Update(key, func(s models.Settings, ok bool) models.Settings {
+		if ok {
+			return s
+		}
+		return models.Settings{
+			Fees: models.FeeSettings{
+				DeliveryFee:       cfg.DELIVERY_FEE,
+				DeliveryFeePerKm:  cfg.DELIVERY_FEE_PER_KM,
+				ServiceFeePercent: cfg.SERVICE_FEE_PERCENT,
+				TaxPercent:        cfg.TAX_PERCENT,
+				CommissionPercent: cfg.KITCHEN_COMMISSION_PERCENT,
+			},
+			Zones:   models.ZoneSettings{Enforced: true},
+			Banners: []models.PlatformBanner{},
+			AppVersions: models.AppVersions{
+				MinIOS:          cfg.MIN_IOS_VERSION,
+				MinAndroid:      cfg.MIN_ANDROID_VERSION,
+				MinWeb:          cfg.MIN_WEB_VERSION,
+				IOSStoreUrl:     cfg.IOS_STORE_URL,
+				AndroidStoreUrl: cfg.ANDROID_STORE_URL,
+			},
+			Version: 1,
+		}
+	})
+	return &Manager{store: store, history: history, logger: logger}
+}
+
+// Get returns the current settings.
+func (m *Manager) Get() models.Settings {
+	s, _ := m.store.Get(key)
+	return s
+}
+
+// OnChange calls fn with the settings after each update that changes
+// them, and once now with the current ones.
+func (m *Manager) OnChange(fn func(models.Settings)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.listeners = append(m.listeners, fn)
+	fn(m.Get())
+}
+
+// Update applies the sections of u, recording what changed. Banners
+// without an ID are given one. An update that changes nothing is not
+// recorded and leaves the version as it was.
+func (m *Manager) Update(u models.SettingsUpdate, by string, now time.Time) (models.Settings, error) {
+	if u.Banners != nil {
+		banners := slices.Clone(*u.Banners)
+		for i := range banners {
+			if banners[i].Id == "" {
+				banners[i].Id = uuid.NewString()
+			}
+		}
+		u.Banners = &banners
+	}
+	if err := validate(u); err != nil {
+		return models.Settings{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cur := m.Get()
+	if u.Version != nil && *u.Version != cur.Version {
+		return cur, errors.Wrapf(ErrVersion, "version %d is not the current %d", *u.Version, cur.Version)
+	}
+
+	next := cur
+	var changes []models.SectionChange
+	change := func(section string, before, after any) {
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, models.SectionChange{Section: section, Before: before, After: after})
+		}
+	}
+	if u.Fees != nil {
+		change(models.SettingsFees, cur.Fees, *u.Fees)
+		next.Fees = *u.Fees
+	}
+	if u.Zones != nil {
+		change(models.SettingsZones, cur.Zones, *u.Zones)
+		next.Zones = *u.Zones
+	}
+	if u.Maintenance != nil {
+		change(models.SettingsMaintenance, cur.Maintenance, *u.Maintenance)
+		next.Maintenance = *u.Maintenance
+	}
+	if u.Banners != nil {
+		change(models.SettingsBanners, cur.Banners, *u.Banners)
+		next.Banners = *u.Banners
+	}
+	if u.AppVersions != nil {
+		change(models.SettingsAppVersions, cur.AppVersions, *u.AppVersions)
+		next.AppVersions = *u.AppVersions
+	}
+	if len(changes) == 0 {
+		return cur, nil
+	}
+
+	next.Version++
+	next.UpdatedBy = by
+	next.UpdatedAt = now.Format(time.RFC3339)
+	m.store.Set(key, next)
+
+	rec := models.SettingsChange{
+		Id:        uuid.NewString(),
+		Version:   next.Version,
+		Sections:  changes,
+		ChangedBy: by,
+		ChangedAt: next.UpdatedAt,
+	}
+	m.history.Set(rec.Id, rec)
+
+	sections := make([]string, len(changes))
+	for i, c := range changes {
+		sections[i] = c.Section
+	}
+	m.logger.Info(fmt.Sprintf("settings version %d by %s changed %s",
+		next.Version, by, strings.Join(sections, ", ")))
+
+	for _, fn := range m.listeners {
+		fn(next)
+	}
+	return next, nil
+}
+
+// History lists the changes of the settings, newest first, optionally
+// only those of a section.
+func (m *Manager) History(section string) []models.SettingsChange {
+	var res []models.SettingsChange
+	for _, c := range m.history.List() {
+		if section == "" || slices.ContainsFunc(c.Sections, func(s models.SectionChange) bool {
+			return s.Section == section
+		}) {
+			res = append(res, c)
+		}
+	}
+	slices.SortFunc(res, func(a, b models.SettingsChange) int {
+		return b.Version - a.Version
+	})
+	return res
+}
+
+// Maintenance returns the maintenance settings.
+func (m *Manager) Maintenance() models.Maintenance {
+	return m.Get().Maintenance
+}
+
+// AppVersions returns the oldest app versions served.
+func (m *Manager) AppVersions() models.AppVersions {
+	return m.Get().AppVersions
+}
+
+// ZonesEnforced reports whether delivery is limited to the zones.
+func (m *Manager) ZonesEnforced() bool {
+	return m.Get().Zones.Enforced
+}
+
+// ActiveBanners returns the banners shown at now.
+func (m *Manager) ActiveBanners(now time.Time) []models.PlatformBanner {
+	res := []models.PlatformBanner{}
+	for _, b := range m.Get().Banners {
+		// Banners were validated, so their times parse.
+		start, _ := time.Parse(time.RFC3339, b.StartsAt)
+		if now.Before(start) {
+			continue
+		}
+		if b.EndsAt != "" {
+			if end, _ := time.Parse(time.RFC3339, b.EndsAt); !now.Before(end) {
+				continue
+			}
+		}
+		res = append(res, b)
+	}
+	return res
+}
+
+func validate(u models.SettingsUpdate) error {
+	if f := u.Fees; f != nil {
+		if f.DeliveryFee < 0 || f.DeliveryFeePerKm < 0 {
+			return errors.Wrap(ErrInvalid, "delivery fees can't be negative")
+		}
+		percents := []struct {
+			name  string
+			value float32
+		}{
+			{"service_fee_percent", f.ServiceFeePercent},
+			{"tax_percent", f.TaxPercent},
+			{"commission_percent", f.CommissionPercent},
+		}
+		for _, p := range percents {
+			if p.value < 0 || p.value > 100 {
+				return errors.Wrapf(ErrInvalid, "%s must be between 0 and 100", p.name)
+			}
+		}
+	}
+
+	if mt := u.Maintenance; mt != nil {
+		if mt.Enabled && strings.TrimSpace(mt.Message) == "" {
+			return errors.Wrap(ErrInvalid, "maintenance needs a message while enabled")
+		}
+		if mt.EndsAt != "" {
+			if _, err := time.Parse(time.RFC3339, mt.EndsAt); err != nil {
+				return errors.Wrap(ErrInvalid, "maintenance ends_at must be an RFC 3339 time")
+			}
+		}
+	}
+
+	if v := u.AppVersions; v != nil {
+		if err := validateAppVersions(*v); err != nil {
+			return err
+		}
+	}
+
+	if u.Banners != nil {
+		if len(*u.Banners) > maxBanners {
+			return errors.Wrapf(ErrInvalid, "at most %d banners", maxBanners)
+		}
+		ids := map[string]bool{}
+		for _, b := range *u.Banners {
+			if err := validateBanner(b); err != nil {
+				return errors.Wrapf(err, "banner %q", b.Title)
+			}
+			if ids[b.Id] {
+				return errors.Wrapf(ErrInvalid, "banner id %s is repeated", b.Id)
+			}
+			ids[b.Id] = true
+		}
+	}
+	return nil
+}
+
+func validateAppVersions(v models.AppVersions) error {
+	for name, min := range map[string]string{
+		"min_ios":     v.MinIOS,
+		"min_android": v.MinAndroid,
+		"min_web":     v.MinWeb,
+	} {
+		if min != "" && !version.Valid(min) {
+			return errors.Wrapf(ErrInvalid, "%s must be a version such as 2.3.1", name)
+		}
+	}
+	for name, link := range map[string]string{
+		"ios_store_url":     v.IOSStoreUrl,
+		"android_store_url": v.AndroidStoreUrl,
+	} {
+		if link != "" && !httpURL(link) {
+			return errors.Wrapf(ErrInvalid, "%s must be an http(s) URL", name)
+		}
+	}
+	return nil
+}
+
+func validateBanner(b models.PlatformBanner) error {
+	if strings.TrimSpace(b.Title) == "" {
+		return errors.Wrap(ErrInvalid, "title is required")
+	}
+	start, err := time.Parse(time.RFC3339, b.StartsAt)
+	if err != nil {
+		return errors.Wrap(ErrInvalid, "starts_at must be an RFC 3339 time")
+	}
+	if b.EndsAt != "" {
+		end, err := time.Parse(time.RFC3339, b.EndsAt)
+		if err != nil {
+			return errors.Wrap(ErrInvalid, "ends_at must be an RFC 3339 time")
+		}
+		if !end.After(start) {
+			return errors.Wrap(ErrInvalid, "ends_at must be after starts_at")
+		}
+	}
+	if b.LinkUrl != "" {
+		if !httpURL(b.LinkUrl) {
+			return errors.Wrap(ErrInvalid, "link_url must be an http(s) URL")
+		}
+	}
+	return nil
+}
+
+func httpURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
@@ -0,0 +1,52 @@
+package slo
+
+import "time"
+
+// counts are the calls to a backend in a time slot: all of those counted,
+// the failed ones and the slow ones.
+type counts struct {
+	calls, failed, slow int64
+}
+
+func (c *counts) add(o counts) {
+	c.calls += o.calls
+	c.failed += o.failed
+	c.slow += o.slow
+}
+
+// ring keeps counts in slots of step, as many as fit the span it was made
+// for. Slots are reused as time goes on, so older counts drop out.
+type ring struct {
+	step  time.Duration
+	slots []counts
+	// at is the slot number, time over step, each slot holds counts of.
+	at []int64
+}
+
+func newRing(step, span time.Duration) *ring {
+	n := int((span + step - 1) / step)
+	return &ring{step: step, slots: make([]counts, n), at: make([]int64, n)}
+}
+
+func (r *ring) add(now time.Time, c counts) {
+	k := now.UnixNano() / int64(r.step)
+	i := k % int64(len(r.slots))
+	if r.at[i] != k {
+		r.at[i], r.slots[i] = k, counts{}
+	}
+	r.slots[i].add(c)
+}
+
+// sum adds up the counts of the span until now, at most the ring's.
+func (r *ring) sum(now time.Time, span time.Duration) counts {
+	k := now.UnixNano() / int64(r.step)
+	n := min(int64((span+r.step-1)/r.step), int64(len(r.slots)))
+
+	var res counts
+	for j := k - n + 1; j <= k; j++ {
+		if i := j % int64(len(r.slots)); r.at[i] == j {
+			res.add(r.slots[i])
+		}
+	}
+	return res
+}
@@ -0,0 +1,238 @@
+package slo
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// burnWindows are the windows burn rates are worked out over, the pairs
+// of which multiwindow burn rate alerts are built on.
+var burnWindows = []struct {
+	name string
+	span time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+}
+
+// failures are the codes a backend answers with when it is at fault.
+// Others, such as NotFound or InvalidArgument, are answers to the
+// gateway's requests and count as good calls.
+var failures = map[codes.Code]bool{
+	codes.Unknown:           true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+	codes.Unimplemented:     true,
+	codes.Internal:          true,
+	codes.Unavailable:       true,
+	codes.DataLoss:          true,
+}
+
+// series are the calls to a backend: by the minute for the burn rates,
+// by the hour for the SLO window.
+type series struct {
+	minutes *ring
+	hours   *ring
+}
+
+// Tracker measures the calls to the backend services against their
+// availability and latency SLOs: the share of calls that did not fail,
+// and of those answered within the latency threshold. Calls the gateway
+// canceled itself are not counted. It exports the calls, their durations
+// and where each backend stands as Prometheus metrics.
+type Tracker struct {
+	availability float64
+	latency      float64
+	threshold    time.Duration
+	window       time.Duration
+
+	mu       sync.Mutex
+	backends map[string]*series
+
+	registry  *prometheus.Registry
+	calls     *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+	sli       *prometheus.Desc
+	objective *prometheus.Desc
+	burnRate  *prometheus.Desc
+	budget    *prometheus.Desc
+}
+
+func NewTracker(cfg *config.Config) *Tracker {
+	t := &Tracker{
+		availability: cfg.SLO_AVAILABILITY_TARGET,
+		latency:      cfg.SLO_LATENCY_TARGET,
+		threshold:    cfg.SLO_LATENCY_THRESHOLD,
+		window:       cfg.SLO_WINDOW,
+		backends:     make(map[string]*series),
+		registry:     prometheus.NewRegistry(),
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "local_eats_backend_calls_total",
+			Help: "Calls to the backend services by gRPC status code.",
+		}, []string{"backend", "code"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "local_eats_backend_call_duration_seconds",
+			Help:    "How long calls to the backend services took.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"backend"}),
+		sli: prometheus.NewDesc("local_eats_slo_sli",
+			"Share of good calls to the backend over the SLO window.",
+			[]string{"backend", "slo"}, nil),
+		objective: prometheus.NewDesc("local_eats_slo_objective",
+			"Share of good calls the SLO aims for.",
+			[]string{"backend", "slo"}, nil),
+		burnRate: prometheus.NewDesc("local_eats_slo_burn_rate",
+			"How fast the error budget is spent over the window, 1 spending it exactly over the SLO window.",
+			[]string{"backend", "slo", "window"}, nil),
+		budget: prometheus.NewDesc("local_eats_slo_error_budget_remaining",
+			"Share of the error budget left over the SLO window, below zero once overspent.",
+			[]string{"backend", "slo"}, nil),
+	}
+	t.registry.MustRegister(t.calls, t.durations, t,
+		collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return t
+}
+
+// Observe is a gRPC client interceptor measuring the calls to the
+// services.
+func (t *Tracker) Observe(ctx context.Context, method string, req, reply any,
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	t.Record(backend(method), status.Code(err), time.Since(start), time.Now())
+	return err
+}
+
+// Record counts a call to backend that was answered with code after d.
+func (t *Tracker) Record(backend string, code codes.Code, d time.Duration, now time.Time) {
+	t.calls.WithLabelValues(backend, code.String()).Inc()
+	if code == codes.Canceled {
+		return
+	}
+	t.durations.WithLabelValues(backend).Observe(d.Seconds())
+
+	c := counts{calls: 1}
+	if failures[code] {
+		c.failed = 1
+	}
+	if d > t.threshold {
+		c.slow = 1
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.backends[backend]
+	if !ok {
+		s = &series{
+			minutes: newRing(time.Minute, burnWindows[len(burnWindows)-1].span),
+			hours:   newRing(time.Hour, t.window),
+		}
+		t.backends[backend] = s
+	}
+	s.minutes.add(now, c)
+	s.hours.add(now, c)
+}
+
+// Report tells where each backend called so far stands, by name.
+func (t *Tracker) Report(now time.Time) models.SLOReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	res := models.SLOReport{Window: t.window.String(), Backends: []models.BackendSLO{}}
+	for name, s := range t.backends {
+		total := s.hours.sum(now, t.window)
+		b := models.BackendSLO{
+			Backend:      name,
+			Availability: sloStatus(t.availability, total.calls, total.failed),
+			Latency:      sloStatus(t.latency, total.calls, total.slow),
+		}
+		for _, w := range burnWindows {
+			c := s.minutes.sum(now, w.span)
+			b.Availability.BurnRates = append(b.Availability.BurnRates,
+				models.BurnRate{Window: w.name, Rate: burn(t.availability, c.calls, c.failed)})
+			b.Latency.BurnRates = append(b.Latency.BurnRates,
+				models.BurnRate{Window: w.name, Rate: burn(t.latency, c.calls, c.slow)})
+		}
+		res.Backends = append(res.Backends, b)
+	}
+	slices.SortFunc(res.Backends, func(a, b models.BackendSLO) int {
+		return strings.Compare(a.Backend, b.Backend)
+	})
+	return res
+}
+
+// Handler serves the metrics to Prometheus.
+func (t *Tracker) Handler() http.Handler {
+	return promhttp.HandlerFor(t.registry, promhttp.HandlerOpts{})
+}
+
+// Describe and Collect export the SLOs, worked out on every scrape.
+func (t *Tracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.sli
+	ch <- t.objective
+	ch <- t.burnRate
+	ch <- t.budget
+}
+
+func (t *Tracker) Collect(ch chan<- prometheus.Metric) {
+	for _, b := range t.Report(time.Now()).Backends {
+		for slo, s := range map[string]models.SLOStatus{
+			models.SLOAvailability: b.Availability,
+			models.SLOLatency:      b.Latency,
+		} {
+			ch <- prometheus.MustNewConstMetric(t.sli, prometheus.GaugeValue, s.SLI, b.Backend, slo)
+			ch <- prometheus.MustNewConstMetric(t.objective, prometheus.GaugeValue, s.Objective, b.Backend, slo)
+			ch <- prometheus.MustNewConstMetric(t.budget, prometheus.GaugeValue, s.BudgetRemaining, b.Backend, slo)
+			for _, r := range s.BurnRates {
+				ch <- prometheus.MustNewConstMetric(t.burnRate, prometheus.GaugeValue, r.Rate, b.Backend, slo, r.Window)
+			}
+		}
+	}
+}
+
+// sloStatus works out an SLO of calls, bad of which were not good. With no
+// calls, the SLO is met and none of the budget is spent.
+func sloStatus(objective float64, calls, bad int64) models.SLOStatus {
+	s := models.SLOStatus{
+		Objective:       objective,
+		Calls:           calls,
+		Good:            calls - bad,
+		SLI:             1,
+		BudgetRemaining: 1,
+	}
+	if calls > 0 {
+		s.SLI = float64(s.Good) / float64(calls)
+		s.BudgetRemaining = 1 - burn(objective, calls, bad)
+	}
+	return s
+}
+
+// burn is the share of bad calls over the share the objective allows.
+func burn(objective float64, calls, bad int64) float64 {
+	if calls == 0 {
+		return 0
+	}
+	return float64(bad) / float64(calls) / (1 - objective)
+}
+
+// backend names the service of a gRPC method, "/order.Order/GetOrder"
+// being of the order service.
+func backend(method string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(method, "/"), ".")
+	return name
+}
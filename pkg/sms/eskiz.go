@@ -0,0 +1,184 @@
+package sms
+
+import (
+	"api-gateway/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Eskiz sends messages with the Eskiz notify API. Its bearer token comes
+// from logging in and is renewed when Eskiz stops accepting it. Delivery
+// reports are posted to the callback URL, which carries the secret.
+type Eskiz struct {
+	apiURL      string
+	email       string
+	password    string
+	from        string
+	callbackURL string
+	secret      string
+	client      *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+func NewEskiz(apiURL, email, password, from, callbackURL, secret string) *Eskiz {
+	if secret != "" {
+		callbackURL += "?" + url.Values{"secret": {secret}}.Encode()
+	}
+	return &Eskiz{
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		email:       email,
+		password:    password,
+		from:        from,
+		callbackURL: callbackURL,
+		secret:      secret,
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+func (e *Eskiz) Name() string {
+	return models.SMSEskiz
+}
+
+func (e *Eskiz) Send(ctx context.Context, id, to, text string) (string, error) {
+	form := url.Values{
+		"mobile_phone": {strings.TrimPrefix(to, "+")},
+		"message":      {text},
+		"from":         {e.from},
+		"callback_url": {e.callbackURL},
+	}
+
+	var out struct {
+		Id      eskizID `json:"id"`
+		Status  string  `json:"status"`
+		Message string  `json:"message"`
+	}
+	status, err := e.post(ctx, "/message/sms/send", form, &out)
+	if status == http.StatusUnauthorized {
+		// The token expired; log in again once.
+		e.mu.Lock()
+		e.token = ""
+		e.mu.Unlock()
+		status, err = e.post(ctx, "/message/sms/send", form, &out)
+	}
+	if err != nil {
+		return "", err
+	}
+	if status >= 300 || out.Id == "" {
+		return "", errors.Errorf("Eskiz returned %d: %s", status, out.Message)
+	}
+	return string(out.Id), nil
+}
+
+// eskizID is a message ID, which Eskiz sends as a string or a number.
+type eskizID string
+
+func (id *eskizID) UnmarshalJSON(b []byte) error {
+	*id = eskizID(strings.Trim(string(b), `"`))
+	return nil
+}
+
+func (e *Eskiz) post(ctx context.Context, path string, form url.Values, out any) (int, error) {
+	token, err := e.login(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.apiURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return resp.StatusCode, errors.New("Eskiz refused the token")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.StatusCode, errors.Wrap(err, "error decoding Eskiz response")
+	}
+	return resp.StatusCode, nil
+}
+
+func (e *Eskiz) login(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token != "" {
+		return e.token, nil
+	}
+
+	form := url.Values{"email": {e.email}, "password": {e.password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.apiURL+"/auth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Message string `json:"message"`
+		Data    struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", errors.Wrap(err, "error decoding Eskiz login response")
+	}
+	if resp.StatusCode != http.StatusOK || out.Data.Token == "" {
+		return "", errors.Errorf("Eskiz login returned %s: %s", resp.Status, out.Message)
+	}
+	e.token = out.Data.Token
+	return e.token, nil
+}
+
+// Callback decodes a report, sent as a form or JSON, whose status is an
+// SMPP delivery state such as DELIVRD or UNDELIV.
+func (e *Eskiz) Callback(r *http.Request) ([]Report, error) {
+	if err := checkSecret(r, e.secret); err != nil {
+		return nil, err
+	}
+
+	var in struct {
+		MessageId eskizID `json:"message_id"`
+		Status    string  `json:"status"`
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			return nil, errors.Wrap(ErrCallback, err.Error())
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return nil, errors.Wrap(ErrCallback, err.Error())
+		}
+		in.MessageId, in.Status = eskizID(r.PostForm.Get("message_id")), r.PostForm.Get("status")
+	}
+
+	rep := Report{ProviderId: string(in.MessageId)}
+	switch strings.ToUpper(in.Status) {
+	case "DELIVRD", "DELIVERED":
+		rep.Status = models.SMSDelivered
+	case "UNDELIV", "UNDELIVERED", "EXPIRED", "REJECTD", "REJECTED", "FAILED":
+		rep.Status, rep.Error = models.SMSFailed, strings.ToLower(in.Status)
+	default:
+		return nil, nil
+	}
+	return []Report{rep}, nil
+}
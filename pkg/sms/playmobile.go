@@ -0,0 +1,117 @@
+package sms
+
+import (
+	"api-gateway/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Playmobile sends messages with the Playmobile broker API, identified by
+// our own message IDs. Delivery reports are set up in the Playmobile
+// account to post to the callback URL with the callback secret.
+type Playmobile struct {
+	apiURL     string
+	login      string
+	password   string
+	originator string
+	secret     string
+	client     *http.Client
+}
+
+func NewPlaymobile(apiURL, login, password, originator, secret string) *Playmobile {
+	return &Playmobile{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		login:      login,
+		password:   password,
+		originator: originator,
+		secret:     secret,
+		client:     &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *Playmobile) Name() string {
+	return models.SMSPlaymobile
+}
+
+type playmobileMessage struct {
+	Recipient string `json:"recipient"`
+	MessageId string `json:"message-id"`
+	SMS       struct {
+		Originator string `json:"originator"`
+		Content    struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"sms"`
+}
+
+func (p *Playmobile) Send(ctx context.Context, id, to, text string) (string, error) {
+	// Message IDs are limited to 40 characters, so the UUID goes without
+	// dashes.
+	msgID := strings.ReplaceAll(id, "-", "")
+
+	m := playmobileMessage{Recipient: strings.TrimPrefix(to, "+"), MessageId: msgID}
+	m.SMS.Originator = p.originator
+	m.SMS.Content.Text = text
+	body, err := json.Marshal(map[string][]playmobileMessage{"messages": {m}})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL+"/send", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(p.login, p.password)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		out, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", errors.Errorf("Playmobile returned %s: %s", resp.Status, out)
+	}
+	return msgID, nil
+}
+
+type playmobileReport struct {
+	MessageId string `json:"message-id"`
+	Status    string `json:"status"`
+}
+
+func (p *Playmobile) Callback(r *http.Request) ([]Report, error) {
+	if err := checkSecret(r, p.secret); err != nil {
+		return nil, err
+	}
+
+	var in struct {
+		Messages []playmobileReport `json:"messages"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		return nil, errors.Wrap(ErrCallback, err.Error())
+	}
+
+	var reports []Report
+	for _, m := range in.Messages {
+		rep := Report{ProviderId: m.MessageId}
+		switch strings.ToLower(m.Status) {
+		case "delivered":
+			rep.Status = models.SMSDelivered
+		case "undelivered", "expired", "rejected", "failed":
+			rep.Status, rep.Error = models.SMSFailed, strings.ToLower(m.Status)
+		default:
+			continue
+		}
+		reports = append(reports, rep)
+	}
+	return reports, nil
+}
@@ -0,0 +1,224 @@
+package sms
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"api-gateway/storage"
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const timeout = 10 * time.Second
+
+var (
+	ErrDisabled        = errors.New("no SMS provider is configured")
+	ErrUnknownProvider = errors.New("unknown SMS provider")
+	ErrCallback        = errors.New("invalid delivery report")
+	ErrSignature       = errors.New("invalid callback signature")
+	ErrPhone           = errors.New("phone must be in international format, e.g. +998901234567")
+)
+
+var phonePattern = regexp.MustCompile(`^\+[1-9]\d{7,14}$`)
+
+// Provider sends text messages through one SMS gateway.
+type Provider interface {
+	Name() string
+	// Send sends text to the phone, given in E.164 format, and returns
+	// the gateway's ID of the message. id is the gateway-independent
+	// message ID.
+	Send(ctx context.Context, id, to, text string) (string, error)
+	// Callback authenticates and decodes the delivery reports the
+	// gateway posts.
+	Callback(r *http.Request) ([]Report, error)
+}
+
+// Report is the delivery status of a message as reported by its gateway.
+type Report struct {
+	ProviderId string
+	Status     string
+	Error      string
+}
+
+// Sender sends messages through the configured providers in order. When
+// a provider fails the next one is tried, so an outage of one gateway
+// doesn't stop codes and notifications.
+type Sender struct {
+	providers []Provider
+	store     *storage.Store[models.SMSMessage]
+	logger    *slog.Logger
+}
+
+func NewSender(cfg *config.Config, store *storage.Store[models.SMSMessage], logger *slog.Logger) *Sender {
+	s := &Sender{store: store, logger: logger}
+
+	callback := strings.TrimSuffix(cfg.SMS_CALLBACK_URL, "/")
+	all := map[string]Provider{}
+	if cfg.ESKIZ_EMAIL != "" {
+		all[models.SMSEskiz] = NewEskiz(cfg.ESKIZ_API_URL, cfg.ESKIZ_EMAIL, cfg.ESKIZ_PASSWORD,
+			cfg.ESKIZ_FROM, callback+"/"+models.SMSEskiz, cfg.SMS_CALLBACK_SECRET)
+	}
+	if cfg.PLAYMOBILE_LOGIN != "" {
+		all[models.SMSPlaymobile] = NewPlaymobile(cfg.PLAYMOBILE_API_URL, cfg.PLAYMOBILE_LOGIN,
+			cfg.PLAYMOBILE_PASSWORD, cfg.PLAYMOBILE_ORIGINATOR, cfg.SMS_CALLBACK_SECRET)
+	}
+	if cfg.TWILIO_ACCOUNT_SID != "" {
+		all[models.SMSTwilio] = NewTwilio(cfg.TWILIO_API_URL, cfg.TWILIO_ACCOUNT_SID,
+			cfg.TWILIO_AUTH_TOKEN, cfg.TWILIO_FROM, callback+"/"+models.SMSTwilio)
+	}
+
+	order := []string{models.SMSEskiz, models.SMSPlaymobile, models.SMSTwilio}
+	if cfg.SMS_PROVIDERS != "" {
+		order = strings.Split(cfg.SMS_PROVIDERS, ",")
+	}
+	for _, name := range order {
+		name = strings.TrimSpace(name)
+		if p, ok := all[name]; ok {
+			s.providers = append(s.providers, p)
+		} else {
+			logger.Error(errors.Wrapf(ErrUnknownProvider, "%q is not configured", name).Error())
+		}
+	}
+	return s
+}
+
+func (s *Sender) Enabled() bool {
+	return len(s.providers) > 0
+}
+
+// Names lists the providers in the order they are tried.
+func (s *Sender) Names() []string {
+	names := make([]string, len(s.providers))
+	for i, p := range s.providers {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Send sends text to the phone and records the message. The message is
+// stored even when every provider failed, with the failures in its
+// attempts.
+func (s *Sender) Send(ctx context.Context, to, text, purpose, campaign string) (models.SMSMessage, error) {
+	if !s.Enabled() {
+		return models.SMSMessage{}, ErrDisabled
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	m := models.SMSMessage{
+		Id:        uuid.NewString(),
+		To:        to,
+		Text:      text,
+		Purpose:   purpose,
+		Campaign:  campaign,
+		Attempts:  []models.SMSAttempt{},
+		CreatedAt: now,
+	}
+
+	var err error
+	for _, p := range s.providers {
+		var providerID string
+		providerID, err = p.Send(ctx, m.Id, to, text)
+		if err == nil {
+			m.Provider, m.ProviderId, m.Status = p.Name(), providerID, models.SMSSent
+			break
+		}
+		m.Attempts = append(m.Attempts, models.SMSAttempt{
+			Provider: p.Name(),
+			Error:    err.Error(),
+			At:       time.Now().Format(time.RFC3339),
+		})
+		s.logger.Error(errors.Wrapf(err, "error sending SMS with %s", p.Name()).Error())
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if m.Status == "" {
+		m.Status, m.Error = models.SMSFailed, err.Error()
+	}
+	m.UpdatedAt = time.Now().Format(time.RFC3339)
+	s.store.Set(m.Id, m)
+
+	if m.Status == models.SMSFailed {
+		return m, errors.Wrap(err, "every SMS provider failed")
+	}
+	return m, nil
+}
+
+// Callback applies the delivery reports posted by the provider to the
+// messages they are about.
+func (s *Sender) Callback(r *http.Request, provider string) error {
+	var p Provider
+	for _, sp := range s.providers {
+		if sp.Name() == provider {
+			p = sp
+		}
+	}
+	if p == nil {
+		return errors.Wrapf(ErrUnknownProvider, "%q", provider)
+	}
+
+	reports, err := p.Callback(r)
+	if err != nil {
+		return err
+	}
+
+	for _, rep := range reports {
+		m, ok := s.byProviderID(provider, rep.ProviderId)
+		if !ok {
+			s.logger.Error("delivery report for unknown SMS " + rep.ProviderId)
+			continue
+		}
+		s.store.Update(m.Id, func(m models.SMSMessage, _ bool) models.SMSMessage {
+			// Reports may arrive out of order; a final status stays.
+			if m.Status == models.SMSSent {
+				m.Status, m.Error = rep.Status, rep.Error
+				m.UpdatedAt = time.Now().Format(time.RFC3339)
+			}
+			return m
+		})
+	}
+	return nil
+}
+
+func (s *Sender) byProviderID(provider, id string) (models.SMSMessage, bool) {
+	if id == "" {
+		return models.SMSMessage{}, false
+	}
+	for _, m := range s.store.List() {
+		if m.Provider == provider && m.ProviderId == id {
+			return m, true
+		}
+	}
+	return models.SMSMessage{}, false
+}
+
+// Normalize returns the phone in E.164 format, dropping the spaces,
+// dashes and brackets people write numbers with.
+func Normalize(phone string) (string, error) {
+	phone = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" -()", r) {
+			return -1
+		}
+		return r
+	}, phone)
+	if !phonePattern.MatchString(phone) {
+		return "", ErrPhone
+	}
+	return phone, nil
+}
+
+// checkSecret authenticates callbacks of gateways that don't sign them,
+// which are told to call back with the secret in the query.
+func checkSecret(r *http.Request, secret string) error {
+	if secret == "" || subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("secret")), []byte(secret)) != 1 {
+		return errors.Wrap(ErrSignature, "wrong secret")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package sms
+
+import (
+	"api-gateway/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Twilio sends messages with the Twilio Messages API. Twilio posts status
+// changes to the callback URL, signed with the auth token.
+type Twilio struct {
+	apiURL      string
+	accountSID  string
+	authToken   string
+	from        string
+	callbackURL string
+	client      *http.Client
+}
+
+func NewTwilio(apiURL, accountSID, authToken, from, callbackURL string) *Twilio {
+	return &Twilio{
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		accountSID:  accountSID,
+		authToken:   authToken,
+		from:        from,
+		callbackURL: callbackURL,
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+func (t *Twilio) Name() string {
+	return models.SMSTwilio
+}
+
+type twilioMessage struct {
+	Sid     string `json:"sid"`
+	Message string `json:"message"`
+}
+
+func (t *Twilio) Send(ctx context.Context, id, to, text string) (string, error) {
+	form := url.Values{
+		"To":             {to},
+		"From":           {t.from},
+		"Body":           {text},
+		"StatusCallback": {t.callbackURL},
+	}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", t.apiURL, t.accountSID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(t.accountSID, t.authToken)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out twilioMessage
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", errors.Wrap(err, "error decoding Twilio response")
+	}
+	if resp.StatusCode >= 300 {
+		return "", errors.Errorf("Twilio returned %s: %s", resp.Status, out.Message)
+	}
+	return out.Sid, nil
+}
+
+// Callback checks the X-Twilio-Signature header: the base64 HMAC-SHA1,
+// keyed with the auth token, of the callback URL followed by every form
+// field's name and value in name order.
+func (t *Twilio) Callback(r *http.Request) ([]Report, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, errors.Wrap(ErrCallback, err.Error())
+	}
+
+	keys := make([]string, 0, len(r.PostForm))
+	for k := range r.PostForm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	mac := hmac.New(sha1.New, []byte(t.authToken))
+	mac.Write([]byte(t.callbackURL))
+	for _, k := range keys {
+		mac.Write([]byte(k + r.PostForm.Get(k)))
+	}
+	want := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(r.Header.Get("X-Twilio-Signature"))) {
+		return nil, ErrSignature
+	}
+
+	rep := Report{ProviderId: r.PostForm.Get("MessageSid")}
+	switch r.PostForm.Get("MessageStatus") {
+	case "delivered":
+		rep.Status = models.SMSDelivered
+	case "undelivered", "failed":
+		rep.Status = models.SMSFailed
+		rep.Error = "error code " + r.PostForm.Get("ErrorCode")
+	default:
+		// Queued and sent change nothing.
+		return nil, nil
+	}
+	return []Report{rep}, nil
+}
@@ -0,0 +1,166 @@
+// Package stock keeps the daily portion counts of dishes and reserves
+// them for orders.
+package stock
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Restocker is told about the dishes that sold out yesterday and have
+// portions again now that a new day has started.
+type Restocker func(dishIDs []string)
+
+type Manager struct {
+	// mu makes reserving the dishes of an order all or nothing.
+	mu      sync.Mutex
+	stocks  *storage.Store[models.DishStock]
+	restock Restocker
+	logger  *slog.Logger
+}
+
+// NewManager starts restocking dishes at midnight UTC.
+func NewManager(stocks *storage.Store[models.DishStock], restock Restocker, logger *slog.Logger) *Manager {
+	m := &Manager{
+		stocks:  stocks,
+		restock: restock,
+		logger:  logger,
+	}
+
+	go func() {
+		for {
+			now := time.Now().UTC()
+			midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+			time.Sleep(midnight.Sub(now))
+			m.Restock(time.Now())
+		}
+	}()
+
+	return m
+}
+
+// Get returns the stock of the dish as of now.
+func (m *Manager) Get(dishID string, now time.Time) (models.DishStock, bool) {
+	s, ok := m.stocks.Get(dishID)
+	return Current(s, now), ok
+}
+
+// List returns the stocks of the kitchen's dishes as of now.
+func (m *Manager) List(kitchenID string, now time.Time) []models.DishStock {
+	res := []models.DishStock{}
+	for _, s := range m.stocks.List() {
+		if s.KitchenId == kitchenID {
+			res = append(res, Current(s, now))
+		}
+	}
+	return res
+}
+
+// Set changes the daily count of the dish, keeping what was sold today.
+func (m *Manager) Set(s models.DishStock, now time.Time) models.DishStock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.stocks.Update(s.DishId, func(cur models.DishStock, _ bool) models.DishStock {
+		cur = Current(cur, now)
+		s.Sold, s.Date = cur.Sold, cur.Date
+		s.UpdatedAt = now.Format(time.RFC3339)
+		return Current(s, now)
+	})
+}
+
+func (m *Manager) Delete(dishID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.stocks.Delete(dishID)
+}
+
+// Reserve takes the quantities, keyed by dish ID, from today's stock. If
+// any dish has too few portions left, nothing is taken and the error is
+// a *models.StockError. It returns the dishes that sold out.
+func (m *Manager) Reserve(quantities map[string]int32, now time.Time) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var limited []string
+	for id, qty := range quantities {
+		s, ok := m.stocks.Get(id)
+		if !ok {
+			continue
+		}
+		if s = Current(s, now); s.Remaining < qty {
+			return nil, &models.StockError{DishId: id, DishName: s.DishName, Remaining: s.Remaining}
+		}
+		limited = append(limited, id)
+	}
+
+	var soldOut []string
+	for _, id := range limited {
+		if m.change(id, quantities[id], now).Remaining == 0 {
+			soldOut = append(soldOut, id)
+		}
+	}
+	return soldOut, nil
+}
+
+// Release gives back portions reserved at the given time, e.g. for an
+// order the order service refused. Portions of a past day are not given
+// back.
+func (m *Manager) Release(quantities map[string]int32, reserved, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if Today(reserved) != Today(now) {
+		return
+	}
+
+	for id, qty := range quantities {
+		if _, ok := m.stocks.Get(id); ok {
+			m.change(id, -qty, now)
+		}
+	}
+}
+
+// Restock tells about the dishes that sold out on the previous day.
+func (m *Manager) Restock(now time.Time) {
+	yesterday := Today(now.AddDate(0, 0, -1))
+
+	var ids []string
+	for _, s := range m.stocks.List() {
+		if s.Date == yesterday && s.Remaining == 0 && s.DailyCount > 0 {
+			ids = append(ids, s.DishId)
+		}
+	}
+	if len(ids) > 0 {
+		m.logger.Info("restocking sold out dishes")
+		m.restock(ids)
+	}
+}
+
+// change adds qty to what was sold today. Callers hold mu.
+func (m *Manager) change(dishID string, qty int32, now time.Time) models.DishStock {
+	return m.stocks.Update(dishID, func(s models.DishStock, _ bool) models.DishStock {
+		s = Current(s, now)
+		s.Sold = max(s.Sold+qty, 0)
+		return Current(s, now)
+	})
+}
+
+// Current starts the stock over if it was last counted on another day
+// and fills in what remains.
+func Current(s models.DishStock, now time.Time) models.DishStock {
+	if today := Today(now); s.Date != today {
+		s.Date, s.Sold = today, 0
+	}
+	s.Remaining = max(s.DailyCount-s.Sold, 0)
+	return s
+}
+
+// Today is the UTC date stocks are counted by.
+func Today(now time.Time) string {
+	return now.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,96 @@
+// Package timezone keeps the time zone of every kitchen, in which its
+// working hours, statistics and scheduled orders are read.
+package timezone
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"strings"
+	"sync"
+	"time"
+	// The zone database is built in, so zones load on hosts without one.
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalid = errors.New("invalid time zone")
+
+// Zones keeps the kitchens' time zones. Kitchens without one of their own
+// are in the fallback zone.
+type Zones struct {
+	store    *storage.Store[models.KitchenTimeZone]
+	fallback *time.Location
+	// locations caches the loaded zones by name.
+	locations sync.Map
+}
+
+// NewZones keeps the zones in store, with kitchens in fallback unless
+// they set theirs.
+func NewZones(store *storage.Store[models.KitchenTimeZone], fallback *time.Location) *Zones {
+	return &Zones{store: store, fallback: fallback}
+}
+
+// Load returns the zone of an IANA name such as "Asia/Tashkent".
+func Load(name string) (*time.Location, error) {
+	// LoadLocation takes "" and "Local" for the host's zone, which is not
+	// a kitchen's.
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.Wrap(ErrInvalid, "time zone is required")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, errors.Wrapf(ErrInvalid, "unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// Get returns the time zone of the kitchen, the fallback one unless it
+// set its own.
+func (z *Zones) Get(kitchenID string) models.KitchenTimeZone {
+	tz, ok := z.store.Get(kitchenID)
+	if !ok {
+		return models.KitchenTimeZone{KitchenId: kitchenID, TimeZone: z.fallback.String(), Default: true}
+	}
+	return tz
+}
+
+// Set sets the time zone of the kitchen on behalf of userID.
+func (z *Zones) Set(kitchenID, name, userID string, now time.Time) (models.KitchenTimeZone, error) {
+	loc, err := Load(name)
+	if err != nil {
+		return models.KitchenTimeZone{}, err
+	}
+	tz := models.KitchenTimeZone{
+		KitchenId: kitchenID,
+		TimeZone:  loc.String(),
+		UpdatedBy: userID,
+		UpdatedAt: now.Format(time.RFC3339),
+	}
+	z.store.Set(kitchenID, tz)
+	return tz, nil
+}
+
+// Default returns the zone of kitchens that have not set one, which is
+// also the gateway's own.
+func (z *Zones) Default() *time.Location {
+	return z.fallback
+}
+
+// Of returns the location of the kitchen's time zone.
+func (z *Zones) Of(kitchenID string) *time.Location {
+	tz, ok := z.store.Get(kitchenID)
+	if !ok {
+		return z.fallback
+	}
+	if loc, ok := z.locations.Load(tz.TimeZone); ok {
+		return loc.(*time.Location)
+	}
+	// Zones were checked when set.
+	loc, err := time.LoadLocation(tz.TimeZone)
+	if err != nil {
+		return z.fallback
+	}
+	z.locations.Store(tz.TimeZone, loc)
+	return loc
+}
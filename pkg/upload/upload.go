@@ -0,0 +1,289 @@
+package upload
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/envelope"
+	"api-gateway/storage"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNotFound       = errors.New("upload not found")
+	ErrInvalid        = errors.New("invalid upload")
+	ErrOffsetMismatch = errors.New("chunk does not start at the upload offset")
+)
+
+// Manager stores resumable uploads on local disk. A file is written in
+// chunks that must arrive in order; a client that lost its connection
+// asks for the current offset and continues from there.
+// With a keyring, every chunk is sealed on its own and the file is a run
+// of sealed chunks, each after its 4-byte length, so no part of an
+// upload is ever on disk in the clear.
+type Manager struct {
+	dir     string
+	maxSize int64
+	uploads *storage.Store[models.Upload]
+	keys    *envelope.Keyring
+	mu      sync.Mutex
+}
+
+// NewManager keeps uploads in dir. keys may be nil to store them in the
+// clear.
+func NewManager(dir string, maxSize int64, uploads *storage.Store[models.Upload], keys *envelope.Keyring) *Manager {
+	return &Manager{
+		dir:     dir,
+		maxSize: maxSize,
+		uploads: uploads,
+		keys:    keys,
+	}
+}
+
+// Create opens an upload for the user.
+func (m *Manager) Create(userID string, data models.NewUpload) (models.Upload, error) {
+	types, ok := models.UploadTypes[data.Purpose]
+	if !ok {
+		return models.Upload{}, errors.Wrapf(ErrInvalid, "unknown purpose %q", data.Purpose)
+	}
+	if !slices.Contains(types, data.ContentType) {
+		return models.Upload{}, errors.Wrapf(ErrInvalid, "content type %q is not allowed for %s", data.ContentType, data.Purpose)
+	}
+	if data.Size <= 0 || data.Size > m.maxSize {
+		return models.Upload{}, errors.Wrapf(ErrInvalid, "size must be between 1 and %d bytes", m.maxSize)
+	}
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return models.Upload{}, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	u := models.Upload{
+		Id:          uuid.NewString(),
+		UserId:      userID,
+		Purpose:     data.Purpose,
+		Filename:    filepath.Base(data.Filename),
+		ContentType: data.ContentType,
+		Size:        data.Size,
+		Status:      models.UploadInProgress,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	f, err := os.OpenFile(m.Path(u.Id), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return models.Upload{}, err
+	}
+	f.Close()
+
+	m.uploads.Set(u.Id, u)
+	return u, nil
+}
+
+func (m *Manager) Get(id string) (models.Upload, error) {
+	u, ok := m.uploads.Get(id)
+	if !ok {
+		return models.Upload{}, ErrNotFound
+	}
+	return u, nil
+}
+
+// Append writes a chunk that starts at offset start and returns the
+// updated upload. Chunks past the declared size are rejected.
+func (m *Manager) Append(id string, start int64, chunk io.Reader) (models.Upload, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.uploads.Get(id)
+	if !ok {
+		return models.Upload{}, ErrNotFound
+	}
+	if u.Status == models.UploadCompleted {
+		return u, errors.Wrap(ErrInvalid, "upload is already completed")
+	}
+	if start != u.Offset {
+		return u, ErrOffsetMismatch
+	}
+
+	// Read one byte more than is left to notice oversized chunks.
+	left := u.Size - u.Offset
+	var (
+		n   int64
+		err error
+	)
+	if m.keys != nil {
+		n, err = m.appendSealed(id, io.LimitReader(chunk, left+1), left)
+	} else {
+		n, err = m.appendPlain(id, u.Offset, io.LimitReader(chunk, left+1), left)
+	}
+	if n > left {
+		return u, errors.Wrap(ErrInvalid, "chunk exceeds the upload size")
+	}
+
+	// Whatever arrived before a broken connection is kept, so the client
+	// can resume from the new offset.
+	u.Offset += n
+	u.UpdatedAt = time.Now().Format(time.RFC3339)
+	if u.Offset == u.Size {
+		u.Status = models.UploadCompleted
+	}
+	m.uploads.Set(id, u)
+
+	return u, err
+}
+
+// appendPlain writes chunk at offset and returns how much of it was
+// written. A chunk longer than left is taken back.
+func (m *Manager) appendPlain(id string, offset int64, chunk io.Reader, left int64) (int64, error) {
+	f, err := os.OpenFile(m.Path(id), os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, chunk)
+	if n > left {
+		f.Truncate(offset)
+	}
+	return n, err
+}
+
+// appendSealed seals chunk and adds it to the end of the file. A chunk
+// longer than left is not added.
+func (m *Manager) appendSealed(id string, chunk io.Reader, left int64) (int64, error) {
+	data, readErr := io.ReadAll(chunk)
+	n := int64(len(data))
+	if n > left || n == 0 {
+		return n, readErr
+	}
+
+	sealed, err := m.keys.Seal(data)
+	if err != nil {
+		return 0, errors.Wrap(err, "error sealing chunk")
+	}
+	f, err := os.OpenFile(m.Path(id), os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	end, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Write(record(sealed)); err != nil {
+		// A chunk is added whole or not at all.
+		f.Truncate(end)
+		return 0, err
+	}
+	return n, readErr
+}
+
+// Open opens the file of an upload for reading.
+func (m *Manager) Open(id string) (io.ReadSeekCloser, error) {
+	if m.keys == nil {
+		return os.Open(m.Path(id))
+	}
+	data, err := m.read(id)
+	if err != nil {
+		return nil, err
+	}
+	return nopCloser{bytes.NewReader(data)}, nil
+}
+
+// read opens the sealed chunks of an upload.
+func (m *Manager) read(id string) ([]byte, error) {
+	file, err := os.ReadFile(m.Path(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	for len(file) > 0 {
+		if len(file) < 4 || int(binary.BigEndian.Uint32(file)) > len(file)-4 {
+			return nil, errors.Wrap(envelope.ErrMalformed, "truncated upload chunk")
+		}
+		size := int(binary.BigEndian.Uint32(file))
+		chunk, err := m.keys.Open(file[4 : 4+size])
+		if err != nil {
+			return nil, errors.Wrap(err, "error opening upload chunk")
+		}
+		data = append(data, chunk...)
+		file = file[4+size:]
+	}
+	return data, nil
+}
+
+// Reseal seals the files of every upload again with the current data key
+// and returns how many it resealed.
+func (m *Manager) Reseal() (int, error) {
+	if m.keys == nil {
+		return 0, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for _, id := range m.uploads.Keys() {
+		data, err := m.read(id)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return n, errors.Wrapf(err, "error reading upload %s", id)
+		}
+		// An empty upload has no chunks to seal.
+		var file []byte
+		if len(data) > 0 {
+			sealed, err := m.keys.Seal(data)
+			if err != nil {
+				return n, errors.Wrapf(err, "error sealing upload %s", id)
+			}
+			file = record(sealed)
+		}
+		tmp := m.Path(id) + ".tmp"
+		if err := os.WriteFile(tmp, file, 0600); err != nil {
+			return n, err
+		}
+		if err := os.Rename(tmp, m.Path(id)); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (m *Manager) Delete(id string) error {
+	if !m.uploads.Delete(id) {
+		return ErrNotFound
+	}
+	return os.Remove(m.Path(id))
+}
+
+func (m *Manager) Path(id string) string {
+	return filepath.Join(m.dir, id)
+}
+
+// record is a sealed chunk as kept in the file.
+func record(sealed []byte) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(sealed))), sealed...)
+}
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
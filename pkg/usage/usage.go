@@ -0,0 +1,185 @@
+// Package usage counts the calls of every route by app version, so the
+// routes old app versions still rely on are known before they are
+// removed.
+package usage
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/version"
+	"api-gateway/storage"
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recorder counts calls by day, route and app version, keeping the days
+// of the retention.
+type Recorder struct {
+	store     *storage.Store[models.APIUsage]
+	retention int
+
+	mu     sync.Mutex
+	pruned string
+}
+
+// NewRecorder keeps retention days of counts in store.
+func NewRecorder(store *storage.Store[models.APIUsage], retention int) *Recorder {
+	return &Recorder{store: store, retention: retention}
+}
+
+// Record counts a call of route by app, answered with status.
+func (r *Recorder) Record(method, route string, app models.ClientApp, status int, now time.Time) {
+	now = now.UTC()
+	day := now.Format(time.DateOnly)
+	r.prune(now)
+
+	key := strings.Join([]string{day, method, route, app.Platform, app.Version}, "|")
+	r.store.Update(key, func(u models.APIUsage, ok bool) models.APIUsage {
+		if !ok {
+			u = models.APIUsage{Date: day, Method: method, Route: route,
+				Platform: app.Platform, Version: app.Version}
+		}
+		u.Calls++
+		switch {
+		case status == http.StatusUpgradeRequired:
+			u.ClientErrors++
+			u.Upgrades++
+		case status >= 500:
+			u.ServerErrors++
+		case status >= 400:
+			u.ClientErrors++
+		}
+		u.LastSeenAt = now.Format(time.RFC3339)
+		return u
+	})
+}
+
+// prune drops the days past the retention, once a day.
+func (r *Recorder) prune(now time.Time) {
+	day := now.Format(time.DateOnly)
+	r.mu.Lock()
+	if r.pruned == day {
+		r.mu.Unlock()
+		return
+	}
+	r.pruned = day
+	r.mu.Unlock()
+
+	// Keys start with the date, which sorts as a string.
+	oldest := now.AddDate(0, 0, 1-r.retention).Format(time.DateOnly)
+	for _, key := range r.store.Keys() {
+		if key[:len(oldest)] < oldest {
+			r.store.Delete(key)
+		}
+	}
+}
+
+// Report sums the counts f selects by route, and by app version within
+// each route.
+func (r *Recorder) Report(f models.UsageFilter) models.APIUsageReport {
+	routes := make(map[string]*models.RouteUsage)
+	versions := make(map[string]map[string]*models.VersionUsage)
+	for _, u := range r.store.List() {
+		if !matches(f, u) {
+			continue
+		}
+
+		key := u.Method + " " + u.Route
+		ru, ok := routes[key]
+		if !ok {
+			ru = &models.RouteUsage{Method: u.Method, Route: u.Route}
+			routes[key] = ru
+			versions[key] = make(map[string]*models.VersionUsage)
+		}
+		vu, ok := versions[key][u.Platform+"|"+u.Version]
+		if !ok {
+			vu = &models.VersionUsage{Platform: u.Platform, Version: u.Version}
+			versions[key][u.Platform+"|"+u.Version] = vu
+		}
+
+		ru.Calls += u.Calls
+		ru.ClientErrors += u.ClientErrors
+		ru.ServerErrors += u.ServerErrors
+		ru.Upgrades += u.Upgrades
+		ru.LastSeenAt = max(ru.LastSeenAt, u.LastSeenAt)
+		add(vu, u)
+	}
+
+	res := models.APIUsageReport{From: f.From, To: f.To, Routes: make([]models.RouteUsage, 0, len(routes))}
+	for key, ru := range routes {
+		ru.ErrorRate = errorRate(ru.Calls, ru.ServerErrors)
+		for _, vu := range versions[key] {
+			vu.ErrorRate = errorRate(vu.Calls, vu.ServerErrors)
+			ru.Versions = append(ru.Versions, *vu)
+		}
+		slices.SortFunc(ru.Versions, func(a, b models.VersionUsage) int {
+			return cmp.Or(cmp.Compare(b.Calls, a.Calls), strings.Compare(a.Platform, b.Platform),
+				version.Compare(b.Version, a.Version))
+		})
+		res.Routes = append(res.Routes, *ru)
+	}
+	slices.SortFunc(res.Routes, func(a, b models.RouteUsage) int {
+		return cmp.Or(cmp.Compare(b.Calls, a.Calls), strings.Compare(a.Route, b.Route),
+			strings.Compare(a.Method, b.Method))
+	})
+	return res
+}
+
+// Versions sums the counts f selects by app version, across routes, by
+// platform and newest first.
+func (r *Recorder) Versions(f models.UsageFilter) []models.VersionUsage {
+	versions := make(map[string]*models.VersionUsage)
+	for _, u := range r.store.List() {
+		if !matches(f, u) {
+			continue
+		}
+		vu, ok := versions[u.Platform+"|"+u.Version]
+		if !ok {
+			vu = &models.VersionUsage{Platform: u.Platform, Version: u.Version}
+			versions[u.Platform+"|"+u.Version] = vu
+		}
+		add(vu, u)
+	}
+
+	res := make([]models.VersionUsage, 0, len(versions))
+	for _, vu := range versions {
+		vu.ErrorRate = errorRate(vu.Calls, vu.ServerErrors)
+		res = append(res, *vu)
+	}
+	slices.SortFunc(res, func(a, b models.VersionUsage) int {
+		return cmp.Or(strings.Compare(a.Platform, b.Platform), version.Compare(b.Version, a.Version))
+	})
+	return res
+}
+
+func add(vu *models.VersionUsage, u models.APIUsage) {
+	vu.Calls += u.Calls
+	vu.ClientErrors += u.ClientErrors
+	vu.ServerErrors += u.ServerErrors
+	vu.Upgrades += u.Upgrades
+	vu.LastSeenAt = max(vu.LastSeenAt, u.LastSeenAt)
+}
+
+func matches(f models.UsageFilter, u models.APIUsage) bool {
+	switch {
+	case f.From != "" && u.Date < f.From, f.To != "" && u.Date > f.To:
+		return false
+	case f.Route != "" && u.Route != f.Route:
+		return false
+	case f.Platform != "" && u.Platform != f.Platform:
+		return false
+	case f.Below != "" && (u.Version == models.AppUnknown || version.Compare(u.Version, f.Below) >= 0):
+		return false
+	}
+	return true
+}
+
+func errorRate(calls, errors int64) float64 {
+	if calls == 0 {
+		return 0
+	}
+	return float64(errors) / float64(calls)
+}
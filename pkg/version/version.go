@@ -0,0 +1,44 @@
+// Package version compares the dotted versions of the API and the apps.
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// appVersion is a version the apps send, "2.3.1" or "2.4.0-beta.1".
+var appVersion = regexp.MustCompile(`^[0-9]{1,4}(\.[0-9]{1,4}){0,3}(-[0-9A-Za-z.]{1,16})?$`)
+
+// Valid reports whether v is a version as the apps send them, "2.3.1" or
+// "2.4.0-beta.1".
+func Valid(v string) bool {
+	return appVersion.MatchString(v)
+}
+
+// Compare compares dotted versions part by part, numerically where both
+// parts are numbers, so 1.10 comes after 1.9; missing parts count as 0.
+func Compare(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case (xerr != nil || yerr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPrivateAddress is returned for webhook URLs that lead into the
+// gateway's own network, which deliveries must not reach.
+var ErrPrivateAddress = errors.New("webhook URL must lead to a public address")
+
+// sharedAddressSpace is the carrier-grade NAT range, private in all but
+// name.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// CheckURL resolves the host of a webhook URL and refuses it unless every
+// address it has is public. Deliveries are checked again as they connect,
+// since the host may resolve elsewhere by then.
+func CheckURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return errors.Wrap(err, "error resolving webhook host")
+	}
+	for _, addr := range addrs {
+		if !public(addr) {
+			return ErrPrivateAddress
+		}
+	}
+	return nil
+}
+
+// public tells whether addr is routable on the internet: not loopback,
+// link-local, private, multicast or unspecified.
+func public(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// newClient is the client deliveries are sent with. It refuses to connect
+// to addresses that are not public, whether the URL names them, its host
+// resolves to them or a redirect leads there. Proxies are not used, so
+// the address checked is the one connected to.
+func newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !public(addr.Addr()) {
+				return ErrPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"api-gateway/models"
+	"api-gateway/storage"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxAttempts   = 3
+	maxDeliveries = 100
+	timeout       = 5 * time.Second
+)
+
+// Dispatcher delivers kitchen events to the webhooks registered for them.
+// Every request is signed with the webhook's secret: the
+// X-Webhook-Signature header holds "sha256=" and the hex HMAC-SHA256 of
+// "<X-Webhook-Timestamp>.<body>". Deliveries only connect to public
+// addresses.
+type Dispatcher struct {
+	storage *storage.Storage
+	client  *http.Client
+	logger  *slog.Logger
+	backoff time.Duration
+}
+
+func NewDispatcher(s *storage.Storage, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		storage: s,
+		client:  newClient(),
+		logger:  logger,
+		backoff: time.Second,
+	}
+}
+
+type envelope struct {
+	Id        string `json:"id"`
+	Event     string `json:"event"`
+	KitchenId string `json:"kitchen_id"`
+	CreatedAt string `json:"created_at"`
+	Data      any    `json:"data"`
+}
+
+// Dispatch sends the event to every webhook of the kitchen subscribed to
+// it. Deliveries run in the background and are retried with exponential
+// backoff.
+func (d *Dispatcher) Dispatch(kitchenID, event string, data any) {
+	for _, w := range d.storage.Webhooks.List() {
+		if w.KitchenId == kitchenID && slices.Contains(w.Events, event) {
+			d.Send(w, event, data)
+		}
+	}
+}
+
+// Send delivers the event to w alone, like Dispatch, whatever events w
+// is subscribed to.
+func (d *Dispatcher) Send(w models.Webhook, event string, data any) {
+	body, err := json.Marshal(envelope{
+		Id:        uuid.NewString(),
+		Event:     event,
+		KitchenId: w.KitchenId,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Data:      data,
+	})
+	if err != nil {
+		d.logger.Error(errors.Wrap(err, "error encoding webhook event").Error())
+		return
+	}
+
+	go d.deliver(w, event, body)
+}
+
+func (d *Dispatcher) deliver(w models.Webhook, event string, body []byte) {
+	delivery := models.WebhookDelivery{
+		Id:        uuid.NewString(),
+		WebhookId: w.Id,
+		Event:     event,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		delivery.Attempts = attempt
+
+		code, err := d.send(w, event, delivery.Id, body)
+		delivery.StatusCode = code
+		if err == nil {
+			delivery.Success = true
+			delivery.Error = ""
+			break
+		}
+
+		delivery.Error = err.Error()
+		d.logger.Error(errors.Wrapf(err, "webhook %s attempt %d failed", w.Id, attempt).Error())
+
+		if attempt < maxAttempts {
+			time.Sleep(d.backoff << (attempt - 1))
+		}
+	}
+
+	d.storage.WebhookDeliveries.Update(w.Id, func(log []models.WebhookDelivery, _ bool) []models.WebhookDelivery {
+		log = append(log, delivery)
+		if len(log) > maxDeliveries {
+			log = log[len(log)-maxDeliveries:]
+		}
+		return log
+	})
+}
+
+func (d *Dispatcher) send(w models.Webhook, event, deliveryID string, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event)
+	req.Header.Set("X-Webhook-Delivery", deliveryID)
+	req.Header.Set("X-Webhook-Timestamp", ts)
+	req.Header.Set("X-Webhook-Signature", Sign(w.Secret, ts, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature receivers use to verify a delivery:
+// "sha256=" followed by the hex HMAC-SHA256, keyed by the secret, of
+// "<timestamp>.<body>".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
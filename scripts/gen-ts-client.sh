@@ -0,0 +1,23 @@
+#!/bin/bash
+# Generates the TypeScript client from the gateway's OpenAPI spec, a file
+# such as a snapshot under openapi/ or the /openapi.json of a running
+# gateway, and publishes it to npm when PUBLISH=1.
+#   scripts/gen-ts-client.sh openapi/1.0.json
+set -e
+SPEC=${1:-http://localhost:8080/openapi.json}
+OUT=sdk/typescript
+
+go run ./cmd/tsclient -spec ${SPEC} -out ${OUT}/src/index.ts
+VERSION=$(sed -n 's/^export const API_VERSION = "\(.*\)";$/\1/p' ${OUT}/src/index.ts)
+# npm wants three-part versions: 1.0 is published as 1.0.0.
+case ${VERSION} in
+  *.*.*) ;;
+  *) VERSION=${VERSION}.0 ;;
+esac
+cd ${OUT}
+npm version --no-git-tag-version --allow-same-version ${VERSION}
+npm install
+npm run build
+if [ "${PUBLISH}" = "1" ]; then
+  npm publish
+fi
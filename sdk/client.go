@@ -0,0 +1,284 @@
+// Package sdk is the Go client of the gateway, for internal tools and
+// services calling it over HTTP. It signs requests with the caller's
+// token, retries the ones the gateway turned away as its Retry-After and
+// backoff hints tell, and pages through lists. Endpoints without a typed
+// method can be called with Client.Do.
+package sdk
+
+import (
+	"api-gateway/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// BasePath is where the gateway serves its API.
+	BasePath = "/local-eats"
+
+	userAgent = "local-eats-go-sdk"
+
+	// defaultMaxRetries is how many times a request is retried.
+	defaultMaxRetries = 3
+	// maxWait caps the wait between retries, whatever the gateway asks.
+	maxWait = time.Minute
+)
+
+// TokenSource gives the token requests are signed with. It is asked on
+// every request, so sources backed by the auth service can refresh tokens
+// before they expire.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a token that never changes.
+type StaticToken string
+
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// Client calls the gateway. Its fields may be changed before its first
+// request.
+type Client struct {
+	// BaseURL is the gateway's address, such as "https://api.example.com".
+	BaseURL string
+	Tokens  TokenSource
+	HTTP    *http.Client
+	// MaxRetries is how many times a failed request is retried, none when
+	// negative.
+	MaxRetries int
+	// RequestTimeout is sent as X-Timeout-Ms, so the gateway gives up on
+	// the services after it too. Zero leaves the gateway's own timeouts.
+	RequestTimeout time.Duration
+}
+
+// New returns a client of the gateway at baseURL signing its requests
+// with tokens. Tokens may be nil for public endpoints.
+func New(baseURL string, tokens TokenSource) *Client {
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Tokens:  tokens,
+		// Long enough for the gateway's long polls.
+		HTTP:       &http.Client{Timeout: time.Minute},
+		MaxRetries: defaultMaxRetries,
+	}
+}
+
+// Error is an error answer of the gateway.
+type Error struct {
+	Status    int
+	Message   string
+	RequestId string
+	// Retry tells how the gateway asked to be retried, when it turned the
+	// request away with 429 or 503.
+	Retry *models.RetryHint
+	// Body is the whole answer, for fields such as "challenge" or "rule".
+	Body map[string]any
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("local-eats: %d %s: %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// Do calls path, relative to BasePath, with the query and the JSON of in
+// as the body when it is not nil, and decodes the answer into out when it
+// is not nil. Protobuf messages are decoded with protojson. Answers
+// wrapped in the {"data", "meta"} envelope are unwrapped. It returns the
+// answer's status, and an *Error for statuses of 400 and above.
+func (cl *Client) Do(ctx context.Context, method, path string, query url.Values, in, out any) (int, error) {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return 0, fmt.Errorf("local-eats: encoding request: %w", err)
+		}
+	}
+
+	u := cl.BaseURL + BasePath + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		status, data, header, err := cl.send(ctx, method, u, body)
+		if err == nil && status < http.StatusBadRequest {
+			return status, decode(data, out)
+		}
+		if err == nil {
+			err = apiError(status, data, header)
+		}
+
+		wait, retry := cl.backoff(method, attempt, err, header)
+		if !retry {
+			return status, err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (cl *Client) send(ctx context.Context, method, u string, body []byte) (int, []byte, http.Header, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if cl.RequestTimeout > 0 {
+		req.Header.Set("X-Timeout-Ms", strconv.FormatInt(cl.RequestTimeout.Milliseconds(), 10))
+	}
+	if cl.Tokens != nil {
+		token, err := cl.Tokens.Token(ctx)
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("local-eats: getting token: %w", err)
+		}
+		req.Header.Set("Authorization", token)
+	}
+
+	res, err := cl.HTTP.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	return res.StatusCode, data, res.Header, err
+}
+
+// backoff tells whether a failed request is retried, and after how long.
+// Requests the gateway turned away with a retry hint were not served, so
+// they are retried whatever their method; other failures only when the
+// request is idempotent.
+func (cl *Client) backoff(method string, attempt int, err error, header http.Header) (time.Duration, bool) {
+	if attempt >= cl.MaxRetries {
+		return 0, false
+	}
+
+	apiErr, ok := err.(*Error)
+	switch {
+	case ok && apiErr.Retry != nil:
+		return hintWait(*apiErr.Retry, attempt), true
+	case !idempotent(method):
+		return 0, false
+	case !ok:
+		// The request never got an answer.
+		return exponential(time.Second, attempt), true
+	}
+
+	switch apiErr.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return 0, false
+	}
+	after := time.Second
+	if s, err := strconv.Atoi(header.Get("Retry-After")); err == nil && s > 0 {
+		after = time.Duration(s) * time.Second
+	}
+	return max(after, exponential(time.Second, attempt)), true
+}
+
+// hintWait is how long to wait before the attempt+1th retry of a request
+// turned away with hint: never less than its Retry-After, and with
+// jitter, a random time up to the exponential backoff.
+func hintWait(hint models.RetryHint, attempt int) time.Duration {
+	after := time.Duration(hint.AfterSeconds) * time.Second
+	if hint.Backoff != models.BackoffExponential {
+		return min(after, maxWait)
+	}
+
+	wait := min(exponential(after, attempt), time.Duration(hint.MaxSeconds)*time.Second)
+	if hint.Jitter && wait > after {
+		wait = after + time.Duration(rand.Int63n(int64(wait-after)))
+	}
+	return min(max(wait, after), maxWait)
+}
+
+// exponential doubles base on every attempt, up to maxWait.
+func exponential(base time.Duration, attempt int) time.Duration {
+	if attempt >= 16 {
+		return maxWait
+	}
+	return min(base<<attempt, maxWait)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func apiError(status int, data []byte, header http.Header) *Error {
+	e := &Error{Status: status, RequestId: header.Get("X-Request-ID")}
+	if err := json.Unmarshal(data, &e.Body); err != nil {
+		e.Message = strings.TrimSpace(string(data))
+		return e
+	}
+	e.Message, _ = e.Body["error"].(string)
+	if raw, ok := e.Body["retry"]; ok {
+		b, _ := json.Marshal(raw)
+		var hint models.RetryHint
+		if json.Unmarshal(b, &hint) == nil && hint.AfterSeconds > 0 {
+			e.Retry = &hint
+		}
+	}
+	return e
+}
+
+// envelope is models.Envelope with its data left raw.
+type envelope struct {
+	Data json.RawMessage `json:"data"`
+	Meta *models.Meta    `json:"meta"`
+}
+
+// unmarshaler reads the answers however the gateway is configured to
+// encode them, ignoring the fields it adds to the services' messages.
+var unmarshaler = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+func decode(data []byte, out any) error {
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	var env envelope
+	if json.Unmarshal(data, &env) == nil && env.Data != nil && env.Meta != nil {
+		data = env.Data
+	}
+
+	var err error
+	if msg, ok := out.(proto.Message); ok {
+		err = unmarshaler.Unmarshal(data, msg)
+	} else {
+		err = json.Unmarshal(data, out)
+	}
+	if err != nil {
+		return fmt.Errorf("local-eats: decoding answer: %w", err)
+	}
+	return nil
+}
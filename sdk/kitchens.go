@@ -0,0 +1,74 @@
+package sdk
+
+import (
+	pbd "api-gateway/genproto/dish"
+	pbk "api-gateway/genproto/kitchen"
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// KitchenSearch is what kitchens are searched by; at least one field must
+// be set.
+type KitchenSearch struct {
+	Query string
+	// CuisineType is a code, name, translation or alias of a cuisine.
+	CuisineType string
+	// Rating is the lowest rating, none when zero.
+	Rating float64
+}
+
+// GetKitchen gets a kitchen by ID.
+func (cl *Client) GetKitchen(ctx context.Context, id string) (*pbk.Info, error) {
+	res := &pbk.Info{}
+	_, err := cl.Do(ctx, http.MethodGet, "/kitchens/"+url.PathEscape(id), nil, nil, res)
+	return res, err
+}
+
+// Kitchens pages through the kitchens, size to a page.
+func (cl *Client) Kitchens(size int) *Pager[*pbk.KitchenDetails] {
+	return cl.kitchens("/kitchens", nil, size)
+}
+
+// SearchKitchens pages through the kitchens found by s, size to a page.
+func (cl *Client) SearchKitchens(s KitchenSearch, size int) *Pager[*pbk.KitchenDetails] {
+	query := url.Values{}
+	if s.Query != "" {
+		query.Set("query", s.Query)
+	}
+	if s.CuisineType != "" {
+		query.Set("cuisine_type", s.CuisineType)
+	}
+	if s.Rating != 0 {
+		query.Set("rating", strconv.FormatFloat(s.Rating, 'f', -1, 32))
+	}
+	return cl.kitchens("/kitchens/search", query, size)
+}
+
+func (cl *Client) kitchens(path string, filter url.Values, size int) *Pager[*pbk.KitchenDetails] {
+	return newPager(size, func(ctx context.Context, query url.Values) ([]*pbk.KitchenDetails, int, error) {
+		for k, v := range filter {
+			query[k] = v
+		}
+		res := &pbk.Kitchens{}
+		_, err := cl.Do(ctx, http.MethodGet, path, query, nil, res)
+		return res.Kitchens, int(res.Total), err
+	})
+}
+
+// GetDish gets a dish by ID.
+func (cl *Client) GetDish(ctx context.Context, id string) (*pbd.DishInfo, error) {
+	res := &pbd.DishInfo{}
+	_, err := cl.Do(ctx, http.MethodGet, "/dishes/"+url.PathEscape(id), nil, nil, res)
+	return res, err
+}
+
+// Dishes pages through the dishes of a kitchen, size to a page.
+func (cl *Client) Dishes(kitchenID string, size int) *Pager[*pbd.DishDetails] {
+	return newPager(size, func(ctx context.Context, query url.Values) ([]*pbd.DishDetails, int, error) {
+		res := &pbd.Dishes{}
+		_, err := cl.Do(ctx, http.MethodGet, "/kitchens/"+url.PathEscape(kitchenID)+"/dishes", query, nil, res)
+		return res.Dishes, int(res.Total), err
+	})
+}
@@ -0,0 +1,81 @@
+package sdk
+
+import (
+	pbo "api-gateway/genproto/order"
+	"api-gateway/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// PlacedOrder is the answer to placing an order: the order, or the fraud
+// case it was held for until an admin reviews it.
+type PlacedOrder struct {
+	Order *pbo.NewOrderResp
+	Hold  *models.FraudHold
+}
+
+// CreateOrder places an order. It is not retried once it reached the
+// services, so an order is never placed twice; on unreliable connections
+// PlaceOrderAsync is safer.
+func (cl *Client) CreateOrder(ctx context.Context, order models.NewOrder) (PlacedOrder, error) {
+	var raw json.RawMessage
+	status, err := cl.Do(ctx, http.MethodPost, "/orders", nil, order, &raw)
+	if err != nil {
+		return PlacedOrder{}, err
+	}
+
+	var res PlacedOrder
+	if status == http.StatusAccepted {
+		res.Hold = &models.FraudHold{}
+		err = decode(raw, res.Hold)
+	} else {
+		res.Order = &pbo.NewOrderResp{}
+		err = decode(raw, res.Order)
+	}
+	return res, err
+}
+
+// PlaceOrderAsync places an order in the background, answering at once
+// with the placement to follow it by with GetOrderPlacement.
+func (cl *Client) PlaceOrderAsync(ctx context.Context, order models.NewOrder) (*models.OrderPlacement, error) {
+	res := &models.OrderPlacement{}
+	_, err := cl.Do(ctx, http.MethodPost, "/orders", url.Values{"async": {"true"}}, order, res)
+	return res, err
+}
+
+// GetOrderPlacement gets an order placed in the background by its token.
+func (cl *Client) GetOrderPlacement(ctx context.Context, token string) (*models.OrderPlacement, error) {
+	res := &models.OrderPlacement{}
+	_, err := cl.Do(ctx, http.MethodGet, "/orders/placements/"+url.PathEscape(token), nil, nil, res)
+	return res, err
+}
+
+// GetOrder gets an order by ID.
+func (cl *Client) GetOrder(ctx context.Context, id string) (*pbo.OrderInfo, error) {
+	res := &pbo.OrderInfo{}
+	_, err := cl.Do(ctx, http.MethodGet, "/orders/"+url.PathEscape(id), nil, nil, res)
+	return res, err
+}
+
+// Orders pages through the caller's orders, size to a page.
+func (cl *Client) Orders(size int) *Pager[*pbo.OrderCustomer] {
+	return newPager(size, func(ctx context.Context, query url.Values) ([]*pbo.OrderCustomer, int, error) {
+		res := &pbo.OrdersCustomer{}
+		_, err := cl.Do(ctx, http.MethodGet, "/orders", query, nil, res)
+		return res.Orders, int(res.Total), err
+	})
+}
+
+// PollOrderStatus waits until the status of an order changes from the
+// version the caller has, 0 for none, and answers with the new status.
+// The gateway answers with the same version when nothing changed for a
+// while; the caller polls again then.
+func (cl *Client) PollOrderStatus(ctx context.Context, id string, since int) (*models.OrderStatus, error) {
+	res := &models.OrderStatus{}
+	query := url.Values{"since_version": {strconv.Itoa(since)}}
+	_, err := cl.Do(ctx, http.MethodGet, "/orders/"+url.PathEscape(id)+"/status/poll", query, nil, res)
+	return res, err
+}
@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// defaultPageSize is how many items a page is asked for when no size is
+// given.
+const defaultPageSize = 50
+
+// Pager walks a list of the gateway page by page, fetching each page as
+// the previous one runs out:
+//
+//	p := client.Kitchens(0)
+//	for p.Next(ctx) {
+//		k := p.Item()
+//		...
+//	}
+//	if err := p.Err(); err != nil {
+//		...
+//	}
+//
+// Some lists leave out items after paging, such as the kitchens the
+// caller blocked, so pages may hold fewer items than their size; the
+// walk ends at the list's total, or at an empty page for lists without
+// one.
+type Pager[T any] struct {
+	fetch func(ctx context.Context, query url.Values) ([]T, int, error)
+	size  int
+
+	page  int
+	items []T
+	item  T
+	done  bool
+	err   error
+}
+
+// newPager pages with fetch, which is given the page and limit in query
+// and answers with the page's items and the list's total, 0 if unknown.
+func newPager[T any](size int, fetch func(ctx context.Context, query url.Values) ([]T, int, error)) *Pager[T] {
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	return &Pager[T]{fetch: fetch, size: size}
+}
+
+// Next moves to the next item, fetching the next page when needed. It
+// returns false at the end of the list or on an error, told by Err.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	for len(p.items) == 0 {
+		if p.done || p.err != nil {
+			return false
+		}
+
+		p.page++
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(p.page))
+		query.Set("limit", strconv.Itoa(p.size))
+		items, total, err := p.fetch(ctx, query)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.items = items
+		p.done = len(items) == 0 || (total > 0 && p.page*p.size >= total)
+	}
+
+	p.item, p.items = p.items[0], p.items[1:]
+	return true
+}
+
+// Item is the item Next moved to.
+func (p *Pager[T]) Item() T {
+	return p.item
+}
+
+// Err is the error that ended the walk, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All walks the rest of the list, returning its items.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var res []T
+	for p.Next(ctx) {
+		res = append(res, p.Item())
+	}
+	return res, p.Err()
+}
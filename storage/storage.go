@@ -0,0 +1,456 @@
+package storage
+
+import (
+	"api-gateway/models"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Storage keeps gateway-side data that the backend services have no
+// place for yet.
+type Storage struct {
+	Modifiers         *Store[models.Modifiers]
+	Webhooks          *Store[models.Webhook]
+	WebhookDeliveries *Store[[]models.WebhookDelivery]
+	Uploads           *Store[models.Upload]
+	SearchHistory     *Store[[]models.SearchHistoryEntry]
+	SavedSearches     *Store[models.SavedSearch]
+	ReportSchedules   *Store[models.ReportSchedule]
+	Reports           *Store[models.Report]
+	Activity          *Store[[]models.FeedItem]
+	Tickets           *Store[models.Ticket]
+	OrderPayments     *Store[string]
+	Payments          *Store[models.Payment]
+	RefundRequests    *Store[models.RefundRequest]
+	// OrderRefunds maps order IDs to their latest refund request, so an
+	// order is claimed by one request at a time.
+	OrderRefunds      *Store[string]
+	Payouts           *Store[models.Payout]
+	CommissionRates   *Store[models.CommissionRate]
+	CommissionChanges *Store[models.CommissionChange]
+	// OrderCommissions holds the commission percent in effect when each
+	// order was placed.
+	OrderCommissions *Store[float32]
+	GroupOrders      *Store[models.GroupOrder]
+	// GroupShareCodes maps share codes to group order IDs.
+	GroupShareCodes *Store[string]
+	MealPlans       *Store[models.MealPlan]
+	MealPlanRuns    *Store[models.MealPlanRun]
+	// DishAlerts is keyed by dish and user ID.
+	DishAlerts *Store[models.DishAlert]
+	DishStocks *Store[models.DishStock]
+	// Cuisines is keyed by cuisine code.
+	Cuisines *Store[models.Cuisine]
+	Zones    *Store[models.Zone]
+	// Verifications is keyed by kitchen ID.
+	Verifications *Store[models.KitchenVerification]
+	// ReviewPurchases tells, by review ID, whether the review's order was
+	// placed by its author at the reviewed kitchen.
+	ReviewPurchases *Store[bool]
+	HeldReviews     *Store[models.HeldReview]
+	// BlockedKitchens is keyed by user ID.
+	BlockedKitchens *Store[[]models.BlockedKitchen]
+	// Links is keyed by short code.
+	Links *Store[models.Link]
+	// Roles is keyed by role name.
+	Roles *Store[models.Role]
+	// KitchenStaff is keyed by kitchen ID.
+	KitchenStaff *Store[[]models.StaffMember]
+	Invitations  *Store[models.Invitation]
+	SMSMessages  *Store[models.SMSMessage]
+	// OTPs is keyed by phone number.
+	OTPs   *Store[models.OTP]
+	Emails *Store[models.Email]
+	// NotifySettings is keyed by user ID.
+	NotifySettings *Store[models.NotificationSettings]
+	Broadcasts     *Store[models.Broadcast]
+	Announcements  *Store[models.Announcement]
+	DishDiscounts  *Store[models.DishDiscount]
+	// OrderPricing is keyed by order ID.
+	OrderPricing *Store[models.OrderPricing]
+	// KitchenRules, KitchenLocations and OrderTimers are keyed by
+	// kitchen ID.
+	KitchenRules     *Store[models.KitchenRules]
+	KitchenLocations *Store[models.KitchenLocation]
+	OrderTimers      *Store[models.OrderTimer]
+	// PendingOrders is keyed by order ID.
+	PendingOrders *Store[models.PendingOrder]
+	// OrderNotes and OrderDelivery are keyed by order ID.
+	OrderNotes    *Store[models.OrderNotes]
+	OrderDelivery *Store[models.DeliveryPreferences]
+	// AbuseProfiles is keyed by user ID.
+	AbuseProfiles *Store[models.AbuseProfile]
+	// FraudHistory is keyed by user ID.
+	FraudHistory *Store[models.FraudHistory]
+	FraudCases   *Store[models.FraudCase]
+	// Chargebacks is keyed by provider and dispute ID, "stripe:dp_1".
+	Chargebacks *Store[models.Chargeback]
+	// Settings holds the platform settings under the single key
+	// "platform".
+	Settings        *Store[models.Settings]
+	SettingsHistory *Store[models.SettingsChange]
+	// OrderPlacements is keyed by placement token.
+	OrderPlacements *Store[models.OrderPlacement]
+	// OrderStatuses is keyed by order ID.
+	OrderStatuses *Store[models.OrderStatus]
+	// APIUsage is keyed by date, method, route, platform and version,
+	// "2024-05-01|GET|/local-eats/orders/:id|ios|2.3.1".
+	APIUsage *Store[models.APIUsage]
+	// Translations is keyed by kind, entity ID and locale,
+	// "dish|<id>|ru".
+	Translations *Store[models.Translation]
+	// KitchenTimeZones is keyed by kitchen ID.
+	KitchenTimeZones *Store[models.KitchenTimeZone]
+	// DishAllergens is keyed by dish ID.
+	DishAllergens *Store[models.DishAllergens]
+	// AllergyProfiles is keyed by user ID.
+	AllergyProfiles *Store[models.AllergyProfile]
+	Inspections     *Store[models.Inspection]
+	// HygieneRatings is keyed by kitchen ID.
+	HygieneRatings *Store[models.HygieneRating]
+	RetentionRuns  *Store[models.RetentionRun]
+	DataRequests   *Store[models.DataRequest]
+	DataAccessLog  *Store[models.DataAccessEntry]
+	// PartnerKeys is keyed by the hash of the key.
+	PartnerKeys *Store[models.PartnerKey]
+	// CatalogChanges is keyed by change time and ID, so it sorts by time.
+	CatalogChanges *Store[models.CatalogChange]
+	// PartnerOrders is keyed by partner and external order ID,
+	// "acme|A-1001"; PartnerOrderKeys maps order IDs to those keys.
+	PartnerOrders    *Store[models.PartnerOrder]
+	PartnerOrderKeys *Store[string]
+	// SandboxOrders holds the test orders placed in the sandbox by ID.
+	SandboxOrders *Store[models.SandboxOrder]
+	// RecordedRequests is keyed by recording ID and the request's
+	// zero-padded sequence number, "<id>|00000042", so a recording's
+	// requests sort in the order they were served.
+	Recordings       *Store[models.Recording]
+	RecordedRequests *Store[models.RecordedRequest]
+
+	// sealed are the stores kept encrypted.
+	sealed []resealer
+}
+
+func New() *Storage {
+	return &Storage{
+		Modifiers:         NewStore[models.Modifiers](),
+		Webhooks:          NewStore[models.Webhook](),
+		WebhookDeliveries: NewStore[[]models.WebhookDelivery](),
+		Uploads:           NewStore[models.Upload](),
+		SearchHistory:     NewStore[[]models.SearchHistoryEntry](),
+		SavedSearches:     NewStore[models.SavedSearch](),
+		ReportSchedules:   NewStore[models.ReportSchedule](),
+		Reports:           NewStore[models.Report](),
+		Activity:          NewStore[[]models.FeedItem](),
+		Tickets:           NewStore[models.Ticket](),
+		OrderPayments:     NewStore[string](),
+		Payments:          NewStore[models.Payment](),
+		RefundRequests:    NewStore[models.RefundRequest](),
+		OrderRefunds:      NewStore[string](),
+		Payouts:           NewStore[models.Payout](),
+		CommissionRates:   NewStore[models.CommissionRate](),
+		CommissionChanges: NewStore[models.CommissionChange](),
+		OrderCommissions:  NewStore[float32](),
+		GroupOrders:       NewStore[models.GroupOrder](),
+		GroupShareCodes:   NewStore[string](),
+		MealPlans:         NewStore[models.MealPlan](),
+		MealPlanRuns:      NewStore[models.MealPlanRun](),
+		DishAlerts:        NewStore[models.DishAlert](),
+		DishStocks:        NewStore[models.DishStock](),
+		Cuisines:          NewStore[models.Cuisine](),
+		Zones:             NewStore[models.Zone](),
+		Verifications:     NewStore[models.KitchenVerification](),
+		ReviewPurchases:   NewStore[bool](),
+		HeldReviews:       NewStore[models.HeldReview](),
+		BlockedKitchens:   NewStore[[]models.BlockedKitchen](),
+		Links:             NewStore[models.Link](),
+		Roles:             NewStore[models.Role](),
+		KitchenStaff:      NewStore[[]models.StaffMember](),
+		Invitations:       NewStore[models.Invitation](),
+		SMSMessages:       NewStore[models.SMSMessage](),
+		OTPs:              NewStore[models.OTP](),
+		Emails:            NewStore[models.Email](),
+		NotifySettings:    NewStore[models.NotificationSettings](),
+		Broadcasts:        NewStore[models.Broadcast](),
+		Announcements:     NewStore[models.Announcement](),
+		DishDiscounts:     NewStore[models.DishDiscount](),
+		OrderPricing:      NewStore[models.OrderPricing](),
+		KitchenRules:      NewStore[models.KitchenRules](),
+		KitchenLocations:  NewStore[models.KitchenLocation](),
+		OrderTimers:       NewStore[models.OrderTimer](),
+		PendingOrders:     NewStore[models.PendingOrder](),
+		OrderNotes:        NewStore[models.OrderNotes](),
+		OrderDelivery:     NewStore[models.DeliveryPreferences](),
+		AbuseProfiles:     NewStore[models.AbuseProfile](),
+		FraudHistory:      NewStore[models.FraudHistory](),
+		FraudCases:        NewStore[models.FraudCase](),
+		Chargebacks:       NewStore[models.Chargeback](),
+		Settings:          NewStore[models.Settings](),
+		SettingsHistory:   NewStore[models.SettingsChange](),
+		OrderPlacements:   NewStore[models.OrderPlacement](),
+		OrderStatuses:     NewStore[models.OrderStatus](),
+		APIUsage:          NewStore[models.APIUsage](),
+		Translations:      NewStore[models.Translation](),
+		KitchenTimeZones:  NewStore[models.KitchenTimeZone](),
+		DishAllergens:     NewStore[models.DishAllergens](),
+		AllergyProfiles:   NewStore[models.AllergyProfile](),
+		Inspections:       NewStore[models.Inspection](),
+		HygieneRatings:    NewStore[models.HygieneRating](),
+		RetentionRuns:     NewStore[models.RetentionRun](),
+		DataRequests:      NewStore[models.DataRequest](),
+		DataAccessLog:     NewStore[models.DataAccessEntry](),
+		PartnerKeys:       NewStore[models.PartnerKey](),
+		CatalogChanges:    NewStore[models.CatalogChange](),
+		PartnerOrders:     NewStore[models.PartnerOrder](),
+		PartnerOrderKeys:  NewStore[string](),
+		SandboxOrders:     NewStore[models.SandboxOrder](),
+		Recordings:        NewStore[models.Recording](),
+		RecordedRequests:  NewStore[models.RecordedRequest](),
+	}
+}
+
+// Sealer encrypts the values of sealed stores.
+type Sealer interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(sealed []byte) ([]byte, error)
+}
+
+type resealer interface {
+	seal(sealer Sealer, report func(error)) error
+	Reseal() (int, error)
+}
+
+// Seal keeps the stores of personal data encrypted with sealer from now
+// on. It is called once, before the stores are used. Values that can't
+// be sealed or opened later on are passed to report.
+func (s *Storage) Seal(sealer Sealer, report func(error)) error {
+	s.sealed = []resealer{
+		s.SearchHistory,
+		s.SavedSearches,
+		s.Activity,
+		s.Tickets,
+		s.GroupOrders,
+		s.MealPlans,
+		s.SMSMessages,
+		s.OTPs,
+		s.Emails,
+		s.OrderNotes,
+		s.OrderDelivery,
+		s.OrderPlacements,
+		s.AllergyProfiles,
+	}
+	for _, store := range s.sealed {
+		if err := store.seal(sealer, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reseal seals the values of the sealed stores again, with the key the
+// sealer seals with now, and returns how many it resealed.
+func (s *Storage) Reseal() (int, error) {
+	n := 0
+	for _, store := range s.sealed {
+		m, err := store.Reseal()
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Store is an in-memory key-value collection safe for concurrent use.
+// A sealed store keeps its values JSON encoded and encrypted. A value it
+// can't open is reported and treated as missing, and a value it can't
+// seal is reported and not stored, so callers never see the plaintext
+// kept in the clear.
+type Store[T any] struct {
+	mu    sync.RWMutex
+	items map[string]T
+
+	sealer Sealer
+	sealed map[string][]byte
+	report func(error)
+}
+
+func NewStore[T any]() *Store[T] {
+	return &Store[T]{items: make(map[string]T)}
+}
+
+func (s *Store[T]) Get(key string) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.get(key)
+}
+
+func (s *Store[T]) Set(key string, value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, value)
+}
+
+// Update atomically replaces the value under key with the result of fn,
+// which receives the current value and whether it exists.
+func (s *Store[T]) Update(key string, fn func(T, bool) T) T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.get(key)
+	v = fn(v, ok)
+	s.set(key, v)
+	return v
+}
+
+func (s *Store[T]) Delete(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sealer != nil {
+		_, ok := s.sealed[key]
+		delete(s.sealed, key)
+		return ok
+	}
+	_, ok := s.items[key]
+	delete(s.items, key)
+	return ok
+}
+
+func (s *Store[T]) List() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sealer != nil {
+		list := make([]T, 0, len(s.sealed))
+		for k, b := range s.sealed {
+			if v, err := s.open(b); err == nil {
+				list = append(list, v)
+			} else {
+				s.report(errors.Wrapf(err, "error opening %q", k))
+			}
+		}
+		return list
+	}
+	list := make([]T, 0, len(s.items))
+	for _, v := range s.items {
+		list = append(list, v)
+	}
+	return list
+}
+
+func (s *Store[T]) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sealer != nil {
+		keys := make([]string, 0, len(s.sealed))
+		for k := range s.sealed {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+	keys := make([]string, 0, len(s.items))
+	for k := range s.items {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Reseal seals every value again with the key the sealer seals with now
+// and returns how many it resealed. It stops at the first value that
+// can't be resealed, which stays sealed with its old key.
+func (s *Store[T]) Reseal() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sealer == nil {
+		return 0, nil
+	}
+	n := 0
+	for k, b := range s.sealed {
+		plain, err := s.sealer.Open(b)
+		if err != nil {
+			return n, errors.Wrapf(err, "error opening %q", k)
+		}
+		if b, err = s.sealer.Seal(plain); err != nil {
+			return n, errors.Wrapf(err, "error sealing %q", k)
+		}
+		s.sealed[k] = b
+		n++
+	}
+	return n, nil
+}
+
+func (s *Store[T]) seal(sealer Sealer, report func(error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sealed := make(map[string][]byte, len(s.items))
+	for k, v := range s.items {
+		b, err := seal(sealer, v)
+		if err != nil {
+			return errors.Wrapf(err, "error sealing %q", k)
+		}
+		sealed[k] = b
+	}
+	s.sealer, s.sealed, s.report = sealer, sealed, report
+	s.items = nil
+	return nil
+}
+
+func (s *Store[T]) get(key string) (T, bool) {
+	if s.sealer != nil {
+		b, ok := s.sealed[key]
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		v, err := s.open(b)
+		if err != nil {
+			s.report(errors.Wrapf(err, "error opening %q", key))
+			return v, false
+		}
+		return v, true
+	}
+	v, ok := s.items[key]
+	return v, ok
+}
+
+func (s *Store[T]) set(key string, value T) {
+	if s.sealer != nil {
+		b, err := seal(s.sealer, value)
+		if err != nil {
+			s.report(errors.Wrapf(err, "error sealing %q", key))
+			return
+		}
+		s.sealed[key] = b
+		return
+	}
+	s.items[key] = value
+}
+
+func (s *Store[T]) open(b []byte) (T, error) {
+	var v T
+	plain, err := s.sealer.Open(b)
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(plain, &v); err != nil {
+		var zero T
+		return zero, errors.Wrap(err, "error decoding stored value")
+	}
+	return v, nil
+}
+
+func seal[T any](sealer Sealer, v T) ([]byte, error) {
+	plain, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding stored value")
+	}
+	return sealer.Seal(plain)
+}